@@ -0,0 +1,86 @@
+// Package adapters converts chunks into the document types of Go RAG libraries,
+// with the chunk context flattened into metadata by CodeChunk.Metadata:
+//
+//	chunks, err := codechunk.Chunk("main.go", code, nil)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	docs := make([]chromem.Document, 0, len(chunks))
+//	for _, doc := range adapters.ChromemDocuments(chunks) {
+//	    docs = append(docs, chromem.Document(doc))
+//	}
+//	err = collection.AddDocuments(ctx, docs, runtime.NumCPU())
+//
+// The document types mirror the libraries' own, so this package depends on none
+// of them.
+package adapters
+
+import (
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+// ChromemDocument has the fields of chromem-go's Document, so that it converts
+// to one with chromem.Document(doc) without this module importing chromem-go
+type ChromemDocument struct {
+	ID        string
+	Metadata  map[string]string
+	Embedding []float32
+	Content   string
+}
+
+// ChromemDocuments converts chunks into chromem-go documents without embeddings,
+// for Collection.AddDocuments to embed. The content is the contextualized text,
+// or the raw text for chunks built with a ContextualizedTextMode other than
+// prefix, so the header can be left to the metadata.
+func ChromemDocuments(chunks []codechunk.CodeChunk) []ChromemDocument {
+	documents := make([]ChromemDocument, len(chunks))
+	for i, chunk := range chunks {
+		documents[i] = ChromemDocument{
+			ID:       chunk.ID,
+			Metadata: chunk.Metadata(),
+			Content:  documentContent(chunk),
+		}
+	}
+	return documents
+}
+
+// LangChainDocument has the fields of langchaingo's schema.Document, so that it
+// converts to one with schema.Document(doc) without this module importing
+// langchaingo
+type LangChainDocument struct {
+	PageContent string
+	Metadata    map[string]any
+	Score       float32
+}
+
+// LangChainDocuments converts chunks into documents for langchaingo vector stores,
+// with the same content as ChromemDocuments and the chunk metadata as
+// map[string]any:
+//
+//	docs := make([]schema.Document, 0, len(chunks))
+//	for _, doc := range adapters.LangChainDocuments(chunks) {
+//	    docs = append(docs, schema.Document(doc))
+//	}
+//	_, err = store.AddDocuments(ctx, docs)
+func LangChainDocuments(chunks []codechunk.CodeChunk) []LangChainDocument {
+	documents := make([]LangChainDocument, len(chunks))
+	for i, chunk := range chunks {
+		metadata := chunk.Metadata()
+		documents[i] = LangChainDocument{
+			PageContent: documentContent(chunk),
+			Metadata:    make(map[string]any, len(metadata)),
+		}
+		for key, value := range metadata {
+			documents[i].Metadata[key] = value
+		}
+	}
+	return documents
+}
+
+// documentContent returns the text of a chunk to embed
+func documentContent(chunk codechunk.CodeChunk) string {
+	if text := chunk.Contextualized(); text != "" {
+		return text
+	}
+	return chunk.Content()
+}
@@ -0,0 +1,92 @@
+package adapters
+
+import (
+	"reflect"
+	"testing"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+const adapterSource = `package main
+
+// Greet prints a greeting.
+func Greet(name string) {
+	println("Hello, " + name)
+}
+`
+
+func TestLangChainDocuments(t *testing.T) {
+	chunks, err := codechunk.Chunk("main.go", adapterSource, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	documents := LangChainDocuments(chunks)
+	if len(documents) != len(chunks) {
+		t.Fatalf("Expected %d documents, got %d", len(chunks), len(documents))
+	}
+	for i, document := range documents {
+		if document.PageContent != chunks[i].ContextualizedText {
+			t.Errorf("Document %d: expected the contextualized text as page content", i)
+		}
+		metadata := chunks[i].Metadata()
+		if len(document.Metadata) != len(metadata) {
+			t.Errorf("Document %d: Metadata = %v, expected %v", i, document.Metadata, metadata)
+		}
+		for key, value := range metadata {
+			if document.Metadata[key] != value {
+				t.Errorf("Document %d: Metadata[%q] = %v, expected %q", i, key, document.Metadata[key], value)
+			}
+		}
+	}
+
+	// The field set of langchaingo's schema.Document, which LangChainDocument
+	// must keep to convert to it
+	type schemaDocument struct {
+		PageContent string
+		Metadata    map[string]any
+		Score       float32
+	}
+	_ = schemaDocument(documents[0])
+}
+
+func TestChromemDocuments(t *testing.T) {
+	chunks, err := codechunk.Chunk("main.go", adapterSource, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	documents := ChromemDocuments(chunks)
+	if len(documents) != len(chunks) {
+		t.Fatalf("Expected %d documents, got %d", len(chunks), len(documents))
+	}
+	for i, document := range documents {
+		if document.ID != chunks[i].ID {
+			t.Errorf("Document %d: ID = %q, expected %q", i, document.ID, chunks[i].ID)
+		}
+		if document.Content != chunks[i].ContextualizedText {
+			t.Errorf("Document %d: expected the contextualized text as content", i)
+		}
+		if !reflect.DeepEqual(document.Metadata, chunks[i].Metadata()) {
+			t.Errorf("Document %d: Metadata = %v, expected %v", i, document.Metadata, chunks[i].Metadata())
+		}
+	}
+
+	opts := codechunk.ChunkOptions{ContextualizedTextMode: codechunk.ContextualizedTextModeNone, LazyText: true}
+	chunks, err = codechunk.Chunk("main.go", adapterSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for i, document := range ChromemDocuments(chunks) {
+		if document.Content != chunks[i].Content() {
+			t.Errorf("Document %d: expected the raw text as content without a header, got %q", i, document.Content)
+		}
+	}
+	// The field set of chromem-go's Document, which ChromemDocument must keep to
+	// convert to it
+	type chromemDocument struct {
+		ID        string
+		Metadata  map[string]string
+		Embedding []float32
+		Content   string
+	}
+	_ = chromemDocument(documents[0])
+}
@@ -0,0 +1,92 @@
+package codechunk
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// FileAnalysis is the structure of a source file as extracted by Analyze: its
+// entities, imports and exports, and the scope tree nesting the entities
+type FileAnalysis struct {
+	Filepath       string             `json:"filepath"`                 // Source file path
+	Language       Language           `json:"language"`                 // Detected or forced language
+	Entities       []*ExtractedEntity `json:"entities"`                 // All entities in source order, including imports
+	Imports        []*ExtractedEntity `json:"imports"`                  // Import entities
+	Exports        []*ExtractedEntity `json:"exports"`                  // Exported entities and export statements
+	ScopeTree      *ScopeTree         `json:"scopeTree"`                // Entities nested by scope
+	ParseError     *ParseError        `json:"parseError,omitempty"`     // Recoverable parse error if any
+	ErrorNodeCount int                `json:"errorNodeCount,omitempty"` // Number of ERROR and MISSING nodes in the syntax tree
+
+	tree *sitter.Tree // Syntax tree kept for Query, nil once closed
+	code []byte       // Source the tree was parsed from
+}
+
+// Tree returns the syntax tree the analysis was extracted from, or nil after
+// Close. The tree belongs to the analysis and must not be closed by the caller.
+func (a *FileAnalysis) Tree() *sitter.Tree {
+	return a.tree
+}
+
+// Close frees the native memory held by the syntax tree. Entities and the scope
+// tree remain valid, but Tree and Query no longer work. Close is idempotent, and
+// analyses that are never closed are freed by a finalizer.
+func (a *FileAnalysis) Close() {
+	if a != nil && a.tree != nil {
+		a.tree.Close()
+		a.tree = nil
+	}
+}
+
+// Analyze parses source code and extracts its entities, imports, exports and scope
+// tree without chunking it, for consumers that need a symbol outline such as
+// navigation or outline views. The syntax tree is kept so that Query can run
+// further tree-sitter queries without parsing again; Close frees it. Of opts,
+// only Language, ParseCache, AnonymousNaming, Provenance and Logger are used;
+// opts may be nil.
+//
+// Container formats handled by a frontend when chunking (notebooks, single-file
+// components, Markdown and HTML) are not analyzed and return ErrUnsupportedLanguage
+// unless opts.Language is set.
+func Analyze(filepath string, code string, opts *ChunkOptions) (*FileAnalysis, error) {
+	return AnalyzeBytes(filepath, []byte(code), opts)
+}
+
+// AnalyzeBytes is like Analyze but accepts []byte instead of string.
+func AnalyzeBytes(filepath string, code []byte, opts *ChunkOptions) (*FileAnalysis, error) {
+	options := ChunkOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	logger := loggerFor(options)
+
+	lang := options.Language
+	if lang == "" && frontendFor(filepath) == nil {
+		lang = resolveLanguage(filepath, code, options, logger)
+	}
+	if lang == "" {
+		return nil, &ChunkError{Stage: StageDetect, Filepath: filepath, Err: ErrUnsupportedLanguage}
+	}
+
+	parsed, err := parseAndExtract(code, lang, options.ParseCache)
+	if err != nil {
+		return nil, stageError(err, StageParse, filepath, lang)
+	}
+	logParseError(logger, filepath, parsed.Error)
+
+	scopeTree := buildScopeTree(nameAnonymousEntities(parsed.Entities, filepath, options.AnonymousNaming))
+	scopeTree.Exports = append(scopeTree.Exports, parsed.Exports...)
+	qualifyScopeTree(scopeTree, fileNamespace(parsed.Tree.RootNode(), code, sourcePath(filepath, options), lang), lang)
+
+	analysis := &FileAnalysis{
+		Filepath:   filepath,
+		Language:   lang,
+		Entities:   scopeTree.AllEntities,
+		Imports:    scopeTree.Imports,
+		Exports:    scopeTree.Exports,
+		ScopeTree:  scopeTree,
+		ParseError: parsed.Error,
+		tree:       parsed.Tree,
+		code:       code,
+	}
+	if parsed.Error != nil {
+		analysis.ErrorNodeCount = parsed.Error.Count
+	}
+	return analysis, nil
+}
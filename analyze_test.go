@@ -0,0 +1,71 @@
+package codechunk
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestAnalyze(t *testing.T) {
+	code := `import { db } from './db'
+
+export class UserService {
+  find(id) {
+    return db.get(id)
+  }
+
+  save(user) {
+    return db.put(user)
+  }
+}
+
+function helper() {}
+`
+	analysis, err := Analyze("user.ts", code, nil)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if analysis.Language != LanguageTypeScript || analysis.Filepath != "user.ts" {
+		t.Errorf("unexpected file %q (%s)", analysis.Filepath, analysis.Language)
+	}
+	if len(analysis.Imports) != 1 || analysis.Imports[0].Source == nil || *analysis.Imports[0].Source != "./db" {
+		t.Errorf("expected the ./db import, got %+v", analysis.Imports)
+	}
+	if len(analysis.Exports) == 0 {
+		t.Error("expected the exported class")
+	}
+
+	roots := analysis.ScopeTree.Root
+	if len(roots) != 2 || roots[0].Entity.Name != "UserService" || roots[1].Entity.Name != "helper" {
+		t.Fatalf("expected UserService and helper at the root, got %d roots", len(roots))
+	}
+	var methods []string
+	for _, child := range roots[0].Children {
+		methods = append(methods, child.Entity.Name)
+	}
+	if len(methods) != 2 || methods[0] != "find" || methods[1] != "save" {
+		t.Errorf("expected find and save nested in UserService, got %v", methods)
+	}
+
+	if _, err := json.Marshal(analysis); err != nil {
+		t.Errorf("expected the analysis to marshal to JSON: %v", err)
+	}
+}
+
+func TestAnalyzeLanguageOverride(t *testing.T) {
+	analysis, err := Analyze("script", "def main():\n    pass\n", &ChunkOptions{Language: LanguagePython})
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if len(analysis.Entities) != 1 || analysis.Entities[0].Name != "main" {
+		t.Errorf("expected main, got %+v", analysis.Entities)
+	}
+}
+
+func TestAnalyzeUnsupported(t *testing.T) {
+	for _, path := range []string{"notes.txt", "notebook.ipynb"} {
+		if _, err := Analyze(path, "plain text", nil); !errors.Is(err, ErrUnsupportedLanguage) {
+			t.Errorf("%s: expected ErrUnsupportedLanguage, got %v", path, err)
+		}
+	}
+}
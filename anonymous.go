@@ -0,0 +1,178 @@
+package codechunk
+
+import (
+	"path"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// anonymousName names entities that nothing names
+const anonymousName = "<anonymous>"
+
+// jsExpressionEntities are the JavaScript and TypeScript expressions extracted as
+// entities when bound to a name or default-exported, such as const f = () => {}
+var jsExpressionEntities = map[string]EntityType{
+	"function_expression": EntityTypeFunction,
+	"generator_function":  EntityTypeFunction,
+	"arrow_function":      EntityTypeFunction,
+	"class":               EntityTypeClass,
+}
+
+// jsBindingValueFields maps the nodes binding a name to a JavaScript or TypeScript
+// value to the fields holding the name and the value
+var jsBindingValueFields = map[string][2]string{
+	"variable_declarator":     {"name", "value"},
+	"pair":                    {"key", "value"},
+	"assignment_expression":   {"left", "right"},
+	"field_definition":        {"property", "value"},
+	"public_field_definition": {"name", "value"},
+}
+
+// jsBinding returns the name a JavaScript or TypeScript expression is bound to by
+// its parent: the variable or field it is assigned to, or its key in an object
+// literal, looking through wrappers such as React.memo. defaultExport reports whether it is instead the default export of its
+// module, by export default or module.exports =.
+func jsBinding(node *sitter.Node, code []byte) (name string, defaultExport bool) {
+	parent := node.Parent()
+	if parent == nil {
+		return "", false
+	}
+	if parent.Type() == "arguments" && isComponentWrapper(parent.Parent(), code) {
+		return jsBinding(parent.Parent(), code)
+	}
+	if parent.Type() == "export_statement" {
+		value := parent.ChildByFieldName("value")
+		return "", value != nil && value.Equal(node)
+	}
+
+	fields, ok := jsBindingValueFields[parent.Type()]
+	if !ok {
+		return "", false
+	}
+	nameNode, value := parent.ChildByFieldName(fields[0]), parent.ChildByFieldName(fields[1])
+	if nameNode == nil || value == nil || !value.Equal(node) {
+		return "", false
+	}
+	if nameNode.Type() == "member_expression" {
+		if nameNode.Content(code) == "module.exports" {
+			return "", true
+		}
+		nameNode = nameNode.ChildByFieldName("property")
+	}
+	if nameNode == nil {
+		return "", false
+	}
+	switch nameNode.Type() {
+	case "identifier", "property_identifier", "private_property_identifier", "shorthand_property_identifier":
+		return nameNode.Content(code), false
+	case "string":
+		return stripQuotes(nameNode.Content(code)), false
+	}
+	return "", false
+}
+
+// jsBindingText returns the text binding a JavaScript or TypeScript expression
+// to its name, such as "handler = ", "onClick: " or
+// "export default "
+func jsBindingText(node *sitter.Node, code []byte) string {
+	parent := node.Parent()
+	if parent == nil {
+		return ""
+	}
+	return strings.TrimSpace(cleanSignature(string(code[parent.StartByte():node.StartByte()]))) + " "
+}
+
+// jsExportedName returns the name a JavaScript or TypeScript export statement
+// exports, that of its declaration or of the first variable it declares, and
+// whether it is a default export of an anonymous value
+func jsExportedName(node *sitter.Node, code []byte) (name string, defaultExport bool) {
+	if declaration := node.ChildByFieldName("declaration"); declaration != nil {
+		if nameNode := declaration.ChildByFieldName("name"); nameNode != nil {
+			return stripQuotes(nameNode.Content(code)), false
+		}
+		for i := 0; i < int(declaration.NamedChildCount()); i++ {
+			if declarator := declaration.NamedChild(i); declarator.Type() == "variable_declarator" {
+				if nameNode := declarator.ChildByFieldName("name"); nameNode != nil && nameNode.Type() == "identifier" {
+					return nameNode.Content(code), false
+				}
+			}
+		}
+		return "", false
+	}
+	return "", node.ChildByFieldName("value") != nil
+}
+
+// anonymousNaming returns the naming context of an entity node without a name
+// of its own
+func anonymousNaming(node *sitter.Node, lang Language, code []byte) (boundName string, defaultExport bool) {
+	if lang != LanguageJavaScript && lang != LanguageTypeScript {
+		return "", false
+	}
+	if node.Type() == "export_statement" {
+		return jsExportedName(node, code)
+	}
+	return jsBinding(node, code)
+}
+
+// nameAnonymousEntities returns entities with those without a name of their own
+// named by naming: after what they are bound to, or after the module filepath
+// names for default exports. The entities are copied rather than renamed in
+// place, as they may be shared through a ParseCache.
+func nameAnonymousEntities(entities []*ExtractedEntity, filepath string, naming AnonymousNaming) []*ExtractedEntity {
+	if naming == AnonymousNamingPlaceholder {
+		return entities
+	}
+
+	names := make(map[*ExtractedEntity]string)
+	for _, entity := range entities {
+		if entity.Name != anonymousName {
+			continue
+		}
+		name := entity.boundName
+		if name == "" && entity.defaultExport {
+			name = moduleName(filepath)
+		}
+		if name != "" {
+			names[entity] = name
+		}
+	}
+	if len(names) == 0 {
+		return entities
+	}
+
+	// Nested entities point at the name of their parent, so every entity is copied
+	// to repoint them at the copy
+	named := make([]*ExtractedEntity, len(entities))
+	parents := make(map[*string]*string, len(entities))
+	for i, entity := range entities {
+		clone := *entity
+		if name, ok := names[entity]; ok {
+			clone.Name = name
+		}
+		named[i] = &clone
+		parents[&entity.Name] = &clone.Name
+	}
+	for _, entity := range named {
+		if parent, ok := parents[entity.Parent]; ok {
+			entity.Parent = parent
+		}
+	}
+	return named
+}
+
+// moduleName returns the name of the module in filepath: the file name without
+// its extension, or the directory name for index files (Button for
+// components/Button/index.tsx)
+func moduleName(filepath string) string {
+	slashed := strings.ReplaceAll(filepath, "\\", "/")
+	base := path.Base(slashed)
+	name := strings.TrimSuffix(base, path.Ext(base))
+	if name == "index" {
+		name = path.Base(path.Dir(slashed))
+	}
+	if name == "." || name == "/" || name == "" {
+		return ""
+	}
+	return name
+}
@@ -0,0 +1,104 @@
+package codechunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const anonymousSource = `export default class {
+  render() {
+    return null
+  }
+}
+
+export const Button = (props) => null;
+
+const routes = {
+  home: function() {},
+};
+
+items.map(x => x);
+`
+
+// entityNames returns the names of entities, with the parent of nested ones
+func entityNames(entities []*ExtractedEntity) []string {
+	var names []string
+	for _, entity := range entities {
+		name := string(entity.Type) + " " + entity.Name
+		if entity.Parent != nil {
+			name += " in " + *entity.Parent
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+func TestAnonymousNaming(t *testing.T) {
+	cache := NewLRUParseCache(4)
+	tests := []struct {
+		filepath string
+		naming   AnonymousNaming
+		expected []string
+	}{
+		{"src/Card.jsx", "", []string{
+			"export Card", "class Card", "method render in Card",
+			"export Button", "function Button", "function home",
+		}},
+		{"src/Card/index.js", AnonymousNamingInferred, []string{
+			"export Card", "class Card", "method render in Card",
+			"export Button", "function Button", "function home",
+		}},
+		{"src/Card.jsx", AnonymousNamingPlaceholder, []string{
+			"export <anonymous>", "class <anonymous>", "method render in <anonymous>",
+			"export <anonymous>", "function <anonymous>", "function <anonymous>",
+		}},
+	}
+
+	for _, tt := range tests {
+		analysis, err := Analyze(tt.filepath, anonymousSource, &ChunkOptions{ParseCache: cache, AnonymousNaming: tt.naming})
+		if err != nil {
+			t.Fatal(err)
+		}
+		analysis.Close()
+		if got := entityNames(analysis.Entities); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("Analyze(%q) with %q naming:\n got %q\nwant %q", tt.filepath, tt.naming, got, tt.expected)
+		}
+	}
+}
+
+func TestAnonymousNamingScope(t *testing.T) {
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 30
+	chunks, err := Chunk("src/Card.tsx", anonymousSource, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, chunk := range chunks {
+		if !strings.Contains(chunk.Text, "render()") {
+			continue
+		}
+		scope := chunk.Context.Scope
+		if len(scope) == 0 || scope[len(scope)-1].Name != "Card" {
+			t.Errorf("Expected the default-exported class to be named after the file, got scope %+v", scope)
+		}
+		return
+	}
+	t.Fatal("Expected a chunk of the render method")
+}
+
+func TestModuleName(t *testing.T) {
+	tests := map[string]string{
+		"src/Button.tsx":                  "Button",
+		"src/components/Button/index.tsx": "Button",
+		`src\api\client.js`:               "client",
+		"button.component.ts":             "button.component",
+		"index.js":                        "",
+	}
+	for filepath, expected := range tests {
+		if got := moduleName(filepath); got != expected {
+			t.Errorf("moduleName(%q) = %q, expected %q", filepath, got, expected)
+		}
+	}
+}
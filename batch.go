@@ -0,0 +1,213 @@
+package codechunk
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// byteBudget limits the number of source bytes in flight across batch workers
+type byteBudget struct {
+	mu       sync.Mutex
+	limit    int64
+	used     int64
+	released chan struct{}
+}
+
+// newByteBudget creates a budget of limit bytes; a limit <= 0 means unbounded
+func newByteBudget(limit int64) *byteBudget {
+	return &byteBudget{
+		limit:    limit,
+		released: make(chan struct{}),
+	}
+}
+
+// acquire reserves n bytes, blocking until they are available or ctx is done.
+// Requests larger than the limit reserve the whole budget. It returns the amount
+// that was reserved, which must be passed to release.
+func (b *byteBudget) acquire(ctx context.Context, n int) (int64, error) {
+	if b.limit <= 0 {
+		return 0, nil
+	}
+
+	amount := int64(n)
+	if amount > b.limit {
+		amount = b.limit
+	}
+
+	for {
+		b.mu.Lock()
+		if b.used+amount <= b.limit {
+			b.used += amount
+			b.mu.Unlock()
+			return amount, nil
+		}
+		wait := b.released
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// release returns previously acquired bytes to the budget and wakes waiters
+func (b *byteBudget) release(amount int64) {
+	if amount == 0 {
+		return
+	}
+
+	b.mu.Lock()
+	b.used -= amount
+	close(b.released)
+	b.released = make(chan struct{})
+	b.mu.Unlock()
+}
+
+// resultSpiller writes completed batch results to a writer as JSON lines
+type resultSpiller struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// newResultSpiller creates a spiller for w; a nil writer disables spilling
+func newResultSpiller(w io.Writer) *resultSpiller {
+	if w == nil {
+		return nil
+	}
+	return &resultSpiller{enc: json.NewEncoder(w)}
+}
+
+// write spills the result, with lazy chunks materialized, and returns it with its
+// chunks dropped. If writing fails, the result is returned unchanged so no chunks
+// are lost.
+func (s *resultSpiller) write(result BatchResult) BatchResult {
+	if s == nil {
+		return result
+	}
+
+	spilled := result
+	spilled.Chunks = materializeChunks(result.Chunks)
+	s.mu.Lock()
+	err := s.enc.Encode(spilled)
+	s.mu.Unlock()
+
+	if err != nil {
+		return result
+	}
+	result.Chunks = nil
+	return result
+}
+
+// orderedWindowPerWorker is the number of results per worker that an ordered
+// batch stream buffers while waiting for an earlier file to complete
+const orderedWindowPerWorker = 4
+
+// sequencedResult is a completed result buffered by a resultSequencer, with what
+// to do once it is received
+type sequencedResult struct {
+	result   BatchResult
+	received func()
+}
+
+// resultSequencer re-sequences the results of a batch stream into input order.
+// Files start in input order and at most window files past the next result to
+// send are processed, which bounds the results buffered. Files also reserve the
+// byte budget in input order, so the next file never waits on the budget held by
+// later ones.
+type resultSequencer struct {
+	mu       sync.Mutex
+	changed  chan struct{} // Closed and replaced whenever the state below changes
+	window   int
+	admitted int // Index of the next file allowed to start
+	next     int // Index of the next result to send
+	pending  map[int]sequencedResult
+}
+
+// newResultSequencer creates a sequencer buffering up to window results
+func newResultSequencer(window int) *resultSequencer {
+	return &resultSequencer{
+		changed: make(chan struct{}),
+		window:  window,
+		pending: make(map[int]sequencedResult),
+	}
+}
+
+// wait blocks until ready reports true under the lock or ctx is done
+func (s *resultSequencer) wait(ctx context.Context, ready func() bool) error {
+	for {
+		s.mu.Lock()
+		if ready() {
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// notify wakes waiters; the caller holds the lock
+func (s *resultSequencer) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// start blocks until file i may start: once the files before it have started and
+// it is within the window. The caller must call started after reserving budget.
+func (s *resultSequencer) start(ctx context.Context, i int) error {
+	return s.wait(ctx, func() bool { return s.admitted == i && i < s.next+s.window })
+}
+
+// started lets the file after the one last admitted start
+func (s *resultSequencer) started() {
+	s.mu.Lock()
+	s.admitted++
+	s.notify()
+	s.mu.Unlock()
+}
+
+// put buffers the result of file i until the results before it are sent
+func (s *resultSequencer) put(i int, result sequencedResult) {
+	s.mu.Lock()
+	s.pending[i] = result
+	s.notify()
+	s.mu.Unlock()
+}
+
+// send sends the n results to ch in order as they complete, stopping early if ctx
+// is done
+func (s *resultSequencer) send(ctx context.Context, ch chan<- BatchResult, n int) {
+	for i := 0; i < n; i++ {
+		var result sequencedResult
+		err := s.wait(ctx, func() bool {
+			var ok bool
+			result, ok = s.pending[i]
+			return ok
+		})
+		if err != nil {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case ch <- result.result:
+		}
+		result.received()
+
+		s.mu.Lock()
+		delete(s.pending, i)
+		s.next++
+		s.notify()
+		s.mu.Unlock()
+	}
+}
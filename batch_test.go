@@ -0,0 +1,215 @@
+package codechunk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestByteBudgetBlocksUntilRelease(t *testing.T) {
+	budget := newByteBudget(100)
+
+	first, err := budget.acquire(context.Background(), 80)
+	if err != nil || first != 80 {
+		t.Fatalf("Expected to reserve 80 bytes, got %d (%v)", first, err)
+	}
+
+	acquired := make(chan int64)
+	go func() {
+		n, _ := budget.acquire(context.Background(), 50)
+		acquired <- n
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("acquire should block while the budget is exhausted")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	budget.release(first)
+
+	select {
+	case n := <-acquired:
+		if n != 50 {
+			t.Errorf("Expected to reserve 50 bytes, got %d", n)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("acquire did not unblock after release")
+	}
+}
+
+func TestByteBudgetClampsAndCancels(t *testing.T) {
+	budget := newByteBudget(10)
+
+	n, err := budget.acquire(context.Background(), 1000)
+	if err != nil || n != 10 {
+		t.Fatalf("Expected oversized request to reserve the whole budget, got %d (%v)", n, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := budget.acquire(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+
+	unbounded := newByteBudget(0)
+	if n, err := unbounded.acquire(context.Background(), 1<<30); err != nil || n != 0 {
+		t.Errorf("Unbounded budget should not reserve, got %d (%v)", n, err)
+	}
+}
+
+func TestChunkBatchStreamWithMaxInFlightBytes(t *testing.T) {
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package a\n\nfunc A() {}\n"},
+		{Filepath: "b.go", Code: "package b\n\nfunc B() {}\n"},
+		{Filepath: "c.go", Code: "package c\n\nfunc C() {}\n"},
+	}
+
+	ch := ChunkBatchStream(files, &BatchOptions{Concurrency: 3, MaxInFlightBytes: 30})
+
+	count := 0
+	for result := range ch {
+		if result.Error != nil {
+			t.Errorf("Unexpected error for %s: %v", result.Filepath, result.Error)
+		}
+		// Simulate a slow consumer
+		time.Sleep(5 * time.Millisecond)
+		count++
+	}
+
+	if count != len(files) {
+		t.Errorf("Expected %d results, got %d", len(files), count)
+	}
+}
+
+func TestChunkBatchStreamOrdered(t *testing.T) {
+	var files []FileInput
+	for i := 0; i < 40; i++ {
+		files = append(files, FileInput{
+			Filepath: fmt.Sprintf("f%d.go", i),
+			Code:     fmt.Sprintf("package p\n\nfunc F%d() {}\n", i),
+		})
+	}
+	// Earlier files of each group of five take longer, so they complete out of order
+	slow := func(text string, ctx ChunkContext, overlapText string) string {
+		var i int
+		fmt.Sscanf(ctx.Filepath, "f%d.go", &i)
+		time.Sleep(time.Duration(5-i%5) * time.Millisecond)
+		return text
+	}
+
+	for _, maxInFlight := range []int64{0, 60} {
+		opts := &BatchOptions{Concurrency: 4, Ordered: true, MaxInFlightBytes: maxInFlight}
+		opts.FormatFunc = slow
+		var got []string
+		for result := range ChunkBatchStream(files, opts) {
+			if result.Error != nil {
+				t.Errorf("Unexpected error for %s: %v", result.Filepath, result.Error)
+			}
+			got = append(got, result.Filepath)
+		}
+		if len(got) != len(files) {
+			t.Fatalf("Expected %d results, got %d", len(files), len(got))
+		}
+		for i, path := range got {
+			if path != files[i].Filepath {
+				t.Fatalf("MaxInFlightBytes %d: expected results in input order, got %v", maxInFlight, got)
+			}
+		}
+	}
+}
+
+func TestResultSequencerWindow(t *testing.T) {
+	seq := newResultSequencer(2)
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 2; i++ {
+		if err := seq.start(ctx, i); err != nil {
+			t.Fatalf("Expected file %d to start: %v", i, err)
+		}
+		seq.started()
+	}
+	// The window is full until the first result is sent
+	if err := seq.start(ctx, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected file 2 to wait for the window, got %v", err)
+	}
+
+	ch := make(chan BatchResult, 2)
+	seq.put(1, sequencedResult{result: BatchResult{Filepath: "b"}, received: func() {}})
+	seq.put(0, sequencedResult{result: BatchResult{Filepath: "a"}, received: func() {}})
+	seq.send(context.Background(), ch, 2)
+	if a, b := <-ch, <-ch; a.Filepath != "a" || b.Filepath != "b" {
+		t.Errorf("Expected results in order, got %s then %s", a.Filepath, b.Filepath)
+	}
+	if err := seq.start(context.Background(), 2); err != nil {
+		t.Errorf("Expected file 2 to start once results are sent: %v", err)
+	}
+}
+
+func TestChunkBatchSpillWriter(t *testing.T) {
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package a\n\nfunc A() {}\n"},
+		{Filepath: "b.css", Code: "body {}"},
+	}
+
+	var buf bytes.Buffer
+	results := ChunkBatch(files, &BatchOptions{SpillWriter: &buf})
+
+	for _, r := range results {
+		if r.Chunks != nil {
+			t.Errorf("Expected spilled result for %s to have nil chunks", r.Filepath)
+		}
+	}
+	if !errors.Is(results[1].Error, ErrUnsupportedLanguage) {
+		t.Errorf("Expected error to be kept on returned result, got %v", results[1].Error)
+	}
+
+	spilled := make(map[string]BatchResult)
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var r BatchResult
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Invalid spilled line %q: %v", scanner.Text(), err)
+		}
+		spilled[r.Filepath] = r
+	}
+
+	if len(spilled) != 2 {
+		t.Fatalf("Expected 2 spilled results, got %d", len(spilled))
+	}
+	if len(spilled["a.go"].Chunks) == 0 {
+		t.Error("Expected spilled chunks for a.go")
+	}
+}
+
+func TestChunkBatchSpillWriterLazyText(t *testing.T) {
+	files := []FileInput{{Filepath: "main.go", Code: lazyTextSource}}
+	opts := ChunkOptions{MaxChunkSize: 80}
+	eager, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	opts.LazyText = true
+	ChunkBatch(files, &BatchOptions{ChunkOptions: opts, SpillWriter: &buf})
+
+	var spilled BatchResult
+	if err := json.Unmarshal(buf.Bytes(), &spilled); err != nil {
+		t.Fatalf("Invalid spilled result %q: %v", buf.String(), err)
+	}
+	if len(spilled.Chunks) != len(eager) {
+		t.Fatalf("Expected %d spilled chunks, got %d", len(eager), len(spilled.Chunks))
+	}
+	for i, chunk := range spilled.Chunks {
+		if chunk.Text != eager[i].Text || chunk.ContextualizedText != eager[i].ContextualizedText {
+			t.Errorf("Chunk %d: expected the spilled text of the eager chunk, got %q", i, chunk.Text)
+		}
+	}
+}
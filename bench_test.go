@@ -0,0 +1,212 @@
+package codechunk
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// benchFixtureSize is the size in bytes the fixtures are grown to, large enough
+// for costs that grow with the number of entities or chunks to show
+const benchFixtureSize = 128 << 10
+
+// benchFixtures are the files in testdata/bench. Each has a header and a body
+// separated by a "bench:repeat" comment line; the body is repeated to reach
+// benchFixtureSize, as in large generated or vendored files.
+var benchFixtures = []string{"service.go", "models.py", "handlers.ts", "lib.rs", "Service.java"}
+
+var (
+	benchSourcesOnce sync.Once
+	benchSources     map[string][]byte
+)
+
+// loadBenchSources grows every fixture to benchFixtureSize
+func loadBenchSources(b *testing.B) map[string][]byte {
+	b.Helper()
+	benchSourcesOnce.Do(func() {
+		benchSources = make(map[string][]byte)
+		for _, name := range benchFixtures {
+			data, err := os.ReadFile(filepath.Join("testdata", "bench", name))
+			if err != nil {
+				panic(err)
+			}
+			lines := strings.SplitAfter(string(data), "\n")
+			for i, line := range lines {
+				if strings.Contains(line, "bench:repeat") {
+					header, body := strings.Join(lines[:i], ""), strings.Join(lines[i+1:], "")
+					var source strings.Builder
+					source.WriteString(header)
+					for source.Len() < benchFixtureSize {
+						source.WriteString(body)
+					}
+					benchSources[name] = []byte(source.String())
+					break
+				}
+			}
+		}
+	})
+	if len(benchSources) != len(benchFixtures) {
+		b.Fatal("bench fixtures are missing a bench:repeat line")
+	}
+	return benchSources
+}
+
+// runBenchFixtures runs fn as a sub-benchmark for each fixture, reporting the
+// throughput in source bytes
+func runBenchFixtures(b *testing.B, fn func(b *testing.B, name string, code []byte, lang Language)) {
+	sources := loadBenchSources(b)
+	for _, name := range benchFixtures {
+		code := sources[name]
+		lang := DetectLanguage(name)
+		b.Run(string(lang), func(b *testing.B) {
+			b.SetBytes(int64(len(code)))
+			b.ReportAllocs()
+			fn(b, name, code, lang)
+		})
+	}
+}
+
+// parseBenchFixture parses and extracts a fixture outside the timed section
+func parseBenchFixture(b *testing.B, code []byte, lang Language) *ParsedFile {
+	b.Helper()
+	parsed, err := parseAndExtract(code, lang, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(parsed.Close)
+	return parsed
+}
+
+// benchScopeTree builds the qualified scope tree of a parsed fixture
+func benchScopeTree(parsed *ParsedFile, name string, code []byte, lang Language) *ScopeTree {
+	scopeTree := buildScopeTree(parsed.Entities)
+	scopeTree.Exports = append(scopeTree.Exports, parsed.Exports...)
+	qualifyScopeTree(scopeTree, fileNamespace(parsed.Tree.RootNode(), code, name, lang), lang)
+	return scopeTree
+}
+
+func BenchmarkParse(b *testing.B) {
+	ctx := context.Background()
+	runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+		for i := 0; i < b.N; i++ {
+			result, err := parseWithContext(ctx, code, lang)
+			if err != nil {
+				b.Fatal(err)
+			}
+			result.Tree.Close()
+		}
+	})
+}
+
+func BenchmarkExtract(b *testing.B) {
+	runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+		root := parseBenchFixture(b, code, lang).Tree.RootNode()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			extractEntities(root, lang, code)
+		}
+	})
+}
+
+// BenchmarkExtractSiblings extracts Go files of growing numbers of documented
+// top-level functions. The time per entity stays flat as long as no entity is
+// extracted by scanning its siblings, as finding a leading comment once did.
+func BenchmarkExtractSiblings(b *testing.B) {
+	for _, n := range []int{500, 2000, 8000} {
+		var source strings.Builder
+		source.WriteString("package siblings\n")
+		for i := 0; i < n; i++ {
+			fmt.Fprintf(&source, "\n// F%d is documented.\nfunc F%d() {}\n", i, i)
+		}
+		code := []byte(source.String())
+		b.Run(fmt.Sprintf("entities=%d", n), func(b *testing.B) {
+			root := parseBenchFixture(b, code, LanguageGo).Tree.RootNode()
+			b.SetBytes(int64(len(code)))
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				extractEntities(root, LanguageGo, code)
+			}
+			b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N*n), "ns/entity")
+		})
+	}
+}
+
+func BenchmarkScopeTree(b *testing.B) {
+	runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+		parsed := parseBenchFixture(b, code, lang)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			benchScopeTree(parsed, name, code, lang)
+		}
+	})
+}
+
+func BenchmarkAssignWindows(b *testing.B) {
+	runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+		children := getNodeChildren(parseBenchFixture(b, code, lang).Tree.RootNode())
+		opts := DefaultChunkOptions()
+		logger := loggerFor(opts)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			assignWindows(children, code, preprocessNwsCumsum(code), opts, lang, logger)
+		}
+	})
+}
+
+func BenchmarkChunkContext(b *testing.B) {
+	runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+		parsed := parseBenchFixture(b, code, lang)
+		scopeTree := benchScopeTree(parsed, name, code, lang)
+		opts := DefaultChunkOptions()
+		_, windows := assignWindows(getNodeChildren(parsed.Tree.RootNode()), code, preprocessNwsCumsum(code), opts, lang, loggerFor(opts))
+		positions := newPositionIndex(code)
+		texts := make([]*rebuiltText, len(windows))
+		for i, window := range windows {
+			texts[i] = rebuildText(window, code, positions)
+		}
+		b.ReportMetric(float64(len(texts)), "chunks")
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			for _, text := range texts {
+				buildChunkContext(text, scopeTree, parsed.Calls, importUses(parsed, code, opts), opts, name, lang)
+			}
+		}
+	})
+}
+
+func BenchmarkChunk(b *testing.B) {
+	for _, mode := range []ContextMode{ContextModeMinimal, ContextModeFull} {
+		b.Run(fmt.Sprintf("context=%s", mode), func(b *testing.B) {
+			runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+				opts := DefaultChunkOptions()
+				opts.ContextMode = mode
+				for i := 0; i < b.N; i++ {
+					if _, err := ChunkBytes(name, code, &opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkChunkParallel(b *testing.B) {
+	for _, parallelism := range []int{1, 4, -1} {
+		b.Run(fmt.Sprintf("parallelism=%d", parallelism), func(b *testing.B) {
+			runBenchFixtures(b, func(b *testing.B, name string, code []byte, lang Language) {
+				opts := DefaultChunkOptions()
+				opts.Parallelism = parallelism
+				for i := 0; i < b.N; i++ {
+					if _, err := ChunkBytes(name, code, &opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
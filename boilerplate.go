@@ -0,0 +1,122 @@
+package codechunk
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// DefaultBoilerplatePatterns are the patterns StripBoilerplate matches leading
+// comment groups against when ChunkOptions.BoilerplatePatterns is empty
+var DefaultBoilerplatePatterns = []string{
+	`(?i)\bcopyright\b`,
+	`(?i)\blicen[cs]ed?\b`,
+	`(?i)\ball rights reserved\b`,
+	`SPDX-License-Identifier:`,
+}
+
+// spdxPattern matches an SPDX license identifier
+var spdxPattern = regexp.MustCompile(`SPDX-License-Identifier:\s*([^\s*]+(?:\s+(?:AND|OR|WITH)\s+[^\s*]+)*)`)
+
+// BoilerplateInfo describes the license header or banner stripped from the top of
+// a file by ChunkOptions.StripBoilerplate
+type BoilerplateInfo struct {
+	LineRange LineRange `json:"lineRange"`         // Lines of the stripped comments
+	License   string    `json:"license,omitempty"` // SPDX license identifier, if the header has one
+}
+
+// stripBoilerplate removes the license headers and banners from the top-level
+// nodes of a file: the comment groups before any code whose text matches one of
+// patterns, stopping at the first group that doesn't. Since chunk text is
+// contiguous, a shebang line above the header is stripped with it. Returns the
+// remaining nodes and a description of the stripped comments, or nil.
+func stripBoilerplate(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, patterns []*regexp.Regexp) ([]*sitter.Node, *BoilerplateInfo) {
+	var stripped []*sitter.Node
+	i := 0
+	for i < len(nodes) {
+		node := nodes[i]
+		if getNwsCountForNode(node, cumsum) == 0 || isShebang(node, code) {
+			i++
+			continue
+		}
+		if !isCommentNode(node) {
+			break
+		}
+
+		// A comment group runs until a blank line or a node that is not a comment
+		end := i + 1
+		for end < len(nodes) && (getNwsCountForNode(nodes[end], cumsum) == 0 || isCommentNode(nodes[end])) {
+			if getNwsCountForNode(nodes[end], cumsum) > 0 && blankLineBefore(nodes[end], code) {
+				break
+			}
+			end++
+		}
+		if !matchesAny(patterns, groupText(nodes[i:end], code)) {
+			break
+		}
+		for _, member := range nodes[i:end] {
+			if isCommentNode(member) {
+				stripped = append(stripped, member)
+			}
+		}
+		i = end
+	}
+	if len(stripped) == 0 {
+		return nodes, nil
+	}
+	remaining := nodes[i:]
+
+	info := &BoilerplateInfo{
+		LineRange: LineRange{
+			Start: nodeLineRange(stripped[0]).Start,
+			End:   nodeLineRange(stripped[len(stripped)-1]).End,
+		},
+	}
+	if match := spdxPattern.FindStringSubmatch(groupText(stripped, code)); match != nil {
+		info.License = strings.TrimSpace(match[1])
+	}
+	return remaining, info
+}
+
+// boilerplatePatterns compiles the boilerplate patterns of opts, or the defaults
+func boilerplatePatterns(opts ChunkOptions) ([]*regexp.Regexp, error) {
+	sources := opts.BoilerplatePatterns
+	if len(sources) == 0 {
+		sources = DefaultBoilerplatePatterns
+	}
+	patterns := make([]*regexp.Regexp, len(sources))
+	for i, source := range sources {
+		pattern, err := regexp.Compile(source)
+		if err != nil {
+			return nil, fmt.Errorf("invalid boilerplate pattern %q: %w", source, err)
+		}
+		patterns[i] = pattern
+	}
+	return patterns, nil
+}
+
+// isShebang reports whether a node is a script's shebang line
+func isShebang(node *sitter.Node, code []byte) bool {
+	return node.StartByte() == 0 && strings.HasPrefix(node.Content(code), "#!")
+}
+
+// groupText joins the text of nodes with newlines
+func groupText(nodes []*sitter.Node, code []byte) string {
+	texts := make([]string, len(nodes))
+	for i, node := range nodes {
+		texts[i] = node.Content(code)
+	}
+	return strings.Join(texts, "\n")
+}
+
+// matchesAny reports whether text matches any of patterns
+func matchesAny(patterns []*regexp.Regexp, text string) bool {
+	for _, pattern := range patterns {
+		if pattern.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
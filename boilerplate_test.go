@@ -0,0 +1,80 @@
+package codechunk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestChunkStripBoilerplate(t *testing.T) {
+	code := `// Copyright 2024 The Authors. All rights reserved.
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build linux
+
+// Package server serves requests.
+package server
+
+func Serve() {}
+`
+	opts := NewChunkOptions(WithStripBoilerplate())
+	chunks, err := Chunk("server.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("expected 1 chunk, got %d", len(chunks))
+	}
+
+	chunk := chunks[0]
+	if strings.Contains(chunk.Text, "Copyright") || strings.Contains(chunk.ContextualizedText, "Copyright") {
+		t.Errorf("expected the license header stripped, got %q", chunk.Text)
+	}
+	if !strings.HasPrefix(chunk.Text, "//go:build linux") || !strings.Contains(chunk.Text, "// Package server") {
+		t.Errorf("expected the build constraint and package doc kept, got %q", chunk.Text)
+	}
+	expected := BoilerplateInfo{LineRange: LineRange{Start: 0, End: 1}, License: "Apache-2.0"}
+	if chunk.Context.Boilerplate == nil || *chunk.Context.Boilerplate != expected {
+		t.Errorf("expected boilerplate %+v, got %+v", expected, chunk.Context.Boilerplate)
+	}
+
+	opts = NewChunkOptions()
+	chunks, err = Chunk("server.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !strings.HasPrefix(chunks[0].Text, "// Copyright") || chunks[0].Context.Boilerplate != nil {
+		t.Error("expected the header kept without StripBoilerplate")
+	}
+}
+
+func TestChunkStripBoilerplatePatterns(t *testing.T) {
+	code := "#!/usr/bin/env python3\n" +
+		"# ==========================\n" +
+		"# ACME Corp internal tool\n" +
+		"# ==========================\n\n" +
+		"# Entry point\n" +
+		"def main():\n    pass\n"
+
+	opts := NewChunkOptions(WithStripBoilerplate(`ACME Corp`))
+	chunks, err := Chunk("tool.py", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	text := chunks[0].Text
+	if text != "# Entry point\ndef main():\n    pass" {
+		t.Errorf("expected only the banner stripped, got %q", text)
+	}
+	if info := chunks[0].Context.Boilerplate; info == nil || info.LineRange != (LineRange{Start: 1, End: 3}) || info.License != "" {
+		t.Errorf("unexpected boilerplate %+v", info)
+	}
+
+	opts = NewChunkOptions(WithStripBoilerplate(`(`))
+	var chunkErr *ChunkError
+	if _, err := Chunk("tool.py", code, &opts); !errors.As(err, &chunkErr) || chunkErr.Stage != StageWindow {
+		t.Errorf("expected a window stage error for an invalid pattern, got %v", err)
+	}
+	if _, err := ChunkStream("tool.py", code, &opts); !errors.As(err, &chunkErr) || chunkErr.Stage != StageWindow {
+		t.Errorf("expected a window stage error from ChunkStream for an invalid pattern, got %v", err)
+	}
+}
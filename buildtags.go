@@ -0,0 +1,126 @@
+package codechunk
+
+import (
+	"go/build/constraint"
+	"path"
+	"strings"
+)
+
+// BuildConstraints describes the platforms a Go file is built for, so retrieval
+// can prefer the implementation of a symbol for the platform at hand when it is
+// defined in several files, such as file_linux.go and file_windows.go
+type BuildConstraints struct {
+	Expr   string `json:"expr,omitempty"`   // //go:build expression, or the // +build lines of older files joined with &&
+	GOOS   string `json:"goos,omitempty"`   // Operating system of the file name suffix, linux for file_linux.go
+	GOARCH string `json:"goarch,omitempty"` // Architecture of the file name suffix, amd64 for file_linux_amd64.go
+}
+
+// knownOS and knownArch are the GOOS and GOARCH values the go command recognizes
+// in file name suffixes
+var (
+	knownOS = map[string]bool{
+		"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true,
+		"hurd": true, "illumos": true, "ios": true, "js": true, "linux": true, "nacl": true,
+		"netbsd": true, "openbsd": true, "plan9": true, "solaris": true, "wasip1": true,
+		"windows": true, "zos": true,
+	}
+	knownArch = map[string]bool{
+		"386": true, "amd64": true, "amd64p32": true, "arm": true, "armbe": true,
+		"arm64": true, "arm64be": true, "loong64": true, "mips": true, "mipsle": true,
+		"mips64": true, "mips64le": true, "mips64p32": true, "mips64p32le": true,
+		"ppc": true, "ppc64": true, "ppc64le": true, "riscv": true, "riscv64": true,
+		"s390": true, "s390x": true, "sparc": true, "sparc64": true, "wasm": true,
+	}
+)
+
+// goBuildConstraints returns the build constraints of a Go file, from the
+// constraint lines before its package clause and the GOOS and GOARCH suffixes of
+// its name. Returns nil for other files and Go files built everywhere.
+func goBuildConstraints(filepath string, code []byte) *BuildConstraints {
+	name := path.Base(strings.ReplaceAll(filepath, "\\", "/"))
+	if path.Ext(name) != ".go" {
+		return nil
+	}
+
+	build := &BuildConstraints{Expr: goBuildExpr(string(code))}
+	build.GOOS, build.GOARCH = goFileSuffix(strings.TrimSuffix(name, ".go"))
+	if *build == (BuildConstraints{}) {
+		return nil
+	}
+	return build
+}
+
+// goBuildExpr returns the build expression of Go source: its //go:build line, or
+// its // +build lines joined with &&, among the comments before the package
+// clause. Returns "" if there are none or they don't parse.
+func goBuildExpr(code string) string {
+	var plusBuild constraint.Expr
+	for rest := code; rest != ""; {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "//") {
+			break
+		}
+		if !constraint.IsGoBuild(line) && !constraint.IsPlusBuild(line) {
+			continue
+		}
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			continue
+		}
+		if constraint.IsGoBuild(line) {
+			return expr.String()
+		}
+		if plusBuild == nil {
+			plusBuild = expr
+		} else {
+			plusBuild = &constraint.AndExpr{X: plusBuild, Y: expr}
+		}
+	}
+	if plusBuild == nil {
+		return ""
+	}
+	return plusBuild.String()
+}
+
+// goFileSuffix returns the GOOS and GOARCH a Go file name without its extension
+// is restricted to by its suffixes, as the go command reads them: name_GOOS,
+// name_GOARCH or name_GOOS_GOARCH, followed by _test for tests. A name that is
+// only a GOOS or GOARCH, such as linux.go, has no suffix.
+func goFileSuffix(name string) (goos, goarch string) {
+	i := strings.Index(name, "_")
+	if i < 0 {
+		return "", ""
+	}
+	parts := strings.Split(name[i+1:], "_")
+	if n := len(parts); n > 0 && parts[n-1] == "test" {
+		parts = parts[:n-1]
+	}
+
+	n := len(parts)
+	switch {
+	case n >= 2 && knownOS[parts[n-2]] && knownArch[parts[n-1]]:
+		return parts[n-2], parts[n-1]
+	case n >= 1 && knownOS[parts[n-1]]:
+		return parts[n-1], ""
+	case n >= 1 && knownArch[parts[n-1]]:
+		return "", parts[n-1]
+	}
+	return "", ""
+}
+
+// constrainChunks sets the build constraints of a Go file on the context of its
+// chunks
+func constrainChunks(filepath string, code []byte, chunks []CodeChunk) {
+	build := goBuildConstraints(filepath, code)
+	if build == nil {
+		return
+	}
+	for i := range chunks {
+		chunks[i].Context.Build = build
+	}
+}
@@ -0,0 +1,57 @@
+package codechunk
+
+import "testing"
+
+func TestGoBuildConstraints(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		want     *BuildConstraints
+	}{
+		{"poll.go", "package poll\n", nil},
+		{"poll_linux.go", "package poll\n", &BuildConstraints{GOOS: "linux"}},
+		{"poll_linux_amd64.go", "package poll\n", &BuildConstraints{GOOS: "linux", GOARCH: "amd64"}},
+		{"poll_arm64_test.go", "package poll\n", &BuildConstraints{GOARCH: "arm64"}},
+		{"internal/windows.go", "package internal\n", nil},
+		{"poll_unix.go", "// Copyright 2024\n\n//go:build linux || darwin\n\npackage poll\n", &BuildConstraints{Expr: "linux || darwin"}},
+		{"poll_wasm.go", "//go:build js\n\npackage poll\n", &BuildConstraints{Expr: "js", GOARCH: "wasm"}},
+		{"old.go", "// +build linux darwin\n// +build !cgo\n\npackage old\n", &BuildConstraints{Expr: "(linux || darwin) && !cgo"}},
+		{"late.go", "package late\n\n//go:build ignore\n", nil},
+		{"poll_linux.py", "x = 1\n", nil},
+	}
+
+	for _, tt := range tests {
+		got := goBuildConstraints(tt.filepath, []byte(tt.code))
+		if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+			t.Errorf("%s: expected %+v, got %+v", tt.filepath, tt.want, got)
+		}
+	}
+}
+
+func TestChunkBuildConstraints(t *testing.T) {
+	code := "//go:build !windows\n\npackage poll\n\nfunc Wait() {}\n"
+	chunks, err := Chunk("poll/wait_linux.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := BuildConstraints{Expr: "!windows", GOOS: "linux"}
+	for _, chunk := range chunks {
+		if chunk.Context.Build == nil || *chunk.Context.Build != want {
+			t.Errorf("Expected build constraints %+v, got %+v", want, chunk.Context.Build)
+		}
+		metadata := chunk.Metadata()
+		if metadata[MetadataBuild] != "!windows" || metadata[MetadataGOOS] != "linux" {
+			t.Errorf("Expected the build constraints in the metadata, got %v", metadata)
+		}
+	}
+
+	stream, err := ChunkStream("poll/wait_linux.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range stream {
+		if chunk.Context.Build == nil || *chunk.Context.Build != want {
+			t.Errorf("Expected streamed build constraints %+v, got %+v", want, chunk.Context.Build)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package codechunk
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// ParsedFile holds the result of parsing a file and extracting its entities.
+// Values stored in a ParseCache are shared between callers and must be treated as
+// read-only; chunking works on a copy of Tree, so the Node of cached entities
+// belongs to the cached tree.
+type ParsedFile struct {
+	Tree     *sitter.Tree       // The parsed syntax tree
+	Entities []*ExtractedEntity // Entities extracted from the tree
+	Exports  []*ExtractedEntity // Exports derived from naming or visibility rules
+	Calls    []CallSite         // Call expressions in source order
+	FileDoc  string             // Documentation of the whole file, if any
+	Error    *ParseError        // Parse error if any
+
+	mu     sync.RWMutex // Guards the tree while it is copied from a cache and closed
+	closed bool
+}
+
+// Close frees the native memory held by the syntax tree. The parsed file must not be
+// used for chunking afterwards; entities and call sites remain valid. Close is
+// idempotent and safe to call while the file is read from a cache, and trees that
+// are never closed are freed by a finalizer.
+func (p *ParsedFile) Close() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Tree != nil && !p.closed {
+		p.Tree.Close()
+	}
+	p.closed = true
+}
+
+// copyTree returns a copy of the tree owned by the caller, or nil once the parsed
+// file is closed, such as by eviction from a cache
+func (p *ParsedFile) copyTree() *sitter.Tree {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.Tree == nil || p.closed {
+		return nil
+	}
+	return p.Tree.Copy()
+}
+
+// ParseCache stores parsed files keyed by ParseCacheKey, so that re-chunking
+// unchanged content skips tree-sitter parsing and entity extraction.
+// Implementations must be safe for concurrent use. They may Close the parsed files
+// they evict; a file closed after Get returns it counts as a cache miss.
+type ParseCache interface {
+	Get(key string) (*ParsedFile, bool)
+	Put(key string, parsed *ParsedFile)
+}
+
+// ParseCacheKey returns the cache key for code parsed as lang: a SHA-256 hash of
+// the language and content.
+func ParseCacheKey(lang Language, code []byte) string {
+	h := sha256.New()
+	h.Write([]byte(lang))
+	h.Write([]byte{0})
+	h.Write(code)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// LRUParseCache is an in-memory ParseCache that evicts the least recently used
+// entries once it holds more than its capacity, closing their trees.
+type LRUParseCache struct {
+	mu       sync.Mutex
+	capacity int
+	order    *list.List
+	entries  map[string]*list.Element
+}
+
+// lruEntry is an element of the LRU list
+type lruEntry struct {
+	key    string
+	parsed *ParsedFile
+}
+
+// NewLRUParseCache creates an LRU cache holding up to capacity parsed files
+// (default: 1024 if capacity <= 0).
+func NewLRUParseCache(capacity int) *LRUParseCache {
+	if capacity <= 0 {
+		capacity = 1024
+	}
+	return &LRUParseCache{
+		capacity: capacity,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+}
+
+// Get returns the parsed file for key and marks it as recently used.
+func (c *LRUParseCache) Get(key string) (*ParsedFile, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(*lruEntry).parsed, true
+}
+
+// Put stores the parsed file for key, evicting the least recently used entry if
+// the cache is full. Evicted and replaced parsed files are closed.
+func (c *LRUParseCache) Put(key string, parsed *ParsedFile) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry)
+		if entry.parsed != parsed {
+			entry.parsed.Close()
+		}
+		entry.parsed = parsed
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry{key: key, parsed: parsed})
+
+	for c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		entry := oldest.Value.(*lruEntry)
+		delete(c.entries, entry.key)
+		entry.parsed.Close()
+	}
+}
+
+// Len returns the number of cached entries.
+func (c *LRUParseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// parseAndExtract parses code and extracts its entities, consulting the cache if set.
+// The returned tree is owned by the caller, who should Close it once chunking is done.
+func parseAndExtract(code []byte, lang Language, cache ParseCache) (*ParsedFile, error) {
+	return parseAndExtractMeasured(context.Background(), code, lang, cache, nil, nil)
+}
+
+// parseAndExtractMeasured is parseAndExtract recording parse and extraction timings in m.
+// If parser is set it is used instead of a pooled parser. Parsing and extraction
+// stop once ctx is done, returning an error matching ErrCancelled. Errors are
+// *ChunkError values without a filepath, for the caller to set with stageError.
+func parseAndExtractMeasured(ctx context.Context, code []byte, lang Language, cache ParseCache, m *FileMetrics, parser *languageParser) (*ParsedFile, error) {
+	if m == nil {
+		m = &FileMetrics{}
+	}
+
+	var key string
+	if cache != nil {
+		key = ParseCacheKey(lang, code)
+		if cached, ok := cache.Get(key); ok {
+			if tree := cached.copyTree(); tree != nil {
+				m.CacheHit = true
+				return &ParsedFile{
+					Tree:     tree,
+					Entities: cached.Entities,
+					Exports:  cached.Exports,
+					Calls:    cached.Calls,
+					FileDoc:  cached.FileDoc,
+					Error:    cached.Error,
+				}, nil
+			}
+		}
+	}
+
+	start := time.Now()
+	var parseResult *ParseResult
+	var err error
+	if parser != nil {
+		parseResult, err = parser.parse(ctx, code, lang)
+	} else {
+		parseResult, err = parseWithContext(ctx, code, lang)
+	}
+	m.ParseTime = time.Since(start)
+	if ctx.Err() != nil {
+		if err == nil {
+			parseResult.Tree.Close()
+		}
+		return nil, &ChunkError{Stage: StageParse, Language: lang, Err: cancelledError(ctx)}
+	}
+	if errors.Is(err, ErrUnsupportedLanguage) {
+		return nil, &ChunkError{Stage: StageDetect, Language: lang, Err: err}
+	}
+	if err != nil {
+		return nil, &ChunkError{Stage: StageParse, Language: lang, Err: err}
+	}
+
+	start = time.Now()
+	rootNode := parseResult.Tree.RootNode()
+	entities, err := extractEntitiesContext(ctx, rootNode, lang, code)
+	if err != nil {
+		parseResult.Tree.Close()
+		return nil, &ChunkError{Stage: StageExtract, Language: lang, Err: cancelledError(ctx)}
+	}
+	parsed := &ParsedFile{
+		Tree:     parseResult.Tree,
+		Entities: entities,
+		Exports:  extractExports(rootNode, lang, code, entities),
+		Calls:    extractCallSites(rootNode, lang, code),
+		FileDoc:  extractFileDoc(rootNode, lang, code),
+		Error:    parseResult.Error,
+	}
+	releaseNodes(parsed.Entities)
+	releaseNodes(parsed.Exports)
+	positions := newPositionIndex(code)
+	setSpans(parsed.Entities, positions)
+	setSpans(parsed.Exports, positions)
+	m.ExtractTime = time.Since(start)
+
+	if cache != nil {
+		// The copy is made first, as the cache may close parsed once it is stored
+		tree := parsed.Tree.Copy()
+		cache.Put(key, parsed)
+		return &ParsedFile{
+			Tree:     tree,
+			Entities: parsed.Entities,
+			Exports:  parsed.Exports,
+			Calls:    parsed.Calls,
+			FileDoc:  parsed.FileDoc,
+			Error:    parsed.Error,
+		}, nil
+	}
+
+	return parsed, nil
+}
+
+// releaseNodes drops the AST node references of extracted entities. Everything
+// chunking needs from a node is copied during extraction, so this keeps cached or
+// returned entities from pinning the tree and lets it be closed.
+func releaseNodes(entities []*ExtractedEntity) {
+	for _, entity := range entities {
+		entity.Node = nil
+	}
+}
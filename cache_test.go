@@ -0,0 +1,205 @@
+package codechunk
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// countingCache wraps a ParseCache and counts hits
+type countingCache struct {
+	ParseCache
+	hits atomic.Int32
+}
+
+func (c *countingCache) Get(key string) (*ParsedFile, bool) {
+	parsed, ok := c.ParseCache.Get(key)
+	if ok {
+		c.hits.Add(1)
+	}
+	return parsed, ok
+}
+
+func TestParseCacheKey(t *testing.T) {
+	code := []byte("def f(): pass")
+	if ParseCacheKey(LanguagePython, code) != ParseCacheKey(LanguagePython, code) {
+		t.Error("Expected stable key for identical input")
+	}
+	if ParseCacheKey(LanguagePython, code) == ParseCacheKey(LanguageJavaScript, code) {
+		t.Error("Expected key to depend on language")
+	}
+	if ParseCacheKey(LanguagePython, code) == ParseCacheKey(LanguagePython, []byte("def g(): pass")) {
+		t.Error("Expected key to depend on content")
+	}
+}
+
+func TestLRUParseCacheEviction(t *testing.T) {
+	cache := NewLRUParseCache(2)
+	a, b, c := &ParsedFile{}, &ParsedFile{}, &ParsedFile{}
+
+	cache.Put("a", a)
+	cache.Put("b", b)
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Expected a to be cached")
+	}
+	cache.Put("c", c)
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Expected least recently used entry b to be evicted")
+	}
+	if got, ok := cache.Get("a"); !ok || got != a {
+		t.Error("Expected a to remain cached")
+	}
+	if got, ok := cache.Get("c"); !ok || got != c {
+		t.Error("Expected c to be cached")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cache.Len())
+	}
+}
+
+func TestLRUParseCacheClosesEvicted(t *testing.T) {
+	cache := NewLRUParseCache(1)
+	a, b := []byte("package a\n\nfunc A() {}\n"), []byte("package b\n\nfunc B() {}\n")
+
+	parsed, err := parseAndExtract(a, LanguageGo, cache)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	parsed.Close()
+	evicted, _ := cache.Get(ParseCacheKey(LanguageGo, a))
+	if evicted.copyTree() == nil {
+		t.Fatal("Expected the cached tree to outlive the caller's copy")
+	}
+
+	parsed, err = parseAndExtract(b, LanguageGo, cache)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	parsed.Close()
+	if evicted.copyTree() != nil {
+		t.Error("Expected the evicted tree to be closed")
+	}
+
+	// An entry closed after Get returned it is parsed again
+	cache.Put(ParseCacheKey(LanguageGo, a), evicted)
+	chunks, err := Chunk("a.go", string(a), &ChunkOptions{ParseCache: cache})
+	if err != nil || len(chunks) == 0 || !strings.Contains(chunks[0].Text, "func A") {
+		t.Fatalf("Expected a closed entry to be parsed again, got %v, %v", chunks, err)
+	}
+}
+
+func TestParseCacheConcurrentEviction(t *testing.T) {
+	cache := NewLRUParseCache(1)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				code := fmt.Sprintf("package p\n\nfunc F%d() {}\n", (i+j)%3)
+				if chunks, err := Chunk("p.go", code, &ChunkOptions{ParseCache: cache}); err != nil || len(chunks) != 1 {
+					t.Errorf("Unexpected result: %v, %v", chunks, err)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestChunkWithParseCache(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+func main() {
+	fmt.Println("hi")
+}
+`
+	cache := &countingCache{ParseCache: NewLRUParseCache(0)}
+	opts := &ChunkOptions{ParseCache: cache}
+
+	first, err := Chunk("main.go", code, opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	second, err := Chunk("other/main.go", code, opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	if cache.hits.Load() != 1 {
+		t.Errorf("Expected 1 cache hit, got %d", cache.hits.Load())
+	}
+	if len(first) != len(second) || first[0].Text != second[0].Text {
+		t.Error("Expected identical chunks from cached parse")
+	}
+
+	uncached, err := Chunk("main.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, uncached) {
+		t.Error("Expected cached chunking to match uncached chunking")
+	}
+}
+
+func TestParseCacheConcurrentBatch(t *testing.T) {
+	code := "package main\n\nfunc A() {}\n\nfunc B() {}\n"
+	files := make([]FileInput, 20)
+	for i := range files {
+		files[i] = FileInput{Filepath: "main.go", Code: code}
+	}
+
+	cache := NewLRUParseCache(8)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results := ChunkBatch(files, &BatchOptions{
+				ChunkOptions: ChunkOptions{ParseCache: cache},
+				Concurrency:  4,
+			})
+			for _, r := range results {
+				if r.Error != nil || len(r.Chunks) == 0 {
+					t.Errorf("Unexpected result: %+v", r)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestParseAndExtractReleasesNodes(t *testing.T) {
+	code := []byte("package main\n\nfunc Run() {}\n\ntype Store struct{}\n")
+	cache := NewLRUParseCache(0)
+
+	parsed, err := parseAndExtract(code, LanguageGo, cache)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	if len(parsed.Entities) == 0 || len(parsed.Exports) == 0 {
+		t.Fatal("Expected entities and exports")
+	}
+	for _, entity := range append(parsed.Entities, parsed.Exports...) {
+		if entity.Node != nil {
+			t.Errorf("Expected node of %s to be released", entity.Name)
+		}
+	}
+
+	parsed.Close()
+	parsed.Close()
+
+	cached, ok := cache.Get(ParseCacheKey(LanguageGo, code))
+	if !ok {
+		t.Fatal("Expected parsed file to be cached")
+	}
+	if cached.Tree.RootNode().Type() != "source_file" {
+		t.Error("Expected closing the returned tree to leave the cached tree usable")
+	}
+}
@@ -0,0 +1,175 @@
+package codechunk
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// Checkpointer records files completed by batch runs, so that a later run can
+// skip files whose content has not changed. Implementations must be safe for
+// concurrent use.
+type Checkpointer interface {
+	// Checkpoint records that filepath was chunked with content of the given hash.
+	Checkpoint(filepath, hash string) error
+	// Completed reports whether filepath was checkpointed with the given hash.
+	Completed(filepath, hash string) bool
+}
+
+// ContentHash returns the hex-encoded SHA-256 hash of content, used for file
+// checkpoints and to find duplicate chunks.
+func ContentHash(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// checkpointEntry is one line of a FileCheckpointer file
+type checkpointEntry struct {
+	Filepath string `json:"filepath"`
+	Hash     string `json:"hash"`
+}
+
+// FileCheckpointer is a Checkpointer backed by an append-only file of JSON lines.
+// Entries from earlier runs are loaded when it is opened.
+type FileCheckpointer struct {
+	mu        sync.Mutex
+	file      *os.File
+	enc       *json.Encoder
+	completed map[string]string
+}
+
+// OpenFileCheckpointer opens or creates the checkpoint file at path and loads the
+// entries it contains. A truncated last line, as left by a crash, is discarded.
+func OpenFileCheckpointer(path string) (*FileCheckpointer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	completed := make(map[string]string)
+	validEnd := 0
+	for offset := 0; offset < len(data); {
+		end := bytes.IndexByte(data[offset:], '\n')
+		if end == -1 {
+			// Incomplete last line
+			break
+		}
+		line := data[offset : offset+end]
+		offset += end + 1
+
+		var entry checkpointEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			return nil, fmt.Errorf("reading checkpoint %s: %w", path, err)
+		}
+		completed[entry.Filepath] = entry.Hash
+		validEnd = offset
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.Truncate(int64(validEnd)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err := file.Seek(int64(validEnd), io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return &FileCheckpointer{
+		file:      file,
+		enc:       json.NewEncoder(file),
+		completed: completed,
+	}, nil
+}
+
+// Checkpoint appends an entry for filepath to the checkpoint file.
+func (c *FileCheckpointer) Checkpoint(filepath, hash string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.enc.Encode(checkpointEntry{Filepath: filepath, Hash: hash}); err != nil {
+		return err
+	}
+	c.completed[filepath] = hash
+	return nil
+}
+
+// Completed reports whether filepath was checkpointed with the given hash, in this
+// or an earlier run.
+func (c *FileCheckpointer) Completed(filepath, hash string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	completedHash, ok := c.completed[filepath]
+	return ok && completedHash == hash
+}
+
+// Len returns the number of checkpointed files.
+func (c *FileCheckpointer) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.completed)
+}
+
+// Close closes the checkpoint file.
+func (c *FileCheckpointer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.file.Close()
+}
+
+// batchCheckpoint applies BatchOptions.ResumeFrom and Checkpointer to batch files
+type batchCheckpoint struct {
+	resumeFrom   Checkpointer
+	checkpointer Checkpointer
+	logger       *slog.Logger
+}
+
+func newBatchCheckpoint(opts BatchOptions) *batchCheckpoint {
+	return &batchCheckpoint{
+		resumeFrom:   opts.ResumeFrom,
+		checkpointer: opts.Checkpointer,
+		logger:       loggerFor(opts.ChunkOptions),
+	}
+}
+
+// hash returns the content hash of file, or "" if checkpointing is disabled
+func (c *batchCheckpoint) hash(file FileInput) string {
+	if c.resumeFrom == nil && c.checkpointer == nil {
+		return ""
+	}
+	return ContentHash(file.Code)
+}
+
+// resumed returns a skipped result if file was completed by a previous run
+func (c *batchCheckpoint) resumed(file FileInput, hash string) (BatchResult, bool) {
+	if c.resumeFrom == nil || !c.resumeFrom.Completed(file.Filepath, hash) {
+		return BatchResult{}, false
+	}
+	c.logger.Debug("skipping unchanged file", "filepath", file.Filepath)
+	return BatchResult{
+		Filepath: file.Filepath,
+		Error:    ErrUnchanged,
+		Skipped:  true,
+	}, true
+}
+
+// record checkpoints a successfully chunked file. Checkpoint failures are logged
+// rather than failing the file, since its chunks are still valid.
+func (c *batchCheckpoint) record(result BatchResult, hash string) {
+	if c.checkpointer == nil || result.Error != nil {
+		return
+	}
+	if err := c.checkpointer.Checkpoint(result.Filepath, hash); err != nil {
+		c.logger.Warn("checkpoint failed", "filepath", result.Filepath, "error", err)
+	}
+}
@@ -0,0 +1,145 @@
+package codechunk
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileCheckpointerPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+
+	cp, err := OpenFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("OpenFileCheckpointer failed: %v", err)
+	}
+	if err := cp.Checkpoint("a.go", "hash-a"); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if err := cp.Checkpoint("b.go", "hash-b"); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	if err := cp.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	// Simulate a crash mid-write
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("OpenFile failed: %v", err)
+	}
+	f.WriteString(`{"filepath":"c.go","ha`)
+	f.Close()
+
+	cp, err = OpenFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer func() { cp.Close() }()
+
+	if cp.Len() != 2 {
+		t.Errorf("Expected 2 entries, got %d", cp.Len())
+	}
+	if !cp.Completed("a.go", "hash-a") {
+		t.Error("Expected a.go to be completed")
+	}
+	if cp.Completed("a.go", "other") {
+		t.Error("Expected changed content not to be completed")
+	}
+	if cp.Completed("c.go", "") {
+		t.Error("Expected truncated entry to be ignored")
+	}
+
+	// Entries appended after a truncated line stay readable
+	if err := cp.Checkpoint("d.go", "hash-d"); err != nil {
+		t.Fatalf("Checkpoint failed: %v", err)
+	}
+	cp.Close()
+	cp, err = OpenFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Reopen after append failed: %v", err)
+	}
+	if cp.Len() != 3 || !cp.Completed("d.go", "hash-d") {
+		t.Errorf("Expected d.go after reopen, got %d entries", cp.Len())
+	}
+}
+
+func TestChunkBatchResumeFrom(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package main\n\nfunc a() {}\n"},
+		{Filepath: "b.go", Code: "package main\n\nfunc b() {}\n"},
+		{Filepath: "c.unknown", Code: "???"},
+	}
+
+	cp, err := OpenFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("OpenFileCheckpointer failed: %v", err)
+	}
+	var spill bytes.Buffer
+	ChunkBatch(files, &BatchOptions{SpillWriter: &spill, Checkpointer: cp, ResumeFrom: cp})
+	cp.Close()
+
+	if cp.Len() != 2 {
+		t.Fatalf("Expected 2 checkpointed files, got %d", cp.Len())
+	}
+
+	// Next run: b.go changed
+	files[1].Code = "package main\n\nfunc b2() {}\n"
+	cp, err = OpenFileCheckpointer(path)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	defer cp.Close()
+
+	var progressSuccess int
+	results := ChunkBatch(files, &BatchOptions{
+		Checkpointer: cp,
+		ResumeFrom:   cp,
+		Concurrency:  1,
+		OnProgress: func(completed, total int, filepath string, success bool) {
+			if success {
+				progressSuccess++
+			}
+		},
+	})
+
+	if !results[0].Skipped || !errors.Is(results[0].Error, ErrUnchanged) {
+		t.Errorf("Expected unchanged a.go to be skipped, got %+v", results[0])
+	}
+	if results[1].Skipped || results[1].Error != nil || len(results[1].Chunks) == 0 {
+		t.Errorf("Expected changed b.go to be chunked, got %+v", results[1])
+	}
+	if results[2].Skipped || !errors.Is(results[2].Error, ErrUnsupportedLanguage) {
+		t.Errorf("Expected failed file to be retried, got %+v", results[2])
+	}
+	if progressSuccess != 2 {
+		t.Errorf("Expected 2 successful progress calls, got %d", progressSuccess)
+	}
+	if !cp.Completed("b.go", ContentHash(files[1].Code)) {
+		t.Error("Expected changed b.go to be checkpointed again")
+	}
+}
+
+func TestChunkBatchStreamCheckpoints(t *testing.T) {
+	cp, err := OpenFileCheckpointer(filepath.Join(t.TempDir(), "checkpoint.jsonl"))
+	if err != nil {
+		t.Fatalf("OpenFileCheckpointer failed: %v", err)
+	}
+	defer cp.Close()
+
+	files := []FileInput{{Filepath: "a.go", Code: "package main\n"}}
+	for range ChunkBatchStream(files, &BatchOptions{Checkpointer: cp}) {
+	}
+	if !cp.Completed("a.go", ContentHash(files[0].Code)) {
+		t.Error("Expected streamed file to be checkpointed")
+	}
+
+	for result := range ChunkBatchStream(files, &BatchOptions{ResumeFrom: cp}) {
+		if !result.Skipped {
+			t.Errorf("Expected resumed file to be skipped, got %+v", result)
+		}
+	}
+}
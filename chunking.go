@@ -1,6 +1,9 @@
 package codechunk
 
 import (
+	"bytes"
+	"context"
+	"log/slog"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -73,8 +76,51 @@ func getAncestorsForNodes(nodes []*sitter.Node) []*sitter.Node {
 	return ancestors
 }
 
-// greedyAssignWindows assigns nodes to windows using a greedy algorithm
-func greedyAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, maxSize int) []*ASTWindow {
+// assignWindows assigns the top-level nodes of a file to windows with the chunking
+// strategy of opts and merges them, returning the raw and merged windows
+func assignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, opts ChunkOptions, lang Language, logger *slog.Logger) (raw, merged []*ASTWindow) {
+	wopts := windowOptionsFor(opts, lang)
+	if opts.ChunkStrategy == ChunkStrategyEntity {
+		raw = entityAssignWindows(nodes, code, cumsum, opts.MaxChunkSize, wopts, logger)
+		return raw, mergeUndersizedWindows(raw, opts.MinChunkSize, logger)
+	}
+
+	raw = greedyAssignWindows(nodes, code, cumsum, opts.MaxChunkSize, wopts, logger)
+	merged = mergeAdjacentWindows(raw, opts.MaxChunkSize, logger)
+	merged = mergeUndersizedWindows(merged, opts.MinChunkSize, logger)
+	return raw, merged
+}
+
+// windowOptions configure how nodes are assigned to windows
+type windowOptions struct {
+	lang               Language
+	boundaryHeuristics bool            // See ChunkOptions.BoundaryHeuristics
+	splitClasses       bool            // See ChunkOptions.SplitClasses
+	ctx                context.Context // Stops window assignment once done, if set
+}
+
+// cancelled reports whether window assignment should stop, leaving the remaining
+// nodes unassigned; the caller then returns the context's error
+func (w windowOptions) cancelled() bool {
+	return w.ctx != nil && w.ctx.Err() != nil
+}
+
+// windowOptionsFor returns the window options for chunking code in lang with opts
+func windowOptionsFor(opts ChunkOptions, lang Language) windowOptions {
+	return windowOptions{
+		lang:               lang,
+		boundaryHeuristics: opts.BoundaryHeuristics,
+		splitClasses:       opts.SplitClasses,
+		ctx:                opts.ctx,
+	}
+}
+
+// greedyAssignWindows assigns nodes to windows using a greedy algorithm. Doc
+// comments are kept in the window of the node they document. With boundary
+// heuristics, window boundaries are moved to blank lines and before comment groups
+// where that keeps windows within maxSize, and with class splitting oversized
+// classes are split into a window per method.
+func greedyAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, maxSize int, wopts windowOptions, logger *slog.Logger) []*ASTWindow {
 	windows := make([]*ASTWindow, 0)
 	currentWindow := &ASTWindow{
 		Nodes:     make([]*sitter.Node, 0),
@@ -82,13 +128,29 @@ func greedyAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, ma
 		Size:      0,
 	}
 
-	for _, node := range nodes {
-		nodeSize := getNwsCountForNode(node, cumsum)
+	for _, unit := range windowUnits(nodes, code, cumsum, wopts.lang) {
+		if wopts.cancelled() {
+			break
+		}
+		node := unit[len(unit)-1]
+		leading := unit[:len(unit)-1]
+		unitSize := nodesSize(unit, cumsum)
+
+		if currentWindow.Size+unitSize <= maxSize {
+			currentWindow.Nodes = append(currentWindow.Nodes, unit...)
+			currentWindow.Size += unitSize
+		} else if unitSize > maxSize {
+			logger.Debug("splitting oversized node",
+				"type", node.Type(), "size", getNwsCountForNode(node, cumsum), "maxSize", maxSize,
+				"line", node.StartPoint().Row, "leaf", isLeafNode(node))
+
+			// A comment group above the node moves to its first window
+			if wopts.boundaryHeuristics && len(leading) == 0 {
+				leading = trailingCommentGroup(currentWindow.Nodes, node, code, cumsum)
+				currentWindow.Nodes = currentWindow.Nodes[:len(currentWindow.Nodes)-len(leading)]
+				currentWindow.Size -= nodesSize(leading, cumsum)
+			}
 
-		if currentWindow.Size+nodeSize <= maxSize {
-			currentWindow.Nodes = append(currentWindow.Nodes, node)
-			currentWindow.Size += nodeSize
-		} else if nodeSize > maxSize {
 			if len(currentWindow.Nodes) > 0 {
 				currentWindow.Ancestors = getAncestorsForNodes(currentWindow.Nodes)
 				windows = append(windows, currentWindow)
@@ -99,28 +161,45 @@ func greedyAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, ma
 				}
 			}
 
-			if !isLeafNode(node) {
+			var nodeWindows []*ASTWindow
+			if wopts.splitClasses {
+				nodeWindows = splitClassWindows(node, code, cumsum, maxSize, wopts, logger)
+			}
+			if nodeWindows != nil {
+				logger.Debug("split class by members", "type", node.Type(), "windows", len(nodeWindows))
+			} else if !isLeafNode(node) {
 				children := make([]*sitter.Node, 0, node.ChildCount())
 				for i := 0; i < int(node.ChildCount()); i++ {
 					if child := node.Child(i); child != nil {
 						children = append(children, child)
 					}
 				}
-				childWindows := greedyAssignWindows(children, code, cumsum, maxSize)
-				windows = append(windows, childWindows...)
+				nodeWindows = greedyAssignWindows(children, code, cumsum, maxSize, wopts, logger)
 			} else {
-				leafWindows := splitOversizedLeafByLines(node, code, maxSize)
-				windows = append(windows, leafWindows...)
+				nodeWindows = splitOversizedLeafByLines(node, code, maxSize, wopts.boundaryHeuristics)
+				logger.Debug("split oversized leaf by lines", "type", node.Type(), "windows", len(nodeWindows))
 			}
+
+			windows = append(windows, attachLeadingNodes(leading, nodeWindows, code, cumsum, maxSize, wopts, logger)...)
 		} else {
+			var carried []*sitter.Node
+			if wopts.boundaryHeuristics {
+				carried = carriedNodes(currentWindow.Nodes, unit[0], code, cumsum, maxSize-unitSize)
+			}
+			if len(carried) > 0 {
+				logger.Debug("moving window boundary", "nodes", len(carried), "line", carried[0].StartPoint().Row)
+				currentWindow.Nodes = currentWindow.Nodes[:len(currentWindow.Nodes)-len(carried)]
+				currentWindow.Size -= nodesSize(carried, cumsum)
+			}
+
 			if len(currentWindow.Nodes) > 0 {
 				currentWindow.Ancestors = getAncestorsForNodes(currentWindow.Nodes)
 				windows = append(windows, currentWindow)
 			}
 			currentWindow = &ASTWindow{
-				Nodes:     []*sitter.Node{node},
+				Nodes:     append(carried[:len(carried):len(carried)], unit...),
 				Ancestors: make([]*sitter.Node, 0),
-				Size:      nodeSize,
+				Size:      nodesSize(carried, cumsum) + unitSize,
 			}
 		}
 	}
@@ -133,85 +212,308 @@ func greedyAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, ma
 	return windows
 }
 
-// splitOversizedLeafByLines splits an oversized leaf node at line boundaries
-func splitOversizedLeafByLines(node *sitter.Node, code []byte, maxSize int) []*ASTWindow {
+// entityAssignWindows assigns each top-level entity, with its doc comment, to
+// windows of its own, splitting only entities larger than maxSize (classes by
+// method). The nodes between entities, such as imports and top-level statements,
+// are packed greedily. The windows of a split entity are merged like greedy
+// windows, but all windows are standalone so entities are never merged together.
+func entityAssignWindows(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, maxSize int, wopts windowOptions, logger *slog.Logger) []*ASTWindow {
 	windows := make([]*ASTWindow, 0)
+	assign := func(nodes []*sitter.Node, wopts windowOptions) {
+		// Whitespace-only windows such as Go's statement terminators are dropped
+		assigned := nonEmptyWindows(greedyAssignWindows(nodes, code, cumsum, maxSize, wopts, logger))
+		windows = append(windows, mergeAdjacentWindows(assigned, maxSize, logger)...)
+	}
+	var pending []*sitter.Node
+	flush := func() {
+		if nodesSize(pending, cumsum) > 0 {
+			assign(pending, wopts)
+		}
+		pending = nil
+	}
 
-	text := string(code[node.StartByte():node.EndByte()])
-	lines := strings.Split(text, "\n")
+	entityOpts := wopts
+	entityOpts.splitClasses = true
+	for _, unit := range windowUnits(nodes, code, cumsum, wopts.lang) {
+		if wopts.cancelled() {
+			break
+		}
+		if !isDefinitionNode(unit[len(unit)-1], wopts.lang, code) {
+			pending = append(pending, unit...)
+			continue
+		}
+		flush()
+		logger.Log(context.Background(), LevelTrace, "assigning entity window",
+			"type", unit[len(unit)-1].Type(), "line", unit[len(unit)-1].StartPoint().Row)
+		assign(unit, entityOpts)
+	}
+	flush()
 
-	var currentChunk strings.Builder
-	currentSize := 0
-	startByte := int(node.StartByte())
-	chunkStartOffset := 0
+	for _, window := range windows {
+		window.Standalone = true
+	}
+	return windows
+}
 
-	for i, line := range lines {
-		lineNws := countNws(line)
-		lineWithNewline := line
-		if i < len(lines)-1 {
-			lineWithNewline += "\n"
+// nonEmptyWindows returns the windows with non-whitespace content
+func nonEmptyWindows(windows []*ASTWindow) []*ASTWindow {
+	nonEmpty := windows[:0]
+	for _, window := range windows {
+		if window.Size > 0 {
+			nonEmpty = append(nonEmpty, window)
 		}
+	}
+	return nonEmpty
+}
 
-		if currentSize+lineNws <= maxSize {
-			currentChunk.WriteString(lineWithNewline)
-			currentSize += lineNws
-		} else {
-			if currentChunk.Len() > 0 {
-				startLine := countNewlines(code, 0, startByte+chunkStartOffset)
-				endLine := countNewlines(code, 0, startByte+chunkStartOffset+currentChunk.Len())
-
-				windows = append(windows, &ASTWindow{
-					Nodes:         []*sitter.Node{node},
-					Ancestors:     getAncestorsForNodes([]*sitter.Node{node}),
-					Size:          currentSize,
-					IsPartialNode: true,
-					LineRanges: []LineRange{
-						{Start: startLine, End: endLine},
-					},
-				})
-			}
+// isDefinitionNode reports whether a node defines an entity other than an import
+// or export, looking through export statements and Python decorators
+func isDefinitionNode(node *sitter.Node, lang Language, code []byte) bool {
+	switch node.Type() {
+	case "export_statement":
+		if declaration := node.ChildByFieldName("declaration"); declaration != nil {
+			node = declaration
+		}
+	case "decorated_definition":
+		if definition := node.ChildByFieldName("definition"); definition != nil {
+			node = definition
+		}
+	}
+	entityType, ok := nodeEntityType(node, lang, code)
+	return ok && entityType != EntityTypeImport && entityType != EntityTypeExport
+}
+
+// leadingNodeTypes are nodes that may stand between a doc comment and the node it
+// documents, such as Rust attributes
+var leadingNodeTypes = map[string]bool{
+	"attribute_item": true,
+}
+
+// windowUnits groups nodes into units that are assigned to windows together: a doc
+// comment starts a unit that runs through the node it documents, including other
+// comments and attributes between them. Other nodes are units of their own.
+func windowUnits(nodes []*sitter.Node, code []byte, cumsum nwsCumsum, lang Language) [][]*sitter.Node {
+	units := make([][]*sitter.Node, 0, len(nodes))
+	for i := 0; i < len(nodes); i++ {
+		end := i
+		if isCommentNode(nodes[i]) && isItemDocComment(nodes[i].Content(code), lang) {
+			end = documentedNode(nodes, i, code, cumsum)
+		}
+		units = append(units, nodes[i:end+1])
+		i = end
+	}
+	return units
+}
 
-			chunkStartOffset += currentChunk.Len()
-			currentChunk.Reset()
-			currentChunk.WriteString(lineWithNewline)
-			currentSize = lineNws
+// documentedNode returns the index of the node documented by the doc comment at
+// nodes[start], or start if the comment is not directly followed by a node
+func documentedNode(nodes []*sitter.Node, start int, code []byte, cumsum nwsCumsum) int {
+	for i := start + 1; i < len(nodes); i++ {
+		node := nodes[i]
+		if getNwsCountForNode(node, cumsum) == 0 {
+			continue
+		}
+		if blankLineBefore(node, code) {
+			return start
+		}
+		if !isCommentNode(node) && !leadingNodeTypes[node.Type()] {
+			return i
 		}
 	}
+	return start
+}
+
+// attachLeadingNodes joins the doc comment or comment group above an oversized
+// node to the first of the node's windows, even if that exceeds maxSize. A comment
+// group larger than maxSize is assigned to windows of its own.
+func attachLeadingNodes(leading []*sitter.Node, nodeWindows []*ASTWindow, code []byte, cumsum nwsCumsum, maxSize int, wopts windowOptions, logger *slog.Logger) []*ASTWindow {
+	if len(leading) == 0 {
+		return nodeWindows
+	}
+	size := nodesSize(leading, cumsum)
+	if size > maxSize || len(nodeWindows) == 0 {
+		return append(greedyAssignWindows(leading, code, cumsum, maxSize, wopts, logger), nodeWindows...)
+	}
 
-	if currentChunk.Len() > 0 {
-		startLine := countNewlines(code, 0, startByte+chunkStartOffset)
-		endLine := countNewlines(code, 0, startByte+chunkStartOffset+currentChunk.Len())
+	logger.Debug("attaching comments to the next window", "comments", len(leading), "line", leading[0].StartPoint().Row)
+	first := nodeWindows[0]
+	nodeWindows[0] = joinWindows(&ASTWindow{Nodes: leading, Ancestors: first.Ancestors, Size: size}, first)
+	return nodeWindows
+}
+
+// isCommentNode reports whether a node is a comment
+func isCommentNode(node *sitter.Node) bool {
+	return strings.Contains(node.Type(), "comment")
+}
+
+// nodesSize returns the total NWS count of nodes
+func nodesSize(nodes []*sitter.Node, cumsum nwsCumsum) int {
+	size := 0
+	for _, node := range nodes {
+		size += getNwsCountForNode(node, cumsum)
+	}
+	return size
+}
+
+// blankLineBefore reports whether a blank line precedes a node's first
+// non-whitespace byte
+func blankLineBefore(node *sitter.Node, code []byte) bool {
+	start, end := int(node.StartByte()), int(node.EndByte())
+	for start < end && isWhitespace(code[start]) {
+		start++
+	}
+	newlines := 0
+	for i := start - 1; i >= 0 && isWhitespace(code[i]); i-- {
+		if code[i] == '\n' {
+			newlines++
+		}
+	}
+	return newlines > 1
+}
 
+// trailingCommentGroup returns the comments at the end of nodes that directly
+// precede next without a blank line between them, leaving at least one node.
+// Whitespace-only nodes, such as statement terminators, are passed over.
+func trailingCommentGroup(nodes []*sitter.Node, next *sitter.Node, code []byte, cumsum nwsCumsum) []*sitter.Node {
+	first, following := len(nodes), next
+	for i := len(nodes) - 1; i > 0; i-- {
+		if getNwsCountForNode(nodes[i], cumsum) == 0 {
+			continue
+		}
+		if !isCommentNode(nodes[i]) || blankLineBefore(following, code) {
+			break
+		}
+		first, following = i, nodes[i]
+	}
+	return nodes[first:]
+}
+
+// carriedNodes returns the nodes at the end of a full window that should start the
+// next window along with next, at most budget NWS characters: a comment group
+// documenting next or, when no blank line precedes next, the nodes after the last
+// blank line in the window's second half
+func carriedNodes(nodes []*sitter.Node, next *sitter.Node, code []byte, cumsum nwsCumsum, budget int) []*sitter.Node {
+	if len(nodes) < 2 {
+		return nil
+	}
+	if group := trailingCommentGroup(nodes, next, code, cumsum); len(group) > 0 {
+		if nodesSize(group, cumsum) <= budget {
+			return group
+		}
+		return nil
+	}
+	if blankLineBefore(next, code) {
+		return nil
+	}
+
+	total := nodesSize(nodes, cumsum)
+	carried := 0
+	for i := len(nodes) - 1; i > 0; i-- {
+		size := getNwsCountForNode(nodes[i], cumsum)
+		carried += size
+		if carried > budget || total-carried < carried {
+			return nil
+		}
+		if size > 0 && blankLineBefore(nodes[i], code) {
+			return nodes[i:]
+		}
+	}
+	return nil
+}
+
+// leafLine is a line of an oversized leaf node with its line number and byte
+// offsets (end excludes the newline)
+type leafLine struct {
+	line       int
+	start, end int
+	nws        int
+}
+
+// splitOversizedLeafByLines splits an oversized leaf node at line boundaries. With
+// preferBlankLines, a window ends at the last blank line of its second half when
+// the lines after it fit in the next window.
+func splitOversizedLeafByLines(node *sitter.Node, code []byte, maxSize int, preferBlankLines bool) []*ASTWindow {
+	windows := make([]*ASTWindow, 0)
+	ancestors := getAncestorsForNodes([]*sitter.Node{node})
+
+	emit := func(lines []leafLine) {
+		size := 0
+		for _, line := range lines {
+			size += line.nws
+		}
+		start, end := lines[0].start, lines[len(lines)-1].end
 		windows = append(windows, &ASTWindow{
-			Nodes:         []*sitter.Node{node},
-			Ancestors:     getAncestorsForNodes([]*sitter.Node{node}),
-			Size:          currentSize,
+			Ancestors:     ancestors,
+			Size:          size,
 			IsPartialNode: true,
 			LineRanges: []LineRange{
-				{Start: startLine, End: endLine},
+				{Start: lines[0].line, End: lines[len(lines)-1].line},
 			},
+			ByteRanges: []ByteRange{{Start: start, End: end}},
 		})
 	}
 
+	var current []leafLine
+	currentSize := 0
+	row := int(node.StartPoint().Row)
+	for start, nodeEnd := int(node.StartByte()), int(node.EndByte()); start <= nodeEnd; row++ {
+		end := bytes.IndexByte(code[start:nodeEnd], '\n')
+		if end < 0 {
+			end = nodeEnd
+		} else {
+			end += start
+		}
+		line := leafLine{line: row, start: start, end: end, nws: countNws(string(code[start:end]))}
+		start = end + 1
+
+		if currentSize+line.nws > maxSize && len(current) > 0 {
+			cut := len(current)
+			if preferBlankLines {
+				cut = blankLineCut(current, currentSize, maxSize-line.nws)
+			}
+			emit(current[:cut])
+			current = current[cut:]
+			for len(current) > 0 && current[0].nws == 0 {
+				current = current[1:]
+			}
+			currentSize = 0
+			for _, l := range current {
+				currentSize += l.nws
+			}
+		}
+		current = append(current, line)
+		currentSize += line.nws
+	}
+
+	// Trailing blank lines belong to no window
+	for len(current) > 0 && current[len(current)-1].nws == 0 {
+		current = current[:len(current)-1]
+	}
+	if len(current) > 0 {
+		emit(current)
+	}
+
 	return windows
 }
 
-// countNewlines counts newlines in code from start to end offset
-func countNewlines(code []byte, start, end int) int {
-	if end > len(code) {
-		end = len(code)
-	}
-	count := 0
-	for i := start; i < end; i++ {
-		if code[i] == '\n' {
-			count++
+// blankLineCut returns the index of the last blank line in the second half of
+// lines after which at most budget NWS characters remain, or len(lines)
+func blankLineCut(lines []leafLine, size, budget int) int {
+	after := 0
+	for i := len(lines) - 1; i > 0; i-- {
+		if lines[i].nws == 0 && after <= budget && size-after >= after {
+			return i
+		}
+		after += lines[i].nws
+		if after > budget {
+			break
 		}
 	}
-	return count
+	return len(lines)
 }
 
 // mergeAdjacentWindows merges adjacent windows that fit within maxSize
-func mergeAdjacentWindows(windows []*ASTWindow, maxSize int) []*ASTWindow {
+func mergeAdjacentWindows(windows []*ASTWindow, maxSize int, logger *slog.Logger) []*ASTWindow {
 	if len(windows) == 0 {
 		return windows
 	}
@@ -222,15 +524,13 @@ func mergeAdjacentWindows(windows []*ASTWindow, maxSize int) []*ASTWindow {
 	for i := 1; i < len(windows); i++ {
 		next := windows[i]
 
-		if current.Size+next.Size <= maxSize {
-			current = &ASTWindow{
-				Nodes:         append(current.Nodes, next.Nodes...),
-				Ancestors:     current.Ancestors,
-				Size:          current.Size + next.Size,
-				IsPartialNode: current.IsPartialNode || next.IsPartialNode,
-				LineRanges:    append(current.LineRanges, next.LineRanges...),
-			}
+		if current.Size+next.Size <= maxSize && !(current.Standalone && next.Standalone) {
+			logger.Log(context.Background(), LevelTrace, "merging adjacent windows",
+				"size", current.Size, "nextSize", next.Size, "maxSize", maxSize)
+			current = joinWindows(current, next)
 		} else {
+			logger.Log(context.Background(), LevelTrace, "keeping window boundary",
+				"size", current.Size, "nextSize", next.Size, "maxSize", maxSize)
 			merged = append(merged, current)
 			current = next
 		}
@@ -241,6 +541,45 @@ func mergeAdjacentWindows(windows []*ASTWindow, maxSize int) []*ASTWindow {
 	return merged
 }
 
+// mergeUndersizedWindows merges windows smaller than minSize into the previous
+// window, or the next one when the first window is undersized. A merged window may
+// exceed maxSize by less than minSize.
+func mergeUndersizedWindows(windows []*ASTWindow, minSize int, logger *slog.Logger) []*ASTWindow {
+	if minSize <= 0 || len(windows) < 2 {
+		return windows
+	}
+
+	merged := make([]*ASTWindow, 0, len(windows))
+	for _, window := range windows {
+		if n := len(merged); n > 0 && (window.Size < minSize || (n == 1 && merged[0].Size < minSize)) {
+			logger.Debug("merging undersized window",
+				"size", merged[n-1].Size, "nextSize", window.Size, "minSize", minSize)
+			merged[n-1] = joinWindows(merged[n-1], window)
+			continue
+		}
+		merged = append(merged, window)
+	}
+	return merged
+}
+
+// joinWindows joins two adjacent windows into one
+func joinWindows(current, next *ASTWindow) *ASTWindow {
+	class := current.Class
+	if class == nil {
+		class = next.Class
+	}
+	return &ASTWindow{
+		Nodes:         append(current.Nodes[:len(current.Nodes):len(current.Nodes)], next.Nodes...),
+		Ancestors:     current.Ancestors,
+		Size:          current.Size + next.Size,
+		IsPartialNode: current.IsPartialNode || next.IsPartialNode,
+		LineRanges:    append(current.LineRanges[:len(current.LineRanges):len(current.LineRanges)], next.LineRanges...),
+		ByteRanges:    append(current.ByteRanges[:len(current.ByteRanges):len(current.ByteRanges)], next.ByteRanges...),
+		Class:         class,
+		Standalone:    current.Standalone || next.Standalone,
+	}
+}
+
 // rebuiltText represents text rebuilt from an AST window
 type rebuiltText struct {
 	text      string
@@ -249,8 +588,8 @@ type rebuiltText struct {
 }
 
 // rebuildText rebuilds text from an AST window
-func rebuildText(window *ASTWindow, code []byte) *rebuiltText {
-	if len(window.Nodes) == 0 {
+func rebuildText(window *ASTWindow, code []byte, positions *positionIndex) *rebuiltText {
+	if len(window.Nodes) == 0 && len(window.ByteRanges) == 0 {
 		return &rebuiltText{
 			text:      "",
 			byteRange: ByteRange{Start: 0, End: 0},
@@ -258,15 +597,20 @@ func rebuildText(window *ASTWindow, code []byte) *rebuiltText {
 		}
 	}
 
-	startByte := int(window.Nodes[0].StartByte())
-	endByte := int(window.Nodes[0].EndByte())
+	// The window spans its whole nodes and the pieces of partial nodes
+	ranges := make([]ByteRange, 0, len(window.Nodes)+len(window.ByteRanges))
+	for _, node := range window.Nodes {
+		ranges = append(ranges, ByteRange{Start: int(node.StartByte()), End: int(node.EndByte())})
+	}
+	ranges = append(ranges, window.ByteRanges...)
 
-	for _, node := range window.Nodes[1:] {
-		if int(node.StartByte()) < startByte {
-			startByte = int(node.StartByte())
+	startByte, endByte := ranges[0].Start, ranges[0].End
+	for _, r := range ranges[1:] {
+		if r.Start < startByte {
+			startByte = r.Start
 		}
-		if int(node.EndByte()) > endByte {
-			endByte = int(node.EndByte())
+		if r.End > endByte {
+			endByte = r.End
 		}
 	}
 
@@ -286,10 +630,17 @@ func rebuildText(window *ASTWindow, code []byte) *rebuiltText {
 		endByte--
 	}
 
-	startLine := countLinesUpTo(code, startByte)
-	endLine := countLinesUpTo(code, endByte)
+	// Trim leading newlines, which the Lua grammar includes in keyword tokens
+	for len(text) > 0 && text[0] == '\n' {
+		text = text[1:]
+		startByte++
+	}
+
+	startLine := positions.line(startByte)
+	endLine := positions.line(endByte)
 
-	if len(window.LineRanges) > 0 {
+	// Windows without byte ranges rely on their line ranges
+	if len(window.LineRanges) > 0 && len(window.ByteRanges) == 0 {
 		startLine = window.LineRanges[0].Start
 		if len(window.LineRanges) > 0 {
 			endLine = window.LineRanges[len(window.LineRanges)-1].End
@@ -309,20 +660,6 @@ func rebuildText(window *ASTWindow, code []byte) *rebuiltText {
 	}
 }
 
-// countLinesUpTo counts newlines from 0 to offset
-func countLinesUpTo(code []byte, offset int) int {
-	if offset > len(code) {
-		offset = len(code)
-	}
-	count := 0
-	for i := 0; i < offset; i++ {
-		if code[i] == '\n' {
-			count++
-		}
-	}
-	return count
-}
-
 // getNodeChildren gets children of a node
 func getNodeChildren(node interface{}) []*sitter.Node {
 	n, ok := node.(*sitter.Node)
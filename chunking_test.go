@@ -98,52 +98,9 @@ func TestGetNwsCountFromCumsum(t *testing.T) {
 	}
 }
 
-func TestCountNewlines(t *testing.T) {
-	tests := []struct {
-		code     string
-		start    int
-		end      int
-		expected int
-	}{
-		{"hello\nworld", 0, 11, 1},
-		{"a\nb\nc", 0, 5, 2},
-		{"no newlines", 0, 11, 0},
-		{"\n\n\n", 0, 3, 3},
-		{"test", 0, 100, 0}, // end beyond length
-	}
-
-	for _, tt := range tests {
-		result := countNewlines([]byte(tt.code), tt.start, tt.end)
-		if result != tt.expected {
-			t.Errorf("countNewlines(%q, %d, %d) = %d, want %d", tt.code, tt.start, tt.end, result, tt.expected)
-		}
-	}
-}
-
-func TestCountLinesUpTo(t *testing.T) {
-	tests := []struct {
-		code     string
-		offset   int
-		expected int
-	}{
-		{"hello\nworld", 5, 0},
-		{"hello\nworld", 6, 1},
-		{"a\nb\nc", 3, 1}, // offset 3 is after first \n at position 1
-		{"test", 4, 0},
-		{"test", 100, 0}, // offset beyond length
-	}
-
-	for _, tt := range tests {
-		result := countLinesUpTo([]byte(tt.code), tt.offset)
-		if result != tt.expected {
-			t.Errorf("countLinesUpTo(%q, %d) = %d, want %d", tt.code, tt.offset, result, tt.expected)
-		}
-	}
-}
-
 func TestMergeAdjacentWindows(t *testing.T) {
 	// Test empty input
-	result := mergeAdjacentWindows([]*ASTWindow{}, 100)
+	result := mergeAdjacentWindows([]*ASTWindow{}, 100, discardLogger)
 	if len(result) != 0 {
 		t.Error("mergeAdjacentWindows([]) should return empty slice")
 	}
@@ -152,7 +109,7 @@ func TestMergeAdjacentWindows(t *testing.T) {
 	singleWindow := []*ASTWindow{
 		{Size: 50},
 	}
-	result = mergeAdjacentWindows(singleWindow, 100)
+	result = mergeAdjacentWindows(singleWindow, 100, discardLogger)
 	if len(result) != 1 {
 		t.Errorf("mergeAdjacentWindows single window should return 1, got %d", len(result))
 	}
@@ -163,7 +120,7 @@ func TestMergeAdjacentWindows(t *testing.T) {
 		{Size: 40, Nodes: nil, Ancestors: nil},
 		{Size: 20, Nodes: nil, Ancestors: nil},
 	}
-	result = mergeAdjacentWindows(windows, 100)
+	result = mergeAdjacentWindows(windows, 100, discardLogger)
 	if len(result) != 1 {
 		t.Errorf("mergeAdjacentWindows should merge 3 small windows into 1, got %d", len(result))
 	}
@@ -173,18 +130,68 @@ func TestMergeAdjacentWindows(t *testing.T) {
 		{Size: 60, Nodes: nil, Ancestors: nil},
 		{Size: 60, Nodes: nil, Ancestors: nil},
 	}
-	result = mergeAdjacentWindows(largeWindows, 100)
+	result = mergeAdjacentWindows(largeWindows, 100, discardLogger)
 	if len(result) != 2 {
 		t.Errorf("mergeAdjacentWindows should not merge large windows, got %d", len(result))
 	}
 }
 
+func TestMergeUndersizedWindows(t *testing.T) {
+	sizes := func(windows []*ASTWindow) []int {
+		out := make([]int, len(windows))
+		for i, w := range windows {
+			out[i] = w.Size
+		}
+		return out
+	}
+
+	windows := []*ASTWindow{{Size: 5}, {Size: 90}, {Size: 80}, {Size: 3}}
+	if got := sizes(mergeUndersizedWindows(windows, 0, discardLogger)); len(got) != 4 {
+		t.Errorf("Expected no merging when disabled, got %v", got)
+	}
+	got := sizes(mergeUndersizedWindows(windows, 10, discardLogger))
+	if len(got) != 2 || got[0] != 95 || got[1] != 83 {
+		t.Errorf("Expected the leading window merged forward and the trailing one back, got %v", got)
+	}
+
+	single := []*ASTWindow{{Size: 2}}
+	if got := mergeUndersizedWindows(single, 10, discardLogger); len(got) != 1 {
+		t.Errorf("Expected a lone window to be kept, got %d windows", len(got))
+	}
+}
+
+func TestSplitOversizedLeafByLinesBlankLines(t *testing.T) {
+	code := "x = \"\"\"\naaaa\nbbbb\n\ncccc\ndddd\n\"\"\"\n"
+	parseResult, err := parseString(code, LanguagePython)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	node := parseResult.Tree.RootNode().Child(0).Child(0).ChildByFieldName("right")
+
+	texts := func(windows []*ASTWindow) []string {
+		out := make([]string, len(windows))
+		for i, window := range windows {
+			out[i] = rebuildText(window, []byte(code), newPositionIndex([]byte(code))).text
+		}
+		return out
+	}
+
+	got := texts(splitOversizedLeafByLines(node, []byte(code), 16, false))
+	if len(got) != 2 || got[0] != "\"\"\"\naaaa\nbbbb\n\ncccc" {
+		t.Errorf("Expected a split at the size limit, got %q", got)
+	}
+	got = texts(splitOversizedLeafByLines(node, []byte(code), 16, true))
+	if len(got) != 2 || got[0] != "\"\"\"\naaaa\nbbbb" || got[1] != "cccc\ndddd\n\"\"\"" {
+		t.Errorf("Expected a split at the blank line, got %q", got)
+	}
+}
+
 func TestRebuildText(t *testing.T) {
 	// Test empty window
 	emptyWindow := &ASTWindow{
 		Nodes: []*sitter.Node{},
 	}
-	result := rebuildText(emptyWindow, []byte("code"))
+	result := rebuildText(emptyWindow, []byte("code"), newPositionIndex([]byte("code")))
 	if result.text != "" {
 		t.Error("rebuildText empty window should return empty text")
 	}
@@ -271,3 +278,26 @@ func TestGetNodeChildren(t *testing.T) {
 	}
 }
 
+
+func TestSplitOversizedLeafByLinesInclusiveEnd(t *testing.T) {
+	code := "x = \"\"\"\naaaa\nbbbb\ncccc\n\"\"\"\n"
+	parseResult, err := parseString(code, LanguagePython)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	// module > expression_statement > assignment > string
+	node := parseResult.Tree.RootNode().Child(0).Child(0).ChildByFieldName("right")
+	windows := splitOversizedLeafByLines(node, []byte(code), 8, false)
+	if len(windows) < 2 {
+		t.Fatalf("Expected the string to be split, got %d windows", len(windows))
+	}
+
+	for i, window := range windows[:len(windows)-1] {
+		lr := window.LineRanges[0]
+		next := windows[i+1].LineRanges[0]
+		if lr.End >= next.Start {
+			t.Errorf("Window %d ends on line %d, expected it to end before the next window's start line %d", i, lr.End, next.Start)
+		}
+	}
+}
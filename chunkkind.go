@@ -0,0 +1,80 @@
+package codechunk
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// headerNodeTypes are the node types that, like imports, only set up a file
+var headerNodeTypes = map[string]bool{
+	"package_clause":           true, // Go
+	"package_declaration":      true, // Java
+	"package":                  true, // Proto
+	"syntax":                   true, // Proto
+	"future_import_statement":  true, // Python
+	"extern_crate_declaration": true, // Rust
+}
+
+// classifyWindows returns the kind of each window, leaving out the windows of
+// imports or comments alone if skipTrivial is set
+func classifyWindows(windows []*ASTWindow, skipTrivial bool) ([]*ASTWindow, []ChunkKind) {
+	kept := windows[:0:0]
+	kinds := make([]ChunkKind, 0, len(windows))
+	for _, window := range windows {
+		kind := windowKind(window)
+		if skipTrivial && (kind == ChunkKindImports || kind == ChunkKindComments) {
+			continue
+		}
+		kept = append(kept, window)
+		kinds = append(kinds, kind)
+	}
+	return kept, kinds
+}
+
+// windowKind classifies a window by its named nodes, so punctuation and statement
+// terminators don't count. Windows holding part of a node that was split, or
+// nothing but punctuation, are code.
+func windowKind(window *ASTWindow) ChunkKind {
+	if window.IsPartialNode {
+		return ChunkKindCode
+	}
+
+	var imports, comments, code bool
+	for _, node := range window.Nodes {
+		if !node.IsNamed() {
+			continue
+		}
+		switch nodeKind(node) {
+		case ChunkKindImports:
+			imports = true
+		case ChunkKindComments:
+			comments = true
+		default:
+			code = true
+		}
+	}
+	switch {
+	case imports && code:
+		return ChunkKindMixed
+	case imports:
+		return ChunkKindImports
+	case comments && !code:
+		return ChunkKindComments
+	default:
+		return ChunkKindCode
+	}
+}
+
+// nodeKind returns ChunkKindImports for imports and package clauses,
+// ChunkKindComments for comments and ChunkKindCode for anything else
+func nodeKind(node *sitter.Node) ChunkKind {
+	nodeType := node.Type()
+	if strings.Contains(nodeType, "comment") {
+		return ChunkKindComments
+	}
+	if entityType, ok := getEntityType(nodeType); ok && entityType == EntityTypeImport || headerNodeTypes[nodeType] {
+		return ChunkKindImports
+	}
+	return ChunkKindCode
+}
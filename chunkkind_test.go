@@ -0,0 +1,130 @@
+package codechunk
+
+import (
+	"testing"
+)
+
+const chunkKindSource = `// Package p does things.
+package p
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Run runs a long function body here.
+func Run() error {
+	fmt.Println(os.Args, strings.ToUpper("x"), bytes.MinRead)
+	return errors.New("done and done and done")
+}
+
+// trailing notes
+// more notes
+`
+
+func TestChunkKind(t *testing.T) {
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 60
+	chunks, err := Chunk("p.go", chunkKindSource, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first, last := chunks[0], chunks[len(chunks)-1]
+	if first.Kind != ChunkKindImports || last.Kind != ChunkKindComments {
+		t.Errorf("Expected an imports chunk first and a comments chunk last, got %q and %q", first.Kind, last.Kind)
+	}
+	for _, chunk := range chunks {
+		if chunk.Kind == "" {
+			t.Errorf("Expected chunk %d to have a kind", chunk.Index)
+		}
+	}
+
+	// Imports packed with code make a mixed chunk
+	chunks, err = Chunk("p.go", chunkKindSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 1 || chunks[0].Kind != ChunkKindMixed {
+		t.Errorf("Expected a single mixed chunk, got %d chunks of kind %q", len(chunks), chunks[0].Kind)
+	}
+}
+
+func TestChunkSkipTrivialChunks(t *testing.T) {
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 60
+	opts.SkipTrivialChunks = true
+	chunks, err := Chunk("p.go", chunkKindSource, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("Expected the code chunks to be kept")
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Kind != ChunkKindCode {
+			t.Errorf("Expected only code chunks, got %q: %q", chunk.Kind, chunk.Text)
+		}
+		if chunk.Index != i || chunk.TotalChunks != len(chunks) {
+			t.Errorf("Expected chunk %d of %d, got %d of %d", i, len(chunks), chunk.Index, chunk.TotalChunks)
+		}
+	}
+	if chunks[0].PrevChunkID != "" || len(chunks[0].Context.Imports) == 0 {
+		t.Errorf("Expected the first code chunk to start the file and list its imports: %+v", chunks[0].Context.Imports)
+	}
+
+	// Streams leave out the same chunks
+	stream, err := ChunkStream("p.go", chunkKindSource, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	streamed := 0
+	for chunk := range stream {
+		if chunk.Kind != ChunkKindCode || chunk.Index != streamed {
+			t.Errorf("Unexpected streamed chunk %d of kind %q", chunk.Index, chunk.Kind)
+		}
+		streamed++
+	}
+	if streamed != len(chunks) {
+		t.Errorf("Expected %d streamed chunks, got %d", len(chunks), streamed)
+	}
+}
+
+func TestChunkKindEveryStrategy(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		opts     ChunkOptions
+		want     ChunkKind // Kind expected among the chunks
+	}{
+		{"p.go", chunkKindSource, NewChunkOptions(WithHierarchical(true)), ChunkKindOverview},
+		{"p.go", chunkKindSource, NewChunkOptions(WithChunkStrategy(ChunkStrategySlidingWindow), WithMaxChunkSize(60)), ChunkKindWindow},
+		{"p.go", chunkKindSource, NewChunkOptions(WithChunkStrategy(ChunkStrategyEntity)), ChunkKindCode},
+		{"README.md", "# Title\n\nSome prose.\n\n```go\nfunc F() {}\n```\n", DefaultChunkOptions(), ChunkKindText},
+		{"page.html", "<main>\n  <p>Hello</p>\n</main>\n", DefaultChunkOptions(), ChunkKindText},
+		{"analysis.ipynb", sampleNotebook, DefaultChunkOptions(), ChunkKindText},
+		{"src/Counter.vue", sampleVueComponent, DefaultChunkOptions(), ChunkKindMixed},
+	}
+
+	for _, tt := range tests {
+		opts := tt.opts
+		chunks, err := Chunk(tt.filepath, tt.code, &opts)
+		if err != nil {
+			t.Fatalf("%s: Chunk failed: %v", tt.filepath, err)
+		}
+		found := false
+		for _, chunk := range chunks {
+			if chunk.Kind == "" {
+				t.Errorf("%s: expected chunk %d to have a kind: %q", tt.filepath, chunk.Index, chunk.Text)
+			}
+			found = found || chunk.Kind == tt.want
+		}
+		if !found {
+			t.Errorf("%s: expected a chunk of kind %q", tt.filepath, tt.want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package codechunk
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Keys of the flat metadata returned by CodeChunk.Metadata
+const (
+	MetadataID             = "id"
+	MetadataFilepath       = "filepath"
+	MetadataLanguage       = "language"
+	MetadataIndex          = "index"
+	MetadataTotalChunks    = "totalChunks"
+	MetadataLevel          = "level"
+	MetadataParentChunkID  = "parentChunkId"
+	MetadataStartLine      = "startLine"
+	MetadataEndLine        = "endLine"
+	MetadataScope          = "scope"
+	MetadataEntities       = "entities"
+	MetadataQualifiedNames = "qualifiedNames"
+	MetadataImports        = "imports"
+	MetadataContentHash    = "contentHash"
+	MetadataKind           = "kind"
+	MetadataClassification = "classification"
+	MetadataTestedSymbols  = "testedSymbols"
+	MetadataTags           = "tags"
+	MetadataBuild          = "build"
+	MetadataGOOS           = "goos"
+	MetadataGOARCH         = "goarch"
+	MetadataRelPath        = "relPath"
+	MetadataCommitSHA      = "commitSha"
+	MetadataBranch         = "branch"
+)
+
+// Metadata flattens the identity, position and context of the chunk into string
+// values, the form vector stores accept as document metadata. Entities, qualified
+// names and import sources are joined with commas and the scope with " > ",
+// innermost last; empty values are left out.
+func (c CodeChunk) Metadata() map[string]string {
+	metadata := map[string]string{
+		MetadataIndex:       strconv.Itoa(c.Index),
+		MetadataTotalChunks: strconv.Itoa(c.TotalChunks),
+		MetadataLevel:       strconv.Itoa(c.Level),
+		MetadataStartLine:   strconv.Itoa(c.LineRange.Start),
+		MetadataEndLine:     strconv.Itoa(c.LineRange.End),
+	}
+	set := func(key, value string) {
+		if value != "" {
+			metadata[key] = value
+		}
+	}
+	set(MetadataID, c.ID)
+	set(MetadataFilepath, c.Context.Filepath)
+	set(MetadataLanguage, string(c.Context.Language))
+	set(MetadataParentChunkID, c.ParentChunkID)
+	set(MetadataScope, scopePath(c.Context.Scope))
+	set(MetadataQualifiedNames, strings.Join(c.QualifiedNames, ","))
+	set(MetadataContentHash, c.ContentHash)
+	set(MetadataKind, string(c.Kind))
+	set(MetadataClassification, string(c.Context.Classification))
+	set(MetadataTestedSymbols, strings.Join(c.Context.TestedSymbols, ","))
+	if build := c.Context.Build; build != nil {
+		set(MetadataBuild, build.Expr)
+		set(MetadataGOOS, build.GOOS)
+		set(MetadataGOARCH, build.GOARCH)
+	}
+	if provenance := c.Context.Provenance; provenance != nil {
+		set(MetadataRelPath, provenance.RelPath)
+		set(MetadataCommitSHA, provenance.CommitSHA)
+		set(MetadataBranch, provenance.Branch)
+	}
+
+	entities := make([]string, 0, len(c.Context.Entities))
+	for _, entity := range c.Context.Entities {
+		if entity.Type != EntityTypeImport {
+			entities = append(entities, entity.Name)
+		}
+	}
+	set(MetadataEntities, strings.Join(entities, ","))
+
+	imports := make([]string, 0, len(c.Context.Imports))
+	seen := make(map[string]bool)
+	for _, imp := range c.Context.Imports {
+		if imp.Source != "" && !seen[imp.Source] {
+			seen[imp.Source] = true
+			imports = append(imports, imp.Source)
+		}
+	}
+	set(MetadataImports, strings.Join(imports, ","))
+
+	tags := make([]string, 0, len(c.Context.Tags))
+	seen = make(map[string]bool)
+	for _, tag := range c.Context.Tags {
+		if !seen[tag.Tag] {
+			seen[tag.Tag] = true
+			tags = append(tags, tag.Tag)
+		}
+	}
+	set(MetadataTags, strings.Join(tags, ","))
+
+	return metadata
+}
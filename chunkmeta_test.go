@@ -0,0 +1,49 @@
+package codechunk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkMetadata(t *testing.T) {
+	code := `package auth
+
+import (
+	"crypto/hmac"
+	"strings"
+)
+
+// Service signs tokens.
+type Service struct{}
+
+// Login signs a token for user.
+func (s *Service) Login(user string) string {
+	return strings.ToLower(user) + string(hmac.New(nil, nil).Sum(nil))
+}
+`
+	chunks, err := Chunk("auth/service.go", code, &ChunkOptions{MaxChunkSize: 80})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	last := chunks[len(chunks)-1]
+
+	expected := map[string]string{
+		MetadataID:             last.ID,
+		MetadataFilepath:       "auth/service.go",
+		MetadataLanguage:       "go",
+		MetadataIndex:          "3",
+		MetadataTotalChunks:    "4",
+		MetadataLevel:          "0",
+		MetadataStartLine:      "11",
+		MetadataEndLine:        "13",
+		MetadataScope:          "Service > Login",
+		MetadataEntities:       "Login",
+		MetadataQualifiedNames: "auth.Service.Login",
+		MetadataImports:        "crypto/hmac,strings",
+		MetadataKind:           "code",
+		MetadataClassification: "source",
+	}
+	if got := last.Metadata(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Metadata() =\n%v\nexpected\n%v", got, expected)
+	}
+}
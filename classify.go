@@ -0,0 +1,113 @@
+package codechunk
+
+import (
+	"path"
+	"strings"
+)
+
+// Classification tells production code apart from tests, generated code and
+// examples, so retrieval can rank them differently
+type Classification string
+
+const (
+	ClassificationSource    Classification = "source"    // Production code
+	ClassificationTest      Classification = "test"      // Tests and test fixtures
+	ClassificationGenerated Classification = "generated" // Output of a code generator
+	ClassificationExample   Classification = "example"   // Examples and sample code
+)
+
+// testDirs, exampleDirs: path segments holding tests and examples
+var (
+	testDirs    = []string{"test", "tests", "__tests__", "spec", "testdata", "__mocks__"}
+	exampleDirs = []string{"example", "examples", "_examples", "sample", "samples", "demo", "demos"}
+)
+
+// testNameSuffixes, testNamePrefixes and testNameInfixes are lowercased file name
+// patterns of test frameworks; javaTestSuffixes are matched case-sensitively, as
+// lowercased they would match names like Contest.java
+var (
+	testNameSuffixes = []string{"_test.go", "_test.py", "_test.rs", "_test.exs", "_test.lua", "_spec.lua"}
+	testNamePrefixes = []string{"test_"}
+	testNameInfixes  = []string{".test.", ".spec.", ".e2e."}
+	javaTestSuffixes = []string{"Test.java", "Tests.java", "IT.java"}
+)
+
+// classifyFile classifies a file by its path and, for generated-code markers, the
+// start of its code. Generated code wins over tests and examples, and a test file
+// in an examples directory is a test.
+func classifyFile(filepath string, code []byte) Classification {
+	if len(code) > generatedHeaderLen {
+		code = code[:generatedHeaderLen]
+	}
+	if isGenerated(filepath, string(code)) {
+		return ClassificationGenerated
+	}
+
+	slashed := strings.ReplaceAll(filepath, "\\", "/")
+	name := path.Base(slashed)
+	dirs := strings.Split(strings.ToLower(path.Dir(slashed)), "/")
+	switch {
+	case isExampleName(strings.ToLower(name)):
+		return ClassificationExample
+	case isTestName(name), hasSegment(dirs, testDirs):
+		return ClassificationTest
+	case hasSegment(dirs, exampleDirs):
+		return ClassificationExample
+	default:
+		return ClassificationSource
+	}
+}
+
+// isTestName reports whether a file name follows a test naming convention, such
+// as user_test.go, test_user.py, user.spec.ts or UserTest.java
+func isTestName(name string) bool {
+	for _, suffix := range javaTestSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	name = strings.ToLower(name)
+	for _, suffix := range testNameSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	for _, prefix := range testNamePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	for _, infix := range testNameInfixes {
+		if strings.Contains(name, infix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isExampleName reports whether a lowercased file name holds Go examples, which
+// are example_test.go or example_*_test.go by convention
+func isExampleName(name string) bool {
+	return name == "example_test.go" || strings.HasPrefix(name, "example_") && strings.HasSuffix(name, "_test.go")
+}
+
+// hasSegment reports whether any of dirs is one of names
+func hasSegment(dirs, names []string) bool {
+	for _, dir := range dirs {
+		for _, name := range names {
+			if dir == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// classifyChunks sets the classification of the file on the context of its chunks
+func classifyChunks(filepath string, code []byte, chunks []CodeChunk) {
+	classification := classifyFile(filepath, code)
+	for i := range chunks {
+		chunks[i].Context.Classification = classification
+	}
+}
@@ -0,0 +1,58 @@
+package codechunk
+
+import (
+	"testing"
+)
+
+func TestClassifyFile(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		expected Classification
+	}{
+		{"auth/service.go", "package auth\n", ClassificationSource},
+		{"auth/service_test.go", "package auth\n", ClassificationTest},
+		{"tests/test_service.py", "", ClassificationTest},
+		{"pkg/test_service.py", "", ClassificationTest},
+		{"src/__tests__/Button.tsx", "", ClassificationTest},
+		{"src/button.spec.ts", "", ClassificationTest},
+		{"src/main/java/UserServiceTest.java", "", ClassificationTest},
+		{"src/main/java/Contest.java", "", ClassificationSource},
+		{`internal\testdata\input.go`, "", ClassificationTest},
+		{"examples/basic/main.go", "package main\n", ClassificationExample},
+		{"auth/example_test.go", "package auth_test\n", ClassificationExample},
+		{"examples/basic/main_test.go", "package main\n", ClassificationTest},
+		{"api/service.pb.go", "package api\n", ClassificationGenerated},
+		{"tests/mocks.go", "// Code generated by mockgen. DO NOT EDIT.\npackage tests\n", ClassificationGenerated},
+		{"src/latest.ts", "", ClassificationSource},
+	}
+
+	for _, tt := range tests {
+		if got := classifyFile(tt.filepath, []byte(tt.code)); got != tt.expected {
+			t.Errorf("classifyFile(%q) = %q, expected %q", tt.filepath, got, tt.expected)
+		}
+	}
+}
+
+func TestChunkClassification(t *testing.T) {
+	code := "package auth\n\nfunc TestLogin(t *testing.T) {}\n"
+	chunks, err := Chunk("auth/login_test.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		if chunk.Context.Classification != ClassificationTest {
+			t.Errorf("Expected a test chunk, got %q", chunk.Context.Classification)
+		}
+	}
+
+	stream, err := ChunkStream("auth/login.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range stream {
+		if chunk.Context.Classification != ClassificationSource {
+			t.Errorf("Expected a streamed source chunk, got %q", chunk.Context.Classification)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package codechunk
+
+import (
+	"log/slog"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// fieldNodeTypes are class members that declare fields or constants
+var fieldNodeTypes = map[string]bool{
+	"field_declaration":       true, // Java
+	"constant_declaration":    true, // Java interfaces
+	"public_field_definition": true, // TypeScript
+	"field_definition":        true, // JavaScript
+	"const_item":              true, // Rust impl and trait blocks
+}
+
+// splitClassWindows splits an oversized class into windows: the class header with
+// the members before the first method, then a window per method with its doc
+// comment, splitting only methods that are oversized themselves. Members between
+// methods are packed greedily and closing tokens join the last window. Every
+// window carries the class signature and field declarations as its Class.
+// Returns nil if node is not a class with a body.
+func splitClassWindows(node *sitter.Node, code []byte, cumsum nwsCumsum, maxSize int, wopts windowOptions, logger *slog.Logger) []*ASTWindow {
+	entityType, ok := nodeEntityType(node, wopts.lang, code)
+	if !ok || (entityType != EntityTypeClass && entityType != EntityTypeInterface) {
+		return nil
+	}
+
+	// The members are the children before the body, then the body's children
+	var members []*sitter.Node
+	var body *sitter.Node
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if node.FieldNameForChild(i) == "body" {
+			body = node.Child(i)
+			break
+		}
+		members = append(members, node.Child(i))
+	}
+	if body == nil {
+		return nil
+	}
+
+	class := &ClassContext{
+		Name:      extractNameFromCode(node, code, wopts.lang),
+		Signature: extractSignature(node, entityType, wopts.lang, code),
+	}
+	for i := 0; i < int(body.ChildCount()); i++ {
+		member := body.Child(i)
+		members = append(members, member)
+		if isFieldNode(member) {
+			class.Fields = append(class.Fields, strings.Join(strings.Fields(member.Content(code)), " "))
+		} else if property := propertySignature(member, wopts.lang, code); property != "" {
+			class.Fields = append(class.Fields, property)
+		}
+	}
+
+	windows := make([]*ASTWindow, 0)
+	var pending []*sitter.Node
+	flush := func() {
+		if len(pending) > 0 {
+			windows = append(windows, greedyAssignWindows(pending, code, cumsum, maxSize, wopts, logger)...)
+			pending = nil
+		}
+	}
+	for _, unit := range windowUnits(members, code, cumsum, wopts.lang) {
+		if !isMethodNode(unit[len(unit)-1], wopts.lang, code) {
+			pending = append(pending, unit...)
+			continue
+		}
+		flush()
+		windows = append(windows, greedyAssignWindows(unit, code, cumsum, maxSize, wopts, logger)...)
+	}
+	if last := len(windows) - 1; last >= 0 && len(pending) > 0 && windows[last].Size+nodesSize(pending, cumsum) <= maxSize {
+		windows[last] = joinWindows(windows[last], &ASTWindow{Nodes: pending, Size: nodesSize(pending, cumsum)})
+		pending = nil
+	}
+	flush()
+
+	for _, window := range windows {
+		window.Standalone = true
+		if window.Class == nil {
+			window.Class = class
+		}
+	}
+	return windows
+}
+
+// isMethodNode reports whether a class member is a method, including decorated
+// Python methods but not Python properties
+func isMethodNode(node *sitter.Node, lang Language, code []byte) bool {
+	if node.Type() == "decorated_definition" {
+		if definition := node.ChildByFieldName("definition"); definition != nil {
+			node = definition
+		}
+	}
+	entityType, ok := nodeEntityType(node, lang, code)
+	return ok && (entityType == EntityTypeMethod || entityType == EntityTypeFunction)
+}
+
+// propertySignature returns the decorators and signature of a class member that
+// declares a Python property, its getter, or "" for other members. Properties
+// are listed with the fields rather than split out like methods.
+func propertySignature(node *sitter.Node, lang Language, code []byte) string {
+	if lang != LanguagePython || node.Type() != "decorated_definition" {
+		return ""
+	}
+	definition := node.ChildByFieldName("definition")
+	if definition == nil || definition.Type() != "function_definition" {
+		return ""
+	}
+	annotations := entityAnnotations(definition, lang, code)
+	if _, getter := pythonPropertyAccessor(annotations); !getter {
+		return ""
+	}
+	return strings.Join(annotations, " ") + " " + extractSignature(definition, EntityTypeProperty, lang, code)
+}
+
+// isFieldNode reports whether a class member declares a field, including Python
+// class attributes
+func isFieldNode(node *sitter.Node) bool {
+	if fieldNodeTypes[node.Type()] {
+		return true
+	}
+	return node.Type() == "expression_statement" && node.NamedChildCount() > 0 && node.NamedChild(0).Type() == "assignment"
+}
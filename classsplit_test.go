@@ -0,0 +1,132 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+const splitClassJava = `public class Account extends Base {
+    private final String id;
+    private long balance = 0;
+
+    /** Deposits an amount into the account. */
+    public void deposit(long amount) {
+        validate(amount);
+        balance += amount;
+        audit("deposit", amount);
+    }
+
+    /** Withdraws an amount from the account. */
+    public void withdraw(long amount) {
+        validate(amount);
+        balance -= amount;
+        audit("withdraw", amount);
+    }
+
+    public long balance() {
+        return balance;
+    }
+}
+`
+
+func TestChunkSplitClasses(t *testing.T) {
+	opts := NewChunkOptions(WithMaxChunkSize(150), WithSplitClasses(true), WithOverlapLines(0))
+	chunks, err := Chunk("Account.java", splitClassJava, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	methods := []string{"deposit(long amount)", "withdraw(long amount)", "balance() {"}
+	for _, method := range methods {
+		var found []CodeChunk
+		for _, chunk := range chunks {
+			if strings.Contains(chunk.Text, method) {
+				found = append(found, chunk)
+			}
+		}
+		if len(found) != 1 {
+			t.Fatalf("expected %q in exactly one chunk, got %d", method, len(found))
+		}
+		for _, other := range methods {
+			if other != method && strings.Contains(found[0].Text, other) {
+				t.Errorf("expected %q in its own chunk, got %q", method, found[0].Text)
+			}
+		}
+	}
+
+	for _, chunk := range chunks {
+		class := chunk.Context.Class
+		if class == nil {
+			t.Fatalf("expected class context on chunk %d", chunk.Index)
+		}
+		if class.Name != "Account" || !strings.Contains(class.Signature, "class Account extends Base") {
+			t.Errorf("unexpected class context %+v", class)
+		}
+		if len(class.Fields) != 2 || class.Fields[1] != "private long balance = 0;" {
+			t.Errorf("unexpected class fields %q", class.Fields)
+		}
+		if !strings.Contains(chunk.ContextualizedText, "Class: ") || !strings.Contains(chunk.ContextualizedText, "Fields: private final String id; private long balance = 0") {
+			t.Errorf("expected class header lines, got %q", chunk.ContextualizedText)
+		}
+	}
+
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "Deposits an amount") && !strings.Contains(chunk.Text, "deposit(long amount)") {
+			t.Errorf("expected the doc comment with its method, got %q", chunk.Text)
+		}
+	}
+}
+
+func TestChunkSplitClassesDisabled(t *testing.T) {
+	opts := NewChunkOptions(WithMaxChunkSize(150), WithOverlapLines(0))
+	chunks, err := Chunk("Account.java", splitClassJava, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for _, chunk := range chunks {
+		if chunk.Context.Class != nil {
+			t.Errorf("expected no class context without SplitClasses, got %+v", chunk.Context.Class)
+		}
+		if strings.Contains(chunk.ContextualizedText, "Fields: ") {
+			t.Errorf("expected no class header lines, got %q", chunk.ContextualizedText)
+		}
+	}
+}
+
+func TestChunkSplitClassesPython(t *testing.T) {
+	code := `class Cache:
+    ttl = 60
+
+    def get(self, key):
+        value = self.store.get(key)
+        self.hits += 1
+        return value
+
+    @property
+    def size(self):
+        total = len(self.store)
+        return total
+
+    def clear(self):
+        self.store.clear()
+        self.hits = 0
+`
+	opts := NewChunkOptions(WithMaxChunkSize(60), WithSplitClasses(true), WithOverlapLines(0))
+	chunks, err := Chunk("cache.py", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "def size") && !strings.Contains(chunk.Text, "@property") {
+			t.Errorf("expected the decorator with its method, got %q", chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "def get") && strings.Contains(chunk.Text, "def size") {
+			t.Errorf("expected get and size in separate chunks, got %q", chunk.Text)
+		}
+		// Properties are listed with the fields
+		if chunk.Context.Class == nil || strings.Join(chunk.Context.Class.Fields, "; ") != "ttl = 60; @property def size(self)" {
+			t.Errorf("expected class context with the ttl field and size property, got %+v", chunk.Context.Class)
+		}
+	}
+}
@@ -29,8 +29,14 @@ package codechunk
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	sitter "github.com/smacker/go-tree-sitter"
 )
@@ -48,6 +54,28 @@ func Chunk(filepath string, code string, opts *ChunkOptions) ([]CodeChunk, error
 	return chunkFile(filepath, []byte(code), options)
 }
 
+// ChunkWithContext is like Chunk but stops once ctx is done, returning an error
+// matching ErrCancelled and ctx.Err(). Parsing, entity extraction, window
+// assignment and chunk building all check ctx as they go, so a deadline bounds
+// the time spent on a pathological file.
+func ChunkWithContext(ctx context.Context, filepath string, code string, opts *ChunkOptions) ([]CodeChunk, error) {
+	options := ChunkOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	options.ctx = ctx
+	return chunkFile(filepath, []byte(code), options)
+}
+
+// chunkContext returns the context chunking stops at: the one set by a
+// WithContext function, or context.Background()
+func (o ChunkOptions) chunkContext() context.Context {
+	if o.ctx == nil {
+		return context.Background()
+	}
+	return o.ctx
+}
+
 // ChunkBytes is like Chunk but accepts []byte instead of string.
 func ChunkBytes(filepath string, code []byte, opts *ChunkOptions) ([]CodeChunk, error) {
 	options := ChunkOptions{}
@@ -59,44 +87,121 @@ func ChunkBytes(filepath string, code []byte, opts *ChunkOptions) ([]CodeChunk,
 
 // chunkFile is the internal implementation
 func chunkFile(filepath string, code []byte, opts ChunkOptions) ([]CodeChunk, error) {
-	// Detect language
-	lang := opts.Language
-	if lang == "" {
-		lang = DetectLanguage(filepath)
+	return chunkFileMeasured(filepath, code, opts, nil, nil)
+}
+
+// chunkFileMeasured is chunkFile recording the language and stage timings in m,
+// parsing with parser if set
+func chunkFileMeasured(filepath string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	if m == nil {
+		m = &FileMetrics{}
 	}
-	if lang == "" {
-		return nil, ErrUnsupportedLanguage
+
+	code, truncated, err := limitFileSize(filepath, code, opts)
+	if err != nil {
+		return nil, err
 	}
 
-	// Parse the code
-	parseResult, err := parse(code, lang)
+	hierarchical := opts.Hierarchical
+	var chunks []CodeChunk
+	if frontend := frontendFor(filepath); frontend != nil {
+		// Container files have no single scope tree to give an overview of
+		hierarchical = false
+		opts.Hierarchical = false
+		chunks, err = frontend(filepath, code, opts, m, parser)
+	} else {
+		chunks, err = chunkSourceMeasured(filepath, code, opts, m, parser)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if chunks, err = limitChunks(filepath, chunks, truncated, opts); err != nil {
+		return nil, err
+	}
+	classifyChunks(filepath, code, chunks)
+	constrainChunks(filepath, code, chunks)
+	attributeChunks(filepath, opts.Provenance, chunks)
+	linkChunks(filepath, chunks, hierarchical)
+	return chunks, nil
+}
 
-	// Extract entities
-	entities := extractEntities(parseResult.Tree.RootNode(), lang, code)
+// chunkSourceMeasured chunks source code in a single language, as chunkFileMeasured
+// does for files that have no frontend
+func chunkSourceMeasured(filepath string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	logger := loggerFor(opts)
+
+	// Detect language
+	lang := resolveLanguage(filepath, code, opts, logger)
+	if lang == "" {
+		return nil, &ChunkError{Stage: StageDetect, Filepath: filepath, Err: ErrUnsupportedLanguage}
+	}
+	m.Language = lang
+
+	// Parse the code and extract entities
+	parsed, err := parseAndExtractMeasured(opts.chunkContext(), code, lang, opts.ParseCache, m, parser)
+	if err != nil {
+		return nil, stageError(err, StageParse, filepath, lang)
+	}
+	defer parsed.Close()
+	logParseError(logger, filepath, parsed.Error)
+	if err := checkParseErrorRatio(parsed.Error, len(code), opts); err != nil {
+		return nil, &ChunkError{Stage: StageParse, Filepath: filepath, Language: lang, Err: err}
+	}
+
+	start := time.Now()
 
 	// Build scope tree
-	scopeTree := buildScopeTree(entities)
+	scopeTree := buildScopeTree(nameAnonymousEntities(parsed.Entities, filepath, opts.AnonymousNaming))
+	scopeTree.Exports = append(scopeTree.Exports, parsed.Exports...)
+	qualifyScopeTree(scopeTree, fileNamespace(parsed.Tree.RootNode(), code, sourcePath(filepath, opts), lang), lang)
+	m.Entities = len(scopeTree.AllEntities)
 
 	// Chunk the code
-	chunks, err := chunkCode(
-		parseResult.Tree.RootNode(),
-		code,
-		scopeTree,
-		lang,
-		opts,
-		filepath,
-	)
+	var chunks []CodeChunk
+	if useSlidingWindow(code, scopeTree, parsed.Error, opts) {
+		logger.Debug("chunking by sliding window", "filepath", filepath, "entities", len(scopeTree.AllEntities))
+		chunks = chunkSlidingWindow(filepath, code, lang, opts)
+	} else {
+		chunks, err = chunkCode(
+			parsed.Tree.RootNode(),
+			code,
+			scopeTree,
+			parsed.Calls,
+			importUses(parsed, code, opts),
+			parsed.FileDoc,
+			lang,
+			opts,
+			filepath,
+		)
+	}
+	if err == nil && opts.Hierarchical {
+		chunks = withOverview(overviewChunk(filepath, code, lang, scopeTree, parsed.FileDoc, opts), chunks)
+	}
+	m.ChunkTime = time.Since(start)
 	if err != nil {
 		return nil, err
 	}
 
-	// Attach parse error to chunks if present
-	if parseResult.Error != nil {
+	// Link the chunks of test files to the symbols their tests exercise
+	if isTestClassification(classifyFile(filepath, code)) {
+		suites := findTestSuites(parsed.Tree.RootNode(), code, lang)
+		for i := range chunks {
+			chunks[i].Context.TestedSymbols = testedSymbols(chunks[i].ByteRange, scopeTree, suites, lang)
+		}
+	}
+
+	// Attach the comment tags to the chunks they appear in
+	if tags := findCommentTags(parsed.Tree.RootNode(), code); tags != nil {
 		for i := range chunks {
-			chunks[i].Context.ParseError = parseResult.Error
+			chunks[i].Context.Tags = tagsInRange(tags, chunks[i].ByteRange)
+		}
+	}
+
+	// Attach the parse error to the chunks overlapping broken regions
+	if parsed.Error != nil {
+		m.ErrorNodeCount = parsed.Error.Count
+		for i := range chunks {
+			chunks[i].Context.ParseError = chunkParseError(parsed.Error, chunks[i].ByteRange)
 		}
 	}
 
@@ -108,6 +213,9 @@ func chunkCode(
 	rootNode interface{},
 	code []byte,
 	scopeTree *ScopeTree,
+	calls []CallSite,
+	imports []ImportUse,
+	fileDoc string,
 	lang Language,
 	opts ChunkOptions,
 	filepath string,
@@ -115,176 +223,324 @@ func chunkCode(
 	// Verify rootNode is a valid tree-sitter node
 	_, ok := rootNode.(*sitter.Node)
 	if !ok {
-		return nil, ErrParseFailed
+		return nil, &ChunkError{Stage: StageParse, Filepath: filepath, Language: lang, Err: ErrParseFailed}
 	}
 
 	// Apply defaults
-	if opts.MaxChunkSize == 0 {
-		opts.MaxChunkSize = 1500
-	}
-	if opts.ContextMode == "" {
-		opts.ContextMode = ContextModeFull
-	}
-	if opts.SiblingDetail == "" {
-		opts.SiblingDetail = SiblingDetailSignatures
-	}
-	if opts.OverlapLines == 0 {
-		opts.OverlapLines = 10
-	}
+	opts = opts.withDefaults()
 
 	maxSize := opts.MaxChunkSize
 
-	// Preprocess NWS cumulative sum
+	// Preprocess NWS cumulative sum and line starts
 	cumsum := preprocessNwsCumsum(code)
+	positions := newPositionIndex(code)
 
 	// Get root's children
 	children := getNodeChildren(rootNode)
 
-	// Assign nodes to windows
-	rawWindows := greedyAssignWindows(children, code, cumsum, maxSize)
+	// Strip license headers
+	var boilerplate *BoilerplateInfo
+	if opts.StripBoilerplate {
+		patterns, err := boilerplatePatterns(opts)
+		if err != nil {
+			return nil, &ChunkError{Stage: StageWindow, Filepath: filepath, Language: lang, Err: err}
+		}
+		children, boilerplate = stripBoilerplate(children, code, cumsum, patterns)
+	}
 
-	// Merge adjacent windows
-	mergedWindows := mergeAdjacentWindows(rawWindows, maxSize)
+	// Assign nodes to windows and merge adjacent windows
+	logger := loggerFor(opts)
+	rawWindows, mergedWindows := assignWindows(children, code, cumsum, opts, lang, logger)
+	if opts.chunkContext().Err() != nil {
+		return nil, &ChunkError{Stage: StageWindow, Filepath: filepath, Language: lang, Err: cancelledError(opts.chunkContext())}
+	}
+	logger.Debug("assigned windows", "filepath", filepath, "raw", len(rawWindows), "merged", len(mergedWindows), "maxSize", maxSize)
+	mergedWindows, kinds := classifyWindows(mergedWindows, opts.SkipTrivialChunks)
 
 	totalChunks := len(mergedWindows)
+	workers := workersFor(totalChunks, opts.Parallelism)
+	if workers > 1 {
+		logger.Debug("building chunks in parallel", "filepath", filepath, "chunks", totalChunks, "workers", workers)
+	}
 
 	// Rebuild text for all windows
 	rebuiltTexts := make([]*rebuiltText, len(mergedWindows))
-	for i, window := range mergedWindows {
-		rebuiltTexts[i] = rebuildText(window, code)
+	parallelFor(len(mergedWindows), workers, func(i int) {
+		rebuiltTexts[i] = rebuildText(mergedWindows[i], code, positions)
+	})
+
+	// Lazy chunks share a single copy of the source
+	var source string
+	if opts.LazyText {
+		source = string(code)
 	}
 
-	// Build chunks
+	// Build chunks. Each depends only on its own text and the previous one's, so
+	// blocks of chunks can be built on separate goroutines.
 	chunks := make([]CodeChunk, len(mergedWindows))
-	for i, text := range rebuiltTexts {
+	parallelFor(len(rebuiltTexts), workers, func(i int) {
+		if opts.chunkContext().Err() != nil {
+			return
+		}
+		text := rebuiltTexts[i]
 		var ctx ChunkContext
 		if opts.ContextMode == ContextModeNone {
 			ctx = ChunkContext{
-				Scope:    []EntityInfo{},
-				Entities: []ChunkEntityInfo{},
-				Siblings: []SiblingInfo{},
-				Imports:  []ImportInfo{},
+				Scope:      []EntityInfo{},
+				Entities:   []ChunkEntityInfo{},
+				Siblings:   []SiblingInfo{},
+				Imports:    []ImportInfo{},
+				References: []ReferenceInfo{},
 			}
 		} else {
-			ctx = buildChunkContext(text, scopeTree, opts, filepath, lang)
+			ctx = buildChunkContext(text, scopeTree, calls, imports, opts, filepath, lang)
+			ctx.Boilerplate = boilerplate
+		}
+		ctx.Cell = opts.cell
+		if opts.ContextMode == ContextModeFull {
+			ctx.Siblings = append(ctx.Siblings, sectionSiblings(text.byteRange, opts.sections, opts.SiblingDetail)...)
+			ctx.Class = mergedWindows[i].Class
+			ctx.FileDoc = fileDoc
 		}
 
 		var overlapText string
-		if opts.OverlapLines > 0 && i > 0 {
-			prevText := rebuiltTexts[i-1]
-			if prevText != nil && prevText.text != "" {
-				prevLines := strings.Split(prevText.text, "\n")
-				overlapLineCount := opts.OverlapLines
-				if overlapLineCount > len(prevLines) {
-					overlapLineCount = len(prevLines)
-				}
-				overlapText = strings.Join(prevLines[len(prevLines)-overlapLineCount:], "\n")
-			}
+		if i > 0 {
+			overlapText = getOverlapText(rebuiltTexts[i-1], scopeTree, opts)
 		}
 
-		contextualizedText := FormatChunkWithContext(text.text, ctx, overlapText)
+		var importBlock string
+		if opts.IncludeImportsInText {
+			importBlock = getImportBlock(text.byteRange, scopeTree, code)
+		}
 
 		chunks[i] = CodeChunk{
-			Text:               text.text,
-			ContextualizedText: contextualizedText,
-			ByteRange:          text.byteRange,
-			LineRange:          text.lineRange,
-			Context:            ctx,
-			Index:              i,
-			TotalChunks:        totalChunks,
+			ByteRange:      text.byteRange,
+			LineRange:      text.lineRange,
+			Span:           positions.span(text.byteRange),
+			Stats:          chunkStats(text.text, text.lineRange, opts),
+			Context:        ctx,
+			Index:          i,
+			TotalChunks:    totalChunks,
+			QualifiedNames: qualifiedNamesInRange(text.byteRange, scopeTree),
+			Kind:           kinds[i],
 		}
+		if opts.LazyText {
+			chunks[i].lazy = &lazyText{source: source, opts: opts, overlap: overlapText, imports: importBlock}
+		} else {
+			chunks[i].Text = text.text
+			chunks[i].contextualize(opts, text.text, overlapText, importBlock)
+		}
+	})
+	if opts.chunkContext().Err() != nil {
+		return nil, &ChunkError{Stage: StageBuild, Filepath: filepath, Language: lang, Err: cancelledError(opts.chunkContext())}
 	}
 
 	return chunks, nil
 }
 
 // ChunkStream streams chunks as they are generated.
-// Useful for large files. Note: TotalChunks is -1 in streaming mode.
+// Useful for large files. Note: TotalChunks is -1 in streaming mode. Each chunk is
+// sent once the next one is generated, so that its NextChunkID is set.
 func ChunkStream(filepath string, code string, opts *ChunkOptions) (<-chan CodeChunk, error) {
+	return chunkStream(context.Background(), filepath, []byte(code), code, opts, func() {})
+}
+
+// ChunkStreamWithContext is like ChunkStream but stops once ctx is done. An error
+// matching ErrCancelled is returned if ctx is done before streaming starts;
+// afterwards the channel is closed without the remaining chunks, so check
+// ctx.Err() once it is.
+func ChunkStreamWithContext(ctx context.Context, filepath string, code string, opts *ChunkOptions) (<-chan CodeChunk, error) {
+	return chunkStream(ctx, filepath, []byte(code), code, opts, func() {})
+}
+
+// chunkStream implements ChunkStreamWithContext for code, which source holds as a
+// string for lazy chunks if it is not empty. release is called once code is no
+// longer used.
+func chunkStream(ctx context.Context, filepath string, code []byte, source string, opts *ChunkOptions, release func()) (<-chan CodeChunk, error) {
 	options := ChunkOptions{}
 	if opts != nil {
 		options = *opts
 	}
+	options.ctx = ctx
 
-	lang := options.Language
-	if lang == "" {
-		lang = DetectLanguage(filepath)
+	// Chunks are built before returning unless the stream goroutine is started
+	streaming := false
+	defer func() {
+		if !streaming {
+			release()
+		}
+	}()
+
+	// The overview chunk of hierarchical chunking lists the IDs of all other chunks,
+	// and the last chunk of a file cut short by a limit is marked Truncated
+	if frontendFor(filepath) != nil || options.Hierarchical || options.MaxFileSize > 0 || options.MaxChunksPerFile > 0 {
+		chunks, err := chunkFile(filepath, code, options)
+		if err != nil {
+			return nil, err
+		}
+		return streamChunks(ctx, chunks), nil
 	}
+
+	logger := loggerFor(options)
+
+	lang := resolveLanguage(filepath, code, options, logger)
 	if lang == "" {
-		return nil, ErrUnsupportedLanguage
+		return nil, &ChunkError{Stage: StageDetect, Filepath: filepath, Err: ErrUnsupportedLanguage}
 	}
 
-	parseResult, err := parseString(code, lang)
+	parsed, err := parseAndExtractMeasured(ctx, code, lang, options.ParseCache, nil, nil)
 	if err != nil {
-		return nil, err
+		return nil, stageError(err, StageParse, filepath, lang)
+	}
+	logParseError(logger, filepath, parsed.Error)
+	if err := checkParseErrorRatio(parsed.Error, len(code), options); err != nil {
+		parsed.Close()
+		return nil, &ChunkError{Stage: StageParse, Filepath: filepath, Language: lang, Err: err}
 	}
 
-	entities := extractEntities(parseResult.Tree.RootNode(), lang, []byte(code))
-	scopeTree := buildScopeTree(entities)
+	scopeTree := buildScopeTree(nameAnonymousEntities(parsed.Entities, filepath, options.AnonymousNaming))
+	scopeTree.Exports = append(scopeTree.Exports, parsed.Exports...)
+	qualifyScopeTree(scopeTree, fileNamespace(parsed.Tree.RootNode(), code, sourcePath(filepath, options), lang), lang)
+
+	if useSlidingWindow(code, scopeTree, parsed.Error, options) {
+		tags := findCommentTags(parsed.Tree.RootNode(), code)
+		parsed.Close()
+		logger.Debug("chunking by sliding window", "filepath", filepath, "entities", len(scopeTree.AllEntities))
+		chunks := chunkSlidingWindow(filepath, code, lang, options)
+		for i := range chunks {
+			chunks[i].Context.Tags = tagsInRange(tags, chunks[i].ByteRange)
+		}
+		classifyChunks(filepath, code, chunks)
+		constrainChunks(filepath, code, chunks)
+		attributeChunks(filepath, options.Provenance, chunks)
+		linkChunks(filepath, chunks, false)
+		return streamChunks(ctx, chunks), nil
+	}
+
+	var patterns []*regexp.Regexp
+	if options.StripBoilerplate {
+		if patterns, err = boilerplatePatterns(options); err != nil {
+			parsed.Close()
+			return nil, &ChunkError{Stage: StageWindow, Filepath: filepath, Language: lang, Err: err}
+		}
+	}
 
 	ch := make(chan CodeChunk)
 
+	streaming = true
 	go func() {
 		defer close(ch)
+		defer release()
+		defer parsed.Close()
 
-		if options.MaxChunkSize == 0 {
-			options.MaxChunkSize = 1500
+		options = options.withDefaults()
+		if options.LazyText && source == "" {
+			source = string(code)
 		}
-		if options.ContextMode == "" {
-			options.ContextMode = ContextModeFull
+
+		maxSize := options.MaxChunkSize
+		cumsum := preprocessNwsCumsum(code)
+		positions := newPositionIndex(code)
+		children := getNodeChildren(parsed.Tree.RootNode())
+		uses := importUses(parsed, code, options)
+		var boilerplate *BoilerplateInfo
+		if options.StripBoilerplate {
+			children, boilerplate = stripBoilerplate(children, code, cumsum, patterns)
 		}
-		if options.SiblingDetail == "" {
-			options.SiblingDetail = SiblingDetailSignatures
+		rawWindows, mergedWindows := assignWindows(children, code, cumsum, options, lang, logger)
+		if ctx.Err() != nil {
+			return
 		}
-		if options.OverlapLines == 0 {
-			options.OverlapLines = 10
+		logger.Debug("assigned windows", "filepath", filepath, "raw", len(rawWindows), "merged", len(mergedWindows), "maxSize", maxSize)
+		mergedWindows, kinds := classifyWindows(mergedWindows, options.SkipTrivialChunks)
+
+		// Each chunk is sent once the next one is built, to link it to the next ID,
+		// until ctx is done
+		done := ctx.Done()
+		classification := classifyFile(filepath, code)
+		build := goBuildConstraints(filepath, code)
+		provenance := fileProvenance(options.Provenance, filepath)
+		var suites []testSuite
+		if isTestClassification(classification) {
+			suites = findTestSuites(parsed.Tree.RootNode(), code, lang)
 		}
-
-		maxSize := options.MaxChunkSize
-		cumsum := preprocessNwsCumsum([]byte(code))
-		children := getNodeChildren(parseResult.Tree.RootNode())
-		rawWindows := greedyAssignWindows(children, []byte(code), cumsum, maxSize)
-		mergedWindows := mergeAdjacentWindows(rawWindows, maxSize)
-
-		var prevText string
+		tags := findCommentTags(parsed.Tree.RootNode(), code)
+		var prevText *rebuiltText
+		var pending *CodeChunk
 		for i, window := range mergedWindows {
-			text := rebuildText(window, []byte(code))
+			if options.chunkContext().Err() != nil {
+				return
+			}
+			text := rebuildText(window, code, positions)
 
 			var ctx ChunkContext
 			if options.ContextMode == ContextModeNone {
 				ctx = ChunkContext{
-					Scope:    []EntityInfo{},
-					Entities: []ChunkEntityInfo{},
-					Siblings: []SiblingInfo{},
-					Imports:  []ImportInfo{},
+					Scope:      []EntityInfo{},
+					Entities:   []ChunkEntityInfo{},
+					Siblings:   []SiblingInfo{},
+					Imports:    []ImportInfo{},
+					References: []ReferenceInfo{},
 				}
 			} else {
-				ctx = buildChunkContext(text, scopeTree, options, filepath, lang)
+				ctx = buildChunkContext(text, scopeTree, parsed.Calls, uses, options, filepath, lang)
+				ctx.Boilerplate = boilerplate
 			}
-
-			var overlapText string
-			if options.OverlapLines > 0 && prevText != "" {
-				prevLines := strings.Split(prevText, "\n")
-				overlapLineCount := options.OverlapLines
-				if overlapLineCount > len(prevLines) {
-					overlapLineCount = len(prevLines)
-				}
-				overlapText = strings.Join(prevLines[len(prevLines)-overlapLineCount:], "\n")
+			if options.ContextMode == ContextModeFull {
+				ctx.Class = window.Class
+				ctx.FileDoc = parsed.FileDoc
+			}
+			ctx.ParseError = chunkParseError(parsed.Error, text.byteRange)
+			ctx.Classification = classification
+			ctx.Build = build
+			ctx.Provenance = provenance
+			ctx.Tags = tagsInRange(tags, text.byteRange)
+			if isTestClassification(classification) {
+				ctx.TestedSymbols = testedSymbols(text.byteRange, scopeTree, suites, lang)
 			}
 
-			contextualizedText := FormatChunkWithContext(text.text, ctx, overlapText)
+			overlapText := getOverlapText(prevText, scopeTree, options)
+			var importBlock string
+			if options.IncludeImportsInText {
+				importBlock = getImportBlock(text.byteRange, scopeTree, code)
+			}
 
-			ch <- CodeChunk{
-				Text:               text.text,
-				ContextualizedText: contextualizedText,
-				ByteRange:          text.byteRange,
-				LineRange:          text.lineRange,
-				Context:            ctx,
-				Index:              i,
-				TotalChunks:        -1,
+			chunk := CodeChunk{
+				ByteRange:      text.byteRange,
+				LineRange:      text.lineRange,
+				Span:           positions.span(text.byteRange),
+				Stats:          chunkStats(text.text, text.lineRange, options),
+				Context:        ctx,
+				Index:          i,
+				TotalChunks:    -1,
+				QualifiedNames: qualifiedNamesInRange(text.byteRange, scopeTree),
+				Kind:           kinds[i],
 			}
+			if options.LazyText {
+				chunk.lazy = &lazyText{source: source, opts: options, overlap: overlapText, imports: importBlock}
+			} else {
+				chunk.Text = text.text
+				chunk.contextualize(options, text.text, overlapText, importBlock)
+			}
+			chunk.ID = chunkID(filepath, chunk)
+			if pending != nil {
+				chunk.PrevChunkID = pending.ID
+				pending.NextChunkID = chunk.ID
+				select {
+				case ch <- *pending:
+				case <-done:
+					return
+				}
+			}
+			pending = &chunk
 
-			prevText = text.text
+			prevText = text
+		}
+		if pending != nil {
+			select {
+			case ch <- *pending:
+			case <-done:
+			}
 		}
 	}()
 
@@ -297,6 +553,9 @@ func ChunkBatch(files []FileInput, opts *BatchOptions) []BatchResult {
 }
 
 // ChunkBatchWithContext processes multiple files with context for cancellation.
+// Results are in input order. Files left unprocessed or interrupted when ctx is
+// done have Skipped set and an Error matching ErrCancelled, so they can be
+// retried later.
 func ChunkBatchWithContext(ctx context.Context, files []FileInput, opts *BatchOptions) []BatchResult {
 	if len(files) == 0 {
 		return []BatchResult{}
@@ -306,27 +565,33 @@ func ChunkBatchWithContext(ctx context.Context, files []FileInput, opts *BatchOp
 	if opts != nil {
 		options = *opts
 	}
+	options.ctx = ctx
 
 	concurrency := options.Concurrency
 	if concurrency <= 0 {
 		concurrency = 10
 	}
 
+	budget := newByteBudget(options.MaxInFlightBytes)
+	spill := newResultSpiller(options.SpillWriter)
+	checkpoint := newBatchCheckpoint(options)
+
 	results := make([]BatchResult, len(files))
+	processed := make([]bool, len(files))
 	work := make(chan int, len(files))
 	for i := range files {
 		work <- i
 	}
 	close(work)
 
-	var completed int
-	var mu sync.Mutex
+	progress := newProgressReporter(options, len(files))
 
 	var wg sync.WaitGroup
 	for i := 0; i < concurrency; i++ {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
+			parser := newLanguageParser()
 
 			for {
 				select {
@@ -338,64 +603,75 @@ func ChunkBatchWithContext(ctx context.Context, files []FileInput, opts *BatchOp
 					}
 
 					file := files[idx]
-					fileOpts := options.ChunkOptions
-					if file.Options != nil {
-						if file.Options.MaxChunkSize > 0 {
-							fileOpts.MaxChunkSize = file.Options.MaxChunkSize
-						}
-						if file.Options.ContextMode != "" {
-							fileOpts.ContextMode = file.Options.ContextMode
-						}
-						if file.Options.SiblingDetail != "" {
-							fileOpts.SiblingDetail = file.Options.SiblingDetail
-						}
-						if file.Options.Language != "" {
-							fileOpts.Language = file.Options.Language
-						}
-						if file.Options.OverlapLines > 0 {
-							fileOpts.OverlapLines = file.Options.OverlapLines
-						}
-						fileOpts.FilterImports = file.Options.FilterImports
+					hash := checkpoint.hash(file)
+					result, done := options.SkipPolicy.skipped(file)
+					if !done {
+						result, done = checkpoint.resumed(file, hash)
 					}
-
-					chunks, err := chunkFile(file.Filepath, []byte(file.Code), fileOpts)
-
-					if err != nil {
-						results[idx] = BatchResult{
-							Filepath: file.Filepath,
-							Chunks:   nil,
-							Error:    err,
-						}
-					} else {
-						results[idx] = BatchResult{
-							Filepath: file.Filepath,
-							Chunks:   chunks,
-							Error:    nil,
+					if !done {
+						reserved, err := budget.acquire(ctx, len(file.Code))
+						if err != nil {
+							return
 						}
-					}
 
-					mu.Lock()
-					completed++
-					if options.OnProgress != nil {
-						options.OnProgress(completed, len(files), file.Filepath, err == nil)
+						var panicked bool
+						result, panicked = chunkFileIsolated(file, options, parser)
+						if panicked {
+							parser = newLanguageParser()
+						}
+						result = spill.write(result)
+						budget.release(reserved)
+						checkpoint.record(result, hash)
 					}
-					mu.Unlock()
+					results[idx] = result
+					processed[idx] = true
+					progress.report(file.Filepath, result.Error == nil || done)
 				}
 			}
 		}()
 	}
 
 	wg.Wait()
+	progress.close()
+
+	// Files not reached before cancellation are reported as skipped so callers can
+	// tell them apart from files that produced no chunks
+	for i := range results {
+		if !processed[i] {
+			results[i] = BatchResult{
+				Filepath: files[i].Filepath,
+				Error:    cancelledError(ctx),
+				Skipped:  true,
+			}
+		}
+	}
 
+	if options.Dedupe && options.SpillWriter == nil {
+		results = DedupeChunks(results)
+	}
 	return results
 }
 
+// cancelledError returns an error matching both ErrCancelled and the context's error
+func cancelledError(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return fmt.Errorf("%w: %w", ErrCancelled, err)
+	}
+	return ErrCancelled
+}
+
 // ChunkBatchStream streams batch results as files complete processing.
 func ChunkBatchStream(files []FileInput, opts *BatchOptions) <-chan BatchResult {
 	return ChunkBatchStreamWithContext(context.Background(), files, opts)
 }
 
 // ChunkBatchStreamWithContext streams batch results with context for cancellation.
+//
+// When MaxInFlightBytes is set, workers wait before chunking a file until the
+// source bytes of files being processed or waiting to be received fit within the
+// budget, so a slow consumer applies backpressure instead of buffering results.
+//
+// Results arrive in completion order unless Ordered is set.
 func ChunkBatchStreamWithContext(ctx context.Context, files []FileInput, opts *BatchOptions) <-chan BatchResult {
 	ch := make(chan BatchResult)
 
@@ -408,6 +684,7 @@ func ChunkBatchStreamWithContext(ctx context.Context, files []FileInput, opts *B
 	if opts != nil {
 		options = *opts
 	}
+	options.ctx = ctx
 
 	concurrency := options.Concurrency
 	if concurrency <= 0 {
@@ -417,148 +694,327 @@ func ChunkBatchStreamWithContext(ctx context.Context, files []FileInput, opts *B
 	go func() {
 		defer close(ch)
 
-		work := make(chan FileInput, len(files))
-		for _, file := range files {
-			work <- file
+		work := make(chan int, len(files))
+		for i := range files {
+			work <- i
 		}
 		close(work)
 
-		var completed int
-		var mu sync.Mutex
+		budget := newByteBudget(options.MaxInFlightBytes)
+		checkpoint := newBatchCheckpoint(options)
+		var sequencer *resultSequencer
+		if options.Ordered {
+			sequencer = newResultSequencer(concurrency * orderedWindowPerWorker)
+		}
+
 		total := len(files)
+		progress := newProgressReporter(options, total)
 
 		var wg sync.WaitGroup
 		for i := 0; i < concurrency; i++ {
 			wg.Add(1)
 			go func() {
 				defer wg.Done()
+				parser := newLanguageParser()
 
 				for {
 					select {
 					case <-ctx.Done():
 						return
-					case file, ok := <-work:
+					case idx, ok := <-work:
 						if !ok {
 							return
 						}
 
-						fileOpts := options.ChunkOptions
-						if file.Options != nil {
-							if file.Options.MaxChunkSize > 0 {
-								fileOpts.MaxChunkSize = file.Options.MaxChunkSize
-							}
-							if file.Options.ContextMode != "" {
-								fileOpts.ContextMode = file.Options.ContextMode
-							}
-							if file.Options.SiblingDetail != "" {
-								fileOpts.SiblingDetail = file.Options.SiblingDetail
-							}
-							if file.Options.Language != "" {
-								fileOpts.Language = file.Options.Language
+						file := files[idx]
+						if sequencer != nil && sequencer.start(ctx, idx) != nil {
+							return
+						}
+						hash := checkpoint.hash(file)
+						result, done := options.SkipPolicy.skipped(file)
+						if !done {
+							result, done = checkpoint.resumed(file, hash)
+						}
+						var reserved int64
+						if !done {
+							var err error
+							reserved, err = budget.acquire(ctx, len(file.Code))
+							if err != nil {
+								return
 							}
-							if file.Options.OverlapLines > 0 {
-								fileOpts.OverlapLines = file.Options.OverlapLines
+						}
+						if sequencer != nil {
+							sequencer.started()
+						}
+						if !done {
+							var panicked bool
+							result, panicked = chunkFileIsolated(file, options, parser)
+							if panicked {
+								parser = newLanguageParser()
 							}
-							fileOpts.FilterImports = file.Options.FilterImports
 						}
 
-						chunks, err := chunkFile(file.Filepath, []byte(file.Code), fileOpts)
+						progress.report(file.Filepath, result.Error == nil || done)
 
-						var result BatchResult
-						if err != nil {
-							result = BatchResult{
-								Filepath: file.Filepath,
-								Chunks:   nil,
-								Error:    err,
-							}
-						} else {
-							result = BatchResult{
-								Filepath: file.Filepath,
-								Chunks:   chunks,
-								Error:    nil,
+						received := func() {
+							budget.release(reserved)
+							if !done {
+								checkpoint.record(result, hash)
 							}
 						}
-
-						mu.Lock()
-						completed++
-						if options.OnProgress != nil {
-							options.OnProgress(completed, total, file.Filepath, err == nil)
+						if sequencer != nil {
+							sequencer.put(idx, sequencedResult{result: result, received: received})
+							continue
 						}
-						mu.Unlock()
-
 						select {
 						case <-ctx.Done():
+							budget.release(reserved)
 							return
 						case ch <- result:
+							received()
 						}
 					}
 				}
 			}()
 		}
 
+		if sequencer != nil {
+			sequencer.send(ctx, ch, total)
+		}
 		wg.Wait()
+		progress.close()
 	}()
 
 	return ch
 }
 
+// merge returns base with the fields set in o overriding it. Zero values leave
+// base unchanged unless o.DefaultsApplied is set, in which case o replaces base
+// entirely.
+func (o *ChunkOptions) merge(base ChunkOptions) ChunkOptions {
+	if o.DefaultsApplied {
+		return *o
+	}
+	if o.MaxChunkSize > 0 {
+		base.MaxChunkSize = o.MaxChunkSize
+	}
+	if o.ContextMode != "" {
+		base.ContextMode = o.ContextMode
+	}
+	if o.SiblingDetail != "" {
+		base.SiblingDetail = o.SiblingDetail
+	}
+	if o.Language != "" {
+		base.Language = o.Language
+	}
+	if o.OverlapLines > 0 {
+		base.OverlapLines = o.OverlapLines
+	}
+	if o.FilterImports {
+		base.FilterImports = true
+	}
+	if o.FormatFunc != nil {
+		base.FormatFunc = o.FormatFunc
+	}
+	if o.CommentPrefix != "" {
+		base.CommentPrefix = o.CommentPrefix
+	}
+	if o.ParseCache != nil {
+		base.ParseCache = o.ParseCache
+	}
+	if o.IncludeImportsInText {
+		base.IncludeImportsInText = true
+	}
+	if o.MaxSiblingSignatureLen != 0 {
+		base.MaxSiblingSignatureLen = o.MaxSiblingSignatureLen
+	}
+	if o.MaxSiblings != 0 {
+		base.MaxSiblings = o.MaxSiblings
+	}
+	if o.OverlapMode != "" {
+		base.OverlapMode = o.OverlapMode
+	}
+	if o.Logger != nil {
+		base.Logger = o.Logger
+	}
+	if o.LazyText {
+		base.LazyText = true
+	}
+	if o.TokenCounter != nil {
+		base.TokenCounter = o.TokenCounter
+	}
+	if o.MinChunkSize != 0 {
+		base.MinChunkSize = o.MinChunkSize
+	}
+	if o.BoundaryHeuristics {
+		base.BoundaryHeuristics = true
+	}
+	if o.SplitClasses {
+		base.SplitClasses = true
+	}
+	if o.ChunkStrategy != "" {
+		base.ChunkStrategy = o.ChunkStrategy
+	}
+	if o.SlidingWindowFallback {
+		base.SlidingWindowFallback = true
+	}
+	if o.Hierarchical {
+		base.Hierarchical = true
+	}
+	if o.StripBoilerplate {
+		base.StripBoilerplate = true
+	}
+	if len(o.BoilerplatePatterns) > 0 {
+		base.BoilerplatePatterns = o.BoilerplatePatterns
+	}
+	if o.MaxContextHeaderSize != 0 {
+		base.MaxContextHeaderSize = o.MaxContextHeaderSize
+	}
+	if o.ContextualizedTextMode != "" {
+		base.ContextualizedTextMode = o.ContextualizedTextMode
+	}
+	if o.Parallelism != 0 {
+		base.Parallelism = o.Parallelism
+	}
+	if o.MaxParseErrorRatio != 0 {
+		base.MaxParseErrorRatio = o.MaxParseErrorRatio
+	}
+	if o.MaxFileSize != 0 {
+		base.MaxFileSize = o.MaxFileSize
+	}
+	if o.MaxChunksPerFile != 0 {
+		base.MaxChunksPerFile = o.MaxChunksPerFile
+	}
+	if o.TruncateOnLimit {
+		base.TruncateOnLimit = true
+	}
+	if o.SkipTrivialChunks {
+		base.SkipTrivialChunks = true
+	}
+	if o.AnonymousNaming != "" {
+		base.AnonymousNaming = o.AnonymousNaming
+	}
+	if o.Provenance != nil {
+		base.Provenance = o.Provenance
+	}
+	return base
+}
+
+// chunkFileInput chunks a single batch input using the batch options merged with
+// the file's own options, reporting to metrics if set. parser is the calling
+// worker's own parser.
+func chunkFileInput(file FileInput, batchOpts ChunkOptions, metrics Metrics, parser *languageParser) BatchResult {
+	start := time.Now()
+	fileOpts := batchOpts
+	if file.Options != nil {
+		fileOpts = file.Options.merge(batchOpts)
+		if !file.Options.DefaultsApplied {
+			// A file's options can turn import filtering off for it
+			fileOpts.FilterImports = file.Options.FilterImports
+		}
+	}
+	fileOpts.Provenance = file.Provenance.merge(fileOpts.Provenance)
+	fileOpts.ctx = batchOpts.ctx
+
+	m := FileMetrics{
+		Filepath:    file.Filepath,
+		SourceBytes: len(file.Code),
+	}
+	chunks, err := chunkFileMeasured(file.Filepath, []byte(file.Code), fileOpts, &m, parser)
+	if metrics != nil {
+		m.TotalTime = time.Since(start)
+		m.Success = err == nil
+		m.Chunks = len(chunks)
+		for _, chunk := range chunks {
+			size := len(chunk.Content())
+			m.ChunkBytes += size
+			if size > m.MaxChunkBytes {
+				m.MaxChunkBytes = size
+			}
+		}
+		metrics.ObserveFile(m)
+	}
+	if err != nil {
+		// A file interrupted by the batch's context can be retried like the files
+		// never reached
+		return BatchResult{
+			Filepath: file.Filepath,
+			Chunks:   nil,
+			Error:    err,
+			Skipped:  errors.Is(err, ErrCancelled),
+		}
+	}
+
+	return BatchResult{
+		Filepath: file.Filepath,
+		Chunks:   chunks,
+		Error:    nil,
+	}
+}
+
 // FormatChunkWithContext formats chunk text with semantic context prepended.
+// Header lines are commented using ChunkOptions.CommentPrefix when called as the
+// FormatFunc of a chunking call, or else the prefix for ctx.Language (see
+// CommentPrefixes).
 func FormatChunkWithContext(text string, ctx ChunkContext, overlapText string) string {
+	return formatChunkWithPrefix(text, ctx, overlapText, headerPrefix(ctx))
+}
+
+// formatChunkWithPrefix formats chunk text with context using the given comment prefix
+func formatChunkWithPrefix(text string, ctx ChunkContext, overlapText string, prefix string) string {
 	parts := make([]string, 0)
 
 	if ctx.Filepath != "" {
 		relPath := getLastPathSegments(ctx.Filepath, 3)
-		parts = append(parts, "# "+relPath)
+		if ctx.Cell != nil {
+			relPath += fmt.Sprintf(" [cell %d]", ctx.Cell.Index)
+		}
+		parts = append(parts, prefix+" "+relPath)
+	}
+
+	if ctx.FileDoc != "" {
+		parts = append(parts, prefix+" Doc: "+fileDocSummary(ctx.FileDoc))
 	}
 
 	if len(ctx.Scope) > 0 {
-		names := make([]string, len(ctx.Scope))
-		for i, s := range ctx.Scope {
-			names[i] = s.Name
-		}
-		for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
-			names[i], names[j] = names[j], names[i]
-		}
-		scopePath := strings.Join(names, " > ")
-		parts = append(parts, "# Scope: "+scopePath)
+		parts = append(parts, prefix+" Scope: "+scopePath(ctx.Scope))
 	}
 
-	signatures := make([]string, 0)
-	for _, e := range ctx.Entities {
-		if e.Signature != "" && e.Type != EntityTypeImport {
-			signatures = append(signatures, e.Signature)
+	if ctx.Class != nil {
+		if ctx.Class.Signature != "" {
+			parts = append(parts, prefix+" Class: "+ctx.Class.Signature)
+		}
+		if len(ctx.Class.Fields) > 0 {
+			fields := make([]string, len(ctx.Class.Fields))
+			for i, field := range ctx.Class.Fields {
+				fields[i] = strings.TrimSuffix(field, ";")
+			}
+			parts = append(parts, prefix+" Fields: "+strings.Join(fields, "; "))
 		}
 	}
-	if len(signatures) > 0 {
-		parts = append(parts, "# Defines: "+strings.Join(signatures, ", "))
+
+	if signatures := definedSignatures(ctx.Entities); len(signatures) > 0 {
+		parts = append(parts, prefix+" Defines: "+strings.Join(signatures, ", "))
 	}
 
 	if len(ctx.Imports) > 0 {
-		importNames := make([]string, 0)
-		for i, imp := range ctx.Imports {
-			if i >= 10 {
-				break
-			}
-			importNames = append(importNames, imp.Name)
-		}
-		parts = append(parts, "# Uses: "+strings.Join(importNames, ", "))
+		parts = append(parts, prefix+" Uses: "+strings.Join(importNames(ctx.Imports, 10), ", "))
 	}
 
-	beforeSiblings := make([]string, 0)
-	afterSiblings := make([]string, 0)
-	for _, s := range ctx.Siblings {
-		if s.Position == "before" {
-			beforeSiblings = append(beforeSiblings, s.Name)
-		} else if s.Position == "after" {
-			afterSiblings = append(afterSiblings, s.Name)
-		}
+	if len(ctx.References) > 0 {
+		parts = append(parts, prefix+" Calls: "+strings.Join(referenceNames(ctx.References, 10), ", "))
 	}
 
-	if len(beforeSiblings) > 0 {
-		parts = append(parts, "# After: "+strings.Join(beforeSiblings, ", "))
+	// Signatures contain commas, so they are separated by semicolons
+	siblingSep := ", "
+	if hasSiblingSignatures(ctx.Siblings) {
+		siblingSep = "; "
 	}
-	if len(afterSiblings) > 0 {
-		parts = append(parts, "# Before: "+strings.Join(afterSiblings, ", "))
+	if beforeSiblings := siblingLabels(ctx.Siblings, "before"); len(beforeSiblings) > 0 {
+		parts = append(parts, prefix+" After: "+strings.Join(beforeSiblings, siblingSep))
+	}
+	if afterSiblings := siblingLabels(ctx.Siblings, "after"); len(afterSiblings) > 0 {
+		parts = append(parts, prefix+" Before: "+strings.Join(afterSiblings, siblingSep))
 	}
 
 	if len(parts) > 0 {
@@ -566,9 +1022,9 @@ func FormatChunkWithContext(text string, ctx ChunkContext, overlapText string) s
 	}
 
 	if overlapText != "" {
-		parts = append(parts, "# ...")
+		parts = append(parts, prefix+" ...")
 		parts = append(parts, overlapText)
-		parts = append(parts, "# ---")
+		parts = append(parts, prefix+" ---")
 	}
 
 	parts = append(parts, text)
@@ -584,22 +1040,86 @@ func getLastPathSegments(path string, n int) string {
 	return strings.Join(parts[len(parts)-n:], "/")
 }
 
-// buildChunkContext builds chunk context from scope tree
-func buildChunkContext(text *rebuiltText, scopeTree *ScopeTree, opts ChunkOptions, filepath string, lang Language) ChunkContext {
+// getOverlapText returns the overlap carried from the previous chunk according to
+// opts.OverlapMode
+func getOverlapText(prev *rebuiltText, scopeTree *ScopeTree, opts ChunkOptions) string {
+	if prev == nil || prev.text == "" {
+		return ""
+	}
+
+	switch opts.OverlapMode {
+	case OverlapModeNone:
+		return ""
+
+	case OverlapModeEntitySignature:
+		entity := lastEntityInRange(prev.byteRange, scopeTree)
+		if entity == nil {
+			return ""
+		}
+		return entity.Signature
+
+	default:
+		if opts.OverlapLines <= 0 {
+			return ""
+		}
+		prevLines := strings.Split(prev.text, "\n")
+		overlapLineCount := opts.OverlapLines
+		if overlapLineCount > len(prevLines) {
+			overlapLineCount = len(prevLines)
+		}
+		return strings.Join(prevLines[len(prevLines)-overlapLineCount:], "\n")
+	}
+}
+
+// lastEntityInRange returns the entity starting last among those overlapping the range,
+// preferring the innermost entity when several start at the same offset
+func lastEntityInRange(byteRange ByteRange, scopeTree *ScopeTree) *ExtractedEntity {
+	var last *ExtractedEntity
+	for _, i := range scopeTree.entityIndex.overlapping(byteRange) {
+		entity := scopeTree.AllEntities[i]
+		if entity.Type == EntityTypeImport || entity.Type == EntityTypeExport {
+			continue
+		}
+		if last == nil || entity.ByteRange.Start > last.ByteRange.Start ||
+			(entity.ByteRange.Start == last.ByteRange.Start && entity.ByteRange.End < last.ByteRange.End) {
+			last = entity
+		}
+	}
+	return last
+}
+
+// buildChunkContext builds chunk context from scope tree.
+// ContextModeMinimal keeps only the filepath and scope chain; ContextModeFull adds
+// entities, siblings, imports and references.
+func buildChunkContext(text *rebuiltText, scopeTree *ScopeTree, calls []CallSite, uses []ImportUse, opts ChunkOptions, filepath string, lang Language) ChunkContext {
 	byteRange := text.byteRange
 
+	if opts.ContextMode == ContextModeMinimal {
+		return ChunkContext{
+			Filepath:   filepath,
+			Language:   lang,
+			Scope:      withModuleScope(getScopeForRange(byteRange, scopeTree), scopeTree, lang),
+			Entities:   []ChunkEntityInfo{},
+			Siblings:   []SiblingInfo{},
+			Imports:    []ImportInfo{},
+			References: []ReferenceInfo{},
+		}
+	}
+
 	entities := getEntitiesInRange(byteRange, scopeTree)
-	scopeChain := getScopeForRange(byteRange, scopeTree)
-	siblings := getSiblings(byteRange, scopeTree, opts.SiblingDetail, 3)
-	imports := getRelevantImports(entities, scopeTree, opts.FilterImports)
+	scopeChain := withModuleScope(getScopeForRange(byteRange, scopeTree), scopeTree, lang)
+	siblings := getSiblings(byteRange, scopeTree, opts.SiblingDetail, opts.MaxSiblings, opts.MaxSiblingSignatureLen)
+	imports := getRelevantImports(byteRange, uses, scopeTree, filepath, opts.FilterImports)
+	references := getReferences(byteRange, calls, scopeTree)
 
 	return ChunkContext{
-		Filepath: filepath,
-		Language: lang,
-		Scope:    scopeChain,
-		Entities: entities,
-		Siblings: siblings,
-		Imports:  imports,
+		Filepath:   filepath,
+		Language:   lang,
+		Scope:      scopeChain,
+		Entities:   entities,
+		Siblings:   siblings,
+		Imports:    imports,
+		References: references,
 	}
 }
 
@@ -615,6 +1135,9 @@ func getScopeForRange(byteRange ByteRange, scopeTree *ScopeTree) []EntityInfo {
 		Type:      scopeNode.Entity.Type,
 		Signature: scopeNode.Entity.Signature,
 	})
+	if receiver, ok := receiverScope(scopeNode, scopeTree); ok {
+		scopeChain = append(scopeChain, receiver)
+	}
 
 	ancestors := getAncestorChain(scopeNode)
 	for _, ancestor := range ancestors {
@@ -623,97 +1146,236 @@ func getScopeForRange(byteRange ByteRange, scopeTree *ScopeTree) []EntityInfo {
 			Type:      ancestor.Entity.Type,
 			Signature: ancestor.Entity.Signature,
 		})
+		if receiver, ok := receiverScope(ancestor, scopeTree); ok {
+			scopeChain = append(scopeChain, receiver)
+		}
 	}
 
 	return scopeChain
 }
 
+// receiverScope returns the type a top-level method belongs to, as Go methods are
+// declared outside their receiver type, so it appears in the scope chain as the
+// class of a method does in other languages. The type's own entity is used when
+// it is declared in the same file.
+func receiverScope(node *ScopeNode, scopeTree *ScopeTree) (EntityInfo, bool) {
+	entity := node.Entity
+	if node.Parent != nil || entity.Type != EntityTypeMethod || entity.Parent == nil {
+		return EntityInfo{}, false
+	}
+	if root, ok := scopeTree.receiverTypes[*entity.Parent]; ok {
+		return EntityInfo{
+			Name:      root.Entity.Name,
+			Type:      root.Entity.Type,
+			Signature: root.Entity.Signature,
+		}, true
+	}
+	return EntityInfo{Name: *entity.Parent, Type: EntityTypeType}, true
+}
+
 func getEntitiesInRange(byteRange ByteRange, scopeTree *ScopeTree) []ChunkEntityInfo {
 	entities := make([]ChunkEntityInfo, 0)
 
-	for _, entity := range scopeTree.AllEntities {
-		if entity.ByteRange.Start < byteRange.End && entity.ByteRange.End > byteRange.Start {
-			isPartial := entity.ByteRange.Start < byteRange.Start || entity.ByteRange.End > byteRange.End
-
-			entityInfo := ChunkEntityInfo{
-				Name:      entity.Name,
-				Type:      entity.Type,
-				Signature: entity.Signature,
-				Docstring: entity.Docstring,
-				LineRange: &entity.LineRange,
-				IsPartial: isPartial,
+	// Declarations of the same interface, which TypeScript merges, are listed once
+	interfaces := make(map[string]int)
+
+	for _, i := range scopeTree.entityIndex.overlapping(byteRange) {
+		entity := scopeTree.AllEntities[i]
+		if entity.Type == EntityTypeInterface {
+			qualifiedName := scopeTree.qualifiedNames[entity]
+			if j, ok := interfaces[qualifiedName]; ok {
+				merged := &entities[j]
+				if entity.Signature != merged.Signature && !slices.Contains(merged.Overloads, entity.Signature) {
+					merged.Overloads = append(slices.Clone(merged.Overloads), entity.Signature)
+				}
+				continue
 			}
-			entities = append(entities, entityInfo)
+			interfaces[qualifiedName] = len(entities)
+		}
+		isPartial := entity.ByteRange.Start < byteRange.Start || entity.ByteRange.End > byteRange.End
+
+		entityInfo := ChunkEntityInfo{
+			Name:           entity.Name,
+			Type:           entity.Type,
+			Signature:      entity.Signature,
+			Docstring:      entity.Docstring,
+			LineRange:      &entity.LineRange,
+			Span:           &entity.Span,
+			IsPartial:      isPartial,
+			Visibility:     entity.Visibility,
+			Annotations:    entity.Annotations,
+			Modifiers:      entity.Modifiers,
+			Parameters:     entity.Parameters,
+			ReturnType:     entity.ReturnType,
+			TypeParameters: entity.TypeParameters,
+			QualifiedName:  scopeTree.qualifiedNames[entity],
+			PropsType:      entity.PropsType,
+			Overloads:      entity.Overloads,
 		}
+		entities = append(entities, entityInfo)
 	}
 
 	return entities
 }
 
-func getSiblings(byteRange ByteRange, scopeTree *ScopeTree, detail SiblingDetail, maxSiblings int) []SiblingInfo {
-	if detail == SiblingDetailNone {
+func getSiblings(byteRange ByteRange, scopeTree *ScopeTree, detail SiblingDetail, maxSiblings int, maxSignatureLen int) []SiblingInfo {
+	if detail == SiblingDetailNone || maxSiblings <= 0 {
 		return []SiblingInfo{}
 	}
 
-	siblings := make([]SiblingInfo, 0)
-	beforeCount := 0
-	afterCount := 0
+	before := make([]*ExtractedEntity, 0, maxSiblings)
+	after := make([]*ExtractedEntity, 0, maxSiblings)
 
-	for _, entity := range scopeTree.AllEntities {
-		if entity.Type == EntityTypeImport || entity.Type == EntityTypeExport {
-			continue
+	// Walk outward from the scope enclosing the chunk, so entities sharing the chunk's
+	// parent (e.g. methods of the same class) are preferred over those further out
+	level := findEnclosingScope(byteRange, scopeTree)
+	for {
+		nodes := scopeTree.Root
+		if level != nil {
+			nodes = level.Children
 		}
 
-		if entity.ByteRange.End <= byteRange.Start && beforeCount < maxSiblings {
-			siblings = append(siblings, SiblingInfo{
-				Name:     entity.Name,
-				Type:     entity.Type,
-				Position: "before",
-				Distance: beforeCount + 1,
-			})
-			beforeCount++
+		// Nodes are in file order, so scan backwards from the last node starting at or
+		// before the chunk for the nearest preceding entities, and forwards from the
+		// first node starting after it for the following ones
+		last := sort.Search(len(nodes), func(i int) bool {
+			return nodes[i].Entity.ByteRange.Start > byteRange.Start
+		}) - 1
+		for i := last; i >= 0 && len(before) < maxSiblings; i-- {
+			if nodes[i].Entity.ByteRange.End <= byteRange.Start {
+				before = append(before, nodes[i].Entity)
+			}
+		}
+		first := sort.Search(len(nodes), func(i int) bool {
+			return nodes[i].Entity.ByteRange.Start >= byteRange.End
+		})
+		for i := first; i < len(nodes) && len(after) < maxSiblings; i++ {
+			after = append(after, nodes[i].Entity)
 		}
 
-		if entity.ByteRange.Start >= byteRange.End && afterCount < maxSiblings {
-			siblings = append(siblings, SiblingInfo{
-				Name:     entity.Name,
-				Type:     entity.Type,
-				Position: "after",
-				Distance: afterCount + 1,
-			})
-			afterCount++
+		if level == nil || (len(before) >= maxSiblings && len(after) >= maxSiblings) {
+			break
 		}
+		level = level.Parent
 	}
 
+	siblings := make([]SiblingInfo, 0, len(before)+len(after))
+	siblings = appendSiblings(siblings, before, "before", detail, maxSignatureLen)
+	siblings = appendSiblings(siblings, after, "after", detail, maxSignatureLen)
 	return siblings
 }
 
-func getRelevantImports(entities []ChunkEntityInfo, scopeTree *ScopeTree, filterImports bool) []ImportInfo {
+// findEnclosingScope finds the deepest scope node that strictly encloses the byte range,
+// or nil if the range is at the top level of the file
+func findEnclosingScope(byteRange ByteRange, scopeTree *ScopeTree) *ScopeNode {
+	var enclosing *ScopeNode
+	nodes, ends := scopeTree.Root, scopeTree.rootEnds
+	for {
+		// Nodes before the first one reaching the end of the range cannot contain it
+		var next *ScopeNode
+		for i := sort.SearchInts(ends, byteRange.End); i < len(nodes) && nodes[i].Entity.ByteRange.Start <= byteRange.Start; i++ {
+			node := nodes[i]
+			if rangeContains(node.Entity.ByteRange, byteRange) && !rangeContains(byteRange, node.Entity.ByteRange) {
+				next = node
+				break
+			}
+		}
+		if next == nil {
+			return enclosing
+		}
+		enclosing = next
+		nodes, ends = next.Children, next.childEnds
+	}
+}
+
+// appendSiblings converts entities ordered by proximity into SiblingInfo values
+func appendSiblings(siblings []SiblingInfo, entities []*ExtractedEntity, position string, detail SiblingDetail, maxSignatureLen int) []SiblingInfo {
+	for i, entity := range entities {
+		sibling := SiblingInfo{
+			Name:     entity.Name,
+			Type:     entity.Type,
+			Position: position,
+			Distance: i + 1,
+		}
+		if detail == SiblingDetailSignatures {
+			sibling.Signature = truncateSignature(entity.Signature, maxSignatureLen)
+		}
+		siblings = append(siblings, sibling)
+	}
+	return siblings
+}
+
+// getImportBlock returns the raw source of the file's import statements, one per line,
+// skipping statements already contained in the chunk
+func getImportBlock(byteRange ByteRange, scopeTree *ScopeTree, code []byte) string {
+	statements := make([]ByteRange, 0)
+	seen := make(map[ByteRange]bool)
+	for _, imp := range scopeTree.Imports {
+		if seen[imp.ByteRange] || rangeContains(byteRange, imp.ByteRange) {
+			continue
+		}
+		seen[imp.ByteRange] = true
+		statements = append(statements, imp.ByteRange)
+	}
+
+	sort.Slice(statements, func(i, j int) bool {
+		return statements[i].Start < statements[j].Start
+	})
+
+	lines := make([]string, 0, len(statements))
+	end := -1
+	for _, stmt := range statements {
+		// Skip symbol ranges nested in an already emitted statement
+		if stmt.End <= end || stmt.Start < 0 || stmt.End > len(code) {
+			continue
+		}
+		lines = append(lines, string(code[stmt.Start:stmt.End]))
+		end = stmt.End
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// getRelevantImports lists the imports of a file. With filterImports, only imports
+// whose name occurs as an identifier within the byte range are kept, along with
+// wildcard imports and others that cannot be matched to identifiers. Relative
+// Python imports are resolved against the module of filepath.
+func getRelevantImports(byteRange ByteRange, uses []ImportUse, scopeTree *ScopeTree, filepath string, filterImports bool) []ImportInfo {
 	imports := make([]ImportInfo, 0)
 
+	var used map[string]bool
+	if filterImports {
+		used = make(map[string]bool)
+		for _, use := range uses {
+			if use.Offset >= byteRange.Start && use.Offset < byteRange.End {
+				used[use.Name] = true
+			}
+		}
+	}
+
 	for _, imp := range scopeTree.Imports {
 		source := ""
 		if imp.Source != nil {
 			source = *imp.Source
 		}
 
-		if !filterImports {
-			imports = append(imports, ImportInfo{
-				Name:   imp.Name,
-				Source: source,
-			})
-			continue
-		}
-
-		for _, entity := range entities {
-			if entity.Name == imp.Name || strings.Contains(entity.Signature, imp.Name) {
-				imports = append(imports, ImportInfo{
-					Name:   imp.Name,
-					Source: source,
-				})
-				break
+		if filterImports {
+			if key := importKey(imp.Name); key != "" && !used[key] {
+				continue
 			}
 		}
+		info := ImportInfo{
+			Name:         imp.Name,
+			Source:       source,
+			OriginalName: imp.OriginalName,
+			IsDefault:    imp.IsDefault,
+			IsNamespace:  imp.IsNamespace,
+			Level:        imp.Level,
+		}
+		if imp.Level > 0 {
+			info.ResolvedSource = resolvePythonImport(source, scopeTree.namespace, isPythonPackageFile(filepath))
+		}
+		imports = append(imports, info)
 	}
 
 	return imports
@@ -722,6 +1384,9 @@ func getRelevantImports(entities []ChunkEntityInfo, scopeTree *ScopeTree, filter
 // Chunker is a reusable chunker instance with default options.
 type Chunker struct {
 	options ChunkOptions
+
+	poolMu sync.RWMutex
+	pool   *workerPool
 }
 
 // NewChunker creates a new Chunker with the given default options.
@@ -734,27 +1399,12 @@ func NewChunker(opts *ChunkOptions) *Chunker {
 }
 
 // Chunk chunks source code using this chunker's default options.
+// Non-zero fields of opts override the defaults; if opts.DefaultsApplied is set,
+// opts replaces them entirely so that zero values are honored.
 func (c *Chunker) Chunk(filepath string, code string, opts *ChunkOptions) ([]CodeChunk, error) {
 	options := c.options
 	if opts != nil {
-		if opts.MaxChunkSize > 0 {
-			options.MaxChunkSize = opts.MaxChunkSize
-		}
-		if opts.ContextMode != "" {
-			options.ContextMode = opts.ContextMode
-		}
-		if opts.SiblingDetail != "" {
-			options.SiblingDetail = opts.SiblingDetail
-		}
-		if opts.Language != "" {
-			options.Language = opts.Language
-		}
-		if opts.OverlapLines > 0 {
-			options.OverlapLines = opts.OverlapLines
-		}
-		if opts.FilterImports {
-			options.FilterImports = opts.FilterImports
-		}
+		options = opts.merge(c.options)
 	}
 	return Chunk(filepath, code, &options)
 }
@@ -2,6 +2,9 @@ package codechunk
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -58,9 +61,13 @@ func TestChunkBytes(t *testing.T) {
 func TestChunkUnsupportedLanguage(t *testing.T) {
 	code := `body { color: red; }`
 	_, err := Chunk("style.css", code, nil)
-	if err != ErrUnsupportedLanguage {
+	if !errors.Is(err, ErrUnsupportedLanguage) {
 		t.Errorf("Expected ErrUnsupportedLanguage, got: %v", err)
 	}
+	var chunkErr *ChunkError
+	if !errors.As(err, &chunkErr) || chunkErr.Stage != StageDetect || chunkErr.Filepath != "style.css" {
+		t.Errorf("Expected a detect ChunkError for style.css, got: %#v", err)
+	}
 }
 
 func TestChunkWithOptions(t *testing.T) {
@@ -110,6 +117,59 @@ func TestChunkContextModeNone(t *testing.T) {
 	}
 }
 
+func TestChunkContextModeMinimal(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+type Greeter struct{}
+
+func (g *Greeter) Hello(name string) {
+	fmt.Println("Hello, " + name)
+}
+
+func (g *Greeter) Bye(name string) {
+	fmt.Println("Bye, " + name)
+}
+`
+	full, err := Chunk("main.go", code, &ChunkOptions{MaxChunkSize: 50, ContextMode: ContextModeFull})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	minimal, err := Chunk("main.go", code, &ChunkOptions{MaxChunkSize: 50, ContextMode: ContextModeMinimal})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(full) != len(minimal) {
+		t.Fatalf("Expected same chunk count, got %d full and %d minimal", len(full), len(minimal))
+	}
+
+	var fullHasDetail bool
+	for i, chunk := range minimal {
+		ctx := chunk.Context
+		if ctx.Filepath != "main.go" {
+			t.Errorf("Chunk %d: expected filepath in minimal context, got %q", i, ctx.Filepath)
+		}
+		if len(ctx.Scope) != len(full[i].Context.Scope) {
+			t.Errorf("Chunk %d: expected minimal scope to match full scope", i)
+		}
+		if len(ctx.Entities) != 0 || len(ctx.Siblings) != 0 || len(ctx.Imports) != 0 || len(ctx.References) != 0 {
+			t.Errorf("Chunk %d: expected minimal context to omit entities, siblings, imports and references, got %+v", i, ctx)
+		}
+		if strings.Contains(chunk.ContextualizedText, "// Uses:") {
+			t.Errorf("Chunk %d: expected no Uses line in minimal header", i)
+		}
+
+		fullCtx := full[i].Context
+		if len(fullCtx.Entities) > 0 && len(fullCtx.Imports) > 0 {
+			fullHasDetail = true
+		}
+	}
+	if !fullHasDetail {
+		t.Error("Expected full context to include entities and imports")
+	}
+}
+
 func TestChunkTypeScript(t *testing.T) {
 	code := `
 interface User {
@@ -267,11 +327,168 @@ func helper() {}
 
 func TestChunkStreamUnsupported(t *testing.T) {
 	_, err := ChunkStream("file.txt", "hello", nil)
-	if err != ErrUnsupportedLanguage {
+	if !errors.Is(err, ErrUnsupportedLanguage) {
 		t.Errorf("Expected ErrUnsupportedLanguage, got: %v", err)
 	}
 }
 
+func TestChunkParseErrorOnBrokenChunksOnly(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("package main\n\n")
+	for i := 0; i < 6; i++ {
+		fmt.Fprintf(&code, "func f%d() int {\n\treturn %d\n}\n\n", i, i)
+	}
+	code.WriteString("func broken() {\n\tx := \n}\n")
+
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 40
+	chunks, err := Chunk("broken.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected several chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		broken := strings.Contains(chunk.Text, "func broken")
+		if parseErr := chunk.Context.ParseError; broken != (parseErr != nil) {
+			t.Errorf("Chunk %d (%q): unexpected parse error %+v", chunk.Index, chunk.Text, parseErr)
+		} else if broken && (parseErr.Count == 0 || parseErr.Count != len(parseErr.Ranges)) {
+			t.Errorf("Expected counted error ranges, got %+v", parseErr)
+		}
+	}
+
+	var metrics FileMetrics
+	ChunkBatch([]FileInput{{Filepath: "broken.go", Code: code.String()}}, &BatchOptions{
+		Metrics: MetricsFunc(func(m FileMetrics) { metrics = m }),
+	})
+	if metrics.ErrorNodeCount == 0 {
+		t.Error("Expected the metrics to count the error nodes")
+	}
+}
+
+func TestChunkWithContext(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("package main\n\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&code, "func f%d() int {\n\treturn %d\n}\n\n", i, i)
+	}
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 50
+
+	want, err := Chunk("main.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	got, err := ChunkWithContext(context.Background(), "main.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("ChunkWithContext failed: %v", err)
+	}
+	if len(got) != len(want) {
+		t.Errorf("Expected %d chunks, got %d", len(want), len(got))
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ChunkWithContext(ctx, "main.go", code.String(), &opts); !errors.Is(err, ErrCancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected ErrCancelled and context.Canceled, got %v", err)
+	}
+	if _, err := ChunkStreamWithContext(ctx, "main.go", code.String(), &opts); !errors.Is(err, ErrCancelled) {
+		t.Errorf("Expected ChunkStreamWithContext to fail with ErrCancelled, got %v", err)
+	}
+
+	// Window assignment stops once the context is done
+	parsed, err := parseAndExtract([]byte(code.String()), LanguageGo, nil)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	defer parsed.Close()
+	children := getNodeChildren(parsed.Tree.RootNode())
+	cumsum := preprocessNwsCumsum([]byte(code.String()))
+	windows := greedyAssignWindows(children, []byte(code.String()), cumsum, 50, windowOptions{lang: LanguageGo, ctx: ctx}, discardLogger)
+	if len(windows) != 0 {
+		t.Errorf("Expected no windows once cancelled, got %d", len(windows))
+	}
+
+	// The stream is closed early once the context is done
+	ctx, cancel = context.WithCancel(context.Background())
+	ch, err := ChunkStreamWithContext(ctx, "main.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("ChunkStreamWithContext failed: %v", err)
+	}
+	<-ch
+	cancel()
+	received := 1
+	for range ch {
+		received++
+	}
+	if received >= len(want) {
+		t.Errorf("Expected the stream to stop before all %d chunks, got %d", len(want), received)
+	}
+}
+
+func TestChunkStreamWithContextBuiltPaths(t *testing.T) {
+	var code, markdown strings.Builder
+	code.WriteString("package main\n\n")
+	for i := 0; i < 50; i++ {
+		fmt.Fprintf(&code, "func f%d() int {\n\treturn %d\n}\n\n", i, i)
+		fmt.Fprintf(&markdown, "## Step %d\n\n```go\nfunc f%d() int {\n\treturn %d\n}\n```\n\n", i, i, i)
+	}
+
+	tests := []struct {
+		name     string
+		filepath string
+		code     string
+		opts     func(*ChunkOptions)
+	}{
+		{"hierarchical", "main.go", code.String(), func(o *ChunkOptions) { o.Hierarchical = true }},
+		{"limits", "main.go", code.String(), func(o *ChunkOptions) { o.MaxChunksPerFile = 1000 }},
+		{"sliding window", "main.go", code.String(), func(o *ChunkOptions) { o.ChunkStrategy = ChunkStrategySlidingWindow }},
+		{"frontend", "guide.md", markdown.String(), func(*ChunkOptions) {}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultChunkOptions()
+			opts.MaxChunkSize = 50
+			tt.opts(&opts)
+			want, err := Chunk(tt.filepath, tt.code, &opts)
+			if err != nil {
+				t.Fatalf("Chunk failed: %v", err)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			ch, err := ChunkStreamWithContext(ctx, tt.filepath, tt.code, &opts)
+			if err != nil {
+				t.Fatalf("ChunkStreamWithContext failed: %v", err)
+			}
+			<-ch
+			cancel()
+			received := 1
+			for range ch {
+				received++
+			}
+			if received >= len(want) {
+				t.Errorf("Expected the stream to stop before all %d chunks, got %d", len(want), received)
+			}
+		})
+	}
+}
+
+func TestStreamChunksStopsWhenDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := streamChunks(ctx, make([]CodeChunk, 100))
+	cancel()
+
+	received := 0
+	for range ch {
+		received++
+	}
+	if received == 100 {
+		t.Errorf("Expected the stream to stop once cancelled, got %d chunks", received)
+	}
+}
+
 func TestChunkBatch(t *testing.T) {
 	files := []FileInput{
 		{Filepath: "main.go", Code: `package main; func main() {}`},
@@ -384,6 +601,69 @@ func TestChunkBatchWithCancelledContext(t *testing.T) {
 	_ = results
 }
 
+func TestChunkFileInputStopsWithBatchContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts := BatchOptions{}
+	opts.ctx = ctx
+
+	// Per-file options replacing the batch options keep the batch's context
+	fileOpts := DefaultChunkOptions()
+	file := FileInput{Filepath: "main.go", Code: "package main\n\nfunc main() {}\n", Options: &fileOpts}
+	result := chunkFileInput(file, opts.ChunkOptions, nil, nil)
+	if !errors.Is(result.Error, ErrCancelled) || !result.Skipped {
+		t.Errorf("Expected a skipped result matching ErrCancelled, got %+v", result)
+	}
+}
+
+func TestChunkBatchCancelledFilesAreSkipped(t *testing.T) {
+	files := make([]FileInput, 20)
+	for i := range files {
+		files[i] = FileInput{
+			Filepath: "file" + string(rune('a'+i)) + ".go",
+			Code:     `package main; func main() {}`,
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Progress is reported asynchronously, so cancel from the worker while it
+	// chunks the third file
+	opts := &BatchOptions{Concurrency: 1}
+	opts.FormatFunc = func(text string, chunkCtx ChunkContext, overlapText string) string {
+		if chunkCtx.Filepath == files[2].Filepath {
+			cancel()
+		}
+		return text
+	}
+	results := ChunkBatchWithContext(ctx, files, opts)
+
+	var processed, skipped int
+	for i, result := range results {
+		if result.Filepath != files[i].Filepath {
+			t.Errorf("Result %d: expected filepath %q, got %q", i, files[i].Filepath, result.Filepath)
+		}
+		if result.Skipped {
+			skipped++
+			if !errors.Is(result.Error, ErrCancelled) || !errors.Is(result.Error, context.Canceled) {
+				t.Errorf("Result %d: expected cancelled error, got %v", i, result.Error)
+			}
+			if result.Chunks != nil {
+				t.Errorf("Result %d: expected no chunks for skipped file", i)
+			}
+			continue
+		}
+		processed++
+		if result.Error != nil || len(result.Chunks) == 0 {
+			t.Errorf("Result %d: expected processed file to have chunks, got %+v", i, result)
+		}
+	}
+	if processed < 2 || skipped == 0 {
+		t.Errorf("Expected processed and skipped files, got %d processed and %d skipped", processed, skipped)
+	}
+}
+
 func TestChunkBatchStream(t *testing.T) {
 	files := []FileInput{
 		{Filepath: "main.go", Code: `package main; func main() {}`},
@@ -568,6 +848,21 @@ func TestChunkerWithOverride(t *testing.T) {
 	}
 }
 
+func TestChunkOptionsMerge(t *testing.T) {
+	base := ChunkOptions{MaxChunkSize: 1000, ContextMode: ContextModeMinimal, FilterImports: true, MinChunkSize: 50}
+
+	merged := (&ChunkOptions{MaxChunkSize: 500, SplitClasses: true}).merge(base)
+	want := ChunkOptions{MaxChunkSize: 500, ContextMode: ContextModeMinimal, FilterImports: true, MinChunkSize: 50, SplitClasses: true}
+	if !reflect.DeepEqual(merged, want) {
+		t.Errorf("Expected set fields to override the base, got %+v", merged)
+	}
+
+	explicit := ChunkOptions{MaxChunkSize: 500, DefaultsApplied: true}
+	if merged := explicit.merge(base); !reflect.DeepEqual(merged, explicit) {
+		t.Errorf("Expected options with DefaultsApplied to replace the base, got %+v", merged)
+	}
+}
+
 func TestChunkerNilOptions(t *testing.T) {
 	chunker := NewChunker(nil)
 
@@ -661,3 +956,595 @@ func TestFileInputWithOptions(t *testing.T) {
 		t.Errorf("Unexpected error: %v", results[0].Error)
 	}
 }
+
+func TestChunkIncludeImportsInText(t *testing.T) {
+	code := `import os
+from typing import List, Optional
+
+def first():
+    return os.getcwd()
+
+def second(items: List[str]) -> Optional[str]:
+    return items[0] if items else None
+`
+	chunks, err := Chunk("util.py", code, &ChunkOptions{
+		MaxChunkSize:         40,
+		OverlapLines:         -1,
+		IncludeImportsInText: true,
+	})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+
+	importBlock := "import os\nfrom typing import List, Optional\n\n"
+	last := chunks[len(chunks)-1]
+	if !strings.HasPrefix(last.ContextualizedText, importBlock) {
+		t.Errorf("Expected last chunk to start with import block, got:\n%s", last.ContextualizedText)
+	}
+	if strings.Contains(last.Text, "import") {
+		t.Errorf("Expected import block only in ContextualizedText, got Text:\n%s", last.Text)
+	}
+
+	// The first chunk already contains the import statements
+	if strings.Count(chunks[0].ContextualizedText, "import os") != 1 {
+		t.Errorf("Expected imports not to be duplicated in first chunk, got:\n%s", chunks[0].ContextualizedText)
+	}
+}
+
+func TestChunkStreamIncludeImportsInText(t *testing.T) {
+	code := "package main\n\nimport \"fmt\"\n\nfunc Greet(name string) {\n\tfmt.Println(\"Hello, \" + name)\n}\n\nfunc Farewell(name string) {\n\tfmt.Println(\"Goodbye, \" + name)\n}\n"
+	for _, lazy := range []bool{false, true} {
+		opts := &ChunkOptions{MaxChunkSize: 40, OverlapLines: -1, IncludeImportsInText: true, LazyText: lazy}
+		want, err := Chunk("main.go", code, opts)
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		ch, err := ChunkStream("main.go", code, opts)
+		if err != nil {
+			t.Fatalf("ChunkStream failed: %v", err)
+		}
+
+		i := 0
+		for chunk := range ch {
+			if i < len(want) && chunk.Contextualized() != want[i].Contextualized() {
+				t.Errorf("Chunk %d (lazy %v): expected streamed text\n%s\ngot\n%s", i, lazy, want[i].Contextualized(), chunk.Contextualized())
+			}
+			i++
+		}
+		if len(want) < 2 || !strings.HasPrefix(want[len(want)-1].Contextualized(), "import \"fmt\"\n") {
+			t.Fatalf("Expected the last chunk to start with the import block, got %q", want[len(want)-1].Contextualized())
+		}
+	}
+}
+
+func TestGetSiblingsScopeAware(t *testing.T) {
+	code := `def setup():
+    pass
+
+def teardown():
+    pass
+
+class Service:
+    def start(self):
+        pass
+
+    def run(self):
+        pass
+
+    def stop(self):
+        pass
+
+def main():
+    pass
+`
+	parsed, err := parseAndExtract([]byte(code), LanguagePython, nil)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	scopeTree := buildScopeTree(parsed.Entities)
+
+	start := strings.Index(code, "def run")
+	end := start + len("def run(self):\n        pass")
+	byteRange := ByteRange{Start: start, End: end}
+
+	siblings := getSiblings(byteRange, scopeTree, SiblingDetailNames, 2, 0)
+
+	var before, after []string
+	for _, s := range siblings {
+		if s.Position == "before" {
+			before = append(before, s.Name)
+		} else {
+			after = append(after, s.Name)
+		}
+	}
+
+	// Same-class methods come first, then the nearest entities of the enclosing scope
+	if strings.Join(before, ",") != "start,teardown" {
+		t.Errorf("Expected before siblings [start teardown], got %v", before)
+	}
+	if strings.Join(after, ",") != "stop,main" {
+		t.Errorf("Expected after siblings [stop main], got %v", after)
+	}
+	for _, s := range siblings {
+		if s.Name == "start" && s.Distance != 1 {
+			t.Errorf("Expected start to be nearest, got distance %d", s.Distance)
+		}
+	}
+
+	if got := getSiblings(byteRange, scopeTree, SiblingDetailNames, -1, 0); len(got) != 0 {
+		t.Errorf("Expected no siblings for negative max, got %v", got)
+	}
+}
+
+func TestChunkMaxSiblings(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("package main\n\n")
+	for _, name := range []string{"a", "b", "c", "d", "e", "f"} {
+		builder.WriteString("func " + name + "() {\n\tprintln(\"" + name + "\")\n}\n\n")
+	}
+
+	chunks, err := Chunk("main.go", builder.String(), &ChunkOptions{MaxChunkSize: 20, MaxSiblings: 5})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	last := chunks[len(chunks)-1]
+	if len(last.Context.Siblings) != 5 {
+		t.Errorf("Expected 5 siblings, got %d: %+v", len(last.Context.Siblings), last.Context.Siblings)
+	}
+}
+
+func TestChunkOverlapModes(t *testing.T) {
+	code := `package main
+
+func first(a int) int {
+	x := a + 1
+	return x
+}
+
+func second(b int) int {
+	y := b * 2
+	return y
+}
+`
+	for _, tt := range []struct {
+		mode     OverlapMode
+		expected string
+	}{
+		{OverlapModeLines, "\treturn x\n}"},
+		{OverlapModeEntitySignature, "func first(a int) int"},
+		{OverlapModeNone, ""},
+	} {
+		chunks, err := Chunk("main.go", code, &ChunkOptions{
+			MaxChunkSize: 45,
+			OverlapLines: 2,
+			OverlapMode:  tt.mode,
+		})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(chunks) < 2 {
+			t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+		}
+
+		text := chunks[1].ContextualizedText
+		if tt.expected == "" {
+			if strings.Contains(text, "// ...") {
+				t.Errorf("%s: expected no overlap, got:\n%s", tt.mode, text)
+			}
+			continue
+		}
+		if !strings.Contains(text, "// ...\n"+tt.expected+"\n// ---") {
+			t.Errorf("%s: expected overlap %q, got:\n%s", tt.mode, tt.expected, text)
+		}
+	}
+}
+
+func TestChunkHonorsExplicitZeroOptions(t *testing.T) {
+	var builder strings.Builder
+	builder.WriteString("package main\n\n")
+	for _, name := range []string{"a", "b", "c", "d"} {
+		builder.WriteString("func " + name + "() {\n\tprintln(\"" + name + "\")\n}\n\n")
+	}
+	code := builder.String()
+
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 20
+	opts.OverlapLines = 0
+	opts.MaxSiblings = 0
+
+	assertNoOverlap := func(name string, chunks []CodeChunk) {
+		t.Helper()
+		if len(chunks) < 2 {
+			t.Fatalf("%s: expected multiple chunks, got %d", name, len(chunks))
+		}
+		for i, chunk := range chunks {
+			if strings.Contains(chunk.ContextualizedText, "// ...") {
+				t.Errorf("%s: chunk %d has overlap despite OverlapLines 0", name, i)
+			}
+			if len(chunk.Context.Siblings) != 0 {
+				t.Errorf("%s: chunk %d has siblings despite MaxSiblings 0", name, i)
+			}
+		}
+	}
+
+	chunks, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	assertNoOverlap("Chunk", chunks)
+
+	chunker := NewChunker(&ChunkOptions{MaxChunkSize: 500, OverlapLines: 5})
+	chunks, err = chunker.Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunker.Chunk failed: %v", err)
+	}
+	assertNoOverlap("Chunker.Chunk", chunks)
+
+	results := ChunkBatch([]FileInput{{Filepath: "main.go", Code: code, Options: &opts}}, nil)
+	if results[0].Error != nil {
+		t.Fatalf("ChunkBatch failed: %v", results[0].Error)
+	}
+	assertNoOverlap("ChunkBatch", results[0].Chunks)
+
+	// Without DefaultsApplied, zero values still get defaults
+	chunks, err = Chunk("main.go", code, &ChunkOptions{MaxChunkSize: 20})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !strings.Contains(chunks[1].ContextualizedText, "// ...") {
+		t.Error("Expected default overlap when DefaultsApplied is unset")
+	}
+}
+
+func TestChunkSQLStatements(t *testing.T) {
+	code := `CREATE TABLE users (id INT PRIMARY KEY, name TEXT);
+
+CREATE TABLE orders (id INT PRIMARY KEY, user_id INT REFERENCES users (id));
+
+CREATE VIEW user_orders AS SELECT users.name, orders.id FROM users JOIN orders ON orders.user_id = users.id;
+`
+	chunks, err := Chunk("migrations/001_init.sql", code, &ChunkOptions{MaxChunkSize: 100})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected the statements to be split into several chunks, got %d", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if !strings.HasPrefix(chunk.Text, "CREATE ") || !strings.HasSuffix(chunk.Text, ";") {
+			t.Errorf("Expected chunks to start and end at statement boundaries, got %q", chunk.Text)
+		}
+		if !strings.HasPrefix(chunk.ContextualizedText, "-- ") {
+			t.Errorf("Expected SQL comment headers, got %q", chunk.ContextualizedText)
+		}
+	}
+	if DetectLanguage("schema.sql") != LanguageSQL {
+		t.Errorf("Expected .sql to be detected as SQL")
+	}
+}
+
+func TestChunkMinChunkSize(t *testing.T) {
+	code := "package main\n\n" +
+		"func process(items []string) int {\n\ttotal := 0\n\tfor _, item := range items {\n\t\ttotal += len(item)\n\t}\n\treturn total\n}\n\n" +
+		"func id(x int) int { return x }\n"
+
+	opts := NewChunkOptions(WithMaxChunkSize(60))
+	chunks, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	last := chunks[len(chunks)-1]
+	if len(chunks) < 2 || last.Stats.NwsBytes >= 30 {
+		t.Fatalf("Expected a small trailing chunk without MinChunkSize, got %d chunks", len(chunks))
+	}
+
+	opts = NewChunkOptions(WithMaxChunkSize(60), WithMinChunkSize(30))
+	merged, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(merged) != len(chunks)-1 {
+		t.Fatalf("Expected the trailing chunk merged into the previous one, got %d chunks from %d", len(merged), len(chunks))
+	}
+	tail := merged[len(merged)-1]
+	if !strings.Contains(tail.Text, "func id") || tail.LineRange.End != last.LineRange.End {
+		t.Errorf("Expected the last chunk to end with the helper, got %q", tail.Text)
+	}
+	for i, chunk := range merged {
+		if chunk.Index != i || chunk.TotalChunks != len(merged) {
+			t.Errorf("Chunk %d: expected renumbered chunks, got %d of %d", i, chunk.Index, chunk.TotalChunks)
+		}
+	}
+}
+
+func TestChunkBoundaryHeuristics(t *testing.T) {
+	// Line comments are not doc comments in JavaScript, so only the heuristics
+	// keep them with the function
+	code := `function first(items) {
+  let total = 0
+  for (const item of items) {
+    total += item.length
+  }
+  return total
+}
+
+// second doubles a value
+// and returns it
+function second(x) {
+  return x * 2
+}
+`
+	find := func(chunks []CodeChunk, text string) CodeChunk {
+		t.Helper()
+		for _, chunk := range chunks {
+			if strings.Contains(chunk.Text, text) {
+				return chunk
+			}
+		}
+		t.Fatalf("No chunk contains %q", text)
+		return CodeChunk{}
+	}
+
+	opts := NewChunkOptions(WithMaxChunkSize(130))
+	chunks, err := Chunk("main.js", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if strings.Contains(find(chunks, "function second").Text, "// second doubles") {
+		t.Fatalf("Expected the size limit alone to detach the comment, got %q", find(chunks, "function second").Text)
+	}
+
+	opts = NewChunkOptions(WithMaxChunkSize(130), WithBoundaryHeuristics(true))
+	chunks, err = Chunk("main.js", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	second := find(chunks, "function second")
+	if !strings.HasPrefix(second.Text, "// second doubles a value\n// and returns it\nfunction second") {
+		t.Errorf("Expected the comment group to start the function's chunk, got %q", second.Text)
+	}
+	if strings.Contains(find(chunks, "function first").Text, "// second") {
+		t.Errorf("Expected the comment to leave the previous chunk")
+	}
+}
+
+func TestChunkBoundaryHeuristicsOversized(t *testing.T) {
+	var body strings.Builder
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&body, "\tvalue%d := compute(%d)\n", i, i)
+		if i%4 == 3 {
+			body.WriteString("\n")
+		}
+	}
+	code := "package main\n\nvar names = []string{\"alpha\", \"beta\", \"gamma\", \"delta\", \"epsilon\", \"zeta\"}\n\n// run computes values\nfunc run() {\n" + body.String() + "}\n\nconst doc = `\n" +
+		strings.Repeat("a line of documentation text\n", 3) + "\n" +
+		strings.Repeat("another paragraph of the text\n", 3) + "`\n"
+
+	opts := NewChunkOptions(WithMaxChunkSize(100), WithBoundaryHeuristics(true))
+	chunks, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	for i, chunk := range chunks {
+		if got := code[chunk.ByteRange.Start:chunk.ByteRange.End]; got != chunk.Text {
+			t.Errorf("Chunk %d: expected the byte range to locate the text, got %q for %q", i, got, chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "func run") && !strings.Contains(chunk.Text, "// run computes values") {
+			t.Errorf("Expected the doc comment to stay with the oversized function's signature, got %q", chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "value2 :=") && !strings.Contains(chunk.Text, "value0 :=") && !strings.Contains(chunk.Text, "value1 :=") {
+			t.Errorf("Expected statements split at blank lines, got %q", chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "another paragraph") && strings.Contains(chunk.Text, "a line of documentation") {
+			continue
+		}
+		if n := strings.Count(chunk.Text, "another paragraph"); n != 0 && n != 3 {
+			t.Errorf("Expected the raw string split at its blank line, got %q", chunk.Text)
+		}
+	}
+}
+
+func TestChunkKeepsDocCommentsWithEntities(t *testing.T) {
+	tests := []struct {
+		name     string
+		filepath string
+		code     string
+		doc      string
+		entity   string
+	}{
+		{
+			name:     "javascript",
+			filepath: "math.js",
+			code: `function sum(items) {
+  let total = 0
+  for (const item of items) {
+    total += item.length
+  }
+  return total
+}
+
+/**
+ * Doubles a value.
+ */
+function double(x) {
+  return x * 2
+}
+`,
+			doc:    "/**\n * Doubles a value.",
+			entity: "function double",
+		},
+		{
+			name:     "rust attribute",
+			filepath: "lib.rs",
+			code: `fn sum(items: &[String]) -> usize {
+    let mut total = 0;
+    for item in items {
+        total += item.len();
+    }
+    total
+}
+
+/// A point in the plane.
+#[derive(Debug, Clone)]
+struct Point {
+    x: i32,
+    y: i32,
+}
+`,
+			doc:    "/// A point in the plane.",
+			entity: "struct Point",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for size := 50; size <= 200; size += 5 {
+				opts := NewChunkOptions(WithMaxChunkSize(size))
+				chunks, err := Chunk(tt.filepath, tt.code, &opts)
+				if err != nil {
+					t.Fatalf("Chunk failed: %v", err)
+				}
+				for _, chunk := range chunks {
+					if strings.Contains(chunk.Text, tt.doc) && !strings.Contains(chunk.Text, tt.entity) {
+						t.Fatalf("MaxChunkSize %d: doc comment separated from %q in %q", size, tt.entity, chunk.Text)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestChunkAttachesDocCommentToOversizedEntity(t *testing.T) {
+	code := "class Service {\n" +
+		"  name = 'a service with a long name'\n\n" +
+		"  /**\n   * Runs the service.\n   */\n" +
+		"  run() {\n" + strings.Repeat("    this.step()\n", 12) + "  }\n}\n"
+
+	for size := 40; size <= 80; size += 5 {
+		opts := NewChunkOptions(WithMaxChunkSize(size))
+		chunks, err := Chunk("service.js", code, &opts)
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, chunk := range chunks {
+			if strings.Contains(chunk.Text, "Runs the service") && !strings.Contains(chunk.Text, "run()") {
+				t.Fatalf("MaxChunkSize %d: expected the doc comment in the chunk starting the method, got %q", size, chunk.Text)
+			}
+		}
+	}
+}
+
+func TestChunkEntityStrategy(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+// Add returns the sum of a and b.
+func Add(a, b int) int {
+	return a + b
+}
+
+func Sub(a, b int) int {
+	return a - b
+}
+
+type Point struct {
+	X, Y int
+}
+
+var debug = false
+
+func main() {
+	fmt.Println(Add(1, 2), Sub(3, 4))
+}
+`
+	opts := NewChunkOptions(WithChunkStrategy(ChunkStrategyEntity), WithOverlapLines(0))
+	chunks, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var texts []string
+	for _, chunk := range chunks {
+		texts = append(texts, chunk.Text)
+	}
+	expected := []string{
+		"package main\n\nimport \"fmt\"",
+		"// Add returns the sum of a and b.\nfunc Add(a, b int) int {\n\treturn a + b\n}",
+		"func Sub(a, b int) int {\n\treturn a - b\n}",
+		"type Point struct {\n\tX, Y int\n}",
+		"var debug = false",
+		"func main() {\n\tfmt.Println(Add(1, 2), Sub(3, 4))\n}",
+	}
+	if len(texts) != len(expected) {
+		t.Fatalf("expected %d chunks, got %d: %q", len(expected), len(texts), texts)
+	}
+	for i := range expected {
+		if texts[i] != expected[i] {
+			t.Errorf("chunk %d: expected %q, got %q", i, expected[i], texts[i])
+		}
+	}
+}
+
+func TestChunkEntityStrategyNoEmptyChunks(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+// Add returns the sum of a and b.
+func Add(a, b int) int {
+	return a + b
+}
+
+var debug = false
+
+func main() {
+	fmt.Println(Add(1, 2))
+}
+`
+	for _, minSize := range []int{0, 6} {
+		opts := NewChunkOptions(WithChunkStrategy(ChunkStrategyEntity), WithMaxChunkSize(10), WithMinChunkSize(minSize))
+		chunks, err := Chunk("a.go", code, &opts)
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		for _, chunk := range chunks {
+			if strings.TrimSpace(chunk.Text) == "" {
+				t.Errorf("min size %d: expected no empty chunks, got one at bytes %d-%d", minSize, chunk.ByteRange.Start, chunk.ByteRange.End)
+			}
+			if size := countNws(chunk.Text); minSize > 0 && size < minSize {
+				t.Errorf("min size %d: expected undersized chunks merged, got %q", minSize, chunk.Text)
+			}
+		}
+	}
+}
+
+func TestChunkEntityStrategySplitsOversizedEntities(t *testing.T) {
+	code := "export function small() { return 1 }\n\n" +
+		"export class Big {\n" +
+		"  first() {\n" + strings.Repeat("    this.step()\n", 6) + "  }\n" +
+		"  second() {\n" + strings.Repeat("    this.step()\n", 6) + "  }\n" +
+		"}\n\n" +
+		"@decorated\nclass Other {}\n"
+	opts := NewChunkOptions(WithChunkStrategy(ChunkStrategyEntity), WithMaxChunkSize(100))
+	chunks, err := Chunk("big.ts", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	if len(chunks) < 4 || chunks[0].Text != "export function small() { return 1 }" {
+		t.Fatalf("expected small in a chunk of its own, got %d chunks", len(chunks))
+	}
+	for _, chunk := range chunks[1:] {
+		if strings.Contains(chunk.Text, "first()") && strings.Contains(chunk.Text, "second()") {
+			t.Errorf("expected the oversized class split by method, got %q", chunk.Text)
+		}
+		if strings.Contains(chunk.Text, "class Other") && strings.Contains(chunk.Text, "step") {
+			t.Errorf("expected Other in a chunk of its own, got %q", chunk.Text)
+		}
+	}
+}
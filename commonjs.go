@@ -0,0 +1,194 @@
+package codechunk
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// jsRequiredModule returns the module a CommonJS require call loads, 'fs' for
+// require('fs'), or "" if node is not a require call of a string
+func jsRequiredModule(node *sitter.Node, code []byte) string {
+	if node == nil || node.Type() != "call_expression" {
+		return ""
+	}
+	fn := node.ChildByFieldName("function")
+	if fn == nil || fn.Content(code) != "require" {
+		return ""
+	}
+	args := node.ChildByFieldName("arguments")
+	if args == nil || args.NamedChildCount() != 1 || args.NamedChild(0).Type() != "string" {
+		return ""
+	}
+	return stripQuotes(args.NamedChild(0).Content(code))
+}
+
+// jsRequiredValue returns the module whose exports a variable is assigned,
+// require('x') or a property of it as in require('x').y, and the property
+func jsRequiredValue(value *sitter.Node, code []byte) (module, property string) {
+	if value != nil && value.Type() == "member_expression" {
+		if prop := value.ChildByFieldName("property"); prop != nil {
+			return jsRequiredModule(value.ChildByFieldName("object"), code), prop.Content(code)
+		}
+	}
+	return jsRequiredModule(value, code), ""
+}
+
+// isJSRequire reports whether a statement loads a module with require: a variable
+// declaration assigning what a require call returns, or a bare require call
+func isJSRequire(node *sitter.Node, code []byte) bool {
+	switch node.Type() {
+	case "lexical_declaration", "variable_declaration":
+		for _, declarator := range childrenOfType(node, "variable_declarator") {
+			if module, _ := jsRequiredValue(declarator.ChildByFieldName("value"), code); module != "" {
+				return true
+			}
+		}
+	case "expression_statement":
+		return node.NamedChildCount() > 0 && jsRequiredModule(node.NamedChild(0), code) != ""
+	}
+	return false
+}
+
+// extractJSRequireSymbols extracts the names a require statement binds. A variable
+// assigned the whole module is a namespace import, and destructuring binds each
+// property: const { readFile, writeFile: write } = require('fs') imports
+// readFile, and write with the original name writeFile. A bare require, loaded
+// for its side effects, is an import named "import".
+func extractJSRequireSymbols(node *sitter.Node, code []byte) []*ExtractedEntity {
+	entities := make([]*ExtractedEntity, 0)
+	if node.Type() == "expression_statement" {
+		return append(entities, createImportEntity(node, "import", jsRequiredModule(node.NamedChild(0), code), code))
+	}
+
+	for _, declarator := range childrenOfType(node, "variable_declarator") {
+		module, property := jsRequiredValue(declarator.ChildByFieldName("value"), code)
+		nameNode := declarator.ChildByFieldName("name")
+		if module == "" || nameNode == nil {
+			continue
+		}
+
+		switch nameNode.Type() {
+		case "identifier":
+			entity := createImportEntity(node, nameNode.Content(code), module, code)
+			if property == "" {
+				entity.IsNamespace = true
+			} else if property != entity.Name {
+				entity.OriginalName = property
+			}
+			entities = append(entities, entity)
+		case "object_pattern":
+			for i := 0; i < int(nameNode.NamedChildCount()); i++ {
+				switch binding := nameNode.NamedChild(i); binding.Type() {
+				case "shorthand_property_identifier_pattern":
+					entities = append(entities, createImportEntity(node, binding.Content(code), module, code))
+				case "pair_pattern":
+					key, value := binding.ChildByFieldName("key"), binding.ChildByFieldName("value")
+					if key == nil || value == nil || value.Type() != "identifier" {
+						continue
+					}
+					entity := createImportEntity(node, value.Content(code), module, code)
+					if original := stripQuotes(key.Content(code)); original != entity.Name {
+						entity.OriginalName = original
+					}
+					entities = append(entities, entity)
+				}
+			}
+		}
+	}
+	return entities
+}
+
+// commonJSExportTarget returns the assignment of a CommonJS export statement and
+// the name it exports: "" for module.exports = ..., and name for
+// module.exports.name = ... and exports.name = .... Returns nil for other
+// statements.
+func commonJSExportTarget(node *sitter.Node, code []byte) (*sitter.Node, string) {
+	if node.Type() != "expression_statement" || node.NamedChildCount() == 0 {
+		return nil, ""
+	}
+	assignment := node.NamedChild(0)
+	if assignment.Type() != "assignment_expression" {
+		return nil, ""
+	}
+	left := assignment.ChildByFieldName("left")
+	if left == nil || left.Type() != "member_expression" {
+		return nil, ""
+	}
+	if left.Content(code) == "module.exports" {
+		return assignment, ""
+	}
+	object, property := left.ChildByFieldName("object"), left.ChildByFieldName("property")
+	if object == nil || property == nil {
+		return nil, ""
+	}
+	if target := object.Content(code); target == "exports" || target == "module.exports" {
+		return assignment, property.Content(code)
+	}
+	return nil, ""
+}
+
+// extractCommonJSExports returns an export entity per name a CommonJS export
+// statement exports. module.exports = { a, b: ... } exports each property of the
+// object, and module.exports = value is the default export, named after the
+// value when it is an identifier or a named function or class.
+func extractCommonJSExports(node *sitter.Node, code []byte, parent *string) []*ExtractedEntity {
+	assignment, name := commonJSExportTarget(node, code)
+	if assignment == nil {
+		return nil
+	}
+	if name != "" {
+		return []*ExtractedEntity{createExportEntity(node, name, firstLineSignature(node, code), parent)}
+	}
+	right := assignment.ChildByFieldName("right")
+	if right == nil {
+		return nil
+	}
+
+	if right.Type() == "object" {
+		var names []string
+		for i := 0; i < int(right.NamedChildCount()); i++ {
+			property := right.NamedChild(i)
+			switch property.Type() {
+			case "shorthand_property_identifier":
+				names = append(names, property.Content(code))
+			case "pair", "method_definition":
+				field := "key"
+				if property.Type() == "method_definition" {
+					field = "name"
+				}
+				if key := property.ChildByFieldName(field); key != nil {
+					names = append(names, stripQuotes(key.Content(code)))
+				}
+			}
+		}
+		signature := "module.exports = { " + strings.Join(names, ", ") + " }"
+		exports := make([]*ExtractedEntity, len(names))
+		for i, name := range names {
+			exports[i] = createExportEntity(node, name, signature, parent)
+		}
+		return exports
+	}
+
+	export := createExportEntity(node, "", firstLineSignature(node, code), parent)
+	export.IsDefault = true
+	if right.Type() == "identifier" {
+		export.Name = right.Content(code)
+	} else if nameNode := right.ChildByFieldName("name"); nameNode != nil {
+		export.Name = nameNode.Content(code)
+	} else {
+		export.Name = anonymousName
+		export.defaultExport = true
+	}
+	return []*ExtractedEntity{export}
+}
+
+// firstLineSignature returns the first line of a statement as its signature,
+// without a trailing semicolon
+func firstLineSignature(node *sitter.Node, code []byte) string {
+	text := node.Content(code)
+	if i := strings.Index(text, "\n"); i != -1 {
+		text = text[:i]
+	}
+	return cleanSignature(strings.TrimSuffix(strings.TrimSpace(text), ";"))
+}
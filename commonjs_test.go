@@ -0,0 +1,104 @@
+package codechunk
+
+import (
+	"reflect"
+	"testing"
+)
+
+const commonJSSource = `'use strict';
+const fs = require('fs');
+const { readFile, writeFile: write } = require('fs/promises');
+const join = require('path').join;
+require('./polyfills');
+
+function load(file) {
+  return fs.readFileSync(join(__dirname, file));
+}
+
+module.exports = { load, save: function() {}, close() {} };
+exports.version = '1.0';
+`
+
+// importDescriptions describes the imports among entities by name, source and
+// how they import
+func importDescriptions(entities []*ExtractedEntity) []string {
+	var descriptions []string
+	for _, e := range entities {
+		if e.Type != EntityTypeImport {
+			continue
+		}
+		description := e.Name + " from " + *e.Source
+		if e.OriginalName != "" {
+			description += " as " + e.OriginalName
+		}
+		if e.IsNamespace {
+			description += " (namespace)"
+		}
+		descriptions = append(descriptions, description)
+	}
+	return descriptions
+}
+
+func TestCommonJSRequire(t *testing.T) {
+	analysis, err := Analyze("lib/files.js", commonJSSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer analysis.Close()
+
+	expected := []string{
+		"fs from fs (namespace)",
+		"readFile from fs/promises",
+		"write from fs/promises as writeFile",
+		"join from path",
+		"import from ./polyfills",
+	}
+	if got := importDescriptions(analysis.Entities); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected imports %q, got %q", expected, got)
+	}
+}
+
+func TestCommonJSExports(t *testing.T) {
+	analysis, err := Analyze("lib/files.js", commonJSSource+"module.exports = class {};\n", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer analysis.Close()
+
+	var exports, functions []string
+	for _, e := range analysis.Entities {
+		switch e.Type {
+		case EntityTypeExport:
+			exports = append(exports, e.Name+": "+e.Signature)
+		case EntityTypeFunction, EntityTypeMethod, EntityTypeClass:
+			functions = append(functions, e.Name)
+		}
+	}
+
+	expectedExports := []string{
+		"load: module.exports = { load, save, close }",
+		"save: module.exports = { load, save, close }",
+		"close: module.exports = { load, save, close }",
+		"version: exports.version = '1.0'",
+		"files: module.exports = class {}",
+	}
+	if !reflect.DeepEqual(exports, expectedExports) {
+		t.Errorf("Expected exports %q, got %q", expectedExports, exports)
+	}
+	if expected := []string{"load", "save", "close", "files"}; !reflect.DeepEqual(functions, expected) {
+		t.Errorf("Expected the exported values to remain entities %q, got %q", expected, functions)
+	}
+}
+
+func TestCommonJSImportsInContext(t *testing.T) {
+	chunks, err := Chunk("lib/files.js", commonJSSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, imp := range chunks[0].Context.Imports {
+		if imp.Name == "fs" && imp.Source == "fs" {
+			return
+		}
+	}
+	t.Errorf("Expected fs in the imports of the chunk, got %+v", chunks[0].Context.Imports)
+}
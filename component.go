@@ -0,0 +1,140 @@
+package codechunk
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// jsxNodeTypes are the JSX expressions a component renders
+var jsxNodeTypes = map[string]bool{"jsx_element": true, "jsx_self_closing_element": true}
+
+// componentWrappers are the calls wrapping a component that keep it bound to the
+// variable they are assigned to, as in const Button = memo(() => ...)
+var componentWrappers = map[string]bool{
+	"memo": true, "forwardRef": true, "React.memo": true, "React.forwardRef": true,
+}
+
+// isComponentWrapper reports whether call wraps a component, such as React.memo
+func isComponentWrapper(call *sitter.Node, code []byte) bool {
+	if call == nil || call.Type() != "call_expression" {
+		return false
+	}
+	fn := call.ChildByFieldName("function")
+	return fn != nil && componentWrappers[fn.Content(code)]
+}
+
+// isReactComponent reports whether a JavaScript or TypeScript function or class is
+// a React component: named with a capital letter, or the anonymous default export
+// of its module, and returning JSX from its body or its render method
+func isReactComponent(node *sitter.Node, entityType EntityType, name string, defaultExport bool, lang Language, code []byte) bool {
+	if lang != LanguageJavaScript && lang != LanguageTypeScript {
+		return false
+	}
+	if r, _ := utf8.DecodeRuneInString(name); !unicode.IsUpper(r) && !defaultExport {
+		return false
+	}
+
+	switch entityType {
+	case EntityTypeFunction:
+		return returnsJSX(node)
+	case EntityTypeClass:
+		body := node.ChildByFieldName("body")
+		if body == nil {
+			return false
+		}
+		for i := 0; i < int(body.NamedChildCount()); i++ {
+			member := body.NamedChild(i)
+			if nameNode := member.ChildByFieldName("name"); member.Type() == "method_definition" && nameNode != nil && nameNode.Content(code) == "render" {
+				return returnsJSX(member)
+			}
+		}
+	}
+	return false
+}
+
+// returnsJSX reports whether a function returns JSX, from a return statement of
+// its own rather than of a nested function, or as the expression body of an arrow
+// function
+func returnsJSX(fn *sitter.Node) bool {
+	body := fn.ChildByFieldName("body")
+	if body == nil {
+		return false
+	}
+	if body.Type() != "statement_block" {
+		return containsJSX(body)
+	}
+
+	var walk func(node *sitter.Node) bool
+	walk = func(node *sitter.Node) bool {
+		if node.Type() == "return_statement" {
+			return containsJSX(node)
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if _, nested := jsExpressionEntities[child.Type()]; nested || isEntityNodeType(child.Type(), LanguageTypeScript) {
+				continue
+			}
+			if walk(child) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(body)
+}
+
+// containsJSX reports whether node is or contains a JSX element
+func containsJSX(node *sitter.Node) bool {
+	if jsxNodeTypes[node.Type()] {
+		return true
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if containsJSX(node.NamedChild(i)) {
+			return true
+		}
+	}
+	return false
+}
+
+// componentPropsType returns the type of the props of a React component as
+// written: that of its first parameter, the type argument of the type of the
+// variable it is assigned to (React.FC<ButtonProps>), or the first type argument
+// of the class it extends (React.Component<PageProps, State>). Returns "" for
+// untyped props.
+func componentPropsType(node *sitter.Node, params []Param, code []byte) string {
+	if len(params) > 0 && params[0].Type != "" {
+		return params[0].Type
+	}
+
+	var typeArgs *sitter.Node
+	switch node.Type() {
+	case "class_declaration", "abstract_class_declaration", "class":
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if heritage := node.NamedChild(i); heritage.Type() == "class_heritage" {
+				for j := 0; j < int(heritage.NamedChildCount()); j++ {
+					if clause := heritage.NamedChild(j); clause.Type() == "extends_clause" {
+						typeArgs = clause.ChildByFieldName("type_arguments")
+					}
+				}
+			}
+		}
+	default:
+		// A component wrapped in memo(...) is bound by the parent of the call
+		declarator := node.Parent()
+		if declarator != nil && declarator.Type() == "arguments" && isComponentWrapper(declarator.Parent(), code) {
+			declarator = declarator.Parent().Parent()
+		}
+		if declarator == nil || declarator.Type() != "variable_declarator" {
+			return ""
+		}
+		if annotation := declarator.ChildByFieldName("type"); annotation != nil && annotation.NamedChildCount() > 0 {
+			typeArgs = annotation.NamedChild(0).ChildByFieldName("type_arguments")
+		}
+	}
+	if typeArgs == nil || typeArgs.NamedChildCount() == 0 {
+		return ""
+	}
+	return typeArgs.NamedChild(0).Content(code)
+}
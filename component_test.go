@@ -0,0 +1,89 @@
+package codechunk
+
+import (
+	"testing"
+)
+
+const componentSource = `import React, { forwardRef } from 'react';
+
+const Button: React.FC<ButtonProps> = ({ label }) => <button>{label}</button>;
+
+function Card({ title }: CardProps) {
+  return (
+    <>
+      <h1>{title}</h1>
+    </>
+  );
+}
+
+class Page extends React.Component<PageProps, State> {
+  render() {
+    return <div />;
+  }
+}
+
+export const Input = forwardRef((props: InputProps, ref) => <input ref={ref} />);
+
+function useTitle() {
+  return <title />;
+}
+
+function Helper() {
+  const render = () => <b />;
+  return null;
+}
+
+export default function () {
+  return <main />;
+}
+`
+
+func TestReactComponents(t *testing.T) {
+	analysis, err := Analyze("src/App.tsx", componentSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer analysis.Close()
+
+	expected := map[string]struct {
+		entityType EntityType
+		propsType  string
+	}{
+		"Button":   {EntityTypeComponent, "ButtonProps"},
+		"Card":     {EntityTypeComponent, "CardProps"},
+		"Page":     {EntityTypeComponent, "PageProps"},
+		"Input":    {EntityTypeComponent, "InputProps"},
+		"App":      {EntityTypeComponent, ""},
+		"useTitle": {EntityTypeFunction, ""},
+		"Helper":   {EntityTypeFunction, ""},
+	}
+	for _, entity := range analysis.Entities {
+		want, ok := expected[entity.Name]
+		if !ok || entity.Type == EntityTypeExport {
+			continue
+		}
+		delete(expected, entity.Name)
+		if entity.Type != want.entityType || entity.PropsType != want.propsType {
+			t.Errorf("%s: expected %s with props %q, got %s with props %q", entity.Name, want.entityType, want.propsType, entity.Type, entity.PropsType)
+		}
+	}
+	for name := range expected {
+		t.Errorf("Expected an entity named %s", name)
+	}
+}
+
+func TestReactComponentContext(t *testing.T) {
+	chunks, err := Chunk("src/App.tsx", componentSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entity := range chunks[0].Context.Entities {
+		if entity.Name == "Card" {
+			if entity.Type != EntityTypeComponent || entity.PropsType != "CardProps" {
+				t.Errorf("Expected Card to be a component with CardProps, got %+v", entity)
+			}
+			return
+		}
+	}
+	t.Error("Expected Card in the context of the chunk")
+}
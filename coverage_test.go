@@ -618,10 +618,10 @@ func c() {}
 	children := getNodeChildren(parseResult.Tree.RootNode())
 	if len(children) > 0 {
 		cumsum := preprocessNwsCumsum([]byte(code))
-		windows := greedyAssignWindows(children, []byte(code), cumsum, 500)
+		windows := greedyAssignWindows(children, []byte(code), cumsum, 500, windowOptions{lang: LanguageGo}, discardLogger)
 
 		for i, window := range windows {
-			text := rebuildText(window, []byte(code))
+			text := rebuildText(window, []byte(code), newPositionIndex([]byte(code)))
 			t.Logf("Window %d: text length=%d, lines=%d-%d",
 				i, len(text.text), text.lineRange.Start, text.lineRange.End)
 		}
@@ -1195,10 +1195,10 @@ func b() {}
 	cumsum := preprocessNwsCumsum([]byte(code))
 
 	// Create windows with very small size to get multiple windows
-	windows := greedyAssignWindows(children, []byte(code), cumsum, 20)
+	windows := greedyAssignWindows(children, []byte(code), cumsum, 20, windowOptions{lang: LanguageGo}, discardLogger)
 
 	for i, window := range windows {
-		text := rebuildText(window, []byte(code))
+		text := rebuildText(window, []byte(code), newPositionIndex([]byte(code)))
 		t.Logf("Window %d: bytes=%d-%d, lines=%d-%d, partial=%v",
 			i, text.byteRange.Start, text.byteRange.End,
 			text.lineRange.Start, text.lineRange.End,
@@ -1490,7 +1490,7 @@ func TestRebuildTextEmptyWindow(t *testing.T) {
 		Size:      0,
 	}
 
-	result := rebuildText(window, []byte("code"))
+	result := rebuildText(window, []byte("code"), newPositionIndex([]byte("code")))
 	if result.text != "" {
 		t.Errorf("Expected empty text for empty window, got %q", result.text)
 	}
@@ -1525,7 +1525,7 @@ func hello() {
 		},
 	}
 
-	result := rebuildText(window, []byte(code))
+	result := rebuildText(window, []byte(code), newPositionIndex([]byte(code)))
 	t.Logf("Rebuilt text: bytes=%d-%d, lines=%d-%d",
 		result.byteRange.Start, result.byteRange.End,
 		result.lineRange.Start, result.lineRange.End)
@@ -1899,7 +1899,7 @@ func b() {}
 
 func TestMergeAdjacentWindowsEmpty(t *testing.T) {
 	// Test mergeAdjacentWindows with empty input
-	result := mergeAdjacentWindows([]*ASTWindow{}, 100)
+	result := mergeAdjacentWindows([]*ASTWindow{}, 100, discardLogger)
 	if len(result) != 0 {
 		t.Errorf("Expected empty result for empty input, got %d", len(result))
 	}
@@ -2201,10 +2201,10 @@ func c() {}`
 	cumsum := preprocessNwsCumsum([]byte(code))
 
 	// Create windows with a size that allows multiple nodes per window
-	windows := greedyAssignWindows(children, []byte(code), cumsum, 1000)
+	windows := greedyAssignWindows(children, []byte(code), cumsum, 1000, windowOptions{lang: LanguageGo}, discardLogger)
 
 	for i, window := range windows {
-		text := rebuildText(window, []byte(code))
+		text := rebuildText(window, []byte(code), newPositionIndex([]byte(code)))
 		t.Logf("Window %d: %d nodes, text length=%d",
 			i, len(window.Nodes), len(text.text))
 	}
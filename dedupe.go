@@ -0,0 +1,55 @@
+package codechunk
+
+// ChunkOccurrence is a place in a batch where the text of a chunk occurs
+type ChunkOccurrence struct {
+	Filepath  string    `json:"filepath"`  // File the text occurs in
+	ChunkID   string    `json:"chunkId"`   // ID of the chunk at this place, which may have been removed as a duplicate
+	ByteRange ByteRange `json:"byteRange"` // Byte range in the file
+	LineRange LineRange `json:"lineRange"` // Line range in the file
+}
+
+// DedupeChunks collapses chunks with byte-identical text across batch results, such
+// as vendored or copy-pasted code. The first chunk in input order is kept as the
+// canonical chunk and lists every place the text occurs in Occurrences; the other
+// chunks are removed from their results. Every remaining chunk has its ContentHash
+// set, as computed by ContentHash. Index, TotalChunks and the chunk links are left
+// unchanged, so they may refer to removed chunks, whose IDs are listed in the
+// occurrences. results is not modified.
+func DedupeChunks(results []BatchResult) []BatchResult {
+	type location struct{ result, chunk int }
+	canonical := make(map[string]location)
+	occurrences := make(map[string][]ChunkOccurrence)
+
+	deduped := make([]BatchResult, len(results))
+	for i, result := range results {
+		deduped[i] = result
+		if result.Chunks == nil {
+			continue
+		}
+
+		kept := make([]CodeChunk, 0, len(result.Chunks))
+		for _, chunk := range result.Chunks {
+			hash := ContentHash(chunk.Content())
+			occurrences[hash] = append(occurrences[hash], ChunkOccurrence{
+				Filepath:  result.Filepath,
+				ChunkID:   chunk.ID,
+				ByteRange: chunk.ByteRange,
+				LineRange: chunk.LineRange,
+			})
+			if _, ok := canonical[hash]; ok {
+				continue
+			}
+			canonical[hash] = location{result: i, chunk: len(kept)}
+			chunk.ContentHash = hash
+			kept = append(kept, chunk)
+		}
+		deduped[i].Chunks = kept
+	}
+
+	for hash, loc := range canonical {
+		if places := occurrences[hash]; len(places) > 1 {
+			deduped[loc.result].Chunks[loc.chunk].Occurrences = places
+		}
+	}
+	return deduped
+}
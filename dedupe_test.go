@@ -0,0 +1,57 @@
+package codechunk
+
+import "testing"
+
+func TestChunkBatchDedupe(t *testing.T) {
+	vendored := "package util\n\nfunc Clamp(v, lo, hi int) int {\n\tif v < lo {\n\t\treturn lo\n\t}\n\treturn min(v, hi)\n}\n"
+	files := []FileInput{
+		{Filepath: "a/util.go", Code: vendored},
+		{Filepath: "b/other.go", Code: "package other\n\nfunc Other() {}\n"},
+		{Filepath: "vendor/util.go", Code: vendored},
+	}
+
+	results := ChunkBatch(files, &BatchOptions{Dedupe: true, Concurrency: 3})
+	if len(results[0].Chunks) != 1 || len(results[1].Chunks) != 1 {
+		t.Fatalf("expected the first copy and the unique file kept, got %d and %d chunks", len(results[0].Chunks), len(results[1].Chunks))
+	}
+	if results[2].Chunks == nil || len(results[2].Chunks) != 0 {
+		t.Errorf("expected the duplicate removed, got %+v", results[2].Chunks)
+	}
+
+	canonical := results[0].Chunks[0]
+	if canonical.ContentHash != ContentHash(vendored[:len(vendored)-1]) {
+		t.Errorf("unexpected content hash %q", canonical.ContentHash)
+	}
+	if len(canonical.Occurrences) != 2 || canonical.Occurrences[0].Filepath != "a/util.go" || canonical.Occurrences[1].Filepath != "vendor/util.go" {
+		t.Fatalf("expected both places in the occurrences, got %+v", canonical.Occurrences)
+	}
+	if canonical.Occurrences[0].ChunkID != canonical.ID || canonical.Occurrences[1].ChunkID == canonical.ID {
+		t.Errorf("expected the occurrences to carry their chunk IDs, got %+v", canonical.Occurrences)
+	}
+	if unique := results[1].Chunks[0]; unique.ContentHash == "" || unique.Occurrences != nil {
+		t.Errorf("expected a hash and no occurrences on a unique chunk, got %+v", unique)
+	}
+
+	plain := ChunkBatch(files, &BatchOptions{Concurrency: 3})
+	if len(plain[2].Chunks) != 1 || plain[2].Chunks[0].ContentHash != "" {
+		t.Error("expected no deduplication without Dedupe")
+	}
+}
+
+func TestDedupeChunksKeepsInput(t *testing.T) {
+	chunk := CodeChunk{Text: "x := 1", ID: "a"}
+	results := []BatchResult{
+		{Filepath: "a.go", Chunks: []CodeChunk{chunk, chunk}},
+		{Filepath: "b.go", Error: ErrUnsupportedLanguage},
+	}
+	deduped := DedupeChunks(results)
+	if len(deduped[0].Chunks) != 1 || len(deduped[0].Chunks[0].Occurrences) != 2 {
+		t.Errorf("expected duplicates within a file collapsed, got %+v", deduped[0].Chunks)
+	}
+	if deduped[1].Chunks != nil || deduped[1].Error == nil {
+		t.Errorf("expected failed results unchanged, got %+v", deduped[1])
+	}
+	if len(results[0].Chunks) != 2 || results[0].Chunks[0].ContentHash != "" {
+		t.Error("expected the input results unchanged")
+	}
+}
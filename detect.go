@@ -0,0 +1,265 @@
+package codechunk
+
+import (
+	"bytes"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// maxDetectBytes bounds how much of a file content-based detection looks at
+const maxDetectBytes = 16 << 10
+
+// shebangInterpreters maps script interpreters to languages
+var shebangInterpreters = map[string]Language{
+	"python":  LanguagePython,
+	"python2": LanguagePython,
+	"python3": LanguagePython,
+	"node":    LanguageJavaScript,
+	"nodejs":  LanguageJavaScript,
+	"bun":     LanguageJavaScript,
+	"deno":    LanguageTypeScript,
+	"ts-node": LanguageTypeScript,
+	"tsx":     LanguageTypeScript,
+	"lua":     LanguageLua,
+	"luajit":  LanguageLua,
+	"elixir":  LanguageElixir,
+}
+
+var (
+	// vimModelinePattern matches vim modelines such as "vim: set ft=python:"
+	vimModelinePattern = regexp.MustCompile(`\b(?:vi|vim|ex):.*?\b(?:ft|filetype|syntax)=([\w+-]+)`)
+	// emacsModelinePattern matches emacs file variables such as "-*- mode: python -*-"
+	emacsModelinePattern = regexp.MustCompile(`-\*-\s*(.*?)\s*-\*-`)
+)
+
+// contentHint is a pattern that suggests a language when it matches source code,
+// weighted by how distinctive it is
+type contentHint struct {
+	lang    Language
+	weight  int
+	pattern *regexp.Regexp
+}
+
+// minHintScore is the weight of hints a language needs to be detected from them:
+// two ordinary hints, or one distinctive one such as syntax = "proto3";
+const minHintScore = 2
+
+// nonCodeExtensions are extensions of text, data and configuration files, whose
+// content is never run through the heuristics
+var nonCodeExtensions = map[string]bool{
+	".txt": true, ".md": true, ".markdown": true, ".rst": true, ".adoc": true,
+	".log": true, ".csv": true, ".tsv": true, ".json": true, ".jsonl": true,
+	".yaml": true, ".yml": true, ".toml": true, ".ini": true, ".cfg": true,
+	".conf": true, ".env": true, ".properties": true, ".xml": true, ".html": true,
+	".htm": true, ".svg": true, ".lock": true, ".sum": true,
+}
+
+// contentHints are the heuristics of DetectLanguageFromContent. JavaScript hints
+// also count for TypeScript, which wins only with TypeScript-specific matches.
+var contentHints = []contentHint{
+	{LanguageGo, 1, regexp.MustCompile(`(?m)^package \w+\s*$`)},
+	{LanguageGo, 1, regexp.MustCompile(`(?m)^func (\([^)]*\) )?\w+\(`)},
+	{LanguageGo, 1, regexp.MustCompile(`(?m)^import (\($|"[\w./-]+"$)`)},
+
+	{LanguagePython, 1, regexp.MustCompile(`(?m)^\s*def \w+\(.*\)( -> .+)?:\s*$`)},
+	{LanguagePython, 1, regexp.MustCompile(`(?m)^class \w+(\(.*\))?:\s*$`)},
+	{LanguagePython, 1, regexp.MustCompile(`(?m)^from [\w.]+ import `)},
+	{LanguagePython, 1, regexp.MustCompile(`(?m)^import [\w.]+(, [\w.]+)*\s*$`)},
+	{LanguagePython, 2, regexp.MustCompile(`(?m)^if __name__ == ["']__main__["']:`)},
+
+	{LanguageRust, 1, regexp.MustCompile(`(?m)^\s*(pub(\(\w+\))? )?fn \w+`)},
+	{LanguageRust, 1, regexp.MustCompile(`(?m)^use \w+(::[\w{}*, ]+)+;`)},
+	{LanguageRust, 1, regexp.MustCompile(`(?m)^impl\b`)},
+	{LanguageRust, 1, regexp.MustCompile(`\blet mut \w+`)},
+
+	{LanguageJava, 2, regexp.MustCompile(`(?m)^package [\w.]+;`)},
+	{LanguageJava, 1, regexp.MustCompile(`(?m)^import (static )?[\w.]+(\.\*)?;`)},
+	{LanguageJava, 1, regexp.MustCompile(`\bpublic (final |abstract )?(class|interface|enum|record) \w+`)},
+	{LanguageJava, 1, regexp.MustCompile(`\bSystem\.out\.print`)},
+
+	{LanguageJavaScript, 1, regexp.MustCompile(`(?m)^(export )?(default )?(async )?function\*? \w*\s*\(`)},
+	{LanguageJavaScript, 1, regexp.MustCompile(`(?m)^(export )?(const|let|var) \w+ = `)},
+	{LanguageJavaScript, 1, regexp.MustCompile(`(?m)^import .+ from ['"]`)},
+	{LanguageJavaScript, 1, regexp.MustCompile(`\brequire\(['"][\w@./-]+['"]\)`)},
+	{LanguageJavaScript, 1, regexp.MustCompile(`\bmodule\.exports\b|\bconsole\.log\(`)},
+
+	{LanguageTypeScript, 1, regexp.MustCompile(`(?m)^(export )?interface \w+`)},
+	{LanguageTypeScript, 1, regexp.MustCompile(`(?m)^(export )?type \w+(<.*>)? = `)},
+	{LanguageTypeScript, 1, regexp.MustCompile(`\w\??: (string|number|boolean|void|unknown|any)\b`)},
+
+	{LanguageSQL, 1, regexp.MustCompile(`(?im)^\s*(create|alter|drop)( or replace)? (table|view|index|function|schema)\b`)},
+	{LanguageSQL, 1, regexp.MustCompile(`(?im)^\s*select\s.+\sfrom\s`)},
+	{LanguageSQL, 1, regexp.MustCompile(`(?im)^\s*insert into\s`)},
+
+	{LanguageHCL, 2, regexp.MustCompile(`(?m)^(resource|data|module|variable|output|provider)( "[\w.-]+")+ \{`)},
+	{LanguageHCL, 2, regexp.MustCompile(`(?m)^(terraform|locals) \{`)},
+
+	{LanguageProto, 2, regexp.MustCompile(`(?m)^syntax = "proto[23]";`)},
+	{LanguageProto, 1, regexp.MustCompile(`(?m)^(message|service) \w+ \{`)},
+
+	{LanguageElixir, 2, regexp.MustCompile(`(?m)^\s*defmodule [\w.]+ do\s*$`)},
+	{LanguageElixir, 1, regexp.MustCompile(`(?m)^\s*defp? \w+.*,? do:? `)},
+	{LanguageElixir, 1, regexp.MustCompile(`(?m)^\s*@(moduledoc|doc|spec) `)},
+
+	{LanguageLua, 1, regexp.MustCompile(`(?m)^local function \w+`)},
+	{LanguageLua, 1, regexp.MustCompile(`(?m)^local \w+ = require\b`)},
+	{LanguageLua, 1, regexp.MustCompile(`(?m)^function [\w.:]+\([^)]*\)\s*$`)},
+	{LanguageLua, 1, regexp.MustCompile(`(?m)\bthen\s*$`)},
+}
+
+// DetectLanguageFromContent detects the language of source code from its content,
+// for files whose extension is missing or unknown. It looks for, in order:
+//
+//   - a shebang line naming the interpreter (#!/usr/bin/env python3)
+//   - an editor modeline in the first or last lines (vim: ft=lua, -*- mode: go -*-)
+//   - language-specific patterns, such as "package main" or "def f():", which
+//     must match at least twice, or once for distinctive ones, and more often
+//     than those of any other language
+//
+// Scripts whose shebang names an unsupported interpreter, such as #!/bin/bash,
+// are not guessed at. Returns empty string if no supported language is
+// recognized.
+func DetectLanguageFromContent(code []byte) Language {
+	if len(code) > maxDetectBytes {
+		code = code[:maxDetectBytes]
+	}
+
+	if interpreter := shebangInterpreter(code); interpreter != "" {
+		return shebangLanguage(interpreter)
+	}
+	if lang := modelineLanguage(code); lang != "" {
+		return lang
+	}
+	return heuristicLanguage(code)
+}
+
+// DetectFileLanguage detects the language of a file from its path and content:
+// the extension if it is known, otherwise the content as DetectLanguageFromContent
+// does, so that executable scripts such as bin/deploy starting with
+// "#!/usr/bin/env python3" are recognized. Text, data and configuration files,
+// such as .txt, .yaml or .json files, are not detected from their content.
+// Chunking and ChunkFile detect languages this way. Returns empty string if the
+// language is not supported.
+func DetectFileLanguage(filepath string, code []byte) Language {
+	if lang := DetectLanguage(filepath); lang != "" {
+		return lang
+	}
+	if nonCodeExtensions[strings.ToLower(path.Ext(strings.ReplaceAll(filepath, "\\", "/")))] {
+		return ""
+	}
+	return DetectLanguageFromContent(code)
+}
+
+// shebangLanguage returns the language of a script interpreter, or ""
+func shebangLanguage(interpreter string) Language {
+	if lang, ok := shebangInterpreters[interpreter]; ok {
+		return lang
+	}
+	// Versioned interpreters such as python3.12 or lua5.4
+	return shebangInterpreters[strings.TrimRight(interpreter, "0123456789.")]
+}
+
+// shebangInterpreter returns the name of the interpreter on a script's shebang
+// line, looking through env and its options (#!/usr/bin/env -S deno run), or ""
+func shebangInterpreter(code []byte) string {
+	if !bytes.HasPrefix(code, []byte("#!")) {
+		return ""
+	}
+	line, _, _ := bytes.Cut(code[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+
+	interpreter := path.Base(fields[0])
+	if interpreter != "env" {
+		return interpreter
+	}
+	for i := 1; i < len(fields); i++ {
+		switch field := fields[i]; {
+		case field == "-u" || field == "--unset" || field == "-C" || field == "--chdir":
+			i++ // Skip the option's argument
+		case strings.HasPrefix(field, "-") || strings.Contains(field, "="):
+			// Other options, such as -S and -i, and variable assignments
+		default:
+			return path.Base(field)
+		}
+	}
+	return ""
+}
+
+// modelineLanguage returns the language named by a vim or emacs modeline in the
+// first or last five lines, or ""
+func modelineLanguage(code []byte) Language {
+	lines := strings.Split(string(code), "\n")
+	candidates := lines
+	if len(lines) > 10 {
+		candidates = append(lines[:5:5], lines[len(lines)-5:]...)
+	}
+
+	for _, line := range candidates {
+		name := ""
+		if match := vimModelinePattern.FindStringSubmatch(line); match != nil {
+			name = match[1]
+		} else if match := emacsModelinePattern.FindStringSubmatch(line); match != nil {
+			name = match[1]
+			for _, variable := range strings.Split(name, ";") {
+				if key, value, ok := strings.Cut(variable, ":"); ok && strings.TrimSpace(key) == "mode" {
+					name = value
+				}
+			}
+		}
+		if name == "" {
+			continue
+		}
+		if lang := languageForName(name); IsLanguageSupported(lang) {
+			return lang
+		}
+	}
+	return ""
+}
+
+// heuristicLanguage returns the language whose content hints match code with the
+// most weight, or "" if it is below minHintScore or shared by another language.
+// JavaScript and TypeScript don't compete with each other, so ties between them
+// go to JavaScript.
+func heuristicLanguage(code []byte) Language {
+	scores := make(map[Language]int)
+	var order []Language
+	for _, hint := range contentHints {
+		if len(order) == 0 || order[len(order)-1] != hint.lang {
+			order = append(order, hint.lang)
+		}
+	}
+
+	for _, hint := range contentHints {
+		if hint.pattern.Match(code) {
+			scores[hint.lang] += hint.weight
+			if hint.lang == LanguageJavaScript {
+				scores[LanguageTypeScript] += hint.weight
+			}
+		}
+	}
+
+	best, bestScore := Language(""), 0
+	for _, lang := range order {
+		if scores[lang] > bestScore {
+			best, bestScore = lang, scores[lang]
+		}
+	}
+	if bestScore < minHintScore {
+		return ""
+	}
+	for _, lang := range order {
+		if lang != best && !isJSFamilyPair(lang, best) && scores[lang] == bestScore {
+			return ""
+		}
+	}
+	return best
+}
+
+// isJSFamilyPair reports whether a and b are JavaScript and TypeScript
+func isJSFamilyPair(a, b Language) bool {
+	return a == LanguageJavaScript && b == LanguageTypeScript || a == LanguageTypeScript && b == LanguageJavaScript
+}
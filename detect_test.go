@@ -0,0 +1,113 @@
+package codechunk
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDetectLanguageFromContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     string
+		expected Language
+	}{
+		{"python shebang", "#!/usr/bin/env python3\nprint('hi')\n", LanguagePython},
+		{"node shebang", "#!/usr/local/bin/node\nconsole.log(1)\n", LanguageJavaScript},
+		{"lua shebang", "#!/usr/bin/lua\nprint(1)\n", LanguageLua},
+		{"unknown shebang", "#!/bin/sh\necho hi\n", ""},
+		{"spaced shebang", "#! /usr/bin/env python3\r\nprint('hi')\r\n", LanguagePython},
+		{"versioned interpreter", "#!/usr/local/bin/python3.12\nprint('hi')\n", LanguagePython},
+		{"env options", "#!/usr/bin/env -S -u HOME deno run --allow-net\nconsole.log(1)\n", LanguageTypeScript},
+		{"env assignment", "#!/usr/bin/env LUA_PATH=./?.lua lua5.4\nprint(1)\n", LanguageLua},
+		{"vim modeline", "x = 1\n# vim: set ft=python ts=4:\n", LanguagePython},
+		{"emacs modeline", "// -*- mode: go; tab-width: 4 -*-\nx := 1\n", LanguageGo},
+		{"emacs short modeline", "-- -*- lua -*-\nx = 1\n", LanguageLua},
+		{"go", "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n}\n", LanguageGo},
+		{"python", "import os\n\ndef main():\n    pass\n", LanguagePython},
+		{"rust", "use std::io;\n\nfn main() {\n    let mut x = 1;\n}\n", LanguageRust},
+		{"java", "package a.b;\n\npublic class Main {\n}\n", LanguageJava},
+		{"javascript", "const fs = require('fs')\nmodule.exports = {}\n", LanguageJavaScript},
+		{"typescript", "export interface User {\n  name: string\n}\n", LanguageTypeScript},
+		{"sql", "CREATE TABLE users (id INT);\nSELECT id FROM users;\n", LanguageSQL},
+		{"hcl", "resource \"aws_vpc\" \"main\" {\n}\n", LanguageHCL},
+		{"proto", "syntax = \"proto3\";\n\nmessage User {\n}\n", LanguageProto},
+		{"elixir", "defmodule App do\n  def run, do: :ok\nend\n", LanguageElixir},
+		{"lua", "local M = require \"m\"\n\nlocal function f()\nend\n", LanguageLua},
+		{"plain text", "Remember to buy milk.\n", ""},
+		{"bash script", "#!/bin/bash\nif [ -f .env ]; then\n  source .env\nfi\nlocal_function() {\n  echo hi\n}\n", ""},
+		{"single weak hint", "Meeting notes\n\nselect the owners from the team list\n", ""},
+		{"tied hints", "package main\nimport os\n", ""},
+		{"distinctive hint", "resource \"aws_s3_bucket\" \"logs\" {\n  bucket = \"logs\"\n}\n", LanguageHCL},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectLanguageFromContent([]byte(tt.code)); got != tt.expected {
+				t.Errorf("DetectLanguageFromContent() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestDetectFileLanguageSkipsNonCode(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+	}{
+		{"notes.txt", "SELECT id FROM users;\nCREATE TABLE users (id INT);\n"},
+		{"config.yaml", "import os\ndef main():\n"},
+		{"data.json", "{\"package main\": \"func main(\"}\n"},
+	}
+	for _, tt := range tests {
+		if got := DetectFileLanguage(tt.filepath, []byte(tt.code)); got != "" {
+			t.Errorf("DetectFileLanguage(%q) = %q, want no language", tt.filepath, got)
+		}
+	}
+}
+
+func TestChunkDetectsLanguageFromContent(t *testing.T) {
+	code := "#!/usr/bin/env python3\n\ndef deploy(env):\n    return env\n"
+	chunks, err := Chunk("bin/deploy", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) == 0 || chunks[0].Context.Language != LanguagePython {
+		t.Fatalf("Expected Python chunks for an extensionless script, got %+v", chunks)
+	}
+
+	// A known extension takes precedence over the content
+	chunks, err = Chunk("deploy.go", "package main\n\nfunc deploy() {}\n// vim: ft=python\n", nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if chunks[0].Context.Language != LanguageGo {
+		t.Errorf("Expected the extension to win, got %q", chunks[0].Context.Language)
+	}
+}
+
+func TestChunkFileShebangScript(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bin", "deploy")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	code := "#!/usr/bin/env python3\n\ndef deploy(env):\n    return env\n"
+	if err := os.WriteFile(path, []byte(code), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	chunks, err := ChunkFile(path, nil)
+	if err != nil {
+		t.Fatalf("ChunkFile failed: %v", err)
+	}
+	if len(chunks) == 0 || chunks[0].Context.Language != LanguagePython {
+		t.Fatalf("Expected Python chunks for a shebang script, got %+v", chunks)
+	}
+	if len(chunks[0].Context.Entities) == 0 || chunks[0].Context.Entities[0].Name != "deploy" {
+		t.Errorf("Expected the script's function as an entity, got %+v", chunks[0].Context.Entities)
+	}
+	if got := DetectFileLanguage("bin/deploy", []byte(code)); got != LanguagePython {
+		t.Errorf("DetectFileLanguage() = %q, want %q", got, LanguagePython)
+	}
+}
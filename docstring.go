@@ -25,6 +25,10 @@ var docCommentPrefixes = map[Language][]string{
 	LanguageRust:        {"///", "//!", "/**", "/*!"},
 	LanguageGo:          {"//", "/*"},
 	LanguageJava:        {"/**", "///"},
+	LanguageSQL:         {"--", "/*"},
+	LanguageHCL:         {"#", "//", "/*"},
+	LanguageProto:       {"//", "/*"},
+	LanguageLua:         {"--"},
 }
 
 // IsDocComment checks if a comment text is a documentation comment
@@ -43,11 +47,44 @@ func IsDocComment(text string, lang Language) bool {
 	return false
 }
 
+// innerDocCommentPrefixes are prefixes of Rust inner doc comments, which document
+// the enclosing module or crate rather than the item that follows them
+var innerDocCommentPrefixes = []string{"//!", "/*!"}
+
+// isItemDocComment checks if a comment text documents the item that follows it:
+// a documentation comment other than a Rust inner doc comment
+func isItemDocComment(text string, lang Language) bool {
+	if !IsDocComment(text, lang) {
+		return false
+	}
+	if lang == LanguageRust {
+		text = strings.TrimSpace(text)
+		for _, prefix := range innerDocCommentPrefixes {
+			if strings.HasPrefix(text, prefix) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 // extractDocstring extracts the documentation comment for an entity
 func extractDocstring(node *sitter.Node, lang Language, code []byte) *string {
 	switch lang {
 	case LanguagePython:
 		return extractPythonDocstring(node, code)
+	case LanguageElixir:
+		return extractElixirDocstring(node, code)
+	case LanguageLua:
+		// LDoc comments (---) directly before a function are part of its node
+		if doc := node.ChildByFieldName("documentation"); doc != nil {
+			docstring := cleanDocComment(doc.Content(code), lang)
+			if docstring != "" {
+				return &docstring
+			}
+			return nil
+		}
+		return extractLeadingComment(node, lang, code)
 	default:
 		return extractLeadingComment(node, lang, code)
 	}
@@ -79,24 +116,142 @@ func extractPythonDocstring(node *sitter.Node, code []byte) *string {
 		return nil
 	}
 
-	if firstStmt.Type() == "expression_statement" && firstStmt.ChildCount() > 0 {
-		strNode := firstStmt.Child(0)
-		if strNode != nil && strNode.Type() == "string" {
-			docstring := string(code[strNode.StartByte():strNode.EndByte()])
-			docstring = strings.TrimPrefix(docstring, "\"\"\"")
-			docstring = strings.TrimPrefix(docstring, "'''")
-			docstring = strings.TrimSuffix(docstring, "\"\"\"")
-			docstring = strings.TrimSuffix(docstring, "'''")
-			docstring = strings.TrimSpace(docstring)
-			if docstring != "" {
-				return &docstring
+	if docstring := pythonDocstring(firstStmt, code); docstring != "" {
+		return &docstring
+	}
+
+	return nil
+}
+
+// pythonDocstring returns the text of a statement that is a docstring, a bare
+// string expression, without its quotes
+func pythonDocstring(stmt *sitter.Node, code []byte) string {
+	if stmt.Type() != "expression_statement" || stmt.ChildCount() == 0 {
+		return ""
+	}
+	strNode := stmt.Child(0)
+	if strNode == nil || strNode.Type() != "string" {
+		return ""
+	}
+	docstring := string(code[strNode.StartByte():strNode.EndByte()])
+	docstring = strings.TrimPrefix(docstring, "\"\"\"")
+	docstring = strings.TrimPrefix(docstring, "'''")
+	docstring = strings.TrimSuffix(docstring, "\"\"\"")
+	docstring = strings.TrimSuffix(docstring, "'''")
+	return strings.TrimSpace(docstring)
+}
+
+// extractFileDoc extracts the documentation of a whole file: the module docstring
+// of a Python file, the package comment of a Go file, or the inner doc comments
+// (//! and /*!) at the top of a Rust file. Returns "" if the file has none.
+func extractFileDoc(rootNode *sitter.Node, lang Language, code []byte) string {
+	switch lang {
+	case LanguagePython:
+		// The docstring is the first statement, after any shebang or comments
+		for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+			if child := rootNode.NamedChild(i); child.Type() != "comment" {
+				return pythonDocstring(child, code)
+			}
+		}
+
+	case LanguageGo:
+		// The package comment is the comment group directly above the package clause
+		clauses := childrenOfType(rootNode, "package_clause")
+		if len(clauses) == 0 {
+			return ""
+		}
+		lines := make([]string, 0)
+		next := clauses[0]
+		for prev := next.PrevNamedSibling(); prev != nil && prev.Type() == "comment"; prev = prev.PrevNamedSibling() {
+			if prev.EndPoint().Row+1 < next.StartPoint().Row {
+				break
+			}
+			lines = append([]string{prev.Content(code)}, lines...)
+			next = prev
+		}
+		return cleanDocComment(strings.Join(lines, "\n"), lang)
+
+	case LanguageRust:
+		lines := make([]string, 0)
+		for i := 0; i < int(rootNode.NamedChildCount()); i++ {
+			child := rootNode.NamedChild(i)
+			if child.Type() != "line_comment" && child.Type() != "block_comment" {
+				break
+			}
+			if child.ChildByFieldName("inner") == nil {
+				continue
+			}
+			if doc := child.ChildByFieldName("doc"); doc != nil && strings.TrimSpace(doc.Content(code)) != "" {
+				lines = append(lines, strings.TrimSpace(doc.Content(code)))
 			}
 		}
+		return strings.Join(lines, " ")
+	}
+	return ""
+}
+
+// extractElixirDocstring extracts the @moduledoc of an Elixir module or the @doc
+// preceding a function, skipping other attributes such as @spec in between.
+// @doc false hides a function and yields no docstring.
+func extractElixirDocstring(node *sitter.Node, code []byte) *string {
+	if elixirCallTarget(node, code) == "defmodule" || elixirCallTarget(node, code) == "defprotocol" {
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if block := node.NamedChild(i); block.Type() == "do_block" {
+				for j := 0; j < int(block.NamedChildCount()); j++ {
+					if doc := elixirAttributeString(block.NamedChild(j), "moduledoc", code); doc != nil {
+						return doc
+					}
+				}
+			}
+		}
+		return nil
 	}
 
+	for prev := node.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if prev.Type() == "comment" {
+			continue
+		}
+		if prev.Type() != "unary_operator" {
+			return nil
+		}
+		operand := prev.ChildByFieldName("operand")
+		if operand == nil || operand.Type() != "call" {
+			return nil
+		}
+		if elixirCallTarget(operand, code) == "doc" {
+			return elixirAttributeString(prev, "doc", code)
+		}
+	}
 	return nil
 }
 
+// elixirAttributeString returns the trimmed string value of a module attribute
+// such as @doc "text", or nil if node is not that attribute or has no string value
+func elixirAttributeString(node *sitter.Node, name string, code []byte) *string {
+	if node.Type() != "unary_operator" {
+		return nil
+	}
+	operand := node.ChildByFieldName("operand")
+	if operand == nil || operand.Type() != "call" || elixirCallTarget(operand, code) != name {
+		return nil
+	}
+	value := elixirDefinitionHead(operand)
+	if value == nil || value.Type() != "string" {
+		return nil
+	}
+	var text strings.Builder
+	for i := 0; i < int(value.NamedChildCount()); i++ {
+		if part := value.NamedChild(i); part.Type() == "quoted_content" {
+			text.WriteString(part.Content(code))
+		}
+	}
+	docstring := strings.TrimSpace(text.String())
+	if docstring == "" {
+		return nil
+	}
+	return &docstring
+}
+
 // extractLeadingComment extracts leading comments before an entity
 func extractLeadingComment(node *sitter.Node, lang Language, code []byte) *string {
 	parent := node.Parent()
@@ -104,19 +259,21 @@ func extractLeadingComment(node *sitter.Node, lang Language, code []byte) *strin
 		return nil
 	}
 
-	var nodeIndex int = -1
-	for i := 0; i < int(parent.ChildCount()); i++ {
-		if parent.Child(i) == node {
-			nodeIndex = i
-			break
+	// SQL definitions are wrapped in a statement, which the comment precedes, and
+	// comments before the first block of an HCL file precede the file's body
+	if (lang == LanguageSQL && parent.Type() == "statement") ||
+		(lang == LanguageHCL && parent.Type() == "body" && parent.Child(0) == node) {
+		node = parent
+		if parent = node.Parent(); parent == nil {
+			return nil
 		}
 	}
 
-	if nodeIndex <= 0 {
-		return nil
+	// Attributes such as #[derive(Debug)] stand between a doc comment and its item
+	prevSibling := node.PrevSibling()
+	for prevSibling != nil && leadingNodeTypes[prevSibling.Type()] {
+		prevSibling = prevSibling.PrevSibling()
 	}
-
-	prevSibling := parent.Child(nodeIndex - 1)
 	if prevSibling == nil {
 		return nil
 	}
@@ -127,7 +284,7 @@ func extractLeadingComment(node *sitter.Node, lang Language, code []byte) *strin
 
 	commentText := string(code[prevSibling.StartByte():prevSibling.EndByte()])
 
-	if !IsDocComment(commentText, lang) {
+	if !isItemDocComment(commentText, lang) {
 		return nil
 	}
 
@@ -160,7 +317,7 @@ func cleanDocComment(text string, lang Language) string {
 		}
 		return strings.Join(cleanLines, " ")
 
-	case LanguageGo:
+	case LanguageGo, LanguageProto:
 		lines := strings.Split(text, "\n")
 		cleanLines := make([]string, 0, len(lines))
 		for _, line := range lines {
@@ -191,6 +348,24 @@ func cleanDocComment(text string, lang Language) string {
 		}
 		return strings.Join(cleanLines, " ")
 
+	case LanguageSQL, LanguageHCL, LanguageLua:
+		text = strings.TrimPrefix(text, "/*")
+		text = strings.TrimSuffix(text, "*/")
+		lines := strings.Split(text, "\n")
+		cleanLines := make([]string, 0, len(lines))
+		for _, line := range lines {
+			line = strings.TrimSpace(line)
+			line = strings.TrimLeft(line, "-")
+			line = strings.TrimPrefix(line, "#")
+			line = strings.TrimPrefix(line, "//")
+			line = strings.TrimPrefix(line, "*")
+			line = strings.TrimSpace(line)
+			if line != "" {
+				cleanLines = append(cleanLines, line)
+			}
+		}
+		return strings.Join(cleanLines, " ")
+
 	default:
 		return text
 	}
@@ -1,6 +1,7 @@
 package codechunk
 
 import (
+	"strings"
 	"testing"
 )
 
@@ -254,3 +255,47 @@ def hello():
 		t.Error("Expected to find hello function")
 	}
 }
+
+func TestRustInnerDocNotItemDoc(t *testing.T) {
+	code := "//! Crate docs\nfn inner() {}\n\n/*! Module docs */\n/// Outer docs\nfn outer() {}\n"
+	if doc := entityByName(t, code, LanguageRust, "inner").Docstring; doc != nil {
+		t.Errorf("Expected no docstring for inner, got %q", *doc)
+	}
+	if doc := entityByName(t, code, LanguageRust, "outer").Docstring; doc == nil || *doc != "Outer docs" {
+		t.Errorf("Expected outer documented by its outer doc comment, got %v", doc)
+	}
+
+	chunks, err := Chunk("lib.rs", code, &ChunkOptions{ContextMode: ContextModeFull})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	header := strings.TrimSuffix(chunks[0].ContextualizedText, chunks[0].Text)
+	if n := strings.Count(header, "Crate docs"); n != 1 {
+		t.Errorf("Expected the crate doc once in the header, got %d times in %q", n, header)
+	}
+}
+
+func TestExtractFileDoc(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		code     string
+		expected string
+	}{
+		{LanguagePython, "#!/usr/bin/env python\n\"\"\"Tools for auth.\"\"\"\n\nimport os\n", "Tools for auth."},
+		{LanguagePython, "import os\n\"\"\"Not a docstring.\"\"\"\n", ""},
+		{LanguageGo, "// Copyright 2024\n\n// Package auth verifies tokens.\n// It is safe for concurrent use.\npackage auth\n", "Package auth verifies tokens. It is safe for concurrent use."},
+		{LanguageGo, "// Package auth verifies tokens.\n\npackage auth\n", ""},
+		{LanguageRust, "//! Token verification.\n//!\n//! Supports JWT.\n\nuse std::io;\n", "Token verification. Supports JWT."},
+		{LanguageRust, "/// Item doc, not file doc.\nfn f() {}\n", ""},
+	}
+
+	for _, tt := range tests {
+		parseResult, err := parseString(tt.code, tt.lang)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+		if doc := extractFileDoc(parseResult.Tree.RootNode(), tt.lang, []byte(tt.code)); doc != tt.expected {
+			t.Errorf("%s: expected file doc %q, got %q", tt.lang, tt.expected, doc)
+		}
+	}
+}
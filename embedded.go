@@ -0,0 +1,201 @@
+package codechunk
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// languageAliases maps names used in fenced code block info strings and script
+// types to languages, in addition to the language names and file extensions
+var languageAliases = map[string]Language{
+	"golang":     LanguageGo,
+	"js":         LanguageJavaScript,
+	"node":       LanguageJavaScript,
+	"module":     LanguageJavaScript,
+	"ecmascript": LanguageJavaScript,
+	"ts":         LanguageTypeScript,
+	"protobuf":   LanguageProto,
+	"py":         LanguagePython,
+	"python3":    LanguagePython,
+	"rs":         LanguageRust,
+	"terraform":  LanguageHCL,
+}
+
+// languageForName returns the language named by a fenced code block info string
+// or a script type, such as "go", "tsx" or "text/javascript". Names of unsupported
+// languages are returned lowercased as is.
+func languageForName(name string) Language {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if i := strings.LastIndexByte(name, '/'); i >= 0 {
+		name = name[i+1:]
+	}
+	if IsLanguageSupported(Language(name)) {
+		return Language(name)
+	}
+	if lang, ok := languageAliases[name]; ok {
+		return lang
+	}
+	if lang, ok := LanguageExtensions["."+name]; ok {
+		return lang
+	}
+	return Language(name)
+}
+
+// chunkMarkdown chunks a Markdown document. Fenced code blocks tagged with a
+// language are chunked in that language, or as plain text when it is unsupported,
+// and the prose between them, including untagged blocks, as Markdown text. Ranges
+// and lines refer to the whole document.
+func chunkMarkdown(path string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	m.Language = LanguageMarkdown
+	return chunkBlocks(path, code, markdownBlocks(code), opts, m, parser)
+}
+
+// chunkHTML chunks the JavaScript and TypeScript of an HTML page's inline <script>
+// elements. Scripts of other types, such as JSON data, are skipped, and a page
+// without scripts is chunked as plain text.
+func chunkHTML(path string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	m.Language = LanguageHTML
+
+	var blocks []embeddedBlock
+	for _, element := range scanComponent(code, false) {
+		if element.name != "script" || len(bytes.TrimSpace(code[element.content.Start:element.content.End])) == 0 {
+			continue
+		}
+		if lang := htmlScriptLanguage(element.openTag); lang != "" {
+			blocks = append(blocks, embeddedBlock{byteRange: element.content, lang: lang})
+		}
+	}
+	if len(blocks) == 0 {
+		return chunkPlainText(path, string(code), LanguageHTML, opts), nil
+	}
+	return chunkBlocks(path, code, blocks, opts, m, parser)
+}
+
+// typeAttrPattern matches the type attribute of a <script> tag
+var typeAttrPattern = regexp.MustCompile(`(?i)\btype\s*=\s*["']?([\w/+.-]+)`)
+
+// htmlScriptLanguage returns the language of an HTML <script> element from its
+// opening tag, or "" if the script is not JavaScript or TypeScript
+func htmlScriptLanguage(openTag string) Language {
+	if match := typeAttrPattern.FindStringSubmatch(openTag); match != nil {
+		switch lang := languageForName(match[1]); lang {
+		case LanguageJavaScript, LanguageTypeScript:
+			return lang
+		default:
+			return ""
+		}
+	}
+	return scriptLanguage(openTag)
+}
+
+// chunkBlocks chunks the embedded blocks of a file in order and numbers the chunks
+// across the whole file
+func chunkBlocks(path string, code []byte, blocks []embeddedBlock, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	positions := newPositionIndex(code)
+	var source string
+	if opts.LazyText {
+		source = string(code)
+	}
+
+	chunks := make([]CodeChunk, 0)
+	for _, block := range blocks {
+		blockChunks, err := chunkEmbedded(path, code, block, opts, m, parser, positions, source)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, blockChunks...)
+	}
+	numberChunks(chunks)
+	return chunks, nil
+}
+
+// markdownBlocks splits a Markdown document into its tagged fenced code blocks and
+// the non-blank prose between them. An unclosed fence runs to the end of the document.
+func markdownBlocks(code []byte) []embeddedBlock {
+	var blocks []embeddedBlock
+	proseStart := 0
+	addProse := func(end int) {
+		if len(bytes.TrimSpace(code[proseStart:end])) > 0 {
+			blocks = append(blocks, embeddedBlock{byteRange: ByteRange{Start: proseStart, End: end}, lang: LanguageMarkdown})
+		}
+	}
+
+	for offset := 0; offset < len(code); {
+		line, next := nextLine(code, offset)
+		fence, info, ok := openingFence(line)
+		if !ok {
+			offset = next
+			continue
+		}
+
+		// Find the closing fence; the content ends before it
+		contentStart := next
+		contentEnd, end := len(code), len(code)
+		for pos := next; pos < len(code); {
+			l, n := nextLine(code, pos)
+			if isClosingFence(l, fence) {
+				contentEnd, end = pos, n
+				break
+			}
+			pos = n
+		}
+
+		lang := Language("")
+		if name, _, _ := strings.Cut(info, " "); name != "" {
+			lang = languageForName(strings.Trim(name, "{}."))
+		}
+		if lang != "" && len(bytes.TrimSpace(code[contentStart:contentEnd])) > 0 {
+			addProse(offset)
+			blocks = append(blocks, embeddedBlock{byteRange: ByteRange{Start: contentStart, End: contentEnd}, lang: lang})
+			proseStart = end
+		}
+		offset = end
+	}
+	addProse(len(code))
+	return blocks
+}
+
+// nextLine returns the line starting at offset without its newline, and the
+// offset of the following line
+func nextLine(code []byte, offset int) ([]byte, int) {
+	end := bytes.IndexByte(code[offset:], '\n')
+	if end < 0 {
+		return code[offset:], len(code)
+	}
+	return code[offset : offset+end], offset + end + 1
+}
+
+// openingFence reports whether a line opens a fenced code block, returning the
+// fence (a run of at least three backticks or tildes) and the info string
+func openingFence(line []byte) (fence string, info string, ok bool) {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 || len(trimmed) < 3 || (trimmed[0] != '`' && trimmed[0] != '~') {
+		return "", "", false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == trimmed[0] {
+		n++
+	}
+	if n < 3 {
+		return "", "", false
+	}
+	info = strings.TrimSpace(string(trimmed[n:]))
+	if trimmed[0] == '`' && strings.Contains(info, "`") {
+		return "", "", false
+	}
+	return string(trimmed[:n]), info, true
+}
+
+// isClosingFence reports whether a line closes a fenced code block opened by fence
+func isClosingFence(line []byte, fence string) bool {
+	trimmed := bytes.TrimLeft(line, " ")
+	if len(line)-len(trimmed) > 3 {
+		return false
+	}
+	n := 0
+	for n < len(trimmed) && trimmed[n] == fence[0] {
+		n++
+	}
+	return n >= len(fence) && len(bytes.TrimSpace(trimmed[n:])) == 0
+}
@@ -0,0 +1,124 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleMarkdown = "# Usage\n" +
+	"\n" +
+	"Create a client first.\n" +
+	"\n" +
+	"```go\n" +
+	"func NewClient(addr string) *Client {\n" +
+	"\treturn &Client{addr: addr}\n" +
+	"}\n" +
+	"```\n" +
+	"\n" +
+	"Then install it:\n" +
+	"\n" +
+	"```\n" +
+	"go get example.com/client\n" +
+	"```\n" +
+	"\n" +
+	"~~~~ {.python}\n" +
+	"def connect(addr):\n" +
+	"    return Client(addr)\n" +
+	"~~~~\n" +
+	"\n" +
+	"```toml\n" +
+	"addr = \"localhost\"\n" +
+	"```\n"
+
+func TestChunkMarkdownFencedCode(t *testing.T) {
+	chunks, err := Chunk("docs/usage.md", sampleMarkdown, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var langs []string
+	for i, chunk := range chunks {
+		langs = append(langs, string(chunk.Context.Language))
+		if chunk.Index != i || chunk.TotalChunks != len(chunks) {
+			t.Errorf("Chunk %d: expected chunks numbered across the document, got %d of %d", i, chunk.Index, chunk.TotalChunks)
+		}
+		if got := sampleMarkdown[chunk.ByteRange.Start:chunk.ByteRange.End]; got != chunk.Text {
+			t.Errorf("Chunk %d: expected the byte range to locate the text in the document, got %q for %q", i, got, chunk.Text)
+		}
+	}
+	want := "markdown,go,markdown,python,toml"
+	if strings.Join(langs, ",") != want {
+		t.Fatalf("Expected chunk languages %s, got %s", want, strings.Join(langs, ","))
+	}
+
+	goChunk := chunks[1]
+	if goChunk.LineRange.Start != 5 || goChunk.LineRange.End != 7 {
+		t.Errorf("Expected the Go block on lines 5-7, got %+v", goChunk.LineRange)
+	}
+	if len(goChunk.Context.Entities) == 0 || goChunk.Context.Entities[0].Name != "NewClient" {
+		t.Errorf("Expected the Go block's function as an entity, got %+v", goChunk.Context.Entities)
+	}
+	if goChunk.Context.Entities[0].LineRange.Start != 5 {
+		t.Errorf("Expected entity lines relative to the document, got %+v", goChunk.Context.Entities[0].LineRange)
+	}
+	if !strings.Contains(chunks[2].Text, "go get example.com/client") {
+		t.Errorf("Expected the untagged block to stay with the prose, got %q", chunks[2].Text)
+	}
+}
+
+func TestLanguageForName(t *testing.T) {
+	tests := map[string]Language{
+		"go":              LanguageGo,
+		"Golang":          LanguageGo,
+		"tsx":             LanguageTypeScript,
+		"py":              LanguagePython,
+		"text/javascript": LanguageJavaScript,
+		"module":          LanguageJavaScript,
+		"TOML":            Language("toml"),
+	}
+	for name, want := range tests {
+		if got := languageForName(name); got != want {
+			t.Errorf("languageForName(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestChunkHTMLScripts(t *testing.T) {
+	code := `<!DOCTYPE html>
+<html>
+<head>
+  <script type="application/ld+json">{"@type": "WebSite"}</script>
+  <script src="vendor.js"></script>
+</head>
+<body>
+  <script type="module">
+    function render(root) {
+      root.textContent = 'hello'
+    }
+  </script>
+</body>
+</html>
+`
+	chunks, err := Chunk("site/index.html", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 1 {
+		t.Fatalf("Expected only the module script to be chunked, got %d chunks", len(chunks))
+	}
+	chunk := chunks[0]
+	if chunk.Context.Language != LanguageJavaScript || !strings.Contains(chunk.Text, "function render") {
+		t.Errorf("Expected a JavaScript chunk of the module script, got %+v", chunk)
+	}
+	if chunk.LineRange.Start != 8 {
+		t.Errorf("Expected the script to start on line 8, got %d", chunk.LineRange.Start)
+	}
+
+	chunks, err = Chunk("site/about.html", "<p>About us</p>\n", nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Context.Language != LanguageHTML {
+		t.Errorf("Expected a page without scripts to be chunked as text, got %+v", chunks)
+	}
+}
@@ -0,0 +1,330 @@
+// Package embeddings computes embeddings of chunks with an OpenAI-compatible
+// /embeddings endpoint. Client batches inputs up to request size and token
+// limits, paces requests to stay under rate limits and retries rate-limited and
+// failed requests with backoff:
+//
+//	client := embeddings.NewClient(embeddings.Options{
+//	    APIKey: os.Getenv("OPENAI_API_KEY"),
+//	    Model:  "text-embedding-3-small",
+//	})
+//	vectors, err := client.EmbedChunks(ctx, chunks)
+package embeddings
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+// DefaultBaseURL is the base URL of the OpenAI API
+const DefaultBaseURL = "https://api.openai.com/v1"
+
+// Embedder computes an embedding for each of a list of texts. Client implements
+// it; other embedding backends can be swapped in where an Embedder is accepted.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+var _ Embedder = (*Client)(nil)
+
+// Options configures a Client
+type Options struct {
+	BaseURL    string       // Base URL of the API, without /embeddings (default: DefaultBaseURL)
+	APIKey     string       // Sent as a bearer token, if set
+	Model      string       // Embedding model
+	Dimensions int          // Dimensions of the embeddings, for models that support shortening them (default: 0, model default)
+	HTTPClient *http.Client // Client sending the requests (default: http.DefaultClient)
+
+	MaxBatchSize   int                    // Inputs per request (default: 2048)
+	MaxBatchTokens int                    // Tokens per request, counted by TokenCounter (default: 300000)
+	TokenCounter   codechunk.TokenCounter // Counts the tokens of inputs (default: codechunk.EstimateTokens)
+
+	RequestsPerMinute int // Requests started per minute (default: 0, no limit)
+	TokensPerMinute   int // Tokens sent per minute (default: 0, no limit)
+
+	MaxRetries    int           // Retries of a request that is rate limited or fails with a server or network error (default: 5, negative: none)
+	MinRetryDelay time.Duration // Delay before the first retry, doubled for each further one (default: 500ms)
+	MaxRetryDelay time.Duration // Upper bound of retry delays, including Retry-After (default: 30s)
+}
+
+// withDefaults fills in defaults for unset options
+func (o Options) withDefaults() Options {
+	if o.BaseURL == "" {
+		o.BaseURL = DefaultBaseURL
+	}
+	o.BaseURL = strings.TrimSuffix(o.BaseURL, "/")
+	if o.HTTPClient == nil {
+		o.HTTPClient = http.DefaultClient
+	}
+	if o.MaxBatchSize <= 0 {
+		o.MaxBatchSize = 2048
+	}
+	if o.MaxBatchTokens <= 0 {
+		o.MaxBatchTokens = 300000
+	}
+	if o.TokenCounter == nil {
+		o.TokenCounter = codechunk.EstimateTokens
+	}
+	if o.MaxRetries == 0 {
+		o.MaxRetries = 5
+	}
+	if o.MinRetryDelay <= 0 {
+		o.MinRetryDelay = 500 * time.Millisecond
+	}
+	if o.MaxRetryDelay <= 0 {
+		o.MaxRetryDelay = 30 * time.Second
+	}
+	return o
+}
+
+// APIError is an error response of the embeddings endpoint
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Message    string // Error message of the response, or its body
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("embeddings request failed with status %d: %s", e.StatusCode, e.Message)
+}
+
+// retryable reports whether the request may succeed when retried
+func (e *APIError) retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// Client computes embeddings with an OpenAI-compatible /embeddings endpoint. It
+// is safe for concurrent use; requests from all goroutines share the rate limits.
+type Client struct {
+	opts     Options
+	requests *pacer
+	tokens   *pacer
+}
+
+// NewClient creates a Client with the given options.
+func NewClient(opts Options) *Client {
+	opts = opts.withDefaults()
+	return &Client{
+		opts:     opts,
+		requests: newPacer(opts.RequestsPerMinute),
+		tokens:   newPacer(opts.TokensPerMinute),
+	}
+}
+
+// Embed returns the embedding of each text, in order. Texts are sent in as few
+// requests as the batch limits allow; a text exceeding MaxBatchTokens on its own
+// is sent alone.
+func (c *Client) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, batch := range c.batches(texts) {
+		embedded, err := c.embedBatch(ctx, batch.texts, batch.tokens)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, embedded...)
+	}
+	return vectors, nil
+}
+
+// EmbedChunks returns the embedding of each chunk, in order. A chunk is embedded
+// by its contextualized text, or by its raw text when it was built with a
+// ContextualizedTextMode that leaves the header out.
+func (c *Client) EmbedChunks(ctx context.Context, chunks []codechunk.CodeChunk) ([][]float32, error) {
+	texts := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		texts[i] = chunk.Contextualized()
+		if texts[i] == "" {
+			texts[i] = chunk.Content()
+		}
+	}
+	return c.Embed(ctx, texts)
+}
+
+// batch is a group of texts sent in one request
+type batch struct {
+	texts  []string
+	tokens int
+}
+
+// batches groups consecutive texts into requests within the batch limits
+func (c *Client) batches(texts []string) []batch {
+	var batches []batch
+	var current batch
+	for _, text := range texts {
+		tokens := c.opts.TokenCounter(text)
+		if len(current.texts) > 0 && (len(current.texts) == c.opts.MaxBatchSize || current.tokens+tokens > c.opts.MaxBatchTokens) {
+			batches = append(batches, current)
+			current = batch{}
+		}
+		current.texts = append(current.texts, text)
+		current.tokens += tokens
+	}
+	if len(current.texts) > 0 {
+		batches = append(batches, current)
+	}
+	return batches
+}
+
+// embedRequest is the body of an embeddings request
+type embedRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+	EncodingFormat string   `json:"encoding_format"`
+}
+
+// embedResponse is the body of a successful embeddings response
+type embedResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+}
+
+// errorResponse is the body of a failed embeddings response
+type errorResponse struct {
+	Error struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// embedBatch sends one request for texts, waiting for the rate limits and
+// retrying it as configured
+func (c *Client) embedBatch(ctx context.Context, texts []string, tokens int) ([][]float32, error) {
+	body, err := json.Marshal(embedRequest{
+		Model:          c.opts.Model,
+		Input:          texts,
+		Dimensions:     c.opts.Dimensions,
+		EncodingFormat: "float",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		if err := c.requests.wait(ctx, 1); err != nil {
+			return nil, err
+		}
+		if err := c.tokens.wait(ctx, tokens); err != nil {
+			return nil, err
+		}
+
+		vectors, retryAfter, err := c.send(ctx, body, len(texts))
+		if err == nil {
+			return vectors, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && !apiErr.retryable() {
+			return nil, err
+		}
+		if attempt >= c.opts.MaxRetries {
+			return nil, err
+		}
+
+		if err := sleep(ctx, c.retryDelay(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// send posts one embeddings request, returning the embeddings or the error with
+// the delay requested by a Retry-After header
+func (c *Client) send(ctx context.Context, body []byte, inputs int) ([][]float32, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.opts.BaseURL+"/embeddings", bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.opts.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.opts.APIKey)
+	}
+
+	resp, err := c.opts.HTTPClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		var errResp errorResponse
+		if json.Unmarshal(data, &errResp) == nil && errResp.Error.Message != "" {
+			apiErr.Message = errResp.Error.Message
+		}
+		return nil, parseRetryAfter(resp.Header.Get("Retry-After")), apiErr
+	}
+
+	var embedResp embedResponse
+	if err := json.Unmarshal(data, &embedResp); err != nil {
+		return nil, 0, fmt.Errorf("decoding embeddings response: %w", err)
+	}
+	vectors := make([][]float32, inputs)
+	for _, item := range embedResp.Data {
+		if item.Index < 0 || item.Index >= inputs {
+			return nil, 0, fmt.Errorf("embeddings response has index %d for %d inputs", item.Index, inputs)
+		}
+		vectors[item.Index] = item.Embedding
+	}
+	for i, vector := range vectors {
+		if vector == nil {
+			return nil, 0, fmt.Errorf("embeddings response is missing input %d", i)
+		}
+	}
+	return vectors, 0, nil
+}
+
+// retryDelay returns the delay before a retry: the server's Retry-After if set,
+// otherwise exponential backoff with jitter, capped at MaxRetryDelay
+func (c *Client) retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	delay := retryAfter
+	if delay <= 0 {
+		delay = c.opts.MinRetryDelay << attempt
+		if delay <= 0 || delay > c.opts.MaxRetryDelay {
+			delay = c.opts.MaxRetryDelay
+		}
+		delay = delay/2 + rand.N(delay/2+1)
+	}
+	return min(delay, c.opts.MaxRetryDelay)
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or as an HTTP date
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
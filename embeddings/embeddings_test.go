@@ -0,0 +1,175 @@
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+// fakeServer serves embeddings whose single component is the length of the
+// input, in reverse order, after failing the first failures requests with status
+type fakeServer struct {
+	mu       sync.Mutex
+	status   int
+	failures int
+	requests []embedRequest
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/embeddings" || r.Header.Get("Authorization") != "Bearer key" {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	var req embedRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	fail := f.failures > 0
+	if fail {
+		f.failures--
+	}
+	f.mu.Unlock()
+
+	if fail {
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(f.status)
+		w.Write([]byte(`{"error":{"message":"slow down"}}`))
+		return
+	}
+
+	var resp embedResponse
+	for i := len(req.Input) - 1; i >= 0; i-- {
+		resp.Data = append(resp.Data, struct {
+			Index     int       `json:"index"`
+			Embedding []float32 `json:"embedding"`
+		}{Index: i, Embedding: []float32{float32(len(req.Input[i]))}})
+	}
+	json.NewEncoder(w).Encode(resp)
+}
+
+func newTestClient(t *testing.T, server *fakeServer, opts Options) *Client {
+	t.Helper()
+	ts := httptest.NewServer(server)
+	t.Cleanup(ts.Close)
+	opts.BaseURL = ts.URL + "/"
+	opts.APIKey = "key"
+	opts.Model = "test-model"
+	opts.MinRetryDelay = time.Millisecond
+	return NewClient(opts)
+}
+
+func TestEmbedBatches(t *testing.T) {
+	server := &fakeServer{}
+	client := newTestClient(t, server, Options{MaxBatchSize: 2, MaxBatchTokens: 5, TokenCounter: func(text string) int { return len(text) }})
+
+	texts := []string{"a", "bb", "ccc", "dddddddd", "e"}
+	vectors, err := client.Embed(context.Background(), texts)
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	expected := [][]float32{{1}, {2}, {3}, {8}, {1}}
+	if !reflect.DeepEqual(vectors, expected) {
+		t.Errorf("Embed = %v, expected %v", vectors, expected)
+	}
+
+	// Batches are limited to 2 inputs and 5 tokens, except a single larger input
+	var batches [][]string
+	for _, req := range server.requests {
+		if req.Model != "test-model" || req.EncodingFormat != "float" {
+			t.Errorf("Unexpected request %+v", req)
+		}
+		batches = append(batches, req.Input)
+	}
+	expectedBatches := [][]string{{"a", "bb"}, {"ccc"}, {"dddddddd"}, {"e"}}
+	if !reflect.DeepEqual(batches, expectedBatches) {
+		t.Errorf("Batches = %v, expected %v", batches, expectedBatches)
+	}
+}
+
+func TestEmbedRetries(t *testing.T) {
+	server := &fakeServer{status: http.StatusTooManyRequests, failures: 2}
+	client := newTestClient(t, server, Options{})
+	vectors, err := client.Embed(context.Background(), []string{"abc"})
+	if err != nil {
+		t.Fatalf("Embed failed: %v", err)
+	}
+	if !reflect.DeepEqual(vectors, [][]float32{{3}}) || len(server.requests) != 3 {
+		t.Errorf("Expected success on the third request, got %v after %d requests", vectors, len(server.requests))
+	}
+
+	server = &fakeServer{status: http.StatusServiceUnavailable, failures: 5}
+	client = newTestClient(t, server, Options{MaxRetries: 2})
+	_, err = client.Embed(context.Background(), []string{"abc"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusServiceUnavailable || apiErr.Message != "slow down" {
+		t.Errorf("Expected the APIError of the last attempt, got %v", err)
+	}
+	if len(server.requests) != 3 {
+		t.Errorf("Expected 3 attempts, got %d", len(server.requests))
+	}
+
+	server = &fakeServer{status: http.StatusBadRequest, failures: 1}
+	client = newTestClient(t, server, Options{})
+	if _, err := client.Embed(context.Background(), []string{"abc"}); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusBadRequest {
+		t.Errorf("Expected a bad request error, got %v", err)
+	}
+	if len(server.requests) != 1 {
+		t.Errorf("Expected a bad request not to be retried, got %d attempts", len(server.requests))
+	}
+}
+
+func TestEmbedChunks(t *testing.T) {
+	code := `package main
+
+func main() {
+	println("hello")
+}
+`
+	opts := codechunk.ChunkOptions{ContextualizedTextMode: codechunk.ContextualizedTextModeNone}
+	chunks, err := codechunk.Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	server := &fakeServer{}
+	client := newTestClient(t, server, Options{})
+	if _, err := client.EmbedChunks(context.Background(), chunks); err != nil {
+		t.Fatalf("EmbedChunks failed: %v", err)
+	}
+	if len(server.requests) != 1 || server.requests[0].Input[0] != chunks[0].Text {
+		t.Errorf("Expected the raw text to be embedded without a header, got %+v", server.requests)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	var unlimited *pacer
+	if err := unlimited.wait(context.Background(), 1000); err != nil {
+		t.Errorf("Expected a nil pacer not to wait, got %v", err)
+	}
+
+	p := newPacer(60)
+	if err := p.wait(context.Background(), 2); err != nil {
+		t.Fatalf("Expected the first wait to return at once, got %v", err)
+	}
+	if ahead := time.Until(p.next); ahead < 1900*time.Millisecond || ahead > 2*time.Second {
+		t.Errorf("Expected 2 units at 60 a minute to be paid off in 2s, got %v", ahead)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.wait(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected waiting to stop when the context is done, got %v", err)
+	}
+}
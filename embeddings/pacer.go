@@ -0,0 +1,46 @@
+package embeddings
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// pacer spreads units of a per-minute limit evenly over time: each wait starts
+// once the units reserved before it have been paid off
+type pacer struct {
+	mu       sync.Mutex
+	interval time.Duration // Time per unit
+	next     time.Time     // When the units reserved so far are paid off
+}
+
+// newPacer creates a pacer for perMinute units a minute; zero or negative
+// disables pacing and returns nil
+func newPacer(perMinute int) *pacer {
+	if perMinute <= 0 {
+		return nil
+	}
+	return &pacer{interval: time.Minute / time.Duration(perMinute)}
+}
+
+// wait reserves n units and waits until they may be used, or until ctx is done.
+// A nil pacer never waits.
+func (p *pacer) wait(ctx context.Context, n int) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	now := time.Now()
+	start := p.next
+	if start.Before(now) {
+		start = now
+	}
+	p.next = start.Add(time.Duration(n) * p.interval)
+	p.mu.Unlock()
+
+	if delay := time.Until(start); delay > 0 {
+		return sleep(ctx, delay)
+	}
+	return nil
+}
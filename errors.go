@@ -0,0 +1,58 @@
+package codechunk
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ChunkStage is the stage of chunking a file at which an error occurred
+type ChunkStage string
+
+const (
+	StageDetect  ChunkStage = "detect"  // Detecting the language of the file
+	StageParse   ChunkStage = "parse"   // Parsing the source into a syntax tree
+	StageExtract ChunkStage = "extract" // Extracting entities from the syntax tree
+	StageWindow  ChunkStage = "window"  // Assigning syntax nodes to chunk windows
+	StageBuild   ChunkStage = "build"   // Building chunks and their context from windows
+)
+
+// ChunkError is the error returned when chunking a file fails. It records the
+// stage that failed with the file and language, and unwraps to its cause, so
+// errors.Is still matches ErrUnsupportedLanguage, ErrParseFailed, ErrCancelled
+// and context errors:
+//
+//	var chunkErr *codechunk.ChunkError
+//	if errors.As(err, &chunkErr) && chunkErr.Stage == codechunk.StageParse {
+//	    // The parser gave up on chunkErr.Filepath
+//	}
+type ChunkError struct {
+	Stage    ChunkStage // Stage that failed
+	Filepath string     // File being chunked
+	Language Language   // Language of the file, empty if it was not detected
+	Err      error      // Underlying cause
+}
+
+func (e *ChunkError) Error() string {
+	if e.Filepath == "" {
+		return fmt.Sprintf("%s: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("%s: %s: %v", e.Filepath, e.Stage, e.Err)
+}
+
+func (e *ChunkError) Unwrap() error {
+	return e.Err
+}
+
+// stageError returns err as a *ChunkError for filepath. An error that already is
+// one keeps its stage and gets the filepath if it has none; others are wrapped as
+// failing at stage.
+func stageError(err error, stage ChunkStage, filepath string, lang Language) error {
+	var chunkErr *ChunkError
+	if errors.As(err, &chunkErr) {
+		if chunkErr.Filepath == "" {
+			chunkErr.Filepath = filepath
+		}
+		return err
+	}
+	return &ChunkError{Stage: stage, Filepath: filepath, Language: lang, Err: err}
+}
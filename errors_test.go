@@ -0,0 +1,90 @@
+package codechunk
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestChunkErrorStages(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name     string
+		chunk    func() error
+		stage    ChunkStage
+		lang     Language
+		sentinel error
+	}{
+		{
+			name: "unsupported language",
+			chunk: func() error {
+				_, err := Chunk("notes.txt", "hello", nil)
+				return err
+			},
+			stage:    StageDetect,
+			sentinel: ErrUnsupportedLanguage,
+		},
+		{
+			name: "invalid notebook",
+			chunk: func() error {
+				_, err := Chunk("notes.ipynb", "{not json", nil)
+				return err
+			},
+			stage:    StageParse,
+			sentinel: ErrParseFailed,
+		},
+		{
+			name: "cancelled",
+			chunk: func() error {
+				_, err := ChunkWithContext(cancelled, "main.go", "package main\n", nil)
+				return err
+			},
+			stage:    StageParse,
+			lang:     LanguageGo,
+			sentinel: ErrCancelled,
+		},
+		{
+			name: "invalid boilerplate pattern",
+			chunk: func() error {
+				opts := DefaultChunkOptions()
+				opts.StripBoilerplate = true
+				opts.BoilerplatePatterns = []string{"("}
+				_, err := Chunk("main.go", "package main\n", &opts)
+				return err
+			},
+			stage: StageWindow,
+			lang:  LanguageGo,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.chunk()
+			var chunkErr *ChunkError
+			if !errors.As(err, &chunkErr) {
+				t.Fatalf("Expected a ChunkError, got %v", err)
+			}
+			if chunkErr.Stage != tt.stage || chunkErr.Language != tt.lang || chunkErr.Filepath == "" {
+				t.Errorf("Unexpected ChunkError: %#v", chunkErr)
+			}
+			if tt.sentinel != nil && !errors.Is(err, tt.sentinel) {
+				t.Errorf("Expected %v to match %v", err, tt.sentinel)
+			}
+		})
+	}
+}
+
+func TestStageError(t *testing.T) {
+	err := stageError(ErrParseFailed, StageParse, "a.go", LanguageGo)
+	if err.Error() != "a.go: parse: parse failed" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	// An existing ChunkError keeps its stage and gains the filepath
+	inner := &ChunkError{Stage: StageExtract, Language: LanguageGo, Err: ErrCancelled}
+	if err := stageError(inner, StageParse, "b.go", LanguageGo); err != inner || inner.Stage != StageExtract || inner.Filepath != "b.go" {
+		t.Errorf("Unexpected error %#v", err)
+	}
+}
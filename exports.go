@@ -0,0 +1,388 @@
+package codechunk
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// extractExports derives export entities for languages that express exports through
+// naming or visibility rules rather than export statements:
+//
+//   - Go: top-level identifiers starting with an upper-case letter
+//   - Python: names listed in __all__, or public module-level functions and classes
+//   - Rust: top-level items declared pub, and macros marked #[macro_export]
+//   - Java: public types and the public members of public types
+//
+// JavaScript and TypeScript exports come from export statements during extraction.
+func extractExports(rootNode *sitter.Node, lang Language, code []byte, entities []*ExtractedEntity) []*ExtractedEntity {
+	switch lang {
+	case LanguageGo:
+		return extractGoExports(rootNode, code, entitySignatures(entities))
+	case LanguagePython:
+		return extractPythonExports(rootNode, code, entities)
+	case LanguageRust:
+		return extractRustExports(rootNode, code, entitySignatures(entities))
+	case LanguageJava:
+		return extractJavaExports(rootNode, code, entitySignatures(entities), nil)
+	default:
+		return nil
+	}
+}
+
+// createExportEntity creates an export entity for a declaration node
+func createExportEntity(node *sitter.Node, name, signature string, parent *string) *ExtractedEntity {
+	return &ExtractedEntity{
+		Type:      EntityTypeExport,
+		Name:      name,
+		Signature: signature,
+		ByteRange: ByteRange{
+			Start: int(node.StartByte()),
+			End:   int(node.EndByte()),
+		},
+		LineRange: nodeLineRange(node),
+		Parent:    parent,
+		Node:      node,
+	}
+}
+
+// entitySignatures maps the byte range of each extracted entity to its signature,
+// keeping the first entity of a range, so each export looks its declaration up
+// once instead of scanning every entity
+func entitySignatures(entities []*ExtractedEntity) map[ByteRange]string {
+	signatures := make(map[ByteRange]string, len(entities))
+	for _, entity := range entities {
+		if _, exists := signatures[entity.ByteRange]; !exists {
+			signatures[entity.ByteRange] = entity.Signature
+		}
+	}
+	return signatures
+}
+
+// declarationSignature returns the signature of the entity extracted for node, as
+// entitySignatures maps them, or the node's first line if none was extracted
+func declarationSignature(node *sitter.Node, code []byte, signatures map[ByteRange]string) string {
+	if signature, ok := signatures[ByteRange{Start: int(node.StartByte()), End: int(node.EndByte())}]; ok {
+		return signature
+	}
+	text := node.Content(code)
+	if i := strings.Index(text, "\n"); i != -1 {
+		text = text[:i]
+	}
+	return cleanSignature(text)
+}
+
+// extractJSExportSymbols returns an export entity per name a JavaScript or
+// TypeScript export clause exports, such as export { A, B as C } from './x', with
+// the module it re-exports from as their Source. export * from './x' exports the
+// namespace named *. CommonJS export assignments are exported as
+// extractCommonJSExports describes. Returns nil for exports of declarations and
+// default exports.
+func extractJSExportSymbols(node *sitter.Node, lang Language, code []byte, parent *string) []*ExtractedEntity {
+	if (lang == LanguageJavaScript || lang == LanguageTypeScript) && node.Type() == "expression_statement" {
+		return extractCommonJSExports(node, code, parent)
+	}
+	if (lang != LanguageJavaScript && lang != LanguageTypeScript) || node.Type() != "export_statement" ||
+		node.ChildByFieldName("declaration") != nil || node.ChildByFieldName("value") != nil {
+		return nil
+	}
+	var source *string
+	if sourceNode := node.ChildByFieldName("source"); sourceNode != nil {
+		path := stripQuotes(sourceNode.Content(code))
+		source = &path
+	}
+	symbol := func(name string) *ExtractedEntity {
+		entity := createExportEntity(node, name, statementSignature(node, code), parent)
+		entity.Source = source
+		return entity
+	}
+
+	exports := make([]*ExtractedEntity, 0)
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		switch child.Type() {
+		case "*":
+			if source != nil {
+				entity := symbol("*")
+				entity.IsNamespace = true
+				exports = append(exports, entity)
+			}
+		case "namespace_export":
+			if nameNode := namespaceImportName(child); nameNode != nil {
+				entity := symbol(nameNode.Content(code))
+				entity.IsNamespace = true
+				exports = append(exports, entity)
+			}
+		case "export_clause":
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				spec := child.NamedChild(j)
+				if spec.Type() != "export_specifier" {
+					continue
+				}
+				name := extractImportSpecifierName(spec, code)
+				if name == "" {
+					continue
+				}
+				entity := symbol(stripQuotes(name))
+				// export { default as X } from './x' re-exports the default export of ./x
+				if original := spec.ChildByFieldName("name"); original != nil && spec.ChildByFieldName("alias") != nil {
+					if original.Content(code) == "default" {
+						entity.IsDefault = true
+					} else {
+						entity.OriginalName = stripQuotes(original.Content(code))
+					}
+				}
+				exports = append(exports, entity)
+			}
+		}
+	}
+	if len(exports) == 0 {
+		return nil
+	}
+	return exports
+}
+
+// isGoExported reports whether a Go identifier is exported
+func isGoExported(name string) bool {
+	r, _ := utf8.DecodeRuneInString(name)
+	return unicode.IsUpper(r)
+}
+
+func extractGoExports(rootNode *sitter.Node, code []byte, signatures map[ByteRange]string) []*ExtractedEntity {
+	exports := make([]*ExtractedEntity, 0)
+
+	for i := 0; i < int(rootNode.ChildCount()); i++ {
+		decl := rootNode.Child(i)
+		switch decl.Type() {
+		case "function_declaration", "method_declaration":
+			if nameNode := decl.ChildByFieldName("name"); nameNode != nil {
+				name := nameNode.Content(code)
+				if !isGoExported(name) {
+					continue
+				}
+				var parent *string
+				if receiver := goReceiverType(decl, code); receiver != "" {
+					parent = &receiver
+				}
+				exports = append(exports, createExportEntity(decl, name, declarationSignature(decl, code, signatures), parent))
+			}
+
+		case "type_declaration", "var_declaration", "const_declaration":
+			keyword := strings.TrimSuffix(decl.Type(), "_declaration")
+			for _, spec := range goDeclarationSpecs(decl) {
+				for j := 0; j < int(spec.ChildCount()); j++ {
+					if spec.FieldNameForChild(j) != "name" {
+						continue
+					}
+					name := spec.Child(j).Content(code)
+					if !isGoExported(name) {
+						continue
+					}
+					signature := declarationSignature(decl, code, signatures)
+					if decl.NamedChildCount() > 1 || !strings.HasPrefix(signature, keyword) {
+						signature = keyword + " " + declarationSignature(spec, code, nil)
+					}
+					exports = append(exports, createExportEntity(spec, name, signature, nil))
+				}
+			}
+		}
+	}
+
+	return exports
+}
+
+// goDeclarationSpecs returns the type/var/const specs of a declaration, including
+// those inside parenthesized groups
+func goDeclarationSpecs(decl *sitter.Node) []*sitter.Node {
+	specs := make([]*sitter.Node, 0)
+	for i := 0; i < int(decl.NamedChildCount()); i++ {
+		child := decl.NamedChild(i)
+		switch child.Type() {
+		case "type_spec", "type_alias", "var_spec", "const_spec":
+			specs = append(specs, child)
+		case "var_spec_list":
+			specs = append(specs, goDeclarationSpecs(child)...)
+		}
+	}
+	return specs
+}
+
+func extractPythonExports(rootNode *sitter.Node, code []byte, entities []*ExtractedEntity) []*ExtractedEntity {
+	exports := make([]*ExtractedEntity, 0)
+
+	topLevel := make(map[string]*ExtractedEntity)
+	for _, entity := range entities {
+		if entity.Parent == nil && (entity.Type == EntityTypeFunction || entity.Type == EntityTypeClass) {
+			if _, exists := topLevel[entity.Name]; !exists {
+				topLevel[entity.Name] = entity
+			}
+		}
+	}
+
+	// Names listed in __all__ define the public interface when present
+	hasAll := false
+	for i := 0; i < int(rootNode.ChildCount()); i++ {
+		stmt := rootNode.Child(i)
+		if stmt.Type() != "expression_statement" || stmt.NamedChildCount() == 0 {
+			continue
+		}
+		assign := stmt.NamedChild(0)
+		if assign.Type() != "assignment" && assign.Type() != "augmented_assignment" {
+			continue
+		}
+		left := assign.ChildByFieldName("left")
+		right := assign.ChildByFieldName("right")
+		if left == nil || right == nil || left.Content(code) != "__all__" {
+			continue
+		}
+		hasAll = true
+
+		for j := 0; j < int(right.NamedChildCount()); j++ {
+			item := right.NamedChild(j)
+			if item.Type() != "string" {
+				continue
+			}
+			name := stripQuotes(item.Content(code))
+			if entity, ok := topLevel[name]; ok {
+				export := createExportEntity(entity.Node, name, entity.Signature, nil)
+				exports = append(exports, export)
+			} else {
+				exports = append(exports, createExportEntity(stmt, name, name, nil))
+			}
+		}
+	}
+
+	if hasAll {
+		return exports
+	}
+
+	for _, entity := range entities {
+		if topLevel[entity.Name] == entity && !strings.HasPrefix(entity.Name, "_") {
+			exports = append(exports, createExportEntity(entity.Node, entity.Name, entity.Signature, nil))
+		}
+	}
+
+	return exports
+}
+
+func extractRustExports(rootNode *sitter.Node, code []byte, signatures map[ByteRange]string) []*ExtractedEntity {
+	exports := make([]*ExtractedEntity, 0)
+
+	for i := 0; i < int(rootNode.ChildCount()); i++ {
+		item := rootNode.Child(i)
+		if !hasRustPubVisibility(item, code) && !isRustMacroExported(item, code) {
+			continue
+		}
+		nameNode := item.ChildByFieldName("name")
+		if nameNode == nil {
+			continue
+		}
+		exports = append(exports, createExportEntity(item, nameNode.Content(code), declarationSignature(item, code, signatures), nil))
+	}
+
+	return exports
+}
+
+// hasRustPubVisibility reports whether an item is declared plain pub (not pub(crate) etc.)
+func hasRustPubVisibility(item *sitter.Node, code []byte) bool {
+	for i := 0; i < int(item.ChildCount()); i++ {
+		child := item.Child(i)
+		if child.Type() == "visibility_modifier" {
+			return child.Content(code) == "pub"
+		}
+	}
+	return false
+}
+
+// isRustMacroExported reports whether an item is a macro_rules! macro exported
+// from its crate by #[macro_export]
+func isRustMacroExported(item *sitter.Node, code []byte) bool {
+	if item.Type() != "macro_definition" {
+		return false
+	}
+	for _, annotation := range entityAnnotations(item, LanguageRust, code) {
+		if annotation == "#[macro_export]" {
+			return true
+		}
+	}
+	return false
+}
+
+// javaTypeDeclarations are Java node types that declare types
+var javaTypeDeclarations = map[string]bool{
+	"class_declaration":     true,
+	"interface_declaration": true,
+	"enum_declaration":      true,
+	"record_declaration":    true,
+}
+
+func extractJavaExports(node *sitter.Node, code []byte, signatures map[ByteRange]string, parent *string) []*ExtractedEntity {
+	exports := make([]*ExtractedEntity, 0)
+
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		childType := child.Type()
+
+		switch {
+		case javaTypeDeclarations[childType]:
+			if !hasJavaModifier(child, "public", code) {
+				continue
+			}
+			nameNode := child.ChildByFieldName("name")
+			if nameNode == nil {
+				continue
+			}
+			name := nameNode.Content(code)
+			exports = append(exports, createExportEntity(child, name, declarationSignature(child, code, signatures), parent))
+			if body := child.ChildByFieldName("body"); body != nil {
+				exports = append(exports, extractJavaExports(body, code, signatures, &name)...)
+			}
+
+		case parent != nil && (childType == "method_declaration" || childType == "constructor_declaration" || childType == "compact_constructor_declaration"):
+			if !hasJavaModifier(child, "public", code) {
+				continue
+			}
+			if nameNode := child.ChildByFieldName("name"); nameNode != nil {
+				exports = append(exports, createExportEntity(child, nameNode.Content(code), declarationSignature(child, code, signatures), parent))
+			}
+
+		case parent != nil && childType == "field_declaration":
+			if !hasJavaModifier(child, "public", code) {
+				continue
+			}
+			for j := 0; j < int(child.NamedChildCount()); j++ {
+				declarator := child.NamedChild(j)
+				if declarator.Type() != "variable_declarator" {
+					continue
+				}
+				if nameNode := declarator.ChildByFieldName("name"); nameNode != nil {
+					exports = append(exports, createExportEntity(child, nameNode.Content(code), declarationSignature(child, code, nil), parent))
+				}
+			}
+
+		case parent == nil && childType != "import_declaration":
+			// Descend through wrappers such as package-level program structure
+			exports = append(exports, extractJavaExports(child, code, signatures, nil)...)
+		}
+	}
+
+	return exports
+}
+
+// hasJavaModifier reports whether a declaration has the given modifier keyword
+func hasJavaModifier(decl *sitter.Node, modifier string, code []byte) bool {
+	for i := 0; i < int(decl.ChildCount()); i++ {
+		child := decl.Child(i)
+		if child.Type() != "modifiers" {
+			continue
+		}
+		for j := 0; j < int(child.ChildCount()); j++ {
+			if child.Child(j).Content(code) == modifier {
+				return true
+			}
+		}
+	}
+	return false
+}
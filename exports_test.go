@@ -0,0 +1,225 @@
+package codechunk
+
+import (
+	"testing"
+)
+
+func extractExportsFromString(t *testing.T, code string, lang Language) []*ExtractedEntity {
+	t.Helper()
+	parseResult, err := parseString(code, lang)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	root := parseResult.Tree.RootNode()
+	entities := extractEntities(root, lang, []byte(code))
+	return extractExports(root, lang, []byte(code), entities)
+}
+
+func exportNames(exports []*ExtractedEntity) []string {
+	names := make([]string, len(exports))
+	for i, e := range exports {
+		names[i] = e.Name
+	}
+	return names
+}
+
+func assertExportNames(t *testing.T, exports []*ExtractedEntity, want []string) {
+	t.Helper()
+	got := exportNames(exports)
+	if len(got) != len(want) {
+		t.Fatalf("Expected exports %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected exports %v, got %v", want, got)
+			break
+		}
+	}
+	for _, e := range exports {
+		if e.Type != EntityTypeExport {
+			t.Errorf("Expected export %q to have type export, got %s", e.Name, e.Type)
+		}
+	}
+}
+
+func TestExtractExportsGo(t *testing.T) {
+	code := `package store
+
+type Store struct{}
+
+type handle int
+
+const (
+	MaxSize = 10
+	minSize = 1
+)
+
+var DefaultStore, fallback = New(), New()
+
+func New() *Store { return &Store{} }
+
+func helper() {}
+
+func (s *Store) Get(key string) string { return key }
+`
+	exports := extractExportsFromString(t, code, LanguageGo)
+	assertExportNames(t, exports, []string{"Store", "MaxSize", "DefaultStore", "New", "Get"})
+
+	if exports[0].Signature != "type Store struct" {
+		t.Errorf("Unexpected Store signature: %q", exports[0].Signature)
+	}
+	if exports[1].Signature != "const MaxSize = 10" {
+		t.Errorf("Unexpected MaxSize signature: %q", exports[1].Signature)
+	}
+	if exports[4].Parent == nil || *exports[4].Parent != "Store" {
+		t.Errorf("Expected Get to belong to Store, got %v", exports[4].Parent)
+	}
+}
+
+func TestExtractExportsPythonAll(t *testing.T) {
+	code := `__all__ = ["load", "Config"]
+__all__ += ["VERSION"]
+
+VERSION = "1.0"
+
+class Config:
+    pass
+
+def load(path):
+    return Config()
+
+def dump(config):
+    pass
+`
+	exports := extractExportsFromString(t, code, LanguagePython)
+	assertExportNames(t, exports, []string{"load", "Config", "VERSION"})
+
+	if exports[0].Signature != "def load(path)" {
+		t.Errorf("Unexpected load signature: %q", exports[0].Signature)
+	}
+}
+
+func TestExtractExportsPythonModuleLevel(t *testing.T) {
+	code := `class Config:
+    def method(self):
+        pass
+
+@cached
+def load(path):
+    pass
+
+def _private():
+    pass
+`
+	exports := extractExportsFromString(t, code, LanguagePython)
+	assertExportNames(t, exports, []string{"Config", "load"})
+}
+
+func TestExtractExportsRust(t *testing.T) {
+	code := `pub struct Point {
+    x: i32,
+}
+
+pub(crate) fn internal() {}
+
+fn private() {}
+
+pub fn distance(a: &Point, b: &Point) -> f64 {
+    0.0
+}
+
+pub trait Shape {}
+`
+	exports := extractExportsFromString(t, code, LanguageRust)
+	assertExportNames(t, exports, []string{"Point", "distance", "Shape"})
+}
+
+func TestExtractExportsJava(t *testing.T) {
+	code := `package com.example;
+
+public class Service {
+    public static final int LIMIT = 5;
+    private int count;
+
+    public Service() {}
+
+    public void run() {}
+
+    void internal() {}
+}
+
+class Hidden {
+    public void visible() {}
+}
+`
+	exports := extractExportsFromString(t, code, LanguageJava)
+	assertExportNames(t, exports, []string{"Service", "LIMIT", "Service", "run"})
+
+	if exports[0].Parent != nil {
+		t.Errorf("Expected Service class to have no parent, got %q", *exports[0].Parent)
+	}
+	if exports[3].Parent == nil || *exports[3].Parent != "Service" {
+		t.Error("Expected run to have parent Service")
+	}
+}
+
+func TestExtractExportsUnsupportedLanguage(t *testing.T) {
+	code := `export function foo() {}`
+	if exports := extractExportsFromString(t, code, LanguageTypeScript); len(exports) != 0 {
+		t.Errorf("Expected no derived exports for TypeScript, got %v", exportNames(exports))
+	}
+}
+
+func TestParseAndExtractDerivesExports(t *testing.T) {
+	code := []byte("package main\n\nfunc Exported() {}\n\nfunc internal() {}\n")
+	parsed, err := parseAndExtract(code, LanguageGo, nil)
+	if err != nil {
+		t.Fatalf("parseAndExtract failed: %v", err)
+	}
+	assertExportNames(t, parsed.Exports, []string{"Exported"})
+}
+
+func TestExtractJSExportSymbols(t *testing.T) {
+	code := `export * from './module';
+export * as utils from './utils';
+export { A, B as C, default as D } from './x';
+export { local };
+export const value = 1;
+`
+	parseResult, err := parseString(code, LanguageTypeScript)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	var exports []*ExtractedEntity
+	for _, e := range extractEntities(parseResult.Tree.RootNode(), LanguageTypeScript, []byte(code)) {
+		if e.Type == EntityTypeExport {
+			exports = append(exports, e)
+		}
+	}
+	assertExportNames(t, exports, []string{"*", "utils", "A", "C", "D", "local", anonymousName})
+
+	expected := []struct {
+		source, originalName string
+		isDefault, namespace bool
+	}{
+		{"./module", "", false, true},
+		{"./utils", "", false, true},
+		{"./x", "", false, false},
+		{"./x", "B", false, false},
+		{"./x", "", true, false},
+		{"", "", false, false},
+	}
+	for i, want := range expected {
+		e := exports[i]
+		source := ""
+		if e.Source != nil {
+			source = *e.Source
+		}
+		if source != want.source || e.OriginalName != want.originalName || e.IsDefault != want.isDefault || e.IsNamespace != want.namespace {
+			t.Errorf("%s: expected %+v, got source %q, original %q, default %v, namespace %v", e.Name, want, source, e.OriginalName, e.IsDefault, e.IsNamespace)
+		}
+	}
+	if exports[2].Signature != "export { A, B as C, default as D } from './x'" {
+		t.Errorf("Expected the export clause as signature, got %q", exports[2].Signature)
+	}
+}
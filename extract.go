@@ -1,6 +1,7 @@
 package codechunk
 
 import (
+	"context"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -16,6 +17,8 @@ var EntityNodeTypes = map[Language][]string{
 		"interface_declaration",
 		"type_alias_declaration",
 		"enum_declaration",
+		"internal_module",
+		"module",
 		"import_statement",
 		"export_statement",
 	},
@@ -40,6 +43,8 @@ var EntityNodeTypes = map[Language][]string{
 		"enum_item",
 		"trait_item",
 		"type_item",
+		"mod_item",
+		"macro_definition",
 		"use_declaration",
 	},
 	LanguageGo: {
@@ -51,11 +56,33 @@ var EntityNodeTypes = map[Language][]string{
 	LanguageJava: {
 		"method_declaration",
 		"constructor_declaration",
+		"compact_constructor_declaration",
 		"class_declaration",
+		"record_declaration",
 		"interface_declaration",
 		"enum_declaration",
+		"package_declaration",
 		"import_declaration",
 	},
+	LanguageSQL: {
+		"create_table",
+		"create_view",
+		"create_materialized_view",
+		"create_function",
+	},
+	LanguageHCL: {
+		"block",
+	},
+	LanguageLua: {
+		"function_statement",
+	},
+	LanguageProto: {
+		"message",
+		"enum",
+		"service",
+		"rpc",
+		"import",
+	},
 }
 
 // NodeTypeToEntityType maps AST node types to entity types
@@ -66,22 +93,42 @@ var NodeTypeToEntityType = map[string]EntityType{
 	"function_item":                  EntityTypeFunction,
 	"generator_function_declaration": EntityTypeFunction,
 	"arrow_function":                 EntityTypeFunction,
+	"function_statement":             EntityTypeFunction,
+	"macro_definition":               EntityTypeFunction,
 
 	// Methods
-	"method_definition":       EntityTypeMethod,
-	"method_declaration":      EntityTypeMethod,
-	"constructor_declaration": EntityTypeMethod,
+	"method_definition":               EntityTypeMethod,
+	"method_declaration":              EntityTypeMethod,
+	"constructor_declaration":         EntityTypeMethod,
+	"compact_constructor_declaration": EntityTypeMethod,
 
 	// Classes
 	"class_declaration":          EntityTypeClass,
 	"class_definition":           EntityTypeClass,
 	"abstract_class_declaration": EntityTypeClass,
 	"impl_item":                  EntityTypeClass,
+	"record_declaration":         EntityTypeClass,
 
 	// Interfaces
 	"interface_declaration": EntityTypeInterface,
 	"trait_item":            EntityTypeInterface,
 
+	// SQL definitions
+	"create_function":          EntityTypeFunction,
+	"create_table":             EntityTypeType,
+	"create_view":              EntityTypeType,
+	"create_materialized_view": EntityTypeType,
+
+	// Configuration blocks
+	"block": EntityTypeBlock,
+
+	// Protocol buffer definitions
+	"message": EntityTypeClass,
+	"service": EntityTypeInterface,
+	"rpc":     EntityTypeMethod,
+	"enum":    EntityTypeEnum,
+	"import":  EntityTypeImport,
+
 	// Types
 	"type_alias_declaration": EntityTypeType,
 	"type_item":              EntityTypeType,
@@ -92,6 +139,12 @@ var NodeTypeToEntityType = map[string]EntityType{
 	"enum_declaration": EntityTypeEnum,
 	"enum_item":        EntityTypeEnum,
 
+	// Namespaces
+	"internal_module":     EntityTypeNamespace,
+	"module":              EntityTypeNamespace,
+	"mod_item":            EntityTypeNamespace,
+	"package_declaration": EntityTypeNamespace,
+
 	// Imports
 	"import_statement":      EntityTypeImport,
 	"import_declaration":    EntityTypeImport,
@@ -116,6 +169,126 @@ func isEntityNodeType(nodeType string, lang Language) bool {
 	return false
 }
 
+// elixirDefinitions maps the Elixir macros that define entities, which are plain
+// calls in the syntax tree, to entity types
+var elixirDefinitions = map[string]EntityType{
+	"defmodule":   EntityTypeClass,
+	"defimpl":     EntityTypeClass,
+	"defprotocol": EntityTypeInterface,
+	"def":         EntityTypeFunction,
+	"defp":        EntityTypeFunction,
+	"defmacro":    EntityTypeFunction,
+	"defmacrop":   EntityTypeFunction,
+	"defguard":    EntityTypeFunction,
+	"defguardp":   EntityTypeFunction,
+	"defdelegate": EntityTypeFunction,
+	"alias":       EntityTypeImport,
+	"import":      EntityTypeImport,
+	"require":     EntityTypeImport,
+	"use":         EntityTypeImport,
+}
+
+// nodeEntityType reports whether a node is an entity for the given language and
+// returns its entity type, which is empty if it cannot be determined
+func nodeEntityType(node *sitter.Node, lang Language, code []byte) (EntityType, bool) {
+	if lang == LanguageElixir {
+		if node.Type() != "call" {
+			return "", false
+		}
+		entityType, ok := elixirDefinitions[elixirCallTarget(node, code)]
+		return entityType, ok
+	}
+	if lang == LanguageGo && node.Type() == "func_literal" {
+		// Closures are entities when assigned to a variable
+		if name, _ := goClosureBinding(node, code); name != "" {
+			return EntityTypeFunction, true
+		}
+		return "", false
+	}
+	if lang == LanguageJavaScript || lang == LanguageTypeScript {
+		// CommonJS modules load modules with require and export by assignment
+		if isJSRequire(node, code) {
+			return EntityTypeImport, true
+		}
+		if assignment, _ := commonJSExportTarget(node, code); assignment != nil {
+			return EntityTypeExport, true
+		}
+
+		// Function and class expressions are entities when bound to a name or
+		// default-exported
+		if entityType, ok := jsExpressionEntities[node.Type()]; ok && node.IsNamed() {
+			if name, defaultExport := jsBinding(node, code); name != "" || defaultExport {
+				return entityType, true
+			}
+			return "", false
+		}
+	}
+	if lang == LanguageTypeScript {
+		// Overload signatures are folded into the declaration they overload, and a
+		// function declared without a body, as in a declaration file, is an entity
+		switch node.Type() {
+		case "function_signature":
+			if isTSOverloadSignature(node, code) {
+				return "", false
+			}
+			return EntityTypeFunction, true
+		case "export_statement":
+			if decl := node.ChildByFieldName("declaration"); decl != nil && isTSOverloadSignature(decl, code) {
+				return "", false
+			}
+		}
+	}
+	if lang == LanguagePython && node.Type() == "function_definition" {
+		return pythonFunctionType(node, code), true
+	}
+	if lang == LanguageRust && node.Type() == "mod_item" && node.ChildByFieldName("body") == nil {
+		// mod name; declares a module kept in another file
+		return "", false
+	}
+	if lang == LanguageLua {
+		switch {
+		case node.Type() == "function_statement" && luaIsMethod(node):
+			return EntityTypeMethod, true
+		case (node.Type() == "variable_declaration" || node.Type() == "function_call") && luaRequiredModule(node, code) != "":
+			return EntityTypeImport, true
+		}
+	}
+
+	// Keywords can share the type name of the definition they introduce (a
+	// protobuf message starts with "message"), so only named nodes are entities
+	if !node.IsNamed() || !isEntityNodeType(node.Type(), lang) {
+		return "", false
+	}
+	entityType, ok := getEntityType(node.Type())
+	if !ok {
+		entityType = inferEntityType(node.Type())
+	}
+	return entityType, true
+}
+
+// elixirCallTarget returns the name of the local function or macro an Elixir call
+// invokes, or "" for remote calls such as Repo.get(id)
+func elixirCallTarget(node *sitter.Node, code []byte) string {
+	if target := node.ChildByFieldName("target"); target != nil && target.Type() == "identifier" {
+		return target.Content(code)
+	}
+	return ""
+}
+
+// elixirDefinitionHead returns the head of an Elixir definition: the module alias
+// of defmodule, or the call, identifier or guarded call naming a function
+func elixirDefinitionHead(node *sitter.Node) *sitter.Node {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if args := node.NamedChild(i); args.Type() == "arguments" {
+			if args.NamedChildCount() == 0 {
+				return nil
+			}
+			return args.NamedChild(0)
+		}
+	}
+	return nil
+}
+
 // getEntityType gets EntityType from node type string
 func getEntityType(nodeType string) (EntityType, bool) {
 	entityType, ok := NodeTypeToEntityType[nodeType]
@@ -124,12 +297,31 @@ func getEntityType(nodeType string) (EntityType, bool) {
 
 // extractEntities extracts entities from an AST tree
 func extractEntities(rootNode *sitter.Node, lang Language, code []byte) []*ExtractedEntity {
+	entities, _ := extractEntitiesContext(context.Background(), rootNode, lang, code)
+	return entities
+}
+
+// extractEntitiesContext is extractEntities stopping with the context's error once
+// ctx is done
+func extractEntitiesContext(ctx context.Context, rootNode *sitter.Node, lang Language, code []byte) ([]*ExtractedEntity, error) {
 	entities := make([]*ExtractedEntity, 0)
 	processedNodes := make(map[uintptr]bool)
 
-	walkAndExtract(rootNode, lang, code, nil, &entities, processedNodes)
+	walkAndExtract(ctx, rootNode, lang, code, nil, &entities, processedNodes)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-	return entities
+	if lang == LanguageLua {
+		for _, entity := range entities {
+			trimLeadingNewlines(entity, code)
+		}
+	}
+	if lang == LanguageGo {
+		constrainReceiverTypeParameters(entities)
+	}
+
+	return entities, nil
 }
 
 // stackItem represents an item in the traversal stack
@@ -138,11 +330,20 @@ type stackItem struct {
 	parentName *string
 }
 
-// walkAndExtract walks the AST iteratively and extracts entities
-func walkAndExtract(rootNode *sitter.Node, lang Language, code []byte, parentName *string, entities *[]*ExtractedEntity, processedNodes map[uintptr]bool) {
+// cancelCheckInterval is the number of nodes visited between checks of the
+// context while walking a syntax tree
+const cancelCheckInterval = 1024
+
+// walkAndExtract walks the AST iteratively and extracts entities, stopping once
+// ctx is done
+func walkAndExtract(ctx context.Context, rootNode *sitter.Node, lang Language, code []byte, parentName *string, entities *[]*ExtractedEntity, processedNodes map[uintptr]bool) {
 	stack := []stackItem{{node: rootNode, parentName: parentName}}
 
-	for len(stack) > 0 {
+	for visited := 1; len(stack) > 0; visited++ {
+		if visited%cancelCheckInterval == 0 && ctx.Err() != nil {
+			return
+		}
+
 		// Pop from stack
 		current := stack[len(stack)-1]
 		stack = stack[:len(stack)-1]
@@ -155,30 +356,38 @@ func walkAndExtract(rootNode *sitter.Node, lang Language, code []byte, parentNam
 		nodePtr := node.ID()
 
 		// Check if this node is an entity type
-		if isEntityNodeType(node.Type(), lang) {
+		if entityType, ok := nodeEntityType(node, lang, code); ok {
 			// Skip if already processed
 			if processedNodes[nodePtr] {
 				continue
 			}
 			processedNodes[nodePtr] = true
 
-			entityType, ok := getEntityType(node.Type())
-			if !ok {
-				entityType = inferEntityType(node.Type())
-				if entityType == "" {
-					continue
-				}
+			if entityType == "" {
+				continue
 			}
 
 			// For import statements, extract individual symbols
 			if entityType == EntityTypeImport {
 				importEntities := extractImportSymbols(node, lang, code)
 				*entities = append(*entities, importEntities...)
+			} else if exportEntities := extractJSExportSymbols(node, lang, code, current.parentName); exportEntities != nil {
+				// Export clauses export a name each, and CommonJS exports assign the
+				// values they export, which may be entities themselves
+				*entities = append(*entities, exportEntities...)
+				for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+					if child := node.Child(i); child != nil {
+						stack = append(stack, stackItem{node: child, parentName: current.parentName})
+					}
+				}
 			} else {
 				// Extract name
 				name := extractNameFromCode(node, code, lang)
+				var boundName string
+				var defaultExport bool
 				if name == "" {
-					name = "<anonymous>"
+					name = anonymousName
+					boundName, defaultExport = anonymousNaming(node, lang, code)
 				}
 
 				// Extract signature
@@ -190,22 +399,60 @@ func walkAndExtract(rootNode *sitter.Node, lang Language, code []byte, parentNam
 				// Extract docstring
 				docstring := extractDocstring(node, lang, code)
 
+				// Overloads document the function more often than its implementation
+				overloads, firstOverload := tsOverloads(node, lang, code)
+				if docstring == nil && firstOverload != nil {
+					docstring = extractDocstring(firstOverload, lang, code)
+				}
+
+				annotations := entityAnnotations(node, lang, code)
+				params, returns := entityParameters(node, entityType, lang, code)
+
+				// Go methods belong to their receiver type rather than an enclosing scope
+				parent := current.parentName
+				if receiver := goReceiverType(node, code); receiver != "" {
+					parent = &receiver
+				}
+
+				byteRange, lineRange := entityRange(node, lang)
+				if firstOverload != nil {
+					byteRange.Start = int(firstOverload.StartByte())
+					lineRange.Start = nodeLineRange(firstOverload).Start
+				}
+				if entityType == EntityTypeExport {
+					overloads = nil
+				}
+
+				var propsType string
+				componentName := name
+				if boundName != "" {
+					componentName = boundName
+				}
+				if isReactComponent(node, entityType, componentName, defaultExport, lang, code) {
+					entityType = EntityTypeComponent
+					propsType = componentPropsType(node, params, code)
+				}
+
 				// Create entity
 				entity := &ExtractedEntity{
-					Type:      entityType,
-					Name:      name,
-					Signature: signature,
-					Docstring: docstring,
-					ByteRange: ByteRange{
-						Start: int(node.StartByte()),
-						End:   int(node.EndByte()),
-					},
-					LineRange: LineRange{
-						Start: int(node.StartPoint().Row),
-						End:   int(node.EndPoint().Row),
-					},
-					Parent: current.parentName,
-					Node:   node,
+					Type:           entityType,
+					Name:           name,
+					Signature:      signature,
+					Docstring:      docstring,
+					ByteRange:      byteRange,
+					LineRange:      lineRange,
+					Parent:         parent,
+					Node:           node,
+					Visibility:     entityVisibility(node, lang, name, code),
+					Annotations:    annotations,
+					Modifiers:      entityModifiers(node, lang, annotations, code),
+					Parameters:     params,
+					ReturnType:     returns,
+					TypeParameters: entityTypeParameters(node, code),
+					PropsType:      propsType,
+					Overloads:      overloads,
+					boundName:      boundName,
+					defaultExport:  defaultExport,
 				}
 
 				*entities = append(*entities, entity)
@@ -214,9 +461,13 @@ func walkAndExtract(rootNode *sitter.Node, lang Language, code []byte, parentNam
 				var newParentName *string
 				if entityType == EntityTypeClass ||
 					entityType == EntityTypeInterface ||
+					entityType == EntityTypeBlock ||
 					entityType == EntityTypeFunction ||
-					entityType == EntityTypeMethod {
-					newParentName = &name
+					entityType == EntityTypeMethod ||
+					entityType == EntityTypeProperty ||
+					entityType == EntityTypeNamespace ||
+					entityType == EntityTypeComponent {
+					newParentName = &entity.Name
 				} else {
 					newParentName = current.parentName
 				}
@@ -277,6 +528,70 @@ var nameNodeTypes = []string{
 
 // extractNameFromCode extracts the name using the source code
 func extractNameFromCode(node *sitter.Node, code []byte, lang Language) string {
+	// SQL objects are named by a possibly schema-qualified object reference
+	if lang == LanguageSQL {
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if child := node.NamedChild(i); child.Type() == "object_reference" {
+				return child.Content(code)
+			}
+		}
+	}
+	switch lang {
+	case LanguageHCL:
+		return hclBlockName(node, code)
+	case LanguageElixir:
+		// Modules are named by their alias and functions by the call in their head,
+		// which may be guarded (def f(x) when is_map(x))
+		head := elixirDefinitionHead(node)
+		if head != nil && head.Type() == "binary_operator" {
+			head = head.ChildByFieldName("left")
+		}
+		if head != nil && head.Type() == "call" {
+			head = head.ChildByFieldName("target")
+		}
+		if head == nil {
+			return ""
+		}
+		return head.Content(code)
+	case LanguageGo:
+		switch node.Type() {
+		case "func_literal":
+			name, _ := goClosureBinding(node, code)
+			return name
+		case "type_declaration":
+			// Named by the first spec of a possibly grouped declaration
+			for _, spec := range goDeclarationSpecs(node) {
+				if nameNode := spec.ChildByFieldName("name"); nameNode != nil {
+					return nameNode.Content(code)
+				}
+			}
+		}
+	case LanguageJava:
+		if node.Type() == "package_declaration" && node.NamedChildCount() > 0 {
+			return node.NamedChild(0).Content(code)
+		}
+	case LanguageJavaScript, LanguageTypeScript:
+		// Function and class expressions are named only by their optional name, not
+		// by the parameter of an arrow function such as x => x
+		if _, ok := jsExpressionEntities[node.Type()]; ok {
+			if nameNode := node.ChildByFieldName("name"); nameNode != nil {
+				return nameNode.Content(code)
+			}
+			return ""
+		}
+		// Ambient modules are named by a string: declare module "express"
+		if nameNode := node.ChildByFieldName("name"); node.Type() == "module" && nameNode != nil && nameNode.Type() == "string" {
+			return stripQuotes(nameNode.Content(code))
+		}
+	case LanguageProto:
+		// Definitions are named by a message_name, enum_name, service_name or rpc_name
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if child := node.NamedChild(i); strings.HasSuffix(child.Type(), "_name") {
+				return child.Content(code)
+			}
+		}
+	}
+
 	// Try to find a named child that is an identifier
 	for _, nameType := range nameNodeTypes {
 		if nameNode := node.ChildByFieldName(nameType); nameNode != nil {
@@ -304,3 +619,183 @@ func extractNameFromCode(node *sitter.Node, code []byte, lang Language) string {
 
 	return ""
 }
+
+// hclBlockName names an HCL block by its labels joined with dots, as Terraform
+// addresses it (resource "aws_vpc" "main" is aws_vpc.main), or by its type when
+// it has no labels (locals, terraform, nested blocks)
+func hclBlockName(node *sitter.Node, code []byte) string {
+	var blockType string
+	labels := make([]string, 0, 2)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "identifier":
+			if blockType == "" {
+				blockType = child.Content(code)
+			} else {
+				labels = append(labels, child.Content(code))
+			}
+		case "string_lit":
+			labels = append(labels, stripQuotes(child.Content(code)))
+		}
+	}
+	if len(labels) == 0 {
+		return blockType
+	}
+	return strings.Join(labels, ".")
+}
+
+// entityRange returns the byte and line range of an entity. A Java package
+// declaration is a single statement, but the package it names spans the rest of
+// the file, so its range runs to the end of the file for the scope tree to nest
+// the file's types in it.
+func entityRange(node *sitter.Node, lang Language) (ByteRange, LineRange) {
+	byteRange := ByteRange{Start: int(node.StartByte()), End: int(node.EndByte())}
+	lineRange := nodeLineRange(node)
+	if lang == LanguageJava && node.Type() == "package_declaration" {
+		if file := node.Parent(); file != nil {
+			byteRange.End = int(file.EndByte())
+			lineRange.End = nodeLineRange(file).End
+		}
+	}
+	return byteRange, lineRange
+}
+
+// goClosureBinding returns the variable a Go function literal is assigned to, in
+// a short variable declaration, var declaration or assignment, and the text that
+// binds it ("handler := "), or "" if the literal is not assigned
+func goClosureBinding(node *sitter.Node, code []byte) (name, binding string) {
+	values := node.Parent()
+	if values == nil || values.Type() != "expression_list" {
+		return "", ""
+	}
+	index := -1
+	for i := 0; i < int(values.NamedChildCount()); i++ {
+		if values.NamedChild(i).Equal(node) {
+			index = i
+		}
+	}
+
+	decl := values.Parent()
+	if decl == nil || index < 0 {
+		return "", ""
+	}
+	switch decl.Type() {
+	case "short_var_declaration", "assignment_statement":
+		left := decl.ChildByFieldName("left")
+		if left == nil || index >= int(left.NamedChildCount()) {
+			return "", ""
+		}
+		name = left.NamedChild(index).Content(code)
+		operator := ":="
+		if op := decl.ChildByFieldName("operator"); op != nil {
+			operator = op.Content(code)
+		} else if decl.Type() == "assignment_statement" {
+			operator = "="
+		}
+		return name, name + " " + operator + " "
+	case "var_spec":
+		names := make([]string, 0, 1)
+		for i := 0; i < int(decl.ChildCount()); i++ {
+			if decl.FieldNameForChild(i) == "name" {
+				names = append(names, decl.Child(i).Content(code))
+			}
+		}
+		if index >= len(names) {
+			return "", ""
+		}
+		return names[index], "var " + names[index] + " = "
+	}
+	return "", ""
+}
+
+// goReceiverType returns the name of the type a Go method is declared on, without
+// the pointer or the type arguments of a generic receiver ("User" for
+// "(u *User)" and "Pair" for "(p Pair[K, V])"), or "" for other nodes
+func goReceiverType(node *sitter.Node, code []byte) string {
+	if node.Type() != "method_declaration" {
+		return ""
+	}
+	receiver := node.ChildByFieldName("receiver")
+	if receiver == nil {
+		return ""
+	}
+	params := childrenOfType(receiver, "parameter_declaration")
+	if len(params) == 0 {
+		return ""
+	}
+
+	typeNode := params[0].ChildByFieldName("type")
+	for typeNode != nil {
+		switch typeNode.Type() {
+		case "pointer_type", "parenthesized_type":
+			typeNode = typeNode.NamedChild(0)
+		case "generic_type":
+			typeNode = typeNode.ChildByFieldName("type")
+		case "type_identifier":
+			return typeNode.Content(code)
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// luaIsMethod reports whether a Lua function statement defines a method
+// (function obj:method()), which receives self
+func luaIsMethod(node *sitter.Node) bool {
+	name := node.ChildByFieldName("name")
+	if name == nil {
+		return false
+	}
+	for i := 0; i < int(name.ChildCount()); i++ {
+		if name.Child(i).Type() == "table_colon" {
+			return true
+		}
+	}
+	return false
+}
+
+// luaRequiredModule returns the module loaded by a Lua require call, or by the
+// require call assigned in a variable declaration (local json = require("cjson")),
+// or "" if node is not a require
+func luaRequiredModule(node *sitter.Node, code []byte) string {
+	call := node
+	if node.Type() == "variable_declaration" {
+		call = node.ChildByFieldName("value")
+	}
+	if call == nil || call.Type() != "function_call" {
+		return ""
+	}
+	prefix := call.ChildByFieldName("prefix")
+	if prefix == nil || strings.TrimSpace(prefix.Content(code)) != "require" {
+		return ""
+	}
+	args := call.ChildByFieldName("args")
+	if args == nil {
+		return ""
+	}
+	if args.Type() == "string_argument" {
+		if content := args.ChildByFieldName("content"); content != nil {
+			return content.Content(code)
+		}
+		return ""
+	}
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		if arg := args.NamedChild(i); arg.Type() == "string" {
+			if content := arg.ChildByFieldName("content"); content != nil {
+				return content.Content(code)
+			}
+		}
+	}
+	return ""
+}
+
+// trimLeadingNewlines moves the start of an entity past leading newlines, which
+// the Lua grammar includes in keyword tokens such as "local" and "function"
+func trimLeadingNewlines(entity *ExtractedEntity, code []byte) {
+	for entity.ByteRange.Start < entity.ByteRange.End && code[entity.ByteRange.Start] == '\n' {
+		entity.ByteRange.Start++
+		entity.LineRange.Start++
+	}
+}
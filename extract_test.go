@@ -1,6 +1,8 @@
 package codechunk
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -255,6 +257,64 @@ trait Drawable {
 	}
 }
 
+func TestExtractEntitiesRustMacros(t *testing.T) {
+	code := `/// Builds a HashMap from key => value pairs.
+#[macro_export]
+macro_rules! hashmap {
+    ($($k:expr => $v:expr),*) => {{ HashMap::new() }};
+}
+
+macro_rules! internal {
+    () => {};
+}
+
+/// Configuration of the server.
+#[derive(Debug, Clone)]
+pub struct Config {
+    port: u16,
+}
+`
+	parseResult, err := parseString(code, LanguageRust)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageRust, []byte(code))
+
+	expected := []struct {
+		name, signature, docstring string
+		visibility                 Visibility
+	}{
+		{"hashmap", "macro_rules! hashmap", "Builds a HashMap from key => value pairs.", VisibilityPublic},
+		{"internal", "macro_rules! internal", "", VisibilityPrivate},
+		{"Config", "pub struct Config", "Configuration of the server.", VisibilityPublic},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		docstring := ""
+		if e.Docstring != nil {
+			docstring = *e.Docstring
+		}
+		if e.Name != want.name || e.Signature != want.signature || docstring != want.docstring || e.Visibility != want.visibility {
+			t.Errorf("Expected %s %q documented %q, got %s %q documented %q", want.visibility, want.signature, want.docstring, e.Visibility, e.Signature, docstring)
+		}
+	}
+	if entities[0].Type != EntityTypeFunction {
+		t.Errorf("Expected macro_rules! to be a function, got %s", entities[0].Type)
+	}
+
+	exports := extractExports(parseResult.Tree.RootNode(), LanguageRust, []byte(code), entities)
+	var exported []string
+	for _, e := range exports {
+		exported = append(exported, e.Name)
+	}
+	if strings.Join(exported, ",") != "hashmap,Config" {
+		t.Errorf("Expected hashmap and Config to be exported, got %v", exported)
+	}
+}
+
 func TestExtractEntitiesJava(t *testing.T) {
 	code := `
 package com.example;
@@ -312,6 +372,62 @@ enum Status {
 	}
 }
 
+func TestExtractEntitiesJavaRecordsAndSealed(t *testing.T) {
+	code := `public record Point(int x, int y) implements Shape {
+    public Point {
+        if (x < 0) throw new IllegalArgumentException();
+    }
+
+    public double length() { return Math.hypot(x, y); }
+}
+
+public sealed interface Shape permits Point, Base {}
+
+public abstract sealed class Base implements Shape permits Circle {
+    static class Inner {
+        class Deeper {
+            void run() {}
+        }
+    }
+}
+`
+	parseResult, err := parseString(code, LanguageJava)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageJava, []byte(code))
+
+	var got []string
+	for _, e := range entities {
+		parent := ""
+		if e.Parent != nil {
+			parent = " in " + *e.Parent
+		}
+		got = append(got, fmt.Sprintf("%s %s%s: %s", e.Type, e.Name, parent, e.Signature))
+	}
+	want := []string{
+		"class Point: public record Point(int x, int y) implements Shape",
+		"method Point in Point: public Point",
+		"method length in Point: public double length()",
+		"interface Shape: public sealed interface Shape permits Point, Base",
+		"class Base: public abstract sealed class Base implements Shape permits Circle",
+		"class Inner in Base: static class Inner",
+		"class Deeper in Inner: class Deeper",
+		"method run in Deeper: void run()",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected entities:\n%s\ngot:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	constructor := entities[1]
+	if len(constructor.Parameters) != 2 || constructor.Parameters[0] != (Param{Name: "x", Type: "int"}) {
+		t.Errorf("Expected the compact constructor to take the record components, got %+v", constructor.Parameters)
+	}
+	if base := entities[4]; strings.Join(base.Modifiers, " ") != "abstract sealed" {
+		t.Errorf("Expected Base to be abstract and sealed, got %v", base.Modifiers)
+	}
+}
+
 func TestExtractEntitiesJavaScript(t *testing.T) {
 	code := `
 import React from 'react';
@@ -339,42 +455,316 @@ export default App;
 
 	entities := extractEntities(parseResult.Tree.RootNode(), LanguageJavaScript, []byte(code))
 
-	// Check for function
+	// Functions and classes rendering JSX are components
 	foundFunction := false
 	for _, e := range entities {
-		if e.Name == "Counter" && e.Type == EntityTypeFunction {
+		if e.Name == "Counter" && e.Type == EntityTypeComponent {
 			foundFunction = true
 			break
 		}
 	}
 	if !foundFunction {
-		t.Error("Expected to find 'Counter' function")
+		t.Error("Expected to find 'Counter' component")
 	}
 
-	// Check for class
 	foundClass := false
 	for _, e := range entities {
-		if e.Name == "App" && e.Type == EntityTypeClass {
+		if e.Name == "App" && e.Type == EntityTypeComponent {
 			foundClass = true
 			break
 		}
 	}
 	if !foundClass {
-		t.Error("Expected to find 'App' class")
+		t.Error("Expected to find 'App' component")
 	}
 
-	// Arrow functions might not be extracted by the current implementation
-	// since they're variable declarations with arrow function expressions
+	// Arrow functions assigned to a variable are bound to its name
 	foundArrow := false
 	for _, e := range entities {
-		if e.Name == "helper" {
+		if e.boundName == "helper" && e.Type == EntityTypeFunction {
 			foundArrow = true
-			t.Logf("Found helper with type: %s", e.Type)
 			break
 		}
 	}
-	if foundArrow {
-		t.Log("Arrow function was extracted")
+	if !foundArrow {
+		t.Error("Expected to find 'helper' function")
+	}
+}
+
+func TestExtractEntitiesSQL(t *testing.T) {
+	code := `-- Registered users
+CREATE TABLE app.users (
+  id INT PRIMARY KEY,
+  name TEXT
+);
+
+CREATE VIEW active_users AS SELECT * FROM app.users WHERE active;
+
+CREATE FUNCTION add(a integer, b integer) RETURNS integer AS $$ SELECT a + b $$ LANGUAGE SQL;
+
+INSERT INTO app.users VALUES (1, 'a');
+`
+	parseResult, err := parseString(code, LanguageSQL)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageSQL, []byte(code))
+
+	expected := []struct {
+		name       string
+		entityType EntityType
+		signature  string
+	}{
+		{"app.users", EntityTypeType, "CREATE TABLE app.users"},
+		{"active_users", EntityTypeType, "CREATE VIEW active_users"},
+		{"add", EntityTypeFunction, "CREATE FUNCTION add(a integer, b integer) RETURNS integer"},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		if e.Name != want.name || e.Type != want.entityType || e.Signature != want.signature {
+			t.Errorf("Entity %d: expected %s %s %q, got %s %s %q", i, want.entityType, want.name, want.signature, e.Type, e.Name, e.Signature)
+		}
+	}
+	if entities[0].Docstring == nil || *entities[0].Docstring != "Registered users" {
+		t.Errorf("Expected the leading comment as docstring, got %v", entities[0].Docstring)
+	}
+}
+
+func TestExtractEntitiesHCL(t *testing.T) {
+	code := `# Main VPC
+resource "aws_vpc" "main" {
+  cidr_block = var.cidr
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+variable "cidr" {
+  type = string
+}
+
+locals {
+  name = "app"
+}
+`
+	parseResult, err := parseString(code, LanguageHCL)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageHCL, []byte(code))
+
+	expected := []struct {
+		name      string
+		signature string
+		parent    string
+	}{
+		{"aws_vpc.main", `resource "aws_vpc" "main"`, ""},
+		{"lifecycle", "lifecycle", "aws_vpc.main"},
+		{"cidr", `variable "cidr"`, ""},
+		{"locals", "locals", ""},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		parent := ""
+		if e.Parent != nil {
+			parent = *e.Parent
+		}
+		if e.Type != EntityTypeBlock || e.Name != want.name || e.Signature != want.signature || parent != want.parent {
+			t.Errorf("Entity %d: expected block %s %q in %q, got %s %s %q in %q", i, want.name, want.signature, want.parent, e.Type, e.Name, e.Signature, parent)
+		}
+	}
+	if entities[0].Docstring == nil || *entities[0].Docstring != "Main VPC" {
+		t.Errorf("Expected the leading comment as docstring, got %v", entities[0].Docstring)
+	}
+}
+
+func TestExtractEntitiesProto(t *testing.T) {
+	code := `syntax = "proto3";
+
+import "google/protobuf/timestamp.proto";
+
+// User is a registered user.
+message User {
+  string name = 1;
+  message Address { string city = 1; }
+}
+
+enum Status {
+  STATUS_UNSPECIFIED = 0;
+}
+
+// Users manages users.
+service Users {
+  // GetUser returns a user.
+  rpc GetUser(GetUserRequest) returns (User);
+}
+`
+	parseResult, err := parseString(code, LanguageProto)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageProto, []byte(code))
+
+	expected := []struct {
+		name       string
+		entityType EntityType
+		signature  string
+		parent     string
+		docstring  string
+	}{
+		{"google/protobuf/timestamp.proto", EntityTypeImport, `import "google/protobuf/timestamp.proto";`, "", ""},
+		{"User", EntityTypeClass, "message User", "", "User is a registered user."},
+		{"Address", EntityTypeClass, "message Address", "User", ""},
+		{"Status", EntityTypeEnum, "enum Status", "", ""},
+		{"Users", EntityTypeInterface, "service Users", "", "Users manages users."},
+		{"GetUser", EntityTypeMethod, "rpc GetUser(GetUserRequest) returns (User)", "Users", "GetUser returns a user."},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		parent, docstring := "", ""
+		if e.Parent != nil {
+			parent = *e.Parent
+		}
+		if e.Docstring != nil {
+			docstring = *e.Docstring
+		}
+		if e.Name != want.name || e.Type != want.entityType || e.Signature != want.signature || parent != want.parent || docstring != want.docstring {
+			t.Errorf("Entity %d: expected %+v, got %s %s %q in %q with docstring %q", i, want, e.Type, e.Name, e.Signature, parent, docstring)
+		}
+	}
+}
+
+func TestExtractEntitiesElixir(t *testing.T) {
+	code := `defmodule MyApp.Accounts do
+  @moduledoc """
+  The Accounts context.
+  """
+  alias MyApp.{Repo, User}
+  alias MyApp.Billing, as: Bill
+  import Ecto.Query, only: [from: 2]
+
+  @doc "Gets a user."
+  @spec get_user(integer) :: User.t()
+  def get_user(id), do: Repo.get(User, id)
+
+  def create_user(attrs \\ %{}) when is_map(attrs) do
+    Repo.insert(attrs)
+  end
+
+  @doc false
+  defp secret(x) do
+    x
+  end
+end
+`
+	parseResult, err := parseString(code, LanguageElixir)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageElixir, []byte(code))
+
+	expected := []struct {
+		name       string
+		entityType EntityType
+		signature  string
+		parent     string
+		docstring  string
+	}{
+		{"MyApp.Accounts", EntityTypeClass, "defmodule MyApp.Accounts", "", "The Accounts context."},
+		{"Repo", EntityTypeImport, "alias MyApp.{Repo, User}", "", ""},
+		{"User", EntityTypeImport, "alias MyApp.{Repo, User}", "", ""},
+		{"Bill", EntityTypeImport, "alias MyApp.Billing, as: Bill", "", ""},
+		{"Ecto.Query", EntityTypeImport, "import Ecto.Query, only: [from: 2]", "", ""},
+		{"get_user", EntityTypeFunction, "def get_user(id)", "MyApp.Accounts", "Gets a user."},
+		{"create_user", EntityTypeFunction, `def create_user(attrs \\ %{}) when is_map(attrs)`, "MyApp.Accounts", ""},
+		{"secret", EntityTypeFunction, "defp secret(x)", "MyApp.Accounts", ""},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		parent, docstring := "", ""
+		if e.Parent != nil {
+			parent = *e.Parent
+		}
+		if e.Docstring != nil {
+			docstring = *e.Docstring
+		}
+		if e.Name != want.name || e.Type != want.entityType || e.Signature != want.signature || parent != want.parent || docstring != want.docstring {
+			t.Errorf("Entity %d: expected %+v, got %s %s %q in %q with docstring %q", i, want, e.Type, e.Name, e.Signature, parent, docstring)
+		}
+	}
+	if source := entities[3].Source; source == nil || *source != "MyApp.Billing" {
+		t.Errorf("Expected the aliased module as import source, got %v", source)
+	}
+}
+
+func TestExtractEntitiesLua(t *testing.T) {
+	code := `local json = require("cjson")
+require "plugin.setup"
+
+--- Greets someone.
+function M.greet(name)
+  return "hi " .. name
+end
+
+-- Doubles x.
+local function double(x)
+  return x * 2
+end
+
+function Stack:push(v) table.insert(self, v) end
+`
+	parseResult, err := parseString(code, LanguageLua)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageLua, []byte(code))
+
+	expected := []struct {
+		name       string
+		entityType EntityType
+		signature  string
+		docstring  string
+		startLine  int
+	}{
+		{"json", EntityTypeImport, `local json = require("cjson")`, "", 0},
+		{"setup", EntityTypeImport, `require "plugin.setup"`, "", 1},
+		{"M.greet", EntityTypeFunction, "function M.greet(name)", "Greets someone.", 3},
+		{"double", EntityTypeFunction, "local function double(x)", "Doubles x.", 9},
+		{"Stack:push", EntityTypeMethod, "function Stack:push(v)", "", 13},
+	}
+	if len(entities) != len(expected) {
+		t.Fatalf("Expected %d entities, got %d", len(expected), len(entities))
+	}
+	for i, want := range expected {
+		e := entities[i]
+		docstring := ""
+		if e.Docstring != nil {
+			docstring = *e.Docstring
+		}
+		if e.Name != want.name || e.Type != want.entityType || e.Signature != want.signature || docstring != want.docstring || e.LineRange.Start != want.startLine {
+			t.Errorf("Entity %d: expected %+v, got %s %s %q with docstring %q on line %d", i, want, e.Type, e.Name, e.Signature, docstring, e.LineRange.Start)
+		}
+	}
+	if source := entities[1].Source; source == nil || *source != "plugin.setup" {
+		t.Errorf("Expected the required module as import source, got %v", source)
 	}
 }
 
@@ -480,3 +870,95 @@ func main() {
 		t.Errorf("Invalid line range: %v", entity.LineRange)
 	}
 }
+
+func TestGoMethodReceiverParent(t *testing.T) {
+	code := `package shapes
+
+type (
+	Point struct{ X, Y int }
+	Pair[K comparable, V any] struct{ Key K; Value V }
+)
+
+func (p *Point) Move(dx int) { p.X += dx }
+
+func (p Pair[K, V]) Swap() {}
+
+func Origin() Point { return Point{} }
+`
+	parseResult, err := parseString(code, LanguageGo)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	entities := extractEntities(parseResult.Tree.RootNode(), LanguageGo, []byte(code))
+	parents := make(map[string]string)
+	for _, e := range entities {
+		parent := ""
+		if e.Parent != nil {
+			parent = *e.Parent
+		}
+		parents[e.Name] = parent
+	}
+
+	expected := map[string]string{
+		"Point":  "",
+		"Move":   "Point",
+		"Swap":   "Pair",
+		"Origin": "",
+	}
+	for name, want := range expected {
+		parent, ok := parents[name]
+		if !ok {
+			t.Errorf("Expected entity %s, got %v", name, parents)
+			continue
+		}
+		if parent != want {
+			t.Errorf("%s: expected parent %q, got %q", name, want, parent)
+		}
+	}
+}
+
+func TestExtractNamespaces(t *testing.T) {
+	tests := []struct {
+		lang      Language
+		code      string
+		name      string
+		signature string
+		child     string
+	}{
+		{LanguageJava, "package com.example.auth;\n\npublic class Service {}\n", "com.example.auth", "package com.example.auth", ""},
+		{LanguageRust, "mod auth {\n    pub fn sign() {}\n}\n\nmod util;\n", "auth", "mod auth", "sign"},
+		{LanguageTypeScript, "namespace Auth.Tokens {\n  export function sign() {}\n}\n", "Auth.Tokens", "namespace Auth.Tokens", "sign"},
+		{LanguageTypeScript, "declare module \"express\" {\n  interface Request {}\n}\n", "express", `module "express"`, "Request"},
+	}
+
+	for _, tt := range tests {
+		parseResult, err := parseString(tt.code, tt.lang)
+		if err != nil {
+			t.Fatalf("Parse failed: %v", err)
+		}
+
+		var namespaces []*ExtractedEntity
+		entities := extractEntities(parseResult.Tree.RootNode(), tt.lang, []byte(tt.code))
+		for _, e := range entities {
+			if e.Type == EntityTypeNamespace {
+				namespaces = append(namespaces, e)
+			}
+		}
+		if len(namespaces) != 1 {
+			t.Errorf("%s: expected one namespace, got %d", tt.lang, len(namespaces))
+			continue
+		}
+		if namespaces[0].Name != tt.name || namespaces[0].Signature != tt.signature {
+			t.Errorf("%s: expected namespace %s %q, got %s %q", tt.lang, tt.name, tt.signature, namespaces[0].Name, namespaces[0].Signature)
+		}
+		if tt.child == "" {
+			continue
+		}
+		for _, e := range entities {
+			if e.Name == tt.child && (e.Parent == nil || *e.Parent != tt.name) {
+				t.Errorf("%s: expected %s to belong to %s, got %v", tt.lang, tt.child, tt.name, e.Parent)
+			}
+		}
+	}
+}
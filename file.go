@@ -0,0 +1,107 @@
+package codechunk
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// ChunkFile reads the file at path, normalizes it with NormalizeSource and chunks it.
+// The path is also used for language detection. Byte ranges and columns of the
+// returned chunks refer to the normalized content, not the bytes on disk.
+func ChunkFile(path string, opts *ChunkOptions) ([]CodeChunk, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ChunkBytes(path, NormalizeSource(data), opts)
+}
+
+// NormalizeSource converts source bytes to UTF-8 with "\n" line endings. A UTF-8
+// byte order mark is stripped; UTF-16 is decoded when it has a byte order mark or
+// looks like ASCII-range UTF-16; other invalid UTF-8 is decoded as Latin-1.
+// CRLF and lone CR line endings become LF.
+func NormalizeSource(data []byte) []byte {
+	switch {
+	case bytes.HasPrefix(data, []byte{0xEF, 0xBB, 0xBF}):
+		data = data[3:]
+	case bytes.HasPrefix(data, []byte{0xFF, 0xFE}):
+		data = decodeUTF16(data[2:], binary.LittleEndian)
+	case bytes.HasPrefix(data, []byte{0xFE, 0xFF}):
+		data = decodeUTF16(data[2:], binary.BigEndian)
+	default:
+		if order := sniffUTF16(data); order != nil {
+			data = decodeUTF16(data, order)
+		} else if !utf8.Valid(data) {
+			data = decodeLatin1(data)
+		}
+	}
+	return normalizeLineEndings(data)
+}
+
+// sniffUTF16 detects BOM-less UTF-16 from the NUL bytes that ASCII characters
+// leave in every other byte, returning its byte order or nil
+func sniffUTF16(data []byte) binary.ByteOrder {
+	n := len(data)
+	if n > 512 {
+		n = 512
+	}
+	n -= n % 2
+	if n < 4 {
+		return nil
+	}
+
+	var evenNUL, oddNUL int
+	for i := 0; i < n; i += 2 {
+		if data[i] == 0 {
+			evenNUL++
+		}
+		if data[i+1] == 0 {
+			oddNUL++
+		}
+	}
+
+	pairs := n / 2
+	switch {
+	case oddNUL == pairs && evenNUL == 0:
+		return binary.LittleEndian
+	case evenNUL == pairs && oddNUL == 0:
+		return binary.BigEndian
+	default:
+		return nil
+	}
+}
+
+// decodeUTF16 decodes UTF-16 in the given byte order to UTF-8, ignoring a trailing odd byte
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+
+	out := make([]byte, 0, len(units))
+	for _, r := range utf16.Decode(units) {
+		out = utf8.AppendRune(out, r)
+	}
+	return out
+}
+
+// decodeLatin1 decodes ISO-8859-1 to UTF-8
+func decodeLatin1(data []byte) []byte {
+	out := make([]byte, 0, len(data)+len(data)/4)
+	for _, b := range data {
+		out = utf8.AppendRune(out, rune(b))
+	}
+	return out
+}
+
+// normalizeLineEndings replaces CRLF and lone CR line endings with LF
+func normalizeLineEndings(data []byte) []byte {
+	if bytes.IndexByte(data, '\r') < 0 {
+		return data
+	}
+	data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	return bytes.ReplaceAll(data, []byte("\r"), []byte("\n"))
+}
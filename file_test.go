@@ -0,0 +1,72 @@
+package codechunk
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    []byte
+		expected string
+	}{
+		{"plain", []byte("a\nb"), "a\nb"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFdef f(): pass"), "def f(): pass"},
+		{"crlf", []byte("a\r\nb\r\n"), "a\nb\n"},
+		{"lone cr", []byte("a\rb"), "a\nb"},
+		{"utf-16le bom", []byte("\xFF\xFEh\x00\xE9\x00\r\x00\n\x00"), "hé\n"},
+		{"utf-16be bom", []byte("\xFE\xFF\x00h\x00i"), "hi"},
+		{"utf-16le sniffed", []byte("f\x00n\x00 \x00m\x00"), "fn m"},
+		{"latin-1", []byte("# caf\xE9\n"), "# café\n"},
+		{"multibyte utf-8", []byte("# café \U0001F600\n"), "# café \U0001F600\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(NormalizeSource(tt.input)); got != tt.expected {
+				t.Errorf("NormalizeSource(%q) = %q, expected %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestChunkFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.go")
+	code := "\xEF\xBB\xBFpackage main\r\n\r\nfunc Greet(name string) {\r\n\tprintln(name)\r\n}\r\n"
+	if err := os.WriteFile(path, []byte(code), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	chunks, err := ChunkFile(path, nil)
+	if err != nil {
+		t.Fatalf("ChunkFile failed: %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("Expected chunks")
+	}
+
+	normalized := string(NormalizeSource([]byte(code)))
+	for _, chunk := range chunks {
+		if strings.Contains(chunk.Text, "\r") {
+			t.Errorf("Chunk %d contains a carriage return: %q", chunk.Index, chunk.Text)
+		}
+		if chunk.Text != normalized[chunk.ByteRange.Start:chunk.ByteRange.End] {
+			t.Errorf("Chunk %d byte range does not match the normalized source", chunk.Index)
+		}
+		for _, entity := range chunk.Context.Entities {
+			if strings.Contains(entity.Signature, "\r") {
+				t.Errorf("Signature of %s contains a carriage return: %q", entity.Name, entity.Signature)
+			}
+		}
+	}
+
+	if _, err := ChunkFile(filepath.Join(dir, "missing.go"), nil); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Expected fs.ErrNotExist, got %v", err)
+	}
+}
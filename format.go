@@ -0,0 +1,328 @@
+package codechunk
+
+import (
+	"io"
+	"strings"
+	"text/template"
+	"unicode/utf8"
+)
+
+// CommentPrefixes maps languages to the line comment prefix used for context headers
+var CommentPrefixes = map[Language]string{
+	LanguageTypeScript: "//",
+	LanguageJavaScript: "//",
+	LanguagePython:     "#",
+	LanguageRust:       "//",
+	LanguageGo:         "//",
+	LanguageJava:       "//",
+	LanguageSQL:        "--",
+	LanguageHCL:        "#",
+	LanguageProto:      "//",
+	LanguageElixir:     "#",
+	LanguageLua:        "--",
+}
+
+// defaultCommentPrefix is used for languages without an entry in CommentPrefixes
+const defaultCommentPrefix = "#"
+
+// commentPrefixFor returns the header comment prefix for a language
+func commentPrefixFor(lang Language) string {
+	if prefix, ok := CommentPrefixes[lang]; ok {
+		return prefix
+	}
+	return defaultCommentPrefix
+}
+
+// headerPrefix returns the comment prefix of a chunk's header: the
+// ChunkOptions.CommentPrefix it was chunked with, or the prefix for its language
+func headerPrefix(ctx ChunkContext) string {
+	if ctx.commentPrefix != "" {
+		return ctx.commentPrefix
+	}
+	return commentPrefixFor(ctx.Language)
+}
+
+// ContextFormatter renders a chunk's text together with its context into the
+// ContextualizedText of a CodeChunk. FormatChunkWithContext is the default.
+type ContextFormatter func(text string, ctx ChunkContext, overlapText string) string
+
+// ContextTemplateData is the data passed to templates created with NewTemplateFormatter
+type ContextTemplateData struct {
+	Text        string       // The raw chunk text
+	Prefix      string       // ChunkOptions.CommentPrefix, or the comment prefix for the chunk's language
+	OverlapText string       // Overlap text from the previous chunk (may be empty)
+	Context     ChunkContext // Full chunk context
+	Filepath    string       // Last path segments of the file path
+	FileDoc     string       // Documentation of the file on one line, truncated
+	ScopePath   string       // Scope chain from root to current, joined with " > "
+	Defines     []string     // Signatures of non-import entities in the chunk
+	Uses        []string     // Names of relevant imports (at most 10)
+	Calls       []string     // Names of symbols called in the chunk (at most 10)
+	After       []string     // Signatures (or names) of sibling entities that precede the chunk
+	Before      []string     // Signatures (or names) of sibling entities that follow the chunk
+}
+
+// templateFuncs are the helper functions available to context templates
+var templateFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// NewTemplateFormatter creates a ContextFormatter from a text/template definition.
+// The template is executed with a ContextTemplateData value, so the fields that appear,
+// their order, and their comment prefix are all under the caller's control:
+//
+//	f, err := codechunk.NewTemplateFormatter(
+//		"{{.Prefix}} {{.Filepath}}\n{{if .ScopePath}}{{.Prefix}} in {{.ScopePath}}\n{{end}}\n{{.Text}}")
+//
+// The template is executed once against a sample context, so that references to
+// missing fields and misused functions are returned as errors here. If it still
+// fails to execute for a chunk, such as by indexing past the end of a list, the
+// error is logged as a warning to ChunkOptions.Logger and FormatChunkWithContext
+// is used instead.
+func NewTemplateFormatter(tmpl string) (ContextFormatter, error) {
+	t, err := template.New("context").Funcs(templateFuncs).Parse(tmpl)
+	if err != nil {
+		return nil, err
+	}
+	sample := newContextTemplateData("", templateSampleContext, "", "//")
+	if err := t.Execute(io.Discard, sample); err != nil {
+		return nil, err
+	}
+
+	return func(text string, ctx ChunkContext, overlapText string) string {
+		var b strings.Builder
+		data := newContextTemplateData(text, ctx, overlapText, headerPrefix(ctx))
+		if err := t.Execute(&b, data); err != nil {
+			if ctx.logger != nil {
+				ctx.logger.Warn("context template failed, using the default format", "filepath", ctx.Filepath, "error", err)
+			}
+			return FormatChunkWithContext(text, ctx, overlapText)
+		}
+		return b.String()
+	}, nil
+}
+
+// templateSampleContext has every list and optional field of a ChunkContext set,
+// for NewTemplateFormatter to check templates against
+var templateSampleContext = ChunkContext{
+	Filepath:      "main.go",
+	Language:      LanguageGo,
+	Scope:         []EntityInfo{{Name: "main", Type: EntityTypeFunction, Signature: "func main()"}},
+	Entities:      []ChunkEntityInfo{{Name: "main", Type: EntityTypeFunction, Signature: "func main()"}},
+	Siblings:      []SiblingInfo{{Name: "init", Position: "before", Distance: 1}, {Name: "run", Position: "after", Distance: 1}},
+	Imports:       []ImportInfo{{Name: "fmt", Source: "fmt"}},
+	References:    []ReferenceInfo{{Name: "fmt.Println"}},
+	ParseError:    &ParseError{},
+	Cell:          &NotebookCell{},
+	Class:         &ClassContext{},
+	Boilerplate:   &BoilerplateInfo{},
+	Build:         &BuildConstraints{},
+	Provenance:    &Provenance{},
+	TestedSymbols: []string{"main"},
+	Tags:          []CommentTag{{}},
+	FileDoc:       "Command main runs.",
+}
+
+// newContextTemplateData precomputes the commonly used header fields for a chunk
+func newContextTemplateData(text string, ctx ChunkContext, overlapText string, prefix string) ContextTemplateData {
+	data := ContextTemplateData{
+		Text:        text,
+		Prefix:      prefix,
+		OverlapText: overlapText,
+		Context:     ctx,
+		FileDoc:     fileDocSummary(ctx.FileDoc),
+		ScopePath:   scopePath(ctx.Scope),
+		Defines:     definedSignatures(ctx.Entities),
+		Uses:        importNames(ctx.Imports, 10),
+		Calls:       referenceNames(ctx.References, 10),
+		After:       siblingLabels(ctx.Siblings, "before"),
+		Before:      siblingLabels(ctx.Siblings, "after"),
+	}
+	if ctx.Filepath != "" {
+		data.Filepath = getLastPathSegments(ctx.Filepath, 3)
+	}
+	return data
+}
+
+// formatChunk renders the contextualized text using the configured formatter, with
+// the context trimmed to fit opts.MaxContextHeaderSize
+func formatChunk(opts ChunkOptions, text string, ctx ChunkContext, overlapText string) string {
+	if opts.MaxContextHeaderSize > 0 {
+		ctx = fitContextHeader(opts, ctx)
+	}
+	return renderChunk(opts, text, ctx, overlapText)
+}
+
+// renderChunk renders the contextualized text using the configured formatter
+func renderChunk(opts ChunkOptions, text string, ctx ChunkContext, overlapText string) string {
+	if opts.FormatFunc != nil {
+		ctx.commentPrefix = opts.CommentPrefix
+		ctx.logger = opts.Logger
+		return opts.FormatFunc(text, ctx, overlapText)
+	}
+	if opts.CommentPrefix != "" {
+		return formatChunkWithPrefix(text, ctx, overlapText, opts.CommentPrefix)
+	}
+	return FormatChunkWithContext(text, ctx, overlapText)
+}
+
+// fitContextHeader trims the context shown in a chunk's header until the header,
+// rendered without the chunk text, fits opts.MaxContextHeaderSize tokens as
+// counted by opts.TokenCounter. Context is dropped from the least to the most
+// important: siblings from the farthest, calls and imports from the last, the
+// file doc, the class context, defined entities from the last and finally the
+// scope chain from the outermost scope. The file path is always kept. The
+// returned context is a trimmed copy; ctx is not modified.
+func fitContextHeader(opts ChunkOptions, ctx ChunkContext) ChunkContext {
+	counter := opts.TokenCounter
+	if counter == nil {
+		counter = EstimateTokens
+	}
+	fits := func() bool {
+		return counter(renderChunk(opts, "", ctx, "")) <= opts.MaxContextHeaderSize
+	}
+	if fits() {
+		return ctx
+	}
+
+	ctx.Siblings = append([]SiblingInfo(nil), ctx.Siblings...)
+	for len(ctx.Siblings) > 0 && !fits() {
+		farthest := 0
+		for i, sibling := range ctx.Siblings {
+			if sibling.Distance >= ctx.Siblings[farthest].Distance {
+				farthest = i
+			}
+		}
+		ctx.Siblings = append(ctx.Siblings[:farthest], ctx.Siblings[farthest+1:]...)
+	}
+	for len(ctx.References) > 0 && !fits() {
+		ctx.References = ctx.References[:len(ctx.References)-1]
+	}
+	for len(ctx.Imports) > 0 && !fits() {
+		ctx.Imports = ctx.Imports[:len(ctx.Imports)-1]
+	}
+	if ctx.FileDoc != "" && !fits() {
+		ctx.FileDoc = ""
+	}
+	if ctx.Class != nil && !fits() {
+		ctx.Class = nil
+	}
+	for len(ctx.Entities) > 0 && !fits() {
+		ctx.Entities = ctx.Entities[:len(ctx.Entities)-1]
+	}
+	for len(ctx.Scope) > 0 && !fits() {
+		ctx.Scope = ctx.Scope[:len(ctx.Scope)-1]
+	}
+	return ctx
+}
+
+// scopePath joins the scope chain from the outermost scope to the innermost one
+func scopePath(scope []EntityInfo) string {
+	names := make([]string, len(scope))
+	for i, s := range scope {
+		names[len(scope)-1-i] = s.Name
+	}
+	return strings.Join(names, " > ")
+}
+
+// definedSignatures returns the signatures of all non-import entities, each after
+// its overloads. Entities declared by the same statement, such as the names of an
+// export clause, share their signature and are listed once.
+func definedSignatures(entities []ChunkEntityInfo) []string {
+	signatures := make([]string, 0)
+	for _, e := range entities {
+		if e.Signature == "" || e.Type == EntityTypeImport {
+			continue
+		}
+		signatures = append(signatures, e.Overloads...)
+		signature := annotatedSignature(e.Signature, e.Annotations)
+		if len(signatures) > 0 && signatures[len(signatures)-1] == signature {
+			continue
+		}
+		signatures = append(signatures, signature)
+	}
+	return signatures
+}
+
+// annotatedSignature prefixes a signature with the entity's annotations, unless it
+// starts with them already as Java signatures do
+func annotatedSignature(signature string, annotations []string) string {
+	if len(annotations) == 0 || strings.HasPrefix(signature, "@") {
+		return signature
+	}
+	return strings.Join(annotations, " ") + " " + signature
+}
+
+// importNames returns the names of up to max imports
+func importNames(imports []ImportInfo, max int) []string {
+	names := make([]string, 0)
+	for i, imp := range imports {
+		if i >= max {
+			break
+		}
+		names = append(names, imp.Name)
+	}
+	return names
+}
+
+// referenceNames returns the names of up to max references
+func referenceNames(references []ReferenceInfo, max int) []string {
+	names := make([]string, 0)
+	for i, ref := range references {
+		if i >= max {
+			break
+		}
+		names = append(names, ref.Name)
+	}
+	return names
+}
+
+// siblingLabels returns the signatures of siblings at the given position, falling back
+// to names for siblings without a signature
+func siblingLabels(siblings []SiblingInfo, position string) []string {
+	labels := make([]string, 0)
+	for _, s := range siblings {
+		if s.Position != position {
+			continue
+		}
+		if s.Signature != "" {
+			labels = append(labels, s.Signature)
+		} else {
+			labels = append(labels, s.Name)
+		}
+	}
+	return labels
+}
+
+// hasSiblingSignatures reports whether any sibling carries a signature
+func hasSiblingSignatures(siblings []SiblingInfo) bool {
+	for _, s := range siblings {
+		if s.Signature != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// maxFileDocLen is the length in bytes the file documentation is truncated to in
+// context headers
+const maxFileDocLen = 160
+
+// fileDocSummary puts the documentation of a file on one line for a context
+// header, truncated to maxFileDocLen bytes
+func fileDocSummary(doc string) string {
+	return truncateSignature(strings.Join(strings.Fields(doc), " "), maxFileDocLen)
+}
+
+// truncateSignature shortens a signature to at most maxLen bytes, marking the cut
+// with "...". A maxLen of zero or less disables truncation.
+func truncateSignature(signature string, maxLen int) string {
+	if maxLen <= 0 || len(signature) <= maxLen {
+		return signature
+	}
+	cut := maxLen
+	for cut > 0 && !utf8.RuneStart(signature[cut]) {
+		cut--
+	}
+	return strings.TrimRight(signature[:cut], " ") + "..."
+}
@@ -0,0 +1,300 @@
+package codechunk
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestNewTemplateFormatter(t *testing.T) {
+	f, err := NewTemplateFormatter("// {{.Filepath}}\n// {{.ScopePath}}\n// {{join .Defines \"; \"}}\n{{.Text}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+
+	ctx := ChunkContext{
+		Filepath: "a/b/c/d.go",
+		Scope: []EntityInfo{
+			{Name: "Get", Type: EntityTypeMethod},
+			{Name: "Service", Type: EntityTypeClass},
+		},
+		Entities: []ChunkEntityInfo{
+			{Name: "Get", Type: EntityTypeMethod, Signature: "func Get()"},
+			{Name: "fmt", Type: EntityTypeImport, Signature: "import \"fmt\""},
+		},
+	}
+
+	result := f("body", ctx, "")
+	expected := "// b/c/d.go\n// Service > Get\n// func Get()\nbody"
+	if result != expected {
+		t.Errorf("Expected %q, got %q", expected, result)
+	}
+}
+
+func TestNewTemplateFormatterInvalid(t *testing.T) {
+	if _, err := NewTemplateFormatter("{{.Text"); err == nil {
+		t.Error("Expected error for invalid template")
+	}
+}
+
+func TestNewTemplateFormatterExecError(t *testing.T) {
+	if _, err := NewTemplateFormatter("{{.Missing}}"); err == nil {
+		t.Error("Expected error for a template referencing a missing field")
+	}
+	if _, err := NewTemplateFormatter("{{join .Filepath \", \"}}"); err == nil {
+		t.Error("Expected error for a template calling join with a string")
+	}
+
+	// Indexing past the end of a list only fails for chunks where it is shorter
+	f, err := NewTemplateFormatter("{{index .Context.Scope 0}}\n{{.Text}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+	var logs bytes.Buffer
+	opts := &ChunkOptions{FormatFunc: f, Logger: slog.New(slog.NewTextHandler(&logs, nil))}
+	chunks, err := Chunk("main.go", "package main\n\nfunc main() {}\n", opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) == 0 || chunks[0].ContextualizedText != FormatChunkWithContext(chunks[0].Text, chunks[0].Context, "") {
+		t.Errorf("Expected fallback to default format, got %+v", chunks)
+	}
+	if !strings.Contains(logs.String(), "context template failed") || !strings.Contains(logs.String(), "index out of range") {
+		t.Errorf("Expected the template error to be logged, got %q", logs.String())
+	}
+}
+
+func TestChunkWithFormatFunc(t *testing.T) {
+	code := `package main
+
+func main() {}
+`
+	opts := &ChunkOptions{
+		FormatFunc: func(text string, ctx ChunkContext, overlapText string) string {
+			return "[" + string(ctx.Language) + "]\n" + text
+		},
+	}
+
+	chunks, err := Chunk("main.go", code, opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	for _, c := range chunks {
+		if !strings.HasPrefix(c.ContextualizedText, "[go]\n") {
+			t.Errorf("Expected custom formatter output, got %q", c.ContextualizedText)
+		}
+	}
+}
+
+func TestFormatChunkWithContextCommentPrefix(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		expected string
+	}{
+		{LanguageGo, "// main.x\n// Scope: main\n\nbody"},
+		{LanguageRust, "// main.x\n// Scope: main\n\nbody"},
+		{LanguageTypeScript, "// main.x\n// Scope: main\n\nbody"},
+		{LanguagePython, "# main.x\n# Scope: main\n\nbody"},
+		{"", "# main.x\n# Scope: main\n\nbody"},
+	}
+
+	for _, tt := range tests {
+		ctx := ChunkContext{
+			Filepath: "main.x",
+			Language: tt.lang,
+			Scope:    []EntityInfo{{Name: "main", Type: EntityTypeFunction}},
+		}
+		if result := FormatChunkWithContext("body", ctx, ""); result != tt.expected {
+			t.Errorf("Language %q: expected %q, got %q", tt.lang, tt.expected, result)
+		}
+	}
+}
+
+func TestChunkCommentPrefixOverride(t *testing.T) {
+	code := `def hello():
+    pass
+`
+	chunks, err := Chunk("hello.py", code, &ChunkOptions{CommentPrefix: "--"})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	if len(chunks) == 0 || !strings.HasPrefix(chunks[0].ContextualizedText, "-- hello.py") {
+		t.Errorf("Expected overridden prefix, got %q", chunks[0].ContextualizedText)
+	}
+
+	chunks, err = Chunk("hello.py", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !strings.HasPrefix(chunks[0].ContextualizedText, "# hello.py") {
+		t.Errorf("Expected Python prefix, got %q", chunks[0].ContextualizedText)
+	}
+}
+
+func TestTemplateFormatterCommentPrefixOverride(t *testing.T) {
+	code := `def hello():
+    pass
+`
+	f, err := NewTemplateFormatter("{{.Prefix}} {{.Filepath}}\n{{.Text}}")
+	if err != nil {
+		t.Fatalf("NewTemplateFormatter failed: %v", err)
+	}
+	chunks, err := Chunk("hello.py", code, &ChunkOptions{FormatFunc: f, CommentPrefix: "--"})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) == 0 || !strings.HasPrefix(chunks[0].ContextualizedText, "-- hello.py\n") {
+		t.Errorf("Expected overridden prefix, got %q", chunks[0].ContextualizedText)
+	}
+
+	chunks, err = Chunk("hello.py", code, &ChunkOptions{FormatFunc: f})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !strings.HasPrefix(chunks[0].ContextualizedText, "# hello.py\n") {
+		t.Errorf("Expected Python prefix, got %q", chunks[0].ContextualizedText)
+	}
+}
+
+func TestChunkSiblingSignatures(t *testing.T) {
+	code := `package main
+
+func add(a, b int) int {
+	return a + b
+}
+
+func subtract(a, b int) int {
+	return a - b
+}
+`
+	chunks, err := Chunk("math.go", code, &ChunkOptions{MaxChunkSize: 30, OverlapLines: -1})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	last := chunks[len(chunks)-1]
+
+	var sibling *SiblingInfo
+	for i := range last.Context.Siblings {
+		if last.Context.Siblings[i].Name == "add" {
+			sibling = &last.Context.Siblings[i]
+		}
+	}
+	if sibling == nil {
+		t.Fatalf("Expected add sibling, got %+v", last.Context.Siblings)
+	}
+	if sibling.Signature != "func add(a, b int) int" {
+		t.Errorf("Unexpected sibling signature: %q", sibling.Signature)
+	}
+	if !strings.Contains(last.ContextualizedText, "// After: func add(a, b int) int") {
+		t.Errorf("Expected sibling signature in header, got:\n%s", last.ContextualizedText)
+	}
+
+	chunks, err = Chunk("math.go", code, &ChunkOptions{MaxChunkSize: 30, SiblingDetail: SiblingDetailNames})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	last = chunks[len(chunks)-1]
+	for _, s := range last.Context.Siblings {
+		if s.Signature != "" {
+			t.Errorf("Expected no signature with names detail, got %q", s.Signature)
+		}
+	}
+	if !strings.Contains(last.ContextualizedText, "// After: add\n") {
+		t.Errorf("Expected sibling name in header, got:\n%s", last.ContextualizedText)
+	}
+}
+
+func TestTruncateSignature(t *testing.T) {
+	tests := []struct {
+		signature string
+		maxLen    int
+		expected  string
+	}{
+		{"func short()", 120, "func short()"},
+		{"func long(a int, b int)", 10, "func long(..."},
+		{"func long(a int, b int)", 0, "func long(a int, b int)"},
+		{"func long(a int, b int)", -1, "func long(a int, b int)"},
+		{"func (é)", 7, "func (..."},
+	}
+
+	for _, tt := range tests {
+		if got := truncateSignature(tt.signature, tt.maxLen); got != tt.expected {
+			t.Errorf("truncateSignature(%q, %d) = %q, want %q", tt.signature, tt.maxLen, got, tt.expected)
+		}
+	}
+}
+
+func TestChunkFileDocHeader(t *testing.T) {
+	code := "// Package auth verifies tokens.\npackage auth\n\nfunc Verify(token string) bool {\n\treturn token != \"\"\n}\n"
+
+	chunks, err := Chunk("auth/verify.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if chunks[0].Context.FileDoc != "Package auth verifies tokens." {
+		t.Errorf("Expected the package comment as file doc, got %q", chunks[0].Context.FileDoc)
+	}
+	if !strings.HasPrefix(chunks[0].ContextualizedText, "// auth/verify.go\n// Doc: Package auth verifies tokens.\n") {
+		t.Errorf("Expected a Doc line after the file path, got %q", chunks[0].ContextualizedText)
+	}
+
+	minimal, err := Chunk("auth/verify.go", code, &ChunkOptions{ContextMode: ContextModeMinimal})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if minimal[0].Context.FileDoc != "" {
+		t.Errorf("Expected no file doc in minimal mode, got %q", minimal[0].Context.FileDoc)
+	}
+}
+
+func TestFileDocSummary(t *testing.T) {
+	doc := "Tools for\n  auth.\n\n" + strings.Repeat("x", 200)
+	summary := fileDocSummary(doc)
+	if !strings.HasPrefix(summary, "Tools for auth. x") || !strings.HasSuffix(summary, "...") {
+		t.Errorf("Expected a truncated single line, got %q", summary)
+	}
+	if len(summary) > maxFileDocLen+3 {
+		t.Errorf("Expected at most %d bytes, got %d", maxFileDocLen+3, len(summary))
+	}
+}
+
+func TestFitContextHeader(t *testing.T) {
+	ctx := ChunkContext{
+		Filepath: "auth/service.go",
+		Language: LanguageGo,
+		Scope:    []EntityInfo{{Name: "Login", Type: EntityTypeMethod}, {Name: "Service", Type: EntityTypeType}},
+		Entities: []ChunkEntityInfo{{Name: "Login", Type: EntityTypeMethod, Signature: "func (s *Service) Login(user string) error"}},
+		Imports:  []ImportInfo{{Name: "errors"}, {Name: "strings"}},
+		Siblings: []SiblingInfo{
+			{Name: "Logout", Type: EntityTypeMethod, Position: "after", Distance: 1, Signature: "func (s *Service) Logout()"},
+			{Name: "Refresh", Type: EntityTypeMethod, Position: "after", Distance: 2, Signature: "func (s *Service) Refresh()"},
+		},
+	}
+	bytes := func(text string) int { return len(text) }
+
+	tests := []struct {
+		size     int
+		expected string
+	}{
+		{0, "// auth/service.go\n// Scope: Service > Login\n// Defines: func (s *Service) Login(user string) error\n// Uses: errors, strings\n// Before: func (s *Service) Logout(); func (s *Service) Refresh()\n\n"},
+		{170, "// auth/service.go\n// Scope: Service > Login\n// Defines: func (s *Service) Login(user string) error\n// Uses: errors, strings\n// Before: func (s *Service) Logout()\n\n"},
+		{120, "// auth/service.go\n// Scope: Service > Login\n// Defines: func (s *Service) Login(user string) error\n// Uses: errors\n\n"},
+		{110, "// auth/service.go\n// Scope: Service > Login\n// Defines: func (s *Service) Login(user string) error\n\n"},
+		{60, "// auth/service.go\n// Scope: Service > Login\n\n"},
+		{40, "// auth/service.go\n// Scope: Login\n\n"},
+		{5, "// auth/service.go\n\n"},
+	}
+
+	for _, tt := range tests {
+		opts := ChunkOptions{MaxContextHeaderSize: tt.size, TokenCounter: bytes}
+		if header := formatChunk(opts, "", ctx, ""); header != tt.expected {
+			t.Errorf("Size %d: expected header %q, got %q", tt.size, tt.expected, header)
+		}
+	}
+	if len(ctx.Siblings) != 2 || len(ctx.Scope) != 2 {
+		t.Error("Expected the context to be left unchanged")
+	}
+}
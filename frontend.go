@@ -0,0 +1,204 @@
+package codechunk
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// frontend chunks a container file whose code is embedded in another format, such
+// as a notebook or a single-file component
+type frontend func(path string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error)
+
+// frontendFor returns the frontend for a path, or nil if it is plain source code
+func frontendFor(path string) frontend {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".ipynb":
+		return chunkNotebook
+	case ".vue", ".svelte":
+		return chunkComponent
+	case ".md", ".markdown":
+		return chunkMarkdown
+	case ".html", ".htm":
+		return chunkHTML
+	default:
+		return nil
+	}
+}
+
+// fileSection is a section of a container file outside the block being chunked,
+// listed in the sibling context of the block's chunks
+type fileSection struct {
+	name      string
+	signature string
+	byteRange ByteRange // Relative to the block being chunked
+}
+
+// sectionSiblings returns the sections before and after a chunk as siblings, ranked
+// by proximity on each side
+func sectionSiblings(byteRange ByteRange, sections []fileSection, detail SiblingDetail) []SiblingInfo {
+	if detail == SiblingDetailNone || len(sections) == 0 {
+		return nil
+	}
+
+	siblings := make([]SiblingInfo, 0, len(sections))
+	before := 0
+	for i := len(sections) - 1; i >= 0; i-- {
+		if sections[i].byteRange.End <= byteRange.Start {
+			before++
+			siblings = append(siblings, sectionSibling(sections[i], "before", before, detail))
+		}
+	}
+	after := 0
+	for _, section := range sections {
+		if section.byteRange.Start >= byteRange.End {
+			after++
+			siblings = append(siblings, sectionSibling(section, "after", after, detail))
+		}
+	}
+	return siblings
+}
+
+// sectionSibling describes a section as a sibling
+func sectionSibling(section fileSection, position string, distance int, detail SiblingDetail) SiblingInfo {
+	sibling := SiblingInfo{
+		Name:     section.name,
+		Type:     EntityTypeSection,
+		Position: position,
+		Distance: distance,
+	}
+	if detail == SiblingDetailSignatures {
+		sibling.Signature = section.signature
+	}
+	return sibling
+}
+
+// embeddedBlock is a region of a container file holding code in a single language
+type embeddedBlock struct {
+	byteRange ByteRange
+	lang      Language
+	sections  []fileSection // Other sections of the file, relative to the whole file
+}
+
+// chunkEmbedded chunks the code of a block and shifts the chunks' ranges, lines and
+// spans so that they refer to the whole file. positions indexes the whole file and
+// source, if set, is the whole file retained by LazyText chunks.
+func chunkEmbedded(path string, code []byte, block embeddedBlock, opts ChunkOptions, m *FileMetrics, parser *languageParser, positions *positionIndex, source string) ([]CodeChunk, error) {
+	offset := block.byteRange.Start
+	lineOffset := positions.position(offset).Line
+
+	opts.Language = block.lang
+	opts.sections = make([]fileSection, len(block.sections))
+	for i, section := range block.sections {
+		section.byteRange = ByteRange{Start: section.byteRange.Start - offset, End: section.byteRange.End - offset}
+		opts.sections[i] = section
+	}
+
+	var chunks []CodeChunk
+	var err error
+	if IsLanguageSupported(block.lang) {
+		var blockMetrics FileMetrics
+		chunks, err = chunkSourceMeasured(path, code[block.byteRange.Start:block.byteRange.End], opts, &blockMetrics, parser)
+		if err != nil {
+			return nil, err
+		}
+		m.ParseTime += blockMetrics.ParseTime
+		m.ExtractTime += blockMetrics.ExtractTime
+		m.ChunkTime += blockMetrics.ChunkTime
+		m.Entities += blockMetrics.Entities
+	} else {
+		chunks = chunkPlainText(path, string(code[block.byteRange.Start:block.byteRange.End]), block.lang, opts)
+	}
+
+	for i := range chunks {
+		shiftChunk(&chunks[i], offset, lineOffset, positions)
+		if chunks[i].lazy != nil {
+			lazy := *chunks[i].lazy
+			lazy.source = source
+			chunks[i].lazy = &lazy
+		}
+	}
+	return chunks, nil
+}
+
+// shiftChunk moves a chunk of an embedded block by the block's byte and line
+// offsets. Entity line ranges, references and tags are copied, since they may be
+// shared with cached entities.
+func shiftChunk(chunk *CodeChunk, offset, lineOffset int, positions *positionIndex) {
+	start := positions.position(offset)
+	chunk.ByteRange = ByteRange{Start: chunk.ByteRange.Start + offset, End: chunk.ByteRange.End + offset}
+	chunk.LineRange = LineRange{Start: chunk.LineRange.Start + lineOffset, End: chunk.LineRange.End + lineOffset}
+	chunk.Span = positions.span(chunk.ByteRange)
+	if parseErr := chunk.Context.ParseError; parseErr != nil {
+		shifted := *parseErr
+		shifted.Ranges = make([]ByteRange, len(parseErr.Ranges))
+		for i, r := range parseErr.Ranges {
+			shifted.Ranges[i] = ByteRange{Start: r.Start + offset, End: r.End + offset}
+		}
+		chunk.Context.ParseError = &shifted
+	}
+
+	entities := make([]ChunkEntityInfo, len(chunk.Context.Entities))
+	for i, entity := range chunk.Context.Entities {
+		if entity.LineRange != nil {
+			entity.LineRange = &LineRange{Start: entity.LineRange.Start + lineOffset, End: entity.LineRange.End + lineOffset}
+		}
+		if entity.Span != nil {
+			entity.Span = &Span{Start: shiftPosition(entity.Span.Start, start), End: shiftPosition(entity.Span.End, start)}
+		}
+		entities[i] = entity
+	}
+	chunk.Context.Entities = entities
+
+	references := make([]ReferenceInfo, len(chunk.Context.References))
+	for i, ref := range chunk.Context.References {
+		ref.Line += lineOffset
+		references[i] = ref
+	}
+	chunk.Context.References = references
+
+	tags := make([]CommentTag, len(chunk.Context.Tags))
+	for i, tag := range chunk.Context.Tags {
+		tag.Line += lineOffset
+		tags[i] = tag
+	}
+	chunk.Context.Tags = tags
+}
+
+// shiftPosition moves a position within an embedded block to the file, given the
+// position where the block starts. Columns only shift on the block's first line.
+func shiftPosition(pos, start Position) Position {
+	if pos.Line == 0 {
+		pos.Column += start.Column
+		pos.RuneColumn += start.RuneColumn
+		pos.UTF16Column += start.UTF16Column
+	}
+	pos.Line += start.Line
+	return pos
+}
+
+// numberChunks numbers the chunks of a container file across the whole file
+func numberChunks(chunks []CodeChunk) {
+	for i := range chunks {
+		chunks[i].Index = i
+		chunks[i].TotalChunks = len(chunks)
+	}
+}
+
+// streamChunks streams already built chunks, with TotalChunks set to -1 like
+// other streamed chunks, until ctx is done
+func streamChunks(ctx context.Context, chunks []CodeChunk) <-chan CodeChunk {
+	ch := make(chan CodeChunk)
+	go func() {
+		defer close(ch)
+		for _, chunk := range chunks {
+			chunk.TotalChunks = -1
+			select {
+			case ch <- chunk:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
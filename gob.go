@@ -0,0 +1,130 @@
+package codechunk
+
+import (
+	"encoding/gob"
+	"errors"
+	"io"
+)
+
+// batchResultGob is the binary representation of BatchResult. Gob leaves empty
+// slices out, so whether Chunks was nil is sent along.
+type batchResultGob struct {
+	Filepath  string
+	Chunks    []CodeChunk
+	HasChunks bool
+	Error     string
+	ErrorCode string
+	Skipped   bool
+}
+
+// BatchEncoder writes batch results to a stream in a compact binary form (gob),
+// much cheaper to encode, decode and ship than JSON. The types are described
+// once per stream, so send many results through one encoder.
+type BatchEncoder struct {
+	enc *gob.Encoder
+}
+
+// NewBatchEncoder returns an encoder writing batch results to w.
+func NewBatchEncoder(w io.Writer) *BatchEncoder {
+	return &BatchEncoder{enc: gob.NewEncoder(w)}
+}
+
+// Encode writes a batch result. Its error is sent as a message and an error
+// code, as with MarshalJSON, and lazy chunks are materialized.
+func (e *BatchEncoder) Encode(result BatchResult) error {
+	out := batchResultGob{
+		Filepath:  result.Filepath,
+		HasChunks: result.Chunks != nil,
+		ErrorCode: ErrorCode(result.Error),
+		Skipped:   result.Skipped,
+	}
+	if result.Error != nil {
+		out.Error = result.Error.Error()
+	}
+	if len(result.Chunks) > 0 {
+		out.Chunks = materializeChunks(result.Chunks)
+	}
+	return e.enc.Encode(out)
+}
+
+// BatchDecoder reads batch results written by a BatchEncoder.
+type BatchDecoder struct {
+	dec *gob.Decoder
+}
+
+// NewBatchDecoder returns a decoder reading batch results from r.
+func NewBatchDecoder(r io.Reader) *BatchDecoder {
+	return &BatchDecoder{dec: gob.NewDecoder(r)}
+}
+
+// Decode reads the next batch result, returning io.EOF at the end of the
+// stream. The restored error keeps its message and matches the original
+// sentinel error with errors.Is.
+func (d *BatchDecoder) Decode() (BatchResult, error) {
+	var in batchResultGob
+	if err := d.dec.Decode(&in); err != nil {
+		return BatchResult{}, err
+	}
+
+	result := BatchResult{
+		Filepath: in.Filepath,
+		Chunks:   in.Chunks,
+		Error:    ErrorFromCode(in.ErrorCode, in.Error),
+		Skipped:  in.Skipped,
+	}
+	if in.HasChunks && result.Chunks == nil {
+		result.Chunks = []CodeChunk{}
+	}
+	for i := range result.Chunks {
+		restoreContextSlices(&result.Chunks[i].Context)
+	}
+	return result, nil
+}
+
+// EncodeBatchResults writes results to w with a BatchEncoder.
+func EncodeBatchResults(w io.Writer, results []BatchResult) error {
+	enc := NewBatchEncoder(w)
+	for _, result := range results {
+		if err := enc.Encode(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DecodeBatchResults reads all the results written to r by EncodeBatchResults
+// or a BatchEncoder.
+func DecodeBatchResults(r io.Reader) ([]BatchResult, error) {
+	dec := NewBatchDecoder(r)
+	results := make([]BatchResult, 0)
+	for {
+		result, err := dec.Decode()
+		if errors.Is(err, io.EOF) {
+			return results, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+}
+
+// restoreContextSlices replaces the nil lists of a decoded context with empty
+// ones, as chunking always sets them
+func restoreContextSlices(ctx *ChunkContext) {
+	if ctx.Scope == nil {
+		ctx.Scope = []EntityInfo{}
+	}
+	if ctx.Entities == nil {
+		ctx.Entities = []ChunkEntityInfo{}
+	}
+	if ctx.Siblings == nil {
+		ctx.Siblings = []SiblingInfo{}
+	}
+	if ctx.Imports == nil {
+		ctx.Imports = []ImportInfo{}
+	}
+	if ctx.References == nil {
+		ctx.References = []ReferenceInfo{}
+	}
+}
@@ -0,0 +1,79 @@
+package codechunk
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBatchResultsBinaryRoundTrip(t *testing.T) {
+	results := ChunkBatch([]FileInput{
+		{Filepath: "main.go", Code: "package main\n\nimport \"fmt\"\n\n// Greet prints a greeting.\nfunc Greet(name string) {\n\tfmt.Println(\"Hello, \" + name)\n}\n"},
+		{Filepath: "app.py", Code: "\"\"\"App module.\"\"\"\n\nclass App:\n    \"\"\"The app.\"\"\"\n\n    def run(self, port: int = 80) -> None:\n        pass\n"},
+		{Filepath: "empty.go", Code: ""},
+		{Filepath: "style.css", Code: "body {}"},
+	}, &BatchOptions{ChunkOptions: ChunkOptions{Hierarchical: true}})
+	results = append(results, BatchResult{Filepath: "spilled.go"})
+
+	var buf bytes.Buffer
+	if err := EncodeBatchResults(&buf, results); err != nil {
+		t.Fatalf("EncodeBatchResults failed: %v", err)
+	}
+	size := buf.Len()
+	decoded, err := DecodeBatchResults(&buf)
+	if err != nil {
+		t.Fatalf("DecodeBatchResults failed: %v", err)
+	}
+	if len(decoded) != len(results) {
+		t.Fatalf("Expected %d results, got %d", len(results), len(decoded))
+	}
+
+	for i := range results {
+		if decoded[i].Filepath != results[i].Filepath || decoded[i].Skipped != results[i].Skipped {
+			t.Errorf("Result %d: decoded %+v, expected %+v", i, decoded[i], results[i])
+		}
+		if !reflect.DeepEqual(decoded[i].Chunks, results[i].Chunks) {
+			t.Errorf("Result %d: chunks did not survive round trip:\ndecoded:  %+v\noriginal: %+v", i, decoded[i].Chunks, results[i].Chunks)
+		}
+		if (decoded[i].Error == nil) != (results[i].Error == nil) {
+			t.Errorf("Result %d: decoded error %v, expected %v", i, decoded[i].Error, results[i].Error)
+		}
+	}
+	if !errors.Is(decoded[3].Error, ErrUnsupportedLanguage) || decoded[3].Error.Error() != results[3].Error.Error() {
+		t.Errorf("Expected the unsupported language error to survive, got %v", decoded[3].Error)
+	}
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if size >= len(data) {
+		t.Errorf("Expected the binary form (%d bytes) to be smaller than JSON (%d bytes)", size, len(data))
+	}
+}
+
+func TestBatchEncoderMaterializesLazyChunks(t *testing.T) {
+	code := "package main\n\nfunc main() {}\n"
+	eager, err := Chunk("main.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	lazy, err := Chunk("main.go", code, &ChunkOptions{LazyText: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := NewBatchEncoder(&buf).Encode(BatchResult{Filepath: "main.go", Chunks: lazy}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	result, err := NewBatchDecoder(&buf).Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if !reflect.DeepEqual(result.Chunks, eager) {
+		t.Errorf("Expected lazy chunks to decode as materialized chunks")
+	}
+}
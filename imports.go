@@ -1,6 +1,7 @@
 package codechunk
 
 import (
+	"sort"
 	"strings"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -13,6 +14,10 @@ func extractImportSymbols(node *sitter.Node, lang Language, code []byte) []*Extr
 
 	switch lang {
 	case LanguageTypeScript, LanguageJavaScript:
+		if node.Type() != "import_statement" {
+			entities = extractJSRequireSymbols(node, code)
+			break
+		}
 		entities = extractJSImportSymbols(node, source, code)
 	case LanguagePython:
 		entities = extractPythonImportSymbols(node, source, code)
@@ -22,6 +27,20 @@ func extractImportSymbols(node *sitter.Node, lang Language, code []byte) []*Extr
 		entities = extractRustImportSymbols(node, source, code)
 	case LanguageJava:
 		entities = extractJavaImportSymbols(node, source, code)
+	case LanguageProto:
+		// A protobuf import brings in every definition of the imported file
+		entities = append(entities, createImportEntity(node, source, source, code))
+	case LanguageElixir:
+		entities = extractElixirImportSymbols(node, code)
+	case LanguageLua:
+		// A require assigned to a variable is referred to by the variable, and a
+		// bare require by the last segment of the module name
+		module := luaRequiredModule(node, code)
+		name := lastDotSegment(module)
+		if declarator := node.ChildByFieldName("name"); declarator != nil && node.Type() == "variable_declaration" {
+			name = declarator.Content(code)
+		}
+		entities = append(entities, createImportEntity(node, name, module, code))
 	default:
 		entities = append(entities, createImportEntity(node, "import", source, code))
 	}
@@ -41,21 +60,34 @@ func extractJSImportSymbols(node *sitter.Node, source string, code []byte) []*Ex
 				switch clauseChild.Type() {
 				case "identifier":
 					name := string(code[clauseChild.StartByte():clauseChild.EndByte()])
-					entities = append(entities, createImportEntity(node, name, source, code))
+					entity := createImportEntity(node, name, source, code)
+					entity.IsDefault = true
+					entities = append(entities, entity)
 				case "named_imports":
 					for k := 0; k < int(clauseChild.ChildCount()); k++ {
 						spec := clauseChild.Child(k)
 						if spec.Type() == "import_specifier" {
 							name := extractImportSpecifierName(spec, code)
 							if name != "" {
-								entities = append(entities, createImportEntity(node, name, source, code))
+								entity := createImportEntity(node, name, source, code)
+								// import { default as X } is a default import under another name
+								if original := spec.ChildByFieldName("name"); original != nil && spec.ChildByFieldName("alias") != nil {
+									if original.Content(code) == "default" {
+										entity.IsDefault = true
+									} else {
+										entity.OriginalName = original.Content(code)
+									}
+								}
+								entities = append(entities, entity)
 							}
 						}
 					}
 				case "namespace_import":
-					if aliasNode := clauseChild.ChildByFieldName("alias"); aliasNode != nil {
+					if aliasNode := namespaceImportName(clauseChild); aliasNode != nil {
 						name := string(code[aliasNode.StartByte():aliasNode.EndByte()])
-						entities = append(entities, createImportEntity(node, name, source, code))
+						entity := createImportEntity(node, name, source, code)
+						entity.IsNamespace = true
+						entities = append(entities, entity)
 					}
 				}
 			}
@@ -69,6 +101,20 @@ func extractJSImportSymbols(node *sitter.Node, source string, code []byte) []*Ex
 	return entities
 }
 
+// namespaceImportName returns the identifier a namespace import binds the module
+// to, which grammar versions expose either as the alias field or a bare child
+func namespaceImportName(node *sitter.Node) *sitter.Node {
+	if alias := node.ChildByFieldName("alias"); alias != nil {
+		return alias
+	}
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child.Type() == "identifier" {
+			return child
+		}
+	}
+	return nil
+}
+
 func extractImportSpecifierName(spec *sitter.Node, code []byte) string {
 	if alias := spec.ChildByFieldName("alias"); alias != nil {
 		return string(code[alias.StartByte():alias.EndByte()])
@@ -90,13 +136,22 @@ func extractPythonImportSymbols(node *sitter.Node, source string, code []byte) [
 
 	switch node.Type() {
 	case "import_statement":
+		// Each module of import a, b as c is its own source
 		for i := 0; i < int(node.ChildCount()); i++ {
 			child := node.Child(i)
-			if child.Type() == "dotted_name" || child.Type() == "aliased_import" {
+			switch child.Type() {
+			case "dotted_name":
+				name := child.Content(code)
+				entities = append(entities, createImportEntity(node, name, name, code))
+			case "aliased_import":
 				name := extractPythonImportName(child, code)
-				if name != "" {
-					entities = append(entities, createImportEntity(node, name, source, code))
+				module := child.ChildByFieldName("name")
+				if name == "" || module == nil {
+					continue
 				}
+				entity := createImportEntity(node, name, module.Content(code), code)
+				entity.OriginalName = module.Content(code)
+				entities = append(entities, entity)
 			}
 		}
 
@@ -107,15 +162,26 @@ func extractPythonImportSymbols(node *sitter.Node, source string, code []byte) [
 			case "aliased_import":
 				name := extractPythonImportName(child, code)
 				if name != "" {
-					entities = append(entities, createImportEntity(node, name, source, code))
+					entity := createImportEntity(node, name, source, code)
+					if original := child.ChildByFieldName("name"); original != nil {
+						entity.OriginalName = original.Content(code)
+					}
+					entities = append(entities, entity)
 				}
 			case "identifier":
 				name := string(code[child.StartByte():child.EndByte()])
 				if name != "from" && name != "import" {
 					entities = append(entities, createImportEntity(node, name, source, code))
 				}
+			case "dotted_name":
+				// The imported names, as opposed to the module_name
+				if node.FieldNameForChild(i) == "name" {
+					entities = append(entities, createImportEntity(node, child.Content(code), source, code))
+				}
 			case "wildcard_import":
-				entities = append(entities, createImportEntity(node, "*", source, code))
+				entity := createImportEntity(node, "*", source, code)
+				entity.IsNamespace = true
+				entities = append(entities, entity)
 			}
 		}
 	}
@@ -124,6 +190,13 @@ func extractPythonImportSymbols(node *sitter.Node, source string, code []byte) [
 		entities = append(entities, createImportEntity(node, "import", source, code))
 	}
 
+	// from ..utils import helper is relative to the package two levels up
+	if level := len(source) - len(strings.TrimLeft(source, ".")); level > 0 {
+		for _, entity := range entities {
+			entity.Level = level
+		}
+	}
+
 	return entities
 }
 
@@ -146,14 +219,12 @@ func extractGoImportSymbols(node *sitter.Node, code []byte) []*ExtractedEntity {
 		child := node.Child(i)
 		switch child.Type() {
 		case "import_spec":
-			name, source := extractGoImportSpec(child, code)
-			entities = append(entities, createImportEntity(node, name, source, code))
+			entities = append(entities, createGoImportEntity(node, child, code))
 		case "import_spec_list":
 			for j := 0; j < int(child.ChildCount()); j++ {
 				spec := child.Child(j)
 				if spec.Type() == "import_spec" {
-					name, source := extractGoImportSpec(spec, code)
-					entities = append(entities, createImportEntity(node, name, source, code))
+					entities = append(entities, createGoImportEntity(node, spec, code))
 				}
 			}
 		}
@@ -166,6 +237,26 @@ func extractGoImportSymbols(node *sitter.Node, code []byte) []*ExtractedEntity {
 	return entities
 }
 
+// createGoImportEntity creates the import entity of an import spec. A named import
+// keeps the package name it replaces, taken from the last path element, and a dot
+// import brings the whole package into scope.
+func createGoImportEntity(node, spec *sitter.Node, code []byte) *ExtractedEntity {
+	name, source := extractGoImportSpec(spec, code)
+	entity := createImportEntity(node, name, source, code)
+
+	alias := spec.ChildByFieldName("name")
+	if alias == nil || source == "" {
+		return entity
+	}
+	switch alias.Type() {
+	case "dot":
+		entity.IsNamespace = true
+	case "package_identifier":
+		entity.OriginalName = source[strings.LastIndexByte(source, '/')+1:]
+	}
+	return entity
+}
+
 func extractGoImportSpec(spec *sitter.Node, code []byte) (name string, source string) {
 	if alias := spec.ChildByFieldName("name"); alias != nil {
 		name = string(code[alias.StartByte():alias.EndByte()])
@@ -220,10 +311,16 @@ func extractRustUseItems(node *sitter.Node, source string, code []byte, entities
 	case "use_as_clause":
 		if alias := node.ChildByFieldName("alias"); alias != nil {
 			name := string(code[alias.StartByte():alias.EndByte()])
-			*entities = append(*entities, createImportEntity(importNode, name, source, code))
+			entity := createImportEntity(importNode, name, source, code)
+			if path := node.ChildByFieldName("path"); path != nil {
+				entity.OriginalName = getLastSegment(path, code)
+			}
+			*entities = append(*entities, entity)
 		}
 	case "use_wildcard":
-		*entities = append(*entities, createImportEntity(importNode, "*", source, code))
+		entity := createImportEntity(importNode, "*", source, code)
+		entity.IsNamespace = true
+		*entities = append(*entities, entity)
 	}
 }
 
@@ -267,12 +364,182 @@ func createImportEntity(node *sitter.Node, name, source string, code []byte) *Ex
 			Start: int(node.StartByte()),
 			End:   int(node.EndByte()),
 		},
-		LineRange: LineRange{
-			Start: int(node.StartPoint().Row),
-			End:   int(node.EndPoint().Row),
-		},
-		Parent: nil,
-		Node:   node,
-		Source: sourcePtr,
+		LineRange: nodeLineRange(node),
+		Parent:    nil,
+		Node:      node,
+		Source:    sourcePtr,
+	}
+}
+
+// extractElixirImportSymbols extracts the modules of an alias, import, require or
+// use. An alias is referred to by its last segment or its as: name, and the
+// multi-alias form alias MyApp.{Repo, User} yields one import per module.
+func extractElixirImportSymbols(node *sitter.Node, code []byte) []*ExtractedEntity {
+	entities := make([]*ExtractedEntity, 0)
+	head := elixirDefinitionHead(node)
+	if head == nil {
+		return entities
+	}
+	isAlias := elixirCallTarget(node, code) == "alias"
+
+	if head.Type() == "dot" && isAlias {
+		left := head.ChildByFieldName("left")
+		right := head.ChildByFieldName("right")
+		if left != nil && right != nil && right.Type() == "tuple" {
+			for i := 0; i < int(right.NamedChildCount()); i++ {
+				module := right.NamedChild(i).Content(code)
+				entities = append(entities, createImportEntity(node, lastDotSegment(module), left.Content(code)+"."+module, code))
+			}
+			return entities
+		}
+	}
+
+	source := head.Content(code)
+	name := source
+	if isAlias {
+		name = lastDotSegment(source)
+		if as := elixirKeyword(head.Parent(), "as", code); as != "" {
+			name = as
+		}
 	}
+	return append(entities, createImportEntity(node, name, source, code))
+}
+
+// elixirKeyword returns the value of a keyword argument such as as: Acc in an
+// Elixir call's arguments, or "" if it is not given
+func elixirKeyword(args *sitter.Node, key string, code []byte) string {
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		keywords := args.NamedChild(i)
+		if keywords.Type() != "keywords" {
+			continue
+		}
+		for j := 0; j < int(keywords.NamedChildCount()); j++ {
+			pair := keywords.NamedChild(j)
+			keyNode, value := pair.ChildByFieldName("key"), pair.ChildByFieldName("value")
+			if keyNode != nil && value != nil && strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(keyNode.Content(code)), ":")) == key {
+				return value.Content(code)
+			}
+		}
+	}
+	return ""
+}
+
+// lastDotSegment returns the part of a dotted name after its last dot
+func lastDotSegment(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+// ImportUse is an occurrence of an imported name in the source outside the import
+// statements
+type ImportUse struct {
+	Name   string `json:"name"`   // Identifier as written, the first segment of a dotted import name
+	Offset int    `json:"offset"` // Byte offset of the identifier
+}
+
+// memberFields are the fields of member access nodes that hold the member, such as
+// Println in fmt.Println or path in os.path, which never refers to an import itself
+var memberFields = map[string]bool{
+	"attribute": true,
+	"property":  true,
+	"field":     true,
+}
+
+// importUses returns the uses of imported names in a parsed file if opts filter
+// imports by them, and nil otherwise, as nothing else needs them
+func importUses(parsed *ParsedFile, code []byte, opts ChunkOptions) []ImportUse {
+	if !opts.FilterImports || opts.ContextMode == ContextModeNone {
+		return nil
+	}
+	return extractImportUses(parsed.Tree.RootNode(), code, parsed.Entities)
+}
+
+// extractImportUses collects the identifier tokens of the tree that name one of the
+// imports, in source order. Comments and strings have no identifier tokens, and
+// identifiers inside the import statements themselves are skipped.
+func extractImportUses(rootNode *sitter.Node, code []byte, entities []*ExtractedEntity) []ImportUse {
+	names := make(map[string]bool)
+	statements := make([]ByteRange, 0)
+	for _, entity := range entities {
+		if entity.Type != EntityTypeImport {
+			continue
+		}
+		statements = append(statements, entity.ByteRange)
+		if key := importKey(entity.Name); key != "" {
+			names[key] = true
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	statements = disjointRanges(statements)
+
+	type item struct {
+		node  *sitter.Node
+		field string
+	}
+	uses := make([]ImportUse, 0)
+	stack := []item{{node: rootNode}}
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		node := top.node
+		if node == nil || memberFields[top.field] || inAnyRange(int(node.StartByte()), int(node.EndByte()), statements) {
+			continue
+		}
+
+		if node.ChildCount() == 0 {
+			// Elixir module aliases such as MyApp.Repo are single tokens
+			if nodeType := node.Type(); nodeType == "alias" || strings.Contains(nodeType, "identifier") {
+				if name := importKey(node.Content(code)); names[name] {
+					uses = append(uses, ImportUse{Name: name, Offset: int(node.StartByte())})
+				}
+			}
+			continue
+		}
+
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, item{node: node.Child(i), field: node.FieldNameForChild(i)})
+		}
+	}
+
+	return uses
+}
+
+// importKey returns the identifier an import is referred to by: its name, or the
+// first segment of a dotted name such as Python's import os.path. Returns "" for
+// imports that cannot be matched to identifiers, such as wildcard imports or
+// imports named by a path.
+func importKey(name string) string {
+	if !isIdentifierPath(name) {
+		return ""
+	}
+	if i := strings.IndexAny(name, ".:"); i != -1 {
+		return name[:i]
+	}
+	return name
+}
+
+// disjointRanges sorts ranges by start and joins overlapping ones, such as an
+// import statement and the import specs nested in it
+func disjointRanges(ranges []ByteRange) []ByteRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	joined := ranges[:0]
+	for _, r := range ranges {
+		if n := len(joined); n > 0 && r.Start < joined[n-1].End {
+			joined[n-1].End = max(joined[n-1].End, r.End)
+			continue
+		}
+		joined = append(joined, r)
+	}
+	return joined
+}
+
+// inAnyRange reports whether the range from start to end lies within one of
+// ranges, which must be sorted and disjoint
+func inAnyRange(start, end int, ranges []ByteRange) bool {
+	i := sort.Search(len(ranges), func(i int) bool { return ranges[i].Start > start }) - 1
+	return i >= 0 && end <= ranges[i].End
 }
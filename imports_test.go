@@ -1,6 +1,9 @@
 package codechunk
 
 import (
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -366,3 +369,255 @@ func TestPythonWildcardImport(t *testing.T) {
 		t.Error("Expected to find wildcard import '*'")
 	}
 }
+
+func TestImportAliasAndFlags(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		code     string
+		name     string
+		source   string
+		original string
+		isDef    bool
+		isNs     bool
+	}{
+		{LanguageTypeScript, `import React from 'react';`, "React", "react", "", true, false},
+		{LanguageTypeScript, `import * as path from 'path';`, "path", "path", "", false, true},
+		{LanguageTypeScript, `import { useState as useLocal } from 'react';`, "useLocal", "react", "useState", false, false},
+		{LanguageJavaScript, `import { default as Lodash } from 'lodash';`, "Lodash", "lodash", "", true, false},
+		{LanguagePython, `import numpy as np`, "np", "numpy", "numpy", false, false},
+		{LanguagePython, `import os, sys`, "sys", "sys", "", false, false},
+		{LanguagePython, `from os.path import join as path_join`, "path_join", "os.path", "join", false, false},
+		{LanguagePython, `from typing import List`, "List", "typing", "", false, false},
+		{LanguagePython, `from os import *`, "*", "os", "", false, true},
+		{LanguageGo, `import str "strings"`, "str", "strings", "strings", false, false},
+		{LanguageGo, `import . "net/http"`, ".", "net/http", "", false, true},
+		{LanguageRust, `use std::collections::HashMap as Map;`, "Map", "std::collections::HashMap", "HashMap", false, false},
+		{LanguageRust, `use serde::Serialize as Ser;`, "Ser", "serde::Serialize", "Serialize", false, false},
+		{LanguageRust, `use std::io::*;`, "*", "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		parseResult, err := parseString(tt.code, tt.lang)
+		if err != nil {
+			t.Fatalf("Parse failed for %q: %v", tt.code, err)
+		}
+		entities := extractEntities(parseResult.Tree.RootNode(), tt.lang, []byte(tt.code))
+
+		var imp *ExtractedEntity
+		for _, e := range entities {
+			if e.Type == EntityTypeImport && e.Name == tt.name {
+				imp = e
+			}
+		}
+		if imp == nil {
+			t.Errorf("%q: expected an import named %q", tt.code, tt.name)
+			continue
+		}
+		if tt.source != "" && (imp.Source == nil || *imp.Source != tt.source) {
+			t.Errorf("%q: expected source %q, got %v", tt.code, tt.source, imp.Source)
+		}
+		if imp.OriginalName != tt.original || imp.IsDefault != tt.isDef || imp.IsNamespace != tt.isNs {
+			t.Errorf("%q: got original %q, default %v, namespace %v", tt.code, imp.OriginalName, imp.IsDefault, imp.IsNamespace)
+		}
+	}
+}
+
+func TestContextImportsCarryAliases(t *testing.T) {
+	code := "import numpy as np\n\ndef mean(xs):\n    return np.mean(xs)\n"
+	chunks, err := Chunk("stats.py", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	for _, imp := range chunks[0].Context.Imports {
+		if imp.Name == "np" {
+			if imp.OriginalName != "numpy" || imp.Source != "numpy" {
+				t.Errorf("Expected np to map back to numpy, got %+v", imp)
+			}
+			return
+		}
+	}
+	t.Errorf("Expected the np import in the context, got %+v", chunks[0].Context.Imports)
+}
+
+func TestFilterImportsMatchesIdentifiers(t *testing.T) {
+	code := `import os
+import numpy as np
+from typing import *
+
+def cost(items):
+    """Sums the costs of items, not read from os."""
+    total = np.sum([item.os for item in items])
+    return total
+`
+	chunks, err := Chunk("cost.py", code, &ChunkOptions{FilterImports: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	names := make(map[string]bool)
+	for _, chunk := range chunks {
+		if !strings.Contains(chunk.Text, "def cost") {
+			continue
+		}
+		for _, imp := range chunk.Context.Imports {
+			names[imp.Name] = true
+		}
+	}
+	if !names["np"] {
+		t.Errorf("Expected np, used only in the body, to be kept, got %v", names)
+	}
+	if names["os"] {
+		t.Errorf("Expected os, only in a docstring and as an attribute, to be dropped, got %v", names)
+	}
+	if !names["*"] {
+		t.Errorf("Expected the wildcard import to be kept, got %v", names)
+	}
+}
+
+func TestFilterImportsUsedOnlyInBody(t *testing.T) {
+	code := `import { useEffect, useState } from 'react';
+
+export function Clock() {
+  useEffect(() => {
+    tick();
+  });
+  return null;
+}
+`
+	chunks, err := Chunk("clock.ts", code, &ChunkOptions{FilterImports: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	stream, err := ChunkStream("clock.ts", code, &ChunkOptions{FilterImports: true})
+	if err != nil {
+		t.Fatalf("ChunkStream failed: %v", err)
+	}
+
+	for _, chunk := range []CodeChunk{chunks[0], <-stream} {
+		var names []string
+		for _, imp := range chunk.Context.Imports {
+			names = append(names, imp.Name)
+		}
+		if len(names) != 1 || names[0] != "useEffect" {
+			t.Errorf("Expected only useEffect, got %v", names)
+		}
+	}
+	for range stream {
+	}
+}
+
+func TestPythonRelativeImports(t *testing.T) {
+	code := "from ..utils import helper\nfrom . import models\nfrom ...core.db import session as db\nfrom typing import List\n"
+	chunks, err := Chunk("src/pkg/auth/service.py", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type resolved struct {
+		source string
+		level  int
+		module string
+	}
+	expected := map[string]resolved{
+		"helper": {"..utils", 2, "pkg.utils"},
+		"models": {".", 1, "pkg.auth"},
+		"db":     {"...core.db", 3, ""},
+		"List":   {"typing", 0, ""},
+	}
+	got := make(map[string]resolved)
+	for _, imp := range chunks[0].Context.Imports {
+		got[imp.Name] = resolved{imp.Source, imp.Level, imp.ResolvedSource}
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("Expected imports %+v, got %+v", expected, got)
+	}
+}
+
+func TestResolvePythonImportAbsolutePath(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "work", "myrepo")
+	file := filepath.Join(root, "src", "pkg", "auth", "service.py")
+	code := "from ..utils import helper\n\ndef login(user):\n    return helper(user)\n"
+
+	tests := []struct {
+		name       string
+		provenance *Provenance
+		want       string
+	}{
+		{"repo root", &Provenance{RepoRoot: root}, "pkg.utils"},
+		{"rel path", &Provenance{RelPath: "pkg/auth/service.py"}, "pkg.utils"},
+		{"no provenance", nil, ""},
+	}
+	for _, tt := range tests {
+		chunks, err := Chunk(file, code, &ChunkOptions{Provenance: tt.provenance})
+		if err != nil {
+			t.Fatalf("%s: Chunk failed: %v", tt.name, err)
+		}
+		var got []string
+		for _, chunk := range chunks {
+			for _, imp := range chunk.Context.Imports {
+				if imp.Source == "..utils" {
+					got = append(got, imp.ResolvedSource)
+				}
+			}
+		}
+		if len(got) == 0 {
+			t.Fatalf("%s: no chunk imports ..utils", tt.name)
+		}
+		for _, resolved := range got {
+			if resolved != tt.want {
+				t.Errorf("%s: expected ResolvedSource %q, got %q", tt.name, tt.want, resolved)
+			}
+		}
+	}
+}
+
+func TestResolvePythonImport(t *testing.T) {
+	tests := []struct {
+		source   string
+		filepath string
+		want     string
+	}{
+		{"..utils", "src/pkg/auth/service.py", "pkg.utils"},
+		{".models.user", "pkg/auth/service.py", "pkg.auth.models.user"},
+		{"..", "pkg/auth/service.py", "pkg"},
+		{".helpers", "pkg/auth/__init__.py", "pkg.auth.helpers"},
+		{"..", "pkg/auth/__init__.py", "pkg"},
+		{".sibling", "service.py", ""},
+		{"...core", "pkg/auth/service.py", ""},
+		{"os.path", "pkg/auth/service.py", ""},
+		{".utils", "my-script.py", ""},
+	}
+
+	for _, tt := range tests {
+		if got := resolvePythonImport(tt.source, pythonModuleName(tt.filepath), isPythonPackageFile(tt.filepath)); got != tt.want {
+			t.Errorf("resolvePythonImport(%q, %q) = %q, want %q", tt.source, tt.filepath, got, tt.want)
+		}
+	}
+}
+
+func TestInAnyRange(t *testing.T) {
+	// An import statement with nested import specs, and another import after a gap
+	ranges := disjointRanges([]ByteRange{{Start: 40, End: 60}, {Start: 10, End: 30}, {Start: 12, End: 20}, {Start: 22, End: 30}})
+	if want := []ByteRange{{Start: 10, End: 30}, {Start: 40, End: 60}}; !reflect.DeepEqual(ranges, want) {
+		t.Fatalf("Expected disjoint ranges %v, got %v", want, ranges)
+	}
+
+	tests := []struct {
+		start, end int
+		want       bool
+	}{
+		{10, 30, true},
+		{22, 25, true},
+		{45, 60, true},
+		{5, 15, false},
+		{25, 35, false},
+		{32, 38, false},
+		{61, 70, false},
+	}
+	for _, tt := range tests {
+		if got := inAnyRange(tt.start, tt.end, ranges); got != tt.want {
+			t.Errorf("inAnyRange(%d, %d): expected %v, got %v", tt.start, tt.end, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package codechunk
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+)
+
+// codeChunkSchema is the JSON Schema describing serialized CodeChunk and BatchResult values
+//
+//go:embed codechunk.schema.json
+var codeChunkSchema []byte
+
+// JSONSchema returns the JSON Schema (draft 2020-12) for the serialized form of
+// CodeChunk and BatchResult. The returned slice is a copy and may be modified.
+func JSONSchema() []byte {
+	schema := make([]byte, len(codeChunkSchema))
+	copy(schema, codeChunkSchema)
+	return schema
+}
+
+// Error codes used when serializing BatchResult errors
+const (
+	ErrorCodeUnsupportedLanguage = "unsupported_language"
+	ErrorCodeParseFailed         = "parse_failed"
+	ErrorCodeCancelled           = "cancelled"
+	ErrorCodeUnchanged           = "unchanged"
+	ErrorCodeSkipped             = "skipped"
+	ErrorCodePanicked            = "panicked"
+	ErrorCodeLimitExceeded       = "limit_exceeded"
+	ErrorCodeUnknown             = "unknown"
+)
+
+// ErrorCode returns a stable code identifying the kind of a chunking error.
+// Returns an empty string for a nil error.
+func ErrorCode(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, ErrUnsupportedLanguage):
+		return ErrorCodeUnsupportedLanguage
+	case errors.Is(err, ErrParseFailed):
+		return ErrorCodeParseFailed
+	case errors.Is(err, ErrCancelled):
+		return ErrorCodeCancelled
+	case errors.Is(err, ErrUnchanged):
+		return ErrorCodeUnchanged
+	case errors.Is(err, ErrSkipped):
+		return ErrorCodeSkipped
+	case errors.Is(err, ErrPanicked):
+		return ErrorCodePanicked
+	case errors.Is(err, ErrLimitExceeded):
+		return ErrorCodeLimitExceeded
+	default:
+		return ErrorCodeUnknown
+	}
+}
+
+// ErrorFromCode restores an error from its code and message, as produced by
+// ErrorCode and err.Error(). The returned error matches the sentinel error for
+// the code with errors.Is. Returns nil when both code and message are empty.
+func ErrorFromCode(code, message string) error {
+	if code == "" && message == "" {
+		return nil
+	}
+	if code == "" {
+		code = ErrorCodeUnknown
+	}
+	return &serializedError{code: code, message: message}
+}
+
+// serializedError is an error restored from its serialized form. It matches
+// the sentinel error for its code with errors.Is.
+type serializedError struct {
+	code    string
+	message string
+}
+
+func (e *serializedError) Error() string {
+	return e.message
+}
+
+func (e *serializedError) Is(target error) bool {
+	switch e.code {
+	case ErrorCodeUnsupportedLanguage:
+		return target == ErrUnsupportedLanguage
+	case ErrorCodeParseFailed:
+		return target == ErrParseFailed
+	case ErrorCodeCancelled:
+		return target == ErrCancelled
+	case ErrorCodeUnchanged:
+		return target == ErrUnchanged
+	case ErrorCodeSkipped:
+		return target == ErrSkipped
+	case ErrorCodePanicked:
+		return target == ErrPanicked
+	case ErrorCodeLimitExceeded:
+		return target == ErrLimitExceeded
+	default:
+		return false
+	}
+}
+
+// batchResultJSON is the wire representation of BatchResult
+type batchResultJSON struct {
+	Filepath  string      `json:"filepath"`
+	Chunks    []CodeChunk `json:"chunks"`
+	Error     string      `json:"error,omitempty"`
+	ErrorCode string      `json:"errorCode,omitempty"`
+	Skipped   bool        `json:"skipped,omitempty"`
+}
+
+// MarshalJSON encodes the result with its error as a message and an error code.
+// Lazy chunks are materialized.
+func (r BatchResult) MarshalJSON() ([]byte, error) {
+	out := batchResultJSON{
+		Filepath:  r.Filepath,
+		Chunks:    materializeChunks(r.Chunks),
+		ErrorCode: ErrorCode(r.Error),
+		Skipped:   r.Skipped,
+	}
+	if r.Error != nil {
+		out.Error = r.Error.Error()
+	}
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON decodes a result produced by MarshalJSON. The restored error
+// keeps its message and matches the original sentinel error with errors.Is.
+func (r *BatchResult) UnmarshalJSON(data []byte) error {
+	var in batchResultJSON
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	*r = BatchResult{
+		Filepath: in.Filepath,
+		Chunks:   in.Chunks,
+		Skipped:  in.Skipped,
+	}
+	r.Error = ErrorFromCode(in.ErrorCode, in.Error)
+	return nil
+}
@@ -0,0 +1,215 @@
+package codechunk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCodeChunkJSONRoundTrip(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+// Greet prints a greeting.
+func Greet(name string) {
+	fmt.Println("Hello, " + name)
+}
+`
+	chunks, err := Chunk("main.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	data, err := json.Marshal(chunks)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded []CodeChunk
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(chunks, decoded) {
+		t.Errorf("Round trip mismatch:\noriginal: %+v\ndecoded:  %+v", chunks, decoded)
+	}
+}
+
+func TestBatchResultJSONRoundTrip(t *testing.T) {
+	results := ChunkBatch([]FileInput{
+		{Filepath: "main.go", Code: "package main\n\nfunc main() {}\n"},
+		{Filepath: "style.css", Code: "body {}"},
+	}, nil)
+
+	data, err := json.Marshal(results)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(data), `"errorCode":"unsupported_language"`) {
+		t.Errorf("Expected error code in output, got %s", data)
+	}
+
+	var decoded []BatchResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+
+	if decoded[0].Error != nil {
+		t.Errorf("Expected nil error, got %v", decoded[0].Error)
+	}
+	if !reflect.DeepEqual(decoded[0].Chunks, results[0].Chunks) {
+		t.Error("Chunks did not survive round trip")
+	}
+	if !errors.Is(decoded[1].Error, ErrUnsupportedLanguage) {
+		t.Errorf("Expected ErrUnsupportedLanguage, got %v", decoded[1].Error)
+	}
+	if decoded[1].Error.Error() != results[1].Error.Error() {
+		t.Errorf("Expected message %q, got %q", results[1].Error.Error(), decoded[1].Error.Error())
+	}
+}
+
+func TestSkippedBatchResultJSONRoundTrip(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := BatchResult{Filepath: "main.go", Error: cancelledError(ctx), Skipped: true}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded BatchResult
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !decoded.Skipped || !errors.Is(decoded.Error, ErrCancelled) {
+		t.Errorf("Expected skipped cancelled result, got %+v", decoded)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected string
+	}{
+		{nil, ""},
+		{ErrUnsupportedLanguage, ErrorCodeUnsupportedLanguage},
+		{errors.Join(ErrParseFailed, errors.New("timeout")), ErrorCodeParseFailed},
+		{cancelledError(context.Background()), ErrorCodeCancelled},
+		{fmt.Errorf("%w: %s", ErrSkipped, SkipReasonBinary), ErrorCodeSkipped},
+		{&PanicError{Filepath: "a.go", Value: "boom"}, ErrorCodePanicked},
+		{&ChunkError{Stage: StageBuild, Err: ErrLimitExceeded}, ErrorCodeLimitExceeded},
+		{errors.New("other"), ErrorCodeUnknown},
+	}
+
+	for _, tt := range tests {
+		if code := ErrorCode(tt.err); code != tt.expected {
+			t.Errorf("ErrorCode(%v) = %q, expected %q", tt.err, code, tt.expected)
+		}
+	}
+}
+
+func TestJSONSchemaCoversFields(t *testing.T) {
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"$defs"`
+	}
+	if err := json.Unmarshal(JSONSchema(), &schema); err != nil {
+		t.Fatalf("Schema is not valid JSON: %v", err)
+	}
+
+	types := map[string]reflect.Type{
+		"LineRange":       reflect.TypeOf(LineRange{}),
+		"ByteRange":       reflect.TypeOf(ByteRange{}),
+		"Position":        reflect.TypeOf(Position{}),
+		"Span":            reflect.TypeOf(Span{}),
+		"ParseError":      reflect.TypeOf(ParseError{}),
+		"EntityInfo":      reflect.TypeOf(EntityInfo{}),
+		"ChunkEntityInfo": reflect.TypeOf(ChunkEntityInfo{}),
+		"SiblingInfo":     reflect.TypeOf(SiblingInfo{}),
+		"ImportInfo":      reflect.TypeOf(ImportInfo{}),
+		"Param":           reflect.TypeOf(Param{}),
+		"TypeParam":       reflect.TypeOf(TypeParam{}),
+		"ReferenceInfo":   reflect.TypeOf(ReferenceInfo{}),
+		"CommentTag":      reflect.TypeOf(CommentTag{}),
+		"NotebookCell":    reflect.TypeOf(NotebookCell{}),
+		"ChunkContext":    reflect.TypeOf(ChunkContext{}),
+		"CodeChunk":       reflect.TypeOf(CodeChunk{}),
+	}
+
+	for name, typ := range types {
+		def, ok := schema.Defs[name]
+		if !ok {
+			t.Errorf("Schema is missing definition %s", name)
+			continue
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			tag := strings.Split(typ.Field(i).Tag.Get("json"), ",")[0]
+			if tag == "" || tag == "-" {
+				continue
+			}
+			if _, ok := def.Properties[tag]; !ok {
+				t.Errorf("Schema definition %s is missing property %q", name, tag)
+			}
+		}
+	}
+}
+
+func TestWriteJSONL(t *testing.T) {
+	code := `package main
+
+// Less reports whether a sorts before b.
+func Less(a, b int) bool {
+	return a < b
+}
+
+// Max returns the larger of a and b.
+func Max(a, b int) int {
+	if Less(a, b) {
+		return b
+	}
+	return a
+}
+`
+	opts := ChunkOptions{MaxChunkSize: 60}
+	chunks, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	opts.LazyText = true
+	lazy, err := Chunk("main.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk with LazyText failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected at least 2 chunks, got %d", len(chunks))
+	}
+
+	var b strings.Builder
+	if err := WriteJSONL(&b, lazy); err != nil {
+		t.Fatalf("WriteJSONL failed: %v", err)
+	}
+	if strings.Contains(b.String(), `\u003c`) || !strings.Contains(b.String(), "a < b") {
+		t.Error("Expected HTML characters not to be escaped")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n")
+	if len(lines) != len(chunks) {
+		t.Fatalf("Expected %d lines, got %d", len(chunks), len(lines))
+	}
+	for i, line := range lines {
+		var decoded CodeChunk
+		if err := json.Unmarshal([]byte(line), &decoded); err != nil {
+			t.Fatalf("Line %d: Unmarshal failed: %v", i, err)
+		}
+		if !reflect.DeepEqual(decoded, chunks[i]) {
+			t.Errorf("Line %d: decoded chunk differs from the chunk", i)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+package codechunk
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// JSONLEncoder writes chunks to a stream as JSON lines, one chunk per line. Fields
+// are encoded in the declaration order of CodeChunk, so every line has the same
+// layout. Lazy chunks are materialized before they are encoded.
+type JSONLEncoder struct {
+	enc *json.Encoder
+}
+
+// NewJSONLEncoder returns an encoder writing JSON lines to w. HTML characters,
+// common in source code, are not escaped.
+func NewJSONLEncoder(w io.Writer) *JSONLEncoder {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	return &JSONLEncoder{enc: enc}
+}
+
+// Encode writes chunk as a single line.
+func (e *JSONLEncoder) Encode(chunk CodeChunk) error {
+	return e.enc.Encode(chunk.Materialize())
+}
+
+// WriteJSONL writes chunks to w as JSON lines, one chunk per line, stopping at
+// the first error. See JSONLEncoder.
+func WriteJSONL(w io.Writer, chunks []CodeChunk) error {
+	enc := NewJSONLEncoder(w)
+	for _, chunk := range chunks {
+		if err := enc.Encode(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
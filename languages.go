@@ -6,29 +6,42 @@ import (
 	"sync"
 
 	sitter "github.com/smacker/go-tree-sitter"
+	"github.com/smacker/go-tree-sitter/elixir"
 	"github.com/smacker/go-tree-sitter/golang"
+	"github.com/smacker/go-tree-sitter/hcl"
 	"github.com/smacker/go-tree-sitter/java"
 	"github.com/smacker/go-tree-sitter/javascript"
+	"github.com/smacker/go-tree-sitter/lua"
+	"github.com/smacker/go-tree-sitter/protobuf"
 	"github.com/smacker/go-tree-sitter/python"
 	"github.com/smacker/go-tree-sitter/rust"
+	"github.com/smacker/go-tree-sitter/sql"
 	"github.com/smacker/go-tree-sitter/typescript/tsx"
 )
 
 // LanguageExtensions maps file extensions to supported languages
 var LanguageExtensions = map[string]Language{
-	".ts":   LanguageTypeScript,
-	".tsx":  LanguageTypeScript,
-	".mts":  LanguageTypeScript,
-	".cts":  LanguageTypeScript,
-	".js":   LanguageJavaScript,
-	".jsx":  LanguageJavaScript,
-	".mjs":  LanguageJavaScript,
-	".cjs":  LanguageJavaScript,
-	".py":   LanguagePython,
-	".pyi":  LanguagePython,
-	".rs":   LanguageRust,
-	".go":   LanguageGo,
-	".java": LanguageJava,
+	".ts":     LanguageTypeScript,
+	".tsx":    LanguageTypeScript,
+	".mts":    LanguageTypeScript,
+	".cts":    LanguageTypeScript,
+	".js":     LanguageJavaScript,
+	".jsx":    LanguageJavaScript,
+	".mjs":    LanguageJavaScript,
+	".cjs":    LanguageJavaScript,
+	".py":     LanguagePython,
+	".pyi":    LanguagePython,
+	".rs":     LanguageRust,
+	".go":     LanguageGo,
+	".java":   LanguageJava,
+	".sql":    LanguageSQL,
+	".tf":     LanguageHCL,
+	".tfvars": LanguageHCL,
+	".hcl":    LanguageHCL,
+	".proto":  LanguageProto,
+	".ex":     LanguageElixir,
+	".exs":    LanguageElixir,
+	".lua":    LanguageLua,
 }
 
 // DetectLanguage detects the programming language from a file path based on its extension.
@@ -46,7 +59,9 @@ func IsLanguageSupported(lang Language) bool {
 	switch lang {
 	case LanguageTypeScript, LanguageJavaScript,
 		LanguagePython, LanguageRust,
-		LanguageGo, LanguageJava:
+		LanguageGo, LanguageJava,
+		LanguageSQL, LanguageHCL, LanguageProto,
+		LanguageElixir, LanguageLua:
 		return true
 	default:
 		return false
@@ -90,6 +105,16 @@ func getLanguageGrammar(lang Language) *sitter.Language {
 		grammar = golang.GetLanguage()
 	case LanguageJava:
 		grammar = java.GetLanguage()
+	case LanguageSQL:
+		grammar = sql.GetLanguage()
+	case LanguageHCL:
+		grammar = hcl.GetLanguage()
+	case LanguageProto:
+		grammar = protobuf.GetLanguage()
+	case LanguageElixir:
+		grammar = elixir.GetLanguage()
+	case LanguageLua:
+		grammar = lua.GetLanguage()
 	default:
 		return nil
 	}
@@ -0,0 +1,87 @@
+package codechunk
+
+import "strings"
+
+// lazyText holds what a chunk built with LazyText needs to materialize its text.
+// source is shared by every chunk of a file, so chunks only add their byte range.
+type lazyText struct {
+	source  string
+	opts    ChunkOptions
+	overlap string
+	imports string
+}
+
+// contextualizeText builds ContextualizedText from the chunk text, its context,
+// the overlap with the previous chunk and the optional import block
+func contextualizeText(opts ChunkOptions, text string, ctx ChunkContext, overlapText string, importBlock string) string {
+	contextualized := formatChunk(opts, text, ctx, overlapText)
+	if importBlock != "" {
+		contextualized = importBlock + "\n\n" + contextualized
+	}
+	return contextualized
+}
+
+// contextualize sets ContextualizedText or ContextHeader of a chunk as selected by
+// opts.ContextualizedTextMode. In ContextualizedTextModeNone nothing is formatted.
+func (c *CodeChunk) contextualize(opts ChunkOptions, text string, overlapText string, importBlock string) {
+	switch opts.ContextualizedTextMode {
+	case ContextualizedTextModeNone:
+	case ContextualizedTextModeSeparate:
+		c.ContextHeader = contextualizeText(opts, "", c.Context, overlapText, importBlock)
+	default:
+		c.ContextualizedText = contextualizeText(opts, text, c.Context, overlapText, importBlock)
+	}
+}
+
+// Content returns the chunk's source text. For chunks built with LazyText it is
+// sliced from the retained source without copying; otherwise it is Text.
+func (c CodeChunk) Content() string {
+	if c.lazy == nil {
+		return c.Text
+	}
+	return c.lazy.source[c.ByteRange.Start:c.ByteRange.End]
+}
+
+// Contextualized returns the chunk's text with semantic context prepended. For
+// chunks built with LazyText it is formatted on each call; otherwise it is
+// ContextualizedText. It is empty unless the chunk was built with
+// ContextualizedTextModePrefix.
+func (c CodeChunk) Contextualized() string {
+	if c.lazy == nil {
+		return c.ContextualizedText
+	}
+	if mode := c.lazy.opts.ContextualizedTextMode; mode == ContextualizedTextModeNone || mode == ContextualizedTextModeSeparate {
+		return ""
+	}
+	return contextualizeText(c.lazy.opts, c.Content(), c.Context, c.lazy.overlap, c.lazy.imports)
+}
+
+// Materialize returns a copy of the chunk with Text and ContextualizedText or
+// ContextHeader filled in and the retained source released. Lazy chunks must be
+// materialized before they are serialized, since only the exported fields are
+// encoded.
+func (c CodeChunk) Materialize() CodeChunk {
+	if c.lazy == nil {
+		return c
+	}
+	c.Text = strings.Clone(c.Content())
+	c.contextualize(c.lazy.opts, c.Text, c.lazy.overlap, c.lazy.imports)
+	c.lazy = nil
+	return c
+}
+
+// materializeChunks returns chunks with the lazy ones materialized, as a copy if
+// any of them is lazy
+func materializeChunks(chunks []CodeChunk) []CodeChunk {
+	for i := range chunks {
+		if chunks[i].lazy == nil {
+			continue
+		}
+		out := make([]CodeChunk, len(chunks))
+		for j, chunk := range chunks {
+			out[j] = chunk.Materialize()
+		}
+		return out
+	}
+	return chunks
+}
@@ -0,0 +1,130 @@
+package codechunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const lazyTextSource = `package main
+
+import "fmt"
+
+// Greet prints a greeting.
+func Greet(name string) {
+	fmt.Println("Hello, " + name)
+}
+
+// Farewell prints a farewell.
+func Farewell(name string) {
+	fmt.Println("Goodbye, " + name)
+}
+`
+
+func TestChunkLazyText(t *testing.T) {
+	opts := ChunkOptions{MaxChunkSize: 80, IncludeImportsInText: true}
+	eager, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	opts.LazyText = true
+	lazy, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk with LazyText failed: %v", err)
+	}
+	if len(lazy) != len(eager) || len(lazy) < 2 {
+		t.Fatalf("Expected %d chunks (at least 2), got %d", len(eager), len(lazy))
+	}
+
+	for i := range lazy {
+		if lazy[i].Text != "" || lazy[i].ContextualizedText != "" {
+			t.Errorf("Chunk %d: expected empty text fields in LazyText mode", i)
+		}
+		if lazy[i].Content() != eager[i].Text {
+			t.Errorf("Chunk %d: Content() = %q, expected %q", i, lazy[i].Content(), eager[i].Text)
+		}
+		if lazy[i].Contextualized() != eager[i].ContextualizedText {
+			t.Errorf("Chunk %d: Contextualized() = %q, expected %q", i, lazy[i].Contextualized(), eager[i].ContextualizedText)
+		}
+		if !reflect.DeepEqual(lazy[i].Materialize(), eager[i]) {
+			t.Errorf("Chunk %d: materialized chunk differs from eager chunk", i)
+		}
+	}
+
+	if eager[0].Content() != eager[0].Text || eager[0].Contextualized() != eager[0].ContextualizedText {
+		t.Error("Expected accessors to return the text fields of eager chunks")
+	}
+}
+
+func TestChunkStreamLazyText(t *testing.T) {
+	ch, err := ChunkStream("main.go", lazyTextSource, &ChunkOptions{MaxChunkSize: 80, LazyText: true})
+	if err != nil {
+		t.Fatalf("ChunkStream failed: %v", err)
+	}
+
+	var rebuilt strings.Builder
+	for chunk := range ch {
+		if chunk.Text != "" {
+			t.Errorf("Chunk %d: expected empty Text in LazyText mode", chunk.Index)
+		}
+		if !strings.Contains(chunk.Contextualized(), chunk.Content()) {
+			t.Errorf("Chunk %d: Contextualized() does not contain Content()", chunk.Index)
+		}
+		rebuilt.WriteString(chunk.Content())
+	}
+	if !strings.Contains(rebuilt.String(), "func Farewell") {
+		t.Errorf("Expected streamed content to cover the source, got %q", rebuilt.String())
+	}
+}
+
+func TestContextualizedTextMode(t *testing.T) {
+	opts := ChunkOptions{MaxChunkSize: 80, IncludeImportsInText: true}
+	prefixed, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	opts.ContextualizedTextMode = ContextualizedTextModeNone
+	none, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk with ContextualizedTextModeNone failed: %v", err)
+	}
+	opts.ContextualizedTextMode = ContextualizedTextModeSeparate
+	separate, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk with ContextualizedTextModeSeparate failed: %v", err)
+	}
+	opts.LazyText = true
+	lazy, err := Chunk("main.go", lazyTextSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk with LazyText failed: %v", err)
+	}
+	if len(none) != len(prefixed) || len(separate) != len(prefixed) || len(lazy) != len(prefixed) {
+		t.Fatalf("Expected %d chunks in every mode", len(prefixed))
+	}
+
+	for i := range prefixed {
+		if prefixed[i].ContextHeader != "" {
+			t.Errorf("Chunk %d: expected no ContextHeader in prefix mode", i)
+		}
+		if none[i].ContextualizedText != "" || none[i].ContextHeader != "" {
+			t.Errorf("Chunk %d: expected no header in none mode", i)
+		}
+		if none[i].Text != prefixed[i].Text || !reflect.DeepEqual(none[i].Context, prefixed[i].Context) {
+			t.Errorf("Chunk %d: expected the same text and context in none mode", i)
+		}
+		if separate[i].ContextualizedText != "" {
+			t.Errorf("Chunk %d: expected empty ContextualizedText in separate mode", i)
+		}
+		if got := separate[i].ContextHeader + separate[i].Text; got != prefixed[i].ContextualizedText {
+			t.Errorf("Chunk %d: ContextHeader + Text = %q, expected %q", i, got, prefixed[i].ContextualizedText)
+		}
+		if lazy[i].Contextualized() != "" {
+			t.Errorf("Chunk %d: expected empty Contextualized() in separate mode", i)
+		}
+		if !reflect.DeepEqual(lazy[i].Materialize(), separate[i]) {
+			t.Errorf("Chunk %d: materialized chunk differs from eager chunk", i)
+		}
+	}
+}
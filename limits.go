@@ -0,0 +1,54 @@
+package codechunk
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// limitFileSize applies opts.MaxFileSize to code. A file over it fails unless
+// opts.TruncateOnLimit is set, in which case it is cut after the last line break
+// within the limit, or at the limit if the first line is longer. It reports
+// whether code was cut.
+func limitFileSize(filepath string, code []byte, opts ChunkOptions) ([]byte, bool, error) {
+	if opts.MaxFileSize <= 0 || len(code) <= opts.MaxFileSize {
+		return code, false, nil
+	}
+	if !opts.TruncateOnLimit {
+		return nil, false, &ChunkError{
+			Stage:    StageDetect,
+			Filepath: filepath,
+			Err:      fmt.Errorf("%w: %d bytes, above the MaxFileSize of %d", ErrLimitExceeded, len(code), opts.MaxFileSize),
+		}
+	}
+
+	cut := bytes.LastIndexByte(code[:opts.MaxFileSize], '\n') + 1
+	if cut == 0 {
+		cut = opts.MaxFileSize
+	}
+	return code[:cut], true, nil
+}
+
+// limitChunks applies opts.MaxChunksPerFile to the chunks of a file, failing it or
+// with opts.TruncateOnLimit keeping the first chunks. The last chunk is marked
+// Truncated if chunks are dropped or truncated reports that the file was cut.
+func limitChunks(filepath string, chunks []CodeChunk, truncated bool, opts ChunkOptions) ([]CodeChunk, error) {
+	if opts.MaxChunksPerFile > 0 && len(chunks) > opts.MaxChunksPerFile {
+		if !opts.TruncateOnLimit {
+			return nil, &ChunkError{
+				Stage:    StageBuild,
+				Filepath: filepath,
+				Language: chunks[0].Context.Language,
+				Err:      fmt.Errorf("%w: %d chunks, above the MaxChunksPerFile of %d", ErrLimitExceeded, len(chunks), opts.MaxChunksPerFile),
+			}
+		}
+		chunks = chunks[:opts.MaxChunksPerFile]
+		for i := range chunks {
+			chunks[i].TotalChunks = len(chunks)
+		}
+		truncated = true
+	}
+	if truncated && len(chunks) > 0 {
+		chunks[len(chunks)-1].Truncated = true
+	}
+	return chunks, nil
+}
@@ -0,0 +1,85 @@
+package codechunk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// limitsSource returns a Go file with n functions
+func limitsSource(n int) string {
+	var b strings.Builder
+	b.WriteString("package p\n")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "\n// F%d does work %d.\nfunc F%d() int {\n\treturn %d\n}\n", i, i, i, i)
+	}
+	return b.String()
+}
+
+func TestChunkMaxFileSize(t *testing.T) {
+	code := limitsSource(40)
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 100
+	opts.MaxFileSize = len(code) / 2
+
+	_, err := Chunk("p.go", code, &opts)
+	var chunkErr *ChunkError
+	if !errors.Is(err, ErrLimitExceeded) || !errors.As(err, &chunkErr) || ErrorCode(err) != ErrorCodeLimitExceeded {
+		t.Fatalf("Expected a ChunkError matching ErrLimitExceeded, got %v", err)
+	}
+
+	opts.TruncateOnLimit = true
+	chunks, err := Chunk("p.go", code, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := chunks[len(chunks)-1]
+	if !last.Truncated || last.ByteRange.End > opts.MaxFileSize {
+		t.Errorf("Expected the last chunk to be truncated within the limit, got %+v", last.ByteRange)
+	}
+	for _, chunk := range chunks[:len(chunks)-1] {
+		if chunk.Truncated {
+			t.Errorf("Expected only the last chunk to be truncated, got chunk %d", chunk.Index)
+		}
+	}
+}
+
+func TestChunkMaxChunksPerFile(t *testing.T) {
+	code := limitsSource(40)
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 100
+	opts.MaxChunksPerFile = 5
+
+	if _, err := Chunk("p.go", code, &opts); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("Expected an error matching ErrLimitExceeded, got %v", err)
+	}
+
+	opts.TruncateOnLimit = true
+	chunks, err := Chunk("p.go", code, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunks) != 5 || !chunks[4].Truncated || chunks[4].NextChunkID != "" || chunks[0].TotalChunks != 5 {
+		t.Errorf("Expected 5 chunks ending with a truncated one, got %d", len(chunks))
+	}
+
+	// Streams apply the limit the same way
+	stream, err := ChunkStream("p.go", code, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var streamed []CodeChunk
+	for chunk := range stream {
+		streamed = append(streamed, chunk)
+	}
+	if len(streamed) != 5 || !streamed[4].Truncated {
+		t.Errorf("Expected 5 streamed chunks ending with a truncated one, got %d", len(streamed))
+	}
+
+	// Files within the limits are not marked
+	chunks, err = Chunk("p.go", limitsSource(1), &opts)
+	if err != nil || chunks[len(chunks)-1].Truncated {
+		t.Errorf("Expected an untruncated small file, got %v", err)
+	}
+}
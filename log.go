@@ -0,0 +1,52 @@
+package codechunk
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LevelTrace is the slog level for per-window chunking decisions, which are too
+// verbose for slog.LevelDebug.
+const LevelTrace = slog.LevelDebug - 4
+
+// discardHandler is a slog.Handler that drops all records
+type discardHandler struct{}
+
+func (discardHandler) Enabled(context.Context, slog.Level) bool  { return false }
+func (discardHandler) Handle(context.Context, slog.Record) error { return nil }
+func (h discardHandler) WithAttrs([]slog.Attr) slog.Handler      { return h }
+func (h discardHandler) WithGroup(string) slog.Handler           { return h }
+
+// discardLogger is used when no Logger is configured
+var discardLogger = slog.New(discardHandler{})
+
+// loggerFor returns opts.Logger, or a logger that discards everything if unset
+func loggerFor(opts ChunkOptions) *slog.Logger {
+	if opts.Logger != nil {
+		return opts.Logger
+	}
+	return discardLogger
+}
+
+// resolveLanguage returns the language to chunk filepath with, or "" if unsupported
+func resolveLanguage(filepath string, code []byte, opts ChunkOptions, logger *slog.Logger) Language {
+	if opts.Language != "" {
+		logger.Debug("using language override", "filepath", filepath, "language", opts.Language)
+		return opts.Language
+	}
+
+	lang := DetectFileLanguage(filepath, code)
+	if lang == "" {
+		logger.Debug("unsupported language", "filepath", filepath)
+		return ""
+	}
+	logger.Debug("detected language", "filepath", filepath, "language", lang)
+	return lang
+}
+
+// logParseError reports a recoverable parse error
+func logParseError(logger *slog.Logger, filepath string, parseErr *ParseError) {
+	if parseErr != nil {
+		logger.Warn("parse error", "filepath", filepath, "error", parseErr.Message, "recoverable", parseErr.Recoverable)
+	}
+}
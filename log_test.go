@@ -0,0 +1,63 @@
+package codechunk
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestChunkLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: LevelTrace}))
+
+	code := `package main
+
+func small() {}
+
+func large() {
+	a := 1
+	b := 2
+	c := 3
+	_ = a + b + c
+}
+`
+	if _, err := Chunk("main.go", code, &ChunkOptions{MaxChunkSize: 20, Logger: logger}); err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"detected language",
+		"splitting oversized node",
+		"assigned windows",
+		"level=DEBUG-4",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in log output:\n%s", want, out)
+		}
+	}
+}
+
+func TestChunkLoggerParseError(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	if _, err := Chunk("broken.go", "package main\n\nfunc broken( {\n", &ChunkOptions{Logger: logger}); err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "parse error") {
+		t.Errorf("Expected parse error warning, got:\n%s", out)
+	}
+	if strings.Contains(out, "level=DEBUG") {
+		t.Errorf("Expected debug logs to be filtered at info level, got:\n%s", out)
+	}
+}
+
+func TestChunkWithoutLogger(t *testing.T) {
+	if _, err := ChunkWithOptions("main.go", "package main\n", WithLogger(nil)); err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+}
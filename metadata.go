@@ -0,0 +1,630 @@
+package codechunk
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// entityVisibility derives the visibility of an entity from its declaration:
+//
+//   - Go: exported if the name starts with an upper-case letter, otherwise private
+//   - Rust: public if declared pub, internal if pub(crate), pub(super) or
+//     pub(in path), otherwise private; trait items, trait impl items and
+//     #[macro_export] macros are public
+//   - TypeScript and JavaScript: top-level declarations, and the values of the
+//     variables they declare, are exported if part of an export statement,
+//     otherwise private; class members take their accessibility modifier or #
+//     prefix and are public by default; export statements themselves have none
+//   - Java: the access modifier, public for interface members and internal
+//     (package-private) without one
+//   - Python: private for __name, internal for _name, otherwise public
+//
+// Returns "" for other languages and entities without a visibility.
+func entityVisibility(node *sitter.Node, lang Language, name string, code []byte) Visibility {
+	switch lang {
+	case LanguageGo:
+		if isGoExported(name) {
+			return VisibilityExported
+		}
+		return VisibilityPrivate
+	case LanguageRust:
+		return rustVisibility(node, code)
+	case LanguageTypeScript, LanguageJavaScript:
+		return jsVisibility(node, name, code)
+	case LanguageJava:
+		return javaVisibility(node, code)
+	case LanguagePython:
+		return pythonVisibility(name)
+	default:
+		return ""
+	}
+}
+
+func rustVisibility(node *sitter.Node, code []byte) Visibility {
+	if node.Type() == "impl_item" {
+		return ""
+	}
+	if isRustMacroExported(node, code) {
+		return VisibilityPublic
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		child := node.Child(i)
+		if child.Type() != "visibility_modifier" {
+			continue
+		}
+		if child.Content(code) == "pub" {
+			return VisibilityPublic
+		}
+		return VisibilityInternal
+	}
+
+	// Items of traits and trait implementations are as visible as the trait
+	if list := node.Parent(); list != nil && list.Type() == "declaration_list" {
+		if owner := list.Parent(); owner != nil {
+			if owner.Type() == "trait_item" || owner.Type() == "impl_item" && owner.ChildByFieldName("trait") != nil {
+				return VisibilityPublic
+			}
+		}
+	}
+	return VisibilityPrivate
+}
+
+func jsVisibility(node *sitter.Node, name string, code []byte) Visibility {
+	if node.Type() == "method_definition" {
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if child := node.Child(i); child.Type() == "accessibility_modifier" {
+				return Visibility(child.Content(code))
+			}
+		}
+		if strings.HasPrefix(name, "#") {
+			return VisibilityPrivate
+		}
+		return VisibilityPublic
+	}
+	if node.Type() == "export_statement" {
+		return ""
+	}
+	if isJSExported(node, code) {
+		return VisibilityExported
+	}
+	return VisibilityPrivate
+}
+
+// isJSExported reports whether an export statement exports a JavaScript or
+// TypeScript entity, directly or as the value of a variable it declares, such as
+// export const Button = React.memo(() => ...)
+func isJSExported(node *sitter.Node, code []byte) bool {
+	for parent := node.Parent(); parent != nil; node, parent = parent, parent.Parent() {
+		switch parent.Type() {
+		case "export_statement":
+			return true
+		case "variable_declarator":
+			if value := parent.ChildByFieldName("value"); value == nil || !value.Equal(node) {
+				return false
+			}
+		case "lexical_declaration", "variable_declaration":
+		case "arguments":
+			if !isComponentWrapper(parent.Parent(), code) {
+				return false
+			}
+			parent = parent.Parent()
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+func javaVisibility(node *sitter.Node, code []byte) Visibility {
+	if node.Type() == "package_declaration" {
+		return ""
+	}
+	for _, modifier := range []Visibility{VisibilityPublic, VisibilityProtected, VisibilityPrivate} {
+		if hasJavaModifier(node, string(modifier), code) {
+			return modifier
+		}
+	}
+	if parent := node.Parent(); parent != nil && parent.Type() == "interface_body" {
+		return VisibilityPublic
+	}
+	return VisibilityInternal
+}
+
+func pythonVisibility(name string) Visibility {
+	switch {
+	case strings.HasPrefix(name, "__") && strings.HasSuffix(name, "__"):
+		return VisibilityPublic
+	case strings.HasPrefix(name, "__"):
+		return VisibilityPrivate
+	case strings.HasPrefix(name, "_"):
+		return VisibilityInternal
+	default:
+		return VisibilityPublic
+	}
+}
+
+// entityAnnotations returns the decorators and annotations attached to an entity,
+// in source order and with whitespace collapsed: Python decorators, Java
+// annotations, Rust attributes, and TypeScript and JavaScript decorators, which a
+// class member precedes and an exported class may place before export.
+func entityAnnotations(node *sitter.Node, lang Language, code []byte) []string {
+	var nodes []*sitter.Node
+	switch lang {
+	case LanguagePython:
+		if parent := node.Parent(); parent != nil && parent.Type() == "decorated_definition" {
+			nodes = childrenOfType(parent, "decorator")
+		}
+	case LanguageJava:
+		for _, modifiers := range childrenOfType(node, "modifiers") {
+			nodes = append(nodes, childrenOfType(modifiers, "marker_annotation", "annotation")...)
+		}
+	case LanguageRust:
+		for sibling := node.PrevSibling(); sibling != nil && sibling.Type() == "attribute_item"; sibling = sibling.PrevSibling() {
+			nodes = append([]*sitter.Node{sibling}, nodes...)
+		}
+	case LanguageTypeScript, LanguageJavaScript:
+		for sibling := node.PrevSibling(); sibling != nil && sibling.Type() == "decorator"; sibling = sibling.PrevSibling() {
+			nodes = append([]*sitter.Node{sibling}, nodes...)
+		}
+		if parent := node.Parent(); parent != nil && parent.Type() == "export_statement" {
+			nodes = append(nodes, childrenOfType(parent, "decorator")...)
+		}
+		nodes = append(nodes, childrenOfType(node, "decorator")...)
+	}
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	annotations := make([]string, len(nodes))
+	for i, n := range nodes {
+		annotations[i] = strings.Join(strings.Fields(n.Content(code)), " ")
+	}
+	return annotations
+}
+
+// childrenOfType returns the children of node with one of the given types
+func childrenOfType(node *sitter.Node, types ...string) []*sitter.Node {
+	children := make([]*sitter.Node, 0)
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		for _, t := range types {
+			if child.Type() == t {
+				children = append(children, child)
+				break
+			}
+		}
+	}
+	return children
+}
+
+// jsModifierKeywords are the keyword children of TypeScript and JavaScript
+// declarations reported as modifiers
+var jsModifierKeywords = map[string]bool{
+	"async":    true,
+	"static":   true,
+	"abstract": true,
+	"get":      true,
+	"set":      true,
+	"readonly": true,
+	"declare":  true,
+	"override": true,
+}
+
+// pythonModifierDecorators maps the decorators of Python functions that act as
+// modifiers to the modifier they report
+var pythonModifierDecorators = map[string]string{
+	"staticmethod":       "static",
+	"classmethod":        "classmethod",
+	"abstractmethod":     "abstract",
+	"abc.abstractmethod": "abstract",
+	"property":           "property",
+}
+
+// pythonPropertyDecorators are the decorators making a Python method the getter of
+// a property; @name.setter and @name.deleter add its other accessors
+var pythonPropertyDecorators = map[string]bool{
+	"property":                  true,
+	"cached_property":           true,
+	"functools.cached_property": true,
+}
+
+// pythonPropertyAccessor reports whether the decorators of a Python method make it
+// a property accessor, and whether it is the getter declaring the property
+func pythonPropertyAccessor(annotations []string) (accessor, getter bool) {
+	for _, annotation := range annotations {
+		decorator := strings.TrimPrefix(annotation, "@")
+		if pythonPropertyDecorators[decorator] {
+			return true, true
+		}
+		if strings.HasSuffix(decorator, ".setter") || strings.HasSuffix(decorator, ".deleter") {
+			accessor = true
+		}
+	}
+	return accessor, false
+}
+
+// pythonFunctionType returns the entity type of a Python function definition: a
+// method when defined in a class body, or a property when decorated as an accessor
+func pythonFunctionType(node *sitter.Node, code []byte) EntityType {
+	container := node.Parent()
+	if container != nil && container.Type() == "decorated_definition" {
+		container = container.Parent()
+	}
+	if container == nil || container.Type() != "block" || container.Parent() == nil || container.Parent().Type() != "class_definition" {
+		return EntityTypeFunction
+	}
+	if accessor, _ := pythonPropertyAccessor(entityAnnotations(node, LanguagePython, code)); accessor {
+		return EntityTypeProperty
+	}
+	return EntityTypeMethod
+}
+
+// entityModifiers returns the modifiers of an entity other than its visibility,
+// in source order: keywords such as async, static, abstract, const, unsafe,
+// final and synchronized, generator for generator functions, and for Python the
+// static, classmethod, abstract and property decorators
+func entityModifiers(node *sitter.Node, lang Language, annotations []string, code []byte) []string {
+	modifiers := make([]string, 0)
+	add := func(modifier string) {
+		for _, m := range modifiers {
+			if m == modifier {
+				return
+			}
+		}
+		modifiers = append(modifiers, modifier)
+	}
+
+	switch lang {
+	case LanguageTypeScript, LanguageJavaScript:
+		if node.Type() == "abstract_class_declaration" {
+			add("abstract")
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			switch {
+			case child.Type() == "override_modifier":
+				add("override")
+			case !child.IsNamed() && jsModifierKeywords[child.Type()]:
+				add(child.Type())
+			case child.Type() == "*":
+				add("generator")
+			}
+		}
+		if node.Type() == "generator_function_declaration" {
+			add("generator")
+		}
+
+	case LanguageRust:
+		for i := 0; i < int(node.ChildCount()); i++ {
+			child := node.Child(i)
+			switch child.Type() {
+			case "function_modifiers":
+				for j := 0; j < int(child.ChildCount()); j++ {
+					add(strings.TrimSuffix(child.Child(j).Type(), "_modifier"))
+				}
+			case "unsafe":
+				add("unsafe")
+			}
+		}
+
+	case LanguageJava:
+		for _, list := range childrenOfType(node, "modifiers") {
+			for i := 0; i < int(list.ChildCount()); i++ {
+				child := list.Child(i)
+				if child.IsNamed() {
+					continue
+				}
+				switch keyword := child.Type(); keyword {
+				case "public", "private", "protected":
+				default:
+					add(keyword)
+				}
+			}
+		}
+
+	case LanguagePython:
+		for _, annotation := range annotations {
+			if modifier, ok := pythonModifierDecorators[strings.TrimPrefix(annotation, "@")]; ok {
+				add(modifier)
+			}
+		}
+		if accessor, _ := pythonPropertyAccessor(annotations); accessor {
+			add("property")
+		}
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if node.Child(i).Type() == "async" {
+				add("async")
+			}
+		}
+		if body := node.ChildByFieldName("body"); node.Type() == "function_definition" && body != nil && containsYield(body) {
+			add("generator")
+		}
+	}
+
+	if len(modifiers) == 0 {
+		return nil
+	}
+	return modifiers
+}
+
+// containsYield reports whether a Python function body yields, not counting
+// nested functions, lambdas and classes
+func containsYield(node *sitter.Node) bool {
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		child := node.NamedChild(i)
+		switch child.Type() {
+		case "yield":
+			return true
+		case "function_definition", "lambda", "class_definition":
+			continue
+		}
+		if containsYield(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// entityParameters returns the parameters and return type of a function or method
+// as written in its declaration. A Go parameter declaration naming several
+// parameters yields one Param per name, and variadic and rest parameters keep
+// their ... or * markers. The compact constructor of a Java record takes the
+// components of the record. Returns nil and "" for other entities.
+func entityParameters(node *sitter.Node, entityType EntityType, lang Language, code []byte) ([]Param, string) {
+	if entityType != EntityTypeFunction && entityType != EntityTypeMethod && entityType != EntityTypeProperty {
+		return nil, ""
+	}
+	if node.Type() == "compact_constructor_declaration" {
+		if body := node.Parent(); body != nil && body.Parent() != nil {
+			node = body.Parent()
+		}
+	}
+	list := node.ChildByFieldName("parameters")
+	if list == nil {
+		return nil, ""
+	}
+
+	var params []Param
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		params = append(params, parameterDeclaration(list.NamedChild(i), code)...)
+	}
+	return params, returnType(node, lang, code)
+}
+
+// parameterDeclaration returns the parameters declared by a child of a parameter list
+func parameterDeclaration(node *sitter.Node, code []byte) []Param {
+	typeText := ""
+	if typeNode := node.ChildByFieldName("type"); typeNode != nil {
+		typeText = typeAnnotation(typeNode, code)
+	}
+
+	switch node.Type() {
+	case "comment", "keyword_separator", "positional_separator", "receiver_parameter":
+		return nil
+
+	case "parameter_declaration", "variadic_parameter_declaration":
+		// Go: a, b int declares two parameters, and results may have no names
+		if node.Type() == "variadic_parameter_declaration" {
+			typeText = "..." + typeText
+		}
+		params := make([]Param, 0, 1)
+		for i := 0; i < int(node.ChildCount()); i++ {
+			if node.FieldNameForChild(i) == "name" {
+				params = append(params, Param{Name: node.Child(i).Content(code), Type: typeText})
+			}
+		}
+		if len(params) == 0 {
+			params = append(params, Param{Type: typeText})
+		}
+		return params
+
+	case "spread_parameter":
+		// Java: String... names
+		param := Param{}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			child := node.NamedChild(i)
+			if child.Type() == "variable_declarator" {
+				if name := child.ChildByFieldName("name"); name != nil {
+					param.Name = name.Content(code)
+				}
+			} else if param.Type == "" {
+				param.Type = child.Content(code) + "..."
+			}
+		}
+		return []Param{param}
+
+	case "optional_parameter":
+		if pattern := node.ChildByFieldName("pattern"); pattern != nil {
+			return []Param{{Name: pattern.Content(code) + "?", Type: typeText}}
+		}
+
+	case "typed_parameter":
+		// Python: the name is the first child, which may be a splat pattern
+		if node.NamedChildCount() > 0 {
+			return []Param{{Name: node.NamedChild(0).Content(code), Type: typeText}}
+		}
+
+	case "assignment_pattern":
+		if left := node.ChildByFieldName("left"); left != nil {
+			return []Param{{Name: left.Content(code)}}
+		}
+	}
+
+	for _, field := range []string{"name", "pattern"} {
+		if nameNode := node.ChildByFieldName(field); nameNode != nil {
+			return []Param{{Name: nameNode.Content(code), Type: typeText}}
+		}
+	}
+	return []Param{{Name: strings.Join(strings.Fields(node.Content(code)), " "), Type: typeText}}
+}
+
+// returnType returns the declared return type of a function or method, or "" if
+// it has none
+func returnType(node *sitter.Node, lang Language, code []byte) string {
+	field := "return_type"
+	switch lang {
+	case LanguageGo:
+		field = "result"
+	case LanguageJava:
+		field = "type"
+	}
+	if typeNode := node.ChildByFieldName(field); typeNode != nil {
+		return typeAnnotation(typeNode, code)
+	}
+	return ""
+}
+
+// typeAnnotation returns the text of a type node on one line (see tightenBrackets),
+// without the leading colon of a TypeScript type annotation
+func typeAnnotation(node *sitter.Node, code []byte) string {
+	text := node.Content(code)
+	if node.Type() == "type_annotation" {
+		text = strings.TrimPrefix(strings.TrimSpace(text), ":")
+	}
+	return tightenBrackets(strings.Join(strings.Fields(text), " "))
+}
+
+// entityTypeParameters returns the generic type parameters of an entity: type
+// parameters with their constraints, Rust lifetimes and const parameters.
+// Returns nil for entities without type parameters.
+func entityTypeParameters(node *sitter.Node, code []byte) []TypeParam {
+	// Go type declarations declare their parameters in the type spec
+	if node.Type() == "type_declaration" {
+		if specs := childrenOfType(node, "type_spec"); len(specs) > 0 {
+			node = specs[0]
+		}
+	}
+	if node.Type() == "method_declaration" {
+		return goReceiverTypeParameters(node, code)
+	}
+	list := node.ChildByFieldName("type_parameters")
+	if list == nil {
+		return nil
+	}
+
+	var params []TypeParam
+	for i := 0; i < int(list.NamedChildCount()); i++ {
+		child := list.NamedChild(i)
+		switch child.Type() {
+		case "comment":
+			continue
+
+		case "type_parameter_declaration":
+			// Go: K, V comparable declares two parameters
+			constraint := ""
+			if typeNode := child.ChildByFieldName("type"); typeNode != nil {
+				constraint = typeAnnotation(typeNode, code)
+			}
+			for j := 0; j < int(child.ChildCount()); j++ {
+				if child.FieldNameForChild(j) == "name" {
+					params = append(params, TypeParam{Name: child.Child(j).Content(code), Constraint: constraint})
+				}
+			}
+
+		case "constrained_type_parameter":
+			// Rust: T: Clone + 'a
+			param := TypeParam{}
+			if left := child.ChildByFieldName("left"); left != nil {
+				param.Name = left.Content(code)
+			}
+			if bounds := child.ChildByFieldName("bounds"); bounds != nil {
+				param.Constraint = typeConstraint(bounds, code)
+			}
+			params = append(params, param)
+
+		default:
+			params = append(params, typeParameter(child, code))
+		}
+	}
+	return params
+}
+
+// goReceiverTypeParameters returns the type parameters a Go method on a generic
+// type names in its receiver, K and V for (c *Cache[K, V]), without constraints,
+// which the type declares (see constrainReceiverTypeParameters)
+func goReceiverTypeParameters(node *sitter.Node, code []byte) []TypeParam {
+	receiver := node.ChildByFieldName("receiver")
+	if receiver == nil {
+		return nil
+	}
+	params := childrenOfType(receiver, "parameter_declaration")
+	if len(params) == 0 {
+		return nil
+	}
+
+	typeNode := params[0].ChildByFieldName("type")
+	for typeNode != nil && (typeNode.Type() == "pointer_type" || typeNode.Type() == "parenthesized_type") {
+		typeNode = typeNode.NamedChild(0)
+	}
+	if typeNode == nil || typeNode.Type() != "generic_type" {
+		return nil
+	}
+	args := typeNode.ChildByFieldName("type_arguments")
+	if args == nil {
+		return nil
+	}
+	var typeParams []TypeParam
+	for i := 0; i < int(args.NamedChildCount()); i++ {
+		typeParams = append(typeParams, TypeParam{Name: strings.TrimSpace(args.NamedChild(i).Content(code))})
+	}
+	return typeParams
+}
+
+// constrainReceiverTypeParameters gives the type parameters of Go methods on
+// generic types the constraints of the type declared in the same file, matched
+// by position, as a receiver may rename them
+func constrainReceiverTypeParameters(entities []*ExtractedEntity) {
+	types := make(map[string]*ExtractedEntity)
+	for _, entity := range entities {
+		if entity.Type == EntityTypeType && len(entity.TypeParameters) > 0 {
+			types[entity.Name] = entity
+		}
+	}
+	for _, entity := range entities {
+		if entity.Type != EntityTypeMethod || entity.Parent == nil || len(entity.TypeParameters) == 0 {
+			continue
+		}
+		declared, ok := types[*entity.Parent]
+		if !ok || len(declared.TypeParameters) != len(entity.TypeParameters) {
+			continue
+		}
+		for i := range entity.TypeParameters {
+			entity.TypeParameters[i].Constraint = declared.TypeParameters[i].Constraint
+		}
+	}
+}
+
+// typeParameter returns a type parameter declared by a node named by its name
+// field or first named child, constrained by its constraint, type or type bound
+func typeParameter(node *sitter.Node, code []byte) TypeParam {
+	if node.NamedChildCount() == 0 || node.Type() == "lifetime" || node.Type() == "type_identifier" {
+		return TypeParam{Name: node.Content(code)}
+	}
+
+	param := TypeParam{Name: node.NamedChild(0).Content(code)}
+	if name := node.ChildByFieldName("name"); name != nil {
+		param.Name = name.Content(code)
+	}
+	for _, field := range []string{"constraint", "type", "bounds"} {
+		if constraint := node.ChildByFieldName(field); constraint != nil {
+			param.Constraint = typeConstraint(constraint, code)
+			return param
+		}
+	}
+	if bounds := childrenOfType(node, "type_bound"); len(bounds) > 0 {
+		param.Constraint = typeConstraint(bounds[0], code)
+	}
+	return param
+}
+
+// typeConstraint returns the text of a constraint without its extends keyword or
+// leading colon
+func typeConstraint(node *sitter.Node, code []byte) string {
+	text := strings.TrimSpace(node.Content(code))
+	text = strings.TrimPrefix(text, ":")
+	if rest, ok := strings.CutPrefix(strings.TrimSpace(text), "extends"); ok {
+		text = rest
+	}
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,308 @@
+package codechunk
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// entityByName returns the first non-import entity extracted from code with the given
+// name, or bound to it if anonymous
+func entityByName(t *testing.T, code string, lang Language, name string) *ExtractedEntity {
+	t.Helper()
+	parseResult, err := parseString(code, lang)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for _, entity := range extractEntities(parseResult.Tree.RootNode(), lang, []byte(code)) {
+		named := entity.Name == name || entity.Name == anonymousName && entity.boundName == name
+		if named && entity.Type != EntityTypeImport && entity.Type != EntityTypeExport {
+			return entity
+		}
+	}
+	t.Fatalf("No entity named %s in %q", name, code)
+	return nil
+}
+
+func TestEntityVisibility(t *testing.T) {
+	tests := []struct {
+		lang Language
+		code string
+		name string
+		want Visibility
+	}{
+		{LanguageGo, "package p\nfunc Exported() {}", "Exported", VisibilityExported},
+		{LanguageGo, "package p\nfunc helper() {}", "helper", VisibilityPrivate},
+		{LanguageRust, "pub fn open() {}", "open", VisibilityPublic},
+		{LanguageRust, "pub(crate) fn open() {}", "open", VisibilityInternal},
+		{LanguageRust, "fn open() {}", "open", VisibilityPrivate},
+		{LanguageRust, "impl Drop for File { fn drop(&mut self) {} }", "drop", VisibilityPublic},
+		{LanguageTypeScript, "export function load() {}", "load", VisibilityExported},
+		{LanguageTypeScript, "function load() {}", "load", VisibilityPrivate},
+		{LanguageTypeScript, "export const Button = () => <div />;", "Button", VisibilityExported},
+		{LanguageTypeScript, "export const Card = React.memo(() => <div />);", "Card", VisibilityExported},
+		{LanguageJavaScript, "export const fn2 = function () {}", "fn2", VisibilityExported},
+		{LanguageJavaScript, "const fn2 = function () {}", "fn2", VisibilityPrivate},
+		{LanguageTypeScript, "class A { protected run() {} }", "run", VisibilityProtected},
+		{LanguageTypeScript, "class A { run() {} }", "run", VisibilityPublic},
+		{LanguageJavaScript, "class A { #run() {} }", "#run", VisibilityPrivate},
+		{LanguageJava, "class A { private void run() {} }", "run", VisibilityPrivate},
+		{LanguageJava, "class A { void run() {} }", "run", VisibilityInternal},
+		{LanguageJava, "interface A { void run(); }", "run", VisibilityPublic},
+		{LanguagePython, "def _helper(): pass", "_helper", VisibilityInternal},
+		{LanguagePython, "class A:\n    def __secret(self): pass", "__secret", VisibilityPrivate},
+		{LanguagePython, "class A:\n    def __init__(self): pass", "__init__", VisibilityPublic},
+		{LanguageSQL, "CREATE TABLE users (id INT);", "users", ""},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, tt.code, tt.lang, tt.name)
+		if entity.Visibility != tt.want {
+			t.Errorf("%s %q: expected visibility %q, got %q", tt.lang, tt.code, tt.want, entity.Visibility)
+		}
+	}
+}
+
+func TestExportStatementVisibility(t *testing.T) {
+	code := "export const Button = () => <div />;\nexport function load() {}\nexport { load as run };"
+	parseResult, err := parseString(code, LanguageTypeScript)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for _, entity := range extractEntities(parseResult.Tree.RootNode(), LanguageTypeScript, []byte(code)) {
+		if entity.Type == EntityTypeExport && entity.Visibility != "" {
+			t.Errorf("Expected no visibility for export statement %q, got %q", entity.Node.Content([]byte(code)), entity.Visibility)
+		}
+	}
+}
+
+func TestChunkEntityVisibility(t *testing.T) {
+	code := "package p\n\nfunc Open() {}\n\nfunc open() {}\n"
+	chunks, err := Chunk("file.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	public := 0
+	for _, entity := range chunks[0].Context.Entities {
+		if entity.Visibility.IsPublicAPI() {
+			public++
+			if entity.Name != "Open" {
+				t.Errorf("Expected only Open in the public API, got %s", entity.Name)
+			}
+		}
+	}
+	if public != 1 {
+		t.Errorf("Expected 1 public entity, got %d", public)
+	}
+}
+
+func TestEntityAnnotations(t *testing.T) {
+	tests := []struct {
+		lang Language
+		code string
+		name string
+		want []string
+	}{
+		{LanguagePython, "@app.route('/users')\n@login_required\ndef users():\n    pass\n", "users", []string{"@app.route('/users')", "@login_required"}},
+		{LanguagePython, "def plain():\n    pass\n", "plain", nil},
+		{LanguageJava, "class A {\n  @Override @Deprecated(since = \"2\")\n  public void run() {}\n}\n", "run", []string{"@Override", `@Deprecated(since = "2")`}},
+		{LanguageTypeScript, "@Injectable()\nclass Service {}\n", "Service", []string{"@Injectable()"}},
+		{LanguageTypeScript, "@Component({ selector: 'app' })\nexport class App {}\n", "App", []string{"@Component({ selector: 'app' })"}},
+		{LanguageTypeScript, "class App {\n  @HostListener('click')\n  onClick() {}\n  other() {}\n}\n", "onClick", []string{"@HostListener('click')"}},
+		{LanguageTypeScript, "class App {\n  @HostListener('click')\n  onClick() {}\n  other() {}\n}\n", "other", nil},
+		{LanguageRust, "#[derive(Debug,\n    Clone)]\n#[serde(rename_all = \"camelCase\")]\npub struct Config {}\n", "Config", []string{"#[derive(Debug, Clone)]", `#[serde(rename_all = "camelCase")]`}},
+		{LanguageRust, "#[test]\nfn first() {}\nfn second() {}\n", "second", nil},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, tt.code, tt.lang, tt.name)
+		if len(entity.Annotations) != len(tt.want) {
+			t.Errorf("%s %s: expected annotations %q, got %q", tt.lang, tt.name, tt.want, entity.Annotations)
+			continue
+		}
+		for i := range tt.want {
+			if entity.Annotations[i] != tt.want[i] {
+				t.Errorf("%s %s: expected annotations %q, got %q", tt.lang, tt.name, tt.want, entity.Annotations)
+				break
+			}
+		}
+	}
+}
+
+func TestAnnotationsInContextHeader(t *testing.T) {
+	code := "@app.route('/users')\ndef users():\n    return []\n"
+	chunks, err := Chunk("app.py", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	want := "# Defines: @app.route('/users') def users()"
+	if !strings.Contains(chunks[0].ContextualizedText, want) {
+		t.Errorf("Expected %q in the header, got:\n%s", want, chunks[0].ContextualizedText)
+	}
+}
+
+func TestEntityModifiers(t *testing.T) {
+	tests := []struct {
+		lang Language
+		code string
+		name string
+		want string
+	}{
+		{LanguageTypeScript, "async function load() {}", "load", "async"},
+		{LanguageTypeScript, "class A {\n  static async *items() {}\n}", "items", "static async generator"},
+		{LanguageTypeScript, "abstract class Shape {}", "Shape", "abstract"},
+		{LanguageTypeScript, "class A {\n  get size() { return 1 }\n}", "size", "get"},
+		{LanguageJavaScript, "function* ids() {}", "ids", "generator"},
+		{LanguageRust, "pub async unsafe fn read() {}", "read", "async unsafe"},
+		{LanguageRust, "const fn zero() -> u32 { 0 }", "zero", "const"},
+		{LanguageRust, "unsafe trait Zeroable {}", "Zeroable", "unsafe"},
+		{LanguageJava, "class A {\n  public static final synchronized void run() {}\n}", "run", "static final synchronized"},
+		{LanguageJava, "abstract class Shape {}", "Shape", "abstract"},
+		{LanguagePython, "class A:\n    @staticmethod\n    async def fetch():\n        pass\n", "fetch", "static async"},
+		{LanguagePython, "def numbers():\n    yield 1\n", "numbers", "generator"},
+		{LanguagePython, "def outer():\n    def inner():\n        yield 1\n    return inner\n", "outer", ""},
+		{LanguageGo, "package p\nfunc run() {}", "run", ""},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, tt.code, tt.lang, tt.name)
+		if got := strings.Join(entity.Modifiers, " "); got != tt.want {
+			t.Errorf("%s %q: expected modifiers %q, got %q", tt.lang, tt.code, tt.want, got)
+		}
+	}
+}
+
+func TestPythonEntityTypes(t *testing.T) {
+	code := `async def fetch(url):
+    pass
+
+class Client:
+    def __init__(self):
+        self._url = ""
+
+    @property
+    def url(self) -> str:
+        return self._url
+
+    @url.setter
+    def url(self, value):
+        self._url = value
+
+    @functools.cached_property
+    def size(self):
+        return 1
+
+    @classmethod
+    def create(cls):
+        return cls()
+`
+	parseResult, err := parseString(code, LanguagePython)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	var got []string
+	for _, entity := range extractEntities(parseResult.Tree.RootNode(), LanguagePython, []byte(code)) {
+		got = append(got, fmt.Sprintf("%s %s %v", entity.Type, entity.Name, entity.Modifiers))
+	}
+	want := []string{
+		"function fetch [async]",
+		"class Client []",
+		"method __init__ []",
+		"property url [property]",
+		"property url [property]",
+		"property size [property]",
+		"method create [classmethod]",
+	}
+	if strings.Join(got, "; ") != strings.Join(want, "; ") {
+		t.Errorf("Expected %q, got %q", want, got)
+	}
+}
+
+func TestEntityParameters(t *testing.T) {
+	tests := []struct {
+		lang    Language
+		code    string
+		name    string
+		params  string
+		returns string
+	}{
+		{LanguageGo, "package p\nfunc (r *R) Copy(dst, src []byte, opts ...Option) (int, error) {}", "Copy", "dst []byte, src []byte, opts ...Option", "(int, error)"},
+		{LanguageGo, "package p\nfunc Name() string {}", "Name", "", "string"},
+		{LanguageTypeScript, "function load(path: string, retries?: number, ...rest: T[]): Promise<void> {}", "load", "path string, retries? number, ...rest T[]", "Promise<void>"},
+		{LanguageJavaScript, "function add(a, b = 1, ...more) {}", "add", "a, b, ...more", ""},
+		{LanguagePython, "def fetch(url: str, timeout=10, *args, retries: int = 3, **kw) -> bytes:\n    pass\n", "fetch", "url str, timeout, *args, retries int, **kw", "bytes"},
+		{LanguageRust, "fn push(&mut self, item: T) -> Result<(), Error> {}", "push", "&mut self, item T", "Result<(), Error>"},
+		{LanguageJava, "class A {\n  public List<T> find(int limit, String... names) {}\n}", "find", "limit int, names String...", "List<T>"},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, tt.code, tt.lang, tt.name)
+		params := make([]string, len(entity.Parameters))
+		for i, p := range entity.Parameters {
+			params[i] = strings.TrimSpace(p.Name + " " + p.Type)
+		}
+		if got := strings.Join(params, ", "); got != tt.params {
+			t.Errorf("%s %s: expected parameters %q, got %q", tt.lang, tt.name, tt.params, got)
+		}
+		if entity.ReturnType != tt.returns {
+			t.Errorf("%s %s: expected return type %q, got %q", tt.lang, tt.name, tt.returns, entity.ReturnType)
+		}
+	}
+}
+
+func TestEntityParametersOnlyForCallables(t *testing.T) {
+	entity := entityByName(t, "class Point:\n    pass\n", LanguagePython, "Point")
+	if entity.Parameters != nil || entity.ReturnType != "" {
+		t.Errorf("Expected no parameters for a class, got %v %q", entity.Parameters, entity.ReturnType)
+	}
+}
+
+func TestEntityTypeParameters(t *testing.T) {
+	tests := []struct {
+		lang Language
+		code string
+		name string
+		want string
+	}{
+		{LanguageGo, "package p\nfunc Map[K comparable, V any](m map[K]V) {}", "Map", "K comparable, V any"},
+		{LanguageTypeScript, "function pick<T extends object, K = keyof T>(o: T) {}", "pick", "T object, K"},
+		{LanguageTypeScript, "interface Store<T extends Item> {}", "Store", "T Item"},
+		{LanguageTypeScript, "class Cache<V> {}", "Cache", "V"},
+		{LanguageRust, "fn get<'a, T: Clone + 'a, const N: usize>(x: &'a T) {}", "get", "'a, T Clone + 'a, N usize"},
+		{LanguageJava, "class Box<T extends Comparable<T>> {}", "Box", "T Comparable<T>"},
+		{LanguageGo, "package p\nfunc Plain() {}", "Plain", ""},
+		{LanguageGo, "package p\nfunc (p *Pair[A, B]) Swap() {}\ntype Pair[K comparable, V any] struct{}", "Swap", "A comparable, B any"},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, tt.code, tt.lang, tt.name)
+		params := make([]string, len(entity.TypeParameters))
+		for i, p := range entity.TypeParameters {
+			params[i] = strings.TrimSpace(p.Name + " " + p.Constraint)
+		}
+		if got := strings.Join(params, ", "); got != tt.want {
+			t.Errorf("%s %s: expected type parameters %q, got %q", tt.lang, tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestGoTypeDeclarationTypeParameters(t *testing.T) {
+	code := "package p\ntype Set[T int | string] struct{}"
+	parseResult, err := parseString(code, LanguageGo)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	for _, entity := range extractEntities(parseResult.Tree.RootNode(), LanguageGo, []byte(code)) {
+		if entity.Type != EntityTypeType {
+			continue
+		}
+		if len(entity.TypeParameters) != 1 || entity.TypeParameters[0] != (TypeParam{Name: "T", Constraint: "int | string"}) {
+			t.Errorf("Expected T int | string, got %+v", entity.TypeParameters)
+		}
+		return
+	}
+	t.Fatal("No type entity extracted")
+}
@@ -0,0 +1,177 @@
+package codechunk
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// FileMetrics holds the timings and chunk statistics of one batch file
+type FileMetrics struct {
+	Filepath       string        // File path
+	Language       Language      // Detected or overridden language (empty if unsupported)
+	SourceBytes    int           // Size of the source code in bytes
+	ParseTime      time.Duration // Time spent parsing with tree-sitter (zero on cache hits)
+	ExtractTime    time.Duration // Time spent extracting entities, calls and exports
+	ChunkTime      time.Duration // Time spent assigning windows and building chunk context
+	TotalTime      time.Duration // Time spent chunking the file, from reading options to the last chunk
+	CacheHit       bool          // Whether the parse cache supplied the parsed file
+	Chunks         int           // Number of chunks produced
+	Entities       int           // Number of entities extracted
+	ChunkBytes     int           // Total size of chunk Text in bytes
+	MaxChunkBytes  int           // Size of the largest chunk Text in bytes
+	ErrorNodeCount int           // Number of ERROR and MISSING nodes in the syntax tree
+	Success        bool          // Whether the file was chunked without error
+}
+
+// Metrics receives FileMetrics from batch processing. ObserveFile is called from
+// worker goroutines, so implementations must be safe for concurrent use.
+type Metrics interface {
+	ObserveFile(m FileMetrics)
+}
+
+// MetricsFunc adapts a function to the Metrics interface
+type MetricsFunc func(m FileMetrics)
+
+// ObserveFile calls f(m).
+func (f MetricsFunc) ObserveFile(m FileMetrics) {
+	f(m)
+}
+
+// MetricsTotals are aggregate counters over observed files
+type MetricsTotals struct {
+	Files       int64         `json:"files"`       // Files observed
+	FailedFiles int64         `json:"failedFiles"` // Files that failed to chunk
+	CacheHits   int64         `json:"cacheHits"`   // Files served from the parse cache
+	SourceBytes int64         `json:"sourceBytes"` // Total source bytes
+	Chunks      int64         `json:"chunks"`      // Total chunks produced
+	Entities    int64         `json:"entities"`    // Total entities extracted
+	ChunkBytes  int64         `json:"chunkBytes"`  // Total chunk Text bytes
+	ParseTime   time.Duration `json:"parseTime"`   // Total parse time
+	ExtractTime time.Duration `json:"extractTime"` // Total extraction time
+	ChunkTime   time.Duration `json:"chunkTime"`   // Total chunking time
+}
+
+func (t *MetricsTotals) add(m FileMetrics) {
+	t.Files++
+	if !m.Success {
+		t.FailedFiles++
+	}
+	if m.CacheHit {
+		t.CacheHits++
+	}
+	t.SourceBytes += int64(m.SourceBytes)
+	t.Chunks += int64(m.Chunks)
+	t.Entities += int64(m.Entities)
+	t.ChunkBytes += int64(m.ChunkBytes)
+	t.ParseTime += m.ParseTime
+	t.ExtractTime += m.ExtractTime
+	t.ChunkTime += m.ChunkTime
+}
+
+// MetricsSnapshot is a point-in-time copy of the aggregates of a MetricsCollector
+type MetricsSnapshot struct {
+	Total      MetricsTotals              `json:"total"`      // Totals over all files
+	ByLanguage map[Language]MetricsTotals `json:"byLanguage"` // Totals per language ("" for unsupported files)
+}
+
+// MetricsCollector is a Metrics implementation that aggregates observed files
+// into totals overall and per language.
+type MetricsCollector struct {
+	mu         sync.Mutex
+	total      MetricsTotals
+	byLanguage map[Language]*MetricsTotals
+}
+
+// NewMetricsCollector creates an empty MetricsCollector.
+func NewMetricsCollector() *MetricsCollector {
+	return &MetricsCollector{byLanguage: make(map[Language]*MetricsTotals)}
+}
+
+// ObserveFile adds m to the aggregates.
+func (c *MetricsCollector) ObserveFile(m FileMetrics) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.total.add(m)
+	totals, ok := c.byLanguage[m.Language]
+	if !ok {
+		totals = &MetricsTotals{}
+		c.byLanguage[m.Language] = totals
+	}
+	totals.add(m)
+}
+
+// Snapshot returns a copy of the current aggregates.
+func (c *MetricsCollector) Snapshot() MetricsSnapshot {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	snapshot := MetricsSnapshot{
+		Total:      c.total,
+		ByLanguage: make(map[Language]MetricsTotals, len(c.byLanguage)),
+	}
+	for lang, totals := range c.byLanguage {
+		snapshot.ByLanguage[lang] = *totals
+	}
+	return snapshot
+}
+
+// WritePrometheus writes the per-language aggregates in the Prometheus text
+// exposition format, as codechunk_* counters labelled by language.
+func (s MetricsSnapshot) WritePrometheus(w io.Writer) error {
+	langs := make([]Language, 0, len(s.ByLanguage))
+	for lang := range s.ByLanguage {
+		langs = append(langs, lang)
+	}
+	sort.Slice(langs, func(i, j int) bool { return langs[i] < langs[j] })
+
+	counters := []struct {
+		name  string
+		help  string
+		value func(MetricsTotals) float64
+	}{
+		{"codechunk_files_total", "Files chunked.", func(t MetricsTotals) float64 { return float64(t.Files) }},
+		{"codechunk_failed_files_total", "Files that failed to chunk.", func(t MetricsTotals) float64 { return float64(t.FailedFiles) }},
+		{"codechunk_parse_cache_hits_total", "Files served from the parse cache.", func(t MetricsTotals) float64 { return float64(t.CacheHits) }},
+		{"codechunk_source_bytes_total", "Source bytes chunked.", func(t MetricsTotals) float64 { return float64(t.SourceBytes) }},
+		{"codechunk_chunks_total", "Chunks produced.", func(t MetricsTotals) float64 { return float64(t.Chunks) }},
+		{"codechunk_chunk_bytes_total", "Chunk text bytes produced.", func(t MetricsTotals) float64 { return float64(t.ChunkBytes) }},
+	}
+
+	for _, c := range counters {
+		if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name); err != nil {
+			return err
+		}
+		for _, lang := range langs {
+			if _, err := fmt.Fprintf(w, "%s{language=%q} %g\n", c.name, lang, c.value(s.ByLanguage[lang])); err != nil {
+				return err
+			}
+		}
+	}
+
+	const stageName = "codechunk_stage_seconds_total"
+	if _, err := fmt.Fprintf(w, "# HELP %s Time spent per chunking stage.\n# TYPE %s counter\n", stageName, stageName); err != nil {
+		return err
+	}
+	for _, lang := range langs {
+		totals := s.ByLanguage[lang]
+		stages := []struct {
+			stage    string
+			duration time.Duration
+		}{
+			{"parse", totals.ParseTime},
+			{"extract", totals.ExtractTime},
+			{"chunk", totals.ChunkTime},
+		}
+		for _, st := range stages {
+			if _, err := fmt.Fprintf(w, "%s{language=%q,stage=%q} %g\n", stageName, lang, st.stage, st.duration.Seconds()); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package codechunk
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestChunkBatchMetrics(t *testing.T) {
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package main\n\nfunc a() {}\n"},
+		{Filepath: "b.py", Code: "def b():\n    pass\n"},
+		{Filepath: "c.unknown", Code: "???"},
+	}
+
+	var mu sync.Mutex
+	observed := make(map[string]FileMetrics)
+	results := ChunkBatch(files, &BatchOptions{
+		Metrics: MetricsFunc(func(m FileMetrics) {
+			mu.Lock()
+			observed[m.Filepath] = m
+			mu.Unlock()
+		}),
+	})
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if len(observed) != 3 {
+		t.Fatalf("Expected metrics for 3 files, got %d", len(observed))
+	}
+
+	a := observed["a.go"]
+	if !a.Success || a.Language != LanguageGo || a.Chunks == 0 || a.Entities != 1 {
+		t.Errorf("Unexpected metrics for a.go: %+v", a)
+	}
+	if a.SourceBytes != len(files[0].Code) || a.ChunkBytes == 0 || a.MaxChunkBytes > a.ChunkBytes {
+		t.Errorf("Unexpected sizes for a.go: %+v", a)
+	}
+	if a.ParseTime <= 0 || a.ChunkTime <= 0 || a.TotalTime < a.ParseTime+a.ChunkTime {
+		t.Errorf("Expected non-zero stage timings for a.go: %+v", a)
+	}
+
+	c := observed["c.unknown"]
+	if c.Success || c.Language != "" || c.Chunks != 0 {
+		t.Errorf("Unexpected metrics for unsupported file: %+v", c)
+	}
+}
+
+func TestMetricsCollector(t *testing.T) {
+	cache := NewLRUParseCache(0)
+	collector := NewMetricsCollector()
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package main\n\nfunc a() {}\n"},
+		{Filepath: "c.unknown", Code: "???"},
+	}
+	opts := &BatchOptions{Metrics: collector, Concurrency: 1}
+	opts.ParseCache = cache
+
+	ChunkBatch(files, opts)
+	ChunkBatch(files[:1], opts)
+
+	snapshot := collector.Snapshot()
+	if snapshot.Total.Files != 3 || snapshot.Total.FailedFiles != 1 {
+		t.Errorf("Unexpected totals: %+v", snapshot.Total)
+	}
+	goTotals := snapshot.ByLanguage[LanguageGo]
+	if goTotals.Files != 2 || goTotals.CacheHits != 1 {
+		t.Errorf("Unexpected Go totals: %+v", goTotals)
+	}
+
+	var buf bytes.Buffer
+	if err := snapshot.WritePrometheus(&buf); err != nil {
+		t.Fatalf("WritePrometheus failed: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"# TYPE codechunk_files_total counter\n",
+		`codechunk_files_total{language="go"} 2`,
+		`codechunk_failed_files_total{language=""} 1`,
+		`codechunk_parse_cache_hits_total{language="go"} 1`,
+		`codechunk_stage_seconds_total{language="go",stage="parse"} `,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in output:\n%s", want, out)
+		}
+	}
+}
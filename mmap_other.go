@@ -0,0 +1,20 @@
+//go:build !unix
+
+package codechunk
+
+import (
+	"io"
+	"os"
+)
+
+// canMap reports whether files can be memory-mapped
+const canMap = false
+
+// mapFile reads the first size bytes of f into memory
+func mapFile(f *os.File, size int64) ([]byte, func(), error) {
+	code := make([]byte, size)
+	if _, err := io.ReadFull(f, code); err != nil {
+		return nil, nil, err
+	}
+	return code, func() {}, nil
+}
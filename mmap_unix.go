@@ -0,0 +1,23 @@
+//go:build unix
+
+package codechunk
+
+import (
+	"os"
+	"syscall"
+)
+
+// canMap reports whether files can be memory-mapped
+const canMap = true
+
+// mapFile maps the first size bytes of f read-only
+func mapFile(f *os.File, size int64) ([]byte, func(), error) {
+	if size == 0 {
+		return nil, func() {}, nil
+	}
+	data, err := syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+	if err != nil {
+		return nil, nil, &os.PathError{Op: "mmap", Path: f.Name(), Err: err}
+	}
+	return data, func() { syscall.Munmap(data) }, nil
+}
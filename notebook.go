@@ -0,0 +1,114 @@
+package codechunk
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// notebook is the subset of the Jupyter notebook format (nbformat 4) used for chunking
+type notebook struct {
+	Cells    []notebookCell `json:"cells"`
+	Metadata struct {
+		LanguageInfo struct {
+			Name string `json:"name"`
+		} `json:"language_info"`
+		Kernelspec struct {
+			Language string `json:"language"`
+		} `json:"kernelspec"`
+	} `json:"metadata"`
+}
+
+// notebookCell is a notebook cell. Source is a string or a list of lines.
+type notebookCell struct {
+	ID             string          `json:"id"`
+	CellType       string          `json:"cell_type"`
+	Source         json.RawMessage `json:"source"`
+	ExecutionCount *int            `json:"execution_count"`
+}
+
+// source returns the cell's source text
+func (c notebookCell) source() (string, error) {
+	if len(c.Source) == 0 {
+		return "", nil
+	}
+	var text string
+	if err := json.Unmarshal(c.Source, &text); err == nil {
+		return text, nil
+	}
+	var lines []string
+	if err := json.Unmarshal(c.Source, &lines); err != nil {
+		return "", err
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// language returns the language of the notebook's code cells, defaulting to Python
+func (nb *notebook) language() Language {
+	for _, name := range []string{nb.Metadata.LanguageInfo.Name, nb.Metadata.Kernelspec.Language} {
+		if name != "" {
+			return Language(strings.ToLower(name))
+		}
+	}
+	return LanguagePython
+}
+
+// chunkNotebook chunks a Jupyter notebook cell by cell. Code cells are chunked with
+// the notebook's language (opts.Language overrides it) and Markdown and raw cells as
+// plain text; code cells in an unsupported language are chunked as plain text too.
+// Every chunk records its cell in Context.Cell, and chunks are numbered across the
+// whole notebook. Cells are parsed with parser if set.
+func chunkNotebook(path string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	var nb notebook
+	if err := json.Unmarshal(code, &nb); err != nil {
+		return nil, &ChunkError{Stage: StageParse, Filepath: path, Err: fmt.Errorf("%w: invalid notebook: %v", ErrParseFailed, err)}
+	}
+
+	lang := opts.Language
+	if lang == "" {
+		lang = nb.language()
+	}
+	m.Language = lang
+
+	chunks := make([]CodeChunk, 0)
+	for i, cell := range nb.Cells {
+		source, err := cell.source()
+		if err != nil {
+			return nil, &ChunkError{Stage: StageParse, Filepath: path, Err: fmt.Errorf("%w: invalid source in notebook cell %d: %v", ErrParseFailed, i, err)}
+		}
+		if strings.TrimSpace(source) == "" {
+			continue
+		}
+
+		cellOpts := opts
+		cellOpts.cell = &NotebookCell{
+			Index:          i,
+			ID:             cell.ID,
+			CellType:       cell.CellType,
+			ExecutionCount: cell.ExecutionCount,
+		}
+
+		var cellChunks []CodeChunk
+		switch {
+		case cell.CellType == "code" && IsLanguageSupported(lang):
+			cellOpts.Language = lang
+			var cellMetrics FileMetrics
+			cellChunks, err = chunkSourceMeasured(path, []byte(source), cellOpts, &cellMetrics, parser)
+			if err != nil {
+				return nil, err
+			}
+			m.ParseTime += cellMetrics.ParseTime
+			m.ExtractTime += cellMetrics.ExtractTime
+			m.ChunkTime += cellMetrics.ChunkTime
+			m.Entities += cellMetrics.Entities
+		case cell.CellType == "markdown":
+			cellChunks = chunkPlainText(path, source, LanguageMarkdown, cellOpts)
+		default:
+			cellChunks = chunkPlainText(path, source, lang, cellOpts)
+		}
+		chunks = append(chunks, cellChunks...)
+	}
+
+	numberChunks(chunks)
+	return chunks, nil
+}
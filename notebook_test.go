@@ -0,0 +1,99 @@
+package codechunk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const sampleNotebook = `{
+ "cells": [
+  {"cell_type": "markdown", "id": "intro", "metadata": {}, "source": ["# Analysis\n", "\n", "Load the data."]},
+  {"cell_type": "code", "id": "load", "execution_count": 3, "metadata": {}, "outputs": [],
+   "source": ["import pandas as pd\n", "\n", "def load(path):\n", "    return pd.read_csv(path)\n"]},
+  {"cell_type": "code", "execution_count": null, "metadata": {}, "outputs": [], "source": ""},
+  {"cell_type": "code", "execution_count": null, "metadata": {}, "outputs": [], "source": "df = load('data.csv')\n"}
+ ],
+ "metadata": {"language_info": {"name": "python"}},
+ "nbformat": 4,
+ "nbformat_minor": 5
+}`
+
+func TestChunkNotebook(t *testing.T) {
+	chunks, err := Chunk("notebooks/analysis.ipynb", sampleNotebook, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 3 {
+		t.Fatalf("Expected 3 chunks (one per non-empty cell), got %d", len(chunks))
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Index != i || chunk.TotalChunks != len(chunks) {
+			t.Errorf("Chunk %d: expected Index %d and TotalChunks %d, got %d and %d", i, i, len(chunks), chunk.Index, chunk.TotalChunks)
+		}
+		if chunk.Context.Cell == nil {
+			t.Fatalf("Chunk %d: expected cell metadata", i)
+		}
+	}
+
+	intro := chunks[0]
+	if intro.Context.Cell.Index != 0 || intro.Context.Cell.CellType != "markdown" || intro.Context.Cell.ID != "intro" {
+		t.Errorf("Unexpected markdown cell metadata: %+v", intro.Context.Cell)
+	}
+	if intro.Context.Language != LanguageMarkdown || intro.Text != "# Analysis\n\nLoad the data." {
+		t.Errorf("Unexpected markdown chunk: %+v", intro)
+	}
+
+	load := chunks[1]
+	if load.Context.Cell.Index != 1 || load.Context.Cell.ExecutionCount == nil || *load.Context.Cell.ExecutionCount != 3 {
+		t.Errorf("Unexpected code cell metadata: %+v", load.Context.Cell)
+	}
+	if load.Context.Language != LanguagePython || load.ByteRange.Start != 0 || load.LineRange.Start != 0 {
+		t.Errorf("Expected a Python chunk relative to the cell source, got %+v", load)
+	}
+	if len(load.Context.Entities) == 0 || len(load.Context.Imports) == 0 {
+		t.Errorf("Expected Python entities and imports, got %+v", load.Context)
+	}
+	if !strings.Contains(load.ContextualizedText, "analysis.ipynb [cell 1]") {
+		t.Errorf("Expected the header to name the cell, got %q", load.ContextualizedText)
+	}
+
+	if chunks[2].Context.Cell.Index != 3 || chunks[2].Context.Cell.ExecutionCount != nil {
+		t.Errorf("Unexpected metadata for the last cell: %+v", chunks[2].Context.Cell)
+	}
+}
+
+func TestChunkNotebookUnsupportedKernel(t *testing.T) {
+	code := `{"cells": [{"cell_type": "code", "source": "x <- 1\n"}], "metadata": {"language_info": {"name": "R"}}}`
+	chunks, err := Chunk("stats.ipynb", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Text != "x <- 1" || chunks[0].Context.Language != Language("r") {
+		t.Errorf("Expected code in an unsupported language to be chunked as text, got %+v", chunks)
+	}
+}
+
+func TestChunkNotebookInvalid(t *testing.T) {
+	if _, err := Chunk("broken.ipynb", "{not json", nil); !errors.Is(err, ErrParseFailed) {
+		t.Errorf("Expected ErrParseFailed, got %v", err)
+	}
+}
+
+func TestChunkStreamNotebook(t *testing.T) {
+	ch, err := ChunkStream("analysis.ipynb", sampleNotebook, nil)
+	if err != nil {
+		t.Fatalf("ChunkStream failed: %v", err)
+	}
+	var count int
+	for chunk := range ch {
+		if chunk.TotalChunks != -1 || chunk.Context.Cell == nil {
+			t.Errorf("Unexpected streamed chunk: %+v", chunk)
+		}
+		count++
+	}
+	if count != 3 {
+		t.Errorf("Expected 3 streamed chunks, got %d", count)
+	}
+}
@@ -0,0 +1,208 @@
+package codechunk
+
+import "log/slog"
+
+// Option configures ChunkOptions. Options are applied in order on top of
+// DefaultChunkOptions, so zero values passed to them are honored.
+type Option func(*ChunkOptions)
+
+// NewChunkOptions returns DefaultChunkOptions with opts applied.
+func NewChunkOptions(opts ...Option) ChunkOptions {
+	options := DefaultChunkOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// ChunkWithOptions is like Chunk but takes functional options:
+//
+//	chunks, err := codechunk.ChunkWithOptions("main.go", code,
+//		codechunk.WithMaxChunkSize(800),
+//		codechunk.WithContextMode(codechunk.ContextModeMinimal),
+//	)
+func ChunkWithOptions(filepath string, code string, opts ...Option) ([]CodeChunk, error) {
+	return chunkFile(filepath, []byte(code), NewChunkOptions(opts...))
+}
+
+// WithMaxChunkSize sets the maximum chunk size in NWS characters.
+func WithMaxChunkSize(size int) Option {
+	return func(o *ChunkOptions) { o.MaxChunkSize = size }
+}
+
+// WithMinChunkSize sets the size in NWS characters below which a chunk is merged
+// into its neighbor.
+func WithMinChunkSize(size int) Option {
+	return func(o *ChunkOptions) { o.MinChunkSize = size }
+}
+
+// WithBoundaryHeuristics sets whether chunks are split at blank lines and before
+// comment groups rather than wherever the size limit is reached.
+func WithBoundaryHeuristics(enabled bool) Option {
+	return func(o *ChunkOptions) { o.BoundaryHeuristics = enabled }
+}
+
+// WithSplitClasses sets whether oversized classes are split into a chunk per method.
+func WithSplitClasses(split bool) Option {
+	return func(o *ChunkOptions) { o.SplitClasses = split }
+}
+
+// WithContextMode sets how much context to include.
+func WithContextMode(mode ContextMode) Option {
+	return func(o *ChunkOptions) { o.ContextMode = mode }
+}
+
+// WithSiblingDetail sets the level of sibling detail.
+func WithSiblingDetail(detail SiblingDetail) Option {
+	return func(o *ChunkOptions) { o.SiblingDetail = detail }
+}
+
+// WithFilterImports sets whether only imports used by the chunk are included.
+func WithFilterImports(filter bool) Option {
+	return func(o *ChunkOptions) { o.FilterImports = filter }
+}
+
+// WithLanguage overrides language detection.
+func WithLanguage(lang Language) Option {
+	return func(o *ChunkOptions) { o.Language = lang }
+}
+
+// WithOverlapLines sets the number of lines carried over from the previous chunk.
+func WithOverlapLines(lines int) Option {
+	return func(o *ChunkOptions) { o.OverlapLines = lines }
+}
+
+// WithOverlapMode sets how overlap with the previous chunk is built.
+func WithOverlapMode(mode OverlapMode) Option {
+	return func(o *ChunkOptions) { o.OverlapMode = mode }
+}
+
+// WithChunkStrategy sets how nodes are grouped into chunks.
+func WithChunkStrategy(strategy ChunkStrategy) Option {
+	return func(o *ChunkOptions) { o.ChunkStrategy = strategy }
+}
+
+// WithStripBoilerplate leaves license headers and banners at the top of files out
+// of chunks, identifying them by patterns or DefaultBoilerplatePatterns if none
+// are given.
+func WithStripBoilerplate(patterns ...string) Option {
+	return func(o *ChunkOptions) {
+		o.StripBoilerplate = true
+		o.BoilerplatePatterns = patterns
+	}
+}
+
+// WithHierarchical enables emitting a file overview chunk as the parent of the
+// other chunks.
+func WithHierarchical(enabled bool) Option {
+	return func(o *ChunkOptions) { o.Hierarchical = enabled }
+}
+
+// WithSlidingWindowFallback enables chunking minified files and files with few
+// entities by overlapping windows of lines.
+func WithSlidingWindowFallback(enabled bool) Option {
+	return func(o *ChunkOptions) { o.SlidingWindowFallback = enabled }
+}
+
+// WithFormatFunc sets a custom ContextualizedText formatter.
+func WithFormatFunc(f ContextFormatter) Option {
+	return func(o *ChunkOptions) { o.FormatFunc = f }
+}
+
+// WithTokenCounter sets the token counter used for CodeChunk.Stats.
+func WithTokenCounter(counter TokenCounter) Option {
+	return func(o *ChunkOptions) { o.TokenCounter = counter }
+}
+
+// WithCommentPrefix overrides the header comment prefix.
+func WithCommentPrefix(prefix string) Option {
+	return func(o *ChunkOptions) { o.CommentPrefix = prefix }
+}
+
+// WithParseCache sets the cache of parsed files.
+func WithParseCache(cache ParseCache) Option {
+	return func(o *ChunkOptions) { o.ParseCache = cache }
+}
+
+// WithIncludeImportsInText sets whether raw import statements are prepended to
+// ContextualizedText.
+func WithIncludeImportsInText(include bool) Option {
+	return func(o *ChunkOptions) { o.IncludeImportsInText = include }
+}
+
+// WithMaxContextHeaderSize sets the size in tokens, as counted by the token
+// counter, that the context header of the contextualized text is trimmed to.
+func WithMaxContextHeaderSize(tokens int) Option {
+	return func(o *ChunkOptions) { o.MaxContextHeaderSize = tokens }
+}
+
+// WithContextualizedTextMode sets whether the context header is prepended to the
+// contextualized text, left out or returned separately in ContextHeader.
+func WithContextualizedTextMode(mode ContextualizedTextMode) Option {
+	return func(o *ChunkOptions) { o.ContextualizedTextMode = mode }
+}
+
+// WithParallelism sets the number of goroutines building the chunks of one file;
+// negative uses GOMAXPROCS.
+func WithParallelism(n int) Option {
+	return func(o *ChunkOptions) { o.Parallelism = n }
+}
+
+// WithMaxParseErrorRatio sets the fraction of the source that ERROR nodes may
+// cover before a file is rejected or, with the sliding-window fallback, chunked
+// as plain text.
+func WithMaxParseErrorRatio(ratio float64) Option {
+	return func(o *ChunkOptions) { o.MaxParseErrorRatio = ratio }
+}
+
+// WithMaxFileSize sets the size in bytes of the largest file to chunk.
+func WithMaxFileSize(n int) Option {
+	return func(o *ChunkOptions) { o.MaxFileSize = n }
+}
+
+// WithMaxChunksPerFile sets the most chunks to produce for a file.
+func WithMaxChunksPerFile(n int) Option {
+	return func(o *ChunkOptions) { o.MaxChunksPerFile = n }
+}
+
+// WithTruncateOnLimit chunks files over MaxFileSize or MaxChunksPerFile up to the
+// limit rather than failing them.
+func WithTruncateOnLimit(truncate bool) Option {
+	return func(o *ChunkOptions) { o.TruncateOnLimit = truncate }
+}
+
+// WithSkipTrivialChunks leaves out chunks holding only imports or only comments.
+func WithSkipTrivialChunks(skip bool) Option {
+	return func(o *ChunkOptions) { o.SkipTrivialChunks = skip }
+}
+
+// WithAnonymousNaming sets how entities without a name of their own are named.
+func WithAnonymousNaming(naming AnonymousNaming) Option {
+	return func(o *ChunkOptions) { o.AnonymousNaming = naming }
+}
+
+// WithProvenance sets the repository coordinates copied into the context of every
+// chunk.
+func WithProvenance(p Provenance) Option {
+	return func(o *ChunkOptions) { o.Provenance = &p }
+}
+
+// WithMaxSiblings sets the number of siblings listed on each side of a chunk.
+func WithMaxSiblings(n int) Option {
+	return func(o *ChunkOptions) { o.MaxSiblings = n }
+}
+
+// WithMaxSiblingSignatureLen sets the length sibling signatures are truncated to.
+func WithMaxSiblingSignatureLen(n int) Option {
+	return func(o *ChunkOptions) { o.MaxSiblingSignatureLen = n }
+}
+
+// WithLazyText sets whether chunk text is materialized on demand from the retained source.
+func WithLazyText(lazy bool) Option {
+	return func(o *ChunkOptions) { o.LazyText = lazy }
+}
+
+// WithLogger sets the logger receiving debug and trace logs of chunking decisions.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *ChunkOptions) { o.Logger = logger }
+}
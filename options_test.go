@@ -0,0 +1,78 @@
+package codechunk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewChunkOptions(t *testing.T) {
+	opts := NewChunkOptions(
+		WithMaxChunkSize(800),
+		WithContextMode(ContextModeMinimal),
+		WithOverlapLines(0),
+		WithLanguage(LanguageGo),
+	)
+
+	if opts.MaxChunkSize != 800 {
+		t.Errorf("expected MaxChunkSize 800, got %d", opts.MaxChunkSize)
+	}
+	if opts.ContextMode != ContextModeMinimal {
+		t.Errorf("expected ContextMode minimal, got %s", opts.ContextMode)
+	}
+	if opts.OverlapLines != 0 {
+		t.Errorf("expected OverlapLines 0, got %d", opts.OverlapLines)
+	}
+	if opts.Language != LanguageGo {
+		t.Errorf("expected Language go, got %s", opts.Language)
+	}
+	if opts.SiblingDetail != SiblingDetailSignatures {
+		t.Errorf("expected default SiblingDetail, got %s", opts.SiblingDetail)
+	}
+	if !opts.DefaultsApplied {
+		t.Error("expected DefaultsApplied true")
+	}
+}
+
+func TestNewChunkOptionsLaterWins(t *testing.T) {
+	opts := NewChunkOptions(WithMaxSiblings(5), WithMaxSiblings(1))
+	if opts.MaxSiblings != 1 {
+		t.Errorf("expected MaxSiblings 1, got %d", opts.MaxSiblings)
+	}
+}
+
+func TestChunkWithFunctionalOptions(t *testing.T) {
+	code := `package main
+
+func first() {
+	println("first")
+}
+
+func second() {
+	println("second")
+}
+`
+	chunks, err := ChunkWithOptions("main.go", code,
+		WithMaxChunkSize(25),
+		WithOverlapLines(0),
+		WithCommentPrefix(";;"),
+	)
+	if err != nil {
+		t.Fatalf("ChunkWithOptions failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !strings.HasPrefix(chunk.ContextualizedText, ";; main.go") {
+			t.Errorf("Chunk %d: expected custom comment prefix, got:\n%s", i, chunk.ContextualizedText)
+		}
+		if strings.Contains(chunk.ContextualizedText, ";; ...") {
+			t.Errorf("Chunk %d: expected no overlap", i)
+		}
+	}
+
+	if _, err := ChunkWithOptions("file.unknown", "just some notes"); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("Expected ErrUnsupportedLanguage, got %v", err)
+	}
+}
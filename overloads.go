@@ -0,0 +1,96 @@
+package codechunk
+
+import (
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// tsSignatureNodeTypes are the TypeScript declarations of a function or method
+// without a body, which overload a declaration of the same name that follows them
+var tsSignatureNodeTypes = map[string]bool{
+	"function_signature": true,
+	"method_signature":   true,
+}
+
+// tsOverloadItem returns the node standing for a TypeScript declaration among its
+// siblings: the export or declare statement wrapping it, or the declaration itself
+func tsOverloadItem(node *sitter.Node) *sitter.Node {
+	if parent := node.Parent(); parent != nil && (parent.Type() == "export_statement" || parent.Type() == "ambient_declaration") {
+		return parent
+	}
+	return node
+}
+
+// tsOverloadDeclaration returns the function or method a statement or class member
+// declares, looking through export and declare statements, and its name. Returns
+// nil for other items.
+func tsOverloadDeclaration(item *sitter.Node, code []byte) (*sitter.Node, string) {
+	decl := item
+	switch item.Type() {
+	case "export_statement":
+		decl = item.ChildByFieldName("declaration")
+	case "ambient_declaration":
+		if item.NamedChildCount() > 0 {
+			decl = item.NamedChild(0)
+		}
+	}
+	if decl == nil {
+		return nil, ""
+	}
+	switch decl.Type() {
+	case "function_signature", "function_declaration", "generator_function_declaration", "method_signature", "method_definition":
+	default:
+		return nil, ""
+	}
+	nameNode := decl.ChildByFieldName("name")
+	if nameNode == nil {
+		return nil, ""
+	}
+	return decl, nameNode.Content(code)
+}
+
+// isTSOverloadSignature reports whether a TypeScript function signature overloads
+// the declaration of the same name following it, skipping comments, and so is
+// folded into that declaration rather than extracted as an entity of its own
+func isTSOverloadSignature(node *sitter.Node, code []byte) bool {
+	if !tsSignatureNodeTypes[node.Type()] {
+		return false
+	}
+	_, name := tsOverloadDeclaration(node, code)
+	next := tsOverloadItem(node).NextNamedSibling()
+	for next != nil && isCommentNode(next) {
+		next = next.NextNamedSibling()
+	}
+	if next == nil {
+		return false
+	}
+	_, nextName := tsOverloadDeclaration(next, code)
+	return name != "" && nextName == name
+}
+
+// tsOverloads returns the signatures of the overloads of a TypeScript function or
+// method, in source order: the declarations of the same name without a body
+// directly before it, skipping comments. first is the statement or member of the
+// first overload, or nil if there are none.
+func tsOverloads(node *sitter.Node, lang Language, code []byte) (signatures []string, first *sitter.Node) {
+	if lang != LanguageTypeScript {
+		return nil, nil
+	}
+	item := tsOverloadItem(node)
+	decl, name := tsOverloadDeclaration(item, code)
+	if decl == nil {
+		return nil, nil
+	}
+
+	for prev := item.PrevNamedSibling(); prev != nil; prev = prev.PrevNamedSibling() {
+		if isCommentNode(prev) {
+			continue
+		}
+		prevDecl, prevName := tsOverloadDeclaration(prev, code)
+		if prevDecl == nil || prevName != name || !tsSignatureNodeTypes[prevDecl.Type()] {
+			break
+		}
+		signatures = append([]string{statementSignature(prevDecl, code)}, signatures...)
+		first = prev
+	}
+	return signatures, first
+}
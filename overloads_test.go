@@ -0,0 +1,91 @@
+package codechunk
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const overloadSource = `/** Parses a document. */
+export function parse(input: string): Node;
+export function parse(input: Buffer): Node;
+export function parse(input: string | Buffer): Node {
+  return build(input);
+}
+
+interface Options { strict: boolean }
+interface Options { depth: number }
+
+class Store {
+  get(key: string): string;
+  // Numeric keys index the entries
+  get(key: number): string;
+  get(key: any) {
+    return "";
+  }
+}
+
+declare function ambient(x: string): void;
+declare function ambient(x: number): void;
+`
+
+func TestOverloads(t *testing.T) {
+	analysis, err := Analyze("parse.ts", overloadSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer analysis.Close()
+
+	expected := map[string][]string{
+		"parse":   {"function parse(input: string): Node", "function parse(input: Buffer): Node"},
+		"get":     {"get(key: string): string", "get(key: number): string"},
+		"ambient": {"function ambient(x: string): void"},
+	}
+	counts := make(map[string]int)
+	for _, entity := range analysis.Entities {
+		counts[string(entity.Type)+" "+entity.Name]++
+		want, ok := expected[entity.Name]
+		if !ok || entity.Type == EntityTypeExport {
+			continue
+		}
+		if !reflect.DeepEqual(entity.Overloads, want) {
+			t.Errorf("%s: expected overloads %q, got %q", entity.Name, want, entity.Overloads)
+		}
+		if entity.Name == "parse" {
+			if entity.LineRange.Start != 1 || entity.Docstring == nil || *entity.Docstring != "Parses a document." {
+				t.Errorf("Expected parse to start at its first overload with its doc comment, got line %d", entity.LineRange.Start)
+			}
+		}
+	}
+	for _, name := range []string{"function parse", "export parse", "method get", "function ambient"} {
+		if counts[name] != 1 {
+			t.Errorf("Expected one %s entity, got %d", name, counts[name])
+		}
+	}
+}
+
+func TestOverloadsInContextHeader(t *testing.T) {
+	chunks, err := Chunk("parse.ts", overloadSource, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := chunks[0].ContextualizedText[:strings.Index(chunks[0].ContextualizedText, "\n\n")]
+
+	want := "function parse(input: string): Node, function parse(input: Buffer): Node, function parse(input: string | Buffer): Node"
+	if !strings.Contains(header, want) {
+		t.Errorf("Expected the overloads of parse in the header, got:\n%s", header)
+	}
+	if n := strings.Count(header, "interface Options"); n != 1 {
+		t.Errorf("Expected the merged interface once in the header, got %d times:\n%s", n, header)
+	}
+
+	var options []ChunkEntityInfo
+	for _, entity := range chunks[0].Context.Entities {
+		if entity.Name == "Options" {
+			options = append(options, entity)
+		}
+	}
+	if len(options) != 1 {
+		t.Errorf("Expected Options to be listed once, got %+v", options)
+	}
+}
@@ -0,0 +1,143 @@
+package codechunk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// overviewChunk builds the file overview chunk of hierarchical chunking: the file
+// path, the imports and the signatures of all entities indented by scope, without
+// their bodies. It spans the whole file.
+func overviewChunk(filepath string, code []byte, lang Language, scopeTree *ScopeTree, fileDoc string, opts ChunkOptions) CodeChunk {
+	opts = opts.withDefaults()
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "File: %s\n", filepath)
+	if len(scopeTree.Imports) > 0 {
+		b.WriteString("Imports:\n")
+		for _, imp := range scopeTree.Imports {
+			name := imp.Name
+			if imp.Source != nil && *imp.Source != "" {
+				name = *imp.Source
+			}
+			fmt.Fprintf(&b, "  %s\n", name)
+		}
+	}
+	if len(scopeTree.Root) > 0 {
+		b.WriteString("Entities:\n")
+		for _, node := range scopeTree.Root {
+			writeOverviewNode(&b, node, 1)
+		}
+	}
+	text := strings.TrimSuffix(b.String(), "\n")
+
+	byteRange := ByteRange{Start: 0, End: len(code)}
+	ctx := ChunkContext{
+		Scope:      []EntityInfo{},
+		Entities:   []ChunkEntityInfo{},
+		Siblings:   []SiblingInfo{},
+		Imports:    []ImportInfo{},
+		References: []ReferenceInfo{},
+	}
+	if opts.ContextMode != ContextModeNone {
+		ctx.Filepath = filepath
+		ctx.Language = lang
+	}
+	if opts.ContextMode == ContextModeFull {
+		ctx.Entities = getEntitiesInRange(byteRange, scopeTree)
+		ctx.Imports = getRelevantImports(byteRange, nil, scopeTree, filepath, false)
+		ctx.FileDoc = fileDoc
+	}
+
+	positions := newPositionIndex(code)
+	lineRange := LineRange{Start: 0, End: positions.position(len(code)).Line}
+	chunk := CodeChunk{
+		Kind:      ChunkKindOverview,
+		Text:      text,
+		ByteRange: byteRange,
+		LineRange: lineRange,
+		Span:      positions.span(byteRange),
+		Stats:     chunkStats(text, LineRange{Start: 0, End: strings.Count(text, "\n")}, opts),
+		Context:   ctx,
+	}
+	chunk.contextualize(opts, text, "", "")
+	return chunk
+}
+
+// writeOverviewNode writes the signature of a scope node and its children, one per
+// line and indented by depth
+func writeOverviewNode(b *strings.Builder, node *ScopeNode, depth int) {
+	signature := strings.Join(strings.Fields(annotatedSignature(node.Entity.Signature, node.Entity.Annotations)), " ")
+	if signature == "" {
+		signature = string(node.Entity.Type) + " " + node.Entity.Name
+	}
+	fmt.Fprintf(b, "%s%s\n", strings.Repeat("  ", depth), signature)
+	for _, child := range node.Children {
+		writeOverviewNode(b, child, depth+1)
+	}
+}
+
+// withOverview prepends the overview chunk to the detail chunks of a file, which
+// become its children at level 1, and renumbers them
+func withOverview(overview CodeChunk, chunks []CodeChunk) []CodeChunk {
+	all := make([]CodeChunk, 0, len(chunks)+1)
+	all = append(all, overview)
+	all = append(all, chunks...)
+	for i := range all {
+		all[i].Index = i
+		all[i].TotalChunks = len(all)
+		if i > 0 {
+			all[i].Level = 1
+		}
+	}
+	return all
+}
+
+// linkChunks sets the ID of every chunk of a file and links each to its previous
+// and next chunk in file order. If hierarchical is set, the leading overview chunk
+// is left out of that order and linked to the detail chunks as their parent.
+func linkChunks(filepath string, chunks []CodeChunk, hierarchical bool) {
+	for i := range chunks {
+		chunks[i].ID = chunkID(filepath, chunks[i])
+	}
+	details := chunks
+	if hierarchical && len(chunks) > 0 {
+		details = chunks[1:]
+	}
+	for i := range details {
+		if i > 0 {
+			details[i].PrevChunkID = details[i-1].ID
+		}
+		if i < len(details)-1 {
+			details[i].NextChunkID = details[i+1].ID
+		}
+	}
+	if !hierarchical || len(chunks) == 0 {
+		return
+	}
+
+	// Without detail chunks ChildChunkIDs stays nil, as serialization leaves it
+	overview := &chunks[0]
+	overview.ChildChunkIDs = nil
+	if len(chunks) > 1 {
+		overview.ChildChunkIDs = make([]string, 0, len(chunks)-1)
+	}
+	for i := 1; i < len(chunks); i++ {
+		chunks[i].ParentChunkID = overview.ID
+		overview.ChildChunkIDs = append(overview.ChildChunkIDs, chunks[i].ID)
+	}
+}
+
+// chunkID derives a stable ID for a chunk from its file path, notebook cell, byte
+// range and level, so re-chunking unchanged content yields the same IDs
+func chunkID(filepath string, chunk CodeChunk) string {
+	cell := -1
+	if chunk.Context.Cell != nil {
+		cell = chunk.Context.Cell.Index
+	}
+	key := fmt.Sprintf("%s\x00%d\x00%d\x00%d\x00%d", filepath, cell, chunk.ByteRange.Start, chunk.ByteRange.End, chunk.Level)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:8])
+}
@@ -0,0 +1,120 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkHierarchical(t *testing.T) {
+	code := `import { db } from './db'
+import path from 'path'
+
+export class UserService {
+  find(id) {
+    return db.get(id)
+  }
+
+  save(user) {
+    return db.put(path.join('users', user.id), user)
+  }
+}
+
+function helper() {
+  return 1
+}
+`
+	opts := NewChunkOptions(WithHierarchical(true), WithMaxChunkSize(60))
+	chunks, err := Chunk("src/user.js", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 3 {
+		t.Fatalf("expected an overview and detail chunks, got %d chunks", len(chunks))
+	}
+
+	overview := chunks[0]
+	expected := "File: src/user.js\n" +
+		"Imports:\n  ./db\n  path\n" +
+		"Entities:\n  class UserService\n    find(id)\n    save(user)\n  function helper()"
+	if overview.Text != expected {
+		t.Errorf("unexpected overview text:\n%s\nexpected:\n%s", overview.Text, expected)
+	}
+	if overview.Level != 0 || overview.ParentChunkID != "" || overview.ByteRange != (ByteRange{Start: 0, End: len(code)}) {
+		t.Errorf("unexpected overview chunk %+v", overview)
+	}
+	if overview.PrevChunkID != "" || overview.NextChunkID != "" {
+		t.Error("expected the overview out of the prev/next order")
+	}
+	if strings.Contains(overview.Text, "db.get") {
+		t.Error("expected no bodies in the overview")
+	}
+	if len(overview.ChildChunkIDs) != len(chunks)-1 {
+		t.Fatalf("expected %d child IDs, got %d", len(chunks)-1, len(overview.ChildChunkIDs))
+	}
+
+	for i, chunk := range chunks {
+		if chunk.Index != i || chunk.TotalChunks != len(chunks) {
+			t.Errorf("chunk %d: unexpected index %d of %d", i, chunk.Index, chunk.TotalChunks)
+		}
+		if i == 0 {
+			continue
+		}
+		if chunk.Level != 1 || chunk.ParentChunkID != overview.ID || overview.ChildChunkIDs[i-1] != chunk.ID {
+			t.Errorf("chunk %d: expected a level 1 child of the overview, got level %d, parent %q", i, chunk.Level, chunk.ParentChunkID)
+		}
+		if (i == 1 && chunk.PrevChunkID != "") || (i > 1 && chunk.PrevChunkID != chunks[i-1].ID) {
+			t.Errorf("chunk %d: expected the previous detail chunk, got %q", i, chunk.PrevChunkID)
+		}
+	}
+}
+
+func TestChunkIDs(t *testing.T) {
+	code := "def a():\n    return 1\n\n\ndef b():\n    return 2\n"
+	opts := NewChunkOptions(WithMaxChunkSize(15))
+	first, err := Chunk("ids.py", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	second, err := Chunk("ids.py", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	other, err := Chunk("other.py", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	if len(first) != 2 {
+		t.Fatalf("expected 2 chunks, got %d", len(first))
+	}
+	if first[0].ID == "" || first[0].ID == first[1].ID {
+		t.Errorf("expected distinct IDs, got %q and %q", first[0].ID, first[1].ID)
+	}
+	for i := range first {
+		if first[i].ID != second[i].ID {
+			t.Errorf("chunk %d: expected a stable ID, got %q and %q", i, first[i].ID, second[i].ID)
+		}
+		if first[i].ID == other[i].ID {
+			t.Errorf("chunk %d: expected IDs to depend on the file path", i)
+		}
+		if first[i].ParentChunkID != "" || first[i].Level != 0 {
+			t.Errorf("chunk %d: expected no hierarchy without Hierarchical", i)
+		}
+	}
+
+	if first[0].PrevChunkID != "" || first[0].NextChunkID != first[1].ID || first[1].PrevChunkID != first[0].ID || first[1].NextChunkID != "" {
+		t.Errorf("expected the chunks linked in file order, got %+v", first)
+	}
+
+	stream, err := ChunkStream("ids.py", code, &opts)
+	if err != nil {
+		t.Fatalf("ChunkStream failed: %v", err)
+	}
+	i := 0
+	for chunk := range stream {
+		if chunk.ID != first[i].ID || chunk.PrevChunkID != first[i].PrevChunkID || chunk.NextChunkID != first[i].NextChunkID {
+			t.Errorf("chunk %d: expected streamed links %+v, got %+v", i, first[i], chunk)
+		}
+		i++
+	}
+}
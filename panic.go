@@ -0,0 +1,71 @@
+package codechunk
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is the error of a batch file whose chunking panicked, for example
+// on a tree-sitter edge case or in a FormatFunc or TokenCounter. It matches
+// ErrPanicked with errors.Is, and unwraps to the panic value if it is an error.
+type PanicError struct {
+	Filepath string // File being chunked
+	Value    any    // Value passed to panic
+	Stack    []byte // Stack trace of the goroutine that panicked
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic while chunking %s: %v", e.Filepath, e.Value)
+}
+
+func (e *PanicError) Is(target error) bool {
+	return target == ErrPanicked
+}
+
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// goroutinePanic carries a panic out of a goroutine started while chunking, with
+// the stack of that goroutine
+type goroutinePanic struct {
+	value any
+	stack []byte
+}
+
+func (p *goroutinePanic) String() string {
+	return fmt.Sprintf("%v\n\n%s", p.value, p.stack)
+}
+
+// recoverGoroutinePanic, deferred in a goroutine, stores a panic in p instead of
+// letting it crash the process; the goroutine waiting for it then re-panics
+func recoverGoroutinePanic(p **goroutinePanic) {
+	if r := recover(); r != nil {
+		if gp, ok := r.(*goroutinePanic); ok {
+			*p = gp
+			return
+		}
+		*p = &goroutinePanic{value: r, stack: debug.Stack()}
+	}
+}
+
+// chunkFileIsolated is chunkFileInput returning a PanicError result if chunking
+// panics, unless opts.StrictMode is set. It reports whether it recovered a panic,
+// after which parser should not be reused.
+func chunkFileIsolated(file FileInput, opts BatchOptions, parser *languageParser) (result BatchResult, panicked bool) {
+	if !opts.StrictMode {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+			err := &PanicError{Filepath: file.Filepath, Value: r, Stack: debug.Stack()}
+			if gp, ok := r.(*goroutinePanic); ok {
+				err.Value, err.Stack = gp.value, gp.stack
+			}
+			result, panicked = BatchResult{Filepath: file.Filepath, Error: err}, true
+		}()
+	}
+	return chunkFileInput(file, opts.ChunkOptions, opts.Metrics, parser), false
+}
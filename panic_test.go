@@ -0,0 +1,84 @@
+package codechunk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// panickyFormatter panics while formatting chunks containing "boom"
+func panickyFormatter(text string, ctx ChunkContext, overlapText string) string {
+	if strings.Contains(text, "boom") {
+		panic("formatter failed")
+	}
+	return text
+}
+
+func TestChunkBatchRecoversPanics(t *testing.T) {
+	var many strings.Builder
+	many.WriteString("package main\n\n")
+	for i := 0; i < 64; i++ {
+		fmt.Fprintf(&many, "func f%d() {\n\tprintln(\"boom\")\n}\n\n", i)
+	}
+	files := []FileInput{
+		{Filepath: "ok.go", Code: "package main\n\nfunc ok() {}\n"},
+		{Filepath: "boom.go", Code: "package main\n\nfunc boom() {}\n"},
+		{Filepath: "parallel.go", Code: many.String(), Options: &ChunkOptions{MaxChunkSize: 40}},
+	}
+	opts := DefaultBatchOptions()
+	opts.FormatFunc = panickyFormatter
+	opts.Parallelism = 4
+	opts.Concurrency = 1
+
+	results := ChunkBatch(files, &opts)
+	if results[0].Error != nil || len(results[0].Chunks) == 0 {
+		t.Errorf("Expected ok.go to be chunked, got %v", results[0].Error)
+	}
+	for _, result := range results[1:] {
+		var panicErr *PanicError
+		if !errors.As(result.Error, &panicErr) || !errors.Is(result.Error, ErrPanicked) {
+			t.Fatalf("Expected a PanicError for %s, got %v", result.Filepath, result.Error)
+		}
+		if panicErr.Filepath != result.Filepath || panicErr.Value != "formatter failed" {
+			t.Errorf("Unexpected PanicError: %+v", panicErr)
+		}
+		if !strings.Contains(string(panicErr.Stack), "panickyFormatter") {
+			t.Errorf("Expected the stack to show the panicking function, got:\n%s", panicErr.Stack)
+		}
+		if ErrorCode(result.Error) != ErrorCodePanicked {
+			t.Errorf("Expected error code %q, got %q", ErrorCodePanicked, ErrorCode(result.Error))
+		}
+	}
+
+	// The streaming API recovers the same way
+	for result := range ChunkBatchStream(files[1:2], &opts) {
+		if !errors.Is(result.Error, ErrPanicked) {
+			t.Errorf("Expected a PanicError from the stream, got %v", result.Error)
+		}
+	}
+
+	opts.StrictMode = true
+	defer func() {
+		if r := recover(); r != "formatter failed" {
+			t.Errorf("Expected the panic to propagate in strict mode, got %v", r)
+		}
+	}()
+	chunkFileIsolated(files[1], opts, newLanguageParser())
+	t.Error("Expected a panic in strict mode")
+}
+
+func TestParallelForPropagatesPanics(t *testing.T) {
+	defer func() {
+		p, ok := recover().(*goroutinePanic)
+		if !ok || p.value != "worker failed" || len(p.stack) == 0 {
+			t.Errorf("Expected the worker panic on the calling goroutine, got %v", p)
+		}
+	}()
+	parallelFor(64, 4, func(i int) {
+		if i == 40 {
+			panic("worker failed")
+		}
+	})
+	t.Error("Expected parallelFor to panic")
+}
@@ -0,0 +1,55 @@
+package codechunk
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minChunksPerWorker is the fewest chunks worth handing to another goroutine;
+// below it, starting the goroutine costs more than building the chunks
+const minChunksPerWorker = 16
+
+// workersFor returns the number of goroutines to build n chunks on with the
+// given Parallelism option: negative means GOMAXPROCS, and no goroutine gets
+// fewer than minChunksPerWorker chunks
+func workersFor(n, parallelism int) int {
+	if parallelism < 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	return max(1, min(parallelism, n/minChunksPerWorker))
+}
+
+// parallelFor calls fn for every index below n on up to workers goroutines,
+// each taking a contiguous block of indexes, and returns once all calls have.
+// With a single worker, fn runs on the calling goroutine in index order. A panic
+// in fn is raised again on the calling goroutine once all goroutines are done.
+func parallelFor(n, workers int, fn func(i int)) {
+	if workers <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	block := (n + workers - 1) / workers
+	panics := make([]*goroutinePanic, (n+block-1)/block)
+	for start := 0; start < n; start += block {
+		end := min(start+block, n)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer recoverGoroutinePanic(&panics[start/block])
+			for i := start; i < end; i++ {
+				fn(i)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, p := range panics {
+		if p != nil {
+			panic(p)
+		}
+	}
+}
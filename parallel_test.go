@@ -0,0 +1,67 @@
+package codechunk
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParallelFor(t *testing.T) {
+	for _, workers := range []int{1, 3, 8} {
+		counts := make([]int, 100)
+		parallelFor(len(counts), workers, func(i int) { counts[i]++ })
+		for i, count := range counts {
+			if count != 1 {
+				t.Fatalf("workers=%d: index %d visited %d times", workers, i, count)
+			}
+		}
+	}
+}
+
+func TestWorkersFor(t *testing.T) {
+	tests := []struct {
+		chunks, parallelism, expected int
+	}{
+		{1000, 0, 1},
+		{1000, 1, 1},
+		{1000, 4, 4},
+		{40, 4, 2},
+		{10, 4, 1},
+	}
+	for _, tt := range tests {
+		if got := workersFor(tt.chunks, tt.parallelism); got != tt.expected {
+			t.Errorf("workersFor(%d, %d) = %d, want %d", tt.chunks, tt.parallelism, got, tt.expected)
+		}
+	}
+	if got := workersFor(1<<20, -1); got < 1 {
+		t.Errorf("workersFor with negative parallelism = %d, want GOMAXPROCS", got)
+	}
+}
+
+func TestChunkParallelism(t *testing.T) {
+	var code strings.Builder
+	code.WriteString("package gen\n\nimport \"fmt\"\n\n")
+	for i := 0; i < 300; i++ {
+		fmt.Fprintf(&code, "// Handler%d handles request %d\nfunc Handler%d(n int) string {\n\tif n > %d {\n\t\treturn fmt.Sprint(Handler%d(n - 1))\n\t}\n\treturn fmt.Sprintf(\"handler %%d\", n)\n}\n\n", i, i, i, i, max(i-1, 0))
+	}
+
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 200
+	sequential, err := Chunk("gen.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(sequential) < 4*minChunksPerWorker {
+		t.Fatalf("Expected at least %d chunks, got %d", 4*minChunksPerWorker, len(sequential))
+	}
+
+	opts.Parallelism = 4
+	parallel, err := Chunk("gen.go", code.String(), &opts)
+	if err != nil {
+		t.Fatalf("Chunk with Parallelism failed: %v", err)
+	}
+	if !reflect.DeepEqual(sequential, parallel) {
+		t.Error("Expected the same chunks with Parallelism as without")
+	}
+}
@@ -0,0 +1,479 @@
+// Package parquet writes chunks to Parquet files, for offline analytics and
+// Spark-based embedding pipelines that read columnar data rather than JSON lines.
+// Each chunk is a row of its text, ranges and context fields, flattened into
+// scalar columns. Rows are buffered a row group at a time, so memory stays
+// bounded however many chunks are written:
+//
+//	f, err := os.Create("chunks.parquet")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//
+//	w, err := parquet.NewWriter(f, parquet.Options{})
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for result := range codechunk.ChunkBatchStream(files, nil) {
+//	    if err := w.Write(result.Chunks...); err != nil {
+//	        log.Fatal(err)
+//	    }
+//	}
+//	if err := w.Close(); err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// The files are written without dependencies beyond the standard library, using
+// a subset of the format that every Parquet reader supports:
+//
+//   - a flat schema of required columns, with no nulls, lists or nested groups:
+//     lists such as the entities of a chunk are joined with commas and its scope
+//     with " > ", and missing values are empty strings
+//   - BYTE_ARRAY columns annotated as UTF8, and INT32, INT64 and BOOLEAN columns
+//   - PLAIN encoding only, with no dictionary pages
+//   - uncompressed version 1 data pages
+//   - no column statistics, page indexes, bloom filters or key-value metadata
+//
+// Files are therefore larger than compressed, dictionary-encoded ones; compress
+// them afterwards if size matters. Readers can't skip row groups or pages by
+// value ranges, only by column.
+package parquet
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+const (
+	// DefaultRowGroupSize is the number of rows per row group unless
+	// Options.RowGroupSize is set
+	DefaultRowGroupSize = 10000
+	// DefaultPageSize is the size in bytes of the values of a data page unless
+	// Options.PageSize is set
+	DefaultPageSize = 1 << 20
+)
+
+// magic opens and closes every Parquet file
+const magic = "PAR1"
+
+// ErrClosed is returned when writing to a closed Writer
+var ErrClosed = errors.New("parquet: writer closed")
+
+// Options configures a Writer
+type Options struct {
+	RowGroupSize int // Rows buffered before they are written as a row group (default: DefaultRowGroupSize)
+	PageSize     int // Bytes of values per data page within a row group (default: DefaultPageSize)
+}
+
+// Parquet physical types, converted types and enum values used by the writer
+const (
+	typeBoolean   = 0
+	typeInt32     = 1
+	typeInt64     = 2
+	typeByteArray = 6
+
+	convertedUTF8 = 0
+
+	repetitionRequired = 0
+
+	encodingPlain = 0
+	encodingRLE   = 3
+
+	pageTypeData = 0
+
+	codecUncompressed = 0
+)
+
+// column is a column of the chunk table. Exactly one of str, i32, i64 and bool
+// reads its value from a chunk.
+type column struct {
+	name string
+	str  func(c codechunk.CodeChunk) string
+	i32  func(c codechunk.CodeChunk) int32
+	i64  func(c codechunk.CodeChunk) int64
+	bool func(c codechunk.CodeChunk) bool
+}
+
+// physicalType returns the Parquet type the column's values are stored as
+func (c column) physicalType() int32 {
+	switch {
+	case c.str != nil:
+		return typeByteArray
+	case c.i32 != nil:
+		return typeInt32
+	case c.i64 != nil:
+		return typeInt64
+	default:
+		return typeBoolean
+	}
+}
+
+// columns are the columns of the chunk table, in file order
+var columns = []column{
+	{name: "id", str: func(c codechunk.CodeChunk) string { return c.ID }},
+	{name: "filepath", str: func(c codechunk.CodeChunk) string { return c.Context.Filepath }},
+	{name: "language", str: func(c codechunk.CodeChunk) string { return string(c.Context.Language) }},
+	{name: "chunk_index", i32: func(c codechunk.CodeChunk) int32 { return int32(c.Index) }},
+	{name: "total_chunks", i32: func(c codechunk.CodeChunk) int32 { return int32(c.TotalChunks) }},
+	{name: "level", i32: func(c codechunk.CodeChunk) int32 { return int32(c.Level) }},
+	{name: "parent_id", str: func(c codechunk.CodeChunk) string { return c.ParentChunkID }},
+	{name: "start_byte", i64: func(c codechunk.CodeChunk) int64 { return int64(c.ByteRange.Start) }},
+	{name: "end_byte", i64: func(c codechunk.CodeChunk) int64 { return int64(c.ByteRange.End) }},
+	{name: "start_line", i32: func(c codechunk.CodeChunk) int32 { return int32(c.LineRange.Start) }},
+	{name: "end_line", i32: func(c codechunk.CodeChunk) int32 { return int32(c.LineRange.End) }},
+	{name: "text", str: codechunk.CodeChunk.Content},
+	{name: "contextualized_text", str: codechunk.CodeChunk.Contextualized},
+	{name: "content_hash", str: func(c codechunk.CodeChunk) string { return c.ContentHash }},
+	{name: "kind", str: func(c codechunk.CodeChunk) string { return string(c.Kind) }},
+	{name: "classification", str: func(c codechunk.CodeChunk) string { return string(c.Context.Classification) }},
+	{name: "truncated", bool: func(c codechunk.CodeChunk) bool { return c.Truncated }},
+	{name: "scope", str: scope},
+	{name: "entities", str: entities},
+	{name: "qualified_names", str: func(c codechunk.CodeChunk) string { return strings.Join(c.QualifiedNames, ",") }},
+	{name: "imports", str: imports},
+	{name: "references", str: references},
+	{name: "tags", str: tags},
+	{name: "tested_symbols", str: func(c codechunk.CodeChunk) string { return strings.Join(c.Context.TestedSymbols, ",") }},
+	{name: "build", str: func(c codechunk.CodeChunk) string { return build(c).Expr }},
+	{name: "goos", str: func(c codechunk.CodeChunk) string { return build(c).GOOS }},
+	{name: "goarch", str: func(c codechunk.CodeChunk) string { return build(c).GOARCH }},
+	{name: "rel_path", str: func(c codechunk.CodeChunk) string { return provenance(c).RelPath }},
+	{name: "commit_sha", str: func(c codechunk.CodeChunk) string { return provenance(c).CommitSHA }},
+	{name: "branch", str: func(c codechunk.CodeChunk) string { return provenance(c).Branch }},
+}
+
+// scope joins the scope chain of a chunk from the outermost scope with " > "
+func scope(c codechunk.CodeChunk) string {
+	names := make([]string, len(c.Context.Scope))
+	for i, entity := range c.Context.Scope {
+		names[len(names)-1-i] = entity.Name
+	}
+	return strings.Join(names, " > ")
+}
+
+// entities joins the names of the entities in a chunk other than imports
+func entities(c codechunk.CodeChunk) string {
+	names := make([]string, 0, len(c.Context.Entities))
+	for _, entity := range c.Context.Entities {
+		if entity.Type != codechunk.EntityTypeImport {
+			names = append(names, entity.Name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// imports joins the distinct sources of the imports of a chunk, or their names
+// for imports without a source
+func imports(c codechunk.CodeChunk) string {
+	sources := make([]string, 0, len(c.Context.Imports))
+	seen := make(map[string]bool)
+	for _, imp := range c.Context.Imports {
+		source := imp.Source
+		if source == "" {
+			source = imp.Name
+		}
+		if !seen[source] {
+			seen[source] = true
+			sources = append(sources, source)
+		}
+	}
+	return strings.Join(sources, ",")
+}
+
+// references joins the names of the symbols called in a chunk
+func references(c codechunk.CodeChunk) string {
+	names := make([]string, len(c.Context.References))
+	for i, ref := range c.Context.References {
+		names[i] = ref.Name
+	}
+	return strings.Join(names, ",")
+}
+
+// tags joins the distinct comment tags of a chunk
+func tags(c codechunk.CodeChunk) string {
+	names := make([]string, 0, len(c.Context.Tags))
+	seen := make(map[string]bool)
+	for _, tag := range c.Context.Tags {
+		if !seen[tag.Tag] {
+			seen[tag.Tag] = true
+			names = append(names, tag.Tag)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+func build(c codechunk.CodeChunk) codechunk.BuildConstraints {
+	if c.Context.Build == nil {
+		return codechunk.BuildConstraints{}
+	}
+	return *c.Context.Build
+}
+
+func provenance(c codechunk.CodeChunk) codechunk.Provenance {
+	if c.Context.Provenance == nil {
+		return codechunk.Provenance{}
+	}
+	return *c.Context.Provenance
+}
+
+// columnBuffer holds the values of a column within the current row group: the
+// pages written so far and the values of the page being filled
+type columnBuffer struct {
+	pages      []byte // Page headers and values of the row group's finished pages
+	values     []byte // PLAIN-encoded values of the current page
+	bools      []bool // Values of the current page of a boolean column, bit-packed when it ends
+	pageValues int    // Number of values in the current page
+	numValues  int64  // Number of values in the row group's finished pages
+}
+
+// columnChunk locates a column of a written row group
+type columnChunk struct {
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+// rowGroup describes a written row group for the file footer
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+	size    int64
+}
+
+// Writer writes chunks to a Parquet file as rows of the columns listed in the
+// package documentation. Close must be called to write the file footer. A Writer
+// is not safe for concurrent use.
+type Writer struct {
+	w         *bufio.Writer
+	opts      Options
+	offset    int64
+	buffers   []columnBuffer
+	groupRows int
+	numRows   int64
+	rowGroups []rowGroup
+	closed    bool
+	err       error
+}
+
+// NewWriter starts a Parquet file on w. The file is complete once Close returns.
+func NewWriter(w io.Writer, opts Options) (*Writer, error) {
+	if opts.RowGroupSize <= 0 {
+		opts.RowGroupSize = DefaultRowGroupSize
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = DefaultPageSize
+	}
+	pw := &Writer{
+		w:       bufio.NewWriter(w),
+		opts:    opts,
+		buffers: make([]columnBuffer, len(columns)),
+	}
+	if err := pw.write([]byte(magic)); err != nil {
+		return nil, err
+	}
+	return pw, nil
+}
+
+// Write appends chunks as rows, writing a row group each time RowGroupSize rows
+// are buffered. Lazy chunks are rendered one at a time as their row is added.
+func (w *Writer) Write(chunks ...codechunk.CodeChunk) error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.err != nil {
+		return w.err
+	}
+	for _, chunk := range chunks {
+		for i, col := range columns {
+			w.appendValue(&w.buffers[i], col, chunk)
+		}
+		w.groupRows++
+		if w.groupRows >= w.opts.RowGroupSize {
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// appendValue encodes the column's value of a chunk into the current page,
+// finishing the page once it reaches PageSize
+func (w *Writer) appendValue(buf *columnBuffer, col column, chunk codechunk.CodeChunk) {
+	switch {
+	case col.str != nil:
+		value := col.str(chunk)
+		buf.values = binary.LittleEndian.AppendUint32(buf.values, uint32(len(value)))
+		buf.values = append(buf.values, value...)
+	case col.i32 != nil:
+		buf.values = binary.LittleEndian.AppendUint32(buf.values, uint32(col.i32(chunk)))
+	case col.i64 != nil:
+		buf.values = binary.LittleEndian.AppendUint64(buf.values, uint64(col.i64(chunk)))
+	default:
+		buf.bools = append(buf.bools, col.bool(chunk))
+	}
+	buf.pageValues++
+	if len(buf.values)+len(buf.bools)/8 >= w.opts.PageSize {
+		finishPage(buf)
+	}
+}
+
+// finishPage appends the current page of a column, with its header, to the
+// pages of the row group
+func finishPage(buf *columnBuffer) {
+	if buf.pageValues == 0 {
+		return
+	}
+	values := buf.values
+	if buf.bools != nil {
+		values = make([]byte, (len(buf.bools)+7)/8)
+		for i, v := range buf.bools {
+			if v {
+				values[i/8] |= 1 << (i % 8)
+			}
+		}
+	}
+
+	var header compactWriter
+	header.begin()
+	header.i32(1, pageTypeData)
+	header.i32(2, int32(len(values)))
+	header.i32(3, int32(len(values)))
+	header.structField(5)
+	header.i32(1, int32(buf.pageValues))
+	header.i32(2, encodingPlain)
+	header.i32(3, encodingRLE)
+	header.i32(4, encodingRLE)
+	header.end()
+	header.end()
+
+	buf.pages = append(buf.pages, header.buf...)
+	buf.pages = append(buf.pages, values...)
+	buf.numValues += int64(buf.pageValues)
+	buf.values = buf.values[:0]
+	buf.bools = buf.bools[:0]
+	buf.pageValues = 0
+}
+
+// Flush writes the buffered rows as a row group, if there are any. Rows are
+// flushed on their own every RowGroupSize rows and by Close.
+func (w *Writer) Flush() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if w.err != nil || w.groupRows == 0 {
+		return w.err
+	}
+
+	group := rowGroup{columns: make([]columnChunk, len(columns)), numRows: int64(w.groupRows)}
+	for i := range w.buffers {
+		buf := &w.buffers[i]
+		finishPage(buf)
+		group.columns[i] = columnChunk{offset: w.offset, size: int64(len(buf.pages)), numValues: buf.numValues}
+		group.size += int64(len(buf.pages))
+		if err := w.write(buf.pages); err != nil {
+			return err
+		}
+		buf.pages = buf.pages[:0]
+		buf.numValues = 0
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.numRows += int64(w.groupRows)
+	w.groupRows = 0
+	return nil
+}
+
+// Close writes the buffered rows and the file footer. It does not close the
+// underlying writer.
+func (w *Writer) Close() error {
+	if w.closed {
+		return ErrClosed
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	w.closed = true
+
+	footer := w.footer()
+	footer = binary.LittleEndian.AppendUint32(footer, uint32(len(footer)))
+	footer = append(footer, magic...)
+	if err := w.write(footer); err != nil {
+		return err
+	}
+	if err := w.w.Flush(); err != nil {
+		w.err = err
+	}
+	return w.err
+}
+
+// footer encodes the FileMetaData of the file: its schema and row groups
+func (w *Writer) footer() []byte {
+	var meta compactWriter
+	meta.begin()
+	meta.i32(1, 1)
+
+	meta.list(2, thriftStruct, len(columns)+1)
+	meta.begin()
+	meta.binary(4, "schema")
+	meta.i32(5, int32(len(columns)))
+	meta.end()
+	for _, col := range columns {
+		meta.begin()
+		meta.i32(1, col.physicalType())
+		meta.i32(3, repetitionRequired)
+		meta.binary(4, col.name)
+		if col.str != nil {
+			meta.i32(6, convertedUTF8)
+		}
+		meta.end()
+	}
+
+	meta.i64(3, w.numRows)
+	meta.list(4, thriftStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		meta.begin()
+		meta.list(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			meta.begin()
+			meta.i64(2, chunk.offset)
+			meta.structField(3)
+			meta.i32(1, columns[i].physicalType())
+			meta.list(2, thriftI32, 2)
+			meta.listI32(encodingPlain)
+			meta.listI32(encodingRLE)
+			meta.list(3, thriftBinary, 1)
+			meta.listBinary(columns[i].name)
+			meta.i32(4, codecUncompressed)
+			meta.i64(5, chunk.numValues)
+			meta.i64(6, chunk.size)
+			meta.i64(7, chunk.size)
+			meta.i64(9, chunk.offset)
+			meta.end()
+			meta.end()
+		}
+		meta.i64(2, group.size)
+		meta.i64(3, group.numRows)
+		meta.end()
+	}
+	meta.binary(6, "tree-code-chunker")
+	meta.end()
+	return meta.buf
+}
+
+// write writes p to the file, recording the first error
+func (w *Writer) write(p []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	n, err := w.w.Write(p)
+	w.offset += int64(n)
+	if err != nil {
+		w.err = err
+	}
+	return err
+}
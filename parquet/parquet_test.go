@@ -0,0 +1,655 @@
+package parquet
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+const parquetSource = `package main
+
+import "fmt"
+
+// Greet prints a greeting.
+func Greet(name string) {
+	fmt.Println("Hello, " + name)
+}
+
+// TODO: localize
+func Farewell(name string) {
+	fmt.Println("Bye, " + name)
+}
+
+func main() {
+	Greet("world")
+	Farewell("world")
+}
+`
+
+// The reader below decodes files from the Parquet format specification
+// (parquet.thrift) alone, sharing no code or constants with the writer, so that
+// a writer bug can't be mirrored by the test.
+
+// Thrift compact protocol type ids
+const (
+	ctBooleanTrue  = 1
+	ctBooleanFalse = 2
+	ctByte         = 3
+	ctI16          = 4
+	ctI32          = 5
+	ctI64          = 6
+	ctDouble       = 7
+	ctBinary       = 8
+	ctList         = 9
+	ctSet          = 10
+	ctMap          = 11
+	ctStruct       = 12
+)
+
+// Parquet enum values
+const (
+	pqBoolean   = 0
+	pqInt32     = 1
+	pqInt64     = 2
+	pqByteArray = 6
+
+	pqRequired = 0
+	pqUTF8     = 0
+
+	pqPlain = 0
+
+	pqDataPage = 0
+
+	pqUncompressed = 0
+	pqSnappy       = 1
+)
+
+// tstruct maps field ids to values: bool, int64 for all integers, float64,
+// string for binaries, []any for lists and sets, map[any]any and tstruct
+type tstruct map[int16]any
+
+// thriftDecoder decodes the Thrift compact protocol, panicking on malformed input
+type thriftDecoder struct {
+	buf []byte
+	pos int
+}
+
+func (d *thriftDecoder) byte() byte {
+	if d.pos >= len(d.buf) {
+		panic("thrift: unexpected end of input")
+	}
+	d.pos++
+	return d.buf[d.pos-1]
+}
+
+func (d *thriftDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.buf[d.pos:])
+	if n <= 0 {
+		panic("thrift: malformed varint")
+	}
+	d.pos += n
+	return v
+}
+
+func (d *thriftDecoder) zigzag() int64 {
+	u := d.uvarint()
+	return int64(u>>1) ^ -int64(u&1)
+}
+
+func (d *thriftDecoder) bytes(n int) []byte {
+	if n < 0 || d.pos+n > len(d.buf) {
+		panic("thrift: value past the end of input")
+	}
+	d.pos += n
+	return d.buf[d.pos-n : d.pos]
+}
+
+func (d *thriftDecoder) readStruct() tstruct {
+	fields := make(tstruct)
+	var last int16
+	for {
+		header := d.byte()
+		if header == 0 {
+			return fields
+		}
+		typ := header & 0x0f
+		id := last + int16(header>>4)
+		if header>>4 == 0 {
+			id = int16(d.zigzag())
+		}
+		last = id
+		switch typ {
+		case ctBooleanTrue:
+			fields[id] = true
+		case ctBooleanFalse:
+			fields[id] = false
+		default:
+			fields[id] = d.value(typ)
+		}
+	}
+}
+
+func (d *thriftDecoder) value(typ byte) any {
+	switch typ {
+	case ctBooleanTrue, ctBooleanFalse:
+		// Booleans in lists, sets and maps take a byte of their own
+		return d.byte() == ctBooleanTrue
+	case ctByte:
+		return int64(int8(d.byte()))
+	case ctI16, ctI32, ctI64:
+		return d.zigzag()
+	case ctDouble:
+		return math.Float64frombits(binary.LittleEndian.Uint64(d.bytes(8)))
+	case ctBinary:
+		return string(d.bytes(int(d.uvarint())))
+	case ctList, ctSet:
+		header := d.byte()
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(d.uvarint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = d.value(header & 0x0f)
+		}
+		return list
+	case ctMap:
+		size := int(d.uvarint())
+		m := make(map[any]any, size)
+		if size > 0 {
+			types := d.byte()
+			for i := 0; i < size; i++ {
+				m[d.value(types>>4)] = d.value(types & 0x0f)
+			}
+		}
+		return m
+	case ctStruct:
+		return d.readStruct()
+	}
+	panic(fmt.Sprintf("thrift: unknown type %d", typ))
+}
+
+// fieldReader reads the fields of decoded Parquet structs, failing the test when
+// a field required by the specification is missing or has the wrong type
+type fieldReader struct {
+	t *testing.T
+}
+
+func (r fieldReader) get(s tstruct, name string, id int16) any {
+	r.t.Helper()
+	v, ok := s[id]
+	if !ok {
+		r.t.Fatalf("Required field %s (%d) is missing", name, id)
+	}
+	return v
+}
+
+func (r fieldReader) int(s tstruct, name string, id int16) int64 {
+	r.t.Helper()
+	v, ok := r.get(s, name, id).(int64)
+	if !ok {
+		r.t.Fatalf("Field %s (%d) is not an integer", name, id)
+	}
+	return v
+}
+
+func (r fieldReader) optionalInt(s tstruct, id int16) (int64, bool) {
+	v, ok := s[id].(int64)
+	return v, ok
+}
+
+func (r fieldReader) string(s tstruct, name string, id int16) string {
+	r.t.Helper()
+	v, ok := r.get(s, name, id).(string)
+	if !ok {
+		r.t.Fatalf("Field %s (%d) is not a binary", name, id)
+	}
+	return v
+}
+
+func (r fieldReader) list(s tstruct, name string, id int16) []any {
+	r.t.Helper()
+	v, ok := r.get(s, name, id).([]any)
+	if !ok {
+		r.t.Fatalf("Field %s (%d) is not a list", name, id)
+	}
+	return v
+}
+
+func (r fieldReader) structs(s tstruct, name string, id int16) []tstruct {
+	r.t.Helper()
+	var structs []tstruct
+	for _, v := range r.list(s, name, id) {
+		elem, ok := v.(tstruct)
+		if !ok {
+			r.t.Fatalf("Field %s (%d) is not a list of structs", name, id)
+		}
+		structs = append(structs, elem)
+	}
+	return structs
+}
+
+func (r fieldReader) structField(s tstruct, name string, id int16) tstruct {
+	r.t.Helper()
+	v, ok := r.get(s, name, id).(tstruct)
+	if !ok {
+		r.t.Fatalf("Field %s (%d) is not a struct", name, id)
+	}
+	return v
+}
+
+// schemaColumn is a leaf of a Parquet schema
+type schemaColumn struct {
+	name       string
+	typ        int64
+	repetition int64
+	converted  int64 // -1 if unset
+}
+
+// columnMeta is the ColumnMetaData of a column chunk
+type columnMeta struct {
+	typ            int64
+	encodings      []int64
+	path           []string
+	codec          int64
+	numValues      int64
+	uncompressed   int64
+	compressed     int64
+	dataPageOffset int64
+}
+
+// parquetFile is the decoded footer of a Parquet file with a flat schema
+type parquetFile struct {
+	data      []byte
+	version   int64
+	columns   []schemaColumn
+	numRows   int64
+	rowGroups [][]columnMeta
+	rowCounts []int64
+	createdBy string
+}
+
+// readParquet checks the framing of a Parquet file and decodes its FileMetaData
+func readParquet(t *testing.T, data []byte) *parquetFile {
+	t.Helper()
+	const parquetMagic = "PAR1"
+	if len(data) < 12 || string(data[:4]) != parquetMagic || string(data[len(data)-4:]) != parquetMagic {
+		t.Fatalf("File is not framed by %s", parquetMagic)
+	}
+	size := int(binary.LittleEndian.Uint32(data[len(data)-8:]))
+	if size > len(data)-12 {
+		t.Fatalf("Footer length %d exceeds the file", size)
+	}
+	d := &thriftDecoder{buf: data[len(data)-8-size : len(data)-8]}
+	meta := decodeStruct(t, d)
+	if d.pos != size {
+		t.Fatalf("Footer decoded %d of %d bytes", d.pos, size)
+	}
+
+	r := fieldReader{t}
+	file := &parquetFile{
+		data:    data,
+		version: r.int(meta, "FileMetaData.version", 1),
+		numRows: r.int(meta, "FileMetaData.num_rows", 3),
+	}
+	file.createdBy, _ = meta[6].(string)
+
+	schema := r.structs(meta, "FileMetaData.schema", 2)
+	if len(schema) == 0 {
+		t.Fatal("Schema has no root element")
+	}
+	if children := r.int(schema[0], "SchemaElement.num_children", 5); int(children) != len(schema)-1 {
+		t.Fatalf("Root schema element has %d children, expected the %d leaves of a flat schema", children, len(schema)-1)
+	}
+	for _, elem := range schema[1:] {
+		col := schemaColumn{
+			name:       r.string(elem, "SchemaElement.name", 4),
+			typ:        r.int(elem, "SchemaElement.type", 1),
+			repetition: r.int(elem, "SchemaElement.repetition_type", 3),
+			converted:  -1,
+		}
+		if converted, ok := r.optionalInt(elem, 6); ok {
+			col.converted = converted
+		}
+		file.columns = append(file.columns, col)
+	}
+
+	var rows int64
+	for _, group := range r.structs(meta, "FileMetaData.row_groups", 4) {
+		r.int(group, "RowGroup.total_byte_size", 2)
+		groupRows := r.int(group, "RowGroup.num_rows", 3)
+		chunks := r.structs(group, "RowGroup.columns", 1)
+		if len(chunks) != len(file.columns) {
+			t.Fatalf("Row group has %d column chunks for %d columns", len(chunks), len(file.columns))
+		}
+		var metas []columnMeta
+		for i, chunk := range chunks {
+			r.int(chunk, "ColumnChunk.file_offset", 2)
+			cm := r.structField(chunk, "ColumnChunk.meta_data", 3)
+			m := columnMeta{
+				typ:            r.int(cm, "ColumnMetaData.type", 1),
+				codec:          r.int(cm, "ColumnMetaData.codec", 4),
+				numValues:      r.int(cm, "ColumnMetaData.num_values", 5),
+				uncompressed:   r.int(cm, "ColumnMetaData.total_uncompressed_size", 6),
+				compressed:     r.int(cm, "ColumnMetaData.total_compressed_size", 7),
+				dataPageOffset: r.int(cm, "ColumnMetaData.data_page_offset", 9),
+			}
+			for _, v := range r.list(cm, "ColumnMetaData.encodings", 2) {
+				m.encodings = append(m.encodings, v.(int64))
+			}
+			for _, v := range r.list(cm, "ColumnMetaData.path_in_schema", 3) {
+				m.path = append(m.path, v.(string))
+			}
+			if m.typ != file.columns[i].typ || len(m.path) != 1 || m.path[0] != file.columns[i].name {
+				t.Fatalf("Column chunk %d (%v, type %d) doesn't match schema column %+v", i, m.path, m.typ, file.columns[i])
+			}
+			if m.numValues != groupRows {
+				t.Fatalf("Column chunk %v has %d values in a row group of %d rows", m.path, m.numValues, groupRows)
+			}
+			metas = append(metas, m)
+		}
+		file.rowGroups = append(file.rowGroups, metas)
+		file.rowCounts = append(file.rowCounts, groupRows)
+		rows += groupRows
+	}
+	if rows != file.numRows {
+		t.Fatalf("Row groups hold %d rows, footer says %d", rows, file.numRows)
+	}
+	return file
+}
+
+// decodeStruct decodes a Thrift struct, failing the test on malformed input
+func decodeStruct(t *testing.T, d *thriftDecoder) (s tstruct) {
+	t.Helper()
+	defer func() {
+		if err := recover(); err != nil {
+			t.Fatalf("Decoding at offset %d: %v", d.pos, err)
+		}
+	}()
+	return d.readStruct()
+}
+
+// column returns the values of the named column across all row groups, decoding
+// the uncompressed PLAIN data pages of a required column
+func (f *parquetFile) column(t *testing.T, name string) []any {
+	t.Helper()
+	index := -1
+	for i, col := range f.columns {
+		if col.name == name {
+			index = i
+		}
+	}
+	if index < 0 {
+		t.Fatalf("No column %s", name)
+	}
+	if f.columns[index].repetition != pqRequired {
+		t.Fatalf("Column %s is not required", name)
+	}
+
+	r := fieldReader{t}
+	var values []any
+	for _, group := range f.rowGroups {
+		m := group[index]
+		if m.codec != pqUncompressed {
+			t.Fatalf("Column %s has codec %d, expected uncompressed", name, m.codec)
+		}
+		d := &thriftDecoder{buf: f.data, pos: int(m.dataPageOffset)}
+		for read := int64(0); read < m.numValues; {
+			header := decodeStruct(t, d)
+			if typ := r.int(header, "PageHeader.type", 1); typ != pqDataPage {
+				t.Fatalf("Column %s has a page of type %d, expected a data page", name, typ)
+			}
+			size := r.int(header, "PageHeader.compressed_page_size", 3)
+			if uncompressed := r.int(header, "PageHeader.uncompressed_page_size", 2); uncompressed != size {
+				t.Fatalf("Uncompressed page of %d bytes has a compressed size of %d", uncompressed, size)
+			}
+			page := r.structField(header, "PageHeader.data_page_header", 5)
+			count := r.int(page, "DataPageHeader.num_values", 1)
+			if encoding := r.int(page, "DataPageHeader.encoding", 2); encoding != pqPlain {
+				t.Fatalf("Column %s has a page encoded with %d, expected PLAIN", name, encoding)
+			}
+			r.int(page, "DataPageHeader.definition_level_encoding", 3)
+			r.int(page, "DataPageHeader.repetition_level_encoding", 4)
+
+			// A required column of a flat schema has no repetition or definition
+			// levels, so the page holds only its values
+			data := d.bytes(int(size))
+			pos := 0
+			for i := 0; i < int(count); i++ {
+				switch m.typ {
+				case pqBoolean:
+					values = append(values, data[i/8]&(1<<(i%8)) != 0)
+				case pqInt32:
+					values = append(values, int32(binary.LittleEndian.Uint32(data[pos:])))
+					pos += 4
+				case pqInt64:
+					values = append(values, int64(binary.LittleEndian.Uint64(data[pos:])))
+					pos += 8
+				case pqByteArray:
+					n := int(binary.LittleEndian.Uint32(data[pos:]))
+					values = append(values, string(data[pos+4:pos+4+n]))
+					pos += 4 + n
+				default:
+					t.Fatalf("Column %s has unsupported type %d", name, m.typ)
+				}
+			}
+			if m.typ == pqBoolean {
+				pos = (int(count) + 7) / 8
+			}
+			if pos != len(data) {
+				t.Fatalf("Page of column %s has %d bytes after its %d values", name, len(data)-pos, count)
+			}
+			read += count
+		}
+		if chunkSize := int64(d.pos) - m.dataPageOffset; chunkSize != m.compressed || chunkSize != m.uncompressed {
+			t.Fatalf("Column chunk %s spans %d bytes, metadata says %d compressed and %d uncompressed", name, chunkSize, m.compressed, m.uncompressed)
+		}
+	}
+	return values
+}
+
+// chunkColumns are the columns of the chunk table in file order, as documented
+var chunkColumns = []struct {
+	name string
+	typ  int64
+}{
+	{"id", pqByteArray}, {"filepath", pqByteArray}, {"language", pqByteArray},
+	{"chunk_index", pqInt32}, {"total_chunks", pqInt32}, {"level", pqInt32},
+	{"parent_id", pqByteArray}, {"start_byte", pqInt64}, {"end_byte", pqInt64},
+	{"start_line", pqInt32}, {"end_line", pqInt32}, {"text", pqByteArray},
+	{"contextualized_text", pqByteArray}, {"content_hash", pqByteArray},
+	{"kind", pqByteArray}, {"classification", pqByteArray}, {"truncated", pqBoolean},
+	{"scope", pqByteArray}, {"entities", pqByteArray}, {"qualified_names", pqByteArray},
+	{"imports", pqByteArray}, {"references", pqByteArray}, {"tags", pqByteArray},
+	{"tested_symbols", pqByteArray}, {"build", pqByteArray}, {"goos", pqByteArray},
+	{"goarch", pqByteArray}, {"rel_path", pqByteArray}, {"commit_sha", pqByteArray},
+	{"branch", pqByteArray},
+}
+
+func TestWriter(t *testing.T) {
+	opts := codechunk.DefaultChunkOptions()
+	opts.MaxChunkSize = 80
+	chunks, err := codechunk.Chunk("main.go", parquetSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 3 {
+		t.Fatalf("Expected at least 3 chunks, got %d", len(chunks))
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{RowGroupSize: 2, PageSize: 64})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	for _, chunk := range chunks {
+		if err := w.Write(chunk); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	file := readParquet(t, buf.Bytes())
+	if file.version != 1 {
+		t.Errorf("Expected format version 1, got %d", file.version)
+	}
+	if file.numRows != int64(len(chunks)) {
+		t.Errorf("Expected %d rows, got %d", len(chunks), file.numRows)
+	}
+	if got, want := len(file.rowGroups), (len(chunks)+1)/2; got != want {
+		t.Errorf("Expected %d row groups, got %d", want, got)
+	}
+	if len(file.columns) != len(chunkColumns) {
+		t.Fatalf("Expected %d columns, got %d", len(chunkColumns), len(file.columns))
+	}
+	for i, want := range chunkColumns {
+		col := file.columns[i]
+		if col.name != want.name || col.typ != want.typ || col.repetition != pqRequired {
+			t.Errorf("Column %d: expected required %s of type %d, got %+v", i, want.name, want.typ, col)
+		}
+		if (col.typ == pqByteArray) != (col.converted == pqUTF8) {
+			t.Errorf("Column %s: expected UTF8 annotations on byte array columns only, got %d", col.name, col.converted)
+		}
+	}
+	for _, group := range file.rowGroups {
+		for _, m := range group {
+			if !reflect.DeepEqual(m.encodings, []int64{pqPlain, 3}) {
+				t.Errorf("Column %v: expected PLAIN and RLE encodings, got %v", m.path, m.encodings)
+			}
+		}
+	}
+
+	var texts, filepaths, entities, startLines, endBytes, truncated []any
+	for _, chunk := range chunks {
+		texts = append(texts, chunk.Text)
+		filepaths = append(filepaths, "main.go")
+		startLines = append(startLines, int32(chunk.LineRange.Start))
+		endBytes = append(endBytes, int64(chunk.ByteRange.End))
+		truncated = append(truncated, false)
+		var names []string
+		for _, entity := range chunk.Context.Entities {
+			if entity.Type != codechunk.EntityTypeImport {
+				names = append(names, entity.Name)
+			}
+		}
+		entities = append(entities, strings.Join(names, ","))
+	}
+	for name, want := range map[string][]any{
+		"text":       texts,
+		"filepath":   filepaths,
+		"entities":   entities,
+		"start_line": startLines,
+		"end_byte":   endBytes,
+		"truncated":  truncated,
+	} {
+		if got := file.column(t, name); !reflect.DeepEqual(got, want) {
+			t.Errorf("Column %s: expected %v, got %v", name, want, got)
+		}
+	}
+}
+
+func TestWriterLazyText(t *testing.T) {
+	opts := codechunk.DefaultChunkOptions()
+	opts.LazyText = true
+	chunks, err := codechunk.Chunk("main.go", parquetSource, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Write(chunks...); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	got := readParquet(t, buf.Bytes()).column(t, "contextualized_text")
+	for i, chunk := range chunks {
+		if got[i] != chunk.Contextualized() || got[i] == "" {
+			t.Errorf("Chunk %d: expected the rendered contextualized text, got %q", i, got[i])
+		}
+	}
+}
+
+func TestWriterEmptyAndClosed(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, Options{})
+	if err != nil {
+		t.Fatalf("NewWriter failed: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	file := readParquet(t, buf.Bytes())
+	if file.numRows != 0 || len(file.rowGroups) != 0 || len(file.columns) != len(chunkColumns) {
+		t.Errorf("Expected the schema with no rows or row groups, got %+v", file)
+	}
+
+	if err := w.Write(codechunk.CodeChunk{}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed writing to a closed writer, got %v", err)
+	}
+	if err := w.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Expected ErrClosed closing twice, got %v", err)
+	}
+}
+
+// TestReaderThirdPartyFile checks the reader against a file written by another
+// implementation, xitongsys/parquet-go (testdata/flat.parquet.snappy, from the
+// examples of github.com/xitongsys/parquet-go-source, Apache License 2.0). Its
+// pages are snappy-compressed, so only its footer and page headers are read.
+func TestReaderThirdPartyFile(t *testing.T) {
+	data, err := os.ReadFile("testdata/flat.parquet.snappy")
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	file := readParquet(t, data)
+	if file.numRows != 10 || len(file.rowGroups) != 1 {
+		t.Fatalf("Expected 10 rows in one row group, got %d in %d", file.numRows, len(file.rowGroups))
+	}
+	want := []schemaColumn{
+		{name: "name", typ: pqByteArray, converted: pqUTF8},
+		{name: "age", typ: pqInt32, converted: -1},
+		{name: "id", typ: pqInt64, converted: -1},
+		{name: "weight", typ: 4, converted: -1}, // FLOAT
+		{name: "sex", typ: pqBoolean, converted: -1},
+		{name: "day", typ: pqInt32, converted: 6}, // DATE
+	}
+	if !reflect.DeepEqual(file.columns, want) {
+		t.Errorf("Expected columns %+v, got %+v", want, file.columns)
+	}
+
+	r := fieldReader{t}
+	for i, m := range file.rowGroups[0] {
+		if m.codec != pqSnappy {
+			t.Errorf("Column %v: expected snappy, got codec %d", m.path, m.codec)
+		}
+		d := &thriftDecoder{buf: data, pos: int(m.dataPageOffset)}
+		var values int64
+		for values < m.numValues {
+			header := decodeStruct(t, d)
+			if typ := r.int(header, "PageHeader.type", 1); typ != pqDataPage {
+				t.Fatalf("Column %s: expected data pages from the data page offset, got type %d", want[i].name, typ)
+			}
+			d.bytes(int(r.int(header, "PageHeader.compressed_page_size", 3)))
+			values += r.int(r.structField(header, "PageHeader.data_page_header", 5), "DataPageHeader.num_values", 1)
+		}
+		if values != 10 {
+			t.Errorf("Column %s: expected pages of 10 values, got %d", want[i].name, values)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package parquet
+
+import "encoding/binary"
+
+// Thrift compact protocol types of the fields written to page headers and the
+// file footer
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// compactWriter encodes Thrift structs with the compact protocol, the encoding
+// of Parquet page headers and file metadata. Fields must be written in
+// increasing id order within each struct.
+type compactWriter struct {
+	buf    []byte
+	lastID int16   // Id of the last field written in the current struct
+	ids    []int16 // lastID of the enclosing structs
+}
+
+func (w *compactWriter) varint(v uint64) {
+	w.buf = binary.AppendUvarint(w.buf, v)
+}
+
+func (w *compactWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+// field writes a field header, with the id as a delta from the previous field's
+// when it fits in four bits
+func (w *compactWriter) field(id int16, typ byte) {
+	if delta := id - w.lastID; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|typ)
+	} else {
+		w.buf = append(w.buf, typ)
+		w.zigzag(int64(id))
+	}
+	w.lastID = id
+}
+
+func (w *compactWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *compactWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *compactWriter) binary(id int16, v string) {
+	w.field(id, thriftBinary)
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// list writes a list field header; the size elements of type elem follow
+func (w *compactWriter) list(id int16, elem byte, size int) {
+	w.field(id, thriftList)
+	w.listHeader(elem, size)
+}
+
+func (w *compactWriter) listHeader(elem byte, size int) {
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elem)
+		return
+	}
+	w.buf = append(w.buf, 0xf0|elem)
+	w.varint(uint64(size))
+}
+
+// listI32 writes a list element of a list of i32
+func (w *compactWriter) listI32(v int32) {
+	w.zigzag(int64(v))
+}
+
+// listBinary writes a list element of a list of binary
+func (w *compactWriter) listBinary(v string) {
+	w.varint(uint64(len(v)))
+	w.buf = append(w.buf, v...)
+}
+
+// structField starts a struct field; end finishes it
+func (w *compactWriter) structField(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
+
+// begin starts a struct, either a list element or the top-level struct
+func (w *compactWriter) begin() {
+	w.ids = append(w.ids, w.lastID)
+	w.lastID = 0
+}
+
+// end writes the stop field of the current struct
+func (w *compactWriter) end() {
+	w.buf = append(w.buf, 0)
+	w.lastID = w.ids[len(w.ids)-1]
+	w.ids = w.ids[:len(w.ids)-1]
+}
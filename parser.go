@@ -3,6 +3,7 @@ package codechunk
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sync"
 
 	sitter "github.com/smacker/go-tree-sitter"
@@ -13,23 +14,49 @@ var (
 	ErrUnsupportedLanguage = errors.New("unsupported language")
 	// ErrParseFailed is returned when parsing fails
 	ErrParseFailed = errors.New("parse failed")
+	// ErrCancelled is reported for batch files left unprocessed because the context was done
+	ErrCancelled = errors.New("cancelled")
+	// ErrUnchanged is reported for batch files skipped because BatchOptions.ResumeFrom
+	// has already checkpointed their current content
+	ErrUnchanged = errors.New("unchanged since last checkpoint")
+	// ErrSkipped is reported for batch files skipped by BatchOptions.SkipPolicy
+	ErrSkipped = errors.New("skipped by policy")
+	// ErrAnalysisClosed is returned by FileAnalysis.Query after the analysis is closed
+	ErrAnalysisClosed = errors.New("analysis closed")
+	// ErrPoolNotRunning is returned by Chunker.Submit when no worker pool is running
+	ErrPoolNotRunning = errors.New("worker pool not running")
+	// ErrPanicked is reported for batch files whose chunking panicked (see PanicError)
+	ErrPanicked = errors.New("panicked")
+	// ErrLimitExceeded is returned for files over ChunkOptions.MaxFileSize or
+	// MaxChunksPerFile unless TruncateOnLimit is set
+	ErrLimitExceeded = errors.New("limit exceeded")
 )
 
-// parserPool manages a pool of tree-sitter parsers
-var parserPool = sync.Pool{
-	New: func() interface{} {
-		return sitter.NewParser()
-	},
-}
-
-// getParser gets a parser from the pool
-func getParser() *sitter.Parser {
-	return parserPool.Get().(*sitter.Parser)
+// parserPools holds one pool of tree-sitter parsers per language, so pooled
+// parsers keep their grammar and SetLanguage runs only when a parser is created
+var parserPools sync.Map // Language -> *sync.Pool
+
+// getParser gets a parser with the language's grammar already set from the
+// language's pool
+func getParser(lang Language, grammar *sitter.Language) *sitter.Parser {
+	pool, ok := parserPools.Load(lang)
+	if !ok {
+		pool, _ = parserPools.LoadOrStore(lang, &sync.Pool{
+			New: func() interface{} {
+				parser := sitter.NewParser()
+				parser.SetLanguage(grammar)
+				return parser
+			},
+		})
+	}
+	return pool.(*sync.Pool).Get().(*sitter.Parser)
 }
 
-// putParser returns a parser to the pool
-func putParser(p *sitter.Parser) {
-	parserPool.Put(p)
+// putParser returns a parser to its language's pool
+func putParser(lang Language, p *sitter.Parser) {
+	if pool, ok := parserPools.Load(lang); ok {
+		pool.(*sync.Pool).Put(p)
+	}
 }
 
 // parse parses source code and returns the AST
@@ -44,12 +71,57 @@ func parseWithContext(ctx context.Context, code []byte, lang Language) (*ParseRe
 		return nil, ErrUnsupportedLanguage
 	}
 
-	parser := getParser()
-	defer putParser(parser)
+	parser := getParser(lang, grammar)
+	defer putParser(lang, parser)
+
+	return parseWithParser(ctx, parser, code)
+}
+
+// languageParser is a parser owned by a single goroutine that remembers its
+// language, so SetLanguage is only called when the language changes
+type languageParser struct {
+	parser *sitter.Parser
+	lang   Language
+}
+
+// newLanguageParser creates a languageParser with no language set
+func newLanguageParser() *languageParser {
+	return &languageParser{parser: sitter.NewParser()}
+}
 
-	parser.SetLanguage(grammar)
+// parse parses source code, switching the parser's language if needed
+func (p *languageParser) parse(ctx context.Context, code []byte, lang Language) (*ParseResult, error) {
+	if lang != p.lang {
+		grammar := getLanguageGrammar(lang)
+		if grammar == nil {
+			return nil, ErrUnsupportedLanguage
+		}
+		p.parser.SetLanguage(grammar)
+		p.lang = lang
+	}
+	return parseWithParser(ctx, p.parser, code)
+}
 
-	tree, err := parser.ParseCtx(ctx, nil, code)
+// inputParseThreshold is the source size from which parsing hands the source to
+// tree-sitter piece by piece rather than copying it whole into C memory
+var inputParseThreshold = 1 << 20
+
+// inputParsePiece is the size of the pieces of large sources read by tree-sitter
+const inputParsePiece = 64 << 10
+
+// parseWithParser parses source code with a parser whose language is already set.
+// Sources under a cancellable context also go through the read callback: ParseCtx
+// watches the context from a goroutine that can still raise the parser's
+// cancellation flag after parsing completes, failing the next parse of a pooled
+// parser, and a parse it does cancel is resumed by that next parse.
+func parseWithParser(ctx context.Context, parser *sitter.Parser, code []byte) (*ParseResult, error) {
+	var tree *sitter.Tree
+	var err error
+	if len(code) >= inputParseThreshold || ctx.Done() != nil {
+		tree, err = parseInput(ctx, parser, code)
+	} else {
+		tree, err = parser.ParseCtx(ctx, nil, code)
+	}
 	if err != nil {
 		return nil, errors.Join(ErrParseFailed, err)
 	}
@@ -60,20 +132,123 @@ func parseWithContext(ctx context.Context, code []byte, lang Language) (*ParseRe
 
 	// Check for parse errors
 	if tree.RootNode().HasError() {
+		ranges := errorRanges(tree.RootNode())
+		result.ErrorNodeCount = len(ranges)
 		result.Error = &ParseError{
 			Message:     "parse error in source code",
 			Recoverable: true, // tree-sitter recovers from errors
+			Ranges:      ranges,
+			Count:       len(ranges),
 		}
 	}
 
 	return result, nil
 }
 
+// parseInput parses code through tree-sitter's read callback, which copies only
+// the piece being read. Tree-sitter cannot be cancelled while reading from a
+// callback, so once ctx is done the callback reports the end of the input and the
+// truncated tree is discarded.
+func parseInput(ctx context.Context, parser *sitter.Parser, code []byte) (*sitter.Tree, error) {
+	tree, err := parser.ParseInputCtx(ctx, nil, sitter.Input{
+		Encoding: sitter.InputEncodingUTF8,
+		Read: func(offset uint32, _ sitter.Point) []byte {
+			if int(offset) >= len(code) || ctx.Err() != nil {
+				return nil
+			}
+			return code[offset:min(int(offset)+inputParsePiece, len(code))]
+		},
+	})
+	if err == nil && ctx.Err() != nil {
+		tree.Close()
+		return nil, ctx.Err()
+	}
+	return tree, err
+}
+
 // parseString is a convenience wrapper for parsing string code
 func parseString(code string, lang Language) (*ParseResult, error) {
 	return parse([]byte(code), lang)
 }
 
+// errorRanges returns the byte ranges of the ERROR and MISSING nodes under root in
+// source order, skipping subtrees without errors. Nodes inside an ERROR node are
+// part of its range and not listed separately.
+func errorRanges(root *sitter.Node) []ByteRange {
+	var ranges []ByteRange
+	stack := []*sitter.Node{root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		if node.IsError() || node.IsMissing() {
+			ranges = append(ranges, ByteRange{Start: int(node.StartByte()), End: int(node.EndByte())})
+			continue
+		}
+		if !node.HasError() {
+			continue
+		}
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, node.Child(i))
+		}
+	}
+	return ranges
+}
+
+// chunkParseError returns the parse error of a chunk covering byteRange: the file's
+// error narrowed to the broken regions overlapping the chunk, or nil if there are
+// none. An empty MISSING range counts at either edge of the chunk.
+func chunkParseError(fileErr *ParseError, byteRange ByteRange) *ParseError {
+	if fileErr == nil {
+		return nil
+	}
+	var ranges []ByteRange
+	for _, r := range fileErr.Ranges {
+		if r.Start < byteRange.End && r.End > byteRange.Start || r.Start == r.End && r.Start >= byteRange.Start && r.Start <= byteRange.End {
+			ranges = append(ranges, r)
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+	return &ParseError{
+		Message:     fileErr.Message,
+		Recoverable: fileErr.Recoverable,
+		Ranges:      ranges,
+		Count:       len(ranges),
+	}
+}
+
+// parseErrorRatio returns the fraction of a source of size bytes covered by the
+// ERROR nodes of its parse error
+func parseErrorRatio(parseErr *ParseError, size int) float64 {
+	if parseErr == nil || size == 0 {
+		return 0
+	}
+	covered := 0
+	for _, r := range parseErr.Ranges {
+		covered += r.End - r.Start
+	}
+	return float64(covered) / float64(size)
+}
+
+// tooManyParseErrors reports whether ERROR nodes cover more of a source of size
+// bytes than opts.MaxParseErrorRatio allows
+func tooManyParseErrors(parseErr *ParseError, size int, opts ChunkOptions) bool {
+	return opts.MaxParseErrorRatio > 0 && parseErrorRatio(parseErr, size) > opts.MaxParseErrorRatio
+}
+
+// checkParseErrorRatio returns an error matching ErrParseFailed if the file has too
+// many parse errors to be chunked by its syntax tree and is not to be chunked by
+// sliding windows instead
+func checkParseErrorRatio(parseErr *ParseError, size int, opts ChunkOptions) error {
+	if !tooManyParseErrors(parseErr, size, opts) || opts.SlidingWindowFallback || opts.ChunkStrategy == ChunkStrategySlidingWindow {
+		return nil
+	}
+	return fmt.Errorf("%w: ERROR nodes cover %.0f%% of the source, above the MaxParseErrorRatio of %.0f%%",
+		ErrParseFailed, parseErrorRatio(parseErr, size)*100, opts.MaxParseErrorRatio*100)
+}
+
 // hasParseErrors checks if the tree contains any parse errors
 func hasParseErrors(tree *sitter.Tree) bool {
 	return tree != nil && tree.RootNode().HasError()
@@ -2,7 +2,11 @@ package codechunk
 
 import (
 	"context"
+	"strings"
+	"sync"
 	"testing"
+
+	sitter "github.com/smacker/go-tree-sitter"
 )
 
 func TestParse(t *testing.T) {
@@ -99,6 +103,37 @@ func TestParseSyntaxError(t *testing.T) {
 	}
 }
 
+func TestParseErrorRanges(t *testing.T) {
+	code := "package main\n\nfunc ok() {}\n\nfunc broken() {\n\tx := \n}\n\nfunc fine() {}\n"
+	result, err := parse([]byte(code), LanguageGo)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	defer result.Tree.Close()
+
+	parseErr := result.Error
+	if parseErr == nil || parseErr.Count == 0 || parseErr.Count != len(parseErr.Ranges) || result.ErrorNodeCount != parseErr.Count {
+		t.Fatalf("Expected counted error ranges, got %+v (ErrorNodeCount %d)", parseErr, result.ErrorNodeCount)
+	}
+	broken := strings.Index(code, "func broken")
+	for _, r := range parseErr.Ranges {
+		if r.Start < broken || r.End > strings.Index(code, "func fine") {
+			t.Errorf("Error range %v is outside the broken function", r)
+		}
+	}
+
+	if chunkParseError(parseErr, ByteRange{Start: 0, End: broken}) != nil {
+		t.Error("Expected no parse error on a range before the broken function")
+	}
+	if got := chunkParseError(parseErr, ByteRange{Start: broken, End: len(code)}); got == nil || got.Count != parseErr.Count {
+		t.Errorf("Expected all error ranges on the broken function, got %+v", got)
+	}
+	missing := &ParseError{Ranges: []ByteRange{{Start: 10, End: 10}}, Count: 1}
+	if chunkParseError(missing, ByteRange{Start: 0, End: 10}) == nil || chunkParseError(missing, ByteRange{Start: 11, End: 20}) != nil {
+		t.Error("Expected an empty MISSING range to count at the edge of a chunk only")
+	}
+}
+
 func TestParseWithContext(t *testing.T) {
 	code := `func hello() {}`
 	result, err := parseWithContext(context.Background(), []byte(code), LanguageGo)
@@ -264,3 +299,60 @@ func TestParseConcurrent(t *testing.T) {
 		<-done
 	}
 }
+
+func TestGetParserKeepsLanguage(t *testing.T) {
+	grammar := getLanguageGrammar(LanguagePython)
+	parser := getParser(LanguagePython, grammar)
+	putParser(LanguagePython, parser)
+
+	parser = getParser(LanguagePython, grammar)
+	defer putParser(LanguagePython, parser)
+	tree, err := parser.ParseCtx(context.Background(), nil, []byte("def hello(): pass"))
+	if err != nil {
+		t.Fatalf("ParseCtx failed: %v", err)
+	}
+	if tree.RootNode().Type() != "module" || tree.RootNode().HasError() {
+		t.Errorf("Expected pooled parser to parse Python, got %s", tree.RootNode().String())
+	}
+}
+
+// mixedLanguageSources interleaves languages the way a large repository batch does
+var mixedLanguageSources = []struct {
+	lang Language
+	code []byte
+}{
+	{LanguageGo, []byte("package main\n\nfunc main() {}\n")},
+	{LanguagePython, []byte("def hello():\n    pass\n")},
+	{LanguageTypeScript, []byte("function hello(): void {}\n")},
+	{LanguageRust, []byte("fn main() {}\n")},
+	{LanguageJava, []byte("class Main { void run() {} }\n")},
+	{LanguageJavaScript, []byte("function hello() {}\n")},
+}
+
+func BenchmarkParseMixedLanguages(b *testing.B) {
+	ctx := context.Background()
+
+	// SetLanguageEachParse is the previous behavior: one language-agnostic pool
+	// whose parsers switch grammar on every parse
+	b.Run("SetLanguageEachParse", func(b *testing.B) {
+		shared := sync.Pool{New: func() interface{} { return sitter.NewParser() }}
+		for i := 0; i < b.N; i++ {
+			src := mixedLanguageSources[i%len(mixedLanguageSources)]
+			parser := shared.Get().(*sitter.Parser)
+			parser.SetLanguage(getLanguageGrammar(src.lang))
+			if _, err := parseWithParser(ctx, parser, src.code); err != nil {
+				b.Fatal(err)
+			}
+			shared.Put(parser)
+		}
+	})
+
+	b.Run("PerLanguagePool", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := mixedLanguageSources[i%len(mixedLanguageSources)]
+			if _, err := parseWithContext(ctx, src.code, src.lang); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package codechunk
+
+import (
+	"errors"
+	"sync"
+)
+
+// poolJob is a file submitted to a worker pool with the channel receiving its result
+type poolJob struct {
+	file   FileInput
+	result chan BatchResult
+}
+
+// workerPool is a set of long-lived workers, each with its own parser
+type workerPool struct {
+	jobs chan poolJob
+	wg   sync.WaitGroup
+}
+
+// StartPool starts n workers (10 if n <= 0) that chunk files passed to Submit
+// using the chunker's options merged with each file's own options. Workers keep
+// their parser between files, so services that chunk continuously avoid the
+// per-call goroutine and parser setup of ChunkBatch. A file whose chunking panics
+// gets a PanicError result. The pool runs until StopPool.
+func (c *Chunker) StartPool(n int) error {
+	if n <= 0 {
+		n = 10
+	}
+
+	c.poolMu.Lock()
+	defer c.poolMu.Unlock()
+
+	if c.pool != nil {
+		return errors.New("worker pool already running")
+	}
+
+	pool := &workerPool{jobs: make(chan poolJob, n)}
+	for i := 0; i < n; i++ {
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			parser := newLanguageParser()
+			for job := range pool.jobs {
+				result, panicked := chunkFileIsolated(job.file, BatchOptions{ChunkOptions: c.options}, parser)
+				if panicked {
+					parser = newLanguageParser()
+				}
+				job.result <- result
+				close(job.result)
+			}
+		}()
+	}
+	c.pool = pool
+	return nil
+}
+
+// Submit queues a file on the worker pool started by StartPool. The returned
+// channel receives the file's result and is then closed. Submit blocks while the
+// queue is full, and returns ErrPoolNotRunning if no pool is running.
+func (c *Chunker) Submit(file FileInput) (<-chan BatchResult, error) {
+	c.poolMu.RLock()
+	defer c.poolMu.RUnlock()
+
+	if c.pool == nil {
+		return nil, ErrPoolNotRunning
+	}
+
+	result := make(chan BatchResult, 1)
+	c.pool.jobs <- poolJob{file: file, result: result}
+	return result, nil
+}
+
+// StopPool stops accepting submissions and waits for queued files to finish.
+// It is a no-op if no pool is running.
+func (c *Chunker) StopPool() {
+	c.poolMu.Lock()
+	pool := c.pool
+	c.pool = nil
+	if pool != nil {
+		close(pool.jobs)
+	}
+	c.poolMu.Unlock()
+
+	if pool != nil {
+		pool.wg.Wait()
+	}
+}
@@ -0,0 +1,91 @@
+package codechunk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestChunkerPool(t *testing.T) {
+	chunker := NewChunker(&ChunkOptions{MaxChunkSize: 500})
+	if err := chunker.StartPool(4); err != nil {
+		t.Fatalf("StartPool failed: %v", err)
+	}
+	if err := chunker.StartPool(4); err == nil {
+		t.Error("Expected error starting a running pool")
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 40)
+	for i := 0; i < 40; i++ {
+		file := FileInput{
+			Filepath: fmt.Sprintf("file%d.go", i),
+			Code:     fmt.Sprintf("package main\n\nfunc f%d() {}\n", i),
+		}
+		if i%2 == 1 {
+			file.Filepath = fmt.Sprintf("file%d.py", i)
+			file.Code = fmt.Sprintf("def f%d():\n    pass\n", i)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ch, err := chunker.Submit(file)
+			if err != nil {
+				errs <- err
+				return
+			}
+			result := <-ch
+			if result.Filepath != file.Filepath || result.Error != nil || len(result.Chunks) == 0 {
+				errs <- fmt.Errorf("unexpected result for %s: %+v", file.Filepath, result)
+			}
+			if _, ok := <-ch; ok {
+				errs <- fmt.Errorf("expected result channel for %s to be closed", file.Filepath)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	chunker.StopPool()
+	if _, err := chunker.Submit(FileInput{Filepath: "a.go"}); !errors.Is(err, ErrPoolNotRunning) {
+		t.Errorf("Expected ErrPoolNotRunning after StopPool, got %v", err)
+	}
+	chunker.StopPool()
+}
+
+func TestChunkerSubmitWithoutPool(t *testing.T) {
+	chunker := NewChunker(nil)
+	if _, err := chunker.Submit(FileInput{Filepath: "a.go"}); !errors.Is(err, ErrPoolNotRunning) {
+		t.Errorf("Expected ErrPoolNotRunning, got %v", err)
+	}
+}
+
+func TestLanguageParserSwitchesLanguage(t *testing.T) {
+	parser := newLanguageParser()
+	for _, tt := range []struct {
+		code string
+		lang Language
+	}{
+		{"package main\n", LanguageGo},
+		{"def f():\n    pass\n", LanguagePython},
+		{"package main\n", LanguageGo},
+	} {
+		result, err := parser.parse(context.Background(), []byte(tt.code), tt.lang)
+		if err != nil {
+			t.Fatalf("parse %s failed: %v", tt.lang, err)
+		}
+		if result.Error != nil {
+			t.Errorf("Unexpected parse error for %s: %v", tt.lang, result.Error.Message)
+		}
+	}
+
+	if _, err := parser.parse(context.Background(), []byte("x"), Language("cobol")); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("Expected ErrUnsupportedLanguage, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package codechunk
+
+import (
+	"sort"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// Line and offset conventions: lines are 0-indexed everywhere (LineRange,
+// Position, ReferenceInfo.Line); LineRange ends are inclusive while ByteRange and
+// Span ends are exclusive. HumanLines and HumanLine convert to the 1-based numbers
+// shown by editors.
+
+// HumanLines returns the range as 1-based inclusive line numbers, as shown by editors.
+func (r LineRange) HumanLines() (start, end int) {
+	return r.Start + 1, r.End + 1
+}
+
+// Len returns the number of lines in the range.
+func (r LineRange) Len() int {
+	return r.End - r.Start + 1
+}
+
+// Contains reports whether the 0-indexed line is within the range.
+func (r LineRange) Contains(line int) bool {
+	return line >= r.Start && line <= r.End
+}
+
+// Len returns the number of bytes in the range.
+func (r ByteRange) Len() int {
+	return r.End - r.Start
+}
+
+// Contains reports whether the byte offset is within the range.
+func (r ByteRange) Contains(offset int) bool {
+	return offset >= r.Start && offset < r.End
+}
+
+// HumanLine returns the position's line as a 1-based number, as shown by editors.
+func (p Position) HumanLine() int {
+	return p.Line + 1
+}
+
+// nodeLineRange returns the inclusive line range of a node. A node ending just
+// after a newline ends on the previous line, not at column 0 of the next one.
+func nodeLineRange(node *sitter.Node) LineRange {
+	start := int(node.StartPoint().Row)
+	end := int(node.EndPoint().Row)
+	if node.EndPoint().Column == 0 && end > start {
+		end--
+	}
+	return LineRange{Start: start, End: end}
+}
+
+// positionIndex converts byte offsets of a file into line and column positions
+type positionIndex struct {
+	code       []byte
+	lineStarts []int // Byte offset of the start of each line
+}
+
+// newPositionIndex indexes the line starts of code
+func newPositionIndex(code []byte) *positionIndex {
+	lineStarts := []int{0}
+	for i, b := range code {
+		if b == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	return &positionIndex{code: code, lineStarts: lineStarts}
+}
+
+// position returns the position of a byte offset, clamped to the code
+func (p *positionIndex) position(offset int) Position {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(p.code) {
+		offset = len(p.code)
+	}
+
+	line := p.line(offset)
+	prefix := p.code[p.lineStarts[line]:offset]
+
+	return Position{
+		Line:        line,
+		Column:      len(prefix),
+		RuneColumn:  utf8.RuneCount(prefix),
+		UTF16Column: utf16Len(prefix),
+	}
+}
+
+// line returns the line of a byte offset, clamped to the code, by binary search
+// over the line starts
+func (p *positionIndex) line(offset int) int {
+	if offset > len(p.code) {
+		offset = len(p.code)
+	}
+	if offset < 0 {
+		return 0
+	}
+	return sort.Search(len(p.lineStarts), func(i int) bool {
+		return p.lineStarts[i] > offset
+	}) - 1
+}
+
+// span returns the positions of a byte range
+func (p *positionIndex) span(byteRange ByteRange) Span {
+	return Span{
+		Start: p.position(byteRange.Start),
+		End:   p.position(byteRange.End),
+	}
+}
+
+// utf16Len returns the number of UTF-16 code units needed to encode b. Invalid
+// bytes count as one unit each, like the replacement character they decode to.
+func utf16Len(b []byte) int {
+	n := 0
+	for len(b) > 0 {
+		r, size := utf8.DecodeRune(b)
+		if r >= 0x10000 {
+			n += 2
+		} else {
+			n++
+		}
+		b = b[size:]
+	}
+	return n
+}
+
+// setSpans fills in the spans of entities from their byte ranges
+func setSpans(entities []*ExtractedEntity, positions *positionIndex) {
+	for _, entity := range entities {
+		entity.Span = positions.span(entity.ByteRange)
+	}
+}
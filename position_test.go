@@ -0,0 +1,157 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPositionIndex(t *testing.T) {
+	// "é" is 2 bytes / 1 UTF-16 unit, "😀" is 4 bytes / 2 UTF-16 units
+	code := []byte("ab\né😀x\n\nend")
+	positions := newPositionIndex(code)
+
+	tests := []struct {
+		offset   int
+		expected Position
+	}{
+		{0, Position{Line: 0, Column: 0, RuneColumn: 0, UTF16Column: 0}},
+		{2, Position{Line: 0, Column: 2, RuneColumn: 2, UTF16Column: 2}},
+		{3, Position{Line: 1, Column: 0, RuneColumn: 0, UTF16Column: 0}},
+		{5, Position{Line: 1, Column: 2, RuneColumn: 1, UTF16Column: 1}},
+		{9, Position{Line: 1, Column: 6, RuneColumn: 2, UTF16Column: 3}},
+		{10, Position{Line: 1, Column: 7, RuneColumn: 3, UTF16Column: 4}},
+		{11, Position{Line: 2, Column: 0, RuneColumn: 0, UTF16Column: 0}},
+		{15, Position{Line: 3, Column: 3, RuneColumn: 3, UTF16Column: 3}},
+		{100, Position{Line: 3, Column: 3, RuneColumn: 3, UTF16Column: 3}},
+		{-1, Position{Line: 0, Column: 0, RuneColumn: 0, UTF16Column: 0}},
+	}
+
+	for _, tt := range tests {
+		if got := positions.position(tt.offset); got != tt.expected {
+			t.Errorf("position(%d) = %+v, expected %+v", tt.offset, got, tt.expected)
+		}
+	}
+}
+
+func TestPositionIndexLine(t *testing.T) {
+	tests := []struct {
+		code     string
+		offset   int
+		expected int
+	}{
+		{"hello\nworld", 5, 0},
+		{"hello\nworld", 6, 1},
+		{"hello\nworld", 11, 1},
+		{"a\nb\nc", 3, 1}, // offset 3 is after first \n at position 1
+		{"a\nb\nc", 5, 2},
+		{"\n\n\n", 3, 3},
+		{"test", 4, 0},
+		{"test", 100, 0}, // offset beyond length
+		{"a\nb", -1, 0},
+	}
+
+	for _, tt := range tests {
+		if got := newPositionIndex([]byte(tt.code)).line(tt.offset); got != tt.expected {
+			t.Errorf("line(%q, %d) = %d, want %d", tt.code, tt.offset, got, tt.expected)
+		}
+	}
+}
+
+func TestUTF16LenInvalidBytes(t *testing.T) {
+	if n := utf16Len([]byte{0xff, 'a', 0xfe}); n != 3 {
+		t.Errorf("Expected invalid bytes to count as one unit each, got %d", n)
+	}
+}
+
+func TestChunkSpans(t *testing.T) {
+	code := `# Grüße 😀
+def greet():
+    return "héllo"
+
+class Greeter:
+    def run(self):
+        return greet()
+`
+	chunks, err := Chunk("greet.py", code, &ChunkOptions{MaxChunkSize: 30})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	found := false
+	for _, chunk := range chunks {
+		if chunk.Span.Start.Line != chunk.LineRange.Start || chunk.Span.End.Line != chunk.LineRange.End {
+			t.Errorf("Chunk %d: span lines %d-%d do not match line range %+v",
+				chunk.Index, chunk.Span.Start.Line, chunk.Span.End.Line, chunk.LineRange)
+		}
+		lineStart := strings.LastIndex(code[:chunk.ByteRange.Start], "\n") + 1
+		if chunk.Span.Start.Column != chunk.ByteRange.Start-lineStart {
+			t.Errorf("Chunk %d: start column %d, expected %d", chunk.Index, chunk.Span.Start.Column, chunk.ByteRange.Start-lineStart)
+		}
+
+		for _, entity := range chunk.Context.Entities {
+			if entity.Name != "greet" || entity.Type != EntityTypeFunction {
+				continue
+			}
+			found = true
+			// `    return "héllo"` ends after 18 runes, 19 bytes
+			expected := Position{Line: 2, Column: 19, RuneColumn: 18, UTF16Column: 18}
+			if entity.Span == nil || entity.Span.End != expected {
+				t.Errorf("Expected greet to end at %+v, got %+v", expected, entity.Span)
+			}
+		}
+	}
+	if !found {
+		t.Error("Expected a chunk containing greet")
+	}
+}
+
+func TestRangeHelpers(t *testing.T) {
+	lr := LineRange{Start: 0, End: 2}
+	if start, end := lr.HumanLines(); start != 1 || end != 3 {
+		t.Errorf("HumanLines() = %d, %d, expected 1, 3", start, end)
+	}
+	if lr.Len() != 3 || !lr.Contains(2) || lr.Contains(3) {
+		t.Errorf("Expected an inclusive range of 3 lines, got %+v", lr)
+	}
+
+	br := ByteRange{Start: 4, End: 10}
+	if br.Len() != 6 || !br.Contains(4) || br.Contains(10) {
+		t.Errorf("Expected an end-exclusive range of 6 bytes, got %+v", br)
+	}
+
+	if (Position{Line: 4}).HumanLine() != 5 {
+		t.Error("Expected HumanLine to be 1-based")
+	}
+}
+
+func TestLineRangesAreInclusive(t *testing.T) {
+	files := map[string]string{
+		"main.go": "package main\n\nimport \"fmt\"\n\nfunc main() {\n\tfmt.Println(1)\n}\n",
+		"app.py":  "import os\n\nclass App:\n    def run(self):\n        pass\n\ndef main():\n    pass\n",
+		"lib.rs":  "use std::io;\n\nfn main() {\n}\n\nstruct S;\n",
+	}
+
+	for path, code := range files {
+		chunks, err := Chunk(path, code, &ChunkOptions{MaxChunkSize: 20})
+		if err != nil {
+			t.Fatalf("Chunk(%s) failed: %v", path, err)
+		}
+		for _, chunk := range chunks {
+			last := strings.Count(code[:chunk.ByteRange.End-1], "\n")
+			if chunk.LineRange.End != last {
+				t.Errorf("%s chunk %d: LineRange.End = %d, expected %d", path, chunk.Index, chunk.LineRange.End, last)
+			}
+		}
+
+		parsed, err := parseAndExtract([]byte(code), DetectLanguage(path), nil)
+		if err != nil {
+			t.Fatalf("parseAndExtract(%s) failed: %v", path, err)
+		}
+		for _, entity := range parsed.Entities {
+			last := strings.Count(code[:entity.ByteRange.End-1], "\n")
+			if entity.LineRange.End != last {
+				t.Errorf("%s entity %s: LineRange.End = %d, expected %d", path, entity.Name, entity.LineRange.End, last)
+			}
+		}
+	}
+}
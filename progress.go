@@ -0,0 +1,119 @@
+package codechunk
+
+import (
+	"sync"
+	"time"
+)
+
+// maxQueuedProgress is the number of progress updates queued for a callback that
+// falls behind before further updates are coalesced into the latest one
+const maxQueuedProgress = 1024
+
+// progressUpdate is one call of a progress callback
+type progressUpdate struct {
+	completed int
+	filepath  string
+	success   bool
+}
+
+// progressReporter delivers the progress of a batch to BatchOptions.OnProgress on
+// its own goroutine, so a slow callback does not hold up the workers. Updates are
+// queued in order; once maxQueuedProgress are waiting, or at every tick with
+// ProgressInterval, waiting updates are coalesced into the latest.
+type progressReporter struct {
+	fn       func(completed, total int, filepath string, success bool)
+	total    int
+	interval time.Duration
+
+	mu        sync.Mutex
+	completed int
+	queue     []progressUpdate
+	wake      chan struct{} // Signalled when an update is queued
+	stop      chan struct{} // Closed by close
+	done      chan struct{} // Closed once the last update is delivered
+}
+
+// newProgressReporter starts delivering the progress of a batch of total files,
+// returning nil if opts has no OnProgress callback
+func newProgressReporter(opts BatchOptions, total int) *progressReporter {
+	if opts.OnProgress == nil {
+		return nil
+	}
+	p := &progressReporter{
+		fn:       opts.OnProgress,
+		total:    total,
+		interval: opts.ProgressInterval,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go p.run()
+	return p
+}
+
+// report records that a file completed, without waiting for the callback
+func (p *progressReporter) report(filepath string, success bool) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.completed++
+	update := progressUpdate{completed: p.completed, filepath: filepath, success: success}
+	if len(p.queue) >= maxQueuedProgress {
+		p.queue[len(p.queue)-1] = update
+	} else {
+		p.queue = append(p.queue, update)
+	}
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// close delivers the remaining updates and waits for the callback to return.
+// Files must not be reported afterwards.
+func (p *progressReporter) close() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	<-p.done
+}
+
+func (p *progressReporter) run() {
+	defer close(p.done)
+	for {
+		select {
+		case <-p.wake:
+			p.deliver()
+			if p.interval > 0 {
+				select {
+				case <-time.After(p.interval):
+				case <-p.stop:
+				}
+			}
+		case <-p.stop:
+			p.deliver()
+			return
+		}
+	}
+}
+
+// deliver calls the callback for the queued updates, or only the latest with
+// ProgressInterval
+func (p *progressReporter) deliver() {
+	p.mu.Lock()
+	queue := p.queue
+	p.queue = nil
+	p.mu.Unlock()
+
+	if p.interval > 0 && len(queue) > 1 {
+		queue = queue[len(queue)-1:]
+	}
+	for _, update := range queue {
+		p.fn(update.completed, p.total, update.filepath, update.success)
+	}
+}
@@ -0,0 +1,95 @@
+package codechunk
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+// progressFiles returns n small Go files
+func progressFiles(n int) []FileInput {
+	files := make([]FileInput, n)
+	for i := range files {
+		files[i] = FileInput{Filepath: fmt.Sprintf("f%d.go", i), Code: fmt.Sprintf("package p\n\nfunc F%d() {}\n", i)}
+	}
+	return files
+}
+
+func TestProgressDoesNotBlockWorkers(t *testing.T) {
+	files := progressFiles(5)
+	release := make(chan struct{})
+	var calls []int
+	opts := &BatchOptions{
+		Concurrency: 1,
+		OnProgress: func(completed, total int, filepath string, success bool) {
+			if len(calls) == 0 {
+				<-release
+			}
+			calls = append(calls, completed)
+		},
+	}
+
+	// Every result is received while the first progress call is blocked
+	ch := ChunkBatchStream(files, opts)
+	for range files {
+		select {
+		case <-ch:
+		case <-time.After(5 * time.Second):
+			t.Fatal("Expected results while the progress callback is blocked")
+		}
+	}
+	close(release)
+	if _, ok := <-ch; ok {
+		t.Fatal("Expected the stream to close")
+	}
+
+	// The stream closes after the last call, and no call was dropped
+	if len(calls) != len(files) || calls[len(calls)-1] != len(files) {
+		t.Errorf("Expected a call per file, got %v", calls)
+	}
+}
+
+func TestProgressCoalescing(t *testing.T) {
+	release := make(chan struct{})
+	var calls []int
+	p := newProgressReporter(BatchOptions{OnProgress: func(completed, total int, filepath string, success bool) {
+		if len(calls) == 0 {
+			<-release
+		}
+		calls = append(calls, completed)
+	}}, 3000)
+
+	for i := 0; i < 3000; i++ {
+		p.report(fmt.Sprintf("f%d.go", i), true)
+	}
+	close(release)
+	p.close()
+
+	if len(calls) >= 3000 || calls[len(calls)-1] != 3000 {
+		t.Fatalf("Expected coalesced calls ending at 3000, got %d calls ending at %d", len(calls), calls[len(calls)-1])
+	}
+	for i := 1; i < len(calls); i++ {
+		if calls[i] <= calls[i-1] {
+			t.Fatalf("Expected increasing counts, got %d after %d", calls[i], calls[i-1])
+		}
+	}
+}
+
+func TestProgressInterval(t *testing.T) {
+	files := progressFiles(20)
+	var calls []int
+	opts := &BatchOptions{
+		Concurrency:      2,
+		ProgressInterval: time.Hour,
+		OnProgress: func(completed, total int, filepath string, success bool) {
+			calls = append(calls, completed)
+		},
+	}
+
+	ChunkBatch(files, opts)
+	// The first update is delivered, then the rest wait out the interval and are
+	// coalesced into the last call once the batch completes
+	if len(calls) > 2 || calls[len(calls)-1] != len(files) {
+		t.Errorf("Expected at most two calls ending at %d, got %v", len(files), calls)
+	}
+}
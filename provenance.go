@@ -0,0 +1,75 @@
+package codechunk
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Provenance locates the file of a chunk in its repository, so retrieved chunks
+// can be cited by stable repository coordinates rather than local paths
+type Provenance struct {
+	RepoRoot  string `json:"repoRoot,omitempty"`  // Local path of the repository root
+	RelPath   string `json:"relPath,omitempty"`   // Slash-separated path of the file from the repository root (default: derived from RepoRoot)
+	CommitSHA string `json:"commitSha,omitempty"` // Commit the file was read at
+	Branch    string `json:"branch,omitempty"`    // Branch the commit was checked out from
+}
+
+// merge returns p with its empty fields taken from base, either of which may be nil
+func (p *Provenance) merge(base *Provenance) *Provenance {
+	if p == nil {
+		return base
+	}
+	if base == nil {
+		return p
+	}
+	merged := *p
+	if merged.RepoRoot == "" {
+		merged.RepoRoot = base.RepoRoot
+	}
+	if merged.RelPath == "" {
+		merged.RelPath = base.RelPath
+	}
+	if merged.CommitSHA == "" {
+		merged.CommitSHA = base.CommitSHA
+	}
+	if merged.Branch == "" {
+		merged.Branch = base.Branch
+	}
+	return &merged
+}
+
+// fileProvenance returns the provenance of the file at path, with RelPath derived
+// from RepoRoot unless set. RelPath stays empty for files outside RepoRoot.
+// Returns nil if p is nil.
+func fileProvenance(p *Provenance, path string) *Provenance {
+	if p == nil {
+		return nil
+	}
+	provenance := *p
+	if provenance.RelPath == "" && provenance.RepoRoot != "" {
+		if rel, err := filepath.Rel(provenance.RepoRoot, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			provenance.RelPath = filepath.ToSlash(rel)
+		}
+	}
+	return &provenance
+}
+
+// sourcePath returns the path of a file to derive names from: its path from the
+// repository root when opts.Provenance locates it, or path otherwise
+func sourcePath(path string, opts ChunkOptions) string {
+	if provenance := fileProvenance(opts.Provenance, path); provenance != nil && provenance.RelPath != "" {
+		return provenance.RelPath
+	}
+	return path
+}
+
+// attributeChunks sets the provenance of a file on the context of its chunks
+func attributeChunks(path string, p *Provenance, chunks []CodeChunk) {
+	provenance := fileProvenance(p, path)
+	if provenance == nil {
+		return
+	}
+	for i := range chunks {
+		chunks[i].Context.Provenance = provenance
+	}
+}
@@ -0,0 +1,93 @@
+package codechunk
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFileProvenance(t *testing.T) {
+	root := filepath.Join("home", "dev", "service")
+	tests := []struct {
+		provenance *Provenance
+		path       string
+		want       string
+	}{
+		{&Provenance{RepoRoot: root}, filepath.Join(root, "auth", "login.go"), "auth/login.go"},
+		{&Provenance{RepoRoot: root, RelPath: "vendored/login.go"}, filepath.Join(root, "auth", "login.go"), "vendored/login.go"},
+		{&Provenance{RepoRoot: root}, filepath.Join("home", "dev", "other", "login.go"), ""},
+		{&Provenance{CommitSHA: "3f2c1a9"}, "login.go", ""},
+	}
+
+	for _, tt := range tests {
+		if got := fileProvenance(tt.provenance, tt.path); got.RelPath != tt.want {
+			t.Errorf("%s from %q: expected RelPath %q, got %q", tt.path, tt.provenance.RepoRoot, tt.want, got.RelPath)
+		}
+	}
+	if fileProvenance(nil, "login.go") != nil {
+		t.Error("Expected no provenance without one set")
+	}
+}
+
+func TestChunkProvenance(t *testing.T) {
+	code := "package auth\n\nfunc Login() {}\n"
+	opts := &ChunkOptions{Provenance: &Provenance{RepoRoot: "service", CommitSHA: "3f2c1a9", Branch: "main"}}
+	path := filepath.Join("service", "auth", "login.go")
+	want := Provenance{RepoRoot: "service", RelPath: "auth/login.go", CommitSHA: "3f2c1a9", Branch: "main"}
+
+	chunks, err := Chunk(path, code, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, chunk := range chunks {
+		if chunk.Context.Provenance == nil || *chunk.Context.Provenance != want {
+			t.Errorf("Expected provenance %+v, got %+v", want, chunk.Context.Provenance)
+		}
+		metadata := chunk.Metadata()
+		if metadata[MetadataRelPath] != "auth/login.go" || metadata[MetadataCommitSHA] != "3f2c1a9" || metadata[MetadataBranch] != "main" {
+			t.Errorf("Expected the provenance in the metadata, got %v", metadata)
+		}
+	}
+
+	stream, err := ChunkStream(path, code, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for chunk := range stream {
+		if chunk.Context.Provenance == nil || *chunk.Context.Provenance != want {
+			t.Errorf("Expected streamed provenance %+v, got %+v", want, chunk.Context.Provenance)
+		}
+	}
+
+	plain, err := Chunk(path, code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if plain[0].Context.Provenance != nil {
+		t.Errorf("Expected no provenance without options, got %+v", plain[0].Context.Provenance)
+	}
+}
+
+func TestBatchProvenance(t *testing.T) {
+	code := "package auth\n\nfunc Login() {}\n"
+	files := []FileInput{
+		{Filepath: filepath.Join("service", "auth", "login.go"), Code: code},
+		{Filepath: "patch.go", Code: code, Provenance: &Provenance{RelPath: "auth/patch.go", Branch: "fix"}},
+	}
+	opts := DefaultBatchOptions()
+	opts.Provenance = &Provenance{RepoRoot: "service", CommitSHA: "3f2c1a9", Branch: "main"}
+
+	want := map[string]Provenance{
+		files[0].Filepath: {RepoRoot: "service", RelPath: "auth/login.go", CommitSHA: "3f2c1a9", Branch: "main"},
+		"patch.go":        {RepoRoot: "service", RelPath: "auth/patch.go", CommitSHA: "3f2c1a9", Branch: "fix"},
+	}
+	for _, result := range ChunkBatch(files, &opts) {
+		if result.Error != nil {
+			t.Fatal(result.Error)
+		}
+		for _, chunk := range result.Chunks {
+			if chunk.Context.Provenance == nil || *chunk.Context.Provenance != want[result.Filepath] {
+				t.Errorf("%s: expected provenance %+v, got %+v", result.Filepath, want[result.Filepath], chunk.Context.Provenance)
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package codechunk
+
+import sitter "github.com/smacker/go-tree-sitter"
+
+// QueryMatch is a match of a pattern of a tree-sitter query
+type QueryMatch struct {
+	Pattern  int            `json:"pattern"`  // Index of the matched pattern in the query
+	Captures []QueryCapture `json:"captures"` // Captured nodes in the order of the pattern
+}
+
+// QueryCapture is a node captured by a tree-sitter query
+type QueryCapture struct {
+	Name      string       `json:"name"`      // Capture name without the leading @
+	Text      string       `json:"text"`      // Source text of the node
+	ByteRange ByteRange    `json:"byteRange"` // Byte range in source
+	LineRange LineRange    `json:"lineRange"` // Line range in source
+	Node      *sitter.Node `json:"-"`         // The captured node, valid until the analysis is closed
+}
+
+// Query runs a tree-sitter query against the analyzed syntax tree and returns its
+// matches in document order, with predicates such as #eq? and #match? applied:
+//
+//	matches, err := analysis.Query(`(call_expression function: (identifier) @callee)`)
+//
+// The query uses the node types of the file's grammar. Returns ErrAnalysisClosed
+// after Close, or the query's syntax error.
+func (a *FileAnalysis) Query(query string) ([]QueryMatch, error) {
+	if a.tree == nil {
+		return nil, ErrAnalysisClosed
+	}
+	grammar := getLanguageGrammar(a.Language)
+	if grammar == nil {
+		return nil, ErrUnsupportedLanguage
+	}
+
+	q, err := sitter.NewQuery([]byte(query), grammar)
+	if err != nil {
+		return nil, err
+	}
+	defer q.Close()
+	cursor := sitter.NewQueryCursor()
+	defer cursor.Close()
+	cursor.Exec(q, a.tree.RootNode())
+
+	matches := make([]QueryMatch, 0)
+	for {
+		match, ok := cursor.NextMatch()
+		if !ok {
+			break
+		}
+		match = cursor.FilterPredicates(match, a.code)
+		if len(match.Captures) == 0 {
+			continue
+		}
+
+		captures := make([]QueryCapture, len(match.Captures))
+		for i, capture := range match.Captures {
+			captures[i] = QueryCapture{
+				Name:      q.CaptureNameForId(capture.Index),
+				Text:      capture.Node.Content(a.code),
+				ByteRange: ByteRange{Start: int(capture.Node.StartByte()), End: int(capture.Node.EndByte())},
+				LineRange: nodeLineRange(capture.Node),
+				Node:      capture.Node,
+			}
+		}
+		matches = append(matches, QueryMatch{Pattern: int(match.PatternIndex), Captures: captures})
+	}
+	return matches, nil
+}
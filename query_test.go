@@ -0,0 +1,61 @@
+package codechunk
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFileAnalysisQuery(t *testing.T) {
+	code := `package main
+
+func main() {
+	run()
+	fmt.Println("done")
+	run()
+}
+`
+	analysis, err := Analyze("main.go", code, nil)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	defer analysis.Close()
+
+	matches, err := analysis.Query(`(call_expression function: (identifier) @callee (#eq? @callee "run"))`)
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("expected 2 matches, got %d", len(matches))
+	}
+	for _, match := range matches {
+		capture := match.Captures[0]
+		if capture.Name != "callee" || capture.Text != "run" {
+			t.Errorf("unexpected capture %+v", capture)
+		}
+		if code[capture.ByteRange.Start:capture.ByteRange.End] != "run" {
+			t.Errorf("byte range %v does not cover the capture", capture.ByteRange)
+		}
+	}
+	if matches[0].Captures[0].LineRange.Start != 3 || matches[1].Captures[0].LineRange.Start != 5 {
+		t.Errorf("expected matches on lines 3 and 5, got %v and %v", matches[0].Captures[0].LineRange, matches[1].Captures[0].LineRange)
+	}
+}
+
+func TestFileAnalysisQueryErrors(t *testing.T) {
+	analysis, err := Analyze("main.go", "package main\n", nil)
+	if err != nil {
+		t.Fatalf("Analyze failed: %v", err)
+	}
+	if _, err := analysis.Query(`(not_a_node_type) @x`); err == nil {
+		t.Error("expected an error for an invalid query")
+	}
+
+	analysis.Close()
+	analysis.Close()
+	if analysis.Tree() != nil {
+		t.Error("expected no tree after Close")
+	}
+	if _, err := analysis.Query(`(identifier) @id`); !errors.Is(err, ErrAnalysisClosed) {
+		t.Errorf("expected ErrAnalysisClosed, got %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package codechunk
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+// ChunkStreamReader is like ChunkStream but reads the source from r, for files of
+// hundreds of megabytes such as generated bindings or data kept as code. The
+// source is memory-mapped rather than read onto the heap: an *os.File at its
+// start is mapped directly and other readers are first copied to a temporary
+// file. Large sources are handed to tree-sitter piece by piece instead of being
+// copied whole. On platforms without mmap the source is read into memory.
+//
+// The mapping is released once the channel is closed, so drain it. Chunks do not
+// refer to the mapping, but LazyText keeps a copy of the whole source on the heap.
+// The file must not be truncated while it is mapped.
+func ChunkStreamReader(filepath string, r io.Reader, opts *ChunkOptions) (<-chan CodeChunk, error) {
+	code, release, err := readSource(r)
+	if err != nil {
+		return nil, err
+	}
+	return chunkStream(context.Background(), filepath, code, "", opts, release)
+}
+
+// readSource maps the source read from r into memory, returning a function that
+// releases it
+func readSource(r io.Reader) ([]byte, func(), error) {
+	if f, ok := r.(*os.File); ok {
+		if offset, err := f.Seek(0, io.SeekCurrent); err == nil && offset == 0 {
+			if info, err := f.Stat(); err == nil && info.Mode().IsRegular() {
+				return mapFile(f, info.Size())
+			}
+		}
+	}
+	if !canMap {
+		code, err := io.ReadAll(r)
+		return code, func() {}, err
+	}
+
+	tmp, err := os.CreateTemp("", "codechunk-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	// The mapping outlives the file's name and descriptor
+	defer tmp.Close()
+	defer os.Remove(tmp.Name())
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return mapFile(tmp, size)
+}
@@ -0,0 +1,104 @@
+package codechunk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const readerTestCode = `package auth
+
+import "strings"
+
+// Normalize trims and lowercases a name
+func Normalize(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Service authenticates users
+type Service struct {
+	users map[string]string
+}
+
+// Login checks a password
+func (s *Service) Login(name, password string) bool {
+	return s.users[Normalize(name)] == password
+}
+`
+
+// drainChunks receives all the chunks of a stream
+func drainChunks(t *testing.T, ch <-chan CodeChunk, err error) []CodeChunk {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("stream failed: %v", err)
+	}
+	var chunks []CodeChunk
+	for chunk := range ch {
+		chunks = append(chunks, chunk)
+	}
+	return chunks
+}
+
+func TestChunkStreamReader(t *testing.T) {
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 100
+	ch, err := ChunkStream("auth.go", readerTestCode, &opts)
+	want := drainChunks(t, ch, err)
+	if len(want) < 2 {
+		t.Fatalf("Expected several chunks, got %d", len(want))
+	}
+
+	path := filepath.Join(t.TempDir(), "auth.go")
+	if err := os.WriteFile(path, []byte(readerTestCode), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	for name, r := range map[string]io.Reader{"file": f, "reader": strings.NewReader(readerTestCode)} {
+		ch, err := ChunkStreamReader("auth.go", r, &opts)
+		if got := drainChunks(t, ch, err); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected the chunks of ChunkStream, got %d chunks", name, len(got))
+		}
+	}
+
+	ch, err = ChunkStreamReader("empty.go", strings.NewReader(""), &opts)
+	if got := drainChunks(t, ch, err); len(got) != 0 {
+		t.Errorf("Expected no chunks for an empty reader, got %d", len(got))
+	}
+}
+
+func TestParseInput(t *testing.T) {
+	// Large enough to be read in several pieces
+	code := []byte(readerTestCode + strings.Repeat("\nfunc Upper(name string) string {\n\treturn strings.ToUpper(name)\n}\n", inputParsePiece/30))
+
+	parser := getParser(LanguageGo, getLanguageGrammar(LanguageGo))
+	defer putParser(LanguageGo, parser)
+	want, err := parser.ParseCtx(context.Background(), nil, code)
+	if err != nil {
+		t.Fatalf("ParseCtx failed: %v", err)
+	}
+	defer want.Close()
+	got, err := parseInput(context.Background(), parser, code)
+	if err != nil {
+		t.Fatalf("parseInput failed: %v", err)
+	}
+	defer got.Close()
+	if got.RootNode().String() != want.RootNode().String() {
+		t.Error("Expected the tree parsed from pieces to match the one parsed at once")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := parseInput(ctx, parser, code); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected a cancelled parse to fail with context.Canceled, got %v", err)
+	}
+}
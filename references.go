@@ -0,0 +1,222 @@
+package codechunk
+
+import (
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// CallSite is a call expression found in the source
+type CallSite struct {
+	Name      string    `json:"name"`      // Callee as written (e.g. "fmt.Println", "helper")
+	ByteRange ByteRange `json:"byteRange"` // Byte range of the call expression
+	Line      int       `json:"line"`      // Line of the call (0-indexed)
+}
+
+// callNodeTypes maps languages to node types that represent calls
+var callNodeTypes = map[Language][]string{
+	LanguageTypeScript: {"call_expression", "new_expression"},
+	LanguageJavaScript: {"call_expression", "new_expression"},
+	LanguagePython:     {"call"},
+	LanguageRust:       {"call_expression"},
+	LanguageGo:         {"call_expression"},
+	LanguageJava:       {"method_invocation", "object_creation_expression"},
+}
+
+// calleeFieldNames are the fields that hold the callee of a call node
+var calleeFieldNames = []string{"function", "constructor", "type"}
+
+// extractCallSites collects all call expressions in the tree in source order
+func extractCallSites(rootNode *sitter.Node, lang Language, code []byte) []CallSite {
+	types := callNodeTypes[lang]
+	if len(types) == 0 {
+		return nil
+	}
+
+	isCall := make(map[string]bool, len(types))
+	for _, t := range types {
+		isCall[t] = true
+	}
+
+	calls := make([]CallSite, 0)
+	stack := []*sitter.Node{rootNode}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if node == nil {
+			continue
+		}
+
+		if isCall[node.Type()] {
+			if name := extractCalleeName(node, code); name != "" {
+				calls = append(calls, CallSite{
+					Name: name,
+					ByteRange: ByteRange{
+						Start: int(node.StartByte()),
+						End:   int(node.EndByte()),
+					},
+					Line: int(node.StartPoint().Row),
+				})
+			}
+		}
+
+		for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+			stack = append(stack, node.Child(i))
+		}
+	}
+
+	return calls
+}
+
+// extractCalleeName extracts the called symbol from a call node
+func extractCalleeName(node *sitter.Node, code []byte) string {
+	// Java method invocations keep the receiver and method name in separate fields
+	if node.Type() == "method_invocation" {
+		nameNode := node.ChildByFieldName("name")
+		if nameNode == nil {
+			return ""
+		}
+		name := nameNode.Content(code)
+		if object := node.ChildByFieldName("object"); object != nil {
+			if qualifier := cleanSignature(object.Content(code)); isIdentifierPath(qualifier) {
+				return qualifier + "." + name
+			}
+		}
+		return name
+	}
+
+	var callee *sitter.Node
+	for _, field := range calleeFieldNames {
+		if callee = node.ChildByFieldName(field); callee != nil {
+			break
+		}
+	}
+	if callee == nil {
+		return ""
+	}
+
+	text := strings.ReplaceAll(cleanSignature(callee.Content(code)), " ", "")
+	if isIdentifierPath(text) {
+		return text
+	}
+	// Calling the result of another call (f()()) names no symbol of its own
+	if strings.Contains(callee.Type(), "call") {
+		return ""
+	}
+	return lastIdentifier(callee, code)
+}
+
+// isIdentifierPath reports whether s is a dotted or ::-separated identifier path
+func isIdentifierPath(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, segment := range strings.FieldsFunc(s, func(r rune) bool { return r == '.' || r == ':' }) {
+		if segment == "" || !isIdentStart(segment[0]) && segment[0] != '$' {
+			return false
+		}
+		for i := 1; i < len(segment); i++ {
+			c := segment[i]
+			if !isIdentStart(c) && c != '$' && (c < '0' || c > '9') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// lastIdentifier returns the rightmost identifier leaf under node
+func lastIdentifier(node *sitter.Node, code []byte) string {
+	for i := int(node.ChildCount()) - 1; i >= 0; i-- {
+		child := node.Child(i)
+		if child.ChildCount() == 0 && strings.Contains(child.Type(), "identifier") {
+			return child.Content(code)
+		}
+		if name := lastIdentifier(child, code); name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// getReferences lists the symbols called within a byte range, in order of first
+// call. Signatures are filled in for callees defined in the same file, as
+// callDefinition resolves them.
+func getReferences(byteRange ByteRange, calls []CallSite, scopeTree *ScopeTree) []ReferenceInfo {
+	references := make([]ReferenceInfo, 0)
+	if len(calls) == 0 {
+		return references
+	}
+
+	seen := make(map[string]bool)
+
+	for _, call := range calls {
+		if call.ByteRange.Start < byteRange.Start || call.ByteRange.Start >= byteRange.End {
+			continue
+		}
+		if seen[call.Name] {
+			continue
+		}
+		seen[call.Name] = true
+
+		ref := ReferenceInfo{
+			Name: call.Name,
+			Line: call.Line,
+		}
+		if def := callDefinition(call, scopeTree); def != nil {
+			ref.Signature = def.Signature
+		}
+		references = append(references, ref)
+	}
+
+	return references
+}
+
+// selfReceivers name the instance or type a method is called on from within its
+// own type, such as self.save() in Python or Self::new() in Rust
+var selfReceivers = map[string]bool{"self": true, "this": true, "cls": true, "Self": true}
+
+// callDefinition returns the entity of the file a call resolves to, or nil. An
+// unqualified call resolves to the first callable of its name. A qualified call
+// only resolves when its receiver does without type information: a member of a
+// type of the file called through the type, such as Config::new, or through
+// self or this within one of the type's members. Calls through variables or
+// imported packages, such as client.get or fmt.Println, are left unresolved.
+func callDefinition(call CallSite, scopeTree *ScopeTree) *ExtractedEntity {
+	i := strings.LastIndexAny(call.Name, ".:")
+	if i == -1 {
+		return scopeTree.callables[call.Name]
+	}
+	receiver := strings.TrimRight(call.Name[:i], ".:")
+	if selfReceivers[receiver] {
+		receiver = ""
+		if node := findScopeAtOffset(scopeTree, call.ByteRange.Start); node != nil && node.Entity.Parent != nil {
+			receiver = *node.Entity.Parent
+		}
+	}
+	if receiver == "" {
+		return nil
+	}
+	return scopeTree.callables[receiver+"."+call.Name[i+1:]]
+}
+
+// callableDefinitions indexes the callable entities of a file by name, and the
+// members among them also by their parent's name and theirs joined by a dot
+func callableDefinitions(entities []*ExtractedEntity) map[string]*ExtractedEntity {
+	definitions := make(map[string]*ExtractedEntity)
+	add := func(key string, entity *ExtractedEntity) {
+		if _, exists := definitions[key]; !exists {
+			definitions[key] = entity
+		}
+	}
+	for _, entity := range entities {
+		switch entity.Type {
+		case EntityTypeFunction, EntityTypeMethod, EntityTypeClass, EntityTypeComponent:
+			add(entity.Name, entity)
+			if entity.Parent != nil {
+				add(*entity.Parent+"."+entity.Name, entity)
+			}
+		}
+	}
+	return definitions
+}
@@ -0,0 +1,153 @@
+package codechunk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func callNames(t *testing.T, code string, lang Language) []string {
+	t.Helper()
+	result, err := parseString(code, lang)
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	names := make([]string, 0)
+	for _, c := range extractCallSites(result.Tree.RootNode(), lang, []byte(code)) {
+		names = append(names, c.Name)
+	}
+	return names
+}
+
+func TestExtractCallSites(t *testing.T) {
+	tests := []struct {
+		name     string
+		lang     Language
+		code     string
+		expected []string
+	}{
+		{
+			name:     "go",
+			lang:     LanguageGo,
+			code:     "package m\n\nfunc f() {\n\tfmt.Println(helper())\n\ts.do()\n}\n",
+			expected: []string{"fmt.Println", "helper", "s.do"},
+		},
+		{
+			name:     "javascript",
+			lang:     LanguageJavaScript,
+			code:     "foo();\na.b.c();\nnew Widget();\nmake()();\n",
+			expected: []string{"foo", "a.b.c", "Widget", "make"},
+		},
+		{
+			name:     "python",
+			lang:     LanguagePython,
+			code:     "def f():\n    os.path.join(a, b)\n    helper()\n",
+			expected: []string{"os.path.join", "helper"},
+		},
+		{
+			name:     "rust",
+			lang:     LanguageRust,
+			code:     "fn f() {\n    Vec::new();\n    helper(1);\n}\n",
+			expected: []string{"Vec::new", "helper"},
+		},
+		{
+			name:     "java",
+			lang:     LanguageJava,
+			code:     "class A { void f() { list.add(1); helper(); new Widget(); } }",
+			expected: []string{"list.add", "helper", "Widget"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := callNames(t, tt.code, tt.lang)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestChunkReferences(t *testing.T) {
+	code := `package main
+
+import "fmt"
+
+func helper(n int) int {
+	return n * 2
+}
+
+func main() {
+	fmt.Println(helper(1))
+	fmt.Println(helper(2))
+}
+`
+	chunks, err := Chunk("main.go", code, &ChunkOptions{MaxChunkSize: 60})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	var found bool
+	for _, c := range chunks {
+		for _, e := range c.Context.Entities {
+			if e.Name != "main" {
+				continue
+			}
+			found = true
+			expected := []ReferenceInfo{
+				{Name: "fmt.Println", Line: 9},
+				{Name: "helper", Signature: "func helper(n int) int", Line: 9},
+			}
+			if !reflect.DeepEqual(c.Context.References, expected) {
+				t.Errorf("Expected references %+v, got %+v", expected, c.Context.References)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("Expected a chunk containing main")
+	}
+}
+
+func TestChunkReferencesQualifiedSignatures(t *testing.T) {
+	code := `class Store:
+    def save(self, item):
+        return item
+
+    def flush(self):
+        self.save(None)
+        Store.save(self, None)
+        self.client.save(None)
+        db.save(None)
+`
+	chunks, err := Chunk("store.py", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	signatures := make(map[string]string)
+	for _, c := range chunks {
+		for _, ref := range c.Context.References {
+			signatures[ref.Name] = ref.Signature
+		}
+	}
+	expected := map[string]string{
+		"self.save":        "def save(self, item)",
+		"Store.save":       "def save(self, item)",
+		"self.client.save": "",
+		"db.save":          "",
+	}
+	if !reflect.DeepEqual(signatures, expected) {
+		t.Errorf("Expected signatures %+v, got %+v", expected, signatures)
+	}
+}
+
+func TestChunkReferencesContextModeNone(t *testing.T) {
+	chunks, err := Chunk("main.go", "package main\n\nfunc main() { run() }\n", &ChunkOptions{ContextMode: ContextModeNone})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for _, c := range chunks {
+		if c.Context.References == nil || len(c.Context.References) != 0 {
+			t.Errorf("Expected empty references, got %+v", c.Context.References)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+	codechunkv1 "github.com/pc-coder/tree-code-chunker/remote/proto/codechunk/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Client calls a remote ChunkerService using codechunk types.
+type Client struct {
+	rpc codechunkv1.ChunkerServiceClient
+}
+
+// NewClient creates a Client on top of a gRPC connection.
+func NewClient(conn grpc.ClientConnInterface) *Client {
+	return &Client{rpc: codechunkv1.NewChunkerServiceClient(conn)}
+}
+
+// Chunk chunks a single file remotely. Nil options use the server defaults.
+func (c *Client) Chunk(ctx context.Context, filepath string, code string, opts *codechunk.ChunkOptions) ([]codechunk.CodeChunk, error) {
+	resp, err := c.rpc.Chunk(ctx, chunkRequest(filepath, code, opts))
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return chunksFromProto(resp.GetChunks()), nil
+}
+
+// ChunkStream chunks a single file remotely, calling fn for each chunk as it arrives.
+// Streaming stops at the first error returned by fn.
+func (c *Client) ChunkStream(ctx context.Context, filepath string, code string, opts *codechunk.ChunkOptions, fn func(codechunk.CodeChunk) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.rpc.ChunkStream(ctx, chunkRequest(filepath, code, opts))
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		if err := fn(chunkFromProto(chunk)); err != nil {
+			return err
+		}
+	}
+}
+
+// ChunkBatch chunks many files remotely, calling fn for each file result as it
+// completes. Per-file failures are reported in BatchResult.Error; the returned
+// error covers transport failures and errors returned by fn.
+func (c *Client) ChunkBatch(ctx context.Context, files []codechunk.FileInput, opts *codechunk.BatchOptions, fn func(codechunk.BatchResult) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req := &codechunkv1.ChunkBatchRequest{
+		Files: make([]*codechunkv1.FileInput, len(files)),
+	}
+	for i, f := range files {
+		req.Files[i] = fileToProto(f)
+	}
+	if opts != nil {
+		req.Options = optionsToProto(&opts.ChunkOptions)
+		req.Concurrency = int32(opts.Concurrency)
+		req.SkipPolicy = skipPolicyToProto(opts.SkipPolicy)
+		req.Ordered = opts.Ordered
+		req.Dedupe = opts.Dedupe
+	}
+
+	stream, err := c.rpc.ChunkBatch(ctx, req)
+	if err != nil {
+		return fromStatus(err)
+	}
+
+	for {
+		result, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		if err := fn(resultFromProto(result)); err != nil {
+			return err
+		}
+	}
+}
+
+func chunkRequest(filepath string, code string, opts *codechunk.ChunkOptions) *codechunkv1.ChunkRequest {
+	return &codechunkv1.ChunkRequest{
+		File: &codechunkv1.FileInput{
+			Filepath: filepath,
+			Code:     code,
+			Options:  optionsToProto(opts),
+		},
+	}
+}
+
+// fromStatus maps gRPC status errors back to codechunk errors where possible
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return codechunk.ErrorFromCode(codechunk.ErrorCodeUnsupportedLanguage, st.Message())
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	default:
+		return err
+	}
+}
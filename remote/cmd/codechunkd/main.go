@@ -0,0 +1,69 @@
+// Command codechunkd serves the codechunk library over gRPC.
+//
+// Usage:
+//
+//	codechunkd -addr :50051 -concurrency 8 -max-chunk-size 1500
+//
+// Requests that carry their own ChunkOptions use them as-is; requests without
+// options use the defaults configured by the flags below.
+package main
+
+import (
+	"flag"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+	"github.com/pc-coder/tree-code-chunker/remote"
+	"google.golang.org/grpc"
+)
+
+func main() {
+	defaults := codechunk.DefaultBatchOptions()
+
+	addr := flag.String("addr", ":50051", "address to listen on")
+	flag.IntVar(&defaults.Concurrency, "concurrency", defaults.Concurrency, "max files chunked concurrently per batch request")
+	flag.IntVar(&defaults.MaxChunkSize, "max-chunk-size", defaults.MaxChunkSize, "default maximum chunk size in NWS characters")
+	flag.IntVar(&defaults.MinChunkSize, "min-chunk-size", defaults.MinChunkSize, "default minimum chunk size in NWS characters (0 disables merging)")
+	flag.IntVar(&defaults.OverlapLines, "overlap-lines", defaults.OverlapLines, "default lines of overlap between chunks")
+	contextMode := flag.String("context-mode", string(defaults.ContextMode), "default context mode (none, minimal, full)")
+	strategy := flag.String("chunk-strategy", string(codechunk.ChunkStrategyGreedy), "default chunk strategy (greedy, entity)")
+	maxMsgSize := flag.Int("max-msg-size", 64<<20, "max request/response message size in bytes")
+	skip := flag.Bool("skip", false, "skip binary, generated, minified and oversized files in batch requests by default")
+	skipBarrels := flag.Bool("skip-barrels", false, "skip JavaScript and TypeScript barrel files in batch requests by default")
+	flag.Parse()
+
+	defaults.ContextMode = codechunk.ContextMode(*contextMode)
+	defaults.ChunkStrategy = codechunk.ChunkStrategy(*strategy)
+	if *skip {
+		defaults.SkipPolicy = codechunk.DefaultSkipPolicy()
+	}
+	defaults.SkipPolicy.Barrel = *skipBarrels
+
+	lis, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("listen: %v", err)
+	}
+
+	srv := grpc.NewServer(
+		grpc.MaxRecvMsgSize(*maxMsgSize),
+		grpc.MaxSendMsgSize(*maxMsgSize),
+	)
+	remote.NewServer(&defaults).Register(srv)
+
+	go func() {
+		sig := make(chan os.Signal, 1)
+		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		<-sig
+		log.Printf("shutting down")
+		srv.GracefulStop()
+	}()
+
+	log.Printf("codechunkd listening on %s", lis.Addr())
+	if err := srv.Serve(lis); err != nil {
+		log.Fatalf("serve: %v", err)
+	}
+}
@@ -0,0 +1,652 @@
+package remote
+
+import (
+	codechunk "github.com/pc-coder/tree-code-chunker"
+	codechunkv1 "github.com/pc-coder/tree-code-chunker/remote/proto/codechunk/v1"
+)
+
+// optionsToProto converts chunk options to their protobuf form
+func optionsToProto(opts *codechunk.ChunkOptions) *codechunkv1.ChunkOptions {
+	if opts == nil {
+		return nil
+	}
+	return &codechunkv1.ChunkOptions{
+		MaxChunkSize:           int32(opts.MaxChunkSize),
+		ContextMode:            string(opts.ContextMode),
+		SiblingDetail:          string(opts.SiblingDetail),
+		FilterImports:          opts.FilterImports,
+		Language:               string(opts.Language),
+		OverlapLines:           int32(opts.OverlapLines),
+		CommentPrefix:          opts.CommentPrefix,
+		IncludeImportsInText:   opts.IncludeImportsInText,
+		MaxSiblingSignatureLen: int32(opts.MaxSiblingSignatureLen),
+		MaxSiblings:            int32(opts.MaxSiblings),
+		OverlapMode:            string(opts.OverlapMode),
+		DefaultsApplied:        opts.DefaultsApplied,
+		MinChunkSize:           int32(opts.MinChunkSize),
+		BoundaryHeuristics:     opts.BoundaryHeuristics,
+		SplitClasses:           opts.SplitClasses,
+		ChunkStrategy:          string(opts.ChunkStrategy),
+		SlidingWindowFallback:  opts.SlidingWindowFallback,
+		Hierarchical:           opts.Hierarchical,
+		StripBoilerplate:       opts.StripBoilerplate,
+		BoilerplatePatterns:    opts.BoilerplatePatterns,
+		MaxContextHeaderSize:   int32(opts.MaxContextHeaderSize),
+		ContextualizedTextMode: string(opts.ContextualizedTextMode),
+		Parallelism:            int32(opts.Parallelism),
+		MaxParseErrorRatio:     opts.MaxParseErrorRatio,
+		MaxFileSize:            int32(opts.MaxFileSize),
+		MaxChunksPerFile:       int32(opts.MaxChunksPerFile),
+		TruncateOnLimit:        opts.TruncateOnLimit,
+		SkipTrivialChunks:      opts.SkipTrivialChunks,
+		AnonymousNaming:        string(opts.AnonymousNaming),
+		Provenance:             provenanceToProto(opts.Provenance),
+		LazyText:               opts.LazyText,
+	}
+}
+
+// optionsFromProto converts protobuf chunk options, returning nil for nil input
+func optionsFromProto(opts *codechunkv1.ChunkOptions) *codechunk.ChunkOptions {
+	if opts == nil {
+		return nil
+	}
+	return &codechunk.ChunkOptions{
+		MaxChunkSize:           int(opts.MaxChunkSize),
+		ContextMode:            codechunk.ContextMode(opts.ContextMode),
+		SiblingDetail:          codechunk.SiblingDetail(opts.SiblingDetail),
+		FilterImports:          opts.FilterImports,
+		Language:               codechunk.Language(opts.Language),
+		OverlapLines:           int(opts.OverlapLines),
+		CommentPrefix:          opts.CommentPrefix,
+		IncludeImportsInText:   opts.IncludeImportsInText,
+		MaxSiblingSignatureLen: int(opts.MaxSiblingSignatureLen),
+		MaxSiblings:            int(opts.MaxSiblings),
+		OverlapMode:            codechunk.OverlapMode(opts.OverlapMode),
+		DefaultsApplied:        opts.DefaultsApplied,
+		MinChunkSize:           int(opts.MinChunkSize),
+		BoundaryHeuristics:     opts.BoundaryHeuristics,
+		SplitClasses:           opts.SplitClasses,
+		ChunkStrategy:          codechunk.ChunkStrategy(opts.ChunkStrategy),
+		SlidingWindowFallback:  opts.SlidingWindowFallback,
+		Hierarchical:           opts.Hierarchical,
+		StripBoilerplate:       opts.StripBoilerplate,
+		BoilerplatePatterns:    opts.BoilerplatePatterns,
+		MaxContextHeaderSize:   int(opts.MaxContextHeaderSize),
+		ContextualizedTextMode: codechunk.ContextualizedTextMode(opts.ContextualizedTextMode),
+		Parallelism:            int(opts.Parallelism),
+		MaxParseErrorRatio:     opts.MaxParseErrorRatio,
+		MaxFileSize:            int(opts.MaxFileSize),
+		MaxChunksPerFile:       int(opts.MaxChunksPerFile),
+		TruncateOnLimit:        opts.TruncateOnLimit,
+		SkipTrivialChunks:      opts.SkipTrivialChunks,
+		AnonymousNaming:        codechunk.AnonymousNaming(opts.AnonymousNaming),
+		Provenance:             provenanceFromProto(opts.GetProvenance()),
+		LazyText:               opts.LazyText,
+	}
+}
+
+// skipPolicyToProto converts a skip policy to its protobuf form, returning nil for
+// the zero policy
+func skipPolicyToProto(policy codechunk.SkipPolicy) *codechunkv1.SkipPolicy {
+	if policy == (codechunk.SkipPolicy{}) {
+		return nil
+	}
+	return &codechunkv1.SkipPolicy{
+		Binary:      policy.Binary,
+		Generated:   policy.Generated,
+		Minified:    policy.Minified,
+		MaxFileSize: int64(policy.MaxFileSize),
+		Barrel:      policy.Barrel,
+	}
+}
+
+// skipPolicyFromProto converts a protobuf skip policy, returning nil for nil input
+func skipPolicyFromProto(policy *codechunkv1.SkipPolicy) *codechunk.SkipPolicy {
+	if policy == nil {
+		return nil
+	}
+	return &codechunk.SkipPolicy{
+		Binary:      policy.GetBinary(),
+		Generated:   policy.GetGenerated(),
+		Minified:    policy.GetMinified(),
+		MaxFileSize: int(policy.GetMaxFileSize()),
+		Barrel:      policy.GetBarrel(),
+	}
+}
+
+// fileToProto converts a file input to its protobuf form
+func fileToProto(file codechunk.FileInput) *codechunkv1.FileInput {
+	return &codechunkv1.FileInput{
+		Filepath:   file.Filepath,
+		Code:       file.Code,
+		Options:    optionsToProto(file.Options),
+		Provenance: provenanceToProto(file.Provenance),
+	}
+}
+
+// fileFromProto converts a protobuf file input
+func fileFromProto(file *codechunkv1.FileInput) codechunk.FileInput {
+	return codechunk.FileInput{
+		Filepath:   file.GetFilepath(),
+		Code:       file.GetCode(),
+		Options:    optionsFromProto(file.GetOptions()),
+		Provenance: provenanceFromProto(file.GetProvenance()),
+	}
+}
+
+// provenanceToProto converts provenance to its protobuf form, returning nil for nil input
+func provenanceToProto(p *codechunk.Provenance) *codechunkv1.Provenance {
+	if p == nil {
+		return nil
+	}
+	return &codechunkv1.Provenance{
+		RepoRoot:  p.RepoRoot,
+		RelPath:   p.RelPath,
+		CommitSha: p.CommitSHA,
+		Branch:    p.Branch,
+	}
+}
+
+// provenanceFromProto converts protobuf provenance, returning nil for nil input
+func provenanceFromProto(p *codechunkv1.Provenance) *codechunk.Provenance {
+	if p == nil {
+		return nil
+	}
+	return &codechunk.Provenance{
+		RepoRoot:  p.GetRepoRoot(),
+		RelPath:   p.GetRelPath(),
+		CommitSHA: p.GetCommitSha(),
+		Branch:    p.GetBranch(),
+	}
+}
+
+// resultToProto converts a batch result to its protobuf form
+func resultToProto(result codechunk.BatchResult) *codechunkv1.BatchResult {
+	out := &codechunkv1.BatchResult{
+		Filepath:  result.Filepath,
+		Chunks:    chunksToProto(result.Chunks),
+		ErrorCode: codechunk.ErrorCode(result.Error),
+		Skipped:   result.Skipped,
+	}
+	if result.Error != nil {
+		out.Error = result.Error.Error()
+	}
+	return out
+}
+
+// resultFromProto converts a protobuf batch result
+func resultFromProto(result *codechunkv1.BatchResult) codechunk.BatchResult {
+	return codechunk.BatchResult{
+		Filepath: result.GetFilepath(),
+		Chunks:   chunksFromProto(result.GetChunks()),
+		Error:    codechunk.ErrorFromCode(result.GetErrorCode(), result.GetError()),
+		Skipped:  result.GetSkipped(),
+	}
+}
+
+func chunksToProto(chunks []codechunk.CodeChunk) []*codechunkv1.CodeChunk {
+	if chunks == nil {
+		return nil
+	}
+	out := make([]*codechunkv1.CodeChunk, len(chunks))
+	for i, c := range chunks {
+		out[i] = chunkToProto(c)
+	}
+	return out
+}
+
+func chunksFromProto(chunks []*codechunkv1.CodeChunk) []codechunk.CodeChunk {
+	if chunks == nil {
+		return nil
+	}
+	out := make([]codechunk.CodeChunk, len(chunks))
+	for i, c := range chunks {
+		out[i] = chunkFromProto(c)
+	}
+	return out
+}
+
+// chunkToProto converts a chunk to its protobuf form
+func chunkToProto(c codechunk.CodeChunk) *codechunkv1.CodeChunk {
+	c = c.Materialize()
+	return &codechunkv1.CodeChunk{
+		Id:                 c.ID,
+		ParentChunkId:      c.ParentChunkID,
+		ChildChunkIds:      c.ChildChunkIDs,
+		Level:              int32(c.Level),
+		PrevChunkId:        c.PrevChunkID,
+		NextChunkId:        c.NextChunkID,
+		ContentHash:        c.ContentHash,
+		Occurrences:        occurrencesToProto(c.Occurrences),
+		QualifiedNames:     c.QualifiedNames,
+		ContextHeader:      c.ContextHeader,
+		Truncated:          c.Truncated,
+		Kind:               string(c.Kind),
+		Text:               c.Content(),
+		ContextualizedText: c.Contextualized(),
+		ByteRange:          &codechunkv1.ByteRange{Start: int32(c.ByteRange.Start), End: int32(c.ByteRange.End)},
+		LineRange:          lineRangeToProto(&c.LineRange),
+		Span:               spanToProto(&c.Span),
+		Stats:              statsToProto(c.Stats),
+		Context:            contextToProto(c.Context),
+		Index:              int32(c.Index),
+		TotalChunks:        int32(c.TotalChunks),
+	}
+}
+
+// chunkFromProto converts a protobuf chunk
+func chunkFromProto(c *codechunkv1.CodeChunk) codechunk.CodeChunk {
+	chunk := codechunk.CodeChunk{
+		ID:                 c.GetId(),
+		ParentChunkID:      c.GetParentChunkId(),
+		ChildChunkIDs:      c.GetChildChunkIds(),
+		Level:              int(c.GetLevel()),
+		PrevChunkID:        c.GetPrevChunkId(),
+		NextChunkID:        c.GetNextChunkId(),
+		ContentHash:        c.GetContentHash(),
+		Occurrences:        occurrencesFromProto(c.GetOccurrences()),
+		QualifiedNames:     c.GetQualifiedNames(),
+		ContextHeader:      c.GetContextHeader(),
+		Truncated:          c.GetTruncated(),
+		Kind:               codechunk.ChunkKind(c.GetKind()),
+		Text:               c.GetText(),
+		ContextualizedText: c.GetContextualizedText(),
+		ByteRange: codechunk.ByteRange{
+			Start: int(c.GetByteRange().GetStart()),
+			End:   int(c.GetByteRange().GetEnd()),
+		},
+		Stats:       statsFromProto(c.GetStats()),
+		Context:     contextFromProto(c.GetContext()),
+		Index:       int(c.GetIndex()),
+		TotalChunks: int(c.GetTotalChunks()),
+	}
+	if lr := lineRangeFromProto(c.GetLineRange()); lr != nil {
+		chunk.LineRange = *lr
+	}
+	if span := spanFromProto(c.GetSpan()); span != nil {
+		chunk.Span = *span
+	}
+	return chunk
+}
+
+func occurrencesToProto(occurrences []codechunk.ChunkOccurrence) []*codechunkv1.ChunkOccurrence {
+	if occurrences == nil {
+		return nil
+	}
+	out := make([]*codechunkv1.ChunkOccurrence, len(occurrences))
+	for i, o := range occurrences {
+		out[i] = &codechunkv1.ChunkOccurrence{
+			Filepath:  o.Filepath,
+			ChunkId:   o.ChunkID,
+			ByteRange: &codechunkv1.ByteRange{Start: int32(o.ByteRange.Start), End: int32(o.ByteRange.End)},
+			LineRange: lineRangeToProto(&o.LineRange),
+		}
+	}
+	return out
+}
+
+func occurrencesFromProto(occurrences []*codechunkv1.ChunkOccurrence) []codechunk.ChunkOccurrence {
+	if occurrences == nil {
+		return nil
+	}
+	out := make([]codechunk.ChunkOccurrence, len(occurrences))
+	for i, o := range occurrences {
+		out[i] = codechunk.ChunkOccurrence{
+			Filepath: o.GetFilepath(),
+			ChunkID:  o.GetChunkId(),
+			ByteRange: codechunk.ByteRange{
+				Start: int(o.GetByteRange().GetStart()),
+				End:   int(o.GetByteRange().GetEnd()),
+			},
+		}
+		if lr := lineRangeFromProto(o.GetLineRange()); lr != nil {
+			out[i].LineRange = *lr
+		}
+	}
+	return out
+}
+
+func paramsToProto(params []codechunk.Param) []*codechunkv1.Param {
+	if params == nil {
+		return nil
+	}
+	out := make([]*codechunkv1.Param, len(params))
+	for i, p := range params {
+		out[i] = &codechunkv1.Param{Name: p.Name, Type: p.Type}
+	}
+	return out
+}
+
+func paramsFromProto(params []*codechunkv1.Param) []codechunk.Param {
+	if params == nil {
+		return nil
+	}
+	out := make([]codechunk.Param, len(params))
+	for i, p := range params {
+		out[i] = codechunk.Param{Name: p.GetName(), Type: p.GetType()}
+	}
+	return out
+}
+
+func typeParamsToProto(params []codechunk.TypeParam) []*codechunkv1.TypeParam {
+	if params == nil {
+		return nil
+	}
+	out := make([]*codechunkv1.TypeParam, len(params))
+	for i, p := range params {
+		out[i] = &codechunkv1.TypeParam{Name: p.Name, Constraint: p.Constraint}
+	}
+	return out
+}
+
+func typeParamsFromProto(params []*codechunkv1.TypeParam) []codechunk.TypeParam {
+	if params == nil {
+		return nil
+	}
+	out := make([]codechunk.TypeParam, len(params))
+	for i, p := range params {
+		out[i] = codechunk.TypeParam{Name: p.GetName(), Constraint: p.GetConstraint()}
+	}
+	return out
+}
+
+func statsToProto(s codechunk.ChunkStats) *codechunkv1.ChunkStats {
+	return &codechunkv1.ChunkStats{
+		Bytes:           int32(s.Bytes),
+		NwsBytes:        int32(s.NwsBytes),
+		Lines:           int32(s.Lines),
+		EstimatedTokens: int32(s.EstimatedTokens),
+	}
+}
+
+func statsFromProto(s *codechunkv1.ChunkStats) codechunk.ChunkStats {
+	return codechunk.ChunkStats{
+		Bytes:           int(s.GetBytes()),
+		NwsBytes:        int(s.GetNwsBytes()),
+		Lines:           int(s.GetLines()),
+		EstimatedTokens: int(s.GetEstimatedTokens()),
+	}
+}
+
+func lineRangeToProto(lr *codechunk.LineRange) *codechunkv1.LineRange {
+	if lr == nil {
+		return nil
+	}
+	return &codechunkv1.LineRange{Start: int32(lr.Start), End: int32(lr.End)}
+}
+
+func lineRangeFromProto(lr *codechunkv1.LineRange) *codechunk.LineRange {
+	if lr == nil {
+		return nil
+	}
+	return &codechunk.LineRange{Start: int(lr.GetStart()), End: int(lr.GetEnd())}
+}
+
+func positionToProto(p codechunk.Position) *codechunkv1.Position {
+	return &codechunkv1.Position{
+		Line:        int32(p.Line),
+		Column:      int32(p.Column),
+		RuneColumn:  int32(p.RuneColumn),
+		Utf16Column: int32(p.UTF16Column),
+	}
+}
+
+func positionFromProto(p *codechunkv1.Position) codechunk.Position {
+	return codechunk.Position{
+		Line:        int(p.GetLine()),
+		Column:      int(p.GetColumn()),
+		RuneColumn:  int(p.GetRuneColumn()),
+		UTF16Column: int(p.GetUtf16Column()),
+	}
+}
+
+func spanToProto(span *codechunk.Span) *codechunkv1.Span {
+	if span == nil {
+		return nil
+	}
+	return &codechunkv1.Span{Start: positionToProto(span.Start), End: positionToProto(span.End)}
+}
+
+func spanFromProto(span *codechunkv1.Span) *codechunk.Span {
+	if span == nil {
+		return nil
+	}
+	return &codechunk.Span{Start: positionFromProto(span.GetStart()), End: positionFromProto(span.GetEnd())}
+}
+
+func contextToProto(ctx codechunk.ChunkContext) *codechunkv1.ChunkContext {
+	out := &codechunkv1.ChunkContext{
+		Filepath:       ctx.Filepath,
+		Language:       string(ctx.Language),
+		SlidingWindow:  ctx.SlidingWindow,
+		FileDoc:        ctx.FileDoc,
+		Classification: string(ctx.Classification),
+		TestedSymbols:  ctx.TestedSymbols,
+		Provenance:     provenanceToProto(ctx.Provenance),
+	}
+	for _, s := range ctx.Scope {
+		out.Scope = append(out.Scope, &codechunkv1.EntityInfo{
+			Name:      s.Name,
+			Type:      string(s.Type),
+			Signature: s.Signature,
+		})
+	}
+	for _, e := range ctx.Entities {
+		out.Entities = append(out.Entities, &codechunkv1.ChunkEntityInfo{
+			Name:           e.Name,
+			Type:           string(e.Type),
+			Signature:      e.Signature,
+			Docstring:      e.Docstring,
+			LineRange:      lineRangeToProto(e.LineRange),
+			Span:           spanToProto(e.Span),
+			IsPartial:      e.IsPartial,
+			Visibility:     string(e.Visibility),
+			Annotations:    e.Annotations,
+			Modifiers:      e.Modifiers,
+			Parameters:     paramsToProto(e.Parameters),
+			ReturnType:     e.ReturnType,
+			TypeParameters: typeParamsToProto(e.TypeParameters),
+			QualifiedName:  e.QualifiedName,
+			PropsType:      e.PropsType,
+			Overloads:      e.Overloads,
+		})
+	}
+	for _, s := range ctx.Siblings {
+		out.Siblings = append(out.Siblings, &codechunkv1.SiblingInfo{
+			Name:      s.Name,
+			Type:      string(s.Type),
+			Position:  s.Position,
+			Distance:  int32(s.Distance),
+			Signature: s.Signature,
+		})
+	}
+	for _, imp := range ctx.Imports {
+		out.Imports = append(out.Imports, &codechunkv1.ImportInfo{
+			Name:           imp.Name,
+			Source:         imp.Source,
+			IsDefault:      imp.IsDefault,
+			IsNamespace:    imp.IsNamespace,
+			OriginalName:   imp.OriginalName,
+			Level:          int32(imp.Level),
+			ResolvedSource: imp.ResolvedSource,
+		})
+	}
+	for _, ref := range ctx.References {
+		out.References = append(out.References, &codechunkv1.ReferenceInfo{
+			Name:      ref.Name,
+			Signature: ref.Signature,
+			Line:      int32(ref.Line),
+		})
+	}
+	for _, tag := range ctx.Tags {
+		out.Tags = append(out.Tags, &codechunkv1.CommentTag{
+			Tag:   tag.Tag,
+			Owner: tag.Owner,
+			Text:  tag.Text,
+			Line:  int32(tag.Line),
+		})
+	}
+	if ctx.ParseError != nil {
+		out.ParseError = &codechunkv1.ParseError{
+			Message:     ctx.ParseError.Message,
+			Recoverable: ctx.ParseError.Recoverable,
+			Count:       int32(ctx.ParseError.Count),
+		}
+		for _, r := range ctx.ParseError.Ranges {
+			out.ParseError.Ranges = append(out.ParseError.Ranges, &codechunkv1.ByteRange{Start: int32(r.Start), End: int32(r.End)})
+		}
+	}
+	if ctx.Cell != nil {
+		out.Cell = &codechunkv1.NotebookCell{
+			Index:    int32(ctx.Cell.Index),
+			Id:       ctx.Cell.ID,
+			CellType: ctx.Cell.CellType,
+		}
+		if ctx.Cell.ExecutionCount != nil {
+			count := int32(*ctx.Cell.ExecutionCount)
+			out.Cell.ExecutionCount = &count
+		}
+	}
+	if ctx.Build != nil {
+		out.Build = &codechunkv1.BuildConstraints{
+			Expr:   ctx.Build.Expr,
+			Goos:   ctx.Build.GOOS,
+			Goarch: ctx.Build.GOARCH,
+		}
+	}
+	if ctx.Boilerplate != nil {
+		out.Boilerplate = &codechunkv1.BoilerplateInfo{
+			LineRange: lineRangeToProto(&ctx.Boilerplate.LineRange),
+			License:   ctx.Boilerplate.License,
+		}
+	}
+	if ctx.Class != nil {
+		out.Class = &codechunkv1.ClassContext{
+			Name:      ctx.Class.Name,
+			Signature: ctx.Class.Signature,
+			Fields:    ctx.Class.Fields,
+		}
+	}
+	return out
+}
+
+func contextFromProto(ctx *codechunkv1.ChunkContext) codechunk.ChunkContext {
+	out := codechunk.ChunkContext{
+		Filepath:       ctx.GetFilepath(),
+		Language:       codechunk.Language(ctx.GetLanguage()),
+		Scope:          make([]codechunk.EntityInfo, 0, len(ctx.GetScope())),
+		Entities:       make([]codechunk.ChunkEntityInfo, 0, len(ctx.GetEntities())),
+		Siblings:       make([]codechunk.SiblingInfo, 0, len(ctx.GetSiblings())),
+		Imports:        make([]codechunk.ImportInfo, 0, len(ctx.GetImports())),
+		References:     make([]codechunk.ReferenceInfo, 0, len(ctx.GetReferences())),
+		SlidingWindow:  ctx.GetSlidingWindow(),
+		FileDoc:        ctx.GetFileDoc(),
+		Classification: codechunk.Classification(ctx.GetClassification()),
+		TestedSymbols:  ctx.GetTestedSymbols(),
+		Provenance:     provenanceFromProto(ctx.GetProvenance()),
+	}
+	for _, s := range ctx.GetScope() {
+		out.Scope = append(out.Scope, codechunk.EntityInfo{
+			Name:      s.GetName(),
+			Type:      codechunk.EntityType(s.GetType()),
+			Signature: s.GetSignature(),
+		})
+	}
+	for _, e := range ctx.GetEntities() {
+		out.Entities = append(out.Entities, codechunk.ChunkEntityInfo{
+			Name:           e.GetName(),
+			Type:           codechunk.EntityType(e.GetType()),
+			Signature:      e.GetSignature(),
+			Docstring:      e.Docstring,
+			LineRange:      lineRangeFromProto(e.GetLineRange()),
+			Span:           spanFromProto(e.GetSpan()),
+			IsPartial:      e.GetIsPartial(),
+			Visibility:     codechunk.Visibility(e.GetVisibility()),
+			Annotations:    e.GetAnnotations(),
+			Modifiers:      e.GetModifiers(),
+			Parameters:     paramsFromProto(e.GetParameters()),
+			ReturnType:     e.GetReturnType(),
+			TypeParameters: typeParamsFromProto(e.GetTypeParameters()),
+			QualifiedName:  e.GetQualifiedName(),
+			PropsType:      e.GetPropsType(),
+			Overloads:      e.GetOverloads(),
+		})
+	}
+	for _, s := range ctx.GetSiblings() {
+		out.Siblings = append(out.Siblings, codechunk.SiblingInfo{
+			Name:      s.GetName(),
+			Type:      codechunk.EntityType(s.GetType()),
+			Position:  s.GetPosition(),
+			Distance:  int(s.GetDistance()),
+			Signature: s.GetSignature(),
+		})
+	}
+	for _, imp := range ctx.GetImports() {
+		out.Imports = append(out.Imports, codechunk.ImportInfo{
+			Name:           imp.GetName(),
+			Source:         imp.GetSource(),
+			IsDefault:      imp.GetIsDefault(),
+			IsNamespace:    imp.GetIsNamespace(),
+			OriginalName:   imp.GetOriginalName(),
+			Level:          int(imp.GetLevel()),
+			ResolvedSource: imp.GetResolvedSource(),
+		})
+	}
+	for _, ref := range ctx.GetReferences() {
+		out.References = append(out.References, codechunk.ReferenceInfo{
+			Name:      ref.GetName(),
+			Signature: ref.GetSignature(),
+			Line:      int(ref.GetLine()),
+		})
+	}
+	for _, tag := range ctx.GetTags() {
+		out.Tags = append(out.Tags, codechunk.CommentTag{
+			Tag:   tag.GetTag(),
+			Owner: tag.GetOwner(),
+			Text:  tag.GetText(),
+			Line:  int(tag.GetLine()),
+		})
+	}
+	if pe := ctx.GetParseError(); pe != nil {
+		out.ParseError = &codechunk.ParseError{
+			Message:     pe.GetMessage(),
+			Recoverable: pe.GetRecoverable(),
+			Count:       int(pe.GetCount()),
+		}
+		for _, r := range pe.GetRanges() {
+			out.ParseError.Ranges = append(out.ParseError.Ranges, codechunk.ByteRange{Start: int(r.GetStart()), End: int(r.GetEnd())})
+		}
+	}
+	if cell := ctx.GetCell(); cell != nil {
+		out.Cell = &codechunk.NotebookCell{
+			Index:    int(cell.GetIndex()),
+			ID:       cell.GetId(),
+			CellType: cell.GetCellType(),
+		}
+		if cell.ExecutionCount != nil {
+			count := int(cell.GetExecutionCount())
+			out.Cell.ExecutionCount = &count
+		}
+	}
+	if build := ctx.GetBuild(); build != nil {
+		out.Build = &codechunk.BuildConstraints{
+			Expr:   build.GetExpr(),
+			GOOS:   build.GetGoos(),
+			GOARCH: build.GetGoarch(),
+		}
+	}
+	if boilerplate := ctx.GetBoilerplate(); boilerplate != nil {
+		out.Boilerplate = &codechunk.BoilerplateInfo{License: boilerplate.GetLicense()}
+		if lr := lineRangeFromProto(boilerplate.GetLineRange()); lr != nil {
+			out.Boilerplate.LineRange = *lr
+		}
+	}
+	if class := ctx.GetClass(); class != nil {
+		out.Class = &codechunk.ClassContext{
+			Name:      class.GetName(),
+			Signature: class.GetSignature(),
+			Fields:    class.GetFields(),
+		}
+	}
+	return out
+}
@@ -0,0 +1,19 @@
+module github.com/pc-coder/tree-code-chunker/remote
+
+go 1.23.1
+
+require (
+	github.com/pc-coder/tree-code-chunker v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.71.1
+	google.golang.org/protobuf v1.36.4
+)
+
+require (
+	github.com/smacker/go-tree-sitter v0.0.0-20240827094217-dd81d9e9be82 // indirect
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f // indirect
+)
+
+replace github.com/pc-coder/tree-code-chunker => ../
@@ -0,0 +1,3015 @@
+// Protocol definition for running codechunk as a remote service.
+//
+// The messages mirror the Go types of github.com/pc-coder/tree-code-chunker
+// field for field; see the Go documentation for the semantics of each field.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.4
+// 	protoc        v5.29.3
+// source: codechunk/v1/codechunk.proto
+
+package codechunkv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type ChunkOptions struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	MaxChunkSize           int32                  `protobuf:"varint,1,opt,name=max_chunk_size,json=maxChunkSize,proto3" json:"max_chunk_size,omitempty"`
+	ContextMode            string                 `protobuf:"bytes,2,opt,name=context_mode,json=contextMode,proto3" json:"context_mode,omitempty"`
+	SiblingDetail          string                 `protobuf:"bytes,3,opt,name=sibling_detail,json=siblingDetail,proto3" json:"sibling_detail,omitempty"`
+	FilterImports          bool                   `protobuf:"varint,4,opt,name=filter_imports,json=filterImports,proto3" json:"filter_imports,omitempty"`
+	Language               string                 `protobuf:"bytes,5,opt,name=language,proto3" json:"language,omitempty"`
+	OverlapLines           int32                  `protobuf:"varint,6,opt,name=overlap_lines,json=overlapLines,proto3" json:"overlap_lines,omitempty"`
+	CommentPrefix          string                 `protobuf:"bytes,7,opt,name=comment_prefix,json=commentPrefix,proto3" json:"comment_prefix,omitempty"`
+	IncludeImportsInText   bool                   `protobuf:"varint,8,opt,name=include_imports_in_text,json=includeImportsInText,proto3" json:"include_imports_in_text,omitempty"`
+	MaxSiblingSignatureLen int32                  `protobuf:"varint,9,opt,name=max_sibling_signature_len,json=maxSiblingSignatureLen,proto3" json:"max_sibling_signature_len,omitempty"`
+	MaxSiblings            int32                  `protobuf:"varint,10,opt,name=max_siblings,json=maxSiblings,proto3" json:"max_siblings,omitempty"`
+	OverlapMode            string                 `protobuf:"bytes,11,opt,name=overlap_mode,json=overlapMode,proto3" json:"overlap_mode,omitempty"`
+	DefaultsApplied        bool                   `protobuf:"varint,12,opt,name=defaults_applied,json=defaultsApplied,proto3" json:"defaults_applied,omitempty"`
+	MinChunkSize           int32                  `protobuf:"varint,13,opt,name=min_chunk_size,json=minChunkSize,proto3" json:"min_chunk_size,omitempty"`
+	BoundaryHeuristics     bool                   `protobuf:"varint,14,opt,name=boundary_heuristics,json=boundaryHeuristics,proto3" json:"boundary_heuristics,omitempty"`
+	SplitClasses           bool                   `protobuf:"varint,15,opt,name=split_classes,json=splitClasses,proto3" json:"split_classes,omitempty"`
+	ChunkStrategy          string                 `protobuf:"bytes,16,opt,name=chunk_strategy,json=chunkStrategy,proto3" json:"chunk_strategy,omitempty"`
+	SlidingWindowFallback  bool                   `protobuf:"varint,17,opt,name=sliding_window_fallback,json=slidingWindowFallback,proto3" json:"sliding_window_fallback,omitempty"`
+	Hierarchical           bool                   `protobuf:"varint,18,opt,name=hierarchical,proto3" json:"hierarchical,omitempty"`
+	StripBoilerplate       bool                   `protobuf:"varint,19,opt,name=strip_boilerplate,json=stripBoilerplate,proto3" json:"strip_boilerplate,omitempty"`
+	BoilerplatePatterns    []string               `protobuf:"bytes,20,rep,name=boilerplate_patterns,json=boilerplatePatterns,proto3" json:"boilerplate_patterns,omitempty"`
+	MaxContextHeaderSize   int32                  `protobuf:"varint,21,opt,name=max_context_header_size,json=maxContextHeaderSize,proto3" json:"max_context_header_size,omitempty"`
+	ContextualizedTextMode string                 `protobuf:"bytes,22,opt,name=contextualized_text_mode,json=contextualizedTextMode,proto3" json:"contextualized_text_mode,omitempty"`
+	Parallelism            int32                  `protobuf:"varint,23,opt,name=parallelism,proto3" json:"parallelism,omitempty"`
+	MaxParseErrorRatio     float64                `protobuf:"fixed64,24,opt,name=max_parse_error_ratio,json=maxParseErrorRatio,proto3" json:"max_parse_error_ratio,omitempty"`
+	MaxFileSize            int32                  `protobuf:"varint,25,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	MaxChunksPerFile       int32                  `protobuf:"varint,26,opt,name=max_chunks_per_file,json=maxChunksPerFile,proto3" json:"max_chunks_per_file,omitempty"`
+	TruncateOnLimit        bool                   `protobuf:"varint,27,opt,name=truncate_on_limit,json=truncateOnLimit,proto3" json:"truncate_on_limit,omitempty"`
+	SkipTrivialChunks      bool                   `protobuf:"varint,28,opt,name=skip_trivial_chunks,json=skipTrivialChunks,proto3" json:"skip_trivial_chunks,omitempty"`
+	AnonymousNaming        string                 `protobuf:"bytes,29,opt,name=anonymous_naming,json=anonymousNaming,proto3" json:"anonymous_naming,omitempty"`
+	Provenance             *Provenance            `protobuf:"bytes,30,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	// The server renders the text of each chunk as it is sent; received chunks
+	// carry their text.
+	LazyText      bool `protobuf:"varint,31,opt,name=lazy_text,json=lazyText,proto3" json:"lazy_text,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkOptions) Reset() {
+	*x = ChunkOptions{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkOptions) ProtoMessage() {}
+
+func (x *ChunkOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkOptions.ProtoReflect.Descriptor instead.
+func (*ChunkOptions) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ChunkOptions) GetMaxChunkSize() int32 {
+	if x != nil {
+		return x.MaxChunkSize
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetContextMode() string {
+	if x != nil {
+		return x.ContextMode
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetSiblingDetail() string {
+	if x != nil {
+		return x.SiblingDetail
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetFilterImports() bool {
+	if x != nil {
+		return x.FilterImports
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetOverlapLines() int32 {
+	if x != nil {
+		return x.OverlapLines
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetCommentPrefix() string {
+	if x != nil {
+		return x.CommentPrefix
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetIncludeImportsInText() bool {
+	if x != nil {
+		return x.IncludeImportsInText
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetMaxSiblingSignatureLen() int32 {
+	if x != nil {
+		return x.MaxSiblingSignatureLen
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetMaxSiblings() int32 {
+	if x != nil {
+		return x.MaxSiblings
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetOverlapMode() string {
+	if x != nil {
+		return x.OverlapMode
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetDefaultsApplied() bool {
+	if x != nil {
+		return x.DefaultsApplied
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetMinChunkSize() int32 {
+	if x != nil {
+		return x.MinChunkSize
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetBoundaryHeuristics() bool {
+	if x != nil {
+		return x.BoundaryHeuristics
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetSplitClasses() bool {
+	if x != nil {
+		return x.SplitClasses
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetChunkStrategy() string {
+	if x != nil {
+		return x.ChunkStrategy
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetSlidingWindowFallback() bool {
+	if x != nil {
+		return x.SlidingWindowFallback
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetHierarchical() bool {
+	if x != nil {
+		return x.Hierarchical
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetStripBoilerplate() bool {
+	if x != nil {
+		return x.StripBoilerplate
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetBoilerplatePatterns() []string {
+	if x != nil {
+		return x.BoilerplatePatterns
+	}
+	return nil
+}
+
+func (x *ChunkOptions) GetMaxContextHeaderSize() int32 {
+	if x != nil {
+		return x.MaxContextHeaderSize
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetContextualizedTextMode() string {
+	if x != nil {
+		return x.ContextualizedTextMode
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetParallelism() int32 {
+	if x != nil {
+		return x.Parallelism
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetMaxParseErrorRatio() float64 {
+	if x != nil {
+		return x.MaxParseErrorRatio
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetMaxFileSize() int32 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetMaxChunksPerFile() int32 {
+	if x != nil {
+		return x.MaxChunksPerFile
+	}
+	return 0
+}
+
+func (x *ChunkOptions) GetTruncateOnLimit() bool {
+	if x != nil {
+		return x.TruncateOnLimit
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetSkipTrivialChunks() bool {
+	if x != nil {
+		return x.SkipTrivialChunks
+	}
+	return false
+}
+
+func (x *ChunkOptions) GetAnonymousNaming() string {
+	if x != nil {
+		return x.AnonymousNaming
+	}
+	return ""
+}
+
+func (x *ChunkOptions) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+func (x *ChunkOptions) GetLazyText() bool {
+	if x != nil {
+		return x.LazyText
+	}
+	return false
+}
+
+type Provenance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RepoRoot      string                 `protobuf:"bytes,1,opt,name=repo_root,json=repoRoot,proto3" json:"repo_root,omitempty"`
+	RelPath       string                 `protobuf:"bytes,2,opt,name=rel_path,json=relPath,proto3" json:"rel_path,omitempty"`
+	CommitSha     string                 `protobuf:"bytes,3,opt,name=commit_sha,json=commitSha,proto3" json:"commit_sha,omitempty"`
+	Branch        string                 `protobuf:"bytes,4,opt,name=branch,proto3" json:"branch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Provenance) Reset() {
+	*x = Provenance{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Provenance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Provenance) ProtoMessage() {}
+
+func (x *Provenance) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Provenance.ProtoReflect.Descriptor instead.
+func (*Provenance) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Provenance) GetRepoRoot() string {
+	if x != nil {
+		return x.RepoRoot
+	}
+	return ""
+}
+
+func (x *Provenance) GetRelPath() string {
+	if x != nil {
+		return x.RelPath
+	}
+	return ""
+}
+
+func (x *Provenance) GetCommitSha() string {
+	if x != nil {
+		return x.CommitSha
+	}
+	return ""
+}
+
+func (x *Provenance) GetBranch() string {
+	if x != nil {
+		return x.Branch
+	}
+	return ""
+}
+
+type FileInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filepath      string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Options       *ChunkOptions          `protobuf:"bytes,3,opt,name=options,proto3" json:"options,omitempty"`
+	Provenance    *Provenance            `protobuf:"bytes,4,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileInput) Reset() {
+	*x = FileInput{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileInput) ProtoMessage() {}
+
+func (x *FileInput) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileInput.ProtoReflect.Descriptor instead.
+func (*FileInput) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileInput) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *FileInput) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *FileInput) GetOptions() *ChunkOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *FileInput) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+type ChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	File          *FileInput             `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkRequest) Reset() {
+	*x = ChunkRequest{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkRequest) ProtoMessage() {}
+
+func (x *ChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkRequest.ProtoReflect.Descriptor instead.
+func (*ChunkRequest) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ChunkRequest) GetFile() *FileInput {
+	if x != nil {
+		return x.File
+	}
+	return nil
+}
+
+type ChunkResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chunks        []*CodeChunk           `protobuf:"bytes,1,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkResponse) Reset() {
+	*x = ChunkResponse{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkResponse) ProtoMessage() {}
+
+func (x *ChunkResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkResponse.ProtoReflect.Descriptor instead.
+func (*ChunkResponse) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChunkResponse) GetChunks() []*CodeChunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+type SkipPolicy struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Binary        bool                   `protobuf:"varint,1,opt,name=binary,proto3" json:"binary,omitempty"`
+	Generated     bool                   `protobuf:"varint,2,opt,name=generated,proto3" json:"generated,omitempty"`
+	Minified      bool                   `protobuf:"varint,3,opt,name=minified,proto3" json:"minified,omitempty"`
+	MaxFileSize   int64                  `protobuf:"varint,4,opt,name=max_file_size,json=maxFileSize,proto3" json:"max_file_size,omitempty"`
+	Barrel        bool                   `protobuf:"varint,5,opt,name=barrel,proto3" json:"barrel,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkipPolicy) Reset() {
+	*x = SkipPolicy{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkipPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkipPolicy) ProtoMessage() {}
+
+func (x *SkipPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkipPolicy.ProtoReflect.Descriptor instead.
+func (*SkipPolicy) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *SkipPolicy) GetBinary() bool {
+	if x != nil {
+		return x.Binary
+	}
+	return false
+}
+
+func (x *SkipPolicy) GetGenerated() bool {
+	if x != nil {
+		return x.Generated
+	}
+	return false
+}
+
+func (x *SkipPolicy) GetMinified() bool {
+	if x != nil {
+		return x.Minified
+	}
+	return false
+}
+
+func (x *SkipPolicy) GetMaxFileSize() int64 {
+	if x != nil {
+		return x.MaxFileSize
+	}
+	return 0
+}
+
+func (x *SkipPolicy) GetBarrel() bool {
+	if x != nil {
+		return x.Barrel
+	}
+	return false
+}
+
+type ChunkBatchRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Files       []*FileInput           `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	Options     *ChunkOptions          `protobuf:"bytes,2,opt,name=options,proto3" json:"options,omitempty"`
+	Concurrency int32                  `protobuf:"varint,3,opt,name=concurrency,proto3" json:"concurrency,omitempty"`
+	SkipPolicy  *SkipPolicy            `protobuf:"bytes,4,opt,name=skip_policy,json=skipPolicy,proto3" json:"skip_policy,omitempty"`
+	Ordered     bool                   `protobuf:"varint,5,opt,name=ordered,proto3" json:"ordered,omitempty"`
+	// Results are deduplicated once the whole batch is chunked, so they are sent
+	// in input order after the last file completes.
+	Dedupe        bool `protobuf:"varint,6,opt,name=dedupe,proto3" json:"dedupe,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkBatchRequest) Reset() {
+	*x = ChunkBatchRequest{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkBatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkBatchRequest) ProtoMessage() {}
+
+func (x *ChunkBatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkBatchRequest.ProtoReflect.Descriptor instead.
+func (*ChunkBatchRequest) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ChunkBatchRequest) GetFiles() []*FileInput {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *ChunkBatchRequest) GetOptions() *ChunkOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ChunkBatchRequest) GetConcurrency() int32 {
+	if x != nil {
+		return x.Concurrency
+	}
+	return 0
+}
+
+func (x *ChunkBatchRequest) GetSkipPolicy() *SkipPolicy {
+	if x != nil {
+		return x.SkipPolicy
+	}
+	return nil
+}
+
+func (x *ChunkBatchRequest) GetOrdered() bool {
+	if x != nil {
+		return x.Ordered
+	}
+	return false
+}
+
+func (x *ChunkBatchRequest) GetDedupe() bool {
+	if x != nil {
+		return x.Dedupe
+	}
+	return false
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filepath      string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	Chunks        []*CodeChunk           `protobuf:"bytes,2,rep,name=chunks,proto3" json:"chunks,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	ErrorCode     string                 `protobuf:"bytes,4,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Skipped       bool                   `protobuf:"varint,5,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *BatchResult) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *BatchResult) GetChunks() []*CodeChunk {
+	if x != nil {
+		return x.Chunks
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BatchResult) GetErrorCode() string {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return ""
+}
+
+func (x *BatchResult) GetSkipped() bool {
+	if x != nil {
+		return x.Skipped
+	}
+	return false
+}
+
+type LineRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LineRange) Reset() {
+	*x = LineRange{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LineRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LineRange) ProtoMessage() {}
+
+func (x *LineRange) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LineRange.ProtoReflect.Descriptor instead.
+func (*LineRange) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *LineRange) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *LineRange) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type ByteRange struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         int32                  `protobuf:"varint,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           int32                  `protobuf:"varint,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ByteRange) Reset() {
+	*x = ByteRange{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ByteRange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ByteRange) ProtoMessage() {}
+
+func (x *ByteRange) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ByteRange.ProtoReflect.Descriptor instead.
+func (*ByteRange) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ByteRange) GetStart() int32 {
+	if x != nil {
+		return x.Start
+	}
+	return 0
+}
+
+func (x *ByteRange) GetEnd() int32 {
+	if x != nil {
+		return x.End
+	}
+	return 0
+}
+
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Line          int32                  `protobuf:"varint,1,opt,name=line,proto3" json:"line,omitempty"`
+	Column        int32                  `protobuf:"varint,2,opt,name=column,proto3" json:"column,omitempty"`
+	RuneColumn    int32                  `protobuf:"varint,3,opt,name=rune_column,json=runeColumn,proto3" json:"rune_column,omitempty"`
+	Utf16Column   int32                  `protobuf:"varint,4,opt,name=utf16_column,json=utf16Column,proto3" json:"utf16_column,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Position) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+func (x *Position) GetColumn() int32 {
+	if x != nil {
+		return x.Column
+	}
+	return 0
+}
+
+func (x *Position) GetRuneColumn() int32 {
+	if x != nil {
+		return x.RuneColumn
+	}
+	return 0
+}
+
+func (x *Position) GetUtf16Column() int32 {
+	if x != nil {
+		return x.Utf16Column
+	}
+	return 0
+}
+
+type Span struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Start         *Position              `protobuf:"bytes,1,opt,name=start,proto3" json:"start,omitempty"`
+	End           *Position              `protobuf:"bytes,2,opt,name=end,proto3" json:"end,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Span) Reset() {
+	*x = Span{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Span) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Span) ProtoMessage() {}
+
+func (x *Span) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Span.ProtoReflect.Descriptor instead.
+func (*Span) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Span) GetStart() *Position {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *Span) GetEnd() *Position {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+type ParseError struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Recoverable   bool                   `protobuf:"varint,2,opt,name=recoverable,proto3" json:"recoverable,omitempty"`
+	Ranges        []*ByteRange           `protobuf:"bytes,3,rep,name=ranges,proto3" json:"ranges,omitempty"`
+	Count         int32                  `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ParseError) Reset() {
+	*x = ParseError{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ParseError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ParseError) ProtoMessage() {}
+
+func (x *ParseError) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ParseError.ProtoReflect.Descriptor instead.
+func (*ParseError) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ParseError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *ParseError) GetRecoverable() bool {
+	if x != nil {
+		return x.Recoverable
+	}
+	return false
+}
+
+func (x *ParseError) GetRanges() []*ByteRange {
+	if x != nil {
+		return x.Ranges
+	}
+	return nil
+}
+
+func (x *ParseError) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type EntityInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Signature     string                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EntityInfo) Reset() {
+	*x = EntityInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EntityInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EntityInfo) ProtoMessage() {}
+
+func (x *EntityInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EntityInfo.ProtoReflect.Descriptor instead.
+func (*EntityInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *EntityInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *EntityInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *EntityInfo) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type ChunkEntityInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Signature      string                 `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
+	Docstring      *string                `protobuf:"bytes,4,opt,name=docstring,proto3,oneof" json:"docstring,omitempty"`
+	LineRange      *LineRange             `protobuf:"bytes,5,opt,name=line_range,json=lineRange,proto3" json:"line_range,omitempty"`
+	IsPartial      bool                   `protobuf:"varint,6,opt,name=is_partial,json=isPartial,proto3" json:"is_partial,omitempty"`
+	Span           *Span                  `protobuf:"bytes,7,opt,name=span,proto3" json:"span,omitempty"`
+	Visibility     string                 `protobuf:"bytes,8,opt,name=visibility,proto3" json:"visibility,omitempty"`
+	Annotations    []string               `protobuf:"bytes,9,rep,name=annotations,proto3" json:"annotations,omitempty"`
+	Modifiers      []string               `protobuf:"bytes,10,rep,name=modifiers,proto3" json:"modifiers,omitempty"`
+	Parameters     []*Param               `protobuf:"bytes,11,rep,name=parameters,proto3" json:"parameters,omitempty"`
+	ReturnType     string                 `protobuf:"bytes,12,opt,name=return_type,json=returnType,proto3" json:"return_type,omitempty"`
+	TypeParameters []*TypeParam           `protobuf:"bytes,13,rep,name=type_parameters,json=typeParameters,proto3" json:"type_parameters,omitempty"`
+	QualifiedName  string                 `protobuf:"bytes,14,opt,name=qualified_name,json=qualifiedName,proto3" json:"qualified_name,omitempty"`
+	PropsType      string                 `protobuf:"bytes,15,opt,name=props_type,json=propsType,proto3" json:"props_type,omitempty"`
+	Overloads      []string               `protobuf:"bytes,16,rep,name=overloads,proto3" json:"overloads,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChunkEntityInfo) Reset() {
+	*x = ChunkEntityInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkEntityInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkEntityInfo) ProtoMessage() {}
+
+func (x *ChunkEntityInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkEntityInfo.ProtoReflect.Descriptor instead.
+func (*ChunkEntityInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ChunkEntityInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetDocstring() string {
+	if x != nil && x.Docstring != nil {
+		return *x.Docstring
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetLineRange() *LineRange {
+	if x != nil {
+		return x.LineRange
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetIsPartial() bool {
+	if x != nil {
+		return x.IsPartial
+	}
+	return false
+}
+
+func (x *ChunkEntityInfo) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetVisibility() string {
+	if x != nil {
+		return x.Visibility
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetAnnotations() []string {
+	if x != nil {
+		return x.Annotations
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetModifiers() []string {
+	if x != nil {
+		return x.Modifiers
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetParameters() []*Param {
+	if x != nil {
+		return x.Parameters
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetReturnType() string {
+	if x != nil {
+		return x.ReturnType
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetTypeParameters() []*TypeParam {
+	if x != nil {
+		return x.TypeParameters
+	}
+	return nil
+}
+
+func (x *ChunkEntityInfo) GetQualifiedName() string {
+	if x != nil {
+		return x.QualifiedName
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetPropsType() string {
+	if x != nil {
+		return x.PropsType
+	}
+	return ""
+}
+
+func (x *ChunkEntityInfo) GetOverloads() []string {
+	if x != nil {
+		return x.Overloads
+	}
+	return nil
+}
+
+type Param struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Param) Reset() {
+	*x = Param{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Param) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Param) ProtoMessage() {}
+
+func (x *Param) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Param.ProtoReflect.Descriptor instead.
+func (*Param) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Param) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Param) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+type TypeParam struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Constraint    string                 `protobuf:"bytes,2,opt,name=constraint,proto3" json:"constraint,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TypeParam) Reset() {
+	*x = TypeParam{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TypeParam) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TypeParam) ProtoMessage() {}
+
+func (x *TypeParam) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TypeParam.ProtoReflect.Descriptor instead.
+func (*TypeParam) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *TypeParam) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TypeParam) GetConstraint() string {
+	if x != nil {
+		return x.Constraint
+	}
+	return ""
+}
+
+type SiblingInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Position      string                 `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Distance      int32                  `protobuf:"varint,4,opt,name=distance,proto3" json:"distance,omitempty"`
+	Signature     string                 `protobuf:"bytes,5,opt,name=signature,proto3" json:"signature,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SiblingInfo) Reset() {
+	*x = SiblingInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SiblingInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SiblingInfo) ProtoMessage() {}
+
+func (x *SiblingInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SiblingInfo.ProtoReflect.Descriptor instead.
+func (*SiblingInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *SiblingInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SiblingInfo) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *SiblingInfo) GetPosition() string {
+	if x != nil {
+		return x.Position
+	}
+	return ""
+}
+
+func (x *SiblingInfo) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *SiblingInfo) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+type ImportInfo struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Name           string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Source         string                 `protobuf:"bytes,2,opt,name=source,proto3" json:"source,omitempty"`
+	IsDefault      bool                   `protobuf:"varint,3,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	IsNamespace    bool                   `protobuf:"varint,4,opt,name=is_namespace,json=isNamespace,proto3" json:"is_namespace,omitempty"`
+	OriginalName   string                 `protobuf:"bytes,5,opt,name=original_name,json=originalName,proto3" json:"original_name,omitempty"`
+	Level          int32                  `protobuf:"varint,6,opt,name=level,proto3" json:"level,omitempty"`
+	ResolvedSource string                 `protobuf:"bytes,7,opt,name=resolved_source,json=resolvedSource,proto3" json:"resolved_source,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ImportInfo) Reset() {
+	*x = ImportInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportInfo) ProtoMessage() {}
+
+func (x *ImportInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportInfo.ProtoReflect.Descriptor instead.
+func (*ImportInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ImportInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportInfo) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *ImportInfo) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *ImportInfo) GetIsNamespace() bool {
+	if x != nil {
+		return x.IsNamespace
+	}
+	return false
+}
+
+func (x *ImportInfo) GetOriginalName() string {
+	if x != nil {
+		return x.OriginalName
+	}
+	return ""
+}
+
+func (x *ImportInfo) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *ImportInfo) GetResolvedSource() string {
+	if x != nil {
+		return x.ResolvedSource
+	}
+	return ""
+}
+
+type ReferenceInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Signature     string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Line          int32                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReferenceInfo) Reset() {
+	*x = ReferenceInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReferenceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReferenceInfo) ProtoMessage() {}
+
+func (x *ReferenceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReferenceInfo.ProtoReflect.Descriptor instead.
+func (*ReferenceInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReferenceInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ReferenceInfo) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ReferenceInfo) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+type CommentTag struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tag           string                 `protobuf:"bytes,1,opt,name=tag,proto3" json:"tag,omitempty"`
+	Owner         string                 `protobuf:"bytes,2,opt,name=owner,proto3" json:"owner,omitempty"`
+	Text          string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Line          int32                  `protobuf:"varint,4,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CommentTag) Reset() {
+	*x = CommentTag{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CommentTag) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CommentTag) ProtoMessage() {}
+
+func (x *CommentTag) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CommentTag.ProtoReflect.Descriptor instead.
+func (*CommentTag) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CommentTag) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *CommentTag) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *CommentTag) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CommentTag) GetLine() int32 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+type NotebookCell struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Index          int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Id             string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	CellType       string                 `protobuf:"bytes,3,opt,name=cell_type,json=cellType,proto3" json:"cell_type,omitempty"`
+	ExecutionCount *int32                 `protobuf:"varint,4,opt,name=execution_count,json=executionCount,proto3,oneof" json:"execution_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NotebookCell) Reset() {
+	*x = NotebookCell{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NotebookCell) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NotebookCell) ProtoMessage() {}
+
+func (x *NotebookCell) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NotebookCell.ProtoReflect.Descriptor instead.
+func (*NotebookCell) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *NotebookCell) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *NotebookCell) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *NotebookCell) GetCellType() string {
+	if x != nil {
+		return x.CellType
+	}
+	return ""
+}
+
+func (x *NotebookCell) GetExecutionCount() int32 {
+	if x != nil && x.ExecutionCount != nil {
+		return *x.ExecutionCount
+	}
+	return 0
+}
+
+type ClassContext struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Signature     string                 `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+	Fields        []string               `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClassContext) Reset() {
+	*x = ClassContext{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClassContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClassContext) ProtoMessage() {}
+
+func (x *ClassContext) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClassContext.ProtoReflect.Descriptor instead.
+func (*ClassContext) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ClassContext) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ClassContext) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *ClassContext) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
+type ChunkContext struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Filepath       string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	Language       string                 `protobuf:"bytes,2,opt,name=language,proto3" json:"language,omitempty"`
+	Scope          []*EntityInfo          `protobuf:"bytes,3,rep,name=scope,proto3" json:"scope,omitempty"`
+	Entities       []*ChunkEntityInfo     `protobuf:"bytes,4,rep,name=entities,proto3" json:"entities,omitempty"`
+	Siblings       []*SiblingInfo         `protobuf:"bytes,5,rep,name=siblings,proto3" json:"siblings,omitempty"`
+	Imports        []*ImportInfo          `protobuf:"bytes,6,rep,name=imports,proto3" json:"imports,omitempty"`
+	ParseError     *ParseError            `protobuf:"bytes,7,opt,name=parse_error,json=parseError,proto3" json:"parse_error,omitempty"`
+	References     []*ReferenceInfo       `protobuf:"bytes,8,rep,name=references,proto3" json:"references,omitempty"`
+	Cell           *NotebookCell          `protobuf:"bytes,9,opt,name=cell,proto3" json:"cell,omitempty"`
+	Class          *ClassContext          `protobuf:"bytes,10,opt,name=class,proto3" json:"class,omitempty"`
+	SlidingWindow  bool                   `protobuf:"varint,11,opt,name=sliding_window,json=slidingWindow,proto3" json:"sliding_window,omitempty"`
+	Boilerplate    *BoilerplateInfo       `protobuf:"bytes,12,opt,name=boilerplate,proto3" json:"boilerplate,omitempty"`
+	FileDoc        string                 `protobuf:"bytes,13,opt,name=file_doc,json=fileDoc,proto3" json:"file_doc,omitempty"`
+	Classification string                 `protobuf:"bytes,14,opt,name=classification,proto3" json:"classification,omitempty"`
+	TestedSymbols  []string               `protobuf:"bytes,15,rep,name=tested_symbols,json=testedSymbols,proto3" json:"tested_symbols,omitempty"`
+	Tags           []*CommentTag          `protobuf:"bytes,16,rep,name=tags,proto3" json:"tags,omitempty"`
+	Build          *BuildConstraints      `protobuf:"bytes,17,opt,name=build,proto3" json:"build,omitempty"`
+	Provenance     *Provenance            `protobuf:"bytes,18,opt,name=provenance,proto3" json:"provenance,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChunkContext) Reset() {
+	*x = ChunkContext{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkContext) ProtoMessage() {}
+
+func (x *ChunkContext) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkContext.ProtoReflect.Descriptor instead.
+func (*ChunkContext) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ChunkContext) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *ChunkContext) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+func (x *ChunkContext) GetScope() []*EntityInfo {
+	if x != nil {
+		return x.Scope
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetEntities() []*ChunkEntityInfo {
+	if x != nil {
+		return x.Entities
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetSiblings() []*SiblingInfo {
+	if x != nil {
+		return x.Siblings
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetImports() []*ImportInfo {
+	if x != nil {
+		return x.Imports
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetParseError() *ParseError {
+	if x != nil {
+		return x.ParseError
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetReferences() []*ReferenceInfo {
+	if x != nil {
+		return x.References
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetCell() *NotebookCell {
+	if x != nil {
+		return x.Cell
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetClass() *ClassContext {
+	if x != nil {
+		return x.Class
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetSlidingWindow() bool {
+	if x != nil {
+		return x.SlidingWindow
+	}
+	return false
+}
+
+func (x *ChunkContext) GetBoilerplate() *BoilerplateInfo {
+	if x != nil {
+		return x.Boilerplate
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetFileDoc() string {
+	if x != nil {
+		return x.FileDoc
+	}
+	return ""
+}
+
+func (x *ChunkContext) GetClassification() string {
+	if x != nil {
+		return x.Classification
+	}
+	return ""
+}
+
+func (x *ChunkContext) GetTestedSymbols() []string {
+	if x != nil {
+		return x.TestedSymbols
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetTags() []*CommentTag {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetBuild() *BuildConstraints {
+	if x != nil {
+		return x.Build
+	}
+	return nil
+}
+
+func (x *ChunkContext) GetProvenance() *Provenance {
+	if x != nil {
+		return x.Provenance
+	}
+	return nil
+}
+
+type BuildConstraints struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Expr          string                 `protobuf:"bytes,1,opt,name=expr,proto3" json:"expr,omitempty"`
+	Goos          string                 `protobuf:"bytes,2,opt,name=goos,proto3" json:"goos,omitempty"`
+	Goarch        string                 `protobuf:"bytes,3,opt,name=goarch,proto3" json:"goarch,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildConstraints) Reset() {
+	*x = BuildConstraints{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildConstraints) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildConstraints) ProtoMessage() {}
+
+func (x *BuildConstraints) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildConstraints.ProtoReflect.Descriptor instead.
+func (*BuildConstraints) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *BuildConstraints) GetExpr() string {
+	if x != nil {
+		return x.Expr
+	}
+	return ""
+}
+
+func (x *BuildConstraints) GetGoos() string {
+	if x != nil {
+		return x.Goos
+	}
+	return ""
+}
+
+func (x *BuildConstraints) GetGoarch() string {
+	if x != nil {
+		return x.Goarch
+	}
+	return ""
+}
+
+type BoilerplateInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	LineRange     *LineRange             `protobuf:"bytes,1,opt,name=line_range,json=lineRange,proto3" json:"line_range,omitempty"`
+	License       string                 `protobuf:"bytes,2,opt,name=license,proto3" json:"license,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BoilerplateInfo) Reset() {
+	*x = BoilerplateInfo{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BoilerplateInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BoilerplateInfo) ProtoMessage() {}
+
+func (x *BoilerplateInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BoilerplateInfo.ProtoReflect.Descriptor instead.
+func (*BoilerplateInfo) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *BoilerplateInfo) GetLineRange() *LineRange {
+	if x != nil {
+		return x.LineRange
+	}
+	return nil
+}
+
+func (x *BoilerplateInfo) GetLicense() string {
+	if x != nil {
+		return x.License
+	}
+	return ""
+}
+
+type CodeChunk struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Text               string                 `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	ContextualizedText string                 `protobuf:"bytes,2,opt,name=contextualized_text,json=contextualizedText,proto3" json:"contextualized_text,omitempty"`
+	ByteRange          *ByteRange             `protobuf:"bytes,3,opt,name=byte_range,json=byteRange,proto3" json:"byte_range,omitempty"`
+	LineRange          *LineRange             `protobuf:"bytes,4,opt,name=line_range,json=lineRange,proto3" json:"line_range,omitempty"`
+	Context            *ChunkContext          `protobuf:"bytes,5,opt,name=context,proto3" json:"context,omitempty"`
+	Index              int32                  `protobuf:"varint,6,opt,name=index,proto3" json:"index,omitempty"`
+	TotalChunks        int32                  `protobuf:"varint,7,opt,name=total_chunks,json=totalChunks,proto3" json:"total_chunks,omitempty"`
+	Span               *Span                  `protobuf:"bytes,8,opt,name=span,proto3" json:"span,omitempty"`
+	Stats              *ChunkStats            `protobuf:"bytes,9,opt,name=stats,proto3" json:"stats,omitempty"`
+	Id                 string                 `protobuf:"bytes,10,opt,name=id,proto3" json:"id,omitempty"`
+	ParentChunkId      string                 `protobuf:"bytes,11,opt,name=parent_chunk_id,json=parentChunkId,proto3" json:"parent_chunk_id,omitempty"`
+	ChildChunkIds      []string               `protobuf:"bytes,12,rep,name=child_chunk_ids,json=childChunkIds,proto3" json:"child_chunk_ids,omitempty"`
+	Level              int32                  `protobuf:"varint,13,opt,name=level,proto3" json:"level,omitempty"`
+	PrevChunkId        string                 `protobuf:"bytes,14,opt,name=prev_chunk_id,json=prevChunkId,proto3" json:"prev_chunk_id,omitempty"`
+	NextChunkId        string                 `protobuf:"bytes,15,opt,name=next_chunk_id,json=nextChunkId,proto3" json:"next_chunk_id,omitempty"`
+	ContentHash        string                 `protobuf:"bytes,16,opt,name=content_hash,json=contentHash,proto3" json:"content_hash,omitempty"`
+	Occurrences        []*ChunkOccurrence     `protobuf:"bytes,17,rep,name=occurrences,proto3" json:"occurrences,omitempty"`
+	QualifiedNames     []string               `protobuf:"bytes,18,rep,name=qualified_names,json=qualifiedNames,proto3" json:"qualified_names,omitempty"`
+	ContextHeader      string                 `protobuf:"bytes,19,opt,name=context_header,json=contextHeader,proto3" json:"context_header,omitempty"`
+	Truncated          bool                   `protobuf:"varint,20,opt,name=truncated,proto3" json:"truncated,omitempty"`
+	Kind               string                 `protobuf:"bytes,21,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CodeChunk) Reset() {
+	*x = CodeChunk{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CodeChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CodeChunk) ProtoMessage() {}
+
+func (x *CodeChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CodeChunk.ProtoReflect.Descriptor instead.
+func (*CodeChunk) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CodeChunk) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetContextualizedText() string {
+	if x != nil {
+		return x.ContextualizedText
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetByteRange() *ByteRange {
+	if x != nil {
+		return x.ByteRange
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetLineRange() *LineRange {
+	if x != nil {
+		return x.LineRange
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetContext() *ChunkContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *CodeChunk) GetTotalChunks() int32 {
+	if x != nil {
+		return x.TotalChunks
+	}
+	return 0
+}
+
+func (x *CodeChunk) GetSpan() *Span {
+	if x != nil {
+		return x.Span
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetStats() *ChunkStats {
+	if x != nil {
+		return x.Stats
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetParentChunkId() string {
+	if x != nil {
+		return x.ParentChunkId
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetChildChunkIds() []string {
+	if x != nil {
+		return x.ChildChunkIds
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *CodeChunk) GetPrevChunkId() string {
+	if x != nil {
+		return x.PrevChunkId
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetNextChunkId() string {
+	if x != nil {
+		return x.NextChunkId
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetContentHash() string {
+	if x != nil {
+		return x.ContentHash
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetOccurrences() []*ChunkOccurrence {
+	if x != nil {
+		return x.Occurrences
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetQualifiedNames() []string {
+	if x != nil {
+		return x.QualifiedNames
+	}
+	return nil
+}
+
+func (x *CodeChunk) GetContextHeader() string {
+	if x != nil {
+		return x.ContextHeader
+	}
+	return ""
+}
+
+func (x *CodeChunk) GetTruncated() bool {
+	if x != nil {
+		return x.Truncated
+	}
+	return false
+}
+
+func (x *CodeChunk) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type ChunkOccurrence struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filepath      string                 `protobuf:"bytes,1,opt,name=filepath,proto3" json:"filepath,omitempty"`
+	ChunkId       string                 `protobuf:"bytes,2,opt,name=chunk_id,json=chunkId,proto3" json:"chunk_id,omitempty"`
+	ByteRange     *ByteRange             `protobuf:"bytes,3,opt,name=byte_range,json=byteRange,proto3" json:"byte_range,omitempty"`
+	LineRange     *LineRange             `protobuf:"bytes,4,opt,name=line_range,json=lineRange,proto3" json:"line_range,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChunkOccurrence) Reset() {
+	*x = ChunkOccurrence{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkOccurrence) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkOccurrence) ProtoMessage() {}
+
+func (x *ChunkOccurrence) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkOccurrence.ProtoReflect.Descriptor instead.
+func (*ChunkOccurrence) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChunkOccurrence) GetFilepath() string {
+	if x != nil {
+		return x.Filepath
+	}
+	return ""
+}
+
+func (x *ChunkOccurrence) GetChunkId() string {
+	if x != nil {
+		return x.ChunkId
+	}
+	return ""
+}
+
+func (x *ChunkOccurrence) GetByteRange() *ByteRange {
+	if x != nil {
+		return x.ByteRange
+	}
+	return nil
+}
+
+func (x *ChunkOccurrence) GetLineRange() *LineRange {
+	if x != nil {
+		return x.LineRange
+	}
+	return nil
+}
+
+type ChunkStats struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Bytes           int32                  `protobuf:"varint,1,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	NwsBytes        int32                  `protobuf:"varint,2,opt,name=nws_bytes,json=nwsBytes,proto3" json:"nws_bytes,omitempty"`
+	Lines           int32                  `protobuf:"varint,3,opt,name=lines,proto3" json:"lines,omitempty"`
+	EstimatedTokens int32                  `protobuf:"varint,4,opt,name=estimated_tokens,json=estimatedTokens,proto3" json:"estimated_tokens,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ChunkStats) Reset() {
+	*x = ChunkStats{}
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChunkStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChunkStats) ProtoMessage() {}
+
+func (x *ChunkStats) ProtoReflect() protoreflect.Message {
+	mi := &file_codechunk_v1_codechunk_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChunkStats.ProtoReflect.Descriptor instead.
+func (*ChunkStats) Descriptor() ([]byte, []int) {
+	return file_codechunk_v1_codechunk_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ChunkStats) GetBytes() int32 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *ChunkStats) GetNwsBytes() int32 {
+	if x != nil {
+		return x.NwsBytes
+	}
+	return 0
+}
+
+func (x *ChunkStats) GetLines() int32 {
+	if x != nil {
+		return x.Lines
+	}
+	return 0
+}
+
+func (x *ChunkStats) GetEstimatedTokens() int32 {
+	if x != nil {
+		return x.EstimatedTokens
+	}
+	return 0
+}
+
+var File_codechunk_v1_codechunk_proto protoreflect.FileDescriptor
+
+var file_codechunk_v1_codechunk_proto_rawDesc = string([]byte{
+	0x0a, 0x1c, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2f, 0x76, 0x31, 0x2f, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c,
+	0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x22, 0xc6, 0x0a, 0x0a,
+	0x0c, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x24, 0x0a,
+	0x0e, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x6d,
+	0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e,
+	0x67, 0x5f, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d,
+	0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x44, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x12, 0x25, 0x0a,
+	0x0e, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x5f, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x49, 0x6d, 0x70,
+	0x6f, 0x72, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75, 0x61, 0x67, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x5f, 0x6c, 0x69, 0x6e, 0x65,
+	0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70,
+	0x4c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74,
+	0x5f, 0x70, 0x72, 0x65, 0x66, 0x69, 0x78, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63,
+	0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x50, 0x72, 0x65, 0x66, 0x69, 0x78, 0x12, 0x35, 0x0a, 0x17,
+	0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x5f,
+	0x69, 0x6e, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x69,
+	0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x49, 0x6e, 0x54,
+	0x65, 0x78, 0x74, 0x12, 0x39, 0x0a, 0x19, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69,
+	0x6e, 0x67, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x6c, 0x65, 0x6e,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x16, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x62, 0x6c, 0x69,
+	0x6e, 0x67, 0x53, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x4c, 0x65, 0x6e, 0x12, 0x21,
+	0x0a, 0x0c, 0x6d, 0x61, 0x78, 0x5f, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67,
+	0x73, 0x12, 0x21, 0x0a, 0x0c, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6f, 0x76, 0x65, 0x72, 0x6c, 0x61, 0x70,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73,
+	0x5f, 0x61, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0f,
+	0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x73, 0x41, 0x70, 0x70, 0x6c, 0x69, 0x65, 0x64, 0x12,
+	0x24, 0x0a, 0x0e, 0x6d, 0x69, 0x6e, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x6d, 0x69, 0x6e, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2f, 0x0a, 0x13, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72,
+	0x79, 0x5f, 0x68, 0x65, 0x75, 0x72, 0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x18, 0x0e, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x12, 0x62, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x48, 0x65, 0x75, 0x72,
+	0x69, 0x73, 0x74, 0x69, 0x63, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x70, 0x6c, 0x69, 0x74, 0x5f,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x73,
+	0x70, 0x6c, 0x69, 0x74, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x73, 0x74, 0x72, 0x61, 0x74, 0x65, 0x67, 0x79, 0x18, 0x10, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x74, 0x72, 0x61, 0x74, 0x65,
+	0x67, 0x79, 0x12, 0x36, 0x0a, 0x17, 0x73, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x77, 0x69,
+	0x6e, 0x64, 0x6f, 0x77, 0x5f, 0x66, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x18, 0x11, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x15, 0x73, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x46, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x22, 0x0a, 0x0c, 0x68, 0x69,
+	0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x18, 0x12, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0c, 0x68, 0x69, 0x65, 0x72, 0x61, 0x72, 0x63, 0x68, 0x69, 0x63, 0x61, 0x6c, 0x12, 0x2b,
+	0x0a, 0x11, 0x73, 0x74, 0x72, 0x69, 0x70, 0x5f, 0x62, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c,
+	0x61, 0x74, 0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x08, 0x52, 0x10, 0x73, 0x74, 0x72, 0x69, 0x70,
+	0x42, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x14, 0x62,
+	0x6f, 0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x5f, 0x70, 0x61, 0x74, 0x74, 0x65,
+	0x72, 0x6e, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x09, 0x52, 0x13, 0x62, 0x6f, 0x69, 0x6c, 0x65,
+	0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x50, 0x61, 0x74, 0x74, 0x65, 0x72, 0x6e, 0x73, 0x12, 0x35,
+	0x0a, 0x17, 0x6d, 0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x65,
+	0x61, 0x64, 0x65, 0x72, 0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x14, 0x6d, 0x61, 0x78, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x38, 0x0a, 0x18, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x6d, 0x6f, 0x64,
+	0x65, 0x18, 0x16, 0x20, 0x01, 0x28, 0x09, 0x52, 0x16, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x54, 0x65, 0x78, 0x74, 0x4d, 0x6f, 0x64, 0x65, 0x12,
+	0x20, 0x0a, 0x0b, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73, 0x6d, 0x18, 0x17,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c, 0x69, 0x73,
+	0x6d, 0x12, 0x31, 0x0a, 0x15, 0x6d, 0x61, 0x78, 0x5f, 0x70, 0x61, 0x72, 0x73, 0x65, 0x5f, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x5f, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x18, 0x18, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x12, 0x6d, 0x61, 0x78, 0x50, 0x61, 0x72, 0x73, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52,
+	0x61, 0x74, 0x69, 0x6f, 0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x5f, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x19, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6d, 0x61, 0x78,
+	0x46, 0x69, 0x6c, 0x65, 0x53, 0x69, 0x7a, 0x65, 0x12, 0x2d, 0x0a, 0x13, 0x6d, 0x61, 0x78, 0x5f,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x1a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x6d, 0x61, 0x78, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73,
+	0x50, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x2a, 0x0a, 0x11, 0x74, 0x72, 0x75, 0x6e, 0x63,
+	0x61, 0x74, 0x65, 0x5f, 0x6f, 0x6e, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x1b, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0f, 0x74, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74, 0x65, 0x4f, 0x6e, 0x4c, 0x69,
+	0x6d, 0x69, 0x74, 0x12, 0x2e, 0x0a, 0x13, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x74, 0x72, 0x69, 0x76,
+	0x69, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x1c, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x11, 0x73, 0x6b, 0x69, 0x70, 0x54, 0x72, 0x69, 0x76, 0x69, 0x61, 0x6c, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x12, 0x29, 0x0a, 0x10, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
+	0x5f, 0x6e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x18, 0x1d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x61,
+	0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73, 0x4e, 0x61, 0x6d, 0x69, 0x6e, 0x67, 0x12, 0x38,
+	0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x1e, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x70, 0x72,
+	0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x6c, 0x61, 0x7a, 0x79,
+	0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x1f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6c, 0x61, 0x7a,
+	0x79, 0x54, 0x65, 0x78, 0x74, 0x22, 0x7b, 0x0a, 0x0a, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x72, 0x65, 0x70, 0x6f, 0x5f, 0x72, 0x6f, 0x6f, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x70, 0x6f, 0x52, 0x6f, 0x6f, 0x74,
+	0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x6c, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x6c, 0x50, 0x61, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x63,
+	0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x5f, 0x73, 0x68, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x6f, 0x6d, 0x6d, 0x69, 0x74, 0x53, 0x68, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x72,
+	0x61, 0x6e, 0x63, 0x68, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x62, 0x72, 0x61, 0x6e,
+	0x63, 0x68, 0x22, 0xab, 0x01, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07, 0x6f,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x38, 0x0a, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64,
+	0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x65, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65,
+	0x22, 0x3b, 0x0a, 0x0c, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x12, 0x2b, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x22, 0x40, 0x0a,
+	0x0d, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2f,
+	0x0a, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f,
+	0x64, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x22,
+	0x9a, 0x01, 0x0a, 0x0a, 0x53, 0x6b, 0x69, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x16,
+	0x0a, 0x06, 0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x62, 0x69, 0x6e, 0x61, 0x72, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x67, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x67, 0x65, 0x6e, 0x65, 0x72,
+	0x61, 0x74, 0x65, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6d, 0x69, 0x6e, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6d, 0x69, 0x6e, 0x69, 0x66, 0x69, 0x65, 0x64,
+	0x12, 0x22, 0x0a, 0x0d, 0x6d, 0x61, 0x78, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x5f, 0x73, 0x69, 0x7a,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x6d, 0x61, 0x78, 0x46, 0x69, 0x6c, 0x65,
+	0x53, 0x69, 0x7a, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x62, 0x61, 0x72, 0x72, 0x65, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x62, 0x61, 0x72, 0x72, 0x65, 0x6c, 0x22, 0x87, 0x02, 0x0a,
+	0x11, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x46, 0x69, 0x6c, 0x65, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x12, 0x34, 0x0a, 0x07, 0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x07,
+	0x6f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x6f, 0x6e, 0x63, 0x75,
+	0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x63, 0x6f,
+	0x6e, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x39, 0x0a, 0x0b, 0x73, 0x6b, 0x69,
+	0x70, 0x5f, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x6b,
+	0x69, 0x70, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x52, 0x0a, 0x73, 0x6b, 0x69, 0x70, 0x50, 0x6f,
+	0x6c, 0x69, 0x63, 0x79, 0x12, 0x18, 0x0a, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x6f, 0x72, 0x64, 0x65, 0x72, 0x65, 0x64, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06,
+	0x64, 0x65, 0x64, 0x75, 0x70, 0x65, 0x22, 0xa9, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61,
+	0x74, 0x68, 0x12, 0x2f, 0x0a, 0x06, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x06, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x6b, 0x69, 0x70,
+	0x70, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x6b, 0x69, 0x70, 0x70,
+	0x65, 0x64, 0x22, 0x33, 0x0a, 0x09, 0x4c, 0x69, 0x6e, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x05, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x33, 0x0a, 0x09, 0x42, 0x79, 0x74, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x7a, 0x0a, 0x08,
+	0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x12, 0x16, 0x0a, 0x06,
+	0x63, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x63, 0x6f,
+	0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x75, 0x6e, 0x65, 0x5f, 0x63, 0x6f, 0x6c,
+	0x75, 0x6d, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x72, 0x75, 0x6e, 0x65, 0x43,
+	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x75, 0x74, 0x66, 0x31, 0x36, 0x5f, 0x63,
+	0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x75, 0x74, 0x66,
+	0x31, 0x36, 0x43, 0x6f, 0x6c, 0x75, 0x6d, 0x6e, 0x22, 0x5e, 0x0a, 0x04, 0x53, 0x70, 0x61, 0x6e,
+	0x12, 0x2c, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x16, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50,
+	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x28,
+	0x0a, 0x03, 0x65, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x63, 0x6f,
+	0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x22, 0x8f, 0x01, 0x0a, 0x0a, 0x50, 0x61, 0x72,
+	0x73, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x76, 0x65, 0x72, 0x61,
+	0x62, 0x6c, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x79, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x06, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x63, 0x6f, 0x75, 0x6e, 0x74, 0x22, 0x52, 0x0a, 0x0a, 0x45, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xe3,
+	0x04, 0x0a, 0x0f, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e,
+	0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x21, 0x0a, 0x09, 0x64, 0x6f, 0x63, 0x73,
+	0x74, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x09, 0x64,
+	0x6f, 0x63, 0x73, 0x74, 0x72, 0x69, 0x6e, 0x67, 0x88, 0x01, 0x01, 0x12, 0x36, 0x0a, 0x0a, 0x6c,
+	0x69, 0x6e, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4c,
+	0x69, 0x6e, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x61,
+	0x6e, 0x67, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x70, 0x61, 0x72, 0x74, 0x69, 0x61,
+	0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x50, 0x61, 0x72, 0x74, 0x69,
+	0x61, 0x6c, 0x12, 0x26, 0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x12, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x70, 0x61, 0x6e, 0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x76, 0x69,
+	0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x76, 0x69, 0x73, 0x69, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x20, 0x0a, 0x0b, 0x61, 0x6e,
+	0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x0b, 0x61, 0x6e, 0x6e, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x6d, 0x6f, 0x64, 0x69, 0x66, 0x69, 0x65, 0x72, 0x73, 0x12, 0x33, 0x0a, 0x0a, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x72, 0x61, 0x6d, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0c,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x72, 0x65, 0x74, 0x75, 0x72, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x40, 0x0a, 0x0f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74,
+	0x65, 0x72, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x52, 0x0e, 0x74, 0x79, 0x70, 0x65, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x65, 0x74, 0x65,
+	0x72, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x71, 0x75, 0x61, 0x6c,
+	0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x72, 0x6f,
+	0x70, 0x73, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70,
+	0x72, 0x6f, 0x70, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x6f, 0x76, 0x65, 0x72,
+	0x6c, 0x6f, 0x61, 0x64, 0x73, 0x18, 0x10, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x6f, 0x76, 0x65,
+	0x72, 0x6c, 0x6f, 0x61, 0x64, 0x73, 0x42, 0x0c, 0x0a, 0x0a, 0x5f, 0x64, 0x6f, 0x63, 0x73, 0x74,
+	0x72, 0x69, 0x6e, 0x67, 0x22, 0x2f, 0x0a, 0x05, 0x50, 0x61, 0x72, 0x61, 0x6d, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x3f, 0x0a, 0x09, 0x54, 0x79, 0x70, 0x65, 0x50, 0x61, 0x72,
+	0x61, 0x6d, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1e, 0x0a, 0x0a, 0x63, 0x6f, 0x6e, 0x73, 0x74, 0x72,
+	0x61, 0x69, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x63, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x22, 0x8b, 0x01, 0x0a, 0x0b, 0x53, 0x69, 0x62, 0x6c, 0x69,
+	0x6e, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x64, 0x69,
+	0x73, 0x74, 0x61, 0x6e, 0x63, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x22, 0xde, 0x01, 0x0a, 0x0a, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x49,
+	0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12,
+	0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x21,
+	0x0a, 0x0c, 0x69, 0x73, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x69, 0x73, 0x4e, 0x61, 0x6d, 0x65, 0x73, 0x70, 0x61, 0x63,
+	0x65, 0x12, 0x23, 0x0a, 0x0d, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e, 0x61, 0x6c, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x6f, 0x72, 0x69, 0x67, 0x69, 0x6e,
+	0x61, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x27, 0x0a, 0x0f,
+	0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x64, 0x53,
+	0x6f, 0x75, 0x72, 0x63, 0x65, 0x22, 0x55, 0x0a, 0x0d, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e,
+	0x63, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73,
+	0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x5c, 0x0a, 0x0a,
+	0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x61, 0x67, 0x12, 0x10, 0x0a, 0x03, 0x74, 0x61,
+	0x67, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x74, 0x61, 0x67, 0x12, 0x14, 0x0a, 0x05,
+	0x6f, 0x77, 0x6e, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6f, 0x77, 0x6e,
+	0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x6c, 0x69, 0x6e, 0x65, 0x22, 0x93, 0x01, 0x0a, 0x0c, 0x4e,
+	0x6f, 0x74, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x43, 0x65, 0x6c, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65,
+	0x78, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x65, 0x6c, 0x6c, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x65, 0x6c, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2c,
+	0x0a, 0x0f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00, 0x52, 0x0e, 0x65, 0x78, 0x65, 0x63, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x88, 0x01, 0x01, 0x42, 0x12, 0x0a, 0x10,
+	0x5f, 0x65, 0x78, 0x65, 0x63, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x63, 0x6f, 0x75, 0x6e, 0x74,
+	0x22, 0x58, 0x0a, 0x0c, 0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74,
+	0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72,
+	0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x22, 0xe6, 0x06, 0x0a, 0x0c, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75,
+	0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6c, 0x61, 0x6e, 0x67, 0x75,
+	0x61, 0x67, 0x65, 0x12, 0x2e, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x05, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x12, 0x39, 0x0a, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x45, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x69, 0x65, 0x73, 0x12, 0x35,
+	0x0a, 0x08, 0x73, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x19, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x53, 0x69, 0x62, 0x6c, 0x69, 0x6e, 0x67, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x08, 0x73, 0x69, 0x62,
+	0x6c, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x32, 0x0a, 0x07, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75,
+	0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x49, 0x6e, 0x66, 0x6f,
+	0x52, 0x07, 0x69, 0x6d, 0x70, 0x6f, 0x72, 0x74, 0x73, 0x12, 0x39, 0x0a, 0x0b, 0x70, 0x61, 0x72,
+	0x73, 0x65, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x61,
+	0x72, 0x73, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x0a, 0x70, 0x61, 0x72, 0x73, 0x65, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x12, 0x3b, 0x0a, 0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63,
+	0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0a, 0x72, 0x65, 0x66, 0x65, 0x72, 0x65, 0x6e, 0x63, 0x65,
+	0x73, 0x12, 0x2e, 0x0a, 0x04, 0x63, 0x65, 0x6c, 0x6c, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4e,
+	0x6f, 0x74, 0x65, 0x62, 0x6f, 0x6f, 0x6b, 0x43, 0x65, 0x6c, 0x6c, 0x52, 0x04, 0x63, 0x65, 0x6c,
+	0x6c, 0x12, 0x30, 0x0a, 0x05, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x6c, 0x61, 0x73, 0x73, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x05, 0x63, 0x6c,
+	0x61, 0x73, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x6c, 0x69, 0x64, 0x69, 0x6e, 0x67, 0x5f, 0x77,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x73, 0x6c, 0x69,
+	0x64, 0x69, 0x6e, 0x67, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x3f, 0x0a, 0x0b, 0x62, 0x6f,
+	0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1d, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42,
+	0x6f, 0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x0b,
+	0x62, 0x6f, 0x69, 0x6c, 0x65, 0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x12, 0x19, 0x0a, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x5f, 0x64, 0x6f, 0x63, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x66,
+	0x69, 0x6c, 0x65, 0x44, 0x6f, 0x63, 0x12, 0x26, 0x0a, 0x0e, 0x63, 0x6c, 0x61, 0x73, 0x73, 0x69,
+	0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x63, 0x6c, 0x61, 0x73, 0x73, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25,
+	0x0a, 0x0e, 0x74, 0x65, 0x73, 0x74, 0x65, 0x64, 0x5f, 0x73, 0x79, 0x6d, 0x62, 0x6f, 0x6c, 0x73,
+	0x18, 0x0f, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x65, 0x73, 0x74, 0x65, 0x64, 0x53, 0x79,
+	0x6d, 0x62, 0x6f, 0x6c, 0x73, 0x12, 0x2c, 0x0a, 0x04, 0x74, 0x61, 0x67, 0x73, 0x18, 0x10, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x6d, 0x65, 0x6e, 0x74, 0x54, 0x61, 0x67, 0x52, 0x04, 0x74,
+	0x61, 0x67, 0x73, 0x12, 0x34, 0x0a, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x18, 0x11, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x73, 0x74, 0x72, 0x61, 0x69, 0x6e,
+	0x74, 0x73, 0x52, 0x05, 0x62, 0x75, 0x69, 0x6c, 0x64, 0x12, 0x38, 0x0a, 0x0a, 0x70, 0x72, 0x6f,
+	0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x12, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e,
+	0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f,
+	0x76, 0x65, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x52, 0x0a, 0x70, 0x72, 0x6f, 0x76, 0x65, 0x6e, 0x61,
+	0x6e, 0x63, 0x65, 0x22, 0x52, 0x0a, 0x10, 0x42, 0x75, 0x69, 0x6c, 0x64, 0x43, 0x6f, 0x6e, 0x73,
+	0x74, 0x72, 0x61, 0x69, 0x6e, 0x74, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x65, 0x78, 0x70, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x65, 0x78, 0x70, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x67,
+	0x6f, 0x6f, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x67, 0x6f, 0x6f, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x67, 0x6f, 0x61, 0x72, 0x63, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x67, 0x6f, 0x61, 0x72, 0x63, 0x68, 0x22, 0x63, 0x0a, 0x0f, 0x42, 0x6f, 0x69, 0x6c, 0x65,
+	0x72, 0x70, 0x6c, 0x61, 0x74, 0x65, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x36, 0x0a, 0x0a, 0x6c, 0x69,
+	0x6e, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17,
+	0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69,
+	0x6e, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6c, 0x69, 0x63, 0x65, 0x6e, 0x73, 0x65, 0x22, 0xab, 0x06, 0x0a,
+	0x09, 0x43, 0x6f, 0x64, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x2f,
+	0x0a, 0x13, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64,
+	0x5f, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x78, 0x74, 0x75, 0x61, 0x6c, 0x69, 0x7a, 0x65, 0x64, 0x54, 0x65, 0x78, 0x74, 0x12,
+	0x36, 0x0a, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e,
+	0x76, 0x31, 0x2e, 0x42, 0x79, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x62, 0x79,
+	0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x36, 0x0a, 0x0a, 0x6c, 0x69, 0x6e, 0x65, 0x5f,
+	0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f,
+	0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x52,
+	0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12,
+	0x34, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x52, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x06,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x21, 0x0a, 0x0c, 0x74,
+	0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x73, 0x12, 0x26,
+	0x0a, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x70, 0x61, 0x6e,
+	0x52, 0x04, 0x73, 0x70, 0x61, 0x6e, 0x12, 0x2e, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x18,
+	0x09, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e,
+	0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x74, 0x61, 0x74, 0x73, 0x52,
+	0x05, 0x73, 0x74, 0x61, 0x74, 0x73, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74,
+	0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x64, 0x12, 0x26,
+	0x0a, 0x0f, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x64,
+	0x73, 0x18, 0x0c, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x68, 0x69, 0x6c, 0x64, 0x43, 0x68,
+	0x75, 0x6e, 0x6b, 0x49, 0x64, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x22, 0x0a, 0x0d,
+	0x70, 0x72, 0x65, 0x76, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x0e, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0b, 0x70, 0x72, 0x65, 0x76, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x49, 0x64,
+	0x12, 0x22, 0x0a, 0x0d, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x5f, 0x69,
+	0x64, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x49, 0x64, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f,
+	0x68, 0x61, 0x73, 0x68, 0x18, 0x10, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x48, 0x61, 0x73, 0x68, 0x12, 0x3f, 0x0a, 0x0b, 0x6f, 0x63, 0x63, 0x75, 0x72,
+	0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x18, 0x11, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x4f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x52, 0x0b, 0x6f, 0x63, 0x63,
+	0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x71, 0x75, 0x61, 0x6c,
+	0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0e, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x66, 0x69, 0x65, 0x64, 0x4e, 0x61, 0x6d, 0x65,
+	0x73, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x5f, 0x68, 0x65, 0x61,
+	0x64, 0x65, 0x72, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x72, 0x75, 0x6e,
+	0x63, 0x61, 0x74, 0x65, 0x64, 0x18, 0x14, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x74, 0x72, 0x75,
+	0x6e, 0x63, 0x61, 0x74, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x18, 0x15,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6b, 0x69, 0x6e, 0x64, 0x22, 0xb8, 0x01, 0x0a, 0x0f, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x4f, 0x63, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x65, 0x12, 0x1a,
+	0x0a, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x70, 0x61, 0x74, 0x68, 0x12, 0x19, 0x0a, 0x08, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x49, 0x64, 0x12, 0x36, 0x0a, 0x0a, 0x62, 0x79, 0x74, 0x65, 0x5f, 0x72, 0x61,
+	0x6e, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x79, 0x74, 0x65, 0x52, 0x61, 0x6e,
+	0x67, 0x65, 0x52, 0x09, 0x62, 0x79, 0x74, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x12, 0x36, 0x0a,
+	0x0a, 0x6c, 0x69, 0x6e, 0x65, 0x5f, 0x72, 0x61, 0x6e, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x4c, 0x69, 0x6e, 0x65, 0x52, 0x61, 0x6e, 0x67, 0x65, 0x52, 0x09, 0x6c, 0x69, 0x6e, 0x65,
+	0x52, 0x61, 0x6e, 0x67, 0x65, 0x22, 0x80, 0x01, 0x0a, 0x0a, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53,
+	0x74, 0x61, 0x74, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x62, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x77,
+	0x73, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x6e,
+	0x77, 0x73, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6e, 0x65, 0x73, 0x12, 0x29, 0x0a,
+	0x10, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x65, 0x73, 0x74, 0x69, 0x6d, 0x61, 0x74,
+	0x65, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x32, 0xe4, 0x01, 0x0a, 0x0e, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x65, 0x72, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x05, 0x43,
+	0x68, 0x75, 0x6e, 0x6b, 0x12, 0x1a, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b,
+	0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x1a, 0x1b, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x44, 0x0a,
+	0x0b, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1a, 0x2e, 0x63,
+	0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x17, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63,
+	0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x64, 0x65, 0x43, 0x68, 0x75, 0x6e,
+	0x6b, 0x30, 0x01, 0x12, 0x4a, 0x0a, 0x0a, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x61, 0x74, 0x63,
+	0x68, 0x12, 0x1f, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76, 0x31,
+	0x2e, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x19, 0x2e, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2e, 0x76,
+	0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x30, 0x01, 0x42,
+	0x4d, 0x5a, 0x4b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x63,
+	0x2d, 0x63, 0x6f, 0x64, 0x65, 0x72, 0x2f, 0x74, 0x72, 0x65, 0x65, 0x2d, 0x63, 0x6f, 0x64, 0x65,
+	0x2d, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x65, 0x72, 0x2f, 0x72, 0x65, 0x6d, 0x6f, 0x74, 0x65, 0x2f,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x2f,
+	0x76, 0x31, 0x3b, 0x63, 0x6f, 0x64, 0x65, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x76, 0x31, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_codechunk_v1_codechunk_proto_rawDescOnce sync.Once
+	file_codechunk_v1_codechunk_proto_rawDescData []byte
+)
+
+func file_codechunk_v1_codechunk_proto_rawDescGZIP() []byte {
+	file_codechunk_v1_codechunk_proto_rawDescOnce.Do(func() {
+		file_codechunk_v1_codechunk_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_codechunk_v1_codechunk_proto_rawDesc), len(file_codechunk_v1_codechunk_proto_rawDesc)))
+	})
+	return file_codechunk_v1_codechunk_proto_rawDescData
+}
+
+var file_codechunk_v1_codechunk_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_codechunk_v1_codechunk_proto_goTypes = []any{
+	(*ChunkOptions)(nil),      // 0: codechunk.v1.ChunkOptions
+	(*Provenance)(nil),        // 1: codechunk.v1.Provenance
+	(*FileInput)(nil),         // 2: codechunk.v1.FileInput
+	(*ChunkRequest)(nil),      // 3: codechunk.v1.ChunkRequest
+	(*ChunkResponse)(nil),     // 4: codechunk.v1.ChunkResponse
+	(*SkipPolicy)(nil),        // 5: codechunk.v1.SkipPolicy
+	(*ChunkBatchRequest)(nil), // 6: codechunk.v1.ChunkBatchRequest
+	(*BatchResult)(nil),       // 7: codechunk.v1.BatchResult
+	(*LineRange)(nil),         // 8: codechunk.v1.LineRange
+	(*ByteRange)(nil),         // 9: codechunk.v1.ByteRange
+	(*Position)(nil),          // 10: codechunk.v1.Position
+	(*Span)(nil),              // 11: codechunk.v1.Span
+	(*ParseError)(nil),        // 12: codechunk.v1.ParseError
+	(*EntityInfo)(nil),        // 13: codechunk.v1.EntityInfo
+	(*ChunkEntityInfo)(nil),   // 14: codechunk.v1.ChunkEntityInfo
+	(*Param)(nil),             // 15: codechunk.v1.Param
+	(*TypeParam)(nil),         // 16: codechunk.v1.TypeParam
+	(*SiblingInfo)(nil),       // 17: codechunk.v1.SiblingInfo
+	(*ImportInfo)(nil),        // 18: codechunk.v1.ImportInfo
+	(*ReferenceInfo)(nil),     // 19: codechunk.v1.ReferenceInfo
+	(*CommentTag)(nil),        // 20: codechunk.v1.CommentTag
+	(*NotebookCell)(nil),      // 21: codechunk.v1.NotebookCell
+	(*ClassContext)(nil),      // 22: codechunk.v1.ClassContext
+	(*ChunkContext)(nil),      // 23: codechunk.v1.ChunkContext
+	(*BuildConstraints)(nil),  // 24: codechunk.v1.BuildConstraints
+	(*BoilerplateInfo)(nil),   // 25: codechunk.v1.BoilerplateInfo
+	(*CodeChunk)(nil),         // 26: codechunk.v1.CodeChunk
+	(*ChunkOccurrence)(nil),   // 27: codechunk.v1.ChunkOccurrence
+	(*ChunkStats)(nil),        // 28: codechunk.v1.ChunkStats
+}
+var file_codechunk_v1_codechunk_proto_depIdxs = []int32{
+	1,  // 0: codechunk.v1.ChunkOptions.provenance:type_name -> codechunk.v1.Provenance
+	0,  // 1: codechunk.v1.FileInput.options:type_name -> codechunk.v1.ChunkOptions
+	1,  // 2: codechunk.v1.FileInput.provenance:type_name -> codechunk.v1.Provenance
+	2,  // 3: codechunk.v1.ChunkRequest.file:type_name -> codechunk.v1.FileInput
+	26, // 4: codechunk.v1.ChunkResponse.chunks:type_name -> codechunk.v1.CodeChunk
+	2,  // 5: codechunk.v1.ChunkBatchRequest.files:type_name -> codechunk.v1.FileInput
+	0,  // 6: codechunk.v1.ChunkBatchRequest.options:type_name -> codechunk.v1.ChunkOptions
+	5,  // 7: codechunk.v1.ChunkBatchRequest.skip_policy:type_name -> codechunk.v1.SkipPolicy
+	26, // 8: codechunk.v1.BatchResult.chunks:type_name -> codechunk.v1.CodeChunk
+	10, // 9: codechunk.v1.Span.start:type_name -> codechunk.v1.Position
+	10, // 10: codechunk.v1.Span.end:type_name -> codechunk.v1.Position
+	9,  // 11: codechunk.v1.ParseError.ranges:type_name -> codechunk.v1.ByteRange
+	8,  // 12: codechunk.v1.ChunkEntityInfo.line_range:type_name -> codechunk.v1.LineRange
+	11, // 13: codechunk.v1.ChunkEntityInfo.span:type_name -> codechunk.v1.Span
+	15, // 14: codechunk.v1.ChunkEntityInfo.parameters:type_name -> codechunk.v1.Param
+	16, // 15: codechunk.v1.ChunkEntityInfo.type_parameters:type_name -> codechunk.v1.TypeParam
+	13, // 16: codechunk.v1.ChunkContext.scope:type_name -> codechunk.v1.EntityInfo
+	14, // 17: codechunk.v1.ChunkContext.entities:type_name -> codechunk.v1.ChunkEntityInfo
+	17, // 18: codechunk.v1.ChunkContext.siblings:type_name -> codechunk.v1.SiblingInfo
+	18, // 19: codechunk.v1.ChunkContext.imports:type_name -> codechunk.v1.ImportInfo
+	12, // 20: codechunk.v1.ChunkContext.parse_error:type_name -> codechunk.v1.ParseError
+	19, // 21: codechunk.v1.ChunkContext.references:type_name -> codechunk.v1.ReferenceInfo
+	21, // 22: codechunk.v1.ChunkContext.cell:type_name -> codechunk.v1.NotebookCell
+	22, // 23: codechunk.v1.ChunkContext.class:type_name -> codechunk.v1.ClassContext
+	25, // 24: codechunk.v1.ChunkContext.boilerplate:type_name -> codechunk.v1.BoilerplateInfo
+	20, // 25: codechunk.v1.ChunkContext.tags:type_name -> codechunk.v1.CommentTag
+	24, // 26: codechunk.v1.ChunkContext.build:type_name -> codechunk.v1.BuildConstraints
+	1,  // 27: codechunk.v1.ChunkContext.provenance:type_name -> codechunk.v1.Provenance
+	8,  // 28: codechunk.v1.BoilerplateInfo.line_range:type_name -> codechunk.v1.LineRange
+	9,  // 29: codechunk.v1.CodeChunk.byte_range:type_name -> codechunk.v1.ByteRange
+	8,  // 30: codechunk.v1.CodeChunk.line_range:type_name -> codechunk.v1.LineRange
+	23, // 31: codechunk.v1.CodeChunk.context:type_name -> codechunk.v1.ChunkContext
+	11, // 32: codechunk.v1.CodeChunk.span:type_name -> codechunk.v1.Span
+	28, // 33: codechunk.v1.CodeChunk.stats:type_name -> codechunk.v1.ChunkStats
+	27, // 34: codechunk.v1.CodeChunk.occurrences:type_name -> codechunk.v1.ChunkOccurrence
+	9,  // 35: codechunk.v1.ChunkOccurrence.byte_range:type_name -> codechunk.v1.ByteRange
+	8,  // 36: codechunk.v1.ChunkOccurrence.line_range:type_name -> codechunk.v1.LineRange
+	3,  // 37: codechunk.v1.ChunkerService.Chunk:input_type -> codechunk.v1.ChunkRequest
+	3,  // 38: codechunk.v1.ChunkerService.ChunkStream:input_type -> codechunk.v1.ChunkRequest
+	6,  // 39: codechunk.v1.ChunkerService.ChunkBatch:input_type -> codechunk.v1.ChunkBatchRequest
+	4,  // 40: codechunk.v1.ChunkerService.Chunk:output_type -> codechunk.v1.ChunkResponse
+	26, // 41: codechunk.v1.ChunkerService.ChunkStream:output_type -> codechunk.v1.CodeChunk
+	7,  // 42: codechunk.v1.ChunkerService.ChunkBatch:output_type -> codechunk.v1.BatchResult
+	40, // [40:43] is the sub-list for method output_type
+	37, // [37:40] is the sub-list for method input_type
+	37, // [37:37] is the sub-list for extension type_name
+	37, // [37:37] is the sub-list for extension extendee
+	0,  // [0:37] is the sub-list for field type_name
+}
+
+func init() { file_codechunk_v1_codechunk_proto_init() }
+func file_codechunk_v1_codechunk_proto_init() {
+	if File_codechunk_v1_codechunk_proto != nil {
+		return
+	}
+	file_codechunk_v1_codechunk_proto_msgTypes[14].OneofWrappers = []any{}
+	file_codechunk_v1_codechunk_proto_msgTypes[21].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_codechunk_v1_codechunk_proto_rawDesc), len(file_codechunk_v1_codechunk_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_codechunk_v1_codechunk_proto_goTypes,
+		DependencyIndexes: file_codechunk_v1_codechunk_proto_depIdxs,
+		MessageInfos:      file_codechunk_v1_codechunk_proto_msgTypes,
+	}.Build()
+	File_codechunk_v1_codechunk_proto = out.File
+	file_codechunk_v1_codechunk_proto_goTypes = nil
+	file_codechunk_v1_codechunk_proto_depIdxs = nil
+}
@@ -0,0 +1,221 @@
+// Protocol definition for running codechunk as a remote service.
+//
+// The messages mirror the Go types of github.com/pc-coder/tree-code-chunker
+// field for field; see the Go documentation for the semantics of each field.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: codechunk/v1/codechunk.proto
+
+package codechunkv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ChunkerService_Chunk_FullMethodName       = "/codechunk.v1.ChunkerService/Chunk"
+	ChunkerService_ChunkStream_FullMethodName = "/codechunk.v1.ChunkerService/ChunkStream"
+	ChunkerService_ChunkBatch_FullMethodName  = "/codechunk.v1.ChunkerService/ChunkBatch"
+)
+
+// ChunkerServiceClient is the client API for ChunkerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// ChunkerService chunks source code into semantic pieces with context.
+type ChunkerServiceClient interface {
+	// Chunk chunks a single file and returns all chunks at once.
+	Chunk(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (*ChunkResponse, error)
+	// ChunkStream chunks a single file and streams chunks as they are produced.
+	ChunkStream(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CodeChunk], error)
+	// ChunkBatch chunks many files concurrently and streams one result per file
+	// in completion order.
+	ChunkBatch(ctx context.Context, in *ChunkBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResult], error)
+}
+
+type chunkerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChunkerServiceClient(cc grpc.ClientConnInterface) ChunkerServiceClient {
+	return &chunkerServiceClient{cc}
+}
+
+func (c *chunkerServiceClient) Chunk(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (*ChunkResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChunkResponse)
+	err := c.cc.Invoke(ctx, ChunkerService_Chunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chunkerServiceClient) ChunkStream(ctx context.Context, in *ChunkRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CodeChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChunkerService_ServiceDesc.Streams[0], ChunkerService_ChunkStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChunkRequest, CodeChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChunkerService_ChunkStreamClient = grpc.ServerStreamingClient[CodeChunk]
+
+func (c *chunkerServiceClient) ChunkBatch(ctx context.Context, in *ChunkBatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[BatchResult], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &ChunkerService_ServiceDesc.Streams[1], ChunkerService_ChunkBatch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ChunkBatchRequest, BatchResult]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChunkerService_ChunkBatchClient = grpc.ServerStreamingClient[BatchResult]
+
+// ChunkerServiceServer is the server API for ChunkerService service.
+// All implementations must embed UnimplementedChunkerServiceServer
+// for forward compatibility.
+//
+// ChunkerService chunks source code into semantic pieces with context.
+type ChunkerServiceServer interface {
+	// Chunk chunks a single file and returns all chunks at once.
+	Chunk(context.Context, *ChunkRequest) (*ChunkResponse, error)
+	// ChunkStream chunks a single file and streams chunks as they are produced.
+	ChunkStream(*ChunkRequest, grpc.ServerStreamingServer[CodeChunk]) error
+	// ChunkBatch chunks many files concurrently and streams one result per file
+	// in completion order.
+	ChunkBatch(*ChunkBatchRequest, grpc.ServerStreamingServer[BatchResult]) error
+	mustEmbedUnimplementedChunkerServiceServer()
+}
+
+// UnimplementedChunkerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedChunkerServiceServer struct{}
+
+func (UnimplementedChunkerServiceServer) Chunk(context.Context, *ChunkRequest) (*ChunkResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Chunk not implemented")
+}
+func (UnimplementedChunkerServiceServer) ChunkStream(*ChunkRequest, grpc.ServerStreamingServer[CodeChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ChunkStream not implemented")
+}
+func (UnimplementedChunkerServiceServer) ChunkBatch(*ChunkBatchRequest, grpc.ServerStreamingServer[BatchResult]) error {
+	return status.Errorf(codes.Unimplemented, "method ChunkBatch not implemented")
+}
+func (UnimplementedChunkerServiceServer) mustEmbedUnimplementedChunkerServiceServer() {}
+func (UnimplementedChunkerServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeChunkerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChunkerServiceServer will
+// result in compilation errors.
+type UnsafeChunkerServiceServer interface {
+	mustEmbedUnimplementedChunkerServiceServer()
+}
+
+func RegisterChunkerServiceServer(s grpc.ServiceRegistrar, srv ChunkerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedChunkerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ChunkerService_ServiceDesc, srv)
+}
+
+func _ChunkerService_Chunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChunkerServiceServer).Chunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChunkerService_Chunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChunkerServiceServer).Chunk(ctx, req.(*ChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ChunkerService_ChunkStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChunkRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChunkerServiceServer).ChunkStream(m, &grpc.GenericServerStream[ChunkRequest, CodeChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChunkerService_ChunkStreamServer = grpc.ServerStreamingServer[CodeChunk]
+
+func _ChunkerService_ChunkBatch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ChunkBatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChunkerServiceServer).ChunkBatch(m, &grpc.GenericServerStream[ChunkBatchRequest, BatchResult]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type ChunkerService_ChunkBatchServer = grpc.ServerStreamingServer[BatchResult]
+
+// ChunkerService_ServiceDesc is the grpc.ServiceDesc for ChunkerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ChunkerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "codechunk.v1.ChunkerService",
+	HandlerType: (*ChunkerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Chunk",
+			Handler:    _ChunkerService_Chunk_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ChunkStream",
+			Handler:       _ChunkerService_ChunkStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ChunkBatch",
+			Handler:       _ChunkerService_ChunkBatch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "codechunk/v1/codechunk.proto",
+}
@@ -0,0 +1,5 @@
+// Package codechunkv1 contains the generated protobuf and gRPC bindings for the
+// codechunk remote chunking service defined in codechunk.proto.
+package codechunkv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative codechunk/v1/codechunk.proto
@@ -0,0 +1,284 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"testing"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const goSource = `package main
+
+import "fmt"
+
+// Greet prints a greeting.
+func Greet(name string) {
+	fmt.Println("Hello, " + name)
+}
+
+func main() {
+	Greet("world")
+}
+`
+
+func newTestClient(t *testing.T, opts *codechunk.BatchOptions) *Client {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	NewServer(opts).Register(srv)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return NewClient(conn)
+}
+
+func TestClientChunkMatchesLocal(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	opts := &codechunk.ChunkOptions{MaxChunkSize: 60}
+	remoteChunks, err := client.Chunk(context.Background(), "main.go", goSource, opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	localChunks, err := codechunk.Chunk("main.go", goSource, opts)
+	if err != nil {
+		t.Fatalf("local Chunk failed: %v", err)
+	}
+
+	if !reflect.DeepEqual(remoteChunks, localChunks) {
+		t.Errorf("Remote chunks differ from local:\nremote: %+v\nlocal:  %+v", remoteChunks, localChunks)
+	}
+}
+
+func TestClientChunkUnsupportedLanguage(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	_, err := client.Chunk(context.Background(), "style.css", "body {}", nil)
+	if !errors.Is(err, codechunk.ErrUnsupportedLanguage) {
+		t.Errorf("Expected ErrUnsupportedLanguage, got %v", err)
+	}
+}
+
+func TestClientChunkStream(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	var chunks []codechunk.CodeChunk
+	err := client.ChunkStream(context.Background(), "main.go", goSource, &codechunk.ChunkOptions{MaxChunkSize: 60},
+		func(c codechunk.CodeChunk) error {
+			chunks = append(chunks, c)
+			return nil
+		})
+	if err != nil {
+		t.Fatalf("ChunkStream failed: %v", err)
+	}
+
+	if len(chunks) < 2 {
+		t.Fatalf("Expected multiple chunks, got %d", len(chunks))
+	}
+	for i, c := range chunks {
+		if c.Index != i || c.TotalChunks != -1 {
+			t.Errorf("Chunk %d has Index=%d TotalChunks=%d", i, c.Index, c.TotalChunks)
+		}
+	}
+}
+
+func TestClientChunkStreamCallbackError(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	stop := errors.New("stop")
+	err := client.ChunkStream(context.Background(), "main.go", goSource, &codechunk.ChunkOptions{MaxChunkSize: 60},
+		func(c codechunk.CodeChunk) error { return stop })
+	if !errors.Is(err, stop) {
+		t.Errorf("Expected callback error, got %v", err)
+	}
+}
+
+func TestClientChunkBatch(t *testing.T) {
+	client := newTestClient(t, &codechunk.BatchOptions{Concurrency: 2})
+
+	files := []codechunk.FileInput{
+		{Filepath: "a.go", Code: goSource},
+		{Filepath: "b.py", Code: "def hello():\n    pass\n"},
+		{Filepath: "c.css", Code: "body {}"},
+	}
+
+	results := make(map[string]codechunk.BatchResult)
+	err := client.ChunkBatch(context.Background(), files, nil, func(r codechunk.BatchResult) error {
+		results[r.Filepath] = r
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChunkBatch failed: %v", err)
+	}
+
+	if len(results) != len(files) {
+		t.Fatalf("Expected %d results, got %d", len(files), len(results))
+	}
+	for _, name := range []string{"a.go", "b.py"} {
+		if results[name].Error != nil || len(results[name].Chunks) == 0 {
+			t.Errorf("Expected chunks for %s, got %+v", name, results[name])
+		}
+	}
+	if !errors.Is(results["c.css"].Error, codechunk.ErrUnsupportedLanguage) {
+		t.Errorf("Expected ErrUnsupportedLanguage for c.css, got %v", results["c.css"].Error)
+	}
+}
+
+func TestClientChunkBatchSkipPolicy(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	files := []codechunk.FileInput{
+		{Filepath: "a.go", Code: goSource},
+		{Filepath: "a.pb.go", Code: goSource},
+	}
+
+	results := make(map[string]codechunk.BatchResult)
+	opts := &codechunk.BatchOptions{SkipPolicy: codechunk.SkipPolicy{Generated: true}}
+	err := client.ChunkBatch(context.Background(), files, opts, func(r codechunk.BatchResult) error {
+		results[r.Filepath] = r
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChunkBatch failed: %v", err)
+	}
+
+	if results["a.go"].Error != nil {
+		t.Errorf("Expected a.go to be chunked, got %v", results["a.go"].Error)
+	}
+	skipped := results["a.pb.go"]
+	if !skipped.Skipped || !errors.Is(skipped.Error, codechunk.ErrSkipped) {
+		t.Errorf("Expected a.pb.go to be skipped, got %+v", skipped)
+	}
+}
+
+func TestClientChunkBatchProvenance(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	files := []codechunk.FileInput{
+		{Filepath: "main.go", Code: goSource, Provenance: &codechunk.Provenance{RelPath: "cmd/greet/main.go"}},
+	}
+	opts := &codechunk.BatchOptions{ChunkOptions: codechunk.ChunkOptions{
+		Provenance: &codechunk.Provenance{CommitSHA: "3f2c1a9", Branch: "main"},
+	}}
+
+	want := codechunk.Provenance{RelPath: "cmd/greet/main.go", CommitSHA: "3f2c1a9", Branch: "main"}
+	err := client.ChunkBatch(context.Background(), files, opts, func(r codechunk.BatchResult) error {
+		if r.Error != nil {
+			return r.Error
+		}
+		for _, chunk := range r.Chunks {
+			if chunk.Context.Provenance == nil || *chunk.Context.Provenance != want {
+				t.Errorf("Expected provenance %+v, got %+v", want, chunk.Context.Provenance)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChunkBatch failed: %v", err)
+	}
+}
+
+func TestClientChunkBatchOrdered(t *testing.T) {
+	client := newTestClient(t, &codechunk.BatchOptions{Concurrency: 4})
+
+	var files []codechunk.FileInput
+	for i := 0; i < 20; i++ {
+		files = append(files, codechunk.FileInput{Filepath: fmt.Sprintf("f%d.go", i), Code: goSource})
+	}
+
+	var got []string
+	err := client.ChunkBatch(context.Background(), files, &codechunk.BatchOptions{Ordered: true}, func(r codechunk.BatchResult) error {
+		got = append(got, r.Filepath)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChunkBatch failed: %v", err)
+	}
+	for i, file := range files {
+		if i >= len(got) || got[i] != file.Filepath {
+			t.Fatalf("Expected results in input order, got %v", got)
+		}
+	}
+}
+
+func TestClientChunkLazyText(t *testing.T) {
+	client := newTestClient(t, nil)
+
+	opts := &codechunk.ChunkOptions{MaxChunkSize: 60, LazyText: true}
+	chunks, err := client.Chunk(context.Background(), "main.go", goSource, opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	localChunks, err := codechunk.Chunk("main.go", goSource, opts)
+	if err != nil {
+		t.Fatalf("local Chunk failed: %v", err)
+	}
+	if len(chunks) != len(localChunks) {
+		t.Fatalf("Expected %d chunks, got %d", len(localChunks), len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Text == "" || chunk.Text != localChunks[i].Content() {
+			t.Errorf("Chunk %d: expected text %q, got %q", i, localChunks[i].Content(), chunk.Text)
+		}
+		if chunk.Contextualized() != localChunks[i].Contextualized() {
+			t.Errorf("Chunk %d: contextualized text differs from local", i)
+		}
+	}
+}
+
+func TestClientChunkBatchDedupe(t *testing.T) {
+	client := newTestClient(t, &codechunk.BatchOptions{Concurrency: 4})
+
+	files := []codechunk.FileInput{
+		{Filepath: "a.go", Code: goSource},
+		{Filepath: "b.go", Code: goSource},
+		{Filepath: "c.go", Code: goSource},
+	}
+	var results []codechunk.BatchResult
+	err := client.ChunkBatch(context.Background(), files, &codechunk.BatchOptions{Dedupe: true}, func(r codechunk.BatchResult) error {
+		results = append(results, r)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("ChunkBatch failed: %v", err)
+	}
+
+	want := codechunk.ChunkBatch(files, &codechunk.BatchOptions{Dedupe: true})
+	if len(results) != len(want) {
+		t.Fatalf("Expected %d results, got %d", len(want), len(results))
+	}
+	for i, result := range results {
+		if result.Filepath != files[i].Filepath {
+			t.Fatalf("Expected results in input order, got %s at %d", result.Filepath, i)
+		}
+		if len(result.Chunks) != len(want[i].Chunks) {
+			t.Errorf("%s: expected %d chunks after deduplication, got %d", result.Filepath, len(want[i].Chunks), len(result.Chunks))
+		}
+	}
+	if len(results[0].Chunks) == 0 || len(results[0].Chunks[0].Occurrences) != len(files) {
+		t.Errorf("Expected the first chunk to list %d occurrences, got %+v", len(files), results[0].Chunks)
+	}
+	if len(results[1].Chunks) != 0 {
+		t.Errorf("Expected duplicate chunks removed from b.go, got %d", len(results[1].Chunks))
+	}
+}
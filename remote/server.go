@@ -0,0 +1,144 @@
+// Package remote exposes the codechunk library over gRPC.
+//
+// Server implements the ChunkerService defined in remote/proto/codechunk/v1 and Client
+// wraps the generated client so that callers work with codechunk types:
+//
+//	conn, err := grpc.NewClient("localhost:50051", grpc.WithTransportCredentials(insecure.NewCredentials()))
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	client := remote.NewClient(conn)
+//	chunks, err := client.Chunk(ctx, "main.go", code, nil)
+package remote
+
+import (
+	"context"
+	"errors"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+	codechunkv1 "github.com/pc-coder/tree-code-chunker/remote/proto/codechunk/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements codechunkv1.ChunkerServiceServer using the codechunk package.
+type Server struct {
+	codechunkv1.UnimplementedChunkerServiceServer
+
+	options codechunk.BatchOptions
+}
+
+// NewServer creates a Server. The chunk options in opts are used for requests that
+// carry no options of their own, and opts.Concurrency and opts.SkipPolicy apply to
+// batch requests that don't specify their own.
+func NewServer(opts *codechunk.BatchOptions) *Server {
+	options := codechunk.DefaultBatchOptions()
+	if opts != nil {
+		options = *opts
+	}
+	options.OnProgress = nil
+	return &Server{options: options}
+}
+
+// Register registers the server with a gRPC service registrar.
+func (s *Server) Register(registrar grpc.ServiceRegistrar) {
+	codechunkv1.RegisterChunkerServiceServer(registrar, s)
+}
+
+// requestOptions returns the request's options, or the server defaults if it has none
+func (s *Server) requestOptions(opts *codechunkv1.ChunkOptions) *codechunk.ChunkOptions {
+	if options := optionsFromProto(opts); options != nil {
+		return options
+	}
+	options := s.options.ChunkOptions
+	return &options
+}
+
+// Chunk chunks a single file.
+func (s *Server) Chunk(ctx context.Context, req *codechunkv1.ChunkRequest) (*codechunkv1.ChunkResponse, error) {
+	file := req.GetFile()
+	chunks, err := codechunk.ChunkWithContext(ctx, file.GetFilepath(), file.GetCode(), s.requestOptions(file.GetOptions()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &codechunkv1.ChunkResponse{Chunks: chunksToProto(chunks)}, nil
+}
+
+// ChunkStream chunks a single file and streams the chunks.
+func (s *Server) ChunkStream(req *codechunkv1.ChunkRequest, stream grpc.ServerStreamingServer[codechunkv1.CodeChunk]) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	file := req.GetFile()
+	ch, err := codechunk.ChunkStreamWithContext(ctx, file.GetFilepath(), file.GetCode(), s.requestOptions(file.GetOptions()))
+	if err != nil {
+		return toStatus(err)
+	}
+
+	for chunk := range ch {
+		if err := stream.Send(chunkToProto(chunk)); err != nil {
+			// Cancelling stops the producing goroutine
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+// ChunkBatch chunks many files concurrently and streams results in completion order,
+// or in input order once the batch completes if the request dedupes them.
+func (s *Server) ChunkBatch(req *codechunkv1.ChunkBatchRequest, stream grpc.ServerStreamingServer[codechunkv1.BatchResult]) error {
+	options := s.options
+	options.ChunkOptions = *s.requestOptions(req.GetOptions())
+	if req.GetConcurrency() > 0 {
+		options.Concurrency = int(req.GetConcurrency())
+	}
+	if policy := skipPolicyFromProto(req.GetSkipPolicy()); policy != nil {
+		options.SkipPolicy = *policy
+	}
+	if req.GetOrdered() {
+		options.Ordered = true
+	}
+	if req.GetDedupe() {
+		options.Dedupe = true
+	}
+
+	files := make([]codechunk.FileInput, len(req.GetFiles()))
+	for i, f := range req.GetFiles() {
+		files[i] = fileFromProto(f)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	// Deduplication needs every result, which the streaming batch doesn't keep
+	if options.Dedupe {
+		for _, result := range codechunk.ChunkBatchWithContext(ctx, files, &options) {
+			if err := stream.Send(resultToProto(result)); err != nil {
+				return err
+			}
+		}
+		return stream.Context().Err()
+	}
+
+	for result := range codechunk.ChunkBatchStreamWithContext(ctx, files, &options) {
+		if err := stream.Send(resultToProto(result)); err != nil {
+			return err
+		}
+	}
+	return stream.Context().Err()
+}
+
+// toStatus converts a chunking error into a gRPC status error
+func toStatus(err error) error {
+	switch {
+	case errors.Is(err, codechunk.ErrUnsupportedLanguage):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
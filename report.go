@@ -0,0 +1,103 @@
+package codechunk
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// BatchReport summarizes a batch: how many files were chunked, skipped and failed,
+// totals overall and per language, and how long the batch and its files took
+type BatchReport struct {
+	Files      int                        `json:"files"`              // Files in the batch
+	Skipped    int                        `json:"skipped"`            // Files not processed (see BatchResult.Skipped)
+	Failures   map[string]int             `json:"failures,omitempty"` // Processed files that failed, by ErrorCode
+	Total      MetricsTotals              `json:"total"`              // Totals over the processed files
+	ByLanguage map[Language]MetricsTotals `json:"byLanguage"`         // Totals per language ("" for unsupported files)
+	WallTime   time.Duration              `json:"wallTime"`           // Time from the start of the batch to its last result
+	P50Latency time.Duration              `json:"p50Latency"`         // Median time to chunk a processed file
+	P95Latency time.Duration              `json:"p95Latency"`         // 95th percentile time to chunk a processed file
+	MaxLatency time.Duration              `json:"maxLatency"`         // Longest time to chunk a processed file
+}
+
+// ChunkBatchWithReport is ChunkBatchWithContext also returning a BatchReport of
+// the batch. opts.Metrics, if set, still observes every file.
+func ChunkBatchWithReport(ctx context.Context, files []FileInput, opts *BatchOptions) ([]BatchResult, BatchReport) {
+	options := BatchOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	collector := &reportCollector{next: options.Metrics}
+	options.Metrics = collector
+
+	start := time.Now()
+	results := ChunkBatchWithContext(ctx, files, &options)
+	return results, newBatchReport(results, collector.files, time.Since(start))
+}
+
+// reportCollector records the FileMetrics of a batch for its report, passing them
+// on to next if set
+type reportCollector struct {
+	mu    sync.Mutex
+	files []FileMetrics
+	next  Metrics
+}
+
+func (c *reportCollector) ObserveFile(m FileMetrics) {
+	c.mu.Lock()
+	c.files = append(c.files, m)
+	c.mu.Unlock()
+
+	if c.next != nil {
+		c.next.ObserveFile(m)
+	}
+}
+
+// newBatchReport builds the report of a batch from its results and the metrics
+// of the files it processed
+func newBatchReport(results []BatchResult, files []FileMetrics, wallTime time.Duration) BatchReport {
+	report := BatchReport{
+		Files:      len(results),
+		ByLanguage: make(map[Language]MetricsTotals),
+		WallTime:   wallTime,
+	}
+
+	for _, result := range results {
+		switch {
+		case result.Skipped:
+			report.Skipped++
+		case result.Error != nil:
+			if report.Failures == nil {
+				report.Failures = make(map[string]int)
+			}
+			report.Failures[ErrorCode(result.Error)]++
+		}
+	}
+
+	latencies := make([]time.Duration, 0, len(files))
+	for _, m := range files {
+		report.Total.add(m)
+		totals := report.ByLanguage[m.Language]
+		totals.add(m)
+		report.ByLanguage[m.Language] = totals
+		latencies = append(latencies, m.TotalTime)
+	}
+
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		report.P50Latency = percentile(latencies, 50)
+		report.P95Latency = percentile(latencies, 95)
+		report.MaxLatency = latencies[len(latencies)-1]
+	}
+	return report
+}
+
+// percentile returns the p-th percentile of sorted by the nearest-rank method
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,58 @@
+package codechunk
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestChunkBatchWithReport(t *testing.T) {
+	files := []FileInput{
+		{Filepath: "a.go", Code: "package main\n\nfunc a() {}\n\nfunc b() {}\n"},
+		{Filepath: "b.py", Code: "def b():\n    pass\n"},
+		{Filepath: "c.unknown", Code: "???"},
+		{Filepath: "d.pb.go", Code: "package p\n"},
+	}
+
+	observed := 0
+	results, report := ChunkBatchWithReport(context.Background(), files, &BatchOptions{
+		Concurrency: 1,
+		SkipPolicy:  SkipPolicy{Generated: true},
+		Metrics:     MetricsFunc(func(m FileMetrics) { observed++ }),
+	})
+	if len(results) != len(files) {
+		t.Fatalf("Expected %d results, got %d", len(files), len(results))
+	}
+	if observed != 3 {
+		t.Errorf("Expected Metrics to still observe 3 files, got %d", observed)
+	}
+
+	if report.Files != 4 || report.Skipped != 1 || report.Failures[ErrorCodeUnsupportedLanguage] != 1 || len(report.Failures) != 1 {
+		t.Errorf("Unexpected file counts: %+v", report)
+	}
+	if report.Total.Files != 3 || report.Total.FailedFiles != 1 || report.Total.Chunks == 0 {
+		t.Errorf("Unexpected totals: %+v", report.Total)
+	}
+	if goTotals := report.ByLanguage[LanguageGo]; goTotals.Files != 1 || goTotals.Entities != 2 {
+		t.Errorf("Unexpected Go totals: %+v", goTotals)
+	}
+	if report.WallTime <= 0 || report.MaxLatency <= 0 || report.P50Latency > report.P95Latency || report.P95Latency > report.MaxLatency {
+		t.Errorf("Unexpected timings: %+v", report)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := make([]time.Duration, 20)
+	for i := range sorted {
+		sorted[i] = time.Duration(i + 1)
+	}
+	if p := percentile(sorted, 95); p != 19 {
+		t.Errorf("Expected p95 of 19, got %d", p)
+	}
+	if p := percentile(sorted, 50); p != 10 {
+		t.Errorf("Expected p50 of 10, got %d", p)
+	}
+	if p := percentile(sorted[:1], 95); p != 1 {
+		t.Errorf("Expected p95 of a single value to be it, got %d", p)
+	}
+}
@@ -1,5 +1,14 @@
 package codechunk
 
+import (
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
 // rangeContains checks if outer range fully contains inner range
 func rangeContains(outer, inner ByteRange) bool {
 	return outer.Start <= inner.Start && inner.End <= outer.End
@@ -14,28 +23,33 @@ func createScopeNode(entity *ExtractedEntity, parent *ScopeNode) *ScopeNode {
 	}
 }
 
-// findParentNode finds the deepest parent node whose range contains the entity's range
-func findParentNode(roots []*ScopeNode, entity *ExtractedEntity) *ScopeNode {
-	for _, root := range roots {
-		if found := findInNode(root, entity); found != nil {
-			return found
+// findParentNode finds the deepest node whose range contains the entity's range.
+// Entities are added in order of their start, so every node starts at or before
+// the entity and the first node of a level reaching its end is the one to descend
+// into; ends holds the running maximum end of the roots (see appendScopeNode).
+func findParentNode(roots []*ScopeNode, ends []int, entity *ExtractedEntity) *ScopeNode {
+	var parent *ScopeNode
+	nodes := roots
+	for {
+		i := sort.SearchInts(ends, entity.ByteRange.End)
+		if i == len(ends) || !rangeContains(nodes[i].Entity.ByteRange, entity.ByteRange) {
+			return parent
 		}
+		parent = nodes[i]
+		nodes, ends = parent.Children, parent.childEnds
 	}
-	return nil
 }
 
-func findInNode(node *ScopeNode, entity *ExtractedEntity) *ScopeNode {
-	if !rangeContains(node.Entity.ByteRange, entity.ByteRange) {
-		return nil
-	}
-
-	for _, child := range node.Children {
-		if deeperMatch := findInNode(child, entity); deeperMatch != nil {
-			return deeperMatch
-		}
+// appendScopeNode appends a node to a level of the tree along with the running
+// maximum end of the level. Siblings are in file order and rarely overlap, but
+// where one does, the maximum keeps the first node reaching an offset findable by
+// binary search.
+func appendScopeNode(nodes []*ScopeNode, ends []int, node *ScopeNode) ([]*ScopeNode, []int) {
+	end := node.Entity.ByteRange.End
+	if len(ends) > 0 {
+		end = max(end, ends[len(ends)-1])
 	}
-
-	return node
+	return append(nodes, node), append(ends, end)
 }
 
 // buildScopeTree builds a scope tree from extracted entities
@@ -59,63 +73,61 @@ func buildScopeTree(entities []*ExtractedEntity) *ScopeTree {
 	sortByByteRange(scopeEntities)
 
 	root := make([]*ScopeNode, 0)
+	var rootEnds []int
 
 	for _, entity := range scopeEntities {
-		parent := findParentNode(root, entity)
+		parent := findParentNode(root, rootEnds, entity)
 		node := createScopeNode(entity, parent)
 
 		if parent != nil {
-			parent.Children = append(parent.Children, node)
+			parent.Children, parent.childEnds = appendScopeNode(parent.Children, parent.childEnds, node)
 		} else {
-			root = append(root, node)
+			root, rootEnds = appendScopeNode(root, rootEnds, node)
+		}
+	}
+
+	// Go methods are declared at the top level, apart from their receiver type
+	receiverTypes := make(map[string]*ScopeNode)
+	for _, node := range root {
+		if _, ok := receiverTypes[node.Entity.Name]; !ok && node.Entity.Type != EntityTypeFunction && node.Entity.Type != EntityTypeMethod {
+			receiverTypes[node.Entity.Name] = node
 		}
 	}
 
 	return &ScopeTree{
-		Root:        root,
-		Imports:     imports,
-		Exports:     exports,
-		AllEntities: entities,
+		Root:          root,
+		Imports:       imports,
+		Exports:       exports,
+		AllEntities:   entities,
+		rootEnds:      rootEnds,
+		entityIndex:   newEntityIndex(entities),
+		callables:     callableDefinitions(entities),
+		receiverTypes: receiverTypes,
 	}
 }
 
-// sortByByteRange sorts entities by byte range start
+// sortByByteRange sorts entities by byte range start, keeping the order of
+// entities starting at the same offset
 func sortByByteRange(entities []*ExtractedEntity) {
-	for i := 1; i < len(entities); i++ {
-		key := entities[i]
-		j := i - 1
-		for j >= 0 && entities[j].ByteRange.Start > key.ByteRange.Start {
-			entities[j+1] = entities[j]
-			j--
-		}
-		entities[j+1] = key
-	}
+	sort.SliceStable(entities, func(i, j int) bool {
+		return entities[i].ByteRange.Start < entities[j].ByteRange.Start
+	})
 }
 
-// findScopeAtOffset finds the scope node that contains a given byte offset
+// findScopeAtOffset finds the deepest scope node that contains a given byte offset
 func findScopeAtOffset(tree *ScopeTree, offset int) *ScopeNode {
-	for _, root := range tree.Root {
-		if found := findScopeInNode(root, offset); found != nil {
+	var found *ScopeNode
+	nodes, ends := tree.Root, tree.rootEnds
+	for {
+		// The first node ending past the offset is the only one that can contain
+		// it first, as nodes are in order of their start
+		i := sort.SearchInts(ends, offset+1)
+		if i == len(ends) || nodes[i].Entity.ByteRange.Start > offset {
 			return found
 		}
+		found = nodes[i]
+		nodes, ends = found.Children, found.childEnds
 	}
-	return nil
-}
-
-func findScopeInNode(node *ScopeNode, offset int) *ScopeNode {
-	byteRange := node.Entity.ByteRange
-
-	if offset < byteRange.Start || offset >= byteRange.End {
-		return nil
-	}
-
-	for _, child := range node.Children {
-		if deeperMatch := findScopeInNode(child, offset); deeperMatch != nil {
-			return deeperMatch
-		}
-	}
-
-	return node
 }
 
 // getAncestorChain gets the ancestor chain for a scope node
@@ -144,3 +156,185 @@ func visitNode(node *ScopeNode, result *[]*ScopeNode) {
 		visitNode(child, result)
 	}
 }
+
+// withModuleScope appends the module a Python file defines, as its __name__ would
+// be when imported, to the outer end of a scope chain: the namespace of its scope
+// tree. Other languages declare their packages and namespaces in the source, so
+// their chains are unchanged.
+func withModuleScope(scope []EntityInfo, tree *ScopeTree, lang Language) []EntityInfo {
+	if lang != LanguagePython || tree.namespace == "" {
+		return scope
+	}
+	return append(scope, EntityInfo{Name: tree.namespace, Type: EntityTypeNamespace})
+}
+
+// pythonModuleName derives the dotted module name of a Python source file from
+// its path. A relative path, such as a path from the repository root, is named by
+// its trailing directories that are valid identifiers, below any src directory,
+// and the file name without its extension ("pkg.auth.service" for
+// "src/pkg/auth/service.py"). An absolute path is named from its package root
+// instead, by the enclosing directories holding an __init__.py, so the
+// directories of the checkout don't become packages. A package's __init__.py is
+// named by its directory. Returns "" for files that are not .py or .pyi files.
+func pythonModuleName(filepath string) string {
+	file := strings.ReplaceAll(filepath, "\\", "/")
+	ext := path.Ext(file)
+	if ext != ".py" && ext != ".pyi" {
+		return ""
+	}
+
+	parts := strings.Split(strings.TrimSuffix(file, ext), "/")
+	if parts[len(parts)-1] == "__init__" {
+		parts = parts[:len(parts)-1]
+	}
+	if path.IsAbs(file) {
+		first := len(parts) - 1
+		if first < 0 || !isPythonIdentifier(parts[first]) {
+			return ""
+		}
+		for first > 0 && isPythonIdentifier(parts[first-1]) && isPythonPackage(strings.Join(parts[:first], "/")) {
+			first--
+		}
+		return strings.Join(parts[first:], ".")
+	}
+
+	first := len(parts)
+	for first > 0 && isPythonIdentifier(parts[first-1]) && parts[first-1] != "src" {
+		first--
+	}
+	return strings.Join(parts[first:], ".")
+}
+
+// isPythonPackage reports whether dir is a Python package, a directory holding an
+// __init__.py
+func isPythonPackage(dir string) bool {
+	for _, name := range []string{"__init__.py", "__init__.pyi"} {
+		if info, err := os.Stat(path.Join(dir, name)); err == nil && !info.IsDir() {
+			return true
+		}
+	}
+	return false
+}
+
+// isPythonPackageFile reports whether a Python file is the __init__.py of a
+// package, which is the package of its own relative imports
+func isPythonPackageFile(filepath string) bool {
+	name := path.Base(strings.ReplaceAll(filepath, "\\", "/"))
+	return name == "__init__.py" || name == "__init__.pyi"
+}
+
+// resolvePythonImport resolves the module of a relative Python import against
+// importer, the module of the importing file as pythonModuleName derives it:
+// from ..utils import helper in pkg.auth.service imports from pkg.utils. The
+// package of an __init__.py, isPackage, is its own module. Returns "" for
+// absolute imports and imports reaching above the top-level package.
+func resolvePythonImport(source, importer string, isPackage bool) string {
+	module := strings.TrimLeft(source, ".")
+	level := len(source) - len(module)
+	if level == 0 || importer == "" {
+		return ""
+	}
+
+	packages := strings.Split(importer, ".")
+	if !isPackage {
+		packages = packages[:len(packages)-1]
+	}
+	if level-1 >= len(packages) {
+		return ""
+	}
+	packages = packages[:len(packages)-(level-1)]
+	if module != "" {
+		packages = append(packages, module)
+	}
+	return strings.Join(packages, ".")
+}
+
+// isPythonIdentifier reports whether s is a valid ASCII Python identifier
+func isPythonIdentifier(s string) bool {
+	if s == "" || !isIdentStart(s[0]) {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		if !isIdentStart(s[i]) && (s[i] < '0' || s[i] > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// qualifyScopeTree sets the qualified name of every scope node: its name joined to
+// those of its enclosing scopes, after the package or module the file declares
+// outside of its scope tree. Go methods are qualified by their receiver type.
+// Names are joined with :: in Rust and with dots otherwise.
+func qualifyScopeTree(tree *ScopeTree, namespace string, lang Language) {
+	separator := "."
+	if lang == LanguageRust {
+		separator = "::"
+	}
+	tree.namespace = namespace
+	tree.qualifiedNames = make(map[*ExtractedEntity]string)
+
+	var qualify func(node *ScopeNode, prefix string)
+	qualify = func(node *ScopeNode, prefix string) {
+		if receiver, ok := receiverScope(node, tree); ok {
+			prefix = joinQualified(prefix, receiver.Name, separator)
+		}
+		node.QualifiedName = joinQualified(prefix, node.Entity.Name, separator)
+		tree.qualifiedNames[node.Entity] = node.QualifiedName
+		for _, child := range node.Children {
+			qualify(child, node.QualifiedName)
+		}
+	}
+	for _, root := range tree.Root {
+		qualify(root, namespace)
+	}
+}
+
+func joinQualified(prefix, name, separator string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + separator + name
+}
+
+// fileNamespace returns the package or module a file belongs to when it is not an
+// entity of its scope tree: the package clause of a Go file, or the module name
+// of a Python file
+func fileNamespace(rootNode *sitter.Node, code []byte, filepath string, lang Language) string {
+	switch lang {
+	case LanguageGo:
+		for _, clause := range childrenOfType(rootNode, "package_clause") {
+			if name := childrenOfType(clause, "package_identifier"); len(name) > 0 {
+				return name[0].Content(code)
+			}
+		}
+	case LanguagePython:
+		return pythonModuleName(filepath)
+	}
+	return ""
+}
+
+// qualifiedNamesInRange returns the distinct qualified names of the scope entities
+// that overlap a byte range, in file order. A Rust struct and its impl share one.
+func qualifiedNamesInRange(byteRange ByteRange, tree *ScopeTree) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	// Children lie within their parent, so only overlapping nodes are descended into
+	var visit func(nodes []*ScopeNode, ends []int)
+	visit = func(nodes []*ScopeNode, ends []int) {
+		for i := sort.SearchInts(ends, byteRange.Start+1); i < len(nodes) && nodes[i].Entity.ByteRange.Start < byteRange.End; i++ {
+			node := nodes[i]
+			if node.Entity.ByteRange.End <= byteRange.Start {
+				continue
+			}
+			if node.QualifiedName != "" && !seen[node.QualifiedName] {
+				seen[node.QualifiedName] = true
+				names = append(names, node.QualifiedName)
+			}
+			visit(node.Children, node.childEnds)
+		}
+	}
+	visit(tree.Root, tree.rootEnds)
+	return names
+}
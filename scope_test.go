@@ -1,6 +1,10 @@
 package codechunk
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -331,8 +335,344 @@ func TestFindParentNodeNotFound(t *testing.T) {
 		},
 	}
 
-	parent := findParentNode(roots, entity)
+	parent := findParentNode(roots, []int{100}, entity)
 	if parent != nil {
 		t.Error("Expected nil when entity is outside all ranges")
 	}
 }
+
+// scopeNames returns the names of a chunk's scope chain, innermost first
+func scopeNames(scope []EntityInfo) []string {
+	names := make([]string, len(scope))
+	for i, entity := range scope {
+		names[i] = entity.Name
+	}
+	return names
+}
+
+func TestNestedFunctionScopes(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		marker   string
+		want     string
+	}{
+		{
+			"nested.py",
+			"def outer():\n    total = 0\n    def inner(x):\n        return x * 2 + total\n    return inner\n",
+			"return x * 2",
+			"inner outer nested",
+		},
+		{
+			"closure.go",
+			"package p\n\nfunc Serve() {\n\thandler := func(w int) {\n\t\tprintln(w * 2)\n\t}\n\thandler(1)\n}\n",
+			"println(w * 2)",
+			"handler Serve",
+		},
+	}
+
+	for _, tt := range tests {
+		chunks, err := Chunk(tt.filepath, tt.code, &ChunkOptions{MaxChunkSize: 20})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		found := false
+		for _, chunk := range chunks {
+			if strings.Contains(chunk.Text, tt.marker) {
+				found = true
+				if got := strings.Join(scopeNames(chunk.Context.Scope), " "); got != tt.want {
+					t.Errorf("%s: expected scope %q, got %q", tt.filepath, tt.want, got)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: no chunk contains %q", tt.filepath, tt.marker)
+		}
+	}
+}
+
+func TestGoClosureEntities(t *testing.T) {
+	code := "package p\n\nfunc Serve() {\n\tvar fallback = func() {}\n\tgo func() {}()\n\tfallback()\n}\n"
+	parseResult, err := parseString(code, LanguageGo)
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	names := make([]string, 0)
+	for _, entity := range extractEntities(parseResult.Tree.RootNode(), LanguageGo, []byte(code)) {
+		names = append(names, entity.Name)
+		if entity.Name == "fallback" {
+			if entity.Signature != "var fallback = func()" {
+				t.Errorf("Expected the signature to include the binding, got %q", entity.Signature)
+			}
+			if entity.Parent == nil || *entity.Parent != "Serve" {
+				t.Errorf("Expected Serve as parent, got %v", entity.Parent)
+			}
+		}
+	}
+	// The goroutine literal is not assigned, so it is not an entity
+	if strings.Join(names, " ") != "Serve fallback" {
+		t.Errorf("Expected entities Serve and fallback, got %v", names)
+	}
+}
+
+func TestGoMethodScopeIncludesReceiver(t *testing.T) {
+	code := "package p\n\ntype User struct {\n\tName string\n}\n\nfunc (u *User) Greet() string {\n\tgreeting := \"Hello, \" + u.Name\n\treturn greeting\n}\n\nfunc (c *Conn) Close() error {\n\tc.open = false\n\treturn nil\n}\n"
+	chunks, err := Chunk("user.go", code, &ChunkOptions{MaxChunkSize: 30})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	expected := map[string]string{
+		"return greeting": "Greet User",
+		"return nil":      "Close Conn",
+	}
+	for marker, want := range expected {
+		found := false
+		for _, chunk := range chunks {
+			if !strings.Contains(chunk.Text, marker) {
+				continue
+			}
+			found = true
+			if got := strings.Join(scopeNames(chunk.Context.Scope), " "); got != want {
+				t.Errorf("%q: expected scope %q, got %q", marker, want, got)
+			}
+			if marker == "return greeting" && chunk.Context.Scope[1].Type != EntityTypeType {
+				t.Errorf("Expected the User type declaration in the scope, got %s", chunk.Context.Scope[1].Type)
+			}
+		}
+		if !found {
+			t.Errorf("No chunk contains %q", marker)
+		}
+	}
+}
+
+func TestNamespaceScopes(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		marker   string
+		want     string
+	}{
+		{
+			"Service.java",
+			"package com.example.auth;\n\npublic class Service {\n    public void login(String user) {\n        audit(user);\n    }\n}\n",
+			"audit(user)",
+			"login Service com.example.auth",
+		},
+		{
+			"src/auth/service.py",
+			"def login(user):\n    audit(user)\n    return True\n",
+			"audit(user)",
+			"login auth.service",
+		},
+	}
+
+	for _, tt := range tests {
+		chunks, err := Chunk(tt.filepath, tt.code, &ChunkOptions{MaxChunkSize: 20})
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		found := false
+		for _, chunk := range chunks {
+			if strings.Contains(chunk.Text, tt.marker) {
+				found = true
+				if got := strings.Join(scopeNames(chunk.Context.Scope), " "); got != tt.want {
+					t.Errorf("%s: expected scope %q, got %q", tt.filepath, tt.want, got)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("%s: no chunk contains %q", tt.filepath, tt.marker)
+		}
+	}
+}
+
+func TestPythonModuleName(t *testing.T) {
+	tests := []struct {
+		filepath string
+		want     string
+	}{
+		{"service.py", "service"},
+		{"pkg/auth/service.py", "pkg.auth.service"},
+		{"src/pkg/auth/__init__.py", "pkg.auth"},
+		{"stubs/types.pyi", "stubs.types"},
+		{"/home/dev/my-repo/pkg/util.py", "util"},
+		{`pkg\windows.py`, "pkg.windows"},
+		{"my-script.py", ""},
+		{"notebook.ipynb", ""},
+	}
+
+	for _, tt := range tests {
+		if got := pythonModuleName(tt.filepath); got != tt.want {
+			t.Errorf("pythonModuleName(%q) = %q, want %q", tt.filepath, got, tt.want)
+		}
+	}
+}
+
+func TestPythonModuleNamePackageRoot(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "work", "myrepo")
+	for _, dir := range []string{"pkg", filepath.Join("pkg", "auth")} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(root, dir, "__init__.py"), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		filepath string
+		want     string
+	}{
+		{filepath.Join(root, "pkg", "auth", "service.py"), "pkg.auth.service"},
+		{filepath.Join(root, "pkg", "auth", "__init__.py"), "pkg.auth"},
+		{filepath.Join(root, "setup.py"), "setup"},
+	}
+	for _, tt := range tests {
+		if got := pythonModuleName(tt.filepath); got != tt.want {
+			t.Errorf("pythonModuleName(%q) = %q, want %q", tt.filepath, got, tt.want)
+		}
+	}
+}
+
+func TestPythonModuleScopeFromProvenance(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "work", "myrepo")
+	file := filepath.Join(root, "src", "pkg", "auth", "service.py")
+	code := "def login(user):\n    audit(user)\n    return True\n"
+
+	tests := []struct {
+		name       string
+		provenance *Provenance
+		want       string
+	}{
+		{"repo root", &Provenance{RepoRoot: root}, "login pkg.auth.service"},
+		{"rel path", &Provenance{RelPath: "lib/auth/service.py"}, "login lib.auth.service"},
+		{"no provenance", nil, "login service"},
+	}
+	for _, tt := range tests {
+		chunks, err := Chunk(file, code, &ChunkOptions{Provenance: tt.provenance})
+		if err != nil {
+			t.Fatalf("%s: Chunk failed: %v", tt.name, err)
+		}
+		if len(chunks) == 0 {
+			t.Fatalf("%s: expected chunks", tt.name)
+		}
+		if got := strings.Join(scopeNames(chunks[0].Context.Scope), " "); got != tt.want {
+			t.Errorf("%s: expected scope %q, got %q", tt.name, tt.want, got)
+		}
+		analysis, err := Analyze(file, code, &ChunkOptions{Provenance: tt.provenance})
+		if err != nil {
+			t.Fatalf("%s: Analyze failed: %v", tt.name, err)
+		}
+		if got, want := analysis.ScopeTree.Root[0].QualifiedName, strings.Fields(tt.want)[1]+".login"; got != want {
+			t.Errorf("%s: expected qualified name %q, got %q", tt.name, want, got)
+		}
+		analysis.Close()
+	}
+}
+
+func TestQualifiedNames(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+		want     []string
+	}{
+		{
+			"auth/service.go",
+			"package auth\n\ntype Service struct{}\n\nfunc (s *Service) Login() {}\n",
+			[]string{"auth.Service", "auth.Service.Login"},
+		},
+		{
+			"Service.java",
+			"package com.example.auth;\n\npublic class Service {\n    void login() {}\n}\n",
+			[]string{"com.example.auth", "com.example.auth.Service", "com.example.auth.Service.login"},
+		},
+		{
+			"lib.rs",
+			"mod auth {\n    struct Service;\n    impl Service {\n        fn sign() {}\n    }\n}\n",
+			[]string{"auth", "auth::Service", "auth::Service::sign"},
+		},
+		{
+			"src/auth/service.py",
+			"class Service:\n    def login(self):\n        pass\n",
+			[]string{"auth.service.Service", "auth.service.Service.login"},
+		},
+		{
+			"service.ts",
+			"export class Service {\n  login() {}\n}\n",
+			[]string{"Service", "Service.login"},
+		},
+	}
+
+	for _, tt := range tests {
+		chunks, err := Chunk(tt.filepath, tt.code, nil)
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(chunks) != 1 {
+			t.Fatalf("%s: expected 1 chunk, got %d", tt.filepath, len(chunks))
+		}
+		if got := strings.Join(chunks[0].QualifiedNames, " "); got != strings.Join(tt.want, " ") {
+			t.Errorf("%s: expected qualified names %v, got %v", tt.filepath, tt.want, chunks[0].QualifiedNames)
+		}
+		for _, entity := range chunks[0].Context.Entities {
+			if entity.Name == "login" && entity.QualifiedName != tt.want[len(tt.want)-1] {
+				t.Errorf("%s: expected login to be qualified as %s, got %q", tt.filepath, tt.want[len(tt.want)-1], entity.QualifiedName)
+			}
+		}
+	}
+}
+
+func TestScopeTreeIndexedLookups(t *testing.T) {
+	// Classes of methods, each class followed by a function, with an import
+	// between them
+	var entities []*ExtractedEntity
+	for c := 0; c < 50; c++ {
+		start := c * 1000
+		entities = append(entities, &ExtractedEntity{Name: fmt.Sprintf("C%d", c), Type: EntityTypeClass, ByteRange: ByteRange{start, start + 800}})
+		for m := 0; m < 20; m++ {
+			methodStart := start + 10 + m*35
+			entities = append(entities, &ExtractedEntity{Name: fmt.Sprintf("C%d.m%d", c, m), Type: EntityTypeMethod, ByteRange: ByteRange{methodStart, methodStart + 30}})
+		}
+		entities = append(entities, &ExtractedEntity{Name: fmt.Sprintf("i%d", c), Type: EntityTypeImport, ByteRange: ByteRange{start + 800, start + 850}})
+		entities = append(entities, &ExtractedEntity{Name: fmt.Sprintf("f%d", c), Type: EntityTypeFunction, ByteRange: ByteRange{start + 850, start + 990}})
+	}
+	tree := buildScopeTree(entities)
+
+	if len(tree.Root) != 100 || len(tree.Root[0].Children) != 20 {
+		t.Fatalf("Expected 100 roots with 20 methods in the first, got %d and %d", len(tree.Root), len(tree.Root[0].Children))
+	}
+
+	for start := 0; start < 50000; start += 97 {
+		byteRange := ByteRange{start, start + 300}
+
+		var want []string
+		for _, entity := range entities {
+			if entity.ByteRange.Start < byteRange.End && entity.ByteRange.End > byteRange.Start {
+				want = append(want, entity.Name)
+			}
+		}
+		var got []string
+		for _, entity := range getEntitiesInRange(byteRange, tree) {
+			got = append(got, entity.Name)
+		}
+		if strings.Join(got, " ") != strings.Join(want, " ") {
+			t.Fatalf("Range %v: expected entities %v, got %v", byteRange, want, got)
+		}
+
+		var wantScope string
+		for _, entity := range entities {
+			if entity.Type != EntityTypeImport && entity.ByteRange.Start <= start && start < entity.ByteRange.End {
+				wantScope = entity.Name
+			}
+		}
+		var gotScope string
+		if node := findScopeAtOffset(tree, start); node != nil {
+			gotScope = node.Entity.Name
+		}
+		if gotScope != wantScope {
+			t.Fatalf("Offset %d: expected scope %q, got %q", start, wantScope, gotScope)
+		}
+	}
+}
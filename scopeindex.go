@@ -0,0 +1,79 @@
+package codechunk
+
+import (
+	"math"
+	"sort"
+)
+
+// entityIndex finds the entities overlapping a byte range without scanning all
+// of them, which matters for generated files with thousands of entities. It is
+// an interval tree laid out over the entities sorted by start: the middle of each
+// span is the root of its subtree, and maxEnd holds the largest end within it.
+type entityIndex struct {
+	entities  []*ExtractedEntity // Entities sorted by start
+	positions []int              // Position of each entity in the indexed list
+	maxEnd    []int              // Largest end in the subtree rooted at each entity
+}
+
+// newEntityIndex indexes a list of entities
+func newEntityIndex(entities []*ExtractedEntity) *entityIndex {
+	idx := &entityIndex{
+		entities:  make([]*ExtractedEntity, len(entities)),
+		positions: make([]int, len(entities)),
+		maxEnd:    make([]int, len(entities)),
+	}
+	for i := range idx.positions {
+		idx.positions[i] = i
+	}
+	sort.SliceStable(idx.positions, func(i, j int) bool {
+		return entities[idx.positions[i]].ByteRange.Start < entities[idx.positions[j]].ByteRange.Start
+	})
+	for i, position := range idx.positions {
+		idx.entities[i] = entities[position]
+	}
+	idx.fillMaxEnd(0, len(entities))
+	return idx
+}
+
+func (idx *entityIndex) fillMaxEnd(lo, hi int) int {
+	if lo >= hi {
+		return math.MinInt
+	}
+	mid := (lo + hi) / 2
+	end := max(idx.entities[mid].ByteRange.End, idx.fillMaxEnd(lo, mid), idx.fillMaxEnd(mid+1, hi))
+	idx.maxEnd[mid] = end
+	return end
+}
+
+// overlapping returns the positions in the indexed list of the entities that
+// overlap a byte range, in ascending order. A nil index has no entities.
+func (idx *entityIndex) overlapping(byteRange ByteRange) []int {
+	if idx == nil {
+		return nil
+	}
+	var positions []int
+	idx.collect(0, len(idx.entities), byteRange, &positions)
+	sort.Ints(positions)
+	return positions
+}
+
+func (idx *entityIndex) collect(lo, hi int, byteRange ByteRange, positions *[]int) {
+	if lo >= hi {
+		return
+	}
+	mid := (lo + hi) / 2
+	if idx.maxEnd[mid] <= byteRange.Start {
+		return
+	}
+	idx.collect(lo, mid, byteRange, positions)
+
+	// Entities after mid start no earlier, so none of them overlap either
+	entity := idx.entities[mid]
+	if entity.ByteRange.Start >= byteRange.End {
+		return
+	}
+	if entity.ByteRange.End > byteRange.Start {
+		*positions = append(*positions, idx.positions[mid])
+	}
+	idx.collect(mid+1, hi, byteRange, positions)
+}
@@ -0,0 +1,199 @@
+package codechunk
+
+import (
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// langAttrPattern matches the lang attribute of a <script> tag
+var langAttrPattern = regexp.MustCompile(`(?i)\blang\s*=\s*["']?([\w-]+)`)
+
+// componentBlock is a top-level block of a single-file component
+type componentBlock struct {
+	name    string    // "script", "template", "style" or "markup"
+	openTag string    // Opening tag, such as `<script setup lang="ts">`
+	element ByteRange // From the opening tag through the closing tag
+	content ByteRange // Between the opening and closing tags
+}
+
+// chunkComponent chunks a Vue or Svelte single-file component. Each <script> block
+// (including <script setup> and Svelte's module script) is chunked as TypeScript
+// when its lang attribute is ts or tsx and as JavaScript otherwise; opts.Language
+// overrides both. The template, style and Svelte markup sections are listed as
+// siblings of the script chunks, and ranges and lines refer to the whole file.
+// A component without scripts is chunked as plain text.
+func chunkComponent(path string, code []byte, opts ChunkOptions, m *FileMetrics, parser *languageParser) ([]CodeChunk, error) {
+	blocks := scanComponent(code, strings.EqualFold(filepath.Ext(path), ".svelte"))
+
+	var sections []fileSection
+	for _, block := range blocks {
+		if block.name != "script" {
+			sections = append(sections, fileSection{name: block.name, signature: block.openTag, byteRange: block.element})
+		}
+	}
+
+	positions := newPositionIndex(code)
+	var source string
+	if opts.LazyText {
+		source = string(code)
+	}
+
+	chunks := make([]CodeChunk, 0)
+	for _, block := range blocks {
+		if block.name != "script" || len(bytes.TrimSpace(code[block.content.Start:block.content.End])) == 0 {
+			continue
+		}
+		lang := opts.Language
+		if lang == "" {
+			lang = scriptLanguage(block.openTag)
+		}
+		if m.Language == "" {
+			m.Language = lang
+		}
+		blockChunks, err := chunkEmbedded(path, code, embeddedBlock{byteRange: block.content, lang: lang, sections: sections}, opts, m, parser, positions, source)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, blockChunks...)
+	}
+
+	if m.Language == "" {
+		m.Language = componentLanguage(path)
+		chunks = chunkPlainText(path, string(code), m.Language, opts)
+	}
+
+	numberChunks(chunks)
+	return chunks, nil
+}
+
+// componentLanguage returns the language recorded for a component chunked as text
+func componentLanguage(path string) Language {
+	if strings.EqualFold(filepath.Ext(path), ".svelte") {
+		return LanguageSvelte
+	}
+	return LanguageVue
+}
+
+// scriptLanguage returns the language of a <script> block from its opening tag
+func scriptLanguage(openTag string) Language {
+	if match := langAttrPattern.FindStringSubmatch(openTag); match != nil {
+		switch strings.ToLower(match[1]) {
+		case "ts", "tsx", "typescript":
+			return LanguageTypeScript
+		}
+	}
+	return LanguageJavaScript
+}
+
+// scanComponent returns the top-level <script>, <template> and <style> blocks of a
+// component in source order, skipping HTML comments. For Svelte, which has no
+// <template> block, the non-blank content between script and style blocks is
+// returned as markup sections.
+func scanComponent(code []byte, svelte bool) []componentBlock {
+	var blocks []componentBlock
+	markupStart := 0
+	addMarkup := func(end int) {
+		if !svelte || len(bytes.TrimSpace(code[markupStart:end])) == 0 {
+			return
+		}
+		start := markupStart + len(code[markupStart:end]) - len(bytes.TrimLeft(code[markupStart:end], " \t\r\n"))
+		end = markupStart + len(bytes.TrimRight(code[markupStart:end], " \t\r\n"))
+		firstLine, _, _ := strings.Cut(string(code[start:end]), "\n")
+		blocks = append(blocks, componentBlock{
+			name:    "markup",
+			openTag: strings.TrimSpace(firstLine),
+			element: ByteRange{Start: start, End: end},
+			content: ByteRange{Start: start, End: end},
+		})
+	}
+
+	for i := 0; i < len(code); {
+		if code[i] != '<' {
+			i++
+			continue
+		}
+		if bytes.HasPrefix(code[i:], []byte("<!--")) {
+			end := bytes.Index(code[i+4:], []byte("-->"))
+			if end < 0 {
+				break
+			}
+			i += 4 + end + 3
+			continue
+		}
+
+		name := ""
+		for _, tag := range []string{"script", "style", "template"} {
+			if (tag != "template" || !svelte) && hasTagPrefix(code[i:], "<"+tag) {
+				name = tag
+				break
+			}
+		}
+		if name == "" {
+			i++
+			continue
+		}
+
+		openEnd := bytes.IndexByte(code[i:], '>')
+		if openEnd < 0 {
+			break
+		}
+		openEnd += i + 1
+		contentEnd, closeEnd := findClosingTag(code, openEnd, name)
+		if contentEnd < 0 {
+			break
+		}
+
+		addMarkup(i)
+		blocks = append(blocks, componentBlock{
+			name:    name,
+			openTag: string(code[i:openEnd]),
+			element: ByteRange{Start: i, End: closeEnd},
+			content: ByteRange{Start: openEnd, End: contentEnd},
+		})
+		i = closeEnd
+		markupStart = closeEnd
+	}
+	addMarkup(len(code))
+	return blocks
+}
+
+// hasTagPrefix reports whether code starts with an opening tag of the given name,
+// ignoring case, rather than a longer tag name sharing the prefix
+func hasTagPrefix(code []byte, open string) bool {
+	if len(code) <= len(open) || !strings.EqualFold(string(code[:len(open)]), open) {
+		return false
+	}
+	switch code[len(open)] {
+	case '>', ' ', '\t', '\r', '\n', '/':
+		return true
+	}
+	return false
+}
+
+// findClosingTag finds the closing tag of an element whose content starts at from,
+// counting nested elements of the same name. It returns the offsets of the start
+// and end of the closing tag, or -1 if the element is not closed.
+func findClosingTag(code []byte, from int, name string) (contentEnd, closeEnd int) {
+	depth := 1
+	for i := from; i < len(code); i++ {
+		if code[i] != '<' {
+			continue
+		}
+		switch {
+		case hasTagPrefix(code[i:], "</"+name):
+			depth--
+			if depth == 0 {
+				end := bytes.IndexByte(code[i:], '>')
+				if end < 0 {
+					return -1, -1
+				}
+				return i, i + end + 1
+			}
+		case name == "template" && hasTagPrefix(code[i:], "<"+name):
+			depth++
+		}
+	}
+	return -1, -1
+}
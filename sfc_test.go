@@ -0,0 +1,158 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleVueComponent = `<template>
+  <div class="counter">
+    <template v-if="visible"><span>{{ count }}</span></template>
+  </div>
+</template>
+
+<!-- <script>not a script</script> -->
+<script setup lang="ts">
+import { ref } from 'vue'
+
+const count = ref(0)
+
+function increment(step: number): void {
+  count.value += step
+}
+</script>
+
+<style scoped>
+.counter { color: red; }
+</style>
+`
+
+func TestChunkVueComponent(t *testing.T) {
+	chunks, err := Chunk("src/Counter.vue", sampleVueComponent, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) == 0 {
+		t.Fatal("Expected chunks from the script block")
+	}
+
+	chunk := chunks[0]
+	if chunk.Context.Language != LanguageTypeScript {
+		t.Errorf("Expected TypeScript for lang=\"ts\", got %q", chunk.Context.Language)
+	}
+	if got := sampleVueComponent[chunk.ByteRange.Start:chunk.ByteRange.End]; got != chunk.Text {
+		t.Errorf("Expected the byte range to locate the text in the file, got %q for %q", got, chunk.Text)
+	}
+	if !strings.Contains(chunk.Text, "function increment") || strings.Contains(chunk.Text, "<template>") {
+		t.Errorf("Expected only the script content, got %q", chunk.Text)
+	}
+
+	importLine := strings.Count(sampleVueComponent[:strings.Index(sampleVueComponent, "import {")], "\n")
+	if chunk.LineRange.Start != importLine {
+		t.Errorf("Expected the chunk to start on file line %d, got %d", importLine, chunk.LineRange.Start)
+	}
+	if chunk.Span.Start.Line != importLine {
+		t.Errorf("Expected the span to start on file line %d, got %+v", importLine, chunk.Span.Start)
+	}
+	for _, entity := range chunk.Context.Entities {
+		if entity.Name == "increment" && entity.LineRange.Start <= importLine {
+			t.Errorf("Expected entity lines relative to the file, got %+v", entity.LineRange)
+		}
+	}
+
+	var before, after []string
+	for _, sibling := range chunk.Context.Siblings {
+		if sibling.Type != EntityTypeSection {
+			continue
+		}
+		if sibling.Position == "before" {
+			before = append(before, sibling.Name)
+		} else {
+			after = append(after, sibling.Name)
+		}
+	}
+	if strings.Join(before, ",") != "template" || strings.Join(after, ",") != "style" {
+		t.Errorf("Expected the template before and the style after, got %v and %v", before, after)
+	}
+	if !strings.Contains(chunk.ContextualizedText, "<style scoped>") {
+		t.Errorf("Expected the style section's tag in the context, got %q", chunk.ContextualizedText)
+	}
+}
+
+func TestChunkVueComponentMultipleScripts(t *testing.T) {
+	code := `<script>
+export default { name: 'Counter' }
+</script>
+
+<script setup>
+const label = 'count'
+</script>
+`
+	chunks, err := Chunk("Counter.vue", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected one chunk per script block, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if chunk.Index != i || chunk.TotalChunks != 2 {
+			t.Errorf("Chunk %d: expected chunks numbered across the component, got %d of %d", i, chunk.Index, chunk.TotalChunks)
+		}
+		if chunk.Context.Language != LanguageJavaScript {
+			t.Errorf("Chunk %d: expected JavaScript without a lang attribute, got %q", i, chunk.Context.Language)
+		}
+	}
+	if chunks[1].LineRange.Start != 5 {
+		t.Errorf("Expected the second script on line 5, got %d", chunks[1].LineRange.Start)
+	}
+}
+
+func TestChunkSvelteComponent(t *testing.T) {
+	code := `<script context="module">
+  export const prerender = true
+</script>
+
+<script>
+  export let name = 'world'
+</script>
+
+<h1>Hello {name}!</h1>
+
+<style>
+  h1 { color: purple; }
+</style>
+`
+	chunks, err := Chunk("App.svelte", code, &ChunkOptions{LazyText: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 2 {
+		t.Fatalf("Expected one chunk per script block, got %d", len(chunks))
+	}
+
+	chunk := chunks[1]
+	if !strings.Contains(chunk.Content(), "export let name") {
+		t.Errorf("Expected lazy content sliced from the file, got %q", chunk.Content())
+	}
+	var names []string
+	for _, sibling := range chunk.Context.Siblings {
+		if sibling.Type == EntityTypeSection {
+			names = append(names, sibling.Position+":"+sibling.Name+":"+sibling.Signature)
+		}
+	}
+	want := "after:markup:<h1>Hello {name}!</h1>,after:style:<style>"
+	if strings.Join(names, ",") != want {
+		t.Errorf("Expected sections %q, got %q", want, strings.Join(names, ","))
+	}
+}
+
+func TestChunkComponentWithoutScript(t *testing.T) {
+	chunks, err := Chunk("Icon.vue", "<template>\n  <svg></svg>\n</template>\n", nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 1 || chunks[0].Context.Language != LanguageVue {
+		t.Fatalf("Expected one plain text chunk, got %+v", chunks)
+	}
+}
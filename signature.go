@@ -129,8 +129,46 @@ func tryExtractSignatureFromBody(node *sitter.Node, code []byte, lang Language)
 
 // extractSignature extracts the signature of an entity from its AST node
 func extractSignature(node *sitter.Node, entityType EntityType, lang Language, code []byte) string {
+	switch lang {
+	case LanguageSQL:
+		return extractSQLSignature(node, code)
+	case LanguageHCL:
+		return extractHCLSignature(node, code)
+	case LanguageProto:
+		if entityType != EntityTypeImport {
+			return extractProtoSignature(node, code)
+		}
+	case LanguageElixir:
+		if entityType != EntityTypeImport {
+			return extractElixirSignature(node, code)
+		}
+	case LanguageLua:
+		if entityType != EntityTypeImport {
+			return extractLuaSignature(node, code)
+		}
+	case LanguageGo:
+		if node.Type() == "func_literal" {
+			_, binding := goClosureBinding(node, code)
+			return binding + extractFunctionSignature(node, lang, code)
+		}
+		if sig := extractGoSignature(node, code); sig != "" {
+			return sig
+		}
+	case LanguageJavaScript, LanguageTypeScript:
+		if entityType, ok := jsExpressionEntities[node.Type()]; ok {
+			signature := extractFunctionSignature(node, lang, code)
+			if entityType == EntityTypeClass {
+				signature = extractClassSignature(node, lang, code)
+			}
+			return jsBindingText(node, code) + signature
+		}
+		if tsSignatureNodeTypes[node.Type()] {
+			return statementSignature(node, code)
+		}
+	}
+
 	switch entityType {
-	case EntityTypeFunction, EntityTypeMethod:
+	case EntityTypeFunction, EntityTypeMethod, EntityTypeProperty:
 		return extractFunctionSignature(node, lang, code)
 	case EntityTypeClass, EntityTypeInterface:
 		return extractClassSignature(node, lang, code)
@@ -138,6 +176,8 @@ func extractSignature(node *sitter.Node, entityType EntityType, lang Language, c
 		return extractTypeSignature(node, lang, code)
 	case EntityTypeImport, EntityTypeExport:
 		return extractImportExportSignature(node, code)
+	case EntityTypeNamespace:
+		return extractNamespaceSignature(node, lang, code)
 	default:
 		nodeText := string(code[node.StartByte():node.EndByte()])
 		firstNewline := strings.Index(nodeText, "\n")
@@ -184,6 +224,23 @@ func extractClassSignature(node *sitter.Node, lang Language, code []byte) string
 	return cleanSignature(strings.TrimSpace(nodeText[:delimPos]))
 }
 
+// extractNamespaceSignature extracts the signature of a namespace: the declaration
+// before the body of a Rust mod or TypeScript namespace block, or a Java package
+// declaration without its semicolon
+func extractNamespaceSignature(node *sitter.Node, lang Language, code []byte) string {
+	if sig := tryExtractSignatureFromBody(node, code, lang); sig != "" {
+		return sig
+	}
+	return cleanSignature(strings.TrimSuffix(strings.TrimSpace(node.Content(code)), ";"))
+}
+
+// statementSignature returns the signature of a declaration without a body, such
+// as a TypeScript overload or an export clause: the whole statement without its
+// semicolon
+func statementSignature(node *sitter.Node, code []byte) string {
+	return cleanSignature(strings.TrimSuffix(strings.TrimSpace(node.Content(code)), ";"))
+}
+
 func extractTypeSignature(node *sitter.Node, lang Language, code []byte) string {
 	nodeText := string(code[node.StartByte():node.EndByte()])
 
@@ -216,6 +273,187 @@ func extractTypeSignature(node *sitter.Node, lang Language, code []byte) string
 	return cleanSignature(strings.TrimSpace(nodeText[:delimPos]))
 }
 
+// sqlBodyNodeTypes are the node types where the signature of a SQL definition ends:
+// table columns, view queries and function languages and bodies
+var sqlBodyNodeTypes = map[string]bool{
+	"column_definitions": true,
+	"keyword_as":         true,
+	"function_language":  true,
+	"function_body":      true,
+}
+
+// extractSQLSignature extracts the signature of a CREATE statement, which ends
+// before its column definitions, query or function body
+func extractSQLSignature(node *sitter.Node, code []byte) string {
+	end := node.EndByte()
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); sqlBodyNodeTypes[child.Type()] {
+			end = child.StartByte()
+			break
+		}
+	}
+	return cleanSignature(string(code[node.StartByte():end]))
+}
+
+// extractHCLSignature extracts the signature of an HCL block: its type and labels
+func extractHCLSignature(node *sitter.Node, code []byte) string {
+	end := node.EndByte()
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == "block_start" {
+			end = child.StartByte()
+			break
+		}
+	}
+	return cleanSignature(string(code[node.StartByte():end]))
+}
+
+// protoBodyNodeTypes are the node types where the signature of a protobuf
+// definition ends: its body, or the semicolon of an rpc without options
+var protoBodyNodeTypes = map[string]bool{
+	"message_body": true,
+	"enum_body":    true,
+	"{":            true,
+	";":            true,
+}
+
+// extractProtoSignature extracts the signature of a protobuf message, enum,
+// service or rpc, such as "rpc GetUser(GetUserRequest) returns (User)"
+func extractProtoSignature(node *sitter.Node, code []byte) string {
+	end := node.EndByte()
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); protoBodyNodeTypes[child.Type()] {
+			end = child.StartByte()
+			break
+		}
+	}
+	return cleanSignature(string(code[node.StartByte():end]))
+}
+
+// extractGoSignature extracts the signature of a Go function, method or type
+// declaration from its fields, so that the brackets of type parameter lists,
+// generic receivers and constraints such as interface{ ~int | ~float64 } are not
+// taken for its body, and lists broken over lines read as on one line:
+// "func (c *Cache[K, V]) Get(k K) (V, bool)". Returns "" for other nodes.
+func extractGoSignature(node *sitter.Node, code []byte) string {
+	switch node.Type() {
+	case "function_declaration", "method_declaration":
+		var b strings.Builder
+		b.WriteString("func ")
+		if receiver := node.ChildByFieldName("receiver"); receiver != nil {
+			b.WriteString(goParameterList(receiver, code) + " ")
+		}
+		if name := node.ChildByFieldName("name"); name != nil {
+			b.WriteString(name.Content(code))
+		}
+		if typeParams := node.ChildByFieldName("type_parameters"); typeParams != nil {
+			b.WriteString(goParameterList(typeParams, code))
+		}
+		if params := node.ChildByFieldName("parameters"); params != nil {
+			b.WriteString(goParameterList(params, code))
+		}
+		if result := node.ChildByFieldName("result"); result != nil {
+			b.WriteString(" " + goParameterList(result, code))
+		}
+		return b.String()
+
+	case "type_declaration":
+		// A grouped declaration is named by its first spec
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			if spec := node.NamedChild(i); spec.Type() == "type_spec" || spec.Type() == "type_alias" {
+				return "type " + goTypeSpecSignature(spec, code)
+			}
+		}
+	}
+	return ""
+}
+
+// goTypeSpecSignature returns the signature of a Go type spec without the type
+// keyword: its name, type parameters and type, abbreviated to struct or interface
+// for struct and interface types ("Pair[K comparable, V any] struct")
+func goTypeSpecSignature(spec *sitter.Node, code []byte) string {
+	name, typeNode := spec.ChildByFieldName("name"), spec.ChildByFieldName("type")
+	if name == nil || typeNode == nil {
+		return cleanSignature(spec.Content(code))
+	}
+
+	signature := name.Content(code)
+	end := name.EndByte()
+	if typeParams := spec.ChildByFieldName("type_parameters"); typeParams != nil {
+		signature += goParameterList(typeParams, code)
+		end = typeParams.EndByte()
+	}
+	// An alias has = before its type, which the grammar may leave in an ERROR node
+	// for a generic alias
+	if strings.Contains(string(code[end:typeNode.StartByte()]), "=") {
+		signature += " ="
+	}
+
+	switch typeNode.Type() {
+	case "struct_type":
+		return signature + " struct"
+	case "interface_type":
+		return signature + " interface"
+	default:
+		return signature + " " + goParameterList(typeNode, code)
+	}
+}
+
+// goParameterList returns a Go parameter, type parameter or result list on one
+// line, without its comments or the trailing comma of a list broken over lines,
+// or any other node with whitespace collapsed
+func goParameterList(node *sitter.Node, code []byte) string {
+	if node.Type() != "parameter_list" && node.Type() != "type_parameter_list" {
+		return tightenBrackets(cleanSignature(node.Content(code)))
+	}
+
+	text := node.Content(code)
+	items := make([]string, 0, node.NamedChildCount())
+	for i := 0; i < int(node.NamedChildCount()); i++ {
+		if child := node.NamedChild(i); child.Type() != "comment" {
+			items = append(items, goParameterList(child, code))
+		}
+	}
+	return text[:1] + strings.Join(items, ", ") + text[len(text)-1:]
+}
+
+// tightenBrackets removes the spaces and trailing commas a collapsed line break
+// leaves inside parentheses and brackets: "func( k K, ) error" becomes
+// "func(k K) error"
+func tightenBrackets(sig string) string {
+	sig = strings.NewReplacer("( ", "(", "[ ", "[", " )", ")", " ]", "]").Replace(sig)
+	return strings.NewReplacer(",)", ")", ",]", "]").Replace(sig)
+}
+
+// extractElixirSignature extracts the signature of an Elixir definition: the macro
+// and head of a function ("def create(attrs) when is_map(attrs)") or the macro and
+// arguments of a module ("defimpl Size, for: Map")
+func extractElixirSignature(node *sitter.Node, code []byte) string {
+	end := node.EndByte()
+	if head := elixirDefinitionHead(node); head != nil {
+		end = head.EndByte()
+		if head.Type() == "alias" {
+			end = head.Parent().EndByte()
+		}
+	}
+	return cleanSignature(string(code[node.StartByte():end]))
+}
+
+// extractLuaSignature extracts the signature of a Lua function statement, without
+// the LDoc comment the grammar includes in it
+func extractLuaSignature(node *sitter.Node, code []byte) string {
+	start, end := node.StartByte(), node.EndByte()
+	if doc := node.ChildByFieldName("documentation"); doc != nil {
+		start = doc.EndByte()
+	}
+	for i := 0; i < int(node.ChildCount()); i++ {
+		if child := node.Child(i); child.Type() == "function_body" || child.Type() == "function_end" {
+			end = child.StartByte()
+			break
+		}
+	}
+	return cleanSignature(string(code[start:end]))
+}
+
 func extractImportExportSignature(node *sitter.Node, code []byte) string {
 	nodeText := string(code[node.StartByte():node.EndByte()])
 	return cleanSignature(nodeText)
@@ -341,6 +579,13 @@ func extractRustUsePath(node *sitter.Node, code []byte) string {
 		return ""
 	}
 
+	// use a::B as C imports from the path, without the alias
+	if node.Type() == "use_as_clause" {
+		if pathChild := node.ChildByFieldName("path"); pathChild != nil {
+			return string(code[pathChild.StartByte():pathChild.EndByte()])
+		}
+	}
+
 	if node.Type() == "scoped_identifier" {
 		lastChild := node.Child(int(node.ChildCount()) - 1)
 		if lastChild != nil && lastChild.Type() == "use_list" {
@@ -46,6 +46,50 @@ func TestExtractSignatureGo(t *testing.T) {
 	}
 }
 
+func TestExtractSignatureGoGenerics(t *testing.T) {
+	tests := []struct {
+		code     string
+		name     string
+		expected string
+	}{
+		{
+			"func Map[T any, U any](s []T, f func(T) U) []U {\n\treturn nil\n}",
+			"Map", "func Map[T any, U any](s []T, f func(T) U) []U",
+		},
+		{
+			"func Sum[T interface{ ~int | ~float64 }](xs ...T) (total T) { return }",
+			"Sum", "func Sum[T interface{ ~int | ~float64 }](xs ...T) (total T)",
+		},
+		{
+			"func Invert[\n\tK comparable, // keys\n\tV comparable,\n](m map[K]V) map[V]K {\n\treturn nil\n}",
+			"Invert", "func Invert[K comparable, V comparable](m map[K]V) map[V]K",
+		},
+		{
+			"func (c *Cache[K, V]) Get(k K) (V, bool) {\n\tvar v V\n\treturn v, false\n}",
+			"Get", "func (c *Cache[K, V]) Get(k K) (V, bool)",
+		},
+		{
+			"type Set[T comparable] map[T]struct{}",
+			"Set", "type Set[T comparable] map[T]struct{}",
+		},
+		{
+			"type Tree[T interface{ Less(T) bool }] struct {\n\troot *T\n}",
+			"Tree", "type Tree[T interface{ Less(T) bool }] struct",
+		},
+		{
+			"type (\n\tList[T any] []T\n\tMap[K comparable, V any] map[K]V\n)",
+			"List", "type List[T any] []T",
+		},
+	}
+
+	for _, tt := range tests {
+		entity := entityByName(t, "package p\n\n"+tt.code, LanguageGo, tt.name)
+		if entity.Signature != tt.expected {
+			t.Errorf("Signature of %s = %q, want %q", tt.name, entity.Signature, tt.expected)
+		}
+	}
+}
+
 func TestExtractSignatureTypeScript(t *testing.T) {
 	tests := []struct {
 		code     string
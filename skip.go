@@ -0,0 +1,231 @@
+package codechunk
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// SkipReason identifies why a SkipPolicy skipped a file
+type SkipReason string
+
+const (
+	SkipReasonBinary    SkipReason = "binary"
+	SkipReasonGenerated SkipReason = "generated"
+	SkipReasonMinified  SkipReason = "minified"
+	SkipReasonTooLarge  SkipReason = "too-large"
+	SkipReasonBarrel    SkipReason = "barrel"
+)
+
+// SkipPolicy selects files that batch runs skip instead of chunking. Skipped files
+// are returned with Skipped set and an Error matching ErrSkipped that names the
+// reason. The zero value skips nothing.
+type SkipPolicy struct {
+	Binary      bool `json:"binary,omitempty"`      // Skip files with NUL bytes near the start
+	Generated   bool `json:"generated,omitempty"`   // Skip generated files, by name (*.pb.go, *_pb2.py, ...) or header marker ("Code generated ... DO NOT EDIT", "@generated")
+	Minified    bool `json:"minified,omitempty"`    // Skip minified JavaScript and TypeScript
+	MaxFileSize int  `json:"maxFileSize,omitempty"` // Skip files larger than this many bytes (default: 0, no limit)
+	Barrel      bool `json:"barrel,omitempty"`      // Skip JavaScript and TypeScript barrel files, which only re-export other modules
+}
+
+// DefaultSkipPolicy returns a policy skipping binary, generated and minified files
+// and files larger than 5 MB.
+func DefaultSkipPolicy() SkipPolicy {
+	return SkipPolicy{
+		Binary:      true,
+		Generated:   true,
+		Minified:    true,
+		MaxFileSize: 5 << 20,
+	}
+}
+
+const (
+	// binarySniffLen is how much of a file is checked for NUL bytes, as git does
+	binarySniffLen = 8000
+	// generatedHeaderLen is how much of a file is searched for generated-code markers
+	generatedHeaderLen = 1024
+	// minifiedMinSize and minifiedLineLen: files larger than minifiedMinSize whose
+	// average line is longer than minifiedLineLen bytes are considered minified
+	minifiedMinSize = 1024
+	minifiedLineLen = 300
+	// barrelMaxSize: larger files are not parsed to check whether they are barrels
+	barrelMaxSize = 16 << 10
+)
+
+// generatedNameSuffixes are file name suffixes used by common code generators
+var generatedNameSuffixes = []string{
+	".pb.go",
+	".pb.gw.go",
+	"_generated.go",
+	".gen.go",
+	"_pb2.py",
+	"_pb2_grpc.py",
+	"_pb2.pyi",
+	".generated.ts",
+	".generated.js",
+	"_pb.js",
+	"_pb.d.ts",
+}
+
+// goGeneratedComment matches the line Go generators mark their output with, as
+// specified by go generate
+var goGeneratedComment = regexp.MustCompile(`^// Code generated .* DO NOT EDIT\.$`)
+
+// lineCommentPrefixes start the comment lines of a file header, before any code
+var lineCommentPrefixes = []string{"//", "#", "--", ";", "*", "/*", "<!--"}
+
+// skipReason returns why the policy skips file, or "" if it should be chunked
+func (p SkipPolicy) skipReason(file FileInput) SkipReason {
+	code := file.Code
+
+	if p.MaxFileSize > 0 && len(code) > p.MaxFileSize {
+		return SkipReasonTooLarge
+	}
+	if p.Binary && isBinary(code) {
+		return SkipReasonBinary
+	}
+	if p.Generated && isGenerated(file.Filepath, code) {
+		return SkipReasonGenerated
+	}
+	if p.Minified && isMinified(file.Filepath, code) {
+		return SkipReasonMinified
+	}
+	if p.Barrel && isBarrel(file.Filepath, code) {
+		return SkipReasonBarrel
+	}
+	return ""
+}
+
+// skipped returns the skipped result for a file the policy skips
+func (p SkipPolicy) skipped(file FileInput) (BatchResult, bool) {
+	reason := p.skipReason(file)
+	if reason == "" {
+		return BatchResult{}, false
+	}
+	return BatchResult{
+		Filepath: file.Filepath,
+		Error:    fmt.Errorf("%w: %s", ErrSkipped, reason),
+		Skipped:  true,
+	}, true
+}
+
+// isBinary reports whether code has a NUL byte near the start
+func isBinary(code string) bool {
+	if len(code) > binarySniffLen {
+		code = code[:binarySniffLen]
+	}
+	return strings.IndexByte(code, 0) >= 0
+}
+
+// isGenerated reports whether a file is named like generated code or starts
+// with a generated-code marker
+func isGenerated(filepath, code string) bool {
+	name := strings.ToLower(path.Base(strings.ReplaceAll(filepath, "\\", "/")))
+	for _, suffix := range generatedNameSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	if len(code) > generatedHeaderLen {
+		code = code[:generatedHeaderLen]
+	}
+	return hasGeneratedHeader(code)
+}
+
+// hasGeneratedHeader reports whether the comments at the start of code, before
+// any code, have the Go "// Code generated ... DO NOT EDIT." line or an
+// @generated marker. Ordinary comments mentioning generated code or asking not to
+// edit something don't mark a file as generated.
+func hasGeneratedHeader(code string) bool {
+	inBlock := false
+	for rest := code; rest != ""; {
+		var line string
+		line, rest, _ = strings.Cut(rest, "\n")
+		line = strings.TrimSuffix(line, "\r")
+		if goGeneratedComment.MatchString(line) {
+			return true
+		}
+
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			continue
+		case inBlock:
+			inBlock = !strings.Contains(trimmed, "*/") && !strings.Contains(trimmed, "-->")
+		case strings.HasPrefix(trimmed, "/*"):
+			inBlock = !strings.Contains(trimmed[2:], "*/")
+		case strings.HasPrefix(trimmed, "<!--"):
+			inBlock = !strings.Contains(trimmed, "-->")
+		case !hasAnyPrefix(trimmed, lineCommentPrefixes):
+			return false
+		}
+		if strings.Contains(trimmed, "@generated") {
+			return true
+		}
+	}
+	return false
+}
+
+// hasAnyPrefix reports whether s starts with one of prefixes
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMinified reports whether a JavaScript or TypeScript file is minified: named
+// *.min.js or made of very long lines
+func isMinified(filepath, code string) bool {
+	lang := DetectLanguage(filepath)
+	if lang != LanguageJavaScript && lang != LanguageTypeScript {
+		return false
+	}
+	if strings.HasSuffix(strings.ToLower(filepath), ".min.js") {
+		return true
+	}
+	return hasMinifiedLines(code)
+}
+
+// hasMinifiedLines reports whether code is larger than minifiedMinSize with an
+// average line longer than minifiedLineLen, as minified code is in any language
+func hasMinifiedLines(code string) bool {
+	if len(code) < minifiedMinSize {
+		return false
+	}
+	lines := strings.Count(strings.TrimRight(code, "\n"), "\n") + 1
+	return len(code)/lines > minifiedLineLen
+}
+
+// isBarrel reports whether a JavaScript or TypeScript file is a barrel file, such
+// as an index.ts made only of imports and export clauses re-exporting other
+// modules. Barrels are small, so files over barrelMaxSize are not parsed.
+func isBarrel(filepath, code string) bool {
+	lang := DetectLanguage(filepath)
+	if (lang != LanguageJavaScript && lang != LanguageTypeScript) || len(code) > barrelMaxSize || !strings.Contains(code, "export") {
+		return false
+	}
+	result, err := parse([]byte(code), lang)
+	if err != nil {
+		return false
+	}
+	defer result.Tree.Close()
+
+	root := result.Tree.RootNode()
+	exports := 0
+	for i := 0; i < int(root.NamedChildCount()); i++ {
+		child := root.NamedChild(i)
+		switch {
+		case isCommentNode(child) || child.Type() == "import_statement":
+		case child.Type() == "export_statement" && child.ChildByFieldName("declaration") == nil && child.ChildByFieldName("value") == nil:
+			exports++
+		default:
+			return false
+		}
+	}
+	return exports > 0
+}
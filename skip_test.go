@@ -0,0 +1,95 @@
+package codechunk
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSkipPolicySkipReason(t *testing.T) {
+	minified := "var a=1;" + strings.Repeat("function f(){return 1};", 100)
+	tests := []struct {
+		name     string
+		file     FileInput
+		expected SkipReason
+	}{
+		{"source", FileInput{Filepath: "main.go", Code: "package main\n"}, ""},
+		{"binary", FileInput{Filepath: "blob.go", Code: "package main\x00\x01"}, SkipReasonBinary},
+		{"protobuf", FileInput{Filepath: "api/v1/api.pb.go", Code: "package v1\n"}, SkipReasonGenerated},
+		{"python protobuf", FileInput{Filepath: "api_pb2.py", Code: "import os\n"}, SkipReasonGenerated},
+		{"go marker", FileInput{Filepath: "zz.go", Code: "// Code generated by stringer. DO NOT EDIT.\n\npackage main\n"}, SkipReasonGenerated},
+		{"at-generated", FileInput{Filepath: "schema.ts", Code: "/** @generated */\nexport const a = 1;\n"}, SkipReasonGenerated},
+		{"python at-generated", FileInput{Filepath: "schema.py", Code: "#!/usr/bin/env python\n# @generated by codegen\nx = 1\n"}, SkipReasonGenerated},
+		{"block at-generated", FileInput{Filepath: "schema.ts", Code: "/*\n * Schema types.\n * @generated\n */\nexport const a = 1;\n"}, SkipReasonGenerated},
+		{"do not edit comment", FileInput{Filepath: "tuning.go", Code: "package tuning\n\n// Values below are tuned by hand; do not edit without benchmarking.\nconst Factor = 3\n"}, ""},
+		{"do not edit header", FileInput{Filepath: "tuning.go", Code: "// Values below are tuned by hand; do not edit without benchmarking.\npackage tuning\n"}, ""},
+		{"code generated comment", FileInput{Filepath: "lint.py", Code: "# Checks style of code generated by users\nimport ast\n"}, ""},
+		{"auto-generated comment", FileInput{Filepath: "rows.go", Code: "// Rows use auto-generated IDs\npackage rows\n"}, ""},
+		{"marker after code", FileInput{Filepath: "gen.ts", Code: "export const a = 1;\n// @generated\n"}, ""},
+		{"min.js", FileInput{Filepath: "vendor/lib.min.js", Code: "var a=1;"}, SkipReasonMinified},
+		{"long lines", FileInput{Filepath: "bundle.js", Code: minified}, SkipReasonMinified},
+		{"long python line", FileInput{Filepath: "data.py", Code: "x = '" + strings.Repeat("a", 2000) + "'\n"}, ""},
+		{"too large", FileInput{Filepath: "big.go", Code: strings.Repeat("\n", 6<<20)}, SkipReasonTooLarge},
+		{"barrel", FileInput{Filepath: "src/index.ts", Code: "// Components\nimport { Button } from './button';\nexport * from './card';\nexport { Button };\n"}, SkipReasonBarrel},
+		{"barrel with code", FileInput{Filepath: "src/index.ts", Code: "export * from './card';\nexport const version = '1';\n"}, ""},
+		{"imports only", FileInput{Filepath: "src/setup.js", Code: "import './polyfills';\n"}, ""},
+	}
+
+	policy := DefaultSkipPolicy()
+	policy.Barrel = true
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if reason := policy.skipReason(tt.file); reason != tt.expected {
+				t.Errorf("skipReason(%s) = %q, expected %q", tt.file.Filepath, reason, tt.expected)
+			}
+		})
+	}
+
+	if reason := (SkipPolicy{}).skipReason(tests[1].file); reason != "" {
+		t.Errorf("Expected the zero policy to skip nothing, got %q", reason)
+	}
+}
+
+func TestChunkBatchSkipPolicy(t *testing.T) {
+	files := []FileInput{
+		{Filepath: "main.go", Code: "package main\n\nfunc main() {}\n"},
+		{Filepath: "api.pb.go", Code: "package main\n\nfunc api() {}\n"},
+		{Filepath: "image.py", Code: "\x89PNG\x00\x00"},
+	}
+
+	var successes int
+	opts := &BatchOptions{
+		SkipPolicy: DefaultSkipPolicy(),
+		OnProgress: func(completed, total int, filepath string, success bool) {
+			if success {
+				successes++
+			}
+		},
+	}
+	results := ChunkBatch(files, opts)
+
+	if results[0].Error != nil || results[0].Skipped || len(results[0].Chunks) == 0 {
+		t.Errorf("Expected main.go to be chunked, got %+v", results[0])
+	}
+	for _, result := range results[1:] {
+		if !result.Skipped || !errors.Is(result.Error, ErrSkipped) || result.Chunks != nil {
+			t.Errorf("Expected %s to be skipped, got %+v", result.Filepath, result)
+		}
+	}
+	if !strings.Contains(results[2].Error.Error(), string(SkipReasonBinary)) {
+		t.Errorf("Expected the error to name the reason, got %q", results[2].Error)
+	}
+	if successes != len(files) {
+		t.Errorf("Expected skipped files to be reported as successful, got %d successes", successes)
+	}
+
+	var streamed int
+	for result := range ChunkBatchStream(files, opts) {
+		if result.Skipped {
+			streamed++
+		}
+	}
+	if streamed != 2 {
+		t.Errorf("Expected 2 skipped results from the stream, got %d", streamed)
+	}
+}
@@ -0,0 +1,128 @@
+package codechunk
+
+import "unicode/utf8"
+
+// slidingWindowEntityRatio: a file has a low entity density when it has more than
+// this many times MaxChunkSize NWS characters per entity
+const slidingWindowEntityRatio = 4
+
+// textSegment is a line of text, or a part of a line longer than the window size,
+// with its byte offsets
+type textSegment struct {
+	start, end int
+	line       int
+	nws        int
+}
+
+// useSlidingWindow reports whether code is chunked by the sliding-window strategy,
+// either chosen in opts or as the fallback for code that needs it or has too many
+// parse errors
+func useSlidingWindow(code []byte, scopeTree *ScopeTree, parseErr *ParseError, opts ChunkOptions) bool {
+	opts = opts.withDefaults()
+	return opts.ChunkStrategy == ChunkStrategySlidingWindow ||
+		(opts.SlidingWindowFallback && (needsSlidingWindow(code, scopeTree, opts) || tooManyParseErrors(parseErr, len(code), opts)))
+}
+
+// needsSlidingWindow reports whether code larger than a chunk should be chunked by
+// sliding windows of lines rather than by its syntax tree: because it looks
+// minified, or because it has no entities other than imports and exports or too
+// few for its size, as data files and bundles do
+func needsSlidingWindow(code []byte, scopeTree *ScopeTree, opts ChunkOptions) bool {
+	nws := countNws(string(code))
+	if nws <= opts.MaxChunkSize {
+		return false
+	}
+	if hasMinifiedLines(string(code)) {
+		return true
+	}
+
+	entities := 0
+	for _, entity := range scopeTree.AllEntities {
+		if entity.Type != EntityTypeImport && entity.Type != EntityTypeExport {
+			entities++
+		}
+	}
+	return entities == 0 || nws/entities > slidingWindowEntityRatio*opts.MaxChunkSize
+}
+
+// chunkSlidingWindow chunks code as plain text in windows of whole lines of at most
+// MaxChunkSize NWS characters, each starting OverlapLines lines before the end of
+// the previous one. Lines longer than MaxChunkSize are split on their own.
+func chunkSlidingWindow(filepath string, code []byte, lang Language, opts ChunkOptions) []CodeChunk {
+	opts = opts.withDefaults()
+	text := string(code)
+	pieces := slidingWindowPieces(text, opts.MaxChunkSize, opts.OverlapLines)
+	return textChunks(filepath, text, lang, pieces, opts, true)
+}
+
+// slidingWindowPieces splits text into overlapping windows of segments of at most
+// maxSize NWS characters, consecutive windows sharing up to overlap segments
+func slidingWindowPieces(text string, maxSize, overlap int) []*rebuiltText {
+	segments := splitTextSegments(text, maxSize)
+	pieces := make([]*rebuiltText, 0)
+
+	for first := 0; first < len(segments); {
+		// Windows start and end at segments that are not blank
+		for first < len(segments) && segments[first].nws == 0 {
+			first++
+		}
+		if first == len(segments) {
+			break
+		}
+
+		last, size := first, segments[first].nws
+		for last+1 < len(segments) && size+segments[last+1].nws <= maxSize {
+			last++
+			size += segments[last].nws
+		}
+		end := last
+		for segments[end].nws == 0 {
+			end--
+		}
+
+		start, stop := segments[first].start, segments[end].end
+		pieces = append(pieces, &rebuiltText{
+			text:      text[start:stop],
+			byteRange: ByteRange{Start: start, End: stop},
+			lineRange: LineRange{Start: segments[first].line, End: segments[end].line},
+		})
+		if last == len(segments)-1 {
+			break
+		}
+
+		next := end + 1 - overlap
+		if next <= first {
+			next = first + 1
+		}
+		first = next
+	}
+	return pieces
+}
+
+// splitTextSegments splits text into lines, and lines longer than maxSize NWS
+// characters into segments of at most maxSize, breaking between runes
+func splitTextSegments(text string, maxSize int) []textSegment {
+	lines := splitTextLines(text)
+	segments := make([]textSegment, 0, len(lines))
+	for i, line := range lines {
+		if line.nws <= maxSize {
+			segments = append(segments, textSegment{start: line.start, end: line.end, line: i, nws: line.nws})
+			continue
+		}
+
+		start, nws := line.start, 0
+		for pos := line.start; pos < line.end; {
+			_, width := utf8.DecodeRuneInString(text[pos:line.end])
+			if !isWhitespace(text[pos]) {
+				if nws > 0 && nws+width > maxSize {
+					segments = append(segments, textSegment{start: start, end: pos, line: i, nws: nws})
+					start, nws = pos, 0
+				}
+				nws += width
+			}
+			pos += width
+		}
+		segments = append(segments, textSegment{start: start, end: line.end, line: i, nws: nws})
+	}
+	return segments
+}
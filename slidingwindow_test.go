@@ -0,0 +1,140 @@
+package codechunk
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSlidingWindowPieces(t *testing.T) {
+	text := "a1 a2\nb1 b2\n\nc1 c2\nd1 d2\ne1 e2\n"
+	pieces := slidingWindowPieces(text, 8, 1)
+
+	expected := []string{
+		"a1 a2\nb1 b2",
+		"b1 b2\n\nc1 c2",
+		"c1 c2\nd1 d2",
+		"d1 d2\ne1 e2",
+	}
+	if len(pieces) != len(expected) {
+		t.Fatalf("expected %d pieces, got %d", len(expected), len(pieces))
+	}
+	for i, piece := range pieces {
+		if piece.text != expected[i] {
+			t.Errorf("piece %d: expected %q, got %q", i, expected[i], piece.text)
+		}
+		if text[piece.byteRange.Start:piece.byteRange.End] != piece.text {
+			t.Errorf("piece %d: byte range %v does not match its text", i, piece.byteRange)
+		}
+	}
+	if pieces[1].lineRange != (LineRange{Start: 1, End: 3}) {
+		t.Errorf("expected lines 1-3, got %v", pieces[1].lineRange)
+	}
+}
+
+func TestSlidingWindowPiecesLongLine(t *testing.T) {
+	text := strings.Repeat("x", 25) + "\nshorter\n"
+	pieces := slidingWindowPieces(text, 10, 0)
+
+	var texts []string
+	for _, piece := range pieces {
+		texts = append(texts, piece.text)
+		if countNws(piece.text) > 10 {
+			t.Errorf("piece %q exceeds the window size", piece.text)
+		}
+	}
+	if len(pieces) != 4 || pieces[3].text != "shorter" || pieces[2].lineRange.Start != 0 {
+		t.Errorf("expected the long line in three pieces, got %q", texts)
+	}
+}
+
+func TestChunkSlidingWindowFallback(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("export default [\n")
+	for i := 0; i < 200; i++ {
+		fmt.Fprintf(&b, "  { id: %d, name: 'item-%d' },\n", i, i)
+	}
+	b.WriteString("]\n")
+	code := b.String()
+
+	opts := NewChunkOptions(WithMaxChunkSize(300), WithOverlapLines(2), WithSlidingWindowFallback(true))
+	chunks, err := Chunk("data.js", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("expected several chunks, got %d", len(chunks))
+	}
+	for i, chunk := range chunks {
+		if !chunk.Context.SlidingWindow {
+			t.Errorf("chunk %d: expected the sliding-window flag", i)
+		}
+		if i > 0 && chunk.LineRange.Start != chunks[i-1].LineRange.End-1 {
+			t.Errorf("chunk %d: expected two lines of overlap, got lines %v after %v", i, chunk.LineRange, chunks[i-1].LineRange)
+		}
+	}
+
+	// Files with enough entities are chunked by their syntax tree
+	var funcs strings.Builder
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&funcs, "function f%d() {\n  return %d\n}\n\n", i, i)
+	}
+	chunks, err = Chunk("funcs.js", funcs.String(), &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for _, chunk := range chunks {
+		if chunk.Context.SlidingWindow {
+			t.Fatal("expected no sliding-window chunks for a file with entities")
+		}
+	}
+}
+
+func TestChunkSlidingWindowStrategy(t *testing.T) {
+	code := "func a() {}\nfunc b() {}\nfunc c() {}\n"
+	opts := NewChunkOptions(WithChunkStrategy(ChunkStrategySlidingWindow), WithMaxChunkSize(20), WithOverlapLines(1))
+	chunks, err := Chunk("main.go", "package main\n\n"+code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) != 3 || chunks[1].Text != "func a() {}\nfunc b() {}" {
+		t.Errorf("unexpected sliding-window chunks: %+v", chunks)
+	}
+}
+
+func TestChunkMaxParseErrorRatio(t *testing.T) {
+	// Python in a .go file is mostly ERROR nodes
+	code := "def greet(name):\n    print(f\"hello {name}\")\n\nclass User:\n    def __init__(self):\n        self.name = None\n"
+
+	opts := NewChunkOptions(WithMaxParseErrorRatio(0.5))
+	_, err := Chunk("misnamed.go", code, &opts)
+	var chunkErr *ChunkError
+	if !errors.Is(err, ErrParseFailed) || !errors.As(err, &chunkErr) || chunkErr.Stage != StageParse {
+		t.Fatalf("Expected a parse ChunkError, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "MaxParseErrorRatio") {
+		t.Errorf("Expected the error to name the ratio, got %q", err.Error())
+	}
+	if _, err := ChunkStream("misnamed.go", code, &opts); !errors.Is(err, ErrParseFailed) {
+		t.Errorf("Expected ChunkStream to fail too, got %v", err)
+	}
+
+	// With the sliding-window fallback the file is chunked as plain text
+	opts.SlidingWindowFallback = true
+	chunks, err := Chunk("misnamed.go", code, &opts)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	for _, chunk := range chunks {
+		if !chunk.Context.SlidingWindow {
+			t.Error("Expected sliding-window chunks")
+		}
+	}
+
+	// A few broken lines stay under the ratio
+	opts = NewChunkOptions(WithMaxParseErrorRatio(0.5))
+	if _, err := Chunk("main.go", "package main\n\nfunc broken() {\n\tx := \n}\n\nfunc ok() int {\n\treturn 1\n}\n", &opts); err != nil {
+		t.Errorf("Expected a mostly valid file to be chunked, got %v", err)
+	}
+}
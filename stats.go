@@ -0,0 +1,34 @@
+package codechunk
+
+// TokenCounter counts the tokens of a chunk's text, usually with the tokenizer of
+// the embedding model the chunks are for
+type TokenCounter func(text string) int
+
+// ChunkStats describes the size of a chunk's text, so oversized chunks can be
+// filtered without re-tokenizing them
+type ChunkStats struct {
+	Bytes           int `json:"bytes"`           // Length of the text in bytes
+	NwsBytes        int `json:"nwsBytes"`        // Non-whitespace bytes, the unit of MaxChunkSize
+	Lines           int `json:"lines"`           // Number of lines spanned
+	EstimatedTokens int `json:"estimatedTokens"` // Tokens counted by ChunkOptions.TokenCounter (default: EstimateTokens)
+}
+
+// EstimateTokens estimates the number of tokens in text at four bytes per token,
+// a common approximation for code with BPE tokenizers
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// chunkStats computes the statistics of a chunk's text spanning lines
+func chunkStats(text string, lines LineRange, opts ChunkOptions) ChunkStats {
+	counter := opts.TokenCounter
+	if counter == nil {
+		counter = EstimateTokens
+	}
+	return ChunkStats{
+		Bytes:           len(text),
+		NwsBytes:        countNws(text),
+		Lines:           lines.Len(),
+		EstimatedTokens: counter(text),
+	}
+}
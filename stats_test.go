@@ -0,0 +1,54 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunkStats(t *testing.T) {
+	code := "package main\n\nfunc add(a, b int) int {\n\treturn a + b\n}\n"
+	chunks, err := Chunk("main.go", code, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+
+	for _, chunk := range chunks {
+		stats := chunk.Stats
+		if stats.Bytes != len(chunk.Text) || stats.NwsBytes != countNws(chunk.Text) {
+			t.Errorf("Expected byte counts of the text, got %+v for %q", stats, chunk.Text)
+		}
+		if stats.Lines != chunk.LineRange.Len() {
+			t.Errorf("Expected %d lines, got %d", chunk.LineRange.Len(), stats.Lines)
+		}
+		if stats.EstimatedTokens != EstimateTokens(chunk.Text) || stats.EstimatedTokens == 0 {
+			t.Errorf("Expected the default token estimate, got %d", stats.EstimatedTokens)
+		}
+	}
+}
+
+func TestChunkStatsTokenCounter(t *testing.T) {
+	words := func(text string) int { return len(strings.Fields(text)) }
+	code := "# Notes\n\nSome words here.\n"
+
+	for _, opts := range []ChunkOptions{
+		NewChunkOptions(WithTokenCounter(words)),
+		NewChunkOptions(WithTokenCounter(words), WithLazyText(true)),
+	} {
+		chunks, err := Chunk("notes.md", code, &opts)
+		if err != nil {
+			t.Fatalf("Chunk failed: %v", err)
+		}
+		if len(chunks) != 1 || chunks[0].Stats.EstimatedTokens != 5 {
+			t.Errorf("Expected the custom counter to count 5 words, got %+v", chunks)
+		}
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	tests := map[string]int{"": 0, "a": 1, "abcd": 1, "abcde": 2}
+	for text, want := range tests {
+		if got := EstimateTokens(text); got != want {
+			t.Errorf("EstimateTokens(%q) = %d, want %d", text, got, want)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/pc-coder/tree-code-chunker/store
+
+go 1.23.1
+
+require (
+	github.com/mattn/go-sqlite3 v1.14.33
+	github.com/pc-coder/tree-code-chunker v0.0.0-00010101000000-000000000000
+)
+
+require github.com/smacker/go-tree-sitter v0.0.0-20240827094217-dd81d9e9be82 // indirect
+
+replace github.com/pc-coder/tree-code-chunker => ../
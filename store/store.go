@@ -0,0 +1,395 @@
+// Package store persists chunks, file hashes and entity metadata to SQLite, so
+// that local code-search tools can reuse the chunker's output without designing
+// their own schema.
+//
+// Chunks are upserted by ID and replaced a file at a time:
+//
+//	s, err := store.Open("chunks.db")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer s.Close()
+//
+//	hash := codechunk.ContentHash(code)
+//	if stored, _ := s.FileHash(ctx, "main.go"); stored != hash {
+//	    chunks, err := codechunk.Chunk("main.go", code, nil)
+//	    ...
+//	    err = s.PutFile(ctx, "main.go", hash, chunks)
+//	}
+//
+// The tables are files (filepath, hash), chunks (one row per chunk, with its
+// context, span, stats and other list fields as JSON) and entities (the entities
+// defined in each chunk, indexed by name and qualified name).
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+// ErrNotFound is returned when a requested chunk is not in the store
+var ErrNotFound = errors.New("chunk not found")
+
+const schema = `
+CREATE TABLE IF NOT EXISTS files (
+	filepath TEXT PRIMARY KEY,
+	hash     TEXT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS chunks (
+	id                  TEXT PRIMARY KEY,
+	filepath            TEXT NOT NULL,
+	chunk_index         INTEGER NOT NULL,
+	total_chunks        INTEGER NOT NULL,
+	level               INTEGER NOT NULL,
+	parent_id           TEXT NOT NULL,
+	child_ids           TEXT NOT NULL,
+	prev_id             TEXT NOT NULL,
+	next_id             TEXT NOT NULL,
+	start_byte          INTEGER NOT NULL,
+	end_byte            INTEGER NOT NULL,
+	start_line          INTEGER NOT NULL,
+	end_line            INTEGER NOT NULL,
+	span                TEXT NOT NULL,
+	stats               TEXT NOT NULL,
+	text                TEXT NOT NULL,
+	contextualized_text TEXT NOT NULL,
+	context_header      TEXT NOT NULL,
+	context             TEXT NOT NULL,
+	content_hash        TEXT NOT NULL,
+	occurrences         TEXT NOT NULL,
+	qualified_names     TEXT NOT NULL,
+	truncated           INTEGER NOT NULL,
+	kind                TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS chunks_filepath ON chunks (filepath, chunk_index);
+
+CREATE TABLE IF NOT EXISTS entities (
+	chunk_id       TEXT NOT NULL,
+	filepath       TEXT NOT NULL,
+	name           TEXT NOT NULL,
+	qualified_name TEXT NOT NULL,
+	type           TEXT NOT NULL,
+	start_line     INTEGER NOT NULL,
+	end_line       INTEGER NOT NULL,
+	info           TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS entities_chunk ON entities (chunk_id);
+CREATE INDEX IF NOT EXISTS entities_filepath ON entities (filepath);
+CREATE INDEX IF NOT EXISTS entities_name ON entities (name);
+CREATE INDEX IF NOT EXISTS entities_qualified_name ON entities (qualified_name);
+`
+
+// chunkColumns are the columns of the chunks table, in the order chunkRow and
+// scanChunk use them
+var chunkColumns = []string{
+	"id", "filepath", "chunk_index", "total_chunks", "level",
+	"parent_id", "child_ids", "prev_id", "next_id",
+	"start_byte", "end_byte", "start_line", "end_line", "span", "stats",
+	"text", "contextualized_text", "context_header", "context",
+	"content_hash", "occurrences", "qualified_names", "truncated", "kind",
+}
+
+// upsertChunkSQL inserts a chunk or replaces the chunk with the same ID
+var upsertChunkSQL = upsertSQL("chunks", "id", chunkColumns)
+
+// selectChunkSQL selects the columns of chunks, to be followed by a WHERE clause
+var selectChunkSQL = "SELECT " + strings.Join(chunkColumns, ", ") + " FROM chunks"
+
+// Store persists chunks to a SQLite database. It is safe for concurrent use.
+type Store struct {
+	db *sql.DB
+}
+
+// Entity is an entity defined in a stored chunk
+type Entity struct {
+	codechunk.ChunkEntityInfo
+	ChunkID  string // ID of the chunk the entity is defined in
+	Filepath string // File of the chunk
+}
+
+// Open opens or creates the SQLite database at path and creates the store's
+// tables if needed. Connections are limited to one, so that concurrent writers
+// wait for each other instead of failing with a locked database.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, err
+	}
+	db.SetMaxOpenConns(1)
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New creates the store's tables in db if needed and returns a Store using it.
+// db may be opened with any SQLite driver supporting upserts and json_each.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("creating store schema: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// PutFile stores the chunks of a file and the hash of its content in a single
+// transaction. Chunks are upserted by ID; chunks stored earlier for the file
+// that are not in chunks are deleted along with their entities. Lazy chunks are
+// materialized.
+func (s *Store) PutFile(ctx context.Context, filepath, hash string, chunks []codechunk.CodeChunk) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, upsertSQL("files", "filepath", []string{"filepath", "hash"}), filepath, hash); err != nil {
+		return fmt.Errorf("storing file %s: %w", filepath, err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM entities WHERE filepath = ?", filepath); err != nil {
+		return fmt.Errorf("deleting entities of %s: %w", filepath, err)
+	}
+
+	ids := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		chunk = chunk.Materialize()
+		ids[i] = chunk.ID
+
+		row, err := chunkRow(filepath, chunk)
+		if err != nil {
+			return fmt.Errorf("encoding chunk %s: %w", chunk.ID, err)
+		}
+		if _, err := tx.ExecContext(ctx, upsertChunkSQL, row...); err != nil {
+			return fmt.Errorf("storing chunk %s: %w", chunk.ID, err)
+		}
+		if err := insertEntities(ctx, tx, filepath, chunk); err != nil {
+			return fmt.Errorf("storing entities of chunk %s: %w", chunk.ID, err)
+		}
+	}
+
+	idList, err := json.Marshal(ids)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM chunks WHERE filepath = ? AND id NOT IN (SELECT value FROM json_each(?))", filepath, string(idList)); err != nil {
+		return fmt.Errorf("deleting stale chunks of %s: %w", filepath, err)
+	}
+	return tx.Commit()
+}
+
+// DeleteFile removes a file with its chunks and entities from the store.
+func (s *Store) DeleteFile(ctx context.Context, filepath string) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, table := range []string{"entities", "chunks", "files"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE filepath = ?", filepath); err != nil {
+			return fmt.Errorf("deleting %s of %s: %w", table, filepath, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// FileHash returns the content hash stored for a file by PutFile, or an empty
+// string if the file is not in the store. Comparing it with the ContentHash of
+// the current content tells whether the file needs to be chunked again.
+func (s *Store) FileHash(ctx context.Context, filepath string) (string, error) {
+	var hash string
+	err := s.db.QueryRowContext(ctx, "SELECT hash FROM files WHERE filepath = ?", filepath).Scan(&hash)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return hash, err
+}
+
+// Files returns the paths of the stored files in sorted order.
+func (s *Store) Files(ctx context.Context) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx, "SELECT filepath FROM files ORDER BY filepath")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var filepath string
+		if err := rows.Scan(&filepath); err != nil {
+			return nil, err
+		}
+		files = append(files, filepath)
+	}
+	return files, rows.Err()
+}
+
+// Chunk returns the stored chunk with the given ID, or an error matching
+// ErrNotFound.
+func (s *Store) Chunk(ctx context.Context, id string) (codechunk.CodeChunk, error) {
+	chunks, err := s.queryChunks(ctx, selectChunkSQL+" WHERE id = ?", id)
+	if err != nil {
+		return codechunk.CodeChunk{}, err
+	}
+	if len(chunks) == 0 {
+		return codechunk.CodeChunk{}, fmt.Errorf("%w: %s", ErrNotFound, id)
+	}
+	return chunks[0], nil
+}
+
+// Chunks returns the stored chunks of a file in index order.
+func (s *Store) Chunks(ctx context.Context, filepath string) ([]codechunk.CodeChunk, error) {
+	return s.queryChunks(ctx, selectChunkSQL+" WHERE filepath = ? ORDER BY chunk_index", filepath)
+}
+
+// FindEntities returns the stored entities whose name or qualified name is name,
+// ordered by file and line. An entity split across chunks is returned once per
+// chunk, with IsPartial set.
+func (s *Store) FindEntities(ctx context.Context, name string) ([]Entity, error) {
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT chunk_id, filepath, info FROM entities WHERE name = ? OR qualified_name = ? ORDER BY filepath, start_line, chunk_id",
+		name, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entities []Entity
+	for rows.Next() {
+		var entity Entity
+		var info string
+		if err := rows.Scan(&entity.ChunkID, &entity.Filepath, &info); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(info), &entity.ChunkEntityInfo); err != nil {
+			return nil, fmt.Errorf("decoding entity %s of chunk %s: %w", name, entity.ChunkID, err)
+		}
+		entities = append(entities, entity)
+	}
+	return entities, rows.Err()
+}
+
+// queryChunks runs a query selecting the columns of chunks and decodes the rows
+func (s *Store) queryChunks(ctx context.Context, query string, args ...any) ([]codechunk.CodeChunk, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var chunks []codechunk.CodeChunk
+	for rows.Next() {
+		chunk, err := scanChunk(rows)
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, rows.Err()
+}
+
+// chunkRow returns the values of the chunks table columns for a chunk
+func chunkRow(filepath string, c codechunk.CodeChunk) ([]any, error) {
+	var err error
+	encode := func(v any) string {
+		if err != nil {
+			return ""
+		}
+		var data []byte
+		data, err = json.Marshal(v)
+		return string(data)
+	}
+
+	row := []any{
+		c.ID, filepath, c.Index, c.TotalChunks, c.Level,
+		c.ParentChunkID, encode(c.ChildChunkIDs), c.PrevChunkID, c.NextChunkID,
+		c.ByteRange.Start, c.ByteRange.End, c.LineRange.Start, c.LineRange.End, encode(c.Span), encode(c.Stats),
+		c.Text, c.ContextualizedText, c.ContextHeader, encode(c.Context),
+		c.ContentHash, encode(c.Occurrences), encode(c.QualifiedNames), c.Truncated, c.Kind,
+	}
+	return row, err
+}
+
+// scanChunk decodes a row of the chunks table columns
+func scanChunk(rows *sql.Rows) (codechunk.CodeChunk, error) {
+	var c codechunk.CodeChunk
+	var filepath, childIDs, span, stats, chunkContext, occurrences, qualifiedNames string
+	err := rows.Scan(
+		&c.ID, &filepath, &c.Index, &c.TotalChunks, &c.Level,
+		&c.ParentChunkID, &childIDs, &c.PrevChunkID, &c.NextChunkID,
+		&c.ByteRange.Start, &c.ByteRange.End, &c.LineRange.Start, &c.LineRange.End, &span, &stats,
+		&c.Text, &c.ContextualizedText, &c.ContextHeader, &chunkContext,
+		&c.ContentHash, &occurrences, &qualifiedNames, &c.Truncated, &c.Kind,
+	)
+	if err != nil {
+		return c, err
+	}
+
+	fields := []struct {
+		data string
+		v    any
+	}{
+		{childIDs, &c.ChildChunkIDs},
+		{span, &c.Span},
+		{stats, &c.Stats},
+		{chunkContext, &c.Context},
+		{occurrences, &c.Occurrences},
+		{qualifiedNames, &c.QualifiedNames},
+	}
+	for _, field := range fields {
+		if err := json.Unmarshal([]byte(field.data), field.v); err != nil {
+			return c, fmt.Errorf("decoding chunk %s: %w", c.ID, err)
+		}
+	}
+	return c, nil
+}
+
+// insertEntities stores the entities defined in a chunk
+func insertEntities(ctx context.Context, tx *sql.Tx, filepath string, c codechunk.CodeChunk) error {
+	for _, entity := range c.Context.Entities {
+		info, err := json.Marshal(entity)
+		if err != nil {
+			return err
+		}
+		var start, end int
+		if entity.LineRange != nil {
+			start, end = entity.LineRange.Start, entity.LineRange.End
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO entities (chunk_id, filepath, name, qualified_name, type, start_line, end_line, info) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			c.ID, filepath, entity.Name, entity.QualifiedName, string(entity.Type), start, end, string(info))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// upsertSQL builds a statement inserting a row into table, or updating the
+// row with the same key
+func upsertSQL(table, key string, columns []string) string {
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	updates := make([]string, 0, len(columns)-1)
+	for _, column := range columns {
+		if column != key {
+			updates = append(updates, column+" = excluded."+column)
+		}
+	}
+	return fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
+		table, strings.Join(columns, ", "), placeholders, key, strings.Join(updates, ", "))
+}
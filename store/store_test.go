@@ -0,0 +1,162 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	codechunk "github.com/pc-coder/tree-code-chunker"
+)
+
+const storeSource = `package auth
+
+// Service signs tokens.
+type Service struct{}
+
+// Login signs a token for user.
+func (s *Service) Login(user string) string {
+	return "token:" + user
+}
+
+// Logout revokes the token of user.
+func (s *Service) Logout(user string) {
+}
+`
+
+func openTestStore(t *testing.T) *Store {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "chunks.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestPutFileRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	chunks, err := codechunk.Chunk("auth/service.go", storeSource, &codechunk.ChunkOptions{MaxChunkSize: 60, MaxChunksPerFile: 4, TruncateOnLimit: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if len(chunks) < 2 {
+		t.Fatalf("Expected at least 2 chunks, got %d", len(chunks))
+	}
+	hash := codechunk.ContentHash(storeSource)
+	if err := s.PutFile(ctx, "auth/service.go", hash, chunks); err != nil {
+		t.Fatalf("PutFile failed: %v", err)
+	}
+
+	stored, err := s.Chunks(ctx, "auth/service.go")
+	if err != nil {
+		t.Fatalf("Chunks failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored, chunks) {
+		t.Errorf("Stored chunks differ:\nstored:   %+v\noriginal: %+v", stored, chunks)
+	}
+
+	chunk, err := s.Chunk(ctx, chunks[1].ID)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if !reflect.DeepEqual(chunk, chunks[1]) {
+		t.Errorf("Chunk(%s) differs from the stored chunk", chunks[1].ID)
+	}
+	if _, err := s.Chunk(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for a missing chunk, got %v", err)
+	}
+
+	if got, err := s.FileHash(ctx, "auth/service.go"); err != nil || got != hash {
+		t.Errorf("FileHash = %q, %v; expected %q", got, err, hash)
+	}
+	if got, err := s.FileHash(ctx, "missing.go"); err != nil || got != "" {
+		t.Errorf("FileHash of a missing file = %q, %v; expected empty", got, err)
+	}
+	if files, err := s.Files(ctx); err != nil || !reflect.DeepEqual(files, []string{"auth/service.go"}) {
+		t.Errorf("Files = %v, %v", files, err)
+	}
+}
+
+func TestPutFileReplacesChunks(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	lazy, err := codechunk.Chunk("auth/service.go", storeSource, &codechunk.ChunkOptions{MaxChunkSize: 60, LazyText: true})
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if err := s.PutFile(ctx, "auth/service.go", "old", lazy); err != nil {
+		t.Fatalf("PutFile failed: %v", err)
+	}
+
+	chunks, err := codechunk.Chunk("auth/service.go", storeSource, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if err := s.PutFile(ctx, "auth/service.go", "new", chunks); err != nil {
+		t.Fatalf("PutFile failed: %v", err)
+	}
+
+	stored, err := s.Chunks(ctx, "auth/service.go")
+	if err != nil {
+		t.Fatalf("Chunks failed: %v", err)
+	}
+	if !reflect.DeepEqual(stored, chunks) {
+		t.Errorf("Expected the stale chunks to be replaced, got %d chunks", len(stored))
+	}
+	if hash, _ := s.FileHash(ctx, "auth/service.go"); hash != "new" {
+		t.Errorf("FileHash = %q, expected %q", hash, "new")
+	}
+
+	entities, err := s.FindEntities(ctx, "Login")
+	if err != nil {
+		t.Fatalf("FindEntities failed: %v", err)
+	}
+	if len(entities) != 1 || entities[0].ChunkID != chunks[0].ID {
+		t.Errorf("Expected Login once in the new chunk, got %+v", entities)
+	}
+
+	if err := s.DeleteFile(ctx, "auth/service.go"); err != nil {
+		t.Fatalf("DeleteFile failed: %v", err)
+	}
+	if stored, _ := s.Chunks(ctx, "auth/service.go"); len(stored) != 0 {
+		t.Errorf("Expected no chunks after DeleteFile, got %d", len(stored))
+	}
+	if entities, _ := s.FindEntities(ctx, "Login"); len(entities) != 0 {
+		t.Errorf("Expected no entities after DeleteFile, got %d", len(entities))
+	}
+}
+
+func TestFindEntities(t *testing.T) {
+	ctx := context.Background()
+	s := openTestStore(t)
+
+	chunks, err := codechunk.Chunk("auth/service.go", storeSource, nil)
+	if err != nil {
+		t.Fatalf("Chunk failed: %v", err)
+	}
+	if err := s.PutFile(ctx, "auth/service.go", "hash", chunks); err != nil {
+		t.Fatalf("PutFile failed: %v", err)
+	}
+
+	for _, name := range []string{"Logout", "auth.Service.Logout"} {
+		entities, err := s.FindEntities(ctx, name)
+		if err != nil {
+			t.Fatalf("FindEntities(%q) failed: %v", name, err)
+		}
+		if len(entities) != 1 {
+			t.Fatalf("FindEntities(%q): expected 1 entity, got %d", name, len(entities))
+		}
+		entity := entities[0]
+		if entity.Name != "Logout" || entity.QualifiedName != "auth.Service.Logout" || entity.Type != codechunk.EntityTypeMethod {
+			t.Errorf("FindEntities(%q) = %+v", name, entity.ChunkEntityInfo)
+		}
+		if entity.Filepath != "auth/service.go" || entity.Docstring == nil {
+			t.Errorf("FindEntities(%q): expected file and docstring, got %+v", name, entity)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package codechunk
+
+import (
+	"regexp"
+	"strings"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// CommentTag is a TODO, FIXME, HACK, BUG or XXX tag opening a line of a comment,
+// such as "// TODO(alice): cache the parsed tree"
+type CommentTag struct {
+	Tag   string `json:"tag"`             // TODO, FIXME, HACK, BUG or XXX
+	Owner string `json:"owner,omitempty"` // Name in parentheses after the tag, as in TODO(alice)
+	Text  string `json:"text"`            // Rest of the comment line
+	Line  int    `json:"line"`            // Line of the tag in the source (0-indexed)
+}
+
+// foundTag is a comment tag with its byte offset, to assign it to chunks
+type foundTag struct {
+	CommentTag
+	offset int
+}
+
+var (
+	// commentTagWords finds files worth searching for comment tags
+	commentTagWords = regexp.MustCompile(`\b(?:TODO|FIXME|HACK|BUG|XXX)\b`)
+	// commentTagPattern matches a tag at the start of a comment line, after the
+	// comment markers of the supported languages
+	commentTagPattern = regexp.MustCompile(`^[\s/*#!;<>{}\-]*\b(TODO|FIXME|HACK|BUG|XXX)\b(?:\(([^)]*)\))?:?\s*(.*?)[\s*/>\-]*$`)
+)
+
+// findCommentTags returns the tags opening the lines of the comments in the tree,
+// in source order
+func findCommentTags(root *sitter.Node, code []byte) []foundTag {
+	if !commentTagWords.Match(code) {
+		return nil
+	}
+
+	var tags []foundTag
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if strings.Contains(node.Type(), "comment") {
+			tags = append(tags, commentLineTags(node, code)...)
+			return
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+	return tags
+}
+
+// commentLineTags returns the tags opening the lines of a comment node
+func commentLineTags(comment *sitter.Node, code []byte) []foundTag {
+	var tags []foundTag
+	offset := int(comment.StartByte())
+	line := int(comment.StartPoint().Row)
+	for _, text := range strings.SplitAfter(comment.Content(code), "\n") {
+		if match := commentTagPattern.FindStringSubmatchIndex(text); match != nil {
+			tags = append(tags, foundTag{
+				CommentTag: CommentTag{
+					Tag:   text[match[2]:match[3]],
+					Owner: submatch(text, match, 2),
+					Text:  submatch(text, match, 3),
+					Line:  line,
+				},
+				offset: offset + match[2],
+			})
+		}
+		offset += len(text)
+		line++
+	}
+	return tags
+}
+
+// submatch returns the text of group n of a FindStringSubmatchIndex match, or ""
+// if the group did not participate
+func submatch(s string, match []int, n int) string {
+	if match[2*n] < 0 {
+		return ""
+	}
+	return s[match[2*n]:match[2*n+1]]
+}
+
+// tagsInRange returns the tags whose start lies within byteRange
+func tagsInRange(tags []foundTag, byteRange ByteRange) []CommentTag {
+	var inRange []CommentTag
+	for _, tag := range tags {
+		if tag.offset >= byteRange.Start && tag.offset < byteRange.End {
+			inRange = append(inRange, tag.CommentTag)
+		}
+	}
+	return inRange
+}
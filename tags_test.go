@@ -0,0 +1,97 @@
+package codechunk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChunkCommentTags(t *testing.T) {
+	code := `package cache
+
+// Get returns the cached value for key.
+// TODO(alice): evict expired entries
+func Get(key string) string {
+	return "" // FIXME: always misses
+}
+
+/*
+ * HACK: global state until the config lands
+ */
+var state = "TODO: not a comment"
+
+// Describes the BUG tracker, without a tag.
+func Put(key, value string) {}
+`
+	chunks, err := Chunk("cache.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tags []CommentTag
+	for _, chunk := range chunks {
+		tags = append(tags, chunk.Context.Tags...)
+	}
+	expected := []CommentTag{
+		{Tag: "TODO", Owner: "alice", Text: "evict expired entries", Line: 3},
+		{Tag: "FIXME", Text: "always misses", Line: 5},
+		{Tag: "HACK", Text: "global state until the config lands", Line: 9},
+	}
+	if !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Unexpected tags:\n%+v\nexpected\n%+v", tags, expected)
+	}
+	if got := chunks[0].Metadata()[MetadataTags]; got != "TODO,FIXME,HACK" {
+		t.Errorf("Expected tags metadata TODO,FIXME,HACK, got %q", got)
+	}
+}
+
+func TestChunkCommentTagsEmbedded(t *testing.T) {
+	tests := []struct {
+		filepath string
+		code     string
+	}{
+		{"docs/cache.md", "# Cache\n\nUsage:\n\n```go\nfunc Get(key string) string {\n\t// TODO: evict expired entries\n\treturn \"\"\n}\n```\n"},
+		{"Cache.vue", "<template>\n  <div>{{ value }}</div>\n</template>\n\n<script>\nexport function get(key) {\n  // TODO: evict expired entries\n  return ''\n}\n</script>\n"},
+		{"Cache.svelte", "<h1>Cache</h1>\n\n<p>Usage:</p>\n\n<script>\n  export function get(key) {\n    // TODO: evict expired entries\n    return ''\n  }\n</script>\n"},
+	}
+
+	for _, tt := range tests {
+		chunks, err := Chunk(tt.filepath, tt.code, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.filepath, err)
+		}
+		var tags []CommentTag
+		for _, chunk := range chunks {
+			tags = append(tags, chunk.Context.Tags...)
+		}
+		if len(tags) != 1 {
+			t.Fatalf("%s: expected 1 tag, got %+v", tt.filepath, tags)
+		}
+		if tags[0].Line != 6 {
+			t.Errorf("%s: expected the tag on line 6 of the file, got %d", tt.filepath, tags[0].Line)
+		}
+	}
+}
+
+func TestChunkCommentTagsSplit(t *testing.T) {
+	code := "# TODO: first\ndef a():\n    return 1\n\n\n# XXX second\ndef b():\n    return 2\n"
+	opts := DefaultChunkOptions()
+	opts.MaxChunkSize = 20
+	chunks, err := Chunk("a.py", code, &opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Each tag is attached to the chunk holding it only
+	count := 0
+	for _, chunk := range chunks {
+		for _, tag := range chunk.Context.Tags {
+			count++
+			if tag.Line < chunk.LineRange.Start || tag.Line > chunk.LineRange.End {
+				t.Errorf("Tag %+v outside chunk lines %+v", tag, chunk.LineRange)
+			}
+		}
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 tags, got %d", count)
+	}
+}
@@ -0,0 +1,54 @@
+package com.example.billing;
+
+import java.math.BigDecimal;
+import java.time.LocalDate;
+import java.util.ArrayList;
+import java.util.List;
+import java.util.Objects;
+
+// bench:repeat
+
+/** Creates and settles invoices for customer accounts. */
+public class InvoiceService {
+    private final InvoiceRepository repository;
+    private final TaxCalculator taxes;
+
+    public InvoiceService(InvoiceRepository repository, TaxCalculator taxes) {
+        this.repository = Objects.requireNonNull(repository);
+        this.taxes = Objects.requireNonNull(taxes);
+    }
+
+    /** Creates an invoice for the given lines, due in thirty days. */
+    public Invoice create(String accountId, List<InvoiceLine> lines) {
+        BigDecimal net = BigDecimal.ZERO;
+        for (InvoiceLine line : lines) {
+            net = net.add(line.amount());
+        }
+        BigDecimal tax = taxes.taxFor(accountId, net);
+        Invoice invoice = new Invoice(accountId, new ArrayList<>(lines), net, tax, LocalDate.now().plusDays(30));
+        repository.save(invoice);
+        return invoice;
+    }
+
+    /** Marks an invoice as paid, rejecting partial payments. */
+    public void settle(String invoiceId, BigDecimal amount) {
+        Invoice invoice = repository.find(invoiceId)
+            .orElseThrow(() -> new IllegalArgumentException("unknown invoice " + invoiceId));
+        if (amount.compareTo(invoice.total()) < 0) {
+            throw new IllegalStateException("partial payment of " + amount);
+        }
+        invoice.markPaid(LocalDate.now());
+        repository.save(invoice);
+    }
+
+    /** Returns the unpaid invoices past their due date. */
+    public List<Invoice> overdue(LocalDate today) {
+        List<Invoice> result = new ArrayList<>();
+        for (Invoice invoice : repository.unpaid()) {
+            if (invoice.dueDate().isBefore(today)) {
+                result.add(invoice);
+            }
+        }
+        return result;
+    }
+}
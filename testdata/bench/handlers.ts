@@ -0,0 +1,67 @@
+import { Request, Response, Router } from "express";
+import { z } from "zod";
+import { Logger } from "./logger";
+
+// bench:repeat
+
+const createUserSchema = z.object({
+  email: z.string().email(),
+  name: z.string().min(1),
+  role: z.enum(["admin", "member"]).default("member"),
+});
+
+export interface User {
+  id: string;
+  email: string;
+  name: string;
+  role: "admin" | "member";
+  createdAt: Date;
+}
+
+export interface UserRepository {
+  find(id: string): Promise<User | undefined>;
+  create(user: Omit<User, "id" | "createdAt">): Promise<User>;
+  list(offset: number, limit: number): Promise<User[]>;
+}
+
+/** Handles the user endpoints. */
+export class UserHandlers {
+  constructor(private readonly users: UserRepository, private readonly logger: Logger) {}
+
+  /** Returns a single user or 404. */
+  async get(req: Request, res: Response): Promise<void> {
+    const user = await this.users.find(req.params.id);
+    if (!user) {
+      res.status(404).json({ error: "not found" });
+      return;
+    }
+    res.json(user);
+  }
+
+  /** Validates the body and creates a user. */
+  async create(req: Request, res: Response): Promise<void> {
+    const parsed = createUserSchema.safeParse(req.body);
+    if (!parsed.success) {
+      res.status(400).json({ error: parsed.error.flatten() });
+      return;
+    }
+    const user = await this.users.create(parsed.data);
+    this.logger.info("created user", { id: user.id });
+    res.status(201).json(user);
+  }
+
+  /** Lists users a page at a time. */
+  async list(req: Request, res: Response): Promise<void> {
+    const offset = Number(req.query.offset ?? 0);
+    const limit = Math.min(Number(req.query.limit ?? 20), 100);
+    res.json(await this.users.list(offset, limit));
+  }
+}
+
+export function userRouter(handlers: UserHandlers): Router {
+  const router = Router();
+  router.get("/users", (req, res) => handlers.list(req, res));
+  router.get("/users/:id", (req, res) => handlers.get(req, res));
+  router.post("/users", (req, res) => handlers.create(req, res));
+  return router;
+}
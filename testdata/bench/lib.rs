@@ -0,0 +1,70 @@
+//! A small LRU cache with expiring entries.
+
+use std::collections::HashMap;
+use std::hash::Hash;
+use std::time::{Duration, Instant};
+
+// bench:repeat
+
+/// An entry with the time it was last written.
+#[derive(Debug, Clone)]
+pub struct Entry<V> {
+    value: V,
+    written: Instant,
+    last_used: u64,
+}
+
+/// A cache evicting the least recently used entry when full.
+pub struct LruCache<K, V> {
+    entries: HashMap<K, Entry<V>>,
+    capacity: usize,
+    ttl: Duration,
+    clock: u64,
+}
+
+impl<K: Hash + Eq + Clone, V: Clone> LruCache<K, V> {
+    /// Creates a cache holding at most `capacity` entries for `ttl` each.
+    pub fn new(capacity: usize, ttl: Duration) -> Self {
+        Self { entries: HashMap::with_capacity(capacity), capacity, ttl, clock: 0 }
+    }
+
+    /// Returns the value for `key` if present and not expired.
+    pub fn get(&mut self, key: &K) -> Option<V> {
+        self.clock += 1;
+        let clock = self.clock;
+        let ttl = self.ttl;
+        match self.entries.get_mut(key) {
+            Some(entry) if entry.written.elapsed() < ttl => {
+                entry.last_used = clock;
+                Some(entry.value.clone())
+            }
+            Some(_) => {
+                self.entries.remove(key);
+                None
+            }
+            None => None,
+        }
+    }
+
+    /// Inserts a value, evicting the least recently used entry if the cache is full.
+    pub fn insert(&mut self, key: K, value: V) {
+        self.clock += 1;
+        if self.entries.len() >= self.capacity && !self.entries.contains_key(&key) {
+            if let Some(oldest) = self
+                .entries
+                .iter()
+                .min_by_key(|(_, entry)| entry.last_used)
+                .map(|(key, _)| key.clone())
+            {
+                self.entries.remove(&oldest);
+            }
+        }
+        let entry = Entry { value, written: Instant::now(), last_used: self.clock };
+        self.entries.insert(key, entry);
+    }
+
+    /// Number of entries, including expired ones not yet evicted.
+    pub fn len(&self) -> usize {
+        self.entries.len()
+    }
+}
@@ -0,0 +1,61 @@
+"""Order models and pricing rules."""
+
+from __future__ import annotations
+
+import dataclasses
+import decimal
+from typing import Iterable, Optional
+
+# bench:repeat
+
+
+@dataclasses.dataclass
+class LineItem:
+    """A product and quantity in an order."""
+
+    sku: str
+    quantity: int
+    unit_price: decimal.Decimal
+
+    @property
+    def total(self) -> decimal.Decimal:
+        """Price of the line before discounts."""
+        return self.unit_price * self.quantity
+
+
+class Order:
+    """A customer order made of line items."""
+
+    def __init__(self, order_id: str, items: Iterable[LineItem] = ()):
+        self.order_id = order_id
+        self.items = list(items)
+        self.coupon: Optional[str] = None
+
+    def add(self, item: LineItem) -> None:
+        """Add a line item, merging it with an existing line for the same SKU."""
+        for existing in self.items:
+            if existing.sku == item.sku:
+                existing.quantity += item.quantity
+                return
+        self.items.append(item)
+
+    def subtotal(self) -> decimal.Decimal:
+        """Sum of the line totals."""
+        return sum((item.total for item in self.items), decimal.Decimal(0))
+
+    def discount(self) -> decimal.Decimal:
+        """Discount granted by the coupon, if any."""
+        if self.coupon == "TENOFF":
+            return self.subtotal() * decimal.Decimal("0.10")
+        if self.coupon == "FREESHIP" and self.subtotal() > 50:
+            return decimal.Decimal("4.99")
+        return decimal.Decimal(0)
+
+    def total(self) -> decimal.Decimal:
+        """Amount due after discounts."""
+        return max(self.subtotal() - self.discount(), decimal.Decimal(0))
+
+
+def largest_orders(orders: Iterable[Order], limit: int = 10) -> list[Order]:
+    """Return the orders with the highest totals."""
+    return sorted(orders, key=lambda order: order.total(), reverse=True)[:limit]
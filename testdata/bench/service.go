@@ -0,0 +1,87 @@
+// Package inventory tracks stock levels across warehouses.
+package inventory
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// bench:repeat
+
+// ErrOutOfStock is returned when a reservation exceeds the available stock.
+var ErrOutOfStock = errors.New("out of stock")
+
+// Item is a stock keeping unit held in a warehouse.
+type Item struct {
+	SKU       string
+	Name      string
+	Quantity  int
+	Reserved  int
+	UpdatedAt time.Time
+}
+
+// Available returns the quantity that can still be reserved.
+func (i *Item) Available() int {
+	return i.Quantity - i.Reserved
+}
+
+// Warehouse holds items by SKU.
+type Warehouse struct {
+	mu    sync.RWMutex
+	name  string
+	items map[string]*Item
+}
+
+// NewWarehouse creates an empty warehouse.
+func NewWarehouse(name string) *Warehouse {
+	return &Warehouse{name: name, items: make(map[string]*Item)}
+}
+
+// Restock adds quantity to the item with the given SKU, creating it if needed.
+func (w *Warehouse) Restock(sku, name string, quantity int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	item, ok := w.items[sku]
+	if !ok {
+		item = &Item{SKU: sku, Name: name}
+		w.items[sku] = item
+	}
+	item.Quantity += quantity
+	item.UpdatedAt = time.Now()
+}
+
+// Reserve reserves quantity of an item for an order.
+func (w *Warehouse) Reserve(ctx context.Context, sku string, quantity int) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	item, ok := w.items[sku]
+	if !ok {
+		return fmt.Errorf("reserve %s: unknown sku", sku)
+	}
+	if item.Available() < quantity {
+		return fmt.Errorf("reserve %d of %s: %w", quantity, sku, ErrOutOfStock)
+	}
+	item.Reserved += quantity
+	return nil
+}
+
+// LowStock returns the items with less than threshold available, by SKU.
+func (w *Warehouse) LowStock(threshold int) []Item {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	var low []Item
+	for _, item := range w.items {
+		if item.Available() < threshold {
+			low = append(low, *item)
+		}
+	}
+	sort.Slice(low, func(a, b int) bool { return low[a].SKU < low[b].SKU })
+	return low
+}
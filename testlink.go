@@ -0,0 +1,187 @@
+package codechunk
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	sitter "github.com/smacker/go-tree-sitter"
+)
+
+// testSuite is a describe block of a JavaScript or TypeScript test file, such as
+// describe('UserService', ...), naming the symbol its tests exercise
+type testSuite struct {
+	subject   string
+	byteRange ByteRange
+}
+
+// testSuiteFuncs are the functions of test frameworks opening a suite
+var testSuiteFuncs = map[string]bool{"describe": true, "context": true, "suite": true}
+
+// suiteSubjectPattern matches describe titles that name a symbol, such as
+// UserService, UserService.login or #login(), rather than describing behavior
+var suiteSubjectPattern = regexp.MustCompile(`^[#.]?([A-Za-z_$][\w$]*(?:[.#][A-Za-z_$][\w$]*)*)(?:\(\))?$`)
+
+// isTestClassification reports whether chunks of a file so classified get the
+// symbols their tests exercise
+func isTestClassification(classification Classification) bool {
+	return classification == ClassificationTest || classification == ClassificationExample
+}
+
+// findTestSuites returns the describe blocks of a JavaScript or TypeScript test
+// file whose title names a symbol, outermost first
+func findTestSuites(root *sitter.Node, code []byte, lang Language) []testSuite {
+	if lang != LanguageJavaScript && lang != LanguageTypeScript {
+		return nil
+	}
+
+	var suites []testSuite
+	var walk func(node *sitter.Node)
+	walk = func(node *sitter.Node) {
+		if node.Type() == "call_expression" {
+			if subject := suiteSubject(node, code); subject != "" {
+				suites = append(suites, testSuite{
+					subject:   subject,
+					byteRange: ByteRange{Start: int(node.StartByte()), End: int(node.EndByte())},
+				})
+			}
+		}
+		for i := 0; i < int(node.NamedChildCount()); i++ {
+			walk(node.NamedChild(i))
+		}
+	}
+	walk(root)
+	return suites
+}
+
+// suiteSubject returns the symbol named by the title of a describe call, or ""
+// if call opens no suite or its title is prose
+func suiteSubject(call *sitter.Node, code []byte) string {
+	fn := call.ChildByFieldName("function")
+	if fn == nil {
+		return ""
+	}
+	// describe.only and describe.skip open suites too
+	if fn.Type() == "member_expression" {
+		fn = fn.ChildByFieldName("object")
+	}
+	if fn == nil || fn.Type() != "identifier" || !testSuiteFuncs[fn.Content(code)] {
+		return ""
+	}
+
+	args := call.ChildByFieldName("arguments")
+	if args == nil || args.NamedChildCount() == 0 {
+		return ""
+	}
+	title := args.NamedChild(0)
+	if title.Type() != "string" && title.Type() != "template_string" {
+		return ""
+	}
+	text := strings.Trim(title.Content(code), "'\"`")
+	match := suiteSubjectPattern.FindStringSubmatch(text)
+	if match == nil {
+		return ""
+	}
+	return strings.ReplaceAll(match[1], "#", ".")
+}
+
+// testedSymbols returns the symbols exercised by the tests overlapping byteRange:
+// the subjects of enclosing describe blocks, then those derived from the names
+// of test functions and classes
+func testedSymbols(byteRange ByteRange, scopeTree *ScopeTree, suites []testSuite, lang Language) []string {
+	var symbols []string
+	seen := make(map[string]bool)
+	add := func(symbol string) {
+		if symbol != "" && !seen[symbol] {
+			seen[symbol] = true
+			symbols = append(symbols, symbol)
+		}
+	}
+
+	for _, suite := range suites {
+		if suite.byteRange.Start < byteRange.End && byteRange.Start < suite.byteRange.End {
+			add(suite.subject)
+		}
+	}
+	for _, i := range scopeTree.entityIndex.overlapping(byteRange) {
+		add(testedSymbol(scopeTree.AllEntities[i], lang))
+	}
+	return symbols
+}
+
+// testedSymbol derives the symbol a test function or class exercises from its
+// name by the conventions of its language, such as TestFoo → Foo in Go,
+// test_foo → foo in Python and FooTest → Foo in Java, or returns ""
+func testedSymbol(entity *ExtractedEntity, lang Language) string {
+	name := entity.Name
+	switch entity.Type {
+	case EntityTypeClass, EntityTypeType:
+		for _, suffix := range []string{"Tests", "Test", "Spec"} {
+			if trimmed, ok := strings.CutSuffix(name, suffix); ok && trimmed != "" {
+				return trimmed
+			}
+		}
+		return cutUpperPrefix(name, "Test")
+
+	case EntityTypeFunction, EntityTypeMethod:
+		if lang == LanguageGo {
+			return goTestedSymbol(name)
+		}
+		if rest, ok := strings.CutPrefix(name, "test_"); ok {
+			return rest
+		}
+		if rest := cutUpperPrefix(name, "test"); rest != "" {
+			r, size := utf8.DecodeRuneInString(rest)
+			return string(unicode.ToLower(r)) + rest[size:]
+		}
+	}
+	return ""
+}
+
+// goTestedSymbol derives the symbol exercised by a Go test, benchmark, fuzz test
+// or example from its name: TestFoo → Foo, TestT_M → T.M and Test_parse → parse.
+// Later parts that start with a lowercase letter, such as the suffix of
+// ExampleFoo_second, are left out. TestMain is not a test.
+func goTestedSymbol(name string) string {
+	if name == "TestMain" {
+		return ""
+	}
+	for _, prefix := range []string{"Test", "Benchmark", "Fuzz", "Example"} {
+		rest, ok := strings.CutPrefix(name, prefix)
+		if !ok {
+			continue
+		}
+		// Testable and Examples are not tests
+		if unexported, ok := strings.CutPrefix(rest, "_"); ok {
+			rest = unexported
+		} else if r, _ := utf8.DecodeRuneInString(rest); !unicode.IsUpper(r) {
+			return ""
+		}
+		parts := strings.Split(rest, "_")
+		kept := parts[:0]
+		for i, part := range parts {
+			r, _ := utf8.DecodeRuneInString(part)
+			if part == "" || i > 0 && !unicode.IsUpper(r) {
+				break
+			}
+			kept = append(kept, part)
+		}
+		return strings.Join(kept, ".")
+	}
+	return ""
+}
+
+// cutUpperPrefix returns name without prefix if an uppercase letter follows it,
+// as in TestFoo or testFoo, or ""
+func cutUpperPrefix(name, prefix string) string {
+	rest, ok := strings.CutPrefix(name, prefix)
+	if !ok {
+		return ""
+	}
+	r, _ := utf8.DecodeRuneInString(rest)
+	if !unicode.IsUpper(r) {
+		return ""
+	}
+	return rest
+}
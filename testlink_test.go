@@ -0,0 +1,106 @@
+package codechunk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTestedSymbol(t *testing.T) {
+	tests := []struct {
+		name       string
+		entityType EntityType
+		lang       Language
+		expected   string
+	}{
+		{"TestParse", EntityTypeFunction, LanguageGo, "Parse"},
+		{"TestService_Login", EntityTypeFunction, LanguageGo, "Service.Login"},
+		{"Test_parse", EntityTypeFunction, LanguageGo, "parse"},
+		{"BenchmarkChunk", EntityTypeFunction, LanguageGo, "Chunk"},
+		{"ExampleChunk_second", EntityTypeFunction, LanguageGo, "Chunk"},
+		{"TestMain", EntityTypeFunction, LanguageGo, ""},
+		{"Testable", EntityTypeFunction, LanguageGo, ""},
+		{"test_parse_header", EntityTypeFunction, LanguagePython, "parse_header"},
+		{"TestUserService", EntityTypeClass, LanguagePython, "UserService"},
+		{"UserServiceTest", EntityTypeClass, LanguageJava, "UserService"},
+		{"testLogin", EntityTypeMethod, LanguageJava, "login"},
+		{"setUp", EntityTypeMethod, LanguageJava, ""},
+		{"testing", EntityTypeFunction, LanguageRust, ""},
+	}
+
+	for _, tt := range tests {
+		entity := &ExtractedEntity{Name: tt.name, Type: tt.entityType}
+		if got := testedSymbol(entity, tt.lang); got != tt.expected {
+			t.Errorf("testedSymbol(%s %q) = %q, expected %q", tt.entityType, tt.name, got, tt.expected)
+		}
+	}
+}
+
+func TestChunkTestedSymbols(t *testing.T) {
+	code := `package auth
+
+import "testing"
+
+func TestLogin(t *testing.T) {
+	if Login("a") == "" {
+		t.Fatal("empty token")
+	}
+}
+
+func helper() {}
+`
+	chunks, err := Chunk("auth/login_test.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chunks[0].Context.TestedSymbols; !reflect.DeepEqual(got, []string{"Login"}) {
+		t.Errorf("Expected Login to be tested, got %v", got)
+	}
+
+	// The same code outside a test file tests nothing
+	chunks, err = Chunk("auth/login.go", code, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := chunks[0].Context.TestedSymbols; got != nil {
+		t.Errorf("Expected no tested symbols outside test files, got %v", got)
+	}
+}
+
+func TestChunkTestedSymbolsDescribe(t *testing.T) {
+	code := `import { UserService } from './user';
+
+describe('UserService', () => {
+  describe('#login()', () => {
+    it('returns a token', () => {
+      expect(new UserService().login('a')).toBeTruthy();
+    });
+  });
+
+  describe('when logged out', () => {
+    it('rejects requests', () => {});
+  });
+});
+`
+	for _, chunkStream := range []bool{false, true} {
+		var chunks []CodeChunk
+		if chunkStream {
+			stream, err := ChunkStream("src/user.spec.ts", code, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for chunk := range stream {
+				chunks = append(chunks, chunk)
+			}
+		} else {
+			var err error
+			if chunks, err = Chunk("src/user.spec.ts", code, nil); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		expected := []string{"UserService", "login"}
+		if got := chunks[len(chunks)-1].Context.TestedSymbols; !reflect.DeepEqual(got, expected) {
+			t.Errorf("Expected %v to be tested (stream: %v), got %v", expected, chunkStream, got)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package codechunk
+
+import "strings"
+
+// textLine is a line of plain text with its byte offsets (end excludes the newline)
+type textLine struct {
+	start, end int
+	nws        int
+}
+
+// splitTextLines splits text into lines
+func splitTextLines(text string) []textLine {
+	lines := make([]textLine, 0, strings.Count(text, "\n")+1)
+	start := 0
+	for start <= len(text) {
+		end := strings.IndexByte(text[start:], '\n')
+		if end < 0 {
+			end = len(text)
+		} else {
+			end += start
+		}
+		lines = append(lines, textLine{start: start, end: end, nws: countNws(text[start:end])})
+		start = end + 1
+	}
+	return lines
+}
+
+// splitPlainText splits text that has no grammar into pieces of at most maxSize NWS
+// characters. Pieces break at blank lines where possible and at line ends
+// otherwise; a single line larger than maxSize becomes a piece of its own.
+func splitPlainText(text string, maxSize int) []*rebuiltText {
+	lines := splitTextLines(text)
+
+	// Group non-blank lines into paragraphs of line indexes [first, last]
+	var paragraphs [][2]int
+	for i, line := range lines {
+		if line.nws == 0 {
+			continue
+		}
+		if n := len(paragraphs); n > 0 && paragraphs[n-1][1] == i-1 {
+			paragraphs[n-1][1] = i
+		} else {
+			paragraphs = append(paragraphs, [2]int{i, i})
+		}
+	}
+
+	pieces := make([]*rebuiltText, 0)
+	emit := func(first, last int) {
+		pieces = append(pieces, &rebuiltText{
+			text:      text[lines[first].start:lines[last].end],
+			byteRange: ByteRange{Start: lines[first].start, End: lines[last].end},
+			lineRange: LineRange{Start: first, End: last},
+		})
+	}
+
+	first, last, size := -1, -1, 0
+	flush := func() {
+		if first >= 0 {
+			emit(first, last)
+		}
+		first, last, size = -1, -1, 0
+	}
+
+	for _, p := range paragraphs {
+		pSize := 0
+		for i := p[0]; i <= p[1]; i++ {
+			pSize += lines[i].nws
+		}
+
+		if pSize > maxSize {
+			// Oversized paragraph: pack its lines on their own
+			flush()
+			for i := p[0]; i <= p[1]; i++ {
+				if first >= 0 && size+lines[i].nws > maxSize {
+					flush()
+				}
+				if first < 0 {
+					first = i
+				}
+				last = i
+				size += lines[i].nws
+			}
+			flush()
+			continue
+		}
+
+		if first >= 0 && size+pSize > maxSize {
+			flush()
+		}
+		if first < 0 {
+			first = p[0]
+		}
+		last = p[1]
+		size += pSize
+	}
+	flush()
+
+	return pieces
+}
+
+// mergeUndersizedPieces merges plain text pieces smaller than minSize NWS
+// characters into the previous piece, or the next one for the first piece, like
+// mergeUndersizedWindows
+func mergeUndersizedPieces(text string, pieces []*rebuiltText, minSize int) []*rebuiltText {
+	if minSize <= 0 || len(pieces) < 2 {
+		return pieces
+	}
+
+	merged := make([]*rebuiltText, 0, len(pieces))
+	for _, piece := range pieces {
+		if n := len(merged); n > 0 && (countNws(piece.text) < minSize || (n == 1 && countNws(merged[0].text) < minSize)) {
+			prev := merged[n-1]
+			merged[n-1] = &rebuiltText{
+				text:      text[prev.byteRange.Start:piece.byteRange.End],
+				byteRange: ByteRange{Start: prev.byteRange.Start, End: piece.byteRange.End},
+				lineRange: LineRange{Start: prev.lineRange.Start, End: piece.lineRange.End},
+			}
+			continue
+		}
+		merged = append(merged, piece)
+	}
+	return merged
+}
+
+// chunkPlainText chunks text that has no grammar, such as Markdown, with a context
+// holding only the file path and language
+func chunkPlainText(filepath string, text string, lang Language, opts ChunkOptions) []CodeChunk {
+	opts = opts.withDefaults()
+
+	pieces := mergeUndersizedPieces(text, splitPlainText(text, opts.MaxChunkSize), opts.MinChunkSize)
+	return textChunks(filepath, text, lang, pieces, opts, false)
+}
+
+// textChunks builds chunks of plain text pieces. Sliding-window pieces already
+// overlap, so they get no overlap text and are flagged in their context and kind.
+func textChunks(filepath string, text string, lang Language, pieces []*rebuiltText, opts ChunkOptions, slidingWindow bool) []CodeChunk {
+	positions := newPositionIndex([]byte(text))
+	emptyScope := &ScopeTree{}
+	kind := ChunkKindText
+	if slidingWindow {
+		kind = ChunkKindWindow
+	}
+
+	chunks := make([]CodeChunk, len(pieces))
+	for i, piece := range pieces {
+		ctx := ChunkContext{
+			Scope:      []EntityInfo{},
+			Entities:   []ChunkEntityInfo{},
+			Siblings:   []SiblingInfo{},
+			Imports:    []ImportInfo{},
+			References: []ReferenceInfo{},
+		}
+		if opts.ContextMode != ContextModeNone {
+			ctx.Filepath = filepath
+			ctx.Language = lang
+		}
+		ctx.Cell = opts.cell
+		ctx.SlidingWindow = slidingWindow
+
+		var overlapText string
+		if i > 0 && !slidingWindow {
+			overlapText = getOverlapText(pieces[i-1], emptyScope, opts)
+		}
+
+		chunks[i] = CodeChunk{
+			Kind:        kind,
+			ByteRange:   piece.byteRange,
+			LineRange:   piece.lineRange,
+			Span:        positions.span(piece.byteRange),
+			Stats:       chunkStats(piece.text, piece.lineRange, opts),
+			Context:     ctx,
+			Index:       i,
+			TotalChunks: len(pieces),
+		}
+		if opts.LazyText {
+			chunks[i].lazy = &lazyText{source: text, opts: opts, overlap: overlapText}
+		} else {
+			chunks[i].Text = piece.text
+			chunks[i].contextualize(opts, piece.text, overlapText, "")
+		}
+	}
+
+	return chunks
+}
@@ -0,0 +1,64 @@
+package codechunk
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitPlainText(t *testing.T) {
+	text := "First paragraph\nstill first.\n\nSecond one.\n\n\nThird paragraph here."
+	pieces := splitPlainText(text, 30)
+
+	expected := []string{"First paragraph\nstill first.", "Second one.\n\n\nThird paragraph here."}
+	if len(pieces) != len(expected) {
+		t.Fatalf("Expected %d pieces, got %d", len(expected), len(pieces))
+	}
+	for i, piece := range pieces {
+		if piece.text != expected[i] {
+			t.Errorf("Piece %d = %q, expected %q", i, piece.text, expected[i])
+		}
+		if text[piece.byteRange.Start:piece.byteRange.End] != piece.text {
+			t.Errorf("Piece %d byte range does not match its text", i)
+		}
+	}
+	if pieces[1].lineRange != (LineRange{Start: 3, End: 6}) {
+		t.Errorf("Unexpected line range %+v", pieces[1].lineRange)
+	}
+}
+
+func TestSplitPlainTextOversizedParagraph(t *testing.T) {
+	lines := make([]string, 10)
+	for i := range lines {
+		lines[i] = strings.Repeat("x", 10)
+	}
+	pieces := splitPlainText(strings.Join(lines, "\n"), 25)
+
+	if len(pieces) != 5 {
+		t.Fatalf("Expected 5 pieces of 2 lines, got %d", len(pieces))
+	}
+	for _, piece := range pieces {
+		if countNws(piece.text) > 25 {
+			t.Errorf("Piece exceeds max size: %q", piece.text)
+		}
+	}
+
+	if pieces := splitPlainText("  \n\n", 25); len(pieces) != 0 {
+		t.Errorf("Expected no pieces for blank text, got %d", len(pieces))
+	}
+}
+
+func TestMergeUndersizedPieces(t *testing.T) {
+	text := "first paragraph here\n\nsecond paragraph here\n\nend"
+	pieces := splitPlainText(text, 20)
+	if len(pieces) != 3 {
+		t.Fatalf("Expected 3 pieces, got %d", len(pieces))
+	}
+
+	merged := mergeUndersizedPieces(text, pieces, 5)
+	if len(merged) != 2 {
+		t.Fatalf("Expected the trailing piece merged, got %d pieces", len(merged))
+	}
+	if merged[1].text != "second paragraph here\n\nend" || merged[1].lineRange != (LineRange{Start: 2, End: 4}) {
+		t.Errorf("Expected the merged piece to span both, got %q on %+v", merged[1].text, merged[1].lineRange)
+	}
+}
@@ -4,6 +4,11 @@
 package codechunk
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
 	sitter "github.com/smacker/go-tree-sitter"
 )
 
@@ -17,6 +22,24 @@ const (
 	LanguageRust        Language = "rust"
 	LanguageGo          Language = "go"
 	LanguageJava        Language = "java"
+	LanguageSQL         Language = "sql"
+	LanguageHCL         Language = "hcl"
+	LanguageProto       Language = "proto"
+	LanguageElixir      Language = "elixir"
+	LanguageLua         Language = "lua"
+
+	// LanguageMarkdown marks chunks of Markdown text, which is chunked at paragraph
+	// boundaries rather than parsed
+	LanguageMarkdown Language = "markdown"
+
+	// LanguageVue and LanguageSvelte mark chunks of single-file components without
+	// a <script> block, which are chunked as plain text
+	LanguageVue    Language = "vue"
+	LanguageSvelte Language = "svelte"
+
+	// LanguageHTML marks chunks of HTML pages without inline scripts, which are
+	// chunked as plain text
+	LanguageHTML Language = "html"
 )
 
 // EntityType represents types of entities that can be extracted from source code
@@ -31,8 +54,29 @@ const (
 	EntityTypeEnum      EntityType = "enum"
 	EntityTypeImport    EntityType = "import"
 	EntityTypeExport    EntityType = "export"
+	EntityTypeBlock     EntityType = "block"     // Configuration block, such as a Terraform resource
+	EntityTypeSection   EntityType = "section"   // Section of a container file, such as the template of a Vue component
+	EntityTypeNamespace EntityType = "namespace" // Package, module or namespace, such as a Java package or a Rust mod block
+	EntityTypeComponent EntityType = "component" // React function or class component rendering JSX
+	EntityTypeProperty  EntityType = "property"  // Accessor of a computed attribute, such as a Python @property method
 )
 
+// Visibility is the access level of an entity, as expressed by the language
+type Visibility string
+
+const (
+	VisibilityPublic    Visibility = "public"
+	VisibilityPrivate   Visibility = "private"
+	VisibilityProtected Visibility = "protected"
+	VisibilityInternal  Visibility = "internal" // Visible within a package, crate or module, or private by convention
+	VisibilityExported  Visibility = "exported" // Exported from a Go package or JavaScript module
+)
+
+// IsPublicAPI reports whether the visibility makes an entity part of the public API
+func (v Visibility) IsPublicAPI() bool {
+	return v == VisibilityPublic || v == VisibilityExported
+}
+
 // LineRange represents a range of lines in the source code (0-indexed, inclusive)
 type LineRange struct {
 	Start int `json:"start"` // Start line (0-indexed, inclusive)
@@ -45,16 +89,37 @@ type ByteRange struct {
 	End   int `json:"end"`   // End byte offset (0-indexed, exclusive)
 }
 
-// ParseError represents error information from parsing
+// Position is a location in the source code. Line is 0-indexed like LineRange; the
+// columns are 0-indexed offsets from the start of the line, counted in bytes, in
+// runes and in UTF-16 code units (as used by LSP and most editors).
+type Position struct {
+	Line        int `json:"line"`        // Line (0-indexed)
+	Column      int `json:"column"`      // Byte offset within the line
+	RuneColumn  int `json:"runeColumn"`  // Rune offset within the line
+	UTF16Column int `json:"utf16Column"` // UTF-16 code unit offset within the line
+}
+
+// Span represents a range of positions in the source code, matching a ByteRange
+// (start inclusive, end exclusive)
+type Span struct {
+	Start Position `json:"start"` // Position of the first byte
+	End   Position `json:"end"`   // Position just past the last byte
+}
+
+// ParseError represents error information from parsing. On a file it lists every
+// broken region; on a chunk, only those overlapping the chunk.
 type ParseError struct {
-	Message     string `json:"message"`
-	Recoverable bool   `json:"recoverable"`
+	Message     string      `json:"message"`
+	Recoverable bool        `json:"recoverable"`
+	Ranges      []ByteRange `json:"ranges,omitempty"` // Byte ranges of the ERROR and MISSING nodes (MISSING nodes are empty)
+	Count       int         `json:"count,omitempty"`  // Number of ERROR and MISSING nodes in Ranges
 }
 
 // ParseResult represents the result of parsing source code
 type ParseResult struct {
-	Tree  *sitter.Tree
-	Error *ParseError
+	Tree           *sitter.Tree
+	Error          *ParseError
+	ErrorNodeCount int // Number of ERROR and MISSING nodes in the tree
 }
 
 // ExtractedEntity represents an entity extracted from the AST (function, class, etc.)
@@ -65,9 +130,41 @@ type ExtractedEntity struct {
 	Docstring *string     `json:"docstring"` // Documentation comment if present
 	ByteRange ByteRange   `json:"byteRange"` // Byte range in source
 	LineRange LineRange   `json:"lineRange"` // Line range in source
+	Span      Span        `json:"span"`      // Line and column positions in source
 	Parent    *string     `json:"parent"`    // Parent entity name if nested
-	Node      *sitter.Node `json:"-"`         // The underlying AST node
-	Source    *string     `json:"source"`    // Import source path (only for import entities)
+	Node      *sitter.Node `json:"-"`         // The underlying AST node (nil once extraction completes, so the tree can be freed)
+	Source    *string     `json:"source"`    // Import source path (only for imports and re-exports)
+	// Import and export details (only for import and export entities)
+	OriginalName string `json:"originalName,omitempty"` // Name in the source module when imported under an alias
+	IsDefault    bool   `json:"isDefault,omitempty"`    // Whether it's a default import
+	IsNamespace  bool   `json:"isNamespace,omitempty"`  // Whether it brings in a whole module namespace
+	Level        int    `json:"level,omitempty"`        // Leading dots of a relative Python import, 2 for from ..utils import x
+
+	Visibility  Visibility `json:"visibility,omitempty"`  // Access level, for languages that have one
+	Annotations []string   `json:"annotations,omitempty"` // Decorators and annotations, such as @app.route("/")
+	Modifiers   []string   `json:"modifiers,omitempty"`   // Modifiers such as async, static, abstract or unsafe
+	Parameters  []Param    `json:"parameters,omitempty"`  // Parameters of a function or method
+	ReturnType  string     `json:"returnType,omitempty"`  // Declared return type of a function or method
+
+	TypeParameters []TypeParam `json:"typeParameters,omitempty"` // Generic type parameters, including Rust lifetimes
+	PropsType      string      `json:"propsType,omitempty"`      // Type of the props of a React component, such as ButtonProps
+	Overloads      []string    `json:"overloads,omitempty"`      // Signatures of the TypeScript overloads declared before the entity
+
+	// Naming context of an entity without a name of its own, used by AnonymousNaming
+	boundName     string // Variable or property the entity is assigned to, or the name it exports
+	defaultExport bool   // The entity is the default export of its module
+}
+
+// Param is a parameter of a function or method
+type Param struct {
+	Name string `json:"name,omitempty"` // Name as written, with markers such as ...rest or *args (empty for unnamed Go results)
+	Type string `json:"type,omitempty"` // Declared type, if any
+}
+
+// TypeParam is a generic type parameter of an entity
+type TypeParam struct {
+	Name       string `json:"name"`                 // Name, such as T or a Rust lifetime 'a
+	Constraint string `json:"constraint,omitempty"` // Constraint or bounds, such as any, Clone + 'a or the type of extends
 }
 
 // ScopeNode represents a node in the scope tree
@@ -75,14 +172,25 @@ type ScopeNode struct {
 	Entity   *ExtractedEntity `json:"entity"`   // The entity at this scope level
 	Children []*ScopeNode     `json:"children"` // Child scope nodes
 	Parent   *ScopeNode       `json:"-"`        // Parent scope node (excluded from JSON to avoid cycles)
+
+	QualifiedName string `json:"qualifiedName,omitempty"` // Name qualified by the package or module and enclosing scopes, such as auth.Service.Login
+
+	childEnds []int // Running maximum end of Children, for binary search
 }
 
 // ScopeTree represents the tree structure of the scope hierarchy of a file
 type ScopeTree struct {
 	Root        []*ScopeNode       `json:"root"`        // Root scope nodes (top-level entities)
 	Imports     []*ExtractedEntity `json:"imports"`     // All import entities
-	Exports     []*ExtractedEntity `json:"exports"`     // All export entities
+	Exports     []*ExtractedEntity `json:"exports"`     // Export statements, plus exports derived from naming or visibility rules (Go, Python, Rust, Java)
 	AllEntities []*ExtractedEntity `json:"allEntities"` // Flat list of all entities
+
+	namespace      string                      // Package or module the file declares outside of the tree
+	qualifiedNames map[*ExtractedEntity]string // Qualified names of the scope entities
+	rootEnds       []int                       // Running maximum end of Root, for binary search
+	entityIndex    *entityIndex                // Range index of AllEntities
+	receiverTypes  map[string]*ScopeNode       // First top-level type of each name, for receiverScope
+	callables      map[string]*ExtractedEntity // Callable entities by name, for callDefinition
 }
 
 // ASTWindow represents a window of AST nodes for context
@@ -92,6 +200,9 @@ type ASTWindow struct {
 	Size          int            // Size of the window in NWS characters
 	IsPartialNode bool           // Whether this window contains a partial node
 	LineRanges    []LineRange    // Line ranges for nodes in this window
+	ByteRanges    []ByteRange    // Byte ranges of the pieces of partial nodes in this window
+	Class         *ClassContext  // Class this window was split from (ChunkOptions.SplitClasses)
+	Standalone    bool           // Whether this window is kept apart from other standalone windows when merging
 }
 
 // EntityInfo contains information about an entity for context
@@ -103,12 +214,23 @@ type EntityInfo struct {
 
 // ChunkEntityInfo contains extended entity info for entities within a chunk
 type ChunkEntityInfo struct {
-	Name      string     `json:"name"`                // Name of the entity
-	Type      EntityType `json:"type"`                // Type of entity
-	Signature string     `json:"signature,omitempty"` // Signature if available
-	Docstring *string    `json:"docstring,omitempty"` // Documentation comment if present
-	LineRange *LineRange `json:"lineRange,omitempty"` // Line range in source
-	IsPartial bool       `json:"isPartial,omitempty"` // Whether entity spans multiple chunks
+	Name        string     `json:"name"`                  // Name of the entity
+	Type        EntityType `json:"type"`                  // Type of entity
+	Signature   string     `json:"signature,omitempty"`   // Signature if available
+	Docstring   *string    `json:"docstring,omitempty"`   // Documentation comment if present
+	LineRange   *LineRange `json:"lineRange,omitempty"`   // Line range in source
+	Span        *Span      `json:"span,omitempty"`        // Line and column positions in source
+	IsPartial   bool       `json:"isPartial,omitempty"`   // Whether entity spans multiple chunks
+	Visibility  Visibility `json:"visibility,omitempty"`  // Access level, for languages that have one
+	Annotations []string   `json:"annotations,omitempty"` // Decorators and annotations, such as @app.route("/")
+	Modifiers   []string   `json:"modifiers,omitempty"`   // Modifiers such as async, static, abstract or unsafe
+	Parameters  []Param    `json:"parameters,omitempty"`  // Parameters of a function or method
+	ReturnType  string     `json:"returnType,omitempty"`  // Declared return type of a function or method
+
+	TypeParameters []TypeParam `json:"typeParameters,omitempty"` // Generic type parameters, including Rust lifetimes
+	QualifiedName  string      `json:"qualifiedName,omitempty"`  // Name qualified by the package or module and enclosing scopes
+	PropsType      string      `json:"propsType,omitempty"`      // Type of the props of a React component, such as ButtonProps
+	Overloads      []string    `json:"overloads,omitempty"`      // Signatures of TypeScript overloads and of other declarations of a merged interface
 }
 
 // SiblingInfo contains information about a sibling entity
@@ -116,15 +238,36 @@ type SiblingInfo struct {
 	Name     string     `json:"name"`     // Name of the sibling
 	Type     EntityType `json:"type"`     // Type of sibling
 	Position string     `json:"position"` // Position relative to current chunk ("before" or "after")
-	Distance int        `json:"distance"` // Distance in entities from current chunk
+	Distance int        `json:"distance"` // Proximity rank among siblings on the same side (1 = nearest)
+	Signature string    `json:"signature,omitempty"` // Signature of the sibling (only with SiblingDetailSignatures)
 }
 
 // ImportInfo contains information about an import statement
 type ImportInfo struct {
-	Name        string `json:"name"`                  // What is being imported
-	Source      string `json:"source"`                // Source module/path
-	IsDefault   bool   `json:"isDefault,omitempty"`   // Whether it's a default import
-	IsNamespace bool   `json:"isNamespace,omitempty"` // Whether it's a namespace import
+	Name         string `json:"name"`                   // What is being imported, as named in the importing file
+	Source       string `json:"source"`                 // Source module/path
+	OriginalName string `json:"originalName,omitempty"` // Name in the source module, if imported under an alias
+	IsDefault    bool   `json:"isDefault,omitempty"`    // Whether it's a default import
+	IsNamespace  bool   `json:"isNamespace,omitempty"`  // Whether it's a namespace import
+	Level        int    `json:"level,omitempty"`        // Leading dots of a relative Python import, 2 for from ..utils import x
+	// Module a relative Python import resolves to from the importing file's module,
+	// pkg.utils for from ..utils import x in pkg/auth/service.py
+	ResolvedSource string `json:"resolvedSource,omitempty"`
+}
+
+// ReferenceInfo contains information about a symbol called within a chunk
+type ReferenceInfo struct {
+	Name      string `json:"name"`                // Called symbol as written (e.g. "fmt.Println")
+	Signature string `json:"signature,omitempty"` // Signature if the symbol is defined in the same file
+	Line      int    `json:"line"`                // Line of the first call in the chunk (0-indexed)
+}
+
+// ClassContext describes the class a chunk was split from when oversized classes
+// are split by method (see ChunkOptions.SplitClasses)
+type ClassContext struct {
+	Name      string   `json:"name"`                // Name of the class
+	Signature string   `json:"signature,omitempty"` // Signature of the class
+	Fields    []string `json:"fields,omitempty"`    // Field declarations of the class, each on one line
 }
 
 // ChunkContext contains context information for a chunk
@@ -135,21 +278,64 @@ type ChunkContext struct {
 	Entities   []ChunkEntityInfo `json:"entities"`             // Entities within this chunk
 	Siblings   []SiblingInfo     `json:"siblings"`             // Nearby sibling entities
 	Imports    []ImportInfo      `json:"imports"`              // Relevant imports
+	References []ReferenceInfo   `json:"references"`           // Symbols called within this chunk
 	ParseError *ParseError       `json:"parseError,omitempty"` // Parse error if any
+	Cell       *NotebookCell     `json:"cell,omitempty"`       // Notebook cell the chunk was taken from (.ipynb only)
+	Class      *ClassContext     `json:"class,omitempty"`      // Class the chunk was split from (SplitClasses only)
+	SlidingWindow bool           `json:"slidingWindow,omitempty"` // Chunked by overlapping windows of lines rather than the syntax tree
+	Boilerplate   *BoilerplateInfo `json:"boilerplate,omitempty"` // License header stripped from the file (StripBoilerplate only)
+	Classification Classification  `json:"classification,omitempty"` // Whether the file is production code, a test, generated code or an example
+	Build          *BuildConstraints `json:"build,omitempty"`        // Build constraints and GOOS/GOARCH file suffix (Go files only)
+	Provenance     *Provenance     `json:"provenance,omitempty"`     // Repository coordinates of the file (ChunkOptions.Provenance or FileInput.Provenance only)
+	TestedSymbols  []string        `json:"testedSymbols,omitempty"`  // Symbols exercised by the tests in the chunk, such as Foo for TestFoo (test and example files only)
+	Tags           []CommentTag    `json:"tags,omitempty"`           // TODO, FIXME, HACK, BUG and XXX tags in the comments of the chunk
+
+	FileDoc string `json:"fileDoc,omitempty"` // Module docstring, package comment or inner doc comments of the file
+
+	commentPrefix string       // ChunkOptions.CommentPrefix, set on the context passed to FormatFunc
+	logger        *slog.Logger // ChunkOptions.Logger, set on the context passed to FormatFunc
+}
+
+// NotebookCell describes the Jupyter notebook cell a chunk was taken from. Ranges
+// and lines of such chunks are relative to the cell's source.
+type NotebookCell struct {
+	Index          int    `json:"index"`                    // Cell index in the notebook (0-indexed)
+	ID             string `json:"id,omitempty"`             // Cell ID (nbformat 4.5 and later)
+	CellType       string `json:"cellType"`                 // "code", "markdown" or "raw"
+	ExecutionCount *int   `json:"executionCount,omitempty"` // Execution count of code cells that have run
 }
 
 // CodeChunk represents a chunk of source code with context
 type CodeChunk struct {
+	ID                string       `json:"id"`                // Stable ID derived from the file path and byte range
+	ParentChunkID     string       `json:"parentChunkId,omitempty"` // ID of the file overview chunk (Hierarchical only)
+	ChildChunkIDs     []string     `json:"childChunkIds,omitempty"` // IDs of the detail chunks of a file overview chunk
+	PrevChunkID       string       `json:"prevChunkId,omitempty"` // ID of the previous chunk in the file, if any
+	NextChunkID       string       `json:"nextChunkId,omitempty"` // ID of the next chunk in the file, if any
+	Level             int          `json:"level,omitempty"`   // Hierarchy level: 0 for the file overview, 1 for detail chunks (Hierarchical only)
 	Text              string       `json:"text"`              // The actual text content
-	ContextualizedText string      `json:"contextualizedText"` // Text with semantic context prepended
+	ContextualizedText string      `json:"contextualizedText"` // Text with semantic context prepended (ContextualizedTextModePrefix only)
 	ByteRange         ByteRange    `json:"byteRange"`         // Byte range in original source
 	LineRange         LineRange    `json:"lineRange"`         // Line range in original source
+	Span              Span         `json:"span"`              // Line and column positions in original source
+	Stats             ChunkStats   `json:"stats"`             // Size of the text in bytes, lines and tokens
 	Context           ChunkContext `json:"context"`           // Contextual information
 	Index             int          `json:"index"`             // Index of this chunk (0-based)
 	TotalChunks       int          `json:"totalChunks"`       // Total number of chunks
+	ContentHash       string       `json:"contentHash,omitempty"` // SHA-256 of Text (BatchOptions.Dedupe only)
+	Occurrences       []ChunkOccurrence `json:"occurrences,omitempty"` // Every place Text occurs in the batch, if more than one (BatchOptions.Dedupe only)
+
+	QualifiedNames []string `json:"qualifiedNames,omitempty"` // Qualified names of the entities the chunk overlaps, for metadata filters
+	ContextHeader  string   `json:"contextHeader,omitempty"`  // Context header rendered apart from the text (ContextualizedTextModeSeparate only)
+	Truncated      bool     `json:"truncated,omitempty"`      // The file was cut short after this chunk by MaxFileSize or MaxChunksPerFile (TruncateOnLimit only)
+	Kind           ChunkKind `json:"kind,omitempty"`          // Kind of syntax in the chunk, or of chunk for overview, sliding-window and plain-text chunks
+
+	lazy *lazyText // Retained source when the chunk was built with LazyText
 }
 
-// ContextMode specifies how much context to include
+// ContextMode specifies how much context to include.
+// None includes no context, Minimal includes the filepath and scope chain, and Full
+// additionally includes entities, siblings, imports and references.
 type ContextMode string
 
 const (
@@ -167,25 +353,177 @@ const (
 	SiblingDetailSignatures SiblingDetail = "signatures"
 )
 
+// OverlapMode specifies how context from the previous chunk is carried into a chunk
+type OverlapMode string
+
+const (
+	OverlapModeLines           OverlapMode = "lines"            // Trailing OverlapLines lines of the previous chunk
+	OverlapModeNone            OverlapMode = "none"             // No overlap
+	OverlapModeEntitySignature OverlapMode = "entity-signature" // Signature of the previous chunk's last entity
+)
+
+// ContextualizedTextMode specifies how the context header is delivered with a chunk
+type ContextualizedTextMode string
+
+const (
+	ContextualizedTextModePrefix   ContextualizedTextMode = "prefix"   // Prepend the header to the text in ContextualizedText
+	ContextualizedTextModeNone     ContextualizedTextMode = "none"     // Build no header; the context is only in Context
+	ContextualizedTextModeSeparate ContextualizedTextMode = "separate" // Set the header alone in ContextHeader
+)
+
+// ChunkKind classifies a chunk by the kind of syntax it holds, so callers can drop
+// or down-weight chunks with nothing but imports or comments
+type ChunkKind string
+
+const (
+	ChunkKindCode     ChunkKind = "code"     // Declarations and statements, without imports
+	ChunkKindImports  ChunkKind = "imports"  // Only imports, package clauses and comments
+	ChunkKindComments ChunkKind = "comments" // Only comments
+	ChunkKindMixed    ChunkKind = "mixed"    // Imports together with other code
+	ChunkKindText     ChunkKind = "text"     // Text not split by syntax, such as Markdown prose, notebook Markdown cells and HTML
+	ChunkKindWindow   ChunkKind = "window"   // A sliding window over code not chunked by syntax
+	ChunkKindOverview ChunkKind = "overview" // The file overview of Hierarchical chunking
+)
+
+// ChunkStrategy specifies how nodes are grouped into chunks
+type ChunkStrategy string
+
+const (
+	ChunkStrategyGreedy ChunkStrategy = "greedy" // Pack nodes into chunks up to MaxChunkSize
+	ChunkStrategyEntity ChunkStrategy = "entity" // One chunk per top-level entity, split only if it exceeds MaxChunkSize
+	ChunkStrategySlidingWindow ChunkStrategy = "sliding-window" // Overlapping windows of lines, ignoring the syntax tree
+)
+
+// AnonymousNaming specifies how entities without a name of their own, such as
+// export default function() {} or const Button = class {}, are named
+type AnonymousNaming string
+
+const (
+	AnonymousNamingInferred    AnonymousNaming = "inferred"    // The variable or property the entity is assigned to, or the file name for default exports
+	AnonymousNamingPlaceholder AnonymousNaming = "placeholder" // Always "<anonymous>"
+)
+
 // ChunkOptions contains options for chunking source code
 type ChunkOptions struct {
 	MaxChunkSize  int           `json:"maxChunkSize,omitempty"`  // Maximum chunk size in bytes (default: 1500)
+	ChunkStrategy ChunkStrategy `json:"chunkStrategy,omitempty"` // How nodes are grouped into chunks (default: greedy)
+	StripBoilerplate bool       `json:"stripBoilerplate,omitempty"` // Leave license headers and banners at the top of files out of chunks (default: false)
+	BoilerplatePatterns []string `json:"boilerplatePatterns,omitempty"` // Regular expressions identifying boilerplate comments (default: DefaultBoilerplatePatterns)
+	Hierarchical  bool          `json:"hierarchical,omitempty"`  // Emit a file overview chunk of imports and signatures as the parent of the other chunks (default: false)
+	SlidingWindowFallback bool  `json:"slidingWindowFallback,omitempty"` // Use the sliding-window strategy for minified files and files with few entities (default: false)
+	BoundaryHeuristics bool     `json:"boundaryHeuristics,omitempty"` // Split at blank lines and before comment groups rather than at the size limit (default: false)
+	SplitClasses  bool          `json:"splitClasses,omitempty"`  // Split oversized classes into a chunk per method with the class signature and fields as context (default: false)
+	MinChunkSize  int           `json:"minChunkSize,omitempty"`  // Merge smaller chunks into their neighbor, exceeding MaxChunkSize if needed (default: 0, disabled)
 	ContextMode   ContextMode   `json:"contextMode,omitempty"`   // How much context to include (default: full)
 	SiblingDetail SiblingDetail `json:"siblingDetail,omitempty"` // Level of sibling detail (default: signatures)
-	FilterImports bool          `json:"filterImports,omitempty"` // Filter out import statements (default: false)
+	FilterImports bool          `json:"filterImports,omitempty"` // Only include imports whose name is used in the chunk (default: false)
 	Language      Language      `json:"language,omitempty"`      // Override language detection
 	OverlapLines  int           `json:"overlapLines,omitempty"`  // Lines from previous chunk to include (default: 10)
+	FormatFunc    ContextFormatter `json:"-"`                   // Custom ContextualizedText formatter (default: FormatChunkWithContext)
+	CommentPrefix string        `json:"commentPrefix,omitempty"` // Override the header comment prefix (default: derived from language)
+	ParseCache    ParseCache    `json:"-"`                       // Cache of parsed files keyed by content hash (default: none)
+	IncludeImportsInText bool   `json:"includeImportsInText,omitempty"` // Prepend the file's raw import statements to ContextualizedText (default: false)
+	MaxSiblingSignatureLen int  `json:"maxSiblingSignatureLen,omitempty"` // Truncate sibling signatures to this many bytes (default: 120, negative: no limit)
+	MaxSiblings   int           `json:"maxSiblings,omitempty"`   // Siblings to include on each side of a chunk (default: 3, negative: none)
+	OverlapMode   OverlapMode   `json:"overlapMode,omitempty"`   // How overlap with the previous chunk is built (default: lines)
+	DefaultsApplied bool        `json:"defaultsApplied,omitempty"` // Honor zero values instead of applying defaults (set by DefaultChunkOptions)
+	Logger        *slog.Logger  `json:"-"`                       // Receives debug and trace logs of chunking decisions (default: none)
+	LazyText      bool          `json:"lazyText,omitempty"`      // Leave Text and ContextualizedText empty; use Content and Contextualized (default: false)
+	TokenCounter  TokenCounter  `json:"-"`                       // Counts tokens for CodeChunk.Stats (default: EstimateTokens)
+
+	MaxContextHeaderSize int `json:"maxContextHeaderSize,omitempty"` // Trim the context header of ContextualizedText to this many tokens, counted by TokenCounter (default: 0, no limit)
+
+	ContextualizedTextMode ContextualizedTextMode `json:"contextualizedTextMode,omitempty"` // Whether the context header is prepended to ContextualizedText, left out or set in ContextHeader (default: prefix)
+
+	// Parallelism is the number of goroutines building the chunks of one file, for
+	// very large files such as generated code, which are otherwise chunked on one
+	// goroutine even in batch mode (default: 0, one; negative: GOMAXPROCS). Each
+	// goroutine gets at least 16 chunks. TokenCounter and FormatFunc are called
+	// concurrently when set.
+	Parallelism int `json:"parallelism,omitempty"`
+
+	// MaxParseErrorRatio rejects files whose ERROR nodes cover more than this
+	// fraction of the source, such as a .ts file that is actually JSON, rather than
+	// chunking them with garbage entity context (default: 0, no limit). They fail
+	// with an error matching ErrParseFailed, or are chunked as plain text by the
+	// sliding-window strategy with SlidingWindowFallback.
+	MaxParseErrorRatio float64 `json:"maxParseErrorRatio,omitempty"`
+
+	// MaxFileSize and MaxChunksPerFile guard against runaway files, such as huge
+	// generated sources, which fail with an error matching ErrLimitExceeded. With
+	// TruncateOnLimit they are chunked up to the limit instead, a file cut at the
+	// last line break within MaxFileSize bytes, and the last chunk has Truncated set.
+	MaxFileSize      int  `json:"maxFileSize,omitempty"`      // Largest file to chunk in bytes (default: 0, no limit)
+	MaxChunksPerFile int  `json:"maxChunksPerFile,omitempty"` // Most chunks to produce for a file (default: 0, no limit)
+	TruncateOnLimit  bool `json:"truncateOnLimit,omitempty"`  // Truncate files over a limit rather than failing them (default: false)
+
+	SkipTrivialChunks bool `json:"skipTrivialChunks,omitempty"` // Leave out chunks of ChunkKindImports and ChunkKindComments (default: false)
+
+	AnonymousNaming AnonymousNaming `json:"anonymousNaming,omitempty"` // How entities without a name of their own are named (default: inferred)
+
+	Provenance *Provenance `json:"provenance,omitempty"` // Repository coordinates copied into the context of every chunk
+
+	cell     *NotebookCell   // Set on the context of every chunk while chunking a notebook cell
+	sections []fileSection   // Sections of the container file listed as siblings of every chunk
+	ctx      context.Context // Stops chunking once done (set by ChunkWithContext and ChunkStreamWithContext)
 }
 
-// DefaultChunkOptions returns the default chunk options
+// DefaultChunkOptions returns the default chunk options.
+// DefaultsApplied is set, so zero values assigned to the returned options
+// (such as OverlapLines = 0) are honored rather than replaced by defaults.
 func DefaultChunkOptions() ChunkOptions {
 	return ChunkOptions{
-		MaxChunkSize:  1500,
-		ContextMode:   ContextModeFull,
-		SiblingDetail: SiblingDetailSignatures,
-		FilterImports: false,
-		OverlapLines:  10,
+		MaxChunkSize:           1500,
+		ContextMode:            ContextModeFull,
+		SiblingDetail:          SiblingDetailSignatures,
+		FilterImports:          false,
+		OverlapLines:           10,
+		MaxSiblingSignatureLen: 120,
+		MaxSiblings:            3,
+		OverlapMode:            OverlapModeLines,
+		ChunkStrategy:          ChunkStrategyGreedy,
+		ContextualizedTextMode: ContextualizedTextModePrefix,
+		AnonymousNaming:        AnonymousNamingInferred,
+		DefaultsApplied:        true,
+	}
+}
+
+// withDefaults fills in defaults for unset fields. Numeric fields whose zero value
+// is meaningful are only defaulted when DefaultsApplied is false.
+func (o ChunkOptions) withDefaults() ChunkOptions {
+	if o.MaxChunkSize == 0 {
+		o.MaxChunkSize = 1500
+	}
+	if o.ContextMode == "" {
+		o.ContextMode = ContextModeFull
+	}
+	if o.SiblingDetail == "" {
+		o.SiblingDetail = SiblingDetailSignatures
+	}
+	if o.OverlapMode == "" {
+		o.OverlapMode = OverlapModeLines
+	}
+	if o.ChunkStrategy == "" {
+		o.ChunkStrategy = ChunkStrategyGreedy
+	}
+	if o.ContextualizedTextMode == "" {
+		o.ContextualizedTextMode = ContextualizedTextModePrefix
+	}
+	if o.AnonymousNaming == "" {
+		o.AnonymousNaming = AnonymousNamingInferred
+	}
+	if !o.DefaultsApplied {
+		if o.OverlapLines == 0 {
+			o.OverlapLines = 10
+		}
+		if o.MaxSiblingSignatureLen == 0 {
+			o.MaxSiblingSignatureLen = 120
+		}
+		if o.MaxSiblings == 0 {
+			o.MaxSiblings = 3
+		}
 	}
+	return o
 }
 
 // FileInput represents input for batch processing - a single file to chunk
@@ -193,6 +531,9 @@ type FileInput struct {
 	Filepath string        `json:"filepath"` // File path (used for language detection)
 	Code     string        `json:"code"`     // Source code content
 	Options  *ChunkOptions `json:"options"`  // Optional per-file chunking options
+	// Optional repository coordinates of the file, its fields overriding those of
+	// the options' Provenance
+	Provenance *Provenance `json:"provenance,omitempty"`
 }
 
 // BatchResult represents the result for a single file in batch processing
@@ -200,13 +541,70 @@ type BatchResult struct {
 	Filepath string      `json:"filepath"`        // File path that was processed
 	Chunks   []CodeChunk `json:"chunks"`          // Generated chunks (nil on error)
 	Error    error       `json:"error,omitempty"` // The error that occurred (nil on success)
+	Skipped  bool        `json:"skipped,omitempty"` // The file was not processed (Error says why)
 }
 
 // BatchOptions contains options for batch processing
 type BatchOptions struct {
 	ChunkOptions
 	Concurrency int                                            `json:"concurrency,omitempty"` // Max files to process concurrently (default: 10)
-	OnProgress  func(completed, total int, filepath string, success bool) `json:"-"`       // Progress callback
+
+	// OnProgress is called as files complete, with the number completed so far and
+	// the latest file. Calls come from a goroutine of their own, one at a time and
+	// in order of completion, so a slow callback does not hold up the workers. If
+	// it falls more than 1024 files behind, waiting calls are coalesced into the
+	// latest, so completed may skip ahead. The last call, reporting every file
+	// processed, returns before ChunkBatch returns or the stream is closed.
+	OnProgress func(completed, total int, filepath string, success bool) `json:"-"`
+
+	// ProgressInterval paces OnProgress to at most one call per interval, each
+	// reporting the latest file and coalescing those completed since the previous
+	// call (default: 0, a call per file).
+	ProgressInterval time.Duration `json:"-"`
+
+	// MaxInFlightBytes bounds the total source size of files being chunked or, in the
+	// streaming APIs, waiting to be received by the consumer (default: 0, unbounded).
+	// A single file larger than the budget is processed on its own.
+	MaxInFlightBytes int64 `json:"maxInFlightBytes,omitempty"`
+
+	// SpillWriter, if set, receives each completed result of ChunkBatch as a JSON line.
+	// Successfully spilled results are returned with nil Chunks so the batch does not
+	// hold every chunk in memory. Ignored by the streaming APIs.
+	SpillWriter io.Writer `json:"-"`
+
+	// Metrics, if set, receives timings and chunk statistics for every file.
+	Metrics Metrics `json:"-"`
+
+	// Checkpointer, if set, records each successfully chunked file and its content
+	// hash. Checkpoints are taken once a result is spilled (ChunkBatch with
+	// SpillWriter) or received (streaming APIs), so use one of those for resumable runs.
+	Checkpointer Checkpointer `json:"-"`
+
+	// ResumeFrom, if set, skips files it has checkpointed with unchanged content; they
+	// are returned with Skipped set and an Error matching ErrUnchanged. It is usually
+	// the same value as Checkpointer.
+	ResumeFrom Checkpointer `json:"-"`
+
+	// Dedupe collapses chunks with byte-identical text across the batch into the
+	// first one, which lists all their places in Occurrences (see DedupeChunks).
+	// Ignored by the streaming APIs and with SpillWriter, since results are
+	// released before the batch completes.
+	Dedupe bool `json:"dedupe,omitempty"`
+
+	// SkipPolicy selects binary, generated, minified or oversized files to skip; they
+	// are returned with Skipped set and an Error matching ErrSkipped (default: none).
+	SkipPolicy SkipPolicy `json:"skipPolicy,omitempty"`
+
+	// StrictMode lets a panic while chunking a file crash the batch. By default
+	// the panic is recovered and the file's result has a PanicError, matching
+	// ErrPanicked, with the panic value and stack.
+	StrictMode bool `json:"strictMode,omitempty"`
+
+	// Ordered makes the streaming APIs send results in input order rather than
+	// completion order. Files still run concurrently; results completed ahead of an
+	// earlier file are buffered, and workers stop taking files while four per
+	// worker are waiting. Ignored by ChunkBatch, whose results are in input order.
+	Ordered bool `json:"ordered,omitempty"`
 }
 
 // DefaultBatchOptions returns the default batch options
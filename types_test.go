@@ -26,6 +26,25 @@ func TestDefaultChunkOptions(t *testing.T) {
 	if opts.OverlapLines != 10 {
 		t.Errorf("expected OverlapLines 10, got %d", opts.OverlapLines)
 	}
+
+	if !opts.DefaultsApplied {
+		t.Error("expected DefaultsApplied true")
+	}
+}
+
+func TestChunkOptionsWithDefaults(t *testing.T) {
+	opts := ChunkOptions{}.withDefaults()
+	if opts.OverlapLines != 10 || opts.MaxSiblings != 3 || opts.MaxSiblingSignatureLen != 120 {
+		t.Errorf("expected zero options to get defaults, got %+v", opts)
+	}
+
+	explicit := ChunkOptions{DefaultsApplied: true}.withDefaults()
+	if explicit.OverlapLines != 0 || explicit.MaxSiblings != 0 || explicit.MaxSiblingSignatureLen != 0 {
+		t.Errorf("expected zero values to be honored, got %+v", explicit)
+	}
+	if explicit.MaxChunkSize != 1500 || explicit.ContextMode != ContextModeFull {
+		t.Errorf("expected required fields to get defaults, got %+v", explicit)
+	}
 }
 
 func TestDefaultBatchOptions(t *testing.T) {
@@ -73,6 +92,8 @@ func TestEntityTypeConstants(t *testing.T) {
 		{EntityTypeEnum, "enum"},
 		{EntityTypeImport, "import"},
 		{EntityTypeExport, "export"},
+		{EntityTypeComponent, "component"},
+		{EntityTypeProperty, "property"},
 	}
 
 	for _, tt := range tests {